@@ -185,6 +185,22 @@ func (e *RedirectError) Error() string {
 	return fmt.Sprintf("redirection to alternative server required")
 }
 
+// OIDCLoginRequiredError is returned from Open when a user logs in
+// without credentials to a controller that authenticates users
+// with an OpenID Connect issuer. The client should obtain an ID
+// token from the issuer and present it as the login credentials.
+type OIDCLoginRequiredError struct {
+	// IssuerURL holds the URL of the issuer trusted by the controller.
+	IssuerURL string
+
+	// ClientID holds the client ID registered for the controller.
+	ClientID string
+}
+
+func (e *OIDCLoginRequiredError) Error() string {
+	return fmt.Sprintf("login with OpenID Connect issuer %q required", e.IssuerURL)
+}
+
 // Open establishes a connection to the API server using the Info
 // given, returning a State instance which can be used to make API
 // requests.
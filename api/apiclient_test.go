@@ -701,6 +701,24 @@ func (s *apiclientSuite) TestOpenWithRedirect(c *gc.C) {
 	})
 }
 
+func (s *apiclientSuite) TestOpenWithOIDCLoginRequired(c *gc.C) {
+	srv := apiservertesting.NewAPIServer(func(modelUUID string) interface{} {
+		return oidcLoginAPI{}
+	})
+	defer srv.Close()
+
+	_, err := api.Open(&api.Info{
+		Addrs:    srv.Addrs,
+		CACert:   jtesting.CACert,
+		ModelTag: names.NewModelTag("beef1beef1-0000-0000-000011112222"),
+	}, api.DialOpts{})
+	c.Assert(err, gc.ErrorMatches, `login with OpenID Connect issuer "https://sso.example.com" required`)
+	c.Assert(errors.Cause(err), jc.DeepEquals, &api.OIDCLoginRequiredError{
+		IssuerURL: "https://sso.example.com",
+		ClientID:  "juju",
+	})
+}
+
 func (s *apiclientSuite) TestOpenCachesDNS(c *gc.C) {
 	fakeDialer := func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
 		return fakeConn{}, nil
@@ -1357,6 +1375,25 @@ func (a *redirectAPIAdmin) RedirectInfo() (params.RedirectInfoResult, error) {
 	}, nil
 }
 
+type oidcLoginAPI struct{}
+
+func (oidcLoginAPI) Admin(id string) (oidcLoginAPIAdmin, error) {
+	return oidcLoginAPIAdmin{}, nil
+}
+
+type oidcLoginAPIAdmin struct{}
+
+func (oidcLoginAPIAdmin) Login(req params.LoginRequest) (params.LoginResult, error) {
+	return params.LoginResult{}, &params.Error{
+		Message: "oidc login required",
+		Code:    params.CodeOIDCLoginRequired,
+		Info: params.OIDCLoginRequiredErrorInfo{
+			IssuerURL: "https://sso.example.com",
+			ClientID:  "juju",
+		}.AsMap(),
+	}
+}
+
 func assertConnAddrForModel(c *gc.C, location, addr, modelUUID string) {
 	c.Assert(location, gc.Equals, "wss://"+addr+"/model/"+modelUUID+"/api")
 }
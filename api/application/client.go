@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/devices"
@@ -114,6 +115,12 @@ type DeployArgs struct {
 	// value being the unique ID of a pre-uploaded resources in
 	// storage.
 	Resources map[string]string
+
+	// CharmOrigin records where a charm that is not identified by its
+	// URL alone came from, such as a charm downloaded from Charmhub.
+	// This field is only understood by Application facade version 15
+	// and greater.
+	CharmOrigin *params.CharmOrigin
 }
 
 // Deploy obtains the charm, either locally or from the charm store, and deploys
@@ -135,6 +142,9 @@ func (c *Client) Deploy(args DeployArgs) error {
 		}
 		attachStorage[i] = names.NewStorageTag(id).String()
 	}
+	if args.CharmOrigin != nil && c.BestAPIVersion() < 15 {
+		return errors.New("this juju controller does not support charm origins")
+	}
 	deployArgs := params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			ApplicationName:  args.ApplicationName,
@@ -151,6 +161,7 @@ func (c *Client) Deploy(args DeployArgs) error {
 			AttachStorage:    attachStorage,
 			EndpointBindings: args.EndpointBindings,
 			Resources:        args.Resources,
+			CharmOrigin:      args.CharmOrigin,
 		}},
 	}
 	var results params.ErrorResults
@@ -320,10 +331,19 @@ type SetCharmConfig struct {
 	// EndpointBindings is a map of operator-defined endpoint names to
 	// space names to be merged with any existing endpoint bindings.
 	EndpointBindings map[string]string
+
+	// CharmOrigin records where the charm came from if it is not
+	// identified by its URL alone. A nil origin clears any origin
+	// recorded for the application. This field is only understood
+	// by Application facade version 15 and greater.
+	CharmOrigin *params.CharmOrigin
 }
 
 // SetCharm sets the charm for a given application.
 func (c *Client) SetCharm(branchName string, cfg SetCharmConfig) error {
+	if cfg.CharmOrigin != nil && c.BestAPIVersion() < 15 {
+		return errors.New("this juju controller does not support charm origins")
+	}
 	var storageConstraints map[string]params.StorageConstraints
 	if len(cfg.StorageConstraints) > 0 {
 		storageConstraints = make(map[string]params.StorageConstraints)
@@ -356,6 +376,7 @@ func (c *Client) SetCharm(branchName string, cfg SetCharmConfig) error {
 		StorageConstraints: storageConstraints,
 		EndpointBindings:   cfg.EndpointBindings,
 		Generation:         branchName,
+		CharmOrigin:        cfg.CharmOrigin,
 	}
 	return c.facade.FacadeCall("SetCharm", args, nil)
 }
@@ -771,10 +792,64 @@ func (c *Client) SetConstraints(application string, constraints constraints.Valu
 	return c.facade.FacadeCall("SetConstraints", args, nil)
 }
 
+// PlacementPolicy returns the placement policy of the given
+// application.
+func (c *Client) PlacementPolicy(application string) (coreapplication.PlacementPolicy, error) {
+	if c.BestAPIVersion() < 14 {
+		return coreapplication.PlacementPolicy{}, errors.NotSupportedf("placement policies with this version of Juju")
+	}
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: names.NewApplicationTag(application).String()},
+	}}
+	var results params.ApplicationPlacementPolicyResults
+	if err := c.facade.FacadeCall("PlacementPolicies", args, &results); err != nil {
+		return coreapplication.PlacementPolicy{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return coreapplication.PlacementPolicy{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return coreapplication.PlacementPolicy{}, errors.Trace(result.Error)
+	}
+	return coreapplication.PlacementPolicy{
+		AntiAffinity: result.Result.AntiAffinity,
+		Strategy:     coreapplication.PlacementStrategy(result.Result.Strategy),
+	}, nil
+}
+
+// SetPlacementPolicy replaces the placement policy of the given
+// application.
+func (c *Client) SetPlacementPolicy(application string, policy coreapplication.PlacementPolicy) error {
+	if c.BestAPIVersion() < 14 {
+		return errors.NotSupportedf("placement policies with this version of Juju")
+	}
+	args := params.ApplicationPlacementPolicies{
+		Policies: []params.ApplicationPlacementPolicy{{
+			ApplicationTag: names.NewApplicationTag(application).String(),
+			AntiAffinity:   policy.AntiAffinity,
+			Strategy:       string(policy.Strategy),
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetPlacementPolicies", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // Expose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
-func (c *Client) Expose(application string) error {
-	args := params.ApplicationExpose{ApplicationName: application}
+// were also explicitly marked by units as open. The optional
+// exposedEndpoints limit the sources allowed to access the ports of
+// each endpoint, and are merged with any existing expose settings.
+func (c *Client) Expose(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
+	if len(exposedEndpoints) != 0 && c.BestAPIVersion() < 13 {
+		return errors.NotSupportedf("exposing individual endpoints or to specific spaces and CIDRs with this version of Juju")
+	}
+	args := params.ApplicationExpose{
+		ApplicationName:  application,
+		ExposedEndpoints: exposedEndpoints,
+	}
 	return c.facade.FacadeCall("Expose", args, nil)
 }
 
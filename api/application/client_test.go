@@ -20,6 +20,7 @@ import (
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/instance"
@@ -83,6 +84,112 @@ func (s *applicationSuite) TestSetApplicationMetricCredentialsFails(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *applicationSuite) TestExpose(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"db": {
+			ExposeToSpaces: []string{"internal"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	}
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Check(objType, gc.Equals, "Application")
+				c.Check(request, gc.Equals, "Expose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationExpose{
+					ApplicationName:  "foo",
+					ExposedEndpoints: exposedEndpoints,
+				})
+				return nil
+			},
+		),
+		BestVersion: 13,
+	})
+	err := client.Expose("foo", exposedEndpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposeEndpointsNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	err := client.Expose("foo", map[string]params.ExposedEndpoint{
+		"db": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *applicationSuite) TestPlacementPolicy(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(objType, gc.Equals, "Application")
+				c.Check(request, gc.Equals, "PlacementPolicies")
+				c.Assert(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "application-foo"}}})
+				*(response.(*params.ApplicationPlacementPolicyResults)) = params.ApplicationPlacementPolicyResults{
+					Results: []params.ApplicationPlacementPolicyResult{{
+						Result: &params.ApplicationPlacementPolicy{
+							ApplicationTag: "application-foo",
+							AntiAffinity:   []string{"bar"},
+							Strategy:       "spread",
+						},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 14,
+	})
+	policy, err := client.PlacementPolicy("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"bar"},
+		Strategy:     coreapplication.PlacementStrategySpread,
+	})
+}
+
+func (s *applicationSuite) TestSetPlacementPolicy(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Check(objType, gc.Equals, "Application")
+				c.Check(request, gc.Equals, "SetPlacementPolicies")
+				c.Assert(a, jc.DeepEquals, params.ApplicationPlacementPolicies{
+					Policies: []params.ApplicationPlacementPolicy{{
+						ApplicationTag: "application-foo",
+						Strategy:       "pack",
+					}},
+				})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 14,
+	})
+	err := client.SetPlacementPolicy("foo", coreapplication.PlacementPolicy{Strategy: coreapplication.PlacementStrategyPack})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestPlacementPolicyNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	_, err := client.PlacementPolicy("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = client.SetPlacementPolicy("foo", coreapplication.PlacementPolicy{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *applicationSuite) TestDeploy(c *gc.C) {
 	var called bool
 	client := application.NewClient(basetesting.BestVersionCaller{
@@ -156,6 +263,58 @@ func (s *applicationSuite) TestDeployAttachStorageV4(c *gc.C) {
 	c.Assert(called, jc.IsFalse)
 }
 
+func (s *applicationSuite) TestDeployCharmOrigin(c *gc.C) {
+	origin := &params.CharmOrigin{Source: "charmhub", ID: "a-charm-id", Revision: 1}
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Deploy")
+				args, ok := a.(params.ApplicationsDeploy)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.Applications, gc.HasLen, 1)
+				c.Assert(args.Applications[0].Channel, gc.Equals, "edge")
+				c.Assert(args.Applications[0].CharmOrigin, jc.DeepEquals, origin)
+				result := response.(*params.ErrorResults)
+				result.Results = make([]params.ErrorResult, 1)
+				return nil
+			},
+		),
+		BestVersion: 15,
+	})
+	err := client.Deploy(application.DeployArgs{
+		CharmID: charmstore.CharmID{
+			URL:     charm.MustParseURL("local:a-charm-1"),
+			Channel: "edge",
+		},
+		ApplicationName: "applicationA",
+		NumUnits:        1,
+		CharmOrigin:     origin,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestDeployCharmOriginV14(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				return nil
+			},
+		),
+		BestVersion: 14,
+	})
+	err := client.Deploy(application.DeployArgs{
+		NumUnits:    1,
+		CharmOrigin: &params.CharmOrigin{Source: "charmhub", ID: "a-charm-id"},
+	})
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support charm origins")
+	c.Assert(called, jc.IsFalse)
+}
+
 func (s *applicationSuite) TestDeployAttachStorageMultipleUnits(c *gc.C) {
 	var called bool
 	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
@@ -307,6 +466,52 @@ func (s *applicationSuite) TestSetCharm(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *applicationSuite) TestSetCharmCharmOrigin(c *gc.C) {
+	origin := &params.CharmOrigin{Source: "charmhub", ID: "application-id", Revision: 2}
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetCharm")
+				args, ok := a.(params.ApplicationSetCharm)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.CharmOrigin, jc.DeepEquals, origin)
+				return nil
+			},
+		),
+		BestVersion: 15,
+	})
+	err := client.SetCharm(newBranchName, application.SetCharmConfig{
+		ApplicationName: "application",
+		CharmID: charmstore.CharmID{
+			URL: charm.MustParseURL("local:trusty/application-2"),
+		},
+		CharmOrigin: origin,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetCharmCharmOriginV14(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				return nil
+			},
+		),
+		BestVersion: 14,
+	})
+	err := client.SetCharm(newBranchName, application.SetCharmConfig{
+		ApplicationName: "application",
+		CharmOrigin:     &params.CharmOrigin{Source: "charmhub", ID: "application-id"},
+	})
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support charm origins")
+	c.Assert(called, jc.IsFalse)
+}
+
 func (s *applicationSuite) TestDestroyDeprecated(c *gc.C) {
 	var called bool
 	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
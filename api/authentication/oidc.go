@@ -0,0 +1,193 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultDevicePollInterval is the interval between token requests
+	// used when the issuer does not specify one (RFC 8628, section 3.2).
+	defaultDevicePollInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the poll interval each time the
+	// issuer asks us to slow down (RFC 8628, section 3.5).
+	slowDownIncrement = 5 * time.Second
+)
+
+// DeviceAuthorization holds the details the user needs to authorize
+// a device login with an OpenID Connect issuer.
+type DeviceAuthorization struct {
+	// VerificationURI holds the URI the user should visit.
+	VerificationURI string
+
+	// VerificationURIComplete optionally holds a URI that
+	// includes the user code.
+	VerificationURIComplete string
+
+	// UserCode holds the code the user should enter.
+	UserCode string
+}
+
+// OIDCDeviceLogin obtains an ID token from an OpenID Connect issuer
+// using the OAuth 2.0 device authorization grant (RFC 8628).
+type OIDCDeviceLogin struct {
+	// IssuerURL holds the URL of the issuer.
+	IssuerURL string
+
+	// ClientID holds the client ID to request the token for.
+	ClientID string
+
+	// HTTPClient is used to talk to the issuer. If it is
+	// nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to wait between token requests.
+	Clock clock.Clock
+
+	// Prompt is called once the issuer has started the
+	// authorization, to tell the user what to do.
+	Prompt func(DeviceAuthorization) error
+}
+
+// IDToken runs the device authorization grant, returning the ID token
+// once the user has authorized the login.
+func (l *OIDCDeviceLogin) IDToken(ctx context.Context) (string, error) {
+	var metadata struct {
+		TokenEndpoint               string `json:"token_endpoint"`
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	discoveryURL := strings.TrimSuffix(l.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := l.do(ctx, "GET", discoveryURL, nil, &metadata); err != nil {
+		return "", errors.Annotate(err, "fetching OIDC discovery document")
+	}
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return "", errors.NotSupportedf("device login with OIDC issuer %q", l.IssuerURL)
+	}
+
+	var auth struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	if err := l.do(ctx, "POST", metadata.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {l.ClientID},
+		"scope":     {"openid profile email groups"},
+	}, &auth); err != nil {
+		return "", errors.Annotate(err, "starting device authorization")
+	}
+	if err := l.Prompt(DeviceAuthorization{
+		VerificationURI:         auth.VerificationURI,
+		VerificationURIComplete: auth.VerificationURIComplete,
+		UserCode:                auth.UserCode,
+	}); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	interval := defaultDevicePollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	var deadline time.Time
+	if auth.ExpiresIn > 0 {
+		deadline = l.Clock.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return "", errors.Trace(ctx.Err())
+		case <-l.Clock.After(interval):
+		}
+		if !deadline.IsZero() && l.Clock.Now().After(deadline) {
+			return "", errors.New("device authorization expired")
+		}
+		var token struct {
+			IDToken string `json:"id_token"`
+		}
+		err := l.do(ctx, "POST", metadata.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {auth.DeviceCode},
+			"client_id":   {l.ClientID},
+		}, &token)
+		switch errors.Cause(err) {
+		case nil:
+			if token.IDToken == "" {
+				return "", errors.New("OIDC issuer did not return an ID token")
+			}
+			return token.IDToken, nil
+		case errAuthorizationPending:
+		case errSlowDown:
+			interval += slowDownIncrement
+		default:
+			return "", errors.Annotate(err, "requesting ID token")
+		}
+	}
+}
+
+var (
+	errAuthorizationPending = errors.New("authorization_pending")
+	errSlowDown             = errors.New("slow_down")
+)
+
+// do sends a request to the issuer, posting the given form values if
+// any, and decodes the JSON response into v. OAuth errors returned by
+// the token endpoint while the user has not yet authorized the login
+// are returned as errAuthorizationPending and errSlowDown.
+func (l *OIDCDeviceLogin) do(ctx context.Context, method, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequest(method, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	client := l.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&oauthErr); err != nil || oauthErr.Error == "" {
+			return errors.Errorf("%s %s: unexpected HTTP response %q", method, endpoint, resp.Status)
+		}
+		switch oauthErr.Error {
+		case errAuthorizationPending.Error():
+			return errAuthorizationPending
+		case errSlowDown.Error():
+			return errSlowDown
+		case "access_denied":
+			return errors.New("login was denied")
+		case "expired_token":
+			return errors.New("device authorization expired")
+		}
+		if oauthErr.ErrorDescription != "" {
+			return errors.Errorf("%s: %s", oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return errors.New(oauthErr.Error)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/testing/oidctest"
+)
+
+type OIDCDeviceLoginSuite struct {
+	testing.IsolationSuite
+
+	issuer  *oidctest.Issuer
+	clock   *testclock.Clock
+	prompts []authentication.DeviceAuthorization
+	login   *authentication.OIDCDeviceLogin
+}
+
+var _ = gc.Suite(&OIDCDeviceLoginSuite{})
+
+func (s *OIDCDeviceLoginSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.issuer = oidctest.NewIssuer()
+	s.AddCleanup(func(*gc.C) { s.issuer.Close() })
+	s.clock = testclock.NewClock(time.Now())
+	s.prompts = nil
+	s.login = &authentication.OIDCDeviceLogin{
+		IssuerURL: s.issuer.URL,
+		ClientID:  oidctest.ClientID,
+		Clock: &testclock.AutoAdvancingClock{
+			Clock:   s.clock,
+			Advance: s.clock.Advance,
+		},
+		Prompt: func(auth authentication.DeviceAuthorization) error {
+			s.prompts = append(s.prompts, auth)
+			return nil
+		},
+	}
+}
+
+func (s *OIDCDeviceLoginSuite) TestIDToken(c *gc.C) {
+	claims := s.issuer.Claims("alice", "staff")
+	s.issuer.ApproveDevice(2, claims)
+	token, err := s.login.IDToken(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, s.issuer.IDToken(claims))
+	c.Assert(s.prompts, jc.DeepEquals, []authentication.DeviceAuthorization{{
+		VerificationURI: s.issuer.URL + "/activate",
+		UserCode:        oidctest.UserCode,
+	}})
+}
+
+func (s *OIDCDeviceLoginSuite) TestIDTokenDenied(c *gc.C) {
+	s.issuer.DenyDevice()
+	_, err := s.login.IDToken(context.Background())
+	c.Assert(err, gc.ErrorMatches, "requesting ID token: login was denied")
+}
+
+func (s *OIDCDeviceLoginSuite) TestIDTokenExpired(c *gc.C) {
+	// The grant is never approved, so polling continues
+	// until the device code expires.
+	_, err := s.login.IDToken(context.Background())
+	c.Assert(err, gc.ErrorMatches, "device authorization expired")
+	c.Assert(s.prompts, gc.HasLen, 1)
+}
+
+func (s *OIDCDeviceLoginSuite) TestIDTokenUnknownClient(c *gc.C) {
+	s.login.ClientID = "unknown"
+	_, err := s.login.IDToken(context.Background())
+	c.Assert(err, gc.ErrorMatches, "starting device authorization: invalid_client")
+	c.Assert(s.prompts, gc.HasLen, 0)
+}
@@ -177,6 +177,7 @@ type ProvisioningInfo struct {
 	Tags                 map[string]string
 	OperatorImagePath    string
 	CharmModifiedVersion int
+	EndpointBindings     map[string]string
 }
 
 // ProvisioningInfo returns the provisioning info for the specified CAAS
@@ -206,6 +207,7 @@ func (c *Client) ProvisioningInfo(appName string) (*ProvisioningInfo, error) {
 		Tags:                 result.Tags,
 		OperatorImagePath:    result.OperatorImagePath,
 		CharmModifiedVersion: result.CharmModifiedVersion,
+		EndpointBindings:     result.EndpointBindings,
 	}
 	if result.DeploymentInfo != nil {
 		info.DeploymentInfo = DeploymentInfo{
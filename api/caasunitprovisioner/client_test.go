@@ -74,6 +74,7 @@ func (s *unitprovisionerSuite) TestProvisioningInfo(c *gc.C) {
 							Attributes: map[string]string{"gpu": "nvidia-tesla-p100"},
 						},
 					},
+					EndpointBindings: map[string]string{"db": "telco"},
 				},
 			}},
 		}
@@ -111,6 +112,7 @@ func (s *unitprovisionerSuite) TestProvisioningInfo(c *gc.C) {
 			Count:      3,
 			Attributes: map[string]string{"gpu": "nvidia-tesla-p100"},
 		}},
+		EndpointBindings: map[string]string{"db": "telco"},
 	})
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/version"
 
 	"github.com/juju/juju/api/base"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -36,6 +37,146 @@ func (c *Client) IsMetered(charmURL string) (bool, error) {
 	return metered.Metered, nil
 }
 
+// PublishLocalCharm publishes the local charm uploaded to the model, with
+// the pending resources uploaded for an application named after it, to
+// the controller's charm repository. It returns the URL of the published
+// revision.
+func (c *Client) PublishLocalCharm(curl *charm.URL, pendingIDs map[string]string) (*charm.URL, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("publishing local charms")
+	}
+	args := params.PublishLocalCharmArgs{
+		Args: []params.PublishLocalCharmArg{{URL: curl.String(), Resources: pendingIDs}},
+	}
+	var results params.StringResults
+	if err := c.facade.FacadeCall("PublishLocalCharms", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, apiservererrors.RestoreError(result.Error)
+	}
+	published, err := charm.ParseURL(result.Result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return published, nil
+}
+
+// ReleaseLocalCharm releases the local charm published to the controller's
+// charm repository to the channel.
+func (c *Client) ReleaseLocalCharm(curl *charm.URL, channel string) error {
+	if c.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("releasing local charms")
+	}
+	args := params.ReleaseLocalCharmArgs{
+		Args: []params.ReleaseLocalCharmArg{{URL: curl.String(), Channel: channel}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ReleaseLocalCharms", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RemoveLocalCharm removes the revision of a local charm, and the
+// resources stored with it, from the controller's charm repository.
+func (c *Client) RemoveLocalCharm(curl *charm.URL) error {
+	if c.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("removing local charms")
+	}
+	args := params.CharmURLs{
+		URLs: []params.CharmURL{{URL: curl.String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveLocalCharms", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ResolveLocalCharm returns the local charm with the given name and series
+// released to the channel of the controller's charm repository, along
+// with the channel it was found in.
+func (c *Client) ResolveLocalCharm(name, series, channel string) (*charm.URL, string, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return nil, "", errors.NotSupportedf("resolving local charms")
+	}
+	args := params.ResolveLocalCharmArgs{
+		Args: []params.ResolveLocalCharmArg{{Name: name, Series: series, Channel: channel}},
+	}
+	var results params.ResolveLocalCharmResults
+	if err := c.facade.FacadeCall("ResolveLocalCharms", args, &results); err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, "", apiservererrors.RestoreError(result.Error)
+	}
+	curl, err := charm.ParseURL(result.URL)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return curl, result.Channel, nil
+}
+
+// AddCharmFromRepository adds the local charm published to the
+// controller's charm repository to the model.
+func (c *Client) AddCharmFromRepository(curl *charm.URL) error {
+	if c.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("adding charms from the charm repository")
+	}
+	args := params.CharmURLs{
+		URLs: []params.CharmURL{{URL: curl.String()}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddCharmsFromRepository", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return apiservererrors.RestoreError(err)
+	}
+	return nil
+}
+
+// AddRepositoryResources adds the resources stored with the local charm
+// in the controller's charm repository, other than those named in
+// exclude, as pending resources of the application. It returns their
+// pending IDs keyed on resource name.
+func (c *Client) AddRepositoryResources(curl *charm.URL, applicationName string, exclude []string) (map[string]string, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("adding resources from the charm repository")
+	}
+	args := params.AddRepositoryResourcesArgs{
+		Args: []params.AddRepositoryResourcesArg{{
+			URL:             curl.String(),
+			ApplicationName: applicationName,
+			Exclude:         exclude,
+		}},
+	}
+	var results params.AddRepositoryResourcesResults
+	if err := c.facade.FacadeCall("AddRepositoryResources", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, apiservererrors.RestoreError(result.Error)
+	}
+	return result.PendingIDs, nil
+}
+
 // CharmInfo holds information about a charm.
 type CharmInfo struct {
 	Revision   int
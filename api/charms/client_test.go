@@ -6,6 +6,7 @@ package charms_test
 import (
 	"github.com/golang/mock/gomock"
 	charm "github.com/juju/charm/v7"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -101,3 +102,198 @@ func (s *charmsMockSuite) TestCharmInfo(c *gc.C) {
 	}
 	c.Assert(got, gc.DeepEquals, want)
 }
+
+func (s *charmsMockSuite) TestReleaseLocalCharm(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.ReleaseLocalCharmArgs{
+		Args: []params.ReleaseLocalCharmArg{{URL: "local:quantal/dummy-1", Channel: "stable"}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("ReleaseLocalCharms", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	err := client.ReleaseLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), "stable")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *charmsMockSuite) TestReleaseLocalCharmNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(2)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	err := client.ReleaseLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), "stable")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *charmsMockSuite) TestRemoveLocalCharm(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: "charm is released to latest/stable"},
+	}}}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("RemoveLocalCharms", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	err := client.RemoveLocalCharm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, gc.ErrorMatches, "charm is released to latest/stable")
+}
+
+func (s *charmsMockSuite) TestRemoveLocalCharmNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(2)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	err := client.RemoveLocalCharm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *charmsMockSuite) TestResolveLocalCharm(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.ResolveLocalCharmArgs{
+		Args: []params.ResolveLocalCharmArg{{Name: "dummy", Series: "quantal", Channel: "candidate"}},
+	}
+	results := params.ResolveLocalCharmResults{
+		Results: []params.ResolveLocalCharmResult{{URL: "local:quantal/dummy-1", Channel: "latest/stable"}},
+	}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("ResolveLocalCharms", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	curl, channel, err := client.ResolveLocalCharm("dummy", "quantal", "candidate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(channel, gc.Equals, "latest/stable")
+}
+
+func (s *charmsMockSuite) TestResolveLocalCharmNotFound(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	results := params.ResolveLocalCharmResults{
+		Results: []params.ResolveLocalCharmResult{{
+			Error: &params.Error{Code: params.CodeNotFound, Message: "not found"},
+		}},
+	}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("ResolveLocalCharms", gomock.Any(), gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	_, _, err := client.ResolveLocalCharm("dummy", "quantal", "stable")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *charmsMockSuite) TestPublishLocalCharm(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.PublishLocalCharmArgs{
+		Args: []params.PublishLocalCharmArg{{
+			URL:       "local:quantal/dummy-1",
+			Resources: map[string]string{"spam": "pending-id"},
+		}},
+	}
+	results := params.StringResults{
+		Results: []params.StringResult{{Result: "local:quantal/dummy-2"}},
+	}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("PublishLocalCharms", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	curl, err := client.PublishLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), map[string]string{"spam": "pending-id"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, charm.MustParseURL("local:quantal/dummy-2"))
+}
+
+func (s *charmsMockSuite) TestPublishLocalCharmNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(2)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	_, err := client.PublishLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *charmsMockSuite) TestAddCharmFromRepository(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}},
+	}
+	results := params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{Code: params.CodeNotFound, Message: "not found"},
+		}},
+	}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("AddCharmsFromRepository", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	err := client.AddCharmFromRepository(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *charmsMockSuite) TestAddRepositoryResources(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+
+	args := params.AddRepositoryResourcesArgs{
+		Args: []params.AddRepositoryResourcesArg{{
+			URL:             "local:quantal/dummy-1",
+			ApplicationName: "dummy",
+			Exclude:         []string{"eggs"},
+		}},
+	}
+	results := params.AddRepositoryResourcesResults{
+		Results: []params.AddRepositoryResourcesResult{{
+			PendingIDs: map[string]string{"spam": "pending-id"},
+		}},
+	}
+
+	mockFacadeCaller.EXPECT().BestAPIVersion().Return(3)
+	mockFacadeCaller.EXPECT().FacadeCall("AddRepositoryResources", args, gomock.Any()).SetArg(2, results).Return(nil)
+
+	client := charms.NewClientWithFacade(mockFacadeCaller)
+	pendingIDs, err := client.AddRepositoryResources(charm.MustParseURL("local:quantal/dummy-1"), "dummy", []string{"eggs"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pendingIDs, jc.DeepEquals, map[string]string{"spam": "pending-id"})
+}
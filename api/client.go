@@ -289,6 +289,36 @@ func (c *Client) SetModelAgentVersion(version version.Number, ignoreAgentVersion
 	return c.facade.FacadeCall("SetModelAgentVersion", args, nil)
 }
 
+// UpgradePreview reports the prechecks and upgrade steps that setting the
+// model agent-version to the given value would run, without changing it.
+func (c *Client) UpgradePreview(version version.Number, ignoreAgentVersions bool) (params.UpgradePreview, error) {
+	if c.facade.BestAPIVersion() < 3 {
+		return params.UpgradePreview{}, errors.NotSupportedf("upgrade preview")
+	}
+	args := params.SetModelAgentVersion{Version: version, IgnoreAgentVersions: ignoreAgentVersions}
+	var result params.UpgradePreview
+	err := c.facade.FacadeCall("UpgradePreview", args, &result)
+	return result, err
+}
+
+// StageAgentVersion stages an upgrade of the given machines, and the
+// units they host, to a later agent version than the rest of the model.
+func (c *Client) StageAgentVersion(vers version.Number, machines ...names.MachineTag) ([]params.ErrorResult, error) {
+	if c.facade.BestAPIVersion() < 4 {
+		return nil, errors.NotSupportedf("staging agent upgrades")
+	}
+	p := params.StageAgentVersion{
+		Version:  vers,
+		Machines: make([]params.Entity, len(machines)),
+	}
+	for i, machine := range machines {
+		p.Machines[i] = params.Entity{Tag: machine.String()}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("StageAgentVersion", p, &results)
+	return results.Results, err
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -575,6 +605,19 @@ func (c *Client) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSer
 	return resp.ToolsList, nil
 }
 
+// ImportAgentMirror uploads an agent mirror bundle to the controller
+// over HTTPS. The controller verifies the bundle against its agent
+// mirror keyring before adding its agent binaries and image metadata.
+// It returns the imported agent binaries and the number of imported
+// images.
+func (c *Client) ImportAgentMirror(r io.ReadSeeker) (tools.List, int, error) {
+	var resp params.AgentMirrorResult
+	if err := c.httpPost(r, "/agent-mirror", "application/x-tar-gz", &resp); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	return resp.ToolsList, resp.Images, nil
+}
+
 func (c *Client) httpPost(content io.ReadSeeker, endpoint, contentType string, response interface{}) error {
 	req, err := http.NewRequest("POST", endpoint, content)
 	if err != nil {
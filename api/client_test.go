@@ -98,6 +98,31 @@ func (s *clientSuite) TestUploadToolsOtherModel(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *clientSuite) TestImportAgentMirror(c *gc.C) {
+	client := s.APIState.Client()
+	bundle := []byte("agent mirror bundle")
+	var called bool
+	defer fakeAPIEndpoint(c, client, modelEndpoint(c, s.APIState, "agent-mirror"), "POST",
+		func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/x-tar-gz")
+			defer r.Body.Close()
+			obtained, err := ioutil.ReadAll(r.Body)
+			c.Assert(err, jc.ErrorIsNil)
+			c.Check(obtained, gc.DeepEquals, bundle)
+			w.Header().Set("Content-Type", params.ContentTypeJSON)
+			fmt.Fprint(w, `{"tools":[{"version":"2.8.1-focal-amd64"}],"images":2}`)
+		},
+	).Close()
+
+	list, images, err := client.ImportAgentMirror(bytes.NewReader(bundle))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(list, gc.HasLen, 1)
+	c.Check(list[0].Version, gc.Equals, version.MustParseBinary("2.8.1-focal-amd64"))
+	c.Check(images, gc.Equals, 2)
+}
+
 func (s *clientSuite) TestZipHasHooksOnly(c *gc.C) {
 	ch := testcharms.Repo.CharmDir("storage-filesystem-subordinate") // has hooks only
 	tempFile, err := ioutil.TempFile(c.MkDir(), "charm")
@@ -629,16 +654,17 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 	s.PatchValue(api.WebsocketDial, catcher.recordLocation)
 
 	params := common.DebugLogParams{
-		IncludeEntity: []string{"a", "b"},
-		IncludeModule: []string{"c", "d"},
-		ExcludeEntity: []string{"e", "f"},
-		ExcludeModule: []string{"g", "h"},
-		Limit:         100,
-		Backlog:       200,
-		Level:         loggo.ERROR,
-		Replay:        true,
-		NoTail:        true,
-		StartTime:     time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		IncludeEntity:   []string{"a", "b"},
+		IncludeModule:   []string{"c", "d"},
+		ExcludeEntity:   []string{"e", "f"},
+		ExcludeModule:   []string{"g", "h"},
+		Limit:           100,
+		Backlog:         200,
+		Level:           loggo.ERROR,
+		Replay:          true,
+		NoTail:          true,
+		IncludeWorkload: true,
+		StartTime:       time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
 	}
 
 	client := s.APIState.Client()
@@ -650,16 +676,17 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 
 	values := connectURL.Query()
 	c.Assert(values, jc.DeepEquals, url.Values{
-		"includeEntity": params.IncludeEntity,
-		"includeModule": params.IncludeModule,
-		"excludeEntity": params.ExcludeEntity,
-		"excludeModule": params.ExcludeModule,
-		"maxLines":      {"100"},
-		"backlog":       {"200"},
-		"level":         {"ERROR"},
-		"replay":        {"true"},
-		"noTail":        {"true"},
-		"startTime":     {"2016-11-30T11:48:00.0000001Z"},
+		"includeEntity":   params.IncludeEntity,
+		"includeModule":   params.IncludeModule,
+		"excludeEntity":   params.ExcludeEntity,
+		"excludeModule":   params.ExcludeModule,
+		"maxLines":        {"100"},
+		"backlog":         {"200"},
+		"level":           {"ERROR"},
+		"replay":          {"true"},
+		"noTail":          {"true"},
+		"includeWorkload": {"true"},
+		"startTime":       {"2016-11-30T11:48:00.0000001Z"},
 	})
 }
 
@@ -862,3 +889,66 @@ func (s *IsolatedClientSuite) TestFindAllErrorsOnOlderController(c *gc.C) {
 	_, err := client.FindTools(0, 0, "", "", "proposed")
 	c.Assert(err, gc.ErrorMatches, "passing agent-stream not supported by the controller")
 }
+
+func (s *IsolatedClientSuite) TestUpgradePreview(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, v int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Client")
+			c.Check(request, gc.Equals, "UpgradePreview")
+			c.Check(arg, jc.DeepEquals, params.SetModelAgentVersion{
+				Version:             version.MustParse("2.8.1"),
+				IgnoreAgentVersions: true,
+			})
+			*(result.(*params.UpgradePreview)) = params.UpgradePreview{
+				TargetVersion: version.MustParse("2.8.1"),
+			}
+			return nil
+		},
+		BestVersion: 3,
+	}
+	client := api.APIClient(apiCaller)
+	preview, err := client.UpgradePreview(version.MustParse("2.8.1"), true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(preview.TargetVersion, gc.Equals, version.MustParse("2.8.1"))
+}
+
+func (s *IsolatedClientSuite) TestStageAgentVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, v int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Client")
+			c.Check(request, gc.Equals, "StageAgentVersion")
+			c.Check(arg, jc.DeepEquals, params.StageAgentVersion{
+				Version: version.MustParse("2.8.1"),
+				Machines: []params.Entity{
+					{Tag: "machine-0"},
+					{Tag: "machine-3"},
+				},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := api.APIClient(apiCaller)
+	results, err := client.StageAgentVersion(version.MustParse("2.8.1"), names.NewMachineTag("0"), names.NewMachineTag("3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0].Error, gc.IsNil)
+	c.Check(results[1].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *IsolatedClientSuite) TestStageAgentVersionNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 3}
+	client := api.APIClient(apiCaller)
+	_, err := client.StageAgentVersion(version.MustParse("2.8.1"), names.NewMachineTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *IsolatedClientSuite) TestUpgradePreviewNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 2}
+	client := api.APIClient(apiCaller)
+	_, err := client.UpgradePreview(version.MustParse("2.8.1"), false)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -50,6 +50,9 @@ type DebugLogParams struct {
 	// NoTail tells the server to only return the logs it has now, and not
 	// to wait for new logs to arrive.
 	NoTail bool
+	// IncludeWorkload tells the server to also return the logs of the
+	// workload containers of the units in a CAAS model.
+	IncludeWorkload bool
 	// StartTime should be a time in the past - only records with a
 	// log time on or after StartTime will be returned.
 	StartTime time.Time
@@ -68,6 +71,9 @@ func (args DebugLogParams) URLQuery() url.Values {
 	if args.NoTail {
 		attrs.Set("noTail", fmt.Sprint(args.NoTail))
 	}
+	if args.IncludeWorkload {
+		attrs.Set("includeWorkload", fmt.Sprint(args.IncludeWorkload))
+	}
 	if args.Limit > 0 {
 		attrs.Set("maxLines", fmt.Sprint(args.Limit))
 	}
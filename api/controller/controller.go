@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/common/cloudspec"
 	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
@@ -319,16 +320,60 @@ func (s *MigrationSpec) Validate() error {
 // but we don't need that at the client side yet (and may never) so
 // this call just supports starting one migration at a time.
 func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
+	args, err := makeInitiateMigrationArgs(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	response := params.InitiateMigrationResults{}
+	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return "", errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return "", errors.Trace(result.Error)
+	}
+	return result.MigrationId, nil
+}
+
+// PrecheckMigration runs all of the migration prechecks for the
+// specified model on the source and target controllers without starting
+// a migration. It returns every check which failed on each controller.
+func (c *Client) PrecheckMigration(spec MigrationSpec) (source, target []coremigration.PrecheckFailure, err error) {
+	if c.BestAPIVersion() < 10 {
+		return nil, nil, errors.NotSupportedf("migration dry runs on this controller version")
+	}
+	args, err := makeInitiateMigrationArgs(spec)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	response := params.MigrationPrecheckReports{}
+	if err := c.facade.FacadeCall("PrecheckMigration", args, &response); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return nil, nil, errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return nil, nil, errors.Trace(result.Error)
+	}
+	return precheckFailuresFromParams(result.Source), precheckFailuresFromParams(result.Target), nil
+}
+
+func makeInitiateMigrationArgs(spec MigrationSpec) (params.InitiateMigrationArgs, error) {
 	if err := spec.Validate(); err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
 	macsJSON, err := macaroonsToJSON(spec.TargetMacaroons)
 	if err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
-	args := params.InitiateMigrationArgs{
+	return params.InitiateMigrationArgs{
 		Specs: []params.MigrationSpec{{
 			ModelTag: names.NewModelTag(spec.ModelUUID).String(),
 			TargetInfo: params.MigrationTargetInfo{
@@ -341,6 +386,159 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 				Macaroons:       macsJSON,
 			},
 		}},
+	}, nil
+}
+
+func precheckFailuresFromParams(in []params.MigrationPrecheckFailure) []coremigration.PrecheckFailure {
+	var out []coremigration.PrecheckFailure
+	for _, failure := range in {
+		out = append(out, coremigration.PrecheckFailure{
+			Entity:  failure.Entity,
+			Message: failure.Message,
+		})
+	}
+	return out
+}
+
+// BulkMigrationSpec holds the details required to migrate a number of
+// models to another controller. The models are either given by UUID, or
+// are all the models owned by Owner.
+type BulkMigrationSpec struct {
+	ModelUUIDs            []string
+	Owner                 string
+	MaxConcurrent         int
+	TargetControllerUUID  string
+	TargetControllerAlias string
+	TargetAddrs           []string
+	TargetCACert          string
+	TargetUser            string
+	TargetPassword        string
+	TargetMacaroons       []macaroon.Slice
+}
+
+// Validate performs sanity checks on the bulk migration configuration
+// it holds.
+func (s *BulkMigrationSpec) Validate() error {
+	if len(s.ModelUUIDs) == 0 && s.Owner == "" {
+		return errors.NotValidf("empty models and owner")
+	}
+	if len(s.ModelUUIDs) > 0 && s.Owner != "" {
+		return errors.NotValidf("both models and owner")
+	}
+	for _, modelUUID := range s.ModelUUIDs {
+		if !names.IsValidModel(modelUUID) {
+			return errors.NotValidf("model UUID %q", modelUUID)
+		}
+	}
+	if s.Owner != "" && !names.IsValidUser(s.Owner) {
+		return errors.NotValidf("owner %q", s.Owner)
+	}
+	if s.MaxConcurrent < 1 {
+		return errors.NotValidf("max concurrent migrations %d", s.MaxConcurrent)
+	}
+	if !names.IsValidModel(s.TargetControllerUUID) {
+		return errors.NotValidf("controller UUID")
+	}
+	if len(s.TargetAddrs) < 1 {
+		return errors.NotValidf("empty target API addresses")
+	}
+	if !names.IsValidUser(s.TargetUser) {
+		return errors.NotValidf("target user")
+	}
+	if s.TargetPassword == "" && len(s.TargetMacaroons) == 0 {
+		return errors.NotValidf("missing authentication secrets")
+	}
+	return nil
+}
+
+// InitiateBulkMigration requests the migration of a number of models to
+// another controller. The controller starts the migrations, with no
+// more than MaxConcurrent of them running at the same time. It returns
+// the id of the bulk migration and the UUIDs of the models to migrate.
+func (c *Client) InitiateBulkMigration(spec BulkMigrationSpec) (string, []string, error) {
+	if c.BestAPIVersion() < 10 {
+		return "", nil, errors.NotSupportedf("bulk migrations on this controller version")
+	}
+	if err := spec.Validate(); err != nil {
+		return "", nil, errors.Annotatef(err, "client-side validation failed")
+	}
+	macsJSON, err := macaroonsToJSON(spec.TargetMacaroons)
+	if err != nil {
+		return "", nil, errors.Annotatef(err, "client-side validation failed")
+	}
+	args := params.InitiateBulkMigrationArgs{
+		MaxConcurrent: spec.MaxConcurrent,
+		TargetInfo: params.MigrationTargetInfo{
+			ControllerTag:   names.NewControllerTag(spec.TargetControllerUUID).String(),
+			ControllerAlias: spec.TargetControllerAlias,
+			Addrs:           spec.TargetAddrs,
+			CACert:          spec.TargetCACert,
+			AuthTag:         names.NewUserTag(spec.TargetUser).String(),
+			Password:        spec.TargetPassword,
+			Macaroons:       macsJSON,
+		},
+	}
+	for _, modelUUID := range spec.ModelUUIDs {
+		args.ModelTags = append(args.ModelTags, names.NewModelTag(modelUUID).String())
+	}
+	if spec.Owner != "" {
+		args.OwnerTag = names.NewUserTag(spec.Owner).String()
+	}
+	var result params.InitiateBulkMigrationResult
+	if err := c.facade.FacadeCall("InitiateBulkMigration", args, &result); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	var modelUUIDs []string
+	for _, tag := range result.ModelTags {
+		modelTag, err := names.ParseModelTag(tag)
+		if err != nil {
+			return "", nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, modelTag.Id())
+	}
+	return result.Id, modelUUIDs, nil
+}
+
+// BulkMigrationStatus returns the progress of the bulk migrations with
+// the given ids, or of all bulk migrations if no ids are given.
+func (c *Client) BulkMigrationStatus(ids ...string) ([]params.BulkMigrationStatus, error) {
+	if c.BestAPIVersion() < 10 {
+		return nil, errors.NotSupportedf("bulk migrations on this controller version")
+	}
+	var results params.BulkMigrationStatusResults
+	args := params.BulkMigrationStatusArgs{Ids: ids}
+	if err := c.facade.FacadeCall("BulkMigrationStatus", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ids) > 0 && len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	statuses := make([]params.BulkMigrationStatus, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, errors.Trace(result.Error)
+		}
+		statuses[i] = *result.Result
+	}
+	return statuses, nil
+}
+
+// InitiateOfflineMigration starts the migration of the specified model
+// to a model archive, returning the migration's ID. The controller runs
+// the source prechecks and locks the model; the migration then waits in
+// the IMPORT phase for the archive to be imported elsewhere.
+func (c *Client) InitiateOfflineMigration(modelUUID string) (string, error) {
+	if c.BestAPIVersion() < 10 {
+		return "", errors.NotSupportedf("offline migrations on this controller version")
+	}
+	if !names.IsValidModel(modelUUID) {
+		return "", errors.NotValidf("model UUID")
+	}
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: names.NewModelTag(modelUUID).String(),
+			Offline:  true,
+		}},
 	}
 	response := params.InitiateMigrationResults{}
 	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
@@ -356,6 +554,88 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 	return result.MigrationId, nil
 }
 
+// MigrationPhase returns the phase and latest status message of the
+// model migration with the given id.
+func (c *Client) MigrationPhase(id string) (coremigration.Phase, string, error) {
+	if c.BestAPIVersion() < 10 {
+		return coremigration.UNKNOWN, "", errors.NotSupportedf("offline migrations on this controller version")
+	}
+	var results params.MigrationPhaseResults
+	args := params.MigrationIds{Ids: []string{id}}
+	if err := c.facade.FacadeCall("MigrationPhase", args, &results); err != nil {
+		return coremigration.UNKNOWN, "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return coremigration.UNKNOWN, "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return coremigration.UNKNOWN, "", errors.Trace(result.Error)
+	}
+	phase, ok := coremigration.ParsePhase(result.Phase)
+	if !ok {
+		return coremigration.UNKNOWN, "", errors.Errorf("unknown migration phase %q", result.Phase)
+	}
+	return phase, result.StatusMessage, nil
+}
+
+// MigrationTarget holds the details of the controller into which the
+// archive of an offline migration has been imported. No credentials are
+// needed, as the source controller doesn't connect to it.
+type MigrationTarget struct {
+	ControllerUUID  string
+	ControllerAlias string
+	Addrs           []string
+	CACert          string
+	User            string
+}
+
+// SetMigrationTarget records the controller into which the archive of
+// the offline migration with the given id has been imported. The
+// model's agents are then handed over to that controller.
+func (c *Client) SetMigrationTarget(id string, target MigrationTarget) error {
+	if c.BestAPIVersion() < 10 {
+		return errors.NotSupportedf("offline migrations on this controller version")
+	}
+	if !names.IsValidModel(target.ControllerUUID) {
+		return errors.NotValidf("controller UUID")
+	}
+	if !names.IsValidUser(target.User) {
+		return errors.NotValidf("target user")
+	}
+	args := params.SetMigrationTargetArgs{
+		Args: []params.SetMigrationTargetArg{{
+			MigrationId: id,
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag:   names.NewControllerTag(target.ControllerUUID).String(),
+				ControllerAlias: target.ControllerAlias,
+				Addrs:           target.Addrs,
+				CACert:          target.CACert,
+				AuthTag:         names.NewUserTag(target.User).String(),
+			},
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetMigrationTarget", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// AbortMigration aborts the offline migration with the given id, as
+// long as its archive hasn't been imported, unlocking the model.
+func (c *Client) AbortMigration(id string) error {
+	if c.BestAPIVersion() < 10 {
+		return errors.NotSupportedf("offline migrations on this controller version")
+	}
+	var results params.ErrorResults
+	args := params.MigrationIds{Ids: []string{id}}
+	if err := c.facade.FacadeCall("AbortMigration", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 func macaroonsToJSON(macs []macaroon.Slice) (string, error) {
 	if len(macs) == 0 {
 		return "", nil
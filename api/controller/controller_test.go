@@ -20,6 +20,7 @@ import (
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/life"
+	coremigration "github.com/juju/juju/core/migration"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -172,6 +173,286 @@ func (s *Suite) TestInitiateMigrationValidationError(c *gc.C) {
 	c.Check(stub.Calls(), gc.HasLen, 0) // API call shouldn't have happened
 }
 
+func (s *Suite) TestPrecheckMigration(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.MigrationPrecheckReports)) = params.MigrationPrecheckReports{
+				Results: []params.MigrationPrecheckReport{{
+					Source: []params.MigrationPrecheckFailure{{Entity: "unit mysql/0", Message: "boom"}},
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	spec := makeSpec()
+	source, target, err := client.PrecheckMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(source, jc.DeepEquals, []coremigration.PrecheckFailure{{Entity: "unit mysql/0", Message: "boom"}})
+	c.Check(target, gc.HasLen, 0)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.PrecheckMigration", []interface{}{specToArgs(spec)}},
+	})
+}
+
+func (s *Suite) TestPrecheckMigrationError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.MigrationPrecheckReports)) = params.MigrationPrecheckReports{
+				Results: []params.MigrationPrecheckReport{{
+					Error: apiservererrors.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, _, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestPrecheckMigrationAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 9}
+	client := controller.NewClient(apiCaller)
+	_, _, err := client.PrecheckMigration(makeSpec())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestInitiateBulkMigration(c *gc.C) {
+	var stub jujutesting.Stub
+	modelUUID := randomUUID()
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.InitiateBulkMigrationResult)) = params.InitiateBulkMigrationResult{
+				Id:        "0",
+				ModelTags: []string{names.NewModelTag(modelUUID).String()},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	spec := makeBulkSpec()
+	id, modelUUIDs, err := client.InitiateBulkMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(id, gc.Equals, "0")
+	c.Check(modelUUIDs, jc.DeepEquals, []string{modelUUID})
+
+	macsJSON, err := json.Marshal(spec.TargetMacaroons)
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.InitiateBulkMigration", []interface{}{params.InitiateBulkMigrationArgs{
+			OwnerTag:      "user-bob",
+			MaxConcurrent: 2,
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag:   names.NewControllerTag(spec.TargetControllerUUID).String(),
+				ControllerAlias: spec.TargetControllerAlias,
+				Addrs:           spec.TargetAddrs,
+				CACert:          spec.TargetCACert,
+				AuthTag:         names.NewUserTag(spec.TargetUser).String(),
+				Password:        spec.TargetPassword,
+				Macaroons:       string(macsJSON),
+			},
+		}}},
+	})
+}
+
+func (s *Suite) TestInitiateBulkMigrationValidationError(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	spec := makeBulkSpec()
+	spec.ModelUUIDs = []string{randomUUID()}
+	_, _, err := client.InitiateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, "client-side validation failed: both models and owner not valid")
+
+	spec = makeBulkSpec()
+	spec.MaxConcurrent = 0
+	_, _, err = client.InitiateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, "client-side validation failed: max concurrent migrations 0 not valid")
+	c.Check(stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestInitiateBulkMigrationAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 9}
+	client := controller.NewClient(apiCaller)
+	_, _, err := client.InitiateBulkMigration(makeBulkSpec())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestBulkMigrationStatus(c *gc.C) {
+	var stub jujutesting.Stub
+	status := params.BulkMigrationStatus{
+		Id:            "0",
+		InitiatedBy:   "admin",
+		MaxConcurrent: 1,
+		Models: []params.BulkMigrationModelStatus{{
+			ModelTag:  names.NewModelTag(randomUUID()).String(),
+			ModelName: "foo",
+			Phase:     "QUIESCE",
+		}},
+	}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.BulkMigrationStatusResults)) = params.BulkMigrationStatusResults{
+				Results: []params.BulkMigrationStatusResult{{Result: &status}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	statuses, err := client.BulkMigrationStatus("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statuses, jc.DeepEquals, []params.BulkMigrationStatus{status})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.BulkMigrationStatus", []interface{}{params.BulkMigrationStatusArgs{Ids: []string{"0"}}}},
+	})
+}
+
+func (s *Suite) TestBulkMigrationStatusError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.BulkMigrationStatusResults)) = params.BulkMigrationStatusResults{
+				Results: []params.BulkMigrationStatusResult{{
+					Error: apiservererrors.ServerError(errors.NotFoundf("bulk migration %q", "42")),
+				}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.BulkMigrationStatus("42")
+	c.Check(err, gc.ErrorMatches, `bulk migration "42" not found`)
+}
+
+func (s *Suite) TestInitiateOfflineMigration(c *gc.C) {
+	var stub jujutesting.Stub
+	modelUUID := randomUUID()
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.InitiateMigrationResults)) = params.InitiateMigrationResults{
+				Results: []params.InitiateMigrationResult{{MigrationId: "id"}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	id, err := client.InitiateOfflineMigration(modelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(id, gc.Equals, "id")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.InitiateMigration", []interface{}{params.InitiateMigrationArgs{
+			Specs: []params.MigrationSpec{{
+				ModelTag: names.NewModelTag(modelUUID).String(),
+				Offline:  true,
+			}},
+		}}},
+	})
+}
+
+func (s *Suite) TestInitiateOfflineMigrationAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 9}
+	client := controller.NewClient(apiCaller)
+	_, err := client.InitiateOfflineMigration(randomUUID())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *Suite) TestMigrationPhase(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.MigrationPhaseResults)) = params.MigrationPhaseResults{
+				Results: []params.MigrationPhaseResult{{Phase: "IMPORT", StatusMessage: "waiting"}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	phase, message, err := client.MigrationPhase("id")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(phase, gc.Equals, coremigration.IMPORT)
+	c.Check(message, gc.Equals, "waiting")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.MigrationPhase", []interface{}{params.MigrationIds{Ids: []string{"id"}}}},
+	})
+}
+
+func (s *Suite) TestSetMigrationTarget(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	controllerUUID := randomUUID()
+	err := client.SetMigrationTarget("id", controller.MigrationTarget{
+		ControllerUUID:  controllerUUID,
+		ControllerAlias: "target",
+		Addrs:           []string{"1.2.3.4:17070"},
+		CACert:          "cert",
+		User:            "admin",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.SetMigrationTarget", []interface{}{params.SetMigrationTargetArgs{
+			Args: []params.SetMigrationTargetArg{{
+				MigrationId: "id",
+				TargetInfo: params.MigrationTargetInfo{
+					ControllerTag:   names.NewControllerTag(controllerUUID).String(),
+					ControllerAlias: "target",
+					Addrs:           []string{"1.2.3.4:17070"},
+					CACert:          "cert",
+					AuthTag:         "user-admin",
+				},
+			}},
+		}}},
+	})
+}
+
+func (s *Suite) TestAbortMigration(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: apiservererrors.ServerError(errors.New("boom"))}},
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	err := client.AbortMigration("id")
+	c.Check(err, gc.ErrorMatches, "boom")
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.AbortMigration", []interface{}{params.MigrationIds{Ids: []string{"id"}}}},
+	})
+}
+
 func (s *Suite) TestHostedModelConfigs_CallError(c *gc.C) {
 	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
 		return errors.New("boom")
@@ -256,6 +537,21 @@ func makeInitiateMigrationClient(results params.InitiateMigrationResults) (
 	return client, &stub
 }
 
+func makeBulkSpec() controller.BulkMigrationSpec {
+	spec := makeSpec()
+	return controller.BulkMigrationSpec{
+		Owner:                 "bob",
+		MaxConcurrent:         2,
+		TargetControllerUUID:  spec.TargetControllerUUID,
+		TargetControllerAlias: spec.TargetControllerAlias,
+		TargetAddrs:           spec.TargetAddrs,
+		TargetCACert:          spec.TargetCACert,
+		TargetUser:            spec.TargetUser,
+		TargetPassword:        spec.TargetPassword,
+		TargetMacaroons:       spec.TargetMacaroons,
+	}
+}
+
 func makeSpec() controller.MigrationSpec {
 	mac, err := macaroon.New([]byte("secret"), []byte("id"), "location", macaroon.LatestVersion)
 	if err != nil {
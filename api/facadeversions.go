@@ -18,7 +18,7 @@ var facadeVersions = map[string]int{
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  12,
+	"Application":                  15,
 	"ApplicationOffers":            2,
 	"ApplicationScaler":            1,
 	"Backups":                      2,
@@ -34,11 +34,11 @@ var facadeVersions = map[string]int{
 	"CAASUnitProvisioner":          1,
 	"CharmHub":                     1,
 	"CharmRevisionUpdater":         2,
-	"Charms":                       2,
+	"Charms":                       3,
 	"Cleaner":                      2,
-	"Client":                       2,
+	"Client":                       4,
 	"Cloud":                        7,
-	"Controller":                   9,
+	"Controller":                   10,
 	"CredentialManager":            1,
 	"CredentialValidator":          2,
 	"CrossController":              1,
@@ -49,7 +49,7 @@ var facadeVersions = map[string]int{
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   5,
+	"Firewaller":                   6,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
@@ -65,7 +65,7 @@ var facadeVersions = map[string]int{
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               6,
+	"MachineManager":               8,
 	"MachineUndertaker":            1,
 	"Machiner":                     4,
 	"MeterStatus":                  2,
@@ -76,7 +76,7 @@ var facadeVersions = map[string]int{
 	"MigrationMaster":              2,
 	"MigrationMinion":              1,
 	"MigrationStatusWatcher":       1,
-	"MigrationTarget":              1,
+	"MigrationTarget":              2,
 	"ModelConfig":                  2,
 	"ModelGeneration":              4,
 	"ModelManager":                 8,
@@ -87,7 +87,7 @@ var facadeVersions = map[string]int{
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
-	"Provisioner":                  11,
+	"Provisioner":                  12,
 	"ProxyUpdater":                 2,
 	"Reboot":                       2,
 	"RelationStatusWatcher":        1,
@@ -98,6 +98,7 @@ var facadeVersions = map[string]int{
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
+	"RoleManager":                  1,
 	"Singular":                     2,
 	"Spaces":                       6,
 	"SSHClient":                    2,
@@ -106,13 +107,14 @@ var facadeVersions = map[string]int{
 	"StorageProvisioner":           4,
 	"StringsWatcher":               1,
 	"Subnets":                      4,
+	"TokenManager":                 1,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
 	"Uniter":                       16,
 	"Upgrader":                     1,
 	"UpgradeSeries":                3,
 	"UpgradeSteps":                 2,
-	"UserManager":                  2,
+	"UserManager":                  3,
 	"VolumeAttachmentsWatcher":     2,
 	"VolumeAttachmentPlansWatcher": 1,
 }
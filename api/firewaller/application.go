@@ -64,3 +64,35 @@ func (s *Application) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposeInfo returns whether this application is exposed, along with the
+// sources allowed to access the ports of each of its endpoints. The space
+// IDs in each ExposeToSpaceIDs are resolved into the CIDRs of the subnets
+// of those spaces in ExposeToCIDRs. No expose settings are returned for
+// applications exposed to everywhere, or by controllers which predate
+// them.
+func (s *Application) ExposeInfo() (bool, map[string]params.ExposedEndpoint, error) {
+	if s.st.BestAPIVersion() < 6 {
+		exposed, err := s.IsExposed()
+		return exposed, nil, err
+	}
+	var results params.ExposeInfoResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposeInfo", args, &results)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(results.Results) != 1 {
+		return false, nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return false, nil, errors.NewNotFound(result.Error, "")
+		}
+		return false, nil, result.Error
+	}
+	return result.Exposed, result.ExposedEndpoints, nil
+}
@@ -9,7 +9,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/state"
 )
 
 type applicationSuite struct {
@@ -78,3 +80,25 @@ func (s *applicationSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *applicationSuite) TestExposeInfo(c *gc.C) {
+	err := s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	isExposed, exposedEndpoints, err := s.apiApplication.ExposeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isExposed, jc.IsTrue)
+	c.Assert(exposedEndpoints, jc.DeepEquals, map[string]params.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	err = s.application.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	isExposed, exposedEndpoints, err = s.apiApplication.ExposeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isExposed, jc.IsFalse)
+	c.Assert(exposedEndpoints, gc.HasLen, 0)
+}
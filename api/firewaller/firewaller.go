@@ -126,6 +126,23 @@ func (c *Client) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	return w, nil
 }
 
+// WatchSpaceSubnets returns a NotifyWatcher that notifies when subnets
+// are added to, removed from or moved between the model's spaces.
+func (c *Client) WatchSpaceSubnets() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("watching space subnets")
+	}
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchSpaceSubnets", nil, &result); err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // Relation provides access to methods of a state.Relation through the
 // facade.
 func (c *Client) Relation(tag names.RelationTag) (*Relation, error) {
@@ -108,9 +108,20 @@ func (m *Machine) ActiveSubnets() ([]names.SubnetTag, error) {
 	return tags, nil
 }
 
-// OpenedPorts returns a map of network.PortRange to unit tag for all opened
-// port ranges on the machine for the subnet matching given subnetTag.
-func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]names.UnitTag, error) {
+// PortRangeOpener identifies the unit that opened a port range, and
+// the endpoint of the unit the port range is opened for.
+type PortRangeOpener struct {
+	UnitTag names.UnitTag
+
+	// Endpoint is empty when the port range is opened for all of
+	// the unit's endpoints.
+	Endpoint string
+}
+
+// OpenedPorts returns a map of network.PortRange to the unit and
+// endpoint that opened it for all opened port ranges on the machine for
+// the subnet matching given subnetTag.
+func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]PortRangeOpener, error) {
 	var results params.MachinePortsResults
 	var subnetTagAsString string
 	if subnetTag.Id() != "" {
@@ -133,13 +144,16 @@ func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]
 		return nil, result.Error
 	}
 	// Convert string tags to names.UnitTag before returning.
-	endResult := make(map[network.PortRange]names.UnitTag)
+	endResult := make(map[network.PortRange]PortRangeOpener)
 	for _, ports := range result.Ports {
 		unitTag, err := names.ParseUnitTag(ports.UnitTag)
 		if err != nil {
 			return nil, err
 		}
-		endResult[ports.PortRange.NetworkPortRange()] = unitTag
+		endResult[ports.PortRange.NetworkPortRange()] = PortRangeOpener{
+			UnitTag:  unitTag,
+			Endpoint: ports.Endpoint,
+		}
 	}
 	return endResult, nil
 }
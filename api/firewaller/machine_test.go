@@ -139,8 +139,22 @@ func (s *machineSuite) TestOpenedPorts(c *gc.C) {
 	s.AssertOpenUnitPort(c, s.units[0], "", "tcp", 1234)
 	ports, err = s.apiMachine.OpenedPorts(names.SubnetTag{})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(ports, jc.DeepEquals, map[network.PortRange]names.UnitTag{
-		{FromPort: 1234, ToPort: 1234, Protocol: "tcp"}: unitTag,
+	c.Assert(ports, jc.DeepEquals, map[network.PortRange]firewaller.PortRangeOpener{
+		{FromPort: 1234, ToPort: 1234, Protocol: "tcp"}: {UnitTag: unitTag},
+	})
+
+	// Open a port for an endpoint.
+	op, err := s.units[0].OpenClosePortsOnEndpointsOperation(map[string][]network.PortRange{
+		"url": {network.MustParsePortRange("80/tcp")},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+	ports, err = s.apiMachine.OpenedPorts(names.SubnetTag{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, jc.DeepEquals, map[network.PortRange]firewaller.PortRangeOpener{
+		{FromPort: 1234, ToPort: 1234, Protocol: "tcp"}: {UnitTag: unitTag},
+		{FromPort: 80, ToPort: 80, Protocol: "tcp"}:     {UnitTag: unitTag, Endpoint: "url"},
 	})
 }
 
@@ -37,6 +37,13 @@ func NewClient(st base.APICallCloser) *Client {
 
 // AddMachines adds new machines with the supplied parameters, creating any requested disks.
 func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]params.AddMachinesResult, error) {
+	if client.BestAPIVersion() < 8 {
+		for _, p := range machineParams {
+			if p.CloudInitUserData != "" {
+				return nil, errors.NotSupportedf("cloud-init user data for a machine")
+			}
+		}
+	}
 	args := params.AddMachines{
 		MachineParams: machineParams,
 	}
@@ -259,3 +266,59 @@ func (client *Client) GetUpgradeSeriesMessages(machineName, watcherId string) ([
 
 	return result.Result, nil
 }
+
+// AddMachinePool adds a machine pool, whose machines the provisioner
+// starts and keeps at the pool's target size.
+func (client *Client) AddMachinePool(pool params.MachinePool) error {
+	if client.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("machine pools")
+	}
+	args := params.MachinePools{Pools: []params.MachinePool{pool}}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("AddMachinePools", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return apiservererrors.RestoreError(results.OneError())
+}
+
+// MachinePools returns the machine pools in the model.
+func (client *Client) MachinePools() ([]params.MachinePool, error) {
+	if client.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("machine pools")
+	}
+	var result params.MachinePools
+	if err := client.facade.FacadeCall("MachinePools", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Pools, nil
+}
+
+// SetMachinePoolSize sets the number of machines the named machine
+// pool is kept at.
+func (client *Client) SetMachinePoolSize(name string, size int) error {
+	if client.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("machine pools")
+	}
+	args := params.MachinePoolSizes{
+		Sizes: []params.MachinePoolSize{{Name: name, TargetSize: size}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("SetMachinePoolSizes", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return apiservererrors.RestoreError(results.OneError())
+}
+
+// RemoveMachinePool removes the named machine pool. Its machines are
+// left in place.
+func (client *Client) RemoveMachinePool(name string) error {
+	if client.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("machine pools")
+	}
+	args := params.MachinePoolNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("RemoveMachinePools", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return apiservererrors.RestoreError(results.OneError())
+}
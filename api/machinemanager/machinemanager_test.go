@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"time"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/storage"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -71,6 +73,43 @@ func (s *MachinemanagerSuite) TestAddMachines(c *gc.C) {
 	c.Check(callCount, gc.Equals, 1)
 }
 
+func (s *MachinemanagerSuite) TestAddMachinesCloudInitUserData(c *gc.C) {
+	machineParams := []params.AddMachineParams{{
+		Series:            "focal",
+		CloudInitUserData: "packages: [htop]",
+	}}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 8,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "AddMachines")
+				c.Check(a, jc.DeepEquals, params.AddMachines{MachineParams: machineParams})
+				*(response.(*params.AddMachinesResults)) = params.AddMachinesResults{
+					Machines: []params.AddMachinesResult{{Machine: "machine-1"}},
+				}
+				return nil
+			})})
+	results, err := client.AddMachines(machineParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.AddMachinesResult{{Machine: "machine-1"}})
+}
+
+func (s *MachinemanagerSuite) TestAddMachinesCloudInitUserDataNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			})})
+	_, err := client.AddMachines([]params.AddMachineParams{{
+		Series:            "focal",
+		CloudInitUserData: "packages: [htop]",
+	}})
+	c.Assert(err, gc.ErrorMatches, "cloud-init user data for a machine not supported")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
+
 func (s *MachinemanagerSuite) TestAddMachinesClientError(c *gc.C) {
 	st := newClient(func(objType string, version int, id, request string, arg, result interface{}) error {
 		return errors.New("blargh")
@@ -248,3 +287,90 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsNilWait(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
+
+func (s *MachinemanagerSuite) TestAddMachinePool(c *gc.C) {
+	pool := params.MachinePool{
+		Name:        "workers",
+		Constraints: constraints.MustParse("mem=4G"),
+		Zones:       []string{"zone-a", "zone-b"},
+		TargetSize:  3,
+	}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "AddMachinePools")
+				c.Check(a, jc.DeepEquals, params.MachinePools{Pools: []params.MachinePool{pool}})
+				c.Assert(response, gc.FitsTypeOf, &params.ErrorResults{})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{
+					Error: &params.Error{Message: `machine pool "workers" already exists`, Code: params.CodeAlreadyExists},
+				}}}
+				return nil
+			})})
+	err := client.AddMachinePool(pool)
+	c.Assert(err, gc.ErrorMatches, `machine pool "workers" already exists`)
+	c.Assert(err, jc.Satisfies, jujuerrors.IsAlreadyExists)
+}
+
+func (s *MachinemanagerSuite) TestMachinePools(c *gc.C) {
+	expected := []params.MachinePool{{
+		Name:       "workers",
+		Series:     "focal",
+		TargetSize: 2,
+		Machines:   []string{"1", "2"},
+	}}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "MachinePools")
+				c.Check(a, gc.IsNil)
+				*(response.(*params.MachinePools)) = params.MachinePools{Pools: expected}
+				return nil
+			})})
+	pools, err := client.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestSetMachinePoolSize(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "SetMachinePoolSizes")
+				c.Check(a, jc.DeepEquals, params.MachinePoolSizes{
+					Sizes: []params.MachinePoolSize{{Name: "workers", TargetSize: 5}},
+				})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+				return nil
+			})})
+	err := client.SetMachinePoolSize("workers", 5)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachinemanagerSuite) TestRemoveMachinePool(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Check(request, gc.Equals, "RemoveMachinePools")
+				c.Check(a, jc.DeepEquals, params.MachinePoolNames{Names: []string{"workers"}})
+				*(response.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+				return nil
+			})})
+	err := client.RemoveMachinePool("workers")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachinemanagerSuite) TestMachinePoolsNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected call to %s", request)
+				return nil
+			})})
+	_, err := client.MachinePools()
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
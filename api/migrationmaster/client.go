@@ -79,7 +79,19 @@ func (c *Client) MigrationStatus() (migration.MigrationStatus, error) {
 		return empty, errors.New("unable to parse phase")
 	}
 
+	out := migration.MigrationStatus{
+		MigrationId:      status.MigrationId,
+		ModelUUID:        modelTag.Id(),
+		Phase:            phase,
+		PhaseChangedTime: status.PhaseChangedTime,
+		Offline:          status.Spec.Offline,
+	}
 	target := status.Spec.TargetInfo
+	if status.Spec.Offline && target.ControllerTag == "" {
+		// The model archive hasn't been imported yet.
+		return out, nil
+	}
+
 	controllerTag, err := names.ParseControllerTag(target.ControllerTag)
 	if err != nil {
 		return empty, errors.Annotatef(err, "parsing controller tag")
@@ -97,20 +109,15 @@ func (c *Client) MigrationStatus() (migration.MigrationStatus, error) {
 		}
 	}
 
-	return migration.MigrationStatus{
-		MigrationId:      status.MigrationId,
-		ModelUUID:        modelTag.Id(),
-		Phase:            phase,
-		PhaseChangedTime: status.PhaseChangedTime,
-		TargetInfo: migration.TargetInfo{
-			ControllerTag: controllerTag,
-			Addrs:         target.Addrs,
-			CACert:        target.CACert,
-			AuthTag:       authTag,
-			Password:      target.Password,
-			Macaroons:     macs,
-		},
-	}, nil
+	out.TargetInfo = migration.TargetInfo{
+		ControllerTag: controllerTag,
+		Addrs:         target.Addrs,
+		CACert:        target.CACert,
+		AuthTag:       authTag,
+		Password:      target.Password,
+		Macaroons:     macs,
+	}
+	return out, nil
 }
 
 // SetPhase updates the phase of the currently active model migration.
@@ -125,6 +125,34 @@ func (s *ClientSuite) TestMigrationStatus(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestMigrationStatusOffline(c *gc.C) {
+	modelUUID := utils.MustNewUUID().String()
+	timestamp := time.Date(2016, 6, 22, 16, 42, 44, 0, time.UTC)
+	apiCaller := apitesting.APICallerFunc(func(_ string, _ int, _, _ string, _, result interface{}) error {
+		out := result.(*params.MasterMigrationStatus)
+		*out = params.MasterMigrationStatus{
+			Spec: params.MigrationSpec{
+				ModelTag: names.NewModelTag(modelUUID).String(),
+				Offline:  true,
+			},
+			MigrationId:      "id",
+			Phase:            "IMPORT",
+			PhaseChangedTime: timestamp,
+		}
+		return nil
+	})
+	client := migrationmaster.NewClient(apiCaller, nil)
+	status, err := client.MigrationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.DeepEquals, migration.MigrationStatus{
+		MigrationId:      "id",
+		ModelUUID:        modelUUID,
+		Phase:            migration.IMPORT,
+		PhaseChangedTime: timestamp,
+		Offline:          true,
+	})
+}
+
 func (s *ClientSuite) TestSetPhase(c *gc.C) {
 	var stub jujutesting.Stub
 	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -53,6 +53,34 @@ func (c *Client) Prechecks(model coremigration.ModelInfo) error {
 	return errors.Trace(c.caller.FacadeCall("Prechecks", args, nil))
 }
 
+// PrecheckReport runs the same checks as Prechecks, returning all of
+// the checks which failed. It returns a NotSupported error if the target
+// controller can't report on its prechecks.
+func (c *Client) PrecheckReport(model coremigration.ModelInfo) ([]coremigration.PrecheckFailure, error) {
+	if c.caller.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("migration precheck reports")
+	}
+	args := params.MigrationModelInfo{
+		UUID:                   model.UUID,
+		Name:                   model.Name,
+		OwnerTag:               model.Owner.String(),
+		AgentVersion:           model.AgentVersion,
+		ControllerAgentVersion: model.ControllerAgentVersion,
+	}
+	var result params.MigrationPrecheckFailures
+	if err := c.caller.FacadeCall("PrecheckReport", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	failures := make([]coremigration.PrecheckFailure, len(result.Failures))
+	for i, failure := range result.Failures {
+		failures[i] = coremigration.PrecheckFailure{
+			Entity:  failure.Entity,
+			Message: failure.Message,
+		}
+	}
+	return failures, nil
+}
+
 // Import takes a serialized model and imports it into the target
 // controller.
 func (c *Client) Import(bytes []byte) error {
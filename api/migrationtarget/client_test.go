@@ -78,6 +78,54 @@ func (s *ClientSuite) TestPrechecks(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestPrecheckReport(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			*(result.(*params.MigrationPrecheckFailures)) = params.MigrationPrecheckFailures{
+				Failures: []params.MigrationPrecheckFailure{{
+					Entity:  "controller machine 0",
+					Message: "machine 0 agent not functioning at this time (down)",
+				}},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+
+	info := coremigration.ModelInfo{
+		UUID:                   "uuid",
+		Owner:                  names.NewUserTag("owner"),
+		Name:                   "name",
+		AgentVersion:           version.MustParse("1.2.3"),
+		ControllerAgentVersion: version.MustParse("1.2.5"),
+	}
+	failures, err := client.PrecheckReport(info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, jc.DeepEquals, []coremigration.PrecheckFailure{{
+		Entity:  "controller machine 0",
+		Message: "machine 0 agent not functioning at this time (down)",
+	}})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.PrecheckReport", []interface{}{"", params.MigrationModelInfo{
+			UUID:                   "uuid",
+			Name:                   "name",
+			OwnerTag:               "user-owner",
+			AgentVersion:           version.MustParse("1.2.3"),
+			ControllerAgentVersion: version.MustParse("1.2.5"),
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestPrecheckReportNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.PrecheckReport(coremigration.ModelInfo{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
 func (s *ClientSuite) TestImport(c *gc.C) {
 	client, stub := s.getClientAndStub(c)
 
@@ -137,6 +137,23 @@ func (st *State) WatchMachineErrorRetry() (watcher.NotifyWatcher, error) {
 	return w, nil
 }
 
+// ReconcileMachinePools asks the controller to replace the lost machines
+// of the model's machine pools, and to bring the pools to their target
+// sizes.
+func (st *State) ReconcileMachinePools() error {
+	if st.facade.BestAPIVersion() < 12 {
+		return errors.NotSupportedf("machine pools")
+	}
+	var result params.ErrorResult
+	if err := st.facade.FacadeCall("ReconcileMachinePools", nil, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // StateAddresses returns the list of addresses used to connect to the state.
 func (st *State) StateAddresses() ([]string, error) {
 	var result params.StringsResult
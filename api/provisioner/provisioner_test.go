@@ -732,6 +732,30 @@ func (s *provisionerSuite) TestContainerConfigV5(c *gc.C) {
 	c.Assert(result.SSLHostnameVerification, jc.IsTrue)
 }
 
+func (s *provisionerSuite) TestReconcileMachinePools(c *gc.C) {
+	pool, err := s.State.AddMachinePool(state.MachinePoolArgs{
+		Name:       "workers",
+		Series:     "quantal",
+		TargetSize: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.provisioner.ReconcileMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	err = pool.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool.MachineIds(), gc.HasLen, 2)
+}
+
+func (s *provisionerSuite) TestReconcileMachinePoolsNotSupported(c *gc.C) {
+	caller := apibasetesting.BestVersionCaller{
+		APICallerFunc: s.st.APICall,
+		BestVersion:   11,
+	}
+	err := provisioner.NewState(caller).ReconcileMachinePools()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *provisionerSuite) TestSetSupportedContainers(c *gc.C) {
 	apiMachine := s.assertGetOneMachine(c, s.machine.MachineTag())
 	err := apiMachine.SetSupportedContainers(instance.LXD, instance.KVM)
@@ -0,0 +1,102 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rolemanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods that the Juju client command uses to manage
+// the custom roles in a controller.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "RoleManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddRole adds a custom role made up of the given capabilities and
+// facade method patterns.
+func (c *Client) AddRole(name string, permissions ...string) error {
+	args := params.AddRoles{
+		Roles: []params.RoleDefinition{{Name: name, Permissions: permissions}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddRole", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RemoveRole removes the named custom roles, along with their
+// assignments to users.
+func (c *Client) RemoveRole(names ...string) error {
+	args := params.RoleNames{Names: names}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveRole", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// Roles returns information on the named custom roles, or on all the
+// roles if no names are given.
+func (c *Client) Roles(roles ...string) ([]params.RoleInfo, error) {
+	args := params.RoleNames{Names: roles}
+	var results params.RoleInfoResults
+	if err := c.facade.FacadeCall("Roles", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := make([]params.RoleInfo, 0, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			if i < len(roles) {
+				return nil, errors.Annotatef(result.Error, "role %q", roles[i])
+			}
+			return nil, errors.Trace(result.Error)
+		}
+		info = append(info, *result.Result)
+	}
+	return info, nil
+}
+
+// AssignRole assigns the custom role to the user on the model or
+// controller, replacing any role previously assigned there.
+func (c *Client) AssignRole(user, role string, target names.Tag) error {
+	return errors.Trace(c.modifyRoleAssignment(params.AssignRole, user, role, target))
+}
+
+// UnassignRole removes the custom role assigned to the user on the
+// model or controller.
+func (c *Client) UnassignRole(user string, target names.Tag) error {
+	return errors.Trace(c.modifyRoleAssignment(params.UnassignRole, user, "", target))
+}
+
+func (c *Client) modifyRoleAssignment(action params.RoleAssignmentAction, user, role string, target names.Tag) error {
+	if !names.IsValidUser(user) {
+		return errors.NotValidf("user name %q", user)
+	}
+	args := params.ModifyRoleAssignments{
+		Changes: []params.ModifyRoleAssignment{{
+			Action:    action,
+			Role:      role,
+			UserTag:   names.NewUserTag(user).String(),
+			TargetTag: target.String(),
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ModifyRoleAssignments", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
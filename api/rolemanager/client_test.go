@@ -0,0 +1,94 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rolemanager_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/rolemanager"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type roleManagerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&roleManagerSuite{})
+
+func (s *roleManagerSuite) TestAddRole(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "RoleManager")
+			c.Check(request, gc.Equals, "AddRole")
+			c.Check(arg, jc.DeepEquals, params.AddRoles{
+				Roles: []params.RoleDefinition{{
+					Name:        "operator",
+					Permissions: []string{"ssh", "run-actions"},
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := rolemanager.NewClient(apiCaller)
+	err := client.AddRole("operator", "ssh", "run-actions")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *roleManagerSuite) TestRoles(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "Roles")
+			c.Check(arg, jc.DeepEquals, params.RoleNames{Names: []string{"operator", "missing"}})
+			*(result.(*params.RoleInfoResults)) = params.RoleInfoResults{
+				Results: []params.RoleInfoResult{{
+					Result: &params.RoleInfo{Name: "operator"},
+				}, {
+					Error: &params.Error{Message: "not found", Code: params.CodeNotFound},
+				}},
+			}
+			return nil
+		})
+	client := rolemanager.NewClient(apiCaller)
+	_, err := client.Roles("operator", "missing")
+	c.Assert(err, gc.ErrorMatches, `role "missing": not found`)
+}
+
+func (s *roleManagerSuite) TestAssignRole(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "ModifyRoleAssignments")
+			c.Check(arg, jc.DeepEquals, params.ModifyRoleAssignments{
+				Changes: []params.ModifyRoleAssignment{{
+					Action:    params.AssignRole,
+					Role:      "operator",
+					UserTag:   "user-alice",
+					TargetTag: coretesting.ModelTag.String(),
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := rolemanager.NewClient(apiCaller)
+	err := client.AssignRole("alice", "operator", coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *roleManagerSuite) TestUnassignRoleInvalidUser(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		})
+	client := rolemanager.NewClient(apiCaller)
+	err := client.UnassignRole("not/valid", names.NewControllerTag(coretesting.ControllerTag.Id()))
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
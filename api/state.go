@@ -62,6 +62,18 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 	}
 	err := st.APICall("Admin", 3, "", "Login", request, &result)
 	if err != nil {
+		if params.IsCodeOIDCLoginRequired(err) {
+			if rpcErr, ok := errors.Cause(err).(*rpc.RequestError); ok {
+				var oidcInfo params.OIDCLoginRequiredErrorInfo
+				if err := rpcErr.UnmarshalInfo(&oidcInfo); err == nil && oidcInfo.IssuerURL != "" {
+					return &OIDCLoginRequiredError{
+						IssuerURL: oidcInfo.IssuerURL,
+						ClientID:  oidcInfo.ClientID,
+					}
+				}
+			}
+			return errors.Trace(err)
+		}
 		if !params.IsRedirect(err) {
 			return errors.Trace(err)
 		}
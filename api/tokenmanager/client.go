@@ -0,0 +1,82 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tokenmanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods that the Juju client command uses to manage
+// the API tokens issued to service accounts.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "TokenManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddAPIToken issues an API token to the service account, granting
+// the access on the models until the token expires. It returns the
+// token's value, which cannot be retrieved again, along with
+// information on the token.
+func (c *Client) AddAPIToken(
+	account, access string, expires time.Time, models ...names.ModelTag,
+) (string, *params.APITokenInfo, error) {
+	modelTags := make([]string, len(models))
+	for i, model := range models {
+		modelTags[i] = model.String()
+	}
+	args := params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			Account:   account,
+			ModelTags: modelTags,
+			Access:    access,
+			Expires:   expires,
+		}},
+	}
+	var results params.AddAPITokenResults
+	if err := c.facade.FacadeCall("AddAPIToken", args, &results); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", nil, errors.Trace(result.Error)
+	}
+	return result.Token, result.Info, nil
+}
+
+// APITokens returns information on the API tokens issued to the
+// service account, or on all API tokens if no account is given.
+func (c *Client) APITokens(account string) ([]params.APITokenInfo, error) {
+	args := params.APITokenFilter{Account: account}
+	var result params.APITokensResult
+	if err := c.facade.FacadeCall("APITokens", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Tokens, nil
+}
+
+// RevokeAPIToken revokes the API tokens with the given IDs.
+func (c *Client) RevokeAPIToken(ids ...string) error {
+	args := params.APITokenIDs{IDs: ids}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeAPIToken", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
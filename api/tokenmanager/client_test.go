@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tokenmanager_test
+
+import (
+	"time"
+
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/tokenmanager"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type tokenManagerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&tokenManagerSuite{})
+
+func (s *tokenManagerSuite) TestAddAPIToken(c *gc.C) {
+	expires := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "TokenManager")
+			c.Check(request, gc.Equals, "AddAPIToken")
+			c.Check(arg, jc.DeepEquals, params.AddAPITokens{
+				Tokens: []params.AddAPIToken{{
+					Account:   "ci",
+					ModelTags: []string{coretesting.ModelTag.String()},
+					Access:    "write",
+					Expires:   expires,
+				}},
+			})
+			*(result.(*params.AddAPITokenResults)) = params.AddAPITokenResults{
+				Results: []params.AddAPITokenResult{{
+					Token: "jujutoken.abc.secret",
+					Info:  &params.APITokenInfo{ID: "abc", Account: "ci"},
+				}},
+			}
+			return nil
+		})
+	client := tokenmanager.NewClient(apiCaller)
+	token, info, err := client.AddAPIToken("ci", "write", expires, coretesting.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token, gc.Equals, "jujutoken.abc.secret")
+	c.Assert(info, jc.DeepEquals, &params.APITokenInfo{ID: "abc", Account: "ci"})
+}
+
+func (s *tokenManagerSuite) TestAddAPITokenError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.AddAPITokenResults)) = params.AddAPITokenResults{
+				Results: []params.AddAPITokenResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	client := tokenmanager.NewClient(apiCaller)
+	_, _, err := client.AddAPIToken("ci", "write", time.Now(), names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *tokenManagerSuite) TestAPITokens(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "APITokens")
+			c.Check(arg, jc.DeepEquals, params.APITokenFilter{Account: "ci"})
+			*(result.(*params.APITokensResult)) = params.APITokensResult{
+				Tokens: []params.APITokenInfo{{ID: "abc", Account: "ci"}},
+			}
+			return nil
+		})
+	client := tokenmanager.NewClient(apiCaller)
+	tokens, err := client.APITokens("ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, jc.DeepEquals, []params.APITokenInfo{{ID: "abc", Account: "ci"}})
+}
+
+func (s *tokenManagerSuite) TestRevokeAPIToken(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(request, gc.Equals, "RevokeAPIToken")
+			c.Check(arg, jc.DeepEquals, params.APITokenIDs{IDs: []string{"abc", "def"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}, {
+					Error: &params.Error{Message: `API token "def" not found`, Code: params.CodeNotFound},
+				}},
+			}
+			return nil
+		})
+	client := tokenmanager.NewClient(apiCaller)
+	err := client.RevokeAPIToken("abc", "def")
+	c.Assert(err, gc.ErrorMatches, `API token "def" not found`)
+}
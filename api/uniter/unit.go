@@ -24,6 +24,7 @@ type Unit struct {
 	life         life.Value
 	resolvedMode params.ResolvedMode
 	providerID   string
+	interrupting bool
 }
 
 // Tag returns the unit's tag.
@@ -56,6 +57,12 @@ func (u *Unit) Resolved() params.ResolvedMode {
 	return u.resolvedMode
 }
 
+// Interrupting returns whether the cloud has given notice that it will
+// reclaim the instance hosting the unit.
+func (u *Unit) Interrupting() bool {
+	return u.interrupting
+}
+
 // Refresh updates the cached local copy of the unit's data.
 func (u *Unit) Refresh() error {
 	var results params.UnitRefreshResults
@@ -79,6 +86,7 @@ func (u *Unit) Refresh() error {
 	u.life = result.Life
 	u.resolvedMode = result.Resolved
 	u.providerID = result.ProviderID
+	u.interrupting = result.Interrupting
 	return nil
 }
 
@@ -950,8 +958,9 @@ func NewCommitHookParamsBuilder(unitTag names.UnitTag) *CommitHookParamsBuilder
 	}
 }
 
-// OpenPortRange records a request to open a particular port range.
-func (b *CommitHookParamsBuilder) OpenPortRange(protocol string, fromPort, toPort int) {
+// OpenPortRange records a request to open a particular port range for
+// the named endpoint, or for all endpoints if the name is empty.
+func (b *CommitHookParamsBuilder) OpenPortRange(endpoint, protocol string, fromPort, toPort int) {
 	b.arg.OpenPorts = append(b.arg.OpenPorts, params.EntityPortRange{
 		// The Tag is optional as the call uses the Tag from the
 		// CommitHookChangesArg; it is included here for consistency.
@@ -959,6 +968,7 @@ func (b *CommitHookParamsBuilder) OpenPortRange(protocol string, fromPort, toPor
 		Protocol: protocol,
 		FromPort: fromPort,
 		ToPort:   toPort,
+		Endpoint: endpoint,
 	})
 }
 
@@ -196,9 +196,10 @@ func (s *unitSuite) TestRefresh(c *gc.C) {
 		c.Assert(result, gc.FitsTypeOf, &params.UnitRefreshResults{})
 		*(result.(*params.UnitRefreshResults)) = params.UnitRefreshResults{
 			Results: []params.UnitRefreshResult{{
-				Life:       life.Dying,
-				Resolved:   params.ResolvedRetryHooks,
-				ProviderID: "666",
+				Life:         life.Dying,
+				Resolved:     params.ResolvedRetryHooks,
+				ProviderID:   "666",
+				Interrupting: true,
 			}},
 		}
 		return nil
@@ -210,6 +211,7 @@ func (s *unitSuite) TestRefresh(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(unit.Life(), gc.Equals, life.Dying)
 	c.Assert(unit.Resolved(), gc.Equals, params.ResolvedRetryHooks)
+	c.Assert(unit.Interrupting(), jc.IsTrue)
 	c.Assert(unit.Life(), gc.Equals, life.Dying)
 }
 
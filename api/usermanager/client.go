@@ -207,3 +207,147 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// groupsSupported returns an error if the controller does not
+// support groups.
+func (c *Client) groupsSupported() error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("groups on this version of Juju")
+	}
+	return nil
+}
+
+// AddGroup adds a group of users to the controller.
+func (c *Client) AddGroup(name string) error {
+	return errors.Trace(c.groupCall("AddGroup", name))
+}
+
+// RemoveGroup removes a group, along with all the access granted to it.
+func (c *Client) RemoveGroup(name string) error {
+	return errors.Trace(c.groupCall("RemoveGroup", name))
+}
+
+func (c *Client) groupCall(methodCall, name string) error {
+	if err := c.groupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	var results params.ErrorResults
+	args := params.Groups{Names: []string{name}}
+	if err := c.facade.FacadeCall(methodCall, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// GroupInfo returns information on the named groups, or on all the
+// groups visible to the user if no names are given.
+func (c *Client) GroupInfo(groups ...string) ([]params.GroupInfo, error) {
+	if err := c.groupsSupported(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.GroupInfoResults
+	args := params.Groups{Names: groups}
+	if err := c.facade.FacadeCall("GroupInfo", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := make([]params.GroupInfo, 0, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			if i < len(groups) {
+				return nil, errors.Annotatef(result.Error, "group %q", groups[i])
+			}
+			return nil, errors.Trace(result.Error)
+		}
+		info = append(info, *result.Result)
+	}
+	return info, nil
+}
+
+// AddToGroup adds the given users to a group.
+func (c *Client) AddToGroup(group string, users ...string) error {
+	return errors.Trace(c.modifyGroupMembers(params.AddGroupMember, group, users))
+}
+
+// RemoveFromGroup removes the given users from a group.
+func (c *Client) RemoveFromGroup(group string, users ...string) error {
+	return errors.Trace(c.modifyGroupMembers(params.RemoveGroupMember, group, users))
+}
+
+func (c *Client) modifyGroupMembers(action params.GroupMemberAction, group string, users []string) error {
+	if err := c.groupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	args := params.ModifyGroupMembers{
+		Changes: make([]params.ModifyGroupMember, len(users)),
+	}
+	for i, user := range users {
+		if !names.IsValidUser(user) {
+			return errors.NotValidf("user name %q", user)
+		}
+		args.Changes[i] = params.ModifyGroupMember{
+			Action:  action,
+			Group:   group,
+			UserTag: names.NewUserTag(user).String(),
+		}
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("ModifyGroupMembers", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// GrantGroup grants a group access to the target, which may be a
+// controller, cloud or model tag.
+func (c *Client) GrantGroup(group, access string, target names.Tag) error {
+	return errors.Trace(c.modifyGroupAccess(params.ModifyGroupAccess{
+		Action:    params.GrantGroupAccess,
+		Group:     group,
+		Access:    access,
+		TargetTag: target.String(),
+	}))
+}
+
+// RevokeGroup revokes a group's access to the target, which may be a
+// controller, cloud or model tag.
+func (c *Client) RevokeGroup(group, access string, target names.Tag) error {
+	return errors.Trace(c.modifyGroupAccess(params.ModifyGroupAccess{
+		Action:    params.RevokeGroupAccess,
+		Group:     group,
+		Access:    access,
+		TargetTag: target.String(),
+	}))
+}
+
+// GrantGroupOffer grants a group access to the offer with the given URL.
+func (c *Client) GrantGroupOffer(group, access, offerURL string) error {
+	return errors.Trace(c.modifyGroupAccess(params.ModifyGroupAccess{
+		Action:   params.GrantGroupAccess,
+		Group:    group,
+		Access:   access,
+		OfferURL: offerURL,
+	}))
+}
+
+// RevokeGroupOffer revokes a group's access to the offer with the
+// given URL.
+func (c *Client) RevokeGroupOffer(group, access, offerURL string) error {
+	return errors.Trace(c.modifyGroupAccess(params.ModifyGroupAccess{
+		Action:   params.RevokeGroupAccess,
+		Group:    group,
+		Access:   access,
+		OfferURL: offerURL,
+	}))
+}
+
+func (c *Client) modifyGroupAccess(change params.ModifyGroupAccess) error {
+	if err := c.groupsSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	var results params.ErrorResults
+	args := params.ModifyGroupAccessRequest{Changes: []params.ModifyGroupAccess{change}}
+	if err := c.facade.FacadeCall("ModifyGroupAccess", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
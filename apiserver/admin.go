@@ -15,6 +15,7 @@ import (
 	"github.com/juju/rpcreflect"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
@@ -117,8 +118,7 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 	}
 
 	// apiRoot is the API root exposed to the client after login.
-	var apiRoot rpc.Root
-	apiRoot, err = newAPIRoot(
+	root, err := newAPIRoot(
 		a.srv.clock,
 		a.root.state,
 		a.root.shared,
@@ -129,6 +129,8 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
+	root.role = authResult.role
+	var apiRoot rpc.Root = root
 	apiRoot, err = restrictAPIRoot(
 		a.srv,
 		apiRoot,
@@ -204,6 +206,7 @@ type authResult struct {
 	controllerOnlyLogin    bool
 	controllerMachineLogin bool
 	userInfo               *params.AuthUserInfo
+	role                   *permission.Role // nil if no custom role applies
 }
 
 func (a *admin) authenticate(ctx context.Context, req params.LoginRequest) (*authResult, error) {
@@ -359,6 +362,9 @@ func (a *admin) handleAuthError(err error) error {
 	if err, ok := errors.Cause(err).(*apiservererrors.DischargeRequiredError); ok {
 		return err
 	}
+	if err, ok := errors.Cause(err).(*apiservererrors.OIDCLoginRequiredError); ok {
+		return err
+	}
 	if a.maintenanceInProgress() {
 		// An upgrade, restore or similar operation is in
 		// progress. It is possible for logins to fail until this
@@ -380,6 +386,10 @@ func (a *admin) fillLoginDetails(result *authResult, lastConnection *time.Time)
 			return errors.Trace(err)
 		}
 		result.userInfo.LastConnection = lastConnection
+		result.role, err = a.userRole(userTag, result.controllerOnlyLogin)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	if result.controllerOnlyLogin {
 		if result.anonymousLogin {
@@ -397,6 +407,54 @@ func (a *admin) fillLoginDetails(result *authResult, lastConnection *time.Time)
 	return nil
 }
 
+// userRole returns the custom role that restricts the API calls the
+// user can make on this connection, or nil if there is none.
+func (a *admin) userRole(userTag names.UserTag, controllerOnlyLogin bool) (*permission.Role, error) {
+	var modelTag *names.ModelTag
+	if !controllerOnlyLogin {
+		tag := a.root.model.ModelTag()
+		modelTag = &tag
+	}
+	return userRole(a.root.state, userTag, modelTag)
+}
+
+// userRole returns the custom role assigned to the user on the model,
+// if one is given, or else on the controller. It returns nil if no
+// custom role applies. A role assigned to the user on the model takes
+// precedence over one assigned on the controller.
+func userRole(st *state.State, userTag names.UserTag, modelTag *names.ModelTag) (*permission.Role, error) {
+	if modelTag != nil {
+		role, err := st.UserRole(userTag, *modelTag)
+		if err == nil {
+			definition := role.Definition()
+			return &definition, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, errors.Annotatef(err, "obtaining model role for logged in user %s", userTag.Id())
+		}
+	}
+	role, err := st.UserRole(userTag, st.ControllerTag())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "obtaining controller role for logged in user %s", userTag.Id())
+	}
+	definition := role.Definition()
+	return &definition, nil
+}
+
+// tokenModelAccess returns the highest access up to maxAccess that
+// the API token allows on the model.
+func tokenModelAccess(token authentication.APIToken, model names.ModelTag, maxAccess permission.Access) permission.Access {
+	for _, access := range []permission.Access{permission.AdminAccess, permission.WriteAccess, permission.ReadAccess} {
+		if !access.GreaterModelAccessThan(maxAccess) && token.Allows(access, model) {
+			return access
+		}
+	}
+	return permission.NoAccess
+}
+
 func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin bool) (*params.AuthUserInfo, error) {
 
 	modelAccess := permission.NoAccess
@@ -424,6 +482,20 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 	} else {
 		return nil, errors.Annotatef(err, "obtaining ControllerUser for logged in user %s", userTag.Id())
 	}
+	groupAccess, err := a.root.state.GroupPermission(userTag, a.root.state.ControllerTag())
+	if err != nil {
+		return nil, errors.Annotatef(err, "obtaining group access for logged in user %s", userTag.Id())
+	}
+	if groupAccess.GreaterControllerAccessThan(controllerAccess) {
+		controllerAccess = groupAccess
+	}
+	tokenAccess, err := a.root.state.ServiceAccountPermission(userTag, a.root.state.ControllerTag())
+	if err != nil {
+		return nil, errors.Annotatef(err, "obtaining API token access for logged in user %s", userTag.Id())
+	}
+	if tokenAccess.GreaterControllerAccessThan(controllerAccess) {
+		controllerAccess = tokenAccess
+	}
 	if !controllerOnlyLogin {
 		// Only grab modelUser permissions if this is not a controller only
 		// login. In all situations, if the model user is not found, they have
@@ -438,6 +510,14 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 		if err != nil && controllerAccess == permission.SuperuserAccess {
 			modelAccess = permission.AdminAccess
 		}
+		if entity, ok := a.root.entity.(*authentication.APITokenEntity); ok {
+			// A service account's other tokens may give it more
+			// access than the token it logged in with.
+			modelAccess = tokenModelAccess(entity.Token, a.root.model.ModelTag(), modelAccess)
+			if modelAccess == permission.NoAccess {
+				return nil, errors.Trace(apiservererrors.ErrPerm)
+			}
+		}
 	}
 
 	// It is possible that the everyoneGroup permissions are more capable than an
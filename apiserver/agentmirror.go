@@ -0,0 +1,149 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/agentmirror"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/binarystorage"
+	"github.com/juju/juju/state/cloudimagemetadata"
+	"github.com/juju/juju/tools"
+)
+
+// agentMirrorHandler handles agent mirror bundle imports through
+// HTTPS in the API server. The bundle contents are always added to
+// the controller model, so that every model on the controller can
+// use them.
+type agentMirrorHandler struct {
+	ctxt          httpContext
+	stateAuthFunc func(*http.Request) (*state.PooledState, error)
+}
+
+func (h *agentMirrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st, err := h.stateAuthFunc(r)
+	if err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	defer st.Release()
+
+	switch r.Method {
+	case "POST":
+		result, err := h.processPost(r, h.ctxt.srv.shared.statePool.SystemState())
+		if err != nil {
+			if err := sendError(w, err); err != nil {
+				logger.Errorf("%v", err)
+			}
+			return
+		}
+		if err := sendStatusAndJSON(w, http.StatusOK, result); err != nil {
+			logger.Errorf("%v", err)
+		}
+	default:
+		if err := sendError(w, errors.MethodNotAllowedf("unsupported method: %q", r.Method)); err != nil {
+			logger.Errorf("%v", err)
+		}
+	}
+}
+
+// processPost verifies the agent mirror bundle in the request body
+// against the controller's agent mirror keyring, and adds its agent
+// binaries and image metadata to the controller.
+func (h *agentMirrorHandler) processPost(r *http.Request, st *state.State) (*params.AgentMirrorResult, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/x-tar-gz" {
+		return nil, errors.BadRequestf("expected Content-Type: application/x-tar-gz, got: %v", contentType)
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keyring := controllerConfig.AgentMirrorKeyring()
+	if keyring == "" {
+		return nil, errors.BadRequestf("no %s set to verify agent mirror bundles", controller.AgentMirrorKeyring)
+	}
+	bundle, err := agentmirror.NewReader(r.Body, keyring)
+	if err != nil {
+		return nil, errors.NewBadRequest(err, "")
+	}
+	defer bundle.Close()
+
+	storage, err := st.ToolsStorage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer storage.Close()
+
+	// Each agent binary is streamed to storage, and fails to be stored
+	// if it does not match the signed manifest. The image metadata is
+	// only imported once all of the agent binaries have been verified.
+	serverRoot := fmt.Sprintf("https://%s/model/%s", r.Host, httpcontext.RequestModelUUID(r))
+	result := &params.AgentMirrorResult{}
+	for {
+		agent, err := bundle.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.NewBadRequest(err, "")
+		}
+		metadata := binarystorage.Metadata{
+			Version: agent.Version.String(),
+			Size:    agent.Size,
+			SHA256:  agent.SHA256,
+		}
+		logger.Debugf("importing agent binaries %+v to storage", metadata)
+		if err := storage.Add(bundle, metadata); err != nil {
+			return nil, errors.Annotatef(err, "importing agent binaries %s", agent.Version)
+		}
+		result.ToolsList = append(result.ToolsList, &tools.Tools{
+			Version: agent.Version,
+			Size:    metadata.Size,
+			SHA256:  metadata.SHA256,
+			URL:     common.ToolsURL(serverRoot, agent.Version),
+		})
+	}
+
+	if len(bundle.Images) == 0 {
+		return result, nil
+	}
+	images := make([]cloudimagemetadata.Metadata, len(bundle.Images))
+	for i, image := range bundle.Images {
+		images[i] = cloudimagemetadata.Metadata{
+			MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+				Stream:          image.Stream,
+				Region:          image.Region,
+				Version:         image.Version,
+				Series:          image.Series,
+				Arch:            image.Arch,
+				VirtType:        image.VirtType,
+				RootStorageType: image.RootStorageType,
+				Source:          "custom",
+			},
+			Priority: simplestreams.CUSTOM_CLOUD_DATA,
+			ImageId:  image.ImageId,
+		}
+	}
+	if err := st.CloudImageMetadataStorage.SaveMetadata(images); err != nil {
+		return nil, errors.Annotate(err, "importing image metadata")
+	}
+	result.Images = len(images)
+	return result, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/agentmirror"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/juju/keys"
+	"github.com/juju/juju/state/cloudimagemetadata"
+)
+
+type agentMirrorSuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&agentMirrorSuite{})
+
+func (s *agentMirrorSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.AgentMirrorKeyring: sstesting.SignedMetadataPublicKey,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *agentMirrorSuite) agentMirrorURI() string {
+	return s.URL(fmt.Sprintf("/model/%s/agent-mirror", s.Model.UUID()), nil).String()
+}
+
+func (s *agentMirrorSuite) bundle(c *gc.C) io.Reader {
+	var buf bytes.Buffer
+	err := agentmirror.Write(&buf, agentmirror.Bundle{
+		Created: time.Now(),
+		Agents: []agentmirror.AgentBinary{{
+			Version: version.MustParseBinary("2.8.1-focal-amd64"),
+			Data:    []byte("focal agent"),
+		}},
+		Images: []agentmirror.ImageMetadata{{
+			ImageId: "ami-1234",
+			Region:  "us-east-1",
+			Version: "20.04",
+			Series:  "focal",
+			Arch:    "amd64",
+		}},
+	}, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+	return &buf
+}
+
+func (s *agentMirrorSuite) importRequest(c *gc.C, contentType string, content io.Reader) *http.Response {
+	return s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:      "POST",
+		URL:         s.agentMirrorURI(),
+		ContentType: contentType,
+		Body:        content,
+	})
+}
+
+func (s *agentMirrorSuite) assertResponse(c *gc.C, resp *http.Response, expStatus int) params.AgentMirrorResult {
+	body := apitesting.AssertResponse(c, resp, expStatus, params.ContentTypeJSON)
+	var result params.AgentMirrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("Body: %s", body))
+	return result
+}
+
+func (s *agentMirrorSuite) TestRequiresAuth(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{Method: "POST", URL: s.agentMirrorURI()})
+	body := apitesting.AssertResponse(c, resp, http.StatusUnauthorized, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authentication failed: no credentials provided\n")
+}
+
+func (s *agentMirrorSuite) TestRequiresPOST(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{Method: "PUT", URL: s.agentMirrorURI()})
+	result := s.assertResponse(c, resp, http.StatusMethodNotAllowed)
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error.Message, gc.Equals, `unsupported method: "PUT"`)
+}
+
+func (s *agentMirrorSuite) TestImport(c *gc.C) {
+	resp := s.importRequest(c, "application/x-tar-gz", s.bundle(c))
+	result := s.assertResponse(c, resp, http.StatusOK)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.ToolsList, gc.HasLen, 1)
+	c.Check(result.ToolsList[0].Version, gc.Equals, version.MustParseBinary("2.8.1-focal-amd64"))
+	c.Check(result.Images, gc.Equals, 1)
+
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	_, r, err := storage.Open("2.8.1-focal-amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "focal agent")
+
+	images, err := s.State.CloudImageMetadataStorage.FindMetadata(cloudimagemetadata.MetadataFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(images["custom"], gc.HasLen, 1)
+	c.Check(images["custom"][0].ImageId, gc.Equals, "ami-1234")
+}
+
+func (s *agentMirrorSuite) TestImportUntrusted(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.AgentMirrorKeyring: keys.JujuPublicKey,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.importRequest(c, "application/x-tar-gz", s.bundle(c))
+	result := s.assertResponse(c, resp, http.StatusBadRequest)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Message, gc.Matches, "verifying agent mirror manifest: .*")
+
+	storage, err := s.State.ToolsStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	defer storage.Close()
+	all, err := storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 0)
+}
+
+func (s *agentMirrorSuite) TestImportWithoutKeyring(c *gc.C) {
+	err := s.State.UpdateControllerConfig(nil, []string{controller.AgentMirrorKeyring})
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.importRequest(c, "application/x-tar-gz", s.bundle(c))
+	result := s.assertResponse(c, resp, http.StatusBadRequest)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Message, gc.Equals, "no agent-mirror-keyring set to verify agent mirror bundles")
+}
+
+func (s *agentMirrorSuite) TestImportRequiresTarGz(c *gc.C) {
+	resp := s.importRequest(c, "application/json", s.bundle(c))
+	result := s.assertResponse(c, resp, http.StatusBadRequest)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Message, gc.Equals, "expected Content-Type: application/x-tar-gz, got: application/json")
+}
@@ -67,10 +67,12 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelmanager" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/payloads"
 	"github.com/juju/juju/apiserver/facades/client/resources"
+	"github.com/juju/juju/apiserver/facades/client/rolemanager"
 	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/sshclient" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/storage"
 	"github.com/juju/juju/apiserver/facades/client/subnets"
+	"github.com/juju/juju/apiserver/facades/client/tokenmanager"
 	"github.com/juju/juju/apiserver/facades/client/usermanager"
 	"github.com/juju/juju/apiserver/facades/controller/actionpruner"
 	"github.com/juju/juju/apiserver/facades/controller/agenttools"
@@ -156,6 +158,9 @@ func AllFacades() *facade.Registry {
 	reg("Application", 10, application.NewFacadeV10) // --force and --no-wait parameters
 	reg("Application", 11, application.NewFacadeV11) // Get call returns the endpoint bindings
 	reg("Application", 12, application.NewFacadeV12) // Adds UnitsInfo()
+	reg("Application", 13, application.NewFacadeV13) // Adds per-endpoint expose settings
+	reg("Application", 14, application.NewFacadeV14) // Adds placement policies
+	reg("Application", 15, application.NewFacadeV15) // Deploy, SetCharm and Get carry charm origins
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationOffers", 2, applicationoffers.NewOffersAPIV2)
@@ -169,10 +174,13 @@ func AllFacades() *facade.Registry {
 	reg("Bundle", 4, bundle.NewFacadeV4)
 	reg("CharmHub", 1, charmhub.NewFacade)
 	reg("CharmRevisionUpdater", 2, charmrevisionupdater.NewCharmRevisionUpdaterAPI)
-	reg("Charms", 2, charms.NewFacade)
+	reg("Charms", 2, charms.NewFacadeV2)
+	reg("Charms", 3, charms.NewFacade) // adds ReleaseLocalCharms, ResolveLocalCharms
 	reg("Cleaner", 2, cleaner.NewCleanerAPI)
 	reg("Client", 1, client.NewFacadeV1)
-	reg("Client", 2, client.NewFacade)
+	reg("Client", 2, client.NewFacadeV2)
+	reg("Client", 3, client.NewFacadeV3) // Adds UpgradePreview.
+	reg("Client", 4, client.NewFacade)   // Adds StageAgentVersion.
 	reg("Cloud", 1, cloud.NewFacadeV1)
 	reg("Cloud", 2, cloud.NewFacadeV2) // adds AddCloud, AddCredentials, CredentialContents, RemoveClouds
 	reg("Cloud", 3, cloud.NewFacadeV3) // changes signature of UpdateCredentials, adds ModifyCloudAccess
@@ -199,6 +207,7 @@ func AllFacades() *facade.Registry {
 	reg("Controller", 7, controller.NewControllerAPIv7)
 	reg("Controller", 8, controller.NewControllerAPIv8)
 	reg("Controller", 9, controller.NewControllerAPIv9)
+	reg("Controller", 10, controller.NewControllerAPIv10)
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPIV1)
 	reg("CrossModelRelations", 2, crossmodelrelations.NewStateCrossModelRelationsAPI) // Adds WatchRelationChanges, removes WatchRelationUnits
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
@@ -213,6 +222,7 @@ func AllFacades() *facade.Registry {
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
 	reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5)
+	reg("Firewaller", 6, firewaller.NewStateFirewallerAPIV6) // Adds GetExposeInfo
 	reg("FirewallRules", 1, firewallrules.NewFacade)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
@@ -241,6 +251,8 @@ func AllFacades() *facade.Registry {
 	reg("MachineManager", 4, machinemanager.NewFacadeV4) // Adds DestroyMachineWithParams.
 	reg("MachineManager", 5, machinemanager.NewFacadeV5) // Adds UpgradeSeriesPrepare, removes UpdateMachineSeries.
 	reg("MachineManager", 6, machinemanager.NewFacadeV6) // DestroyMachinesWithParams gains maxWait.
+	reg("MachineManager", 7, machinemanager.NewFacadeV7) // Adds machine pools.
+	reg("MachineManager", 8, machinemanager.NewFacadeV8) // AddMachines accepts cloud-init user data.
 
 	reg("MachineUndertaker", 1, machineundertaker.NewFacade)
 	reg("Machiner", 1, machine.NewMachinerAPIV1)
@@ -258,7 +270,8 @@ func AllFacades() *facade.Registry {
 	reg("MigrationMaster", 1, migrationmaster.NewMigrationMasterFacade)
 	reg("MigrationMaster", 2, migrationmaster.NewMigrationMasterFacadeV2)
 	reg("MigrationMinion", 1, migrationminion.NewFacade)
-	reg("MigrationTarget", 1, migrationtarget.NewFacade)
+	reg("MigrationTarget", 1, migrationtarget.NewFacadeV1)
+	reg("MigrationTarget", 2, migrationtarget.NewFacade)
 
 	reg("ModelConfig", 1, modelconfig.NewFacadeV1)
 	reg("ModelConfig", 2, modelconfig.NewFacadeV2)
@@ -292,6 +305,7 @@ func AllFacades() *facade.Registry {
 	reg("Provisioner", 9, provisioner.NewProvisionerAPIV9)   // Adds supported containers
 	reg("Provisioner", 10, provisioner.NewProvisionerAPIV10) // Adds support for multiple space constraints.
 	reg("Provisioner", 11, provisioner.NewProvisionerAPIV11) // Relies on agent-set origin in SetHostMachineNetworkConfig.
+	reg("Provisioner", 12, provisioner.NewProvisionerAPIV12) // Adds ReconcileMachinePools.
 
 	reg("ProxyUpdater", 1, proxyupdater.NewFacadeV1)
 	reg("ProxyUpdater", 2, proxyupdater.NewFacadeV2)
@@ -304,6 +318,7 @@ func AllFacades() *facade.Registry {
 
 	reg("Resumer", 2, resumer.NewResumerAPI)
 	reg("RetryStrategy", 1, retrystrategy.NewRetryStrategyAPI)
+	reg("RoleManager", 1, rolemanager.NewFacade)
 	reg("Singular", 2, singular.NewExternalFacade)
 
 	reg("SSHClient", 1, sshclient.NewFacade)
@@ -327,6 +342,7 @@ func AllFacades() *facade.Registry {
 	reg("Subnets", 2, subnets.NewAPIv2)
 	reg("Subnets", 3, subnets.NewAPIv3)
 	reg("Subnets", 4, subnets.NewAPI) // Adds SubnetsByCIDR; removes AllSpaces.
+	reg("TokenManager", 1, tokenmanager.NewFacade)
 	reg("Undertaker", 1, undertaker.NewUndertakerAPI)
 	reg("UnitAssigner", 1, unitassigner.New)
 
@@ -352,8 +368,9 @@ func AllFacades() *facade.Registry {
 
 	reg("UpgradeSteps", 1, upgradesteps.NewFacadeV1)
 	reg("UpgradeSteps", 2, upgradesteps.NewFacadeV2)
-	reg("UserManager", 1, usermanager.NewUserManagerAPI)
-	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
+	reg("UserManager", 1, usermanager.NewUserManagerAPIV2)
+	reg("UserManager", 2, usermanager.NewUserManagerAPIV2) // Adds ResetPassword
+	reg("UserManager", 3, usermanager.NewUserManagerAPIV3) // Adds groups
 
 	regRaw("AllWatcher", 1, NewAllWatcher, reflect.TypeOf((*SrvAllWatcher)(nil)))
 	// Note: AllModelWatcher uses the same infrastructure as AllWatcher
@@ -539,9 +539,13 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		authorizer      httpcontext.Authorizer
 		tracked         bool
 		noModelUUID     bool
+		// roleEndpoint names the endpoint when checking requests
+		// from users with a custom role; see permission.HTTPFacade.
+		roleEndpoint string
 	}
 	var endpoints []apihttp.Endpoint
 	controllerModelUUID := srv.shared.statePool.SystemState().ModelUUID()
+	httpCtxt := httpContext{srv: srv}
 	addHandler := func(handler handler) {
 		methods := handler.methods
 		if methods == nil {
@@ -552,6 +556,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			h = srv.trackRequests(h)
 		}
 		if !handler.unauthenticated {
+			h = &roleCheckHandler{
+				Handler:  h,
+				ctxt:     httpCtxt,
+				endpoint: handler.roleEndpoint,
+			}
 			h = &httpcontext.BasicAuthHandler{
 				Handler:       h,
 				Authenticator: srv.authenticator,
@@ -580,7 +589,6 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		}
 	}
 
-	httpCtxt := httpContext{srv: srv}
 	mainAPIHandler := http.HandlerFunc(srv.apiHandler)
 	healthHandler := http.HandlerFunc(srv.healthHandler)
 	logStreamHandler := newLogStreamEndpointHandler(httpCtxt)
@@ -687,6 +695,10 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		ctxt:          httpCtxt,
 		stateAuthFunc: httpCtxt.stateForMigrationImporting,
 	}
+	agentMirrorHandler := &agentMirrorHandler{
+		ctxt:          httpCtxt,
+		stateAuthFunc: httpCtxt.stateForRequestAuthenticatedUser,
+	}
 	resourcesMigrationUploadHandler := &resourcesMigrationUploadHandler{
 		ctxt:          httpCtxt,
 		stateAuthFunc: httpCtxt.stateForMigrationImporting,
@@ -710,9 +722,10 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		tracked:    true,
 		authorizer: controllerAuthorizer{},
 	}, {
-		pattern: modelRoutePrefix + "/logstream",
-		handler: logStreamHandler,
-		tracked: true,
+		pattern:      modelRoutePrefix + "/logstream",
+		handler:      logStreamHandler,
+		tracked:      true,
+		roleEndpoint: "LogStream",
 	}, {
 		pattern: modelRoutePrefix + "/log",
 		handler: debugLogHandler,
@@ -731,52 +744,68 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		tracked:         true,
 		unauthenticated: true,
 	}, {
-		pattern: modelRoutePrefix + "/rest/1.0/:entity/:name/:attribute",
-		handler: modelRestServer,
+		pattern:      modelRoutePrefix + "/rest/1.0/:entity/:name/:attribute",
+		handler:      modelRestServer,
+		roleEndpoint: "ModelRest",
 	}, {
 		// GET /charms has no authorizer
-		pattern: modelRoutePrefix + "/charms",
-		methods: []string{"GET"},
-		handler: modelCharmsHTTPHandler,
+		pattern:      modelRoutePrefix + "/charms",
+		methods:      []string{"GET"},
+		handler:      modelCharmsHTTPHandler,
+		roleEndpoint: "Charms",
+	}, {
+		pattern:      modelRoutePrefix + "/charms",
+		methods:      []string{"POST"},
+		handler:      modelCharmsHTTPHandler,
+		authorizer:   modelCharmsUploadAuthorizer,
+		roleEndpoint: "Charms",
 	}, {
-		pattern:    modelRoutePrefix + "/charms",
-		methods:    []string{"POST"},
-		handler:    modelCharmsHTTPHandler,
-		authorizer: modelCharmsUploadAuthorizer,
+		pattern:      modelRoutePrefix + "/tools",
+		handler:      modelToolsUploadHandler,
+		authorizer:   modelToolsUploadAuthorizer,
+		roleEndpoint: "Tools",
 	}, {
-		pattern:    modelRoutePrefix + "/tools",
-		handler:    modelToolsUploadHandler,
-		authorizer: modelToolsUploadAuthorizer,
+		pattern:      modelRoutePrefix + "/agent-mirror",
+		handler:      agentMirrorHandler,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "AgentMirror",
 	}, {
 		pattern:         modelRoutePrefix + "/tools/:version",
 		handler:         modelToolsDownloadHandler,
 		unauthenticated: true,
 	}, {
-		pattern: modelRoutePrefix + "/applications/:application/resources/:resource",
-		handler: resourcesHandler,
+		pattern:      modelRoutePrefix + "/applications/:application/resources/:resource",
+		handler:      resourcesHandler,
+		roleEndpoint: "Resources",
 	}, {
-		pattern: modelRoutePrefix + "/units/:unit/resources/:resource",
-		handler: unitResourcesHandler,
+		pattern:      modelRoutePrefix + "/units/:unit/resources/:resource",
+		handler:      unitResourcesHandler,
+		roleEndpoint: "UnitResources",
 	}, {
-		pattern: modelRoutePrefix + "/backups",
-		handler: backupHandler,
+		pattern:      modelRoutePrefix + "/backups",
+		handler:      backupHandler,
+		roleEndpoint: "Backups",
 	}, {
-		pattern:    "/migrate/charms",
-		handler:    migrateCharmsHTTPHandler,
-		authorizer: controllerAdminAuthorizer,
+		pattern:      "/migrate/charms",
+		handler:      migrateCharmsHTTPHandler,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "MigrateCharms",
 	}, {
-		pattern:    "/migrate/tools",
-		handler:    migrateToolsUploadHandler,
-		authorizer: controllerAdminAuthorizer,
+		pattern:      "/migrate/tools",
+		handler:      migrateToolsUploadHandler,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "MigrateTools",
 	}, {
-		pattern:    "/migrate/resources",
-		handler:    resourcesMigrationUploadHandler,
-		authorizer: controllerAdminAuthorizer,
+		pattern:      "/migrate/resources",
+		handler:      resourcesMigrationUploadHandler,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "MigrateResources",
 	}, {
-		pattern:    "/migrate/logtransfer",
-		handler:    logTransferHandler,
-		tracked:    true,
-		authorizer: controllerAdminAuthorizer,
+		pattern:      "/migrate/logtransfer",
+		handler:      logTransferHandler,
+		tracked:      true,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "MigrateLogTransfer",
 	}, {
 		pattern:         "/api",
 		handler:         mainAPIHandler,
@@ -803,9 +832,15 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:         registerHandler,
 		unauthenticated: true,
 	}, {
-		pattern:    "/tools",
-		handler:    modelToolsUploadHandler,
-		authorizer: modelToolsUploadAuthorizer,
+		pattern:      "/tools",
+		handler:      modelToolsUploadHandler,
+		authorizer:   modelToolsUploadAuthorizer,
+		roleEndpoint: "Tools",
+	}, {
+		pattern:      "/agent-mirror",
+		handler:      agentMirrorHandler,
+		authorizer:   controllerAdminAuthorizer,
+		roleEndpoint: "AgentMirror",
 	}, {
 		pattern:         "/tools/:version",
 		handler:         modelToolsDownloadHandler,
@@ -819,32 +854,37 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		unauthenticated: true,
 	}, {
 		// GET /charms has no authorizer
-		pattern: "/charms",
-		methods: []string{"GET"},
-		handler: modelCharmsHTTPHandler,
+		pattern:      "/charms",
+		methods:      []string{"GET"},
+		handler:      modelCharmsHTTPHandler,
+		roleEndpoint: "Charms",
 	}, {
-		pattern:    "/charms",
-		methods:    []string{"POST"},
-		handler:    modelCharmsHTTPHandler,
-		authorizer: modelCharmsUploadAuthorizer,
+		pattern:      "/charms",
+		methods:      []string{"POST"},
+		handler:      modelCharmsHTTPHandler,
+		authorizer:   modelCharmsUploadAuthorizer,
+		roleEndpoint: "Charms",
 	}, {
-		pattern: "/gui-archive",
-		methods: []string{"POST"},
-		handler: guiArchiveHandler,
+		pattern:      "/gui-archive",
+		methods:      []string{"POST"},
+		handler:      guiArchiveHandler,
+		roleEndpoint: "GUIArchive",
 	}, {
 		pattern:         "/gui-archive",
 		methods:         []string{"GET"},
 		handler:         guiArchiveHandler,
 		unauthenticated: true,
 	}, {
-		pattern: "/gui-version",
-		handler: guiVersionHandler,
+		pattern:      "/gui-version",
+		handler:      guiVersionHandler,
+		roleEndpoint: "GUIVersion",
 	}}
 	if srv.registerIntrospectionHandlers != nil {
 		add := func(subpath string, h http.Handler) {
 			handlers = append(handlers, handler{
-				pattern: path.Join("/introspection/", subpath),
-				handler: introspectionHandler{httpCtxt, h},
+				pattern:      path.Join("/introspection/", subpath),
+				handler:      introspectionHandler{httpCtxt, h},
+				roleEndpoint: "Introspection",
 			})
 		}
 		srv.registerIntrospectionHandlers(add)
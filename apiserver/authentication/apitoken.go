@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// APIToken is an API token issued to a service account, as
+// implemented by *state.APIToken.
+type APIToken interface {
+	// Id returns the ID of the token.
+	Id() string
+
+	// UserTag returns the tag of the service account the token
+	// was issued to.
+	UserTag() names.UserTag
+
+	// SecretValid reports whether the secret matches the token's.
+	SecretValid(secret string) bool
+
+	// Expired reports whether the token has expired at the given time.
+	Expired(now time.Time) bool
+
+	// Allows reports whether the token allows the operation on
+	// the target.
+	Allows(operation permission.Access, target names.Tag) bool
+}
+
+// APITokenGetter gets API tokens by ID.
+type APITokenGetter interface {
+	// APIToken returns the token with the given ID, or an error
+	// satisfying errors.IsNotFound if there is none.
+	APIToken(id string) (APIToken, error)
+}
+
+// APITokenEntity is the entity authenticated by an API token. It is
+// the service account the token was issued to, restricted to what
+// the token allows.
+type APITokenEntity struct {
+	Token APIToken
+}
+
+// Tag implements state.Entity.
+func (e *APITokenEntity) Tag() names.Tag {
+	return e.Token.UserTag()
+}
+
+// APITokenAuthenticator authenticates service accounts presenting an
+// API token as their credentials.
+type APITokenAuthenticator struct {
+	// Tokens gets the presented tokens.
+	Tokens APITokenGetter
+
+	// Clock is used to check whether tokens have expired.
+	Clock clock.Clock
+}
+
+var _ EntityAuthenticator = (*APITokenAuthenticator)(nil)
+
+// IsAPITokenLogin reports whether the login request presents an API
+// token as its credentials.
+func IsAPITokenLogin(req params.LoginRequest) bool {
+	return state.IsAPIToken(req.Credentials)
+}
+
+// Authenticate implements EntityAuthenticator. The tag may be nil, in
+// which case the service account is taken from the token; otherwise
+// it must match the token's service account.
+func (a *APITokenAuthenticator) Authenticate(
+	ctx context.Context, _ EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	id, secret, err := state.ParseAPIToken(req.Credentials)
+	if err != nil {
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	}
+	token, err := a.Tokens.APIToken(id)
+	if errors.IsNotFound(err) {
+		logger.Debugf("unknown API token %q", id)
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !token.SecretValid(secret) {
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	}
+	if token.Expired(a.Clock.Now()) {
+		logger.Debugf("API token %q has expired", id)
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	}
+	if tag != nil && tag != token.UserTag() {
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	}
+	return &APITokenEntity{Token: token}, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+type apiTokenAuthenticatorSuite struct {
+	testing.IsolationSuite
+
+	clock *testclock.Clock
+	token *stubAPIToken
+	auth  *authentication.APITokenAuthenticator
+}
+
+var _ = gc.Suite(&apiTokenAuthenticatorSuite{})
+
+func (s *apiTokenAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.token = &stubAPIToken{
+		id:      "1234",
+		secret:  "s3cret",
+		expires: s.clock.Now().Add(time.Hour),
+	}
+	s.auth = &authentication.APITokenAuthenticator{
+		Tokens: s,
+		Clock:  s.clock,
+	}
+}
+
+// APIToken implements authentication.APITokenGetter.
+func (s *apiTokenAuthenticatorSuite) APIToken(id string) (authentication.APIToken, error) {
+	if id != s.token.id {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	return s.token, nil
+}
+
+func (s *apiTokenAuthenticatorSuite) login(tag names.Tag, credentials string) (state.Entity, error) {
+	return s.auth.Authenticate(context.Background(), nil, tag, params.LoginRequest{Credentials: credentials})
+}
+
+func (s *apiTokenAuthenticatorSuite) TestIsAPITokenLogin(c *gc.C) {
+	c.Assert(authentication.IsAPITokenLogin(params.LoginRequest{Credentials: "jujutoken.1234.s3cret"}), jc.IsTrue)
+	c.Assert(authentication.IsAPITokenLogin(params.LoginRequest{Credentials: "password"}), jc.IsFalse)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	entity, err := s.login(nil, "jujutoken.1234.s3cret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, state.ServiceAccountUserTag("ci"))
+	c.Assert(entity.(*authentication.APITokenEntity).Token, gc.Equals, s.token)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateMatchingTag(c *gc.C) {
+	_, err := s.login(state.ServiceAccountUserTag("ci"), "jujutoken.1234.s3cret")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.login(names.NewUserTag("bob"), "jujutoken.1234.s3cret")
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateBadCredentials(c *gc.C) {
+	for _, credentials := range []string{
+		"jujutoken.1234.wrong",
+		"jujutoken.5678.s3cret",
+		"jujutoken.1234",
+	} {
+		_, err := s.login(nil, credentials)
+		c.Check(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds, gc.Commentf("%s", credentials))
+	}
+}
+
+func (s *apiTokenAuthenticatorSuite) TestAuthenticateExpired(c *gc.C) {
+	s.clock.Advance(2 * time.Hour)
+	_, err := s.login(nil, "jujutoken.1234.s3cret")
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+type stubAPIToken struct {
+	id      string
+	secret  string
+	expires time.Time
+}
+
+func (t *stubAPIToken) Id() string                     { return t.id }
+func (t *stubAPIToken) UserTag() names.UserTag         { return state.ServiceAccountUserTag("ci") }
+func (t *stubAPIToken) SecretValid(secret string) bool { return secret == t.secret }
+func (t *stubAPIToken) Expired(now time.Time) bool     { return !now.Before(t.expires) }
+
+func (t *stubAPIToken) Allows(operation permission.Access, target names.Tag) bool {
+	return operation == permission.ReadAccess
+}
@@ -0,0 +1,438 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+const (
+	// OIDCUserDomain is the domain of the user names given to users
+	// authenticated by an OpenID Connect issuer.
+	OIDCUserDomain = "oidc"
+
+	// oidcClockSkew is the leeway allowed when checking the
+	// time-based claims of an ID token.
+	oidcClockSkew = time.Minute
+
+	// oidcKeyRefreshInterval is the minimum time between fetches of
+	// the issuer's signing keys. Tokens signed by an unknown key can
+	// be presented by anyone, so they mustn't each cause a fetch.
+	oidcKeyRefreshInterval = 30 * time.Second
+)
+
+// OIDCProviderMetadata holds the subset of the OpenID Connect discovery
+// document that is used by the controller and clients.
+type OIDCProviderMetadata struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
+// OIDCProvider verifies RS256-signed ID tokens issued by an OpenID
+// Connect issuer. The issuer's discovery document and signing keys
+// are fetched on first use and the keys are refreshed when a token
+// signed by an unknown key is presented, at most once every
+// oidcKeyRefreshInterval.
+type OIDCProvider struct {
+	// IssuerURL holds the URL of the issuer.
+	IssuerURL string
+
+	// ClientID holds the client ID that tokens must be issued to.
+	ClientID string
+
+	// HTTPClient is used to fetch the discovery document and keys.
+	// If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to check token expiry and to limit how often
+	// the signing keys are fetched.
+	Clock clock.Clock
+
+	// fetchMu serializes fetches from the issuer. It is held without
+	// holding mu, so that tokens signed by known keys are verified
+	// while the keys are being fetched.
+	fetchMu sync.Mutex
+
+	// mu guards the fields below.
+	mu       sync.Mutex
+	metadata *OIDCProviderMetadata
+	keys     map[string]*rsa.PublicKey
+	// keysFetched holds the time of the latest attempt to
+	// fetch the signing keys.
+	keysFetched time.Time
+}
+
+// OIDCClaims holds the claims of a verified ID token.
+type OIDCClaims map[string]interface{}
+
+// String returns the string value of the named claim, or the empty
+// string if it is not present or not a string.
+func (c OIDCClaims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns the named claim as a list of strings. Claims holding
+// a single string are returned as a list of one element.
+func (c OIDCClaims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Metadata returns the issuer's discovery document.
+func (p *OIDCProvider) Metadata(ctx context.Context) (*OIDCProviderMetadata, error) {
+	if metadata := p.cachedMetadata(); metadata != nil {
+		return metadata, nil
+	}
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+	return p.fetchMetadata(ctx)
+}
+
+func (p *OIDCProvider) cachedMetadata() *OIDCProviderMetadata {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.metadata
+}
+
+// fetchMetadata returns the issuer's discovery document, fetching it
+// if it isn't known yet. It must be called with fetchMu held.
+func (p *OIDCProvider) fetchMetadata(ctx context.Context) (*OIDCProviderMetadata, error) {
+	if metadata := p.cachedMetadata(); metadata != nil {
+		return metadata, nil
+	}
+	var metadata OIDCProviderMetadata
+	discoveryURL := strings.TrimSuffix(p.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, &metadata); err != nil {
+		return nil, errors.Annotate(err, "fetching OIDC discovery document")
+	}
+	if metadata.Issuer != p.IssuerURL {
+		return nil, errors.Errorf("OIDC issuer %q does not match configured issuer %q", metadata.Issuer, p.IssuerURL)
+	}
+	if metadata.JWKSURI == "" {
+		return nil, errors.Errorf("OIDC issuer %q does not publish signing keys", p.IssuerURL)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// key returns the signing key with the given ID, refreshing the key
+// set from the issuer if the key is not known and the keys haven't
+// been fetched within oidcKeyRefreshInterval.
+func (p *OIDCProvider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, refresh := p.cachedKey(kid); key != nil {
+		return key, nil
+	} else if !refresh {
+		return nil, errors.NotFoundf("OIDC signing key %q", kid)
+	}
+
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+	// The keys may have been fetched while waiting for the lock.
+	if key, refresh := p.cachedKey(kid); key != nil {
+		return key, nil
+	} else if !refresh {
+		return nil, errors.NotFoundf("OIDC signing key %q", kid)
+	}
+	// Failed fetches count too, so that an unavailable issuer
+	// isn't asked again for every login.
+	p.mu.Lock()
+	p.keysFetched = p.Clock.Now()
+	p.mu.Unlock()
+
+	metadata, err := p.fetchMetadata(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys, err := p.fetchKeys(ctx, metadata.JWKSURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = keys
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, errors.NotFoundf("OIDC signing key %q", kid)
+	}
+	return key, nil
+}
+
+// cachedKey returns the known signing key with the given ID. If there
+// is none, it reports whether the keys may be fetched again.
+func (p *OIDCProvider) cachedKey(kid string) (*rsa.PublicKey, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, false
+	}
+	refresh := p.keysFetched.IsZero() || p.Clock.Now().Sub(p.keysFetched) >= oidcKeyRefreshInterval
+	return nil, refresh
+}
+
+// fetchKeys fetches the issuer's RSA signing keys, keyed by key ID.
+func (p *OIDCProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var keySet struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			Use     string `json:"use"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &keySet); err != nil {
+		return nil, errors.Annotate(err, "fetching OIDC signing keys")
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range keySet.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, errors.Annotatef(err, "decoding modulus of key %q", k.KeyID)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, errors.Annotatef(err, "decoding exponent of key %q", k.KeyID)
+		}
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: unexpected HTTP response %q", url, resp.Status)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
+
+// Verify checks the signature and claims of the given ID token and
+// returns its claims. An error satisfying errors.IsUnauthorized is
+// returned if the token is not valid, and ErrLoginExpired if it has
+// expired.
+func (p *OIDCProvider) Verify(ctx context.Context, rawToken string) (OIDCClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.Unauthorizedf("malformed ID token")
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeTokenPart(parts[0], &header); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if header.Algorithm != "RS256" {
+		return nil, errors.Unauthorizedf("ID token signing algorithm %q not supported", header.Algorithm)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Unauthorizedf("malformed ID token signature")
+	}
+	key, err := p.key(ctx, header.KeyID)
+	if errors.IsNotFound(err) {
+		return nil, errors.NewUnauthorized(err, "")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.Unauthorizedf("invalid ID token signature")
+	}
+
+	var claims OIDCClaims
+	if err := decodeTokenPart(parts[1], &claims); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if iss := claims.String("iss"); iss != p.IssuerURL {
+		return nil, errors.Unauthorizedf("ID token issued by %q, not %q", iss, p.IssuerURL)
+	}
+	audienceOK := false
+	for _, aud := range claims.Strings("aud") {
+		if aud == p.ClientID {
+			audienceOK = true
+			break
+		}
+	}
+	if !audienceOK {
+		return nil, errors.Unauthorizedf("ID token not issued to %q", p.ClientID)
+	}
+	now := p.Clock.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.Unauthorizedf("ID token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return nil, errors.Trace(apiservererrors.ErrLoginExpired)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.Unauthorizedf("ID token not yet valid")
+	}
+	return claims, nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.Unauthorizedf("malformed ID token")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Unauthorizedf("malformed ID token")
+	}
+	return nil
+}
+
+// OIDCTokenVerifier verifies OpenID Connect ID tokens.
+type OIDCTokenVerifier interface {
+	Verify(ctx context.Context, rawToken string) (OIDCClaims, error)
+}
+
+// OIDCAccessUpdater records the access an OpenID Connect user is given
+// through the group claims of their ID token.
+type OIDCAccessUpdater interface {
+	// UpdateOIDCAccess sets the controller access of the user and
+	// their access to each of the models in modelAccess. NoAccess
+	// means that any access previously given to the user through
+	// group claims is to be removed. The display name is recorded
+	// for users that are given access.
+	UpdateOIDCAccess(user names.UserTag, displayName string, controllerAccess permission.Access, modelAccess map[string]permission.Access) error
+}
+
+// OIDCAuthenticator authenticates users presenting an ID token issued
+// by a trusted OpenID Connect issuer as their credentials. Users are
+// identified by the token's issuer and subject, as described by
+// OIDCUserTag.
+type OIDCAuthenticator struct {
+	// Verifier verifies the presented ID tokens.
+	Verifier OIDCTokenVerifier
+
+	// GroupsClaim holds the name of the claim listing the
+	// groups the user is a member of.
+	GroupsClaim string
+
+	// GroupAccess holds the mappings from groups to access levels.
+	GroupAccess []permission.GroupClaimAccess
+
+	// AccessUpdater records the access given by the group claims.
+	AccessUpdater OIDCAccessUpdater
+}
+
+var _ EntityAuthenticator = (*OIDCAuthenticator)(nil)
+
+// Authenticate implements EntityAuthenticator. The tag may be nil,
+// in which case the user is taken from the ID token; otherwise it
+// must match the user in the token.
+func (a *OIDCAuthenticator) Authenticate(
+	ctx context.Context, entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
+) (state.Entity, error) {
+	if req.Credentials == "" {
+		return nil, errors.Trace(apiservererrors.ErrNoCreds)
+	}
+	claims, err := a.Verifier.Verify(ctx, req.Credentials)
+	if err != nil {
+		logger.Debugf("OIDC ID token verification failed: %v", err)
+		if errors.IsUnauthorized(err) {
+			return nil, errors.Trace(apiservererrors.ErrBadCreds)
+		}
+		return nil, errors.Trace(err)
+	}
+	userTag, err := OIDCUserTag(claims)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tag != nil && tag != userTag {
+		return nil, errors.Trace(apiservererrors.ErrBadCreds)
+	}
+
+	groups := claims.Strings(a.GroupsClaim)
+	modelAccess := make(map[string]permission.Access)
+	for _, modelUUID := range permission.GroupClaimModelUUIDs(a.GroupAccess) {
+		modelAccess[modelUUID] = permission.ResolveGroupClaimAccess(a.GroupAccess, groups, modelUUID)
+	}
+	controllerAccess := permission.ResolveGroupClaimAccess(a.GroupAccess, groups, "")
+	displayName := claims.String("preferred_username")
+	if err := a.AccessUpdater.UpdateOIDCAccess(userTag, displayName, controllerAccess, modelAccess); err != nil {
+		return nil, errors.Annotatef(err, "updating access for %q", userTag.Id())
+	}
+
+	entity, err := entityFinder.FindEntity(userTag)
+	if errors.IsNotFound(err) {
+		logger.Debugf("OIDC user %s has no access", userTag.Id())
+		return nil, errors.Trace(apiservererrors.ErrPerm)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
+
+// OIDCUserTag returns the tag of the user identified by the given
+// ID token claims. The OpenID Connect specification only guarantees
+// that the issuer and subject together identify a user; claims such
+// as preferred_username may be changed by the user or reused by the
+// issuer. The user name is therefore derived from a hash of the
+// issuer and subject, in the OIDCUserDomain domain.
+func OIDCUserTag(claims OIDCClaims) (names.UserTag, error) {
+	issuer, subject := claims.String("iss"), claims.String("sub")
+	if issuer == "" || subject == "" {
+		return names.UserTag{}, errors.NotValidf("ID token without issuer and subject")
+	}
+	sum := sha256.Sum256([]byte(issuer + "\x00" + subject))
+	name := hex.EncodeToString(sum[:16])
+	return names.NewUserTag(name).WithDomain(OIDCUserDomain), nil
+}
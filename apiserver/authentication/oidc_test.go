@@ -0,0 +1,260 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/oidctest"
+)
+
+const oidcModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type oidcAuthenticatorSuite struct {
+	testing.IsolationSuite
+
+	issuer   *oidctest.Issuer
+	clock    *testclock.Clock
+	provider *authentication.OIDCProvider
+	updater  *stubAccessUpdater
+	auth     *authentication.OIDCAuthenticator
+}
+
+var _ = gc.Suite(&oidcAuthenticatorSuite{})
+
+func (s *oidcAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.issuer = oidctest.NewIssuer()
+	s.AddCleanup(func(*gc.C) { s.issuer.Close() })
+	s.clock = testclock.NewClock(time.Now())
+	s.provider = &authentication.OIDCProvider{
+		IssuerURL: s.issuer.URL,
+		ClientID:  oidctest.ClientID,
+		Clock:     s.clock,
+	}
+	s.updater = &stubAccessUpdater{}
+	mappings, err := permission.ParseGroupClaimAccessList([]string{
+		"staff=login",
+		"admins=superuser",
+		"devs=write@" + oidcModelUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.auth = &authentication.OIDCAuthenticator{
+		Verifier:      s.provider,
+		GroupsClaim:   "groups",
+		GroupAccess:   mappings,
+		AccessUpdater: s.updater,
+	}
+}
+
+// userTag returns the tag of the user the issuer names username.
+func (s *oidcAuthenticatorSuite) userTag(c *gc.C, username string) names.UserTag {
+	tag, err := authentication.OIDCUserTag(s.issuer.Claims(username))
+	c.Assert(err, jc.ErrorIsNil)
+	return tag
+}
+
+func (s *oidcAuthenticatorSuite) authenticate(tag names.Tag, token string) (state.Entity, error) {
+	return s.auth.Authenticate(context.Background(), stubEntityFinder{}, tag, params.LoginRequest{
+		Credentials: token,
+	})
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	token := s.issuer.IDToken(s.issuer.Claims("alice", "staff", "devs"))
+	entity, err := s.authenticate(nil, token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, s.userTag(c, "alice"))
+
+	c.Assert(s.updater.calls, gc.HasLen, 1)
+	call := s.updater.calls[0]
+	c.Check(call.user, gc.Equals, s.userTag(c, "alice"))
+	c.Check(call.displayName, gc.Equals, "alice")
+	c.Check(call.controllerAccess, gc.Equals, permission.LoginAccess)
+	c.Check(call.modelAccess, jc.DeepEquals, map[string]permission.Access{
+		oidcModelUUID: permission.WriteAccess,
+	})
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateWithTag(c *gc.C) {
+	token := s.issuer.IDToken(s.issuer.Claims("alice", "admins"))
+	entity, err := s.authenticate(s.userTag(c, "alice"), token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, s.userTag(c, "alice"))
+	c.Assert(s.updater.calls, gc.HasLen, 1)
+	c.Check(s.updater.calls[0].controllerAccess, gc.Equals, permission.SuperuserAccess)
+	c.Check(s.updater.calls[0].modelAccess[oidcModelUUID], gc.Equals, permission.NoAccess)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateTagMismatch(c *gc.C) {
+	token := s.issuer.IDToken(s.issuer.Claims("alice", "staff"))
+	_, err := s.authenticate(s.userTag(c, "bob"), token)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+	c.Assert(s.updater.calls, gc.HasLen, 0)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNoCredentials(c *gc.C) {
+	_, err := s.authenticate(s.userTag(c, "alice"), "")
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrNoCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateUnknownKey(c *gc.C) {
+	token := s.issuer.ForeignIDToken(s.issuer.Claims("alice", "staff"))
+	_, err := s.authenticate(nil, token)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestUnknownKeysRefreshRateLimited(c *gc.C) {
+	_, err := s.authenticate(nil, s.issuer.IDToken(s.issuer.Claims("alice", "staff")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.issuer.KeyRequests(), gc.Equals, 1)
+
+	// Tokens signed by unknown keys only cause the keys to be
+	// fetched again once the refresh interval has passed.
+	unknown := s.issuer.UnknownKeyIDToken(s.issuer.Claims("alice", "staff"))
+	for i := 0; i < 3; i++ {
+		_, err = s.authenticate(nil, unknown)
+		c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+	}
+	c.Assert(s.issuer.KeyRequests(), gc.Equals, 1)
+
+	s.clock.Advance(30 * time.Second)
+	_, err = s.authenticate(nil, unknown)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+	c.Assert(s.issuer.KeyRequests(), gc.Equals, 2)
+
+	// Known keys are still used.
+	_, err = s.authenticate(nil, s.issuer.IDToken(s.issuer.Claims("alice", "staff")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.issuer.KeyRequests(), gc.Equals, 2)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateWrongAudience(c *gc.C) {
+	claims := s.issuer.Claims("alice", "staff")
+	claims["aud"] = []string{"some-other-client"}
+	_, err := s.authenticate(nil, s.issuer.IDToken(claims))
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateWrongIssuer(c *gc.C) {
+	claims := s.issuer.Claims("alice", "staff")
+	claims["iss"] = "https://elsewhere.example.com"
+	_, err := s.authenticate(nil, s.issuer.IDToken(claims))
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateExpired(c *gc.C) {
+	token := s.issuer.IDToken(s.issuer.Claims("alice", "staff"))
+	s.clock.Advance(2 * time.Hour)
+	_, err := s.authenticate(nil, token)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrLoginExpired)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateMalformed(c *gc.C) {
+	_, err := s.authenticate(nil, "not-a-token")
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNoAccess(c *gc.C) {
+	token := s.issuer.IDToken(s.issuer.Claims("mallory"))
+	_, err := s.auth.Authenticate(context.Background(), stubEntityFinder{notFound: true}, nil, params.LoginRequest{
+		Credentials: token,
+	})
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrPerm)
+	c.Assert(s.updater.calls, gc.HasLen, 1)
+	c.Check(s.updater.calls[0].controllerAccess, gc.Equals, permission.NoAccess)
+}
+
+func (s *oidcAuthenticatorSuite) TestUserTagKeyedOnIssuerAndSubject(c *gc.C) {
+	claims := authentication.OIDCClaims{
+		"iss":                "https://issuer.example.com",
+		"sub":                "1234",
+		"preferred_username": "alice",
+	}
+	tag, err := authentication.OIDCUserTag(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag.Domain(), gc.Equals, authentication.OIDCUserDomain)
+
+	// The preferred user name can change without changing the user.
+	claims["preferred_username"] = "bob"
+	renamed, err := authentication.OIDCUserTag(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(renamed, gc.Equals, tag)
+
+	// The same subject from another issuer is another user.
+	claims["iss"] = "https://elsewhere.example.com"
+	other, err := authentication.OIDCUserTag(claims)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other, gc.Not(gc.Equals), tag)
+
+	_, err = authentication.OIDCUserTag(authentication.OIDCClaims{"preferred_username": "alice"})
+	c.Assert(err, gc.ErrorMatches, `ID token without issuer and subject not valid`)
+}
+
+func (s *oidcAuthenticatorSuite) TestProviderMetadata(c *gc.C) {
+	metadata, err := s.provider.Metadata(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.Issuer, gc.Equals, s.issuer.URL)
+	c.Assert(metadata.DeviceAuthorizationEndpoint, gc.Equals, s.issuer.URL+"/device")
+}
+
+func (s *oidcAuthenticatorSuite) TestProviderIssuerMismatch(c *gc.C) {
+	provider := &authentication.OIDCProvider{
+		IssuerURL: s.issuer.URL + "/",
+		ClientID:  oidctest.ClientID,
+		Clock:     s.clock,
+	}
+	_, err := provider.Metadata(context.Background())
+	c.Assert(err, gc.ErrorMatches, `OIDC issuer ".*" does not match configured issuer ".*/"`)
+}
+
+type accessUpdate struct {
+	user             names.UserTag
+	displayName      string
+	controllerAccess permission.Access
+	modelAccess      map[string]permission.Access
+}
+
+type stubAccessUpdater struct {
+	calls []accessUpdate
+}
+
+func (u *stubAccessUpdater) UpdateOIDCAccess(
+	user names.UserTag, displayName string, controllerAccess permission.Access, modelAccess map[string]permission.Access,
+) error {
+	u.calls = append(u.calls, accessUpdate{user, displayName, controllerAccess, modelAccess})
+	return nil
+}
+
+type stubEntityFinder struct {
+	notFound bool
+}
+
+func (f stubEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	if f.notFound {
+		return nil, errors.NotFoundf("model or controller user")
+	}
+	return stubEntity{tag}, nil
+}
+
+type stubEntity struct {
+	tag names.Tag
+}
+
+func (e stubEntity) Tag() names.Tag {
+	return e.tag
+}
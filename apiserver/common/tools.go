@@ -143,6 +143,12 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag names.Tag, agentVersio
 	if err != nil {
 		return nil, err
 	}
+	// An upgrade may be staged for a machine ahead of the model.
+	if staged, ok := entity.(targetAgentVersioner); ok {
+		if target, ok := staged.TargetAgentVersion(); ok && target.Compare(agentVersion) > 0 {
+			agentVersion = target
+		}
+	}
 	toolsFinder := NewToolsFinder(t.configGetter, t.toolsStorageGetter, t.urlGetter, t.newEnviron)
 	list, err := toolsFinder.findTools(params.FindToolsParams{
 		Number:       agentVersion,
@@ -157,6 +163,12 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag names.Tag, agentVersio
 	return list, nil
 }
 
+// targetAgentVersioner is implemented by entities for which an agent
+// version may be staged ahead of the model agent version.
+type targetAgentVersioner interface {
+	TargetAgentVersion() (version.Number, bool)
+}
+
 // ToolsSetter implements a common Tools method for use by various
 // facades.
 type ToolsSetter struct {
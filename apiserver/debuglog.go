@@ -85,6 +85,9 @@ func newDebugLogHandler(
 //   replay -> string - one of [true, false], if true, start the file from the start
 //   noTail -> string - one of [true, false], if true, existing logs are sent back,
 //      - but the command does not wait for new ones.
+//   includeWorkload -> string - one of [true, false], if true, the logs of the
+//      - workload containers of the units in a CAAS model are included. This
+//      - requires the authenticated user to have read access to the model.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	handler := func(conn *websocket.Conn) {
 		socket := &debugLogSocketImpl{conn}
@@ -101,6 +104,10 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			socket.sendError(errors.Annotate(err, "authorization failed"))
 			return
 		}
+		if err := h.ctxt.checkRole(req, authInfo.Entity, "DebugLog"); err != nil {
+			socket.sendError(errors.Annotate(err, "authorization failed"))
+			return
+		}
 
 		st, err := h.ctxt.stateForRequestUnauthenticated(req)
 		if err != nil {
@@ -108,12 +115,22 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			return
 		}
 		defer st.Release()
+		if err := checkTokenModelScope(authInfo.Entity, st.ModelUUID()); err != nil {
+			socket.sendError(errors.Annotate(err, "authorization failed"))
+			return
+		}
 
 		params, err := readDebugLogParams(req.URL.Query())
 		if err != nil {
 			socket.sendError(err)
 			return
 		}
+		if params.includeWorkload {
+			if err := checkWorkloadLogAccess(st.State, authInfo.Entity); err != nil {
+				socket.sendError(err)
+				return
+			}
+		}
 
 		clock := h.ctxt.srv.clock
 		maxDuration := h.ctxt.srv.shared.maxDebugLogDuration()
@@ -180,16 +197,17 @@ func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) error {
 
 // debugLogParams contains the parsed debuglog API request parameters.
 type debugLogParams struct {
-	startTime     time.Time
-	maxLines      uint
-	fromTheStart  bool
-	noTail        bool
-	backlog       uint
-	filterLevel   loggo.Level
-	includeEntity []string
-	excludeEntity []string
-	includeModule []string
-	excludeModule []string
+	startTime       time.Time
+	maxLines        uint
+	fromTheStart    bool
+	noTail          bool
+	includeWorkload bool
+	backlog         uint
+	filterLevel     loggo.Level
+	includeEntity   []string
+	excludeEntity   []string
+	includeModule   []string
+	excludeModule   []string
 }
 
 func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
@@ -219,6 +237,14 @@ func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
 		params.noTail = noTail
 	}
 
+	if value := queryMap.Get("includeWorkload"); value != "" {
+		includeWorkload, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, errors.Errorf("includeWorkload value %q is not a valid boolean", value)
+		}
+		params.includeWorkload = includeWorkload
+	}
+
 	if value := queryMap.Get("backlog"); value != "" {
 		num, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
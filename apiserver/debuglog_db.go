@@ -31,37 +31,66 @@ func handleDebugLogDBRequest(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
-	params := makeLogTailerParams(reqParams)
-	tailer, err := newLogTailer(st, params)
+	tailerParams := makeLogTailerParams(reqParams)
+	tailer, err := newLogTailer(st, tailerParams)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer tailer.Stop()
 
+	var workloadLogs <-chan *params.LogMessage
+	if reqParams.includeWorkload {
+		workloadTailer, err := newWorkloadLogTailer(clock, st, reqParams)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer workloadTailer.Stop()
+		workloadLogs = workloadTailer.Logs()
+	}
+
 	// Indicate that all is well.
 	socket.sendOk()
 
 	timeout := clock.After(maxDuration)
 
 	var lineCount uint
+	sendLogRecord := func(rec *params.LogMessage) (bool, error) {
+		if err := socket.sendLogRecord(rec); err != nil {
+			return false, errors.Annotate(err, "sending failed")
+		}
+		lineCount++
+		return reqParams.maxLines > 0 && lineCount == reqParams.maxLines, nil
+	}
+
+	logs := tailer.Logs()
 	for {
 		select {
 		case <-stop:
 			return nil
 		case <-timeout:
 			return nil
-		case rec, ok := <-tailer.Logs():
+		case rec, ok := <-logs:
 			if !ok {
-				return errors.Annotate(tailer.Err(), "tailer stopped")
+				if err := tailer.Err(); err != nil || workloadLogs == nil {
+					return errors.Annotate(err, "tailer stopped")
+				}
+				// Keep sending the workload logs until they are exhausted.
+				logs = nil
+				continue
 			}
-
-			if err := socket.sendLogRecord(formatLogRecord(rec)); err != nil {
-				return errors.Annotate(err, "sending failed")
+			if done, err := sendLogRecord(formatLogRecord(rec)); done || err != nil {
+				return err
 			}
-
-			lineCount++
-			if reqParams.maxLines > 0 && lineCount == reqParams.maxLines {
-				return nil
+		case rec, ok := <-workloadLogs:
+			if !ok {
+				if logs == nil {
+					return nil
+				}
+				workloadLogs = nil
+				continue
+			}
+			if done, err := sendLogRecord(rec); done || err != nil {
+				return err
 			}
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -214,6 +215,96 @@ func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestWorkloadLogs(c *gc.C) {
+	tailer := newFakeLogTailer()
+	close(tailer.logsCh)
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+	workloadTailer := newFakeWorkloadLogTailer()
+	workloadTailer.logsCh <- &params.LogMessage{
+		Timestamp: time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:    "unit-gitlab-0",
+		Module:    "workload.gitlab",
+		Severity:  "INFO",
+		Message:   "listening",
+	}
+	close(workloadTailer.logsCh)
+	s.PatchValue(&newWorkloadLogTailer, func(_ clock.Clock, _ state.LogTailerState, reqParams debugLogParams) (workloadLogTailer, error) {
+		c.Assert(reqParams.includeWorkload, jc.IsTrue)
+		return workloadTailer, nil
+	})
+
+	done := s.runRequest(debugLogParams{noTail: true, includeWorkload: true}, nil)
+
+	s.assertOutput(c, []string{
+		"ok", // sendOk() call needs to happen first.
+		"unit-gitlab-0: 2015-06-19 15:34:37 INFO workload.gitlab  listening\n",
+	})
+
+	// The request stops once both the tailers are exhausted.
+	s.assertStops(c, done, tailer)
+	c.Assert(workloadTailer.stopped, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestWorkloadLogsMaxLines(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+	workloadTailer := newFakeWorkloadLogTailer()
+	for i := 0; i < 5; i++ {
+		workloadTailer.logsCh <- &params.LogMessage{
+			Timestamp: time.Date(2015, 6, 19, 15, 34, 38, 0, time.UTC),
+			Entity:    "unit-gitlab-0",
+			Module:    "workload.gitlab",
+			Severity:  "INFO",
+			Message:   "listening",
+		}
+	}
+	s.PatchValue(&newWorkloadLogTailer, func(clock.Clock, state.LogTailerState, debugLogParams) (workloadLogTailer, error) {
+		return workloadTailer, nil
+	})
+
+	done := s.runRequest(debugLogParams{maxLines: 3, includeWorkload: true}, nil)
+
+	c.Assert(<-s.sock.writes, gc.Equals, "ok")
+	for i := 0; i < 3; i++ {
+		select {
+		case <-s.sock.writes:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for socket write (received %d)", i)
+		}
+	}
+
+	// The request stops after the line limit was reached, counting
+	// the lines from both of the tailers.
+	s.assertStops(c, done, tailer)
+	c.Assert(workloadTailer.stopped, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestWorkloadLogsError(c *gc.C) {
+	tailer := newFakeLogTailer()
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+	s.PatchValue(&newWorkloadLogTailer, func(clock.Clock, state.LogTailerState, debugLogParams) (workloadLogTailer, error) {
+		return nil, errors.New("boom")
+	})
+
+	err := handleDebugLogDBRequest(s.clock, s.timeout, nil, debugLogParams{includeWorkload: true}, s.sock, nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(tailer.stopped, jc.IsTrue)
+}
+
 func (s *debugLogDBIntSuite) runRequest(params debugLogParams, stop chan struct{}) chan error {
 	done := make(chan error)
 	go func() {
@@ -282,6 +373,25 @@ func (t *fakeLogTailer) Err() error {
 	return nil
 }
 
+func newFakeWorkloadLogTailer() *fakeWorkloadLogTailer {
+	return &fakeWorkloadLogTailer{
+		logsCh: make(chan *params.LogMessage, 10),
+	}
+}
+
+type fakeWorkloadLogTailer struct {
+	logsCh  chan *params.LogMessage
+	stopped bool
+}
+
+func (t *fakeWorkloadLogTailer) Logs() <-chan *params.LogMessage {
+	return t.logsCh
+}
+
+func (t *fakeWorkloadLogTailer) Stop() {
+	t.stopped = true
+}
+
 func newFakeDebugLogSocket() *fakeDebugLogSocket {
 	return &fakeDebugLogSocket{
 		writes: make(chan string, 10),
@@ -0,0 +1,311 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider/exec"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
+)
+
+// workloadLogModule is the logging module prefix given to the lines
+// read from workload container logs. The container name is appended.
+const workloadLogModule = "workload"
+
+// workloadLogTailer streams the logs of the workload containers
+// of the units in a CAAS model.
+type workloadLogTailer interface {
+	// Logs returns the channel of log lines, which is closed once
+	// all of the container logs are exhausted.
+	Logs() <-chan *params.LogMessage
+
+	// Stop stops reading the container logs.
+	Stop()
+}
+
+// workloadLogState describes the state methods required to
+// find the pods of a model's units.
+type workloadLogState interface {
+	Model() (*state.Model, error)
+	AllApplications() ([]*state.Application, error)
+}
+
+// execClientGetter is implemented by CAAS brokers able to
+// read the logs of the pods they manage.
+type execClientGetter interface {
+	ExecClient() exec.Executor
+}
+
+// checkWorkloadLogAccess returns an error unless the authenticated
+// entity is a user allowed to read the workload logs of the model.
+func checkWorkloadLogAccess(st *state.State, entity httpcontext.Entity) error {
+	if _, ok := entity.Tag().(names.UserTag); !ok {
+		return &params.Error{Code: params.CodeForbidden, Message: "access denied"}
+	}
+	ok, err := entityHasPermission(st, entity, permission.SuperuserAccess, st.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		ok, err = entityHasPermission(st, entity, permission.ReadAccess, names.NewModelTag(st.ModelUUID()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if !ok {
+		return &params.Error{Code: params.CodeForbidden, Message: "access denied"}
+	}
+
+	model, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if model.Type() != state.ModelTypeCAAS {
+		return errors.NotSupportedf("workload logs for %s models", model.Type())
+	}
+	return nil
+}
+
+var newWorkloadLogTailer = _newWorkloadLogTailer // For replacing in tests
+
+func _newWorkloadLogTailer(clock clock.Clock, st state.LogTailerState, reqParams debugLogParams) (workloadLogTailer, error) {
+	wst, ok := st.(workloadLogState)
+	if !ok {
+		return nil, errors.NotSupportedf("workload logs")
+	}
+	model, err := wst.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	broker, err := stateenvirons.GetNewCAASBrokerFunc(caas.New)(model)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting caas broker")
+	}
+	getter, ok := broker.(execClientGetter)
+	if !ok {
+		return nil, errors.NotSupportedf("workload logs for cloud %q", model.CloudName())
+	}
+
+	unitPods := make(map[string]string)
+	if reqParams.filterLevel <= loggo.INFO {
+		apps, err := wst.AllApplications()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, app := range apps {
+			units, err := app.AllUnits()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, u := range units {
+				info, err := u.ContainerInfo()
+				if errors.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if info.ProviderId() != "" {
+					unitPods[u.Tag().String()] = info.ProviderId()
+				}
+			}
+		}
+	}
+	return startWorkloadLogTailer(clock, getter.ExecClient(), unitPods, reqParams), nil
+}
+
+type workloadLogTailerImpl struct {
+	clock   clock.Clock
+	filter  workloadLogFilter
+	logs    chan *params.LogMessage
+	stop    chan struct{}
+	stopped sync.Once
+	wg      sync.WaitGroup
+}
+
+// startWorkloadLogTailer starts reading the logs of the containers
+// of the specified pods, keyed by the tag of the unit they belong to.
+func startWorkloadLogTailer(
+	clock clock.Clock,
+	executor exec.Executor,
+	unitPods map[string]string,
+	reqParams debugLogParams,
+) *workloadLogTailerImpl {
+	t := &workloadLogTailerImpl{
+		clock:  clock,
+		filter: newWorkloadLogFilter(reqParams),
+		logs:   make(chan *params.LogMessage),
+		stop:   make(chan struct{}),
+	}
+	logsParams := exec.LogsParams{
+		Follow: !reqParams.noTail,
+	}
+	if reqParams.backlog > 0 && !reqParams.fromTheStart {
+		tailLines := int64(reqParams.backlog)
+		logsParams.TailLines = &tailLines
+	}
+	if !reqParams.startTime.IsZero() {
+		startTime := reqParams.startTime
+		logsParams.SinceTime = &startTime
+	}
+	for entity, podName := range unitPods {
+		if !t.filter.entityIncluded(entity) {
+			continue
+		}
+		t.wg.Add(1)
+		go t.tailPod(executor, entity, podName, logsParams)
+	}
+	go func() {
+		t.wg.Wait()
+		close(t.logs)
+	}()
+	return t
+}
+
+// Logs is part of the workloadLogTailer interface.
+func (t *workloadLogTailerImpl) Logs() <-chan *params.LogMessage {
+	return t.logs
+}
+
+// Stop is part of the workloadLogTailer interface.
+func (t *workloadLogTailerImpl) Stop() {
+	t.stopped.Do(func() { close(t.stop) })
+	t.wg.Wait()
+}
+
+func (t *workloadLogTailerImpl) tailPod(executor exec.Executor, entity, podName string, logsParams exec.LogsParams) {
+	defer t.wg.Done()
+
+	lines := make(chan exec.LogLine)
+	done := make(chan error, 1)
+	logsParams.PodName = podName
+	logsParams.Lines = lines
+	go func() {
+		done <- executor.Logs(logsParams, t.stop)
+	}()
+	for {
+		select {
+		case <-t.stop:
+			<-done
+			return
+		case err := <-done:
+			if err != nil {
+				logger.Warningf("reading workload logs of %s: %v", entity, err)
+			}
+			return
+		case line := <-lines:
+			rec := t.formatLogLine(entity, line)
+			if !t.filter.moduleIncluded(rec.Module) {
+				continue
+			}
+			select {
+			case t.logs <- rec:
+			case <-t.stop:
+				<-done
+				return
+			}
+		}
+	}
+}
+
+func (t *workloadLogTailerImpl) formatLogLine(entity string, line exec.LogLine) *params.LogMessage {
+	timestamp := line.Timestamp
+	if timestamp.IsZero() {
+		timestamp = t.clock.Now()
+	}
+	return &params.LogMessage{
+		Entity:    entity,
+		Timestamp: timestamp.In(time.UTC),
+		Severity:  loggo.INFO.String(),
+		Module:    workloadLogModule + "." + line.ContainerName,
+		Message:   line.Message,
+	}
+}
+
+// workloadLogFilter applies the entity and module filters of a
+// debug-log request to workload log lines, matching the way the
+// log tailer filters the logs stored in the database.
+type workloadLogFilter struct {
+	includeEntity *regexp.Regexp
+	excludeEntity *regexp.Regexp
+	includeModule *regexp.Regexp
+	excludeModule *regexp.Regexp
+}
+
+func newWorkloadLogFilter(reqParams debugLogParams) workloadLogFilter {
+	return workloadLogFilter{
+		includeEntity: entityRegexp(reqParams.includeEntity),
+		excludeEntity: entityRegexp(reqParams.excludeEntity),
+		includeModule: moduleRegexp(reqParams.includeModule),
+		excludeModule: moduleRegexp(reqParams.excludeModule),
+	}
+}
+
+// entityIncluded reports whether the logs of the unit with the
+// specified tag are included. The unit's application tag is also
+// matched, as the agent logs of a CAAS model are logged against it.
+func (f workloadLogFilter) entityIncluded(entity string) bool {
+	entities := []string{entity}
+	if tag, err := names.ParseUnitTag(entity); err == nil {
+		appName, err := names.UnitApplication(tag.Id())
+		if err == nil {
+			entities = append(entities, names.NewApplicationTag(appName).String())
+		}
+	}
+	included := f.includeEntity == nil
+	for _, e := range entities {
+		if matches(f.excludeEntity, e, false) {
+			return false
+		}
+		included = included || matches(f.includeEntity, e, false)
+	}
+	return included
+}
+
+func (f workloadLogFilter) moduleIncluded(module string) bool {
+	return matches(f.includeModule, module, true) && !matches(f.excludeModule, module, false)
+}
+
+func matches(re *regexp.Regexp, value string, empty bool) bool {
+	if re == nil {
+		return empty
+	}
+	return re.MatchString(value)
+}
+
+func entityRegexp(entities []string) *regexp.Regexp {
+	if len(entities) == 0 {
+		return nil
+	}
+	var patterns []string
+	for _, entity := range entities {
+		patterns = append(patterns, strings.Replace(regexp.QuoteMeta(entity), `\*`, ".*", -1))
+	}
+	return regexp.MustCompile(`^(` + strings.Join(patterns, "|") + `)$`)
+}
+
+func moduleRegexp(modules []string) *regexp.Regexp {
+	if len(modules) == 0 {
+		return nil
+	}
+	var patterns []string
+	for _, module := range modules {
+		patterns = append(patterns, regexp.QuoteMeta(module))
+	}
+	return regexp.MustCompile(`^(` + strings.Join(patterns, "|") + `)(\..+)?$`)
+}
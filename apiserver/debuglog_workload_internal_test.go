@@ -0,0 +1,189 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas/kubernetes/provider/exec"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type workloadLogTailerSuite struct {
+	coretesting.BaseSuite
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&workloadLogTailerSuite{})
+
+func (s *workloadLogTailerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC))
+}
+
+func (s *workloadLogTailerSuite) readAll(c *gc.C, tailer workloadLogTailer) []*params.LogMessage {
+	var result []*params.LogMessage
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case rec, ok := <-tailer.Logs():
+			if !ok {
+				sort.Slice(result, func(i, j int) bool {
+					return result[i].Entity+result[i].Module < result[j].Entity+result[j].Module
+				})
+				return result
+			}
+			result = append(result, rec)
+		case <-timeout:
+			c.Fatalf("timed out waiting for workload logs")
+		}
+	}
+}
+
+func (s *workloadLogTailerSuite) TestLogs(c *gc.C) {
+	backlog := int64(10)
+	since := time.Date(2020, 5, 1, 9, 0, 0, 0, time.UTC)
+	executor := &fakeExecutor{lines: map[string][]exec.LogLine{
+		"gitlab-0-pod": {{
+			ContainerName: "gitlab",
+			Timestamp:     time.Date(2020, 5, 1, 9, 30, 0, 0, time.UTC),
+			Message:       "listening",
+		}, {
+			ContainerName: "sidecar",
+			Message:       "no timestamp",
+		}},
+		"mariadb-0-pod": {{
+			ContainerName: "mariadb",
+			Timestamp:     time.Date(2020, 5, 1, 9, 31, 0, 0, time.UTC),
+			Message:       "ready",
+		}},
+	}}
+	tailer := startWorkloadLogTailer(s.clock, executor, map[string]string{
+		"unit-gitlab-0":  "gitlab-0-pod",
+		"unit-mariadb-0": "mariadb-0-pod",
+	}, debugLogParams{
+		noTail:    true,
+		backlog:   uint(backlog),
+		startTime: since,
+	})
+	defer tailer.Stop()
+
+	c.Assert(s.readAll(c, tailer), jc.DeepEquals, []*params.LogMessage{{
+		Entity:    "unit-gitlab-0",
+		Timestamp: time.Date(2020, 5, 1, 9, 30, 0, 0, time.UTC),
+		Severity:  "INFO",
+		Module:    "workload.gitlab",
+		Message:   "listening",
+	}, {
+		Entity:    "unit-gitlab-0",
+		Timestamp: s.clock.Now(),
+		Severity:  "INFO",
+		Module:    "workload.sidecar",
+		Message:   "no timestamp",
+	}, {
+		Entity:    "unit-mariadb-0",
+		Timestamp: time.Date(2020, 5, 1, 9, 31, 0, 0, time.UTC),
+		Severity:  "INFO",
+		Module:    "workload.mariadb",
+		Message:   "ready",
+	}})
+
+	c.Assert(executor.params, gc.HasLen, 2)
+	for _, p := range executor.params {
+		c.Check(p.Follow, jc.IsFalse)
+		c.Check(*p.TailLines, gc.Equals, backlog)
+		c.Check(*p.SinceTime, gc.Equals, since)
+	}
+}
+
+func (s *workloadLogTailerSuite) TestLogsFiltered(c *gc.C) {
+	executor := &fakeExecutor{lines: map[string][]exec.LogLine{
+		"gitlab-0-pod": {
+			{ContainerName: "gitlab", Message: "listening"},
+			{ContainerName: "sidecar", Message: "proxying"},
+		},
+		"mariadb-0-pod": {
+			{ContainerName: "mariadb", Message: "ready"},
+		},
+	}}
+	tailer := startWorkloadLogTailer(s.clock, executor, map[string]string{
+		"unit-gitlab-0":  "gitlab-0-pod",
+		"unit-mariadb-0": "mariadb-0-pod",
+	}, debugLogParams{
+		noTail:        true,
+		includeEntity: []string{"application-gitlab"},
+		excludeModule: []string{"workload.sidecar"},
+	})
+	defer tailer.Stop()
+
+	logs := s.readAll(c, tailer)
+	c.Assert(logs, gc.HasLen, 1)
+	c.Assert(logs[0].Entity, gc.Equals, "unit-gitlab-0")
+	c.Assert(logs[0].Module, gc.Equals, "workload.gitlab")
+	c.Assert(executor.params, gc.HasLen, 1)
+	c.Assert(executor.params[0].PodName, gc.Equals, "gitlab-0-pod")
+}
+
+func (s *workloadLogTailerSuite) TestLogsPodError(c *gc.C) {
+	executor := &fakeExecutor{err: errors.NotFoundf("pod")}
+	tailer := startWorkloadLogTailer(s.clock, executor, map[string]string{
+		"unit-gitlab-0": "gitlab-0-pod",
+	}, debugLogParams{})
+	defer tailer.Stop()
+
+	c.Assert(s.readAll(c, tailer), gc.HasLen, 0)
+}
+
+func (s *workloadLogTailerSuite) TestStop(c *gc.C) {
+	executor := &fakeExecutor{follow: true}
+	tailer := startWorkloadLogTailer(s.clock, executor, map[string]string{
+		"unit-gitlab-0": "gitlab-0-pod",
+	}, debugLogParams{})
+	tailer.Stop()
+
+	c.Assert(s.readAll(c, tailer), gc.HasLen, 0)
+	c.Assert(executor.params, gc.HasLen, 1)
+	c.Assert(executor.params[0].Follow, jc.IsTrue)
+	c.Assert(executor.params[0].TailLines, gc.IsNil)
+	c.Assert(executor.params[0].SinceTime, gc.IsNil)
+}
+
+type fakeExecutor struct {
+	exec.Executor
+
+	mu     sync.Mutex
+	params []exec.LogsParams
+
+	lines  map[string][]exec.LogLine
+	follow bool
+	err    error
+}
+
+func (e *fakeExecutor) Logs(p exec.LogsParams, cancel <-chan struct{}) error {
+	e.mu.Lock()
+	e.params = append(e.params, p)
+	e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	for _, line := range e.lines[p.PodName] {
+		select {
+		case p.Lines <- line:
+		case <-cancel:
+			return nil
+		}
+	}
+	if e.follow {
+		<-cancel
+	}
+	return nil
+}
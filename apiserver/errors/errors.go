@@ -123,6 +123,29 @@ func IsRedirectError(err error) bool {
 	return ok
 }
 
+// OIDCLoginRequiredError is the error returned when a user logs in
+// without credentials to a controller that authenticates users with
+// an OpenID Connect issuer.
+type OIDCLoginRequiredError struct {
+	// IssuerURL holds the URL of the trusted issuer.
+	IssuerURL string
+
+	// ClientID holds the client ID registered for the controller.
+	ClientID string
+}
+
+// Error implements the error interface.
+func (e *OIDCLoginRequiredError) Error() string {
+	return fmt.Sprintf("login with OpenID Connect issuer %q required", e.IssuerURL)
+}
+
+// IsOIDCLoginRequiredError returns true if err is caused by an
+// OIDCLoginRequiredError.
+func IsOIDCLoginRequiredError(err error) bool {
+	_, ok := errors.Cause(err).(*OIDCLoginRequiredError)
+	return ok
+}
+
 var (
 	ErrBadId              = errors.New("id not found")
 	ErrBadCreds           = errors.New("invalid entity name or password")
@@ -217,7 +240,7 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusBadRequest
 	case params.CodeForbidden:
 		status = http.StatusForbidden
-	case params.CodeDischargeRequired:
+	case params.CodeDischargeRequired, params.CodeOIDCLoginRequired:
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
@@ -288,6 +311,8 @@ func ServerError(err error) *params.Error {
 		code = params.CodeForbidden
 	case stateerrors.IsIncompatibleSeriesError(err):
 		code = params.CodeIncompatibleSeries
+	case stateerrors.IsPlacementPolicyError(err):
+		code = params.CodePlacementPolicyViolated
 	case IsDischargeRequiredError(err):
 		dischErr := errors.Cause(err).(*DischargeRequiredError)
 		code = params.CodeDischargeRequired
@@ -313,6 +338,13 @@ func ServerError(err error) *params.Error {
 			ControllerTag:   controllerTag,
 			ControllerAlias: redirErr.ControllerAlias,
 		}.AsMap()
+	case IsOIDCLoginRequiredError(err):
+		oidcErr := errors.Cause(err).(*OIDCLoginRequiredError)
+		code = params.CodeOIDCLoginRequired
+		info = params.OIDCLoginRequiredErrorInfo{
+			IssuerURL: oidcErr.IssuerURL,
+			ClientID:  oidcErr.ClientID,
+		}.AsMap()
 	case errors.IsQuotaLimitExceeded(err):
 		code = params.CodeQuotaLimitExceeded
 	default:
@@ -413,6 +445,8 @@ func RestoreError(err error) error {
 		return err
 	case params.IsCodeQuotaLimitExceeded(err):
 		return errors.NewQuotaLimitExceeded(nil, msg)
+	case params.IsCodePlacementPolicyViolated(err):
+		return stateerrors.NewPlacementPolicyError("%s", msg)
 	default:
 		return err
 	}
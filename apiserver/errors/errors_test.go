@@ -209,11 +209,34 @@ var errorTransformTests = []struct {
 		}
 		return true
 	},
+}, {
+	err: &apiservererrors.OIDCLoginRequiredError{
+		IssuerURL: "https://sso.example.com",
+		ClientID:  "juju",
+	},
+	status: http.StatusUnauthorized,
+	code:   params.CodeOIDCLoginRequired,
+	helperFunc: func(err error) bool {
+		err1, ok := err.(*params.Error)
+		exp := asMap(params.OIDCLoginRequiredErrorInfo{
+			IssuerURL: "https://sso.example.com",
+			ClientID:  "juju",
+		})
+		if !ok || err1.Info == nil || !reflect.DeepEqual(err1.Info, exp) {
+			return false
+		}
+		return true
+	},
 }, {
 	err:        errors.QuotaLimitExceededf("mailbox full"),
 	code:       params.CodeQuotaLimitExceeded,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeQuotaLimitExceeded,
+}, {
+	err:        stateerrors.NewPlacementPolicyError("cannot place unit"),
+	code:       params.CodePlacementPolicyViolated,
+	status:     http.StatusInternalServerError,
+	helperFunc: params.IsCodePlacementPolicyViolated,
 }, {
 	err:    nil,
 	code:   "",
@@ -280,7 +303,8 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 			params.CodeDischargeRequired,
 			params.CodeModelNotFound,
 			params.CodeRetry,
-			params.CodeRedirect:
+			params.CodeRedirect,
+			params.CodeOIDCLoginRequired:
 			continue
 		case params.CodeOperationBlocked:
 			// ServerError doesn't actually have a case for this code.
@@ -264,6 +264,10 @@ func (cc *mockCloudContainer) Address() *network.SpaceAddress {
 	return nil
 }
 
+func (cc *mockCloudContainer) SpaceAddresses() network.SpaceAddresses {
+	return nil
+}
+
 func (cc *mockCloudContainer) Ports() []string {
 	return nil
 }
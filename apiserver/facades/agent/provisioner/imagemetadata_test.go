@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facades/agent/provisioner"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	imagetesting "github.com/juju/juju/environs/imagemetadata/testing"
 	sstesting "github.com/juju/juju/environs/simplestreams/testing"
@@ -86,6 +87,26 @@ func (s *ImageMetadataSuite) TestMetadataFromState(c *gc.C) {
 	s.assertImageMetadataResults(c, result, expected...)
 }
 
+func (s *ImageMetadataSuite) TestMetadataSkippedForImageConstraint(c *gc.C) {
+	api, err := provisioner.NewProvisionerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Write metadata to state.
+	expected := s.expectedDataSoureImageMetadata()
+	err = s.State.CloudImageMetadataStorage.SaveMetadata(s.convertCloudImageMetadata(expected[0]))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine asks for a specific image, so none is looked up.
+	err = s.machines[0].SetConstraints(constraints.MustParse("image-id=ami-0123456"))
+	c.Assert(err, jc.ErrorIsNil)
+	expected[0] = nil
+
+	result, err := api.ProvisioningInfo(s.getTestMachinesTags(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertImageMetadataResults(c, result, expected...)
+}
+
 func (s *ImageMetadataSuite) getTestMachinesTags(c *gc.C) params.Entities {
 
 	testMachines := make([]params.Entity, len(s.machines))
@@ -208,6 +208,12 @@ type ProvisionerAPIV10 struct {
 // ProvisionerAPIV11 provides v10 of the provisioner facade.
 // It relies on agent-set origin when calling SetHostMachineNetworkConfig.
 type ProvisionerAPIV11 struct {
+	*ProvisionerAPIV12
+}
+
+// ProvisionerAPIV12 provides v12 of the provisioner facade.
+// Added ReconcileMachinePools
+type ProvisionerAPIV12 struct {
 	*ProvisionerAPI
 }
 
@@ -276,13 +282,22 @@ func NewProvisionerAPIV10(st *state.State, resources facade.Resources, authorize
 
 // NewProvisionerAPIV11 creates a new server-side Provisioner API facade.
 func NewProvisionerAPIV11(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV11, error) {
-	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	provisionerAPI, err := NewProvisionerAPIV12(st, resources, authorizer)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &ProvisionerAPIV11{provisionerAPI}, nil
 }
 
+// NewProvisionerAPIV12 creates a new server-side Provisioner API facade.
+func NewProvisionerAPIV12(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ProvisionerAPIV12, error) {
+	provisionerAPI, err := NewProvisionerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ProvisionerAPIV12{provisionerAPI}, nil
+}
+
 func (api *ProvisionerAPI) getMachine(canAccess common.AuthFunc, tag names.MachineTag) (*state.Machine, error) {
 	if !canAccess(tag) {
 		return nil, apiservererrors.ErrPerm
@@ -445,6 +460,10 @@ func (api *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerCo
 	case instance.LXD:
 		cfg[config.LXDSnapChannel] = mConfig.LXDSnapChannel()
 		// TODO(jam): DefaultMTU needs to be handled here
+	case instance.KVM:
+		if cpus := mConfig.KVMPinnedCPUs(); cpus != "" {
+			cfg[config.KVMPinnedCPUs] = cpus
+		}
 	}
 
 	if url, set := mConfig.ContainerImageMetadataURL(); set {
@@ -692,7 +711,8 @@ func controllerInstances(st *state.State) ([]instance.Id, error) {
 }
 
 // commonServiceInstances returns instances with
-// services in common with the specified machine.
+// services in common with the specified machine, or
+// running services they have anti-affinity with.
 func commonServiceInstances(st *state.State, m *state.Machine) ([]instance.Id, error) {
 	units, err := m.Units()
 	if err != nil {
@@ -703,7 +723,7 @@ func commonServiceInstances(st *state.State, m *state.Machine) ([]instance.Id, e
 		if !unit.IsPrincipal() {
 			continue
 		}
-		instanceIds, err := state.ApplicationInstances(st, unit.ApplicationName())
+		instanceIds, err := state.ApplicationDistributionGroup(st, unit.ApplicationName())
 		if err != nil {
 			return nil, err
 		}
@@ -878,6 +898,21 @@ func (api *ProvisionerAPI) WatchMachineErrorRetry() (params.NotifyWatchResult, e
 	return result, nil
 }
 
+// ReconcileMachinePools replaces the lost machines of the model's
+// machine pools, and brings the pools to their target sizes.
+func (api *ProvisionerAPI) ReconcileMachinePools() (params.ErrorResult, error) {
+	result := params.ErrorResult{}
+	if !api.authorizer.AuthController() {
+		return result, apiservererrors.ErrPerm
+	}
+	err := api.st.ReconcileMachinePools(common.MaxWait(nil))
+	result.Error = apiservererrors.ServerError(err)
+	return result, nil
+}
+
+// ReconcileMachinePools isn't on the v11 API.
+func (p *ProvisionerAPIV11) ReconcileMachinePools(_, _ struct{}) {}
+
 // WatchModelMachinesCharmProfiles returns a StringsWatcher that notifies when
 // the provisioner should update the charm profiles used by a machine.
 //
@@ -1421,6 +1421,24 @@ func (s *withoutControllerSuite) TestContainerManagerConfigDefaults(c *gc.C) {
 	})
 }
 
+func (s *withoutControllerSuite) TestContainerManagerConfigKVMPinnedCPUs(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		config.KVMPinnedCPUs: "2,3",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := s.getManagerConfig(c, instance.KVM)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigModelUUID:      coretesting.ModelTag.Id(),
+		config.ContainerImageStreamKey: "released",
+		config.KVMPinnedCPUs:           "2,3",
+	})
+
+	cfg = s.getManagerConfig(c, instance.LXD)
+	_, ok := cfg[config.KVMPinnedCPUs]
+	c.Assert(ok, jc.IsFalse)
+}
+
 func (s *withoutControllerSuite) TestWatchMachineErrorRetry(c *gc.C) {
 	s.WaitForModelWatchersIdle(c, s.Model.UUID())
 	s.PatchValue(&provisioner.ErrorRetryWaitDelay, 2*coretesting.ShortWait)
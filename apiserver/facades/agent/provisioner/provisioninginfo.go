@@ -17,16 +17,20 @@ import (
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
 )
 
 // ProvisioningInfo returns the provisioning information for each given machine entity.
@@ -172,9 +176,8 @@ func (api *ProvisionerAPI) getProvisioningInfoBase(m *state.Machine,
 	var err error
 
 	result := params.ProvisioningInfoBase{
-		Series:            m.Series(),
-		Placement:         m.Placement(),
-		CloudInitUserData: env.Config().CloudInitUserData(),
+		Series:    m.Series(),
+		Placement: m.Placement(),
 
 		// EndpointBindings are used by MAAS by the provider. Operator defined
 		// space bindings are reflected in ProvisioningNetworkTopology.
@@ -185,6 +188,10 @@ func (api *ProvisionerAPI) getProvisioningInfoBase(m *state.Machine,
 		return result, errors.Trace(err)
 	}
 
+	if result.CloudInitUserData, err = api.machineCloudInitUserData(m, env); err != nil {
+		return result, errors.Annotate(err, "cannot get cloud-init user data")
+	}
+
 	if result.Volumes, result.VolumeAttachments, err = api.machineVolumeParams(m, env); err != nil {
 		return result, errors.Trace(err)
 	}
@@ -193,8 +200,12 @@ func (api *ProvisionerAPI) getProvisioningInfoBase(m *state.Machine,
 		return result, errors.Annotate(err, "cannot write lxd profiles")
 	}
 
-	if result.ImageMetadata, err = api.availableImageMetadata(m, env); err != nil {
-		return result, errors.Annotate(err, "cannot get available image metadata")
+	// Image metadata is not needed when the constraints choose the
+	// image; the provider starts the machine from that image.
+	if !result.Constraints.HasImage() {
+		if result.ImageMetadata, err = api.availableImageMetadata(m, env); err != nil {
+			return result, errors.Annotate(err, "cannot get available image metadata")
+		}
 	}
 
 	if result.ControllerConfig, err = api.st.ControllerConfig(); err != nil {
@@ -261,7 +272,15 @@ func (api *ProvisionerAPI) machineVolumeParams(
 		if err != nil {
 			return nil, nil, errors.Annotatef(err, "getting volume %q parameters", volumeTag.Id())
 		}
-		if _, err := env.StorageProvider(storage.ProviderType(volumeParams.Provider)); errors.IsNotFound(err) {
+		providerType := storage.ProviderType(volumeParams.Provider)
+		if providerType == provider.KVMProviderType {
+			// KVM disks are created by the KVM broker when the
+			// container starts, so they are only of use to KVM
+			// containers.
+			if m.ContainerType() != instance.KVM {
+				continue
+			}
+		} else if _, err := env.StorageProvider(providerType); errors.IsNotFound(err) {
 			// This storage type is not managed by the environ
 			// provider, so ignore it. It'll be managed by one
 			// of the storage provisioners.
@@ -520,6 +539,52 @@ func (api *ProvisionerAPI) machineLXDProfileNames(m *state.Machine, env environs
 	return pNames, nil
 }
 
+// machineCloudInitUserData returns the cloud-init user data for the
+// machine, merged from that of the model, then that of the principal
+// applications on the machine in name order, then that of the machine.
+func (api *ProvisionerAPI) machineCloudInitUserData(m *state.Machine, env environs.Environ) (map[string]interface{}, error) {
+	layers := []map[string]interface{}{env.Config().CloudInitUserData()}
+
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appNames := set.NewStrings()
+	for _, unit := range units {
+		if unit.IsPrincipal() {
+			appNames.Add(unit.ApplicationName())
+		}
+	}
+	for _, appName := range appNames.SortedValues() {
+		app, err := api.st.Application(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		appConfig, err := app.ApplicationConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		raw := appConfig.GetString(application.CloudInitUserDataConfigOptionName, "")
+		if raw == "" {
+			continue
+		}
+		userData, err := config.ParseCloudInitUserData(raw)
+		if err != nil {
+			return nil, errors.Annotatef(err, "application %q", appName)
+		}
+		layers = append(layers, userData)
+	}
+
+	if raw := m.CloudInitUserData(); raw != "" {
+		userData, err := config.ParseCloudInitUserData(raw)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %q", m.Id())
+		}
+		layers = append(layers, userData)
+	}
+	return config.MergeCloudInitUserData(layers...), nil
+}
+
 func (api *ProvisionerAPI) machineEndpointBindings(m *state.Machine) (map[string]*state.Bindings, error) {
 	units, err := m.Units()
 	if err != nil {
@@ -9,10 +9,12 @@ import (
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/apiserver/facades/agent/provisioner"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
@@ -642,6 +644,49 @@ func (s *withoutControllerSuite) TestProviderInfoCloudInitUserData(c *gc.C) {
 		"package_upgrade": false})
 }
 
+func (s *withoutControllerSuite) TestProviderInfoCloudInitUserDataMerged(c *gc.C) {
+	attrs := map[string]interface{}{"cloudinit-userdata": validCloudInitUserData}
+	err := s.Model.UpdateModelConfig(attrs, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:            "quantal",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		CloudInitUserData: "postruncmd: [mkdir /tmp/machine]\npackage_upgrade: true\n",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fields := environschema.Fields{
+		"cloudinit-userdata": environschema.Attr{Type: environschema.Tstring},
+	}
+	for _, name := range []string{"wordpress", "mysql"} {
+		app := s.AddTestingApplication(c, name, s.AddTestingCharm(c, name))
+		err := app.UpdateApplicationConfig(coreapplication.ConfigAttributes{
+			"cloudinit-userdata": fmt.Sprintf("packages: [%s-tools]\n", name),
+		}, nil, fields, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: m.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.CloudInitUserData, gc.DeepEquals, map[string]interface{}{
+		"packages": []interface{}{
+			"python-keystoneclient", "python-glanceclient", "mysql-tools", "wordpress-tools",
+		},
+		"preruncmd": []interface{}{"mkdir /tmp/preruncmd", "mkdir /tmp/preruncmd2"},
+		"postruncmd": []interface{}{
+			"mkdir /tmp/postruncmd", "mkdir /tmp/postruncmd2", "mkdir /tmp/machine",
+		},
+		"package_upgrade": true})
+}
+
 var validCloudInitUserData = `
 packages:
   - 'python-keystoneclient'
@@ -193,6 +193,30 @@ func (n *NetworkInfo) ProcessAPIRequest(args params.NetworkInfoParams) (params.N
 		networkInfos[corenetwork.AlphaSpaceId] = state.MachineNetworkInfoResult{
 			NetworkInfos: []network.NetworkInfo{{Addresses: interfaceAddr}},
 		}
+
+		// Pods attached to secondary networks have an address in the
+		// space of each network. These are both the binding and the
+		// ingress addresses for endpoints bound to that space.
+		spaceAddrs, err := n.unit.ContainerSpaceAddresses()
+		if err != nil {
+			return params.NetworkInfoResults{}, err
+		}
+		for _, a := range spaceAddrs {
+			networkInfos[a.SpaceID] = state.MachineNetworkInfoResult{
+				NetworkInfos: append(networkInfos[a.SpaceID].NetworkInfos,
+					network.NetworkInfo{Addresses: []network.InterfaceAddress{{Address: a.Value}}}),
+			}
+		}
+		for endpoint, space := range bindings {
+			if _, ok := endpointIngressAddresses[endpoint]; ok || space == corenetwork.AlphaSpaceId {
+				continue
+			}
+			for _, a := range spaceAddrs {
+				if a.SpaceID == space {
+					endpointIngressAddresses[endpoint] = append(endpointIngressAddresses[endpoint], a)
+				}
+			}
+		}
 	}
 
 	for endpoint, space := range bindings {
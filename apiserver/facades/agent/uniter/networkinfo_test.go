@@ -16,6 +16,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -269,6 +270,56 @@ func (s *networkInfoSuite) TestNetworksForRelationCAASModel(c *gc.C) {
 	c.Assert(egress, gc.DeepEquals, []string{"1.2.3.4/32"})
 }
 
+func (s *networkInfoSuite) TestProcessAPIRequestCAASSpaceAddresses(c *gc.C) {
+	st := s.Factory.MakeCAASModel(c, nil)
+	defer func() { _ = st.Close() }()
+
+	space, err := st.AddSpace("telco", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	f := factory.NewFactory(st, s.StatePool)
+	ch := f.MakeCharm(c, &factory.CharmParams{Name: "mysql", Series: "kubernetes"})
+	app := f.MakeApplication(c, &factory.ApplicationParams{
+		Name:             "mysql",
+		Charm:            ch,
+		EndpointBindings: map[string]string{"server": space.Id()},
+	})
+	unit := f.MakeUnit(c, &factory.UnitParams{Application: app})
+
+	telcoAddr := network.NewSpaceAddress("10.10.0.5")
+	telcoAddr.SpaceID = space.Id()
+	providerId, podAddr := "pod-uuid", "192.168.1.2"
+	var updateUnits state.UpdateUnitsOperation
+	updateUnits.Updates = []*state.UpdateUnitOperation{unit.UpdateOperation(state.UnitUpdateProperties{
+		ProviderId:     &providerId,
+		Address:        &podAddr,
+		SpaceAddresses: &network.SpaceAddresses{telcoAddr},
+	})}
+	err = app.UpdateUnits(&updateUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	netInfo, err := uniter.NewNetworkInfo(st, unit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := netInfo.ProcessAPIRequest(params.NetworkInfoParams{
+		Unit:      unit.UnitTag().String(),
+		Endpoints: []string{"server", "server-admin"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	server := result.Results["server"]
+	c.Assert(server.Error, gc.IsNil)
+	c.Assert(server.Info, gc.HasLen, 1)
+	c.Assert(server.Info[0].Addresses, gc.DeepEquals, []params.InterfaceAddress{{Address: "10.10.0.5"}})
+	c.Assert(server.IngressAddresses, gc.DeepEquals, []string{"10.10.0.5"})
+	c.Assert(server.EgressSubnets, gc.DeepEquals, []string{"10.10.0.5/32"})
+
+	admin := result.Results["server-admin"]
+	c.Assert(admin.Error, gc.IsNil)
+	c.Assert(admin.Info, gc.HasLen, 1)
+	c.Assert(admin.Info[0].Addresses, gc.DeepEquals, []params.InterfaceAddress{{Address: "192.168.1.2"}})
+}
+
 func (s *networkInfoSuite) newNetworkInfo(c *gc.C, tag names.UnitTag) *uniter.NetworkInfo {
 	ni, err := uniter.NewNetworkInfo(s.State, tag)
 	c.Assert(err, jc.ErrorIsNil)
@@ -1487,6 +1487,7 @@ func (u *UniterAPI) Refresh(args params.Entities) (params.UnitRefreshResults, er
 			if unit, err = u.getUnit(tag); err == nil {
 				result.Results[i].Life = life.Value(unit.Life().String())
 				result.Results[i].Resolved = params.ResolvedMode(unit.Resolved())
+				result.Results[i].Interrupting = unit.Interrupting()
 
 				var err1 error
 				result.Results[i].ProviderID, err1 = u.getProviderID(unit)
@@ -3554,13 +3555,16 @@ func (u *UniterAPI) commitHookChangesForOneUnit(unitTag names.UnitTag, changes p
 	}
 
 	if len(changes.OpenPorts)+len(changes.ClosePorts) > 0 {
-		var openPortRanges, closePortRanges []corenetwork.PortRange
+		var closePortRanges []corenetwork.PortRange
+		// Port ranges to open are keyed by the endpoint they are
+		// opened for; an empty endpoint stands for all endpoints.
+		openPortRanges := make(map[string][]corenetwork.PortRange)
 		for _, r := range changes.OpenPorts {
 			// Ensure the tag in the port open request matches the root unit name
 			if r.Tag != changes.Tag {
 				return apiservererrors.ErrPerm
 			}
-			openPortRanges = append(openPortRanges, corenetwork.PortRange{
+			openPortRanges[r.Endpoint] = append(openPortRanges[r.Endpoint], corenetwork.PortRange{
 				FromPort: r.FromPort,
 				ToPort:   r.ToPort,
 				Protocol: r.Protocol,
@@ -3578,10 +3582,7 @@ func (u *UniterAPI) commitHookChangesForOneUnit(unitTag names.UnitTag, changes p
 			})
 		}
 
-		// TODO(achilleas): we should be using endpoints instead of subnets
-		// here. This emulates the existing behavior for the individual
-		// Open/ClosePort API calls.
-		modelOp, err := unit.OpenClosePortsInSubnetOperation("", openPortRanges, closePortRanges)
+		modelOp, err := unit.OpenClosePortsOnEndpointsOperation(openPortRanges, closePortRanges)
 		if err != nil {
 			return errors.Trace(err)
 		}
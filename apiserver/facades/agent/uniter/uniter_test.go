@@ -4966,8 +4966,8 @@ func (s *uniterNetworkInfoSuite) TestCommitHookChanges(c *gc.C) {
 	b := apiuniter.NewCommitHookParamsBuilder(s.wordpressUnit.UnitTag())
 	b.UpdateNetworkInfo()
 	b.UpdateRelationUnitSettings(relList[0].Tag().String(), params.Settings{"just": "added"}, params.Settings{"app_data": "updated"})
-	b.OpenPortRange("tcp", 80, 81)
-	b.OpenPortRange("tcp", 7337, 7337) // same port closed below; this should be a no-op
+	b.OpenPortRange("", "tcp", 80, 81)
+	b.OpenPortRange("", "tcp", 7337, 7337) // same port closed below; this should be a no-op
 	b.ClosePortRange("tcp", 7337, 7337)
 	b.UpdateCharmState(map[string]string{"charm-key": "charm-value"})
 	req, _ := b.Build()
@@ -5024,6 +5024,32 @@ func (s *uniterNetworkInfoSuite) TestCommitHookChanges(c *gc.C) {
 	c.Assert(appCfg, gc.DeepEquals, map[string]interface{}{"app_data": "updated"}, gc.Commentf("application data not updated by leader unit"))
 }
 
+func (s *uniterNetworkInfoSuite) TestCommitHookChangesOpensPortsOnEndpoints(c *gc.C) {
+	b := apiuniter.NewCommitHookParamsBuilder(s.wordpressUnit.UnitTag())
+	b.OpenPortRange("", "tcp", 22, 22)
+	b.OpenPortRange("url", "tcp", 80, 80)
+	req, _ := b.Build()
+
+	api, err := uniter.NewUniterAPI(s.facadeContext())
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.CommitHookChanges(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: nil}},
+	})
+
+	machineID, err := s.wordpressUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineID)
+	c.Assert(err, jc.ErrorIsNil)
+	ports, err := machine.OpenedPortsInSubnet("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.PortRangeEndpointsForUnit(s.wordpressUnit.Name()), jc.DeepEquals, map[network.PortRange]string{
+		{Protocol: "tcp", FromPort: 22, ToPort: 22}: "",
+		{Protocol: "tcp", FromPort: 80, ToPort: 80}: "url",
+	})
+}
+
 func (s *uniterNetworkInfoSuite) TestCommitHookChangesWhenNotLeader(c *gc.C) {
 	s.addRelationAndAssertInScope(c)
 
@@ -5068,8 +5094,8 @@ func (s *uniterSuite) TestCommitHookChangesWithStorage(c *gc.C) {
 	stCount := uint64(1)
 	b := apiuniter.NewCommitHookParamsBuilder(unit.UnitTag())
 	b.UpdateNetworkInfo()
-	b.OpenPortRange("tcp", 80, 81)
-	b.OpenPortRange("tcp", 7337, 7337) // same port closed below; this should be a no-op
+	b.OpenPortRange("", "tcp", 80, 81)
+	b.OpenPortRange("", "tcp", 7337, 7337) // same port closed below; this should be a no-op
 	b.ClosePortRange("tcp", 7337, 7337)
 	b.UpdateCharmState(map[string]string{"charm-key": "charm-value"})
 	b.AddStorage(map[string][]params.StorageConstraints{
@@ -109,23 +109,36 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = apiservererrors.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.m.WatchForModelConfigChanges()
-			// Consume the initial event. Technically, API
-			// calls to Watch 'transmit' the initial event
-			// in the Watch response. But NotifyWatchers
-			// have no state to transmit.
-			if _, ok := <-watch.Changes(); ok {
-				result.Results[i].NotifyWatcherId = u.resources.Register(watch)
-				err = nil
-			} else {
-				err = watcher.EnsureErr(watch)
-			}
+			result.Results[i].NotifyWatcherId, err = u.watchAPIVersion(tag)
 		}
 		result.Results[i].Error = apiservererrors.ServerError(err)
 	}
 	return result, nil
 }
 
+// watchAPIVersion returns the id of a watcher which notifies when the
+// desired version of the agent may have changed. Machine agents also
+// watch their machine, as an upgrade may be staged for the machine.
+func (u *UpgraderAPI) watchAPIVersion(tag names.Tag) (string, error) {
+	var watch state.NotifyWatcher = u.m.WatchForModelConfigChanges()
+	if tag, ok := tag.(names.MachineTag); ok {
+		machine, err := u.st.Machine(tag.Id())
+		if err != nil {
+			_ = watch.Stop()
+			return "", errors.Trace(err)
+		}
+		watch = common.NewMultiNotifyWatcher(watch, machine.Watch())
+	}
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
 func (u *UpgraderAPI) getGlobalAgentVersion() (version.Number, *config.Config, error) {
 	// Get the Agent Version requested in the Model Config
 	cfg, err := u.m.ModelConfig()
@@ -143,16 +156,28 @@ type hasIsManager interface {
 	IsManager() bool
 }
 
-func (u *UpgraderAPI) entityIsManager(tag names.Tag) bool {
+// hasTargetAgentVersion is implemented by machines, for which an
+// upgrade may be staged ahead of the rest of the model.
+type hasTargetAgentVersion interface {
+	TargetAgentVersion() (version.Number, bool)
+}
+
+// entityAgentVersion returns the agent version that the entity should
+// run: the version staged for it, if that is later than the model agent
+// version, or else the model agent version. It also reports whether the
+// entity is a controller.
+func (u *UpgraderAPI) entityAgentVersion(tag names.Tag, agentVersion version.Number) (version.Number, bool) {
 	entity, err := u.st.FindEntity(tag)
 	if err != nil {
-		return false
+		return agentVersion, false
 	}
-	if m, ok := entity.(hasIsManager); !ok {
-		return false
-	} else {
-		return m.IsManager()
+	if m, ok := entity.(hasTargetAgentVersion); ok {
+		if target, ok := m.TargetAgentVersion(); ok && target.Compare(agentVersion) > 0 {
+			agentVersion = target
+		}
 	}
+	m, ok := entity.(hasIsManager)
+	return agentVersion, ok && m.IsManager()
 }
 
 // DesiredVersion reports the Agent Version that we want that agent to be running
@@ -161,12 +186,10 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	if len(args.Entities) == 0 {
 		return params.VersionResults{}, nil
 	}
-	agentVersion, _, err := u.getGlobalAgentVersion()
+	modelAgentVersion, _, err := u.getGlobalAgentVersion()
 	if err != nil {
 		return params.VersionResults{}, apiservererrors.ServerError(err)
 	}
-	// Is the desired version greater than the current API server version?
-	isNewerVersion := agentVersion.Compare(jujuversion.Current) > 0
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -175,17 +198,20 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 		}
 		err = apiservererrors.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			// Only return the globally desired agent version if the
-			// asking entity is a machine agent with JobManageModel or
-			// if this API server is running the globally desired agent
-			// version. Otherwise report this API server's current
-			// agent version.
+			agentVersion, isManager := u.entityAgentVersion(tag, modelAgentVersion)
+			// Is the desired version greater than the current API server version?
+			isNewerVersion := agentVersion.Compare(jujuversion.Current) > 0
+			// Only return the desired agent version if the asking
+			// entity is a machine agent with JobManageModel or if
+			// this API server is running the desired agent version.
+			// Otherwise report this API server's current agent
+			// version.
 			//
 			// This ensures that state machine agents will upgrade
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
+			if !isNewerVersion || isManager {
 				results[i].Version = &agentVersion
 			} else {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
@@ -402,3 +402,95 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
+
+func (s *upgraderSuite) stageAgentVersion(c *gc.C, target version.Number) version.Number {
+	older := jujuversion.Current
+	older.Minor--
+	err := statetesting.SetAgentVersion(s.State, older)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rawMachine.SetTargetAgentVersion(target)
+	c.Assert(err, jc.ErrorIsNil)
+	return older
+}
+
+func (s *upgraderSuite) TestDesiredVersionStagedForMachine(c *gc.C) {
+	older := s.stageAgentVersion(c, jujuversion.Current)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: other.Tag(),
+	}
+	otherUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, jujuversion.Current)
+
+	args = params.Entities{Entities: []params.Entity{{Tag: other.Tag().String()}}}
+	results, err = otherUpgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, older)
+}
+
+func (s *upgraderSuite) TestDesiredVersionStagedRestrictedForNonAPIAgents(c *gc.C) {
+	newer := jujuversion.Current
+	newer.Patch++
+	s.stageAgentVersion(c, newer)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, jujuversion.Current)
+}
+
+func (s *upgraderSuite) TestDesiredVersionStagedVersionOvertaken(c *gc.C) {
+	staged := jujuversion.Current
+	staged.Minor--
+	older := staged
+	older.Minor--
+	err := statetesting.SetAgentVersion(s.State, older)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rawMachine.SetTargetAgentVersion(staged)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Once the model agent version passes the staged version, the
+	// model agent version applies again.
+	err = statetesting.SetAgentVersion(s.State, jujuversion.Current)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, jujuversion.Current)
+}
+
+func (s *upgraderSuite) TestWatchAPIVersionStagedUpgrade(c *gc.C) {
+	older := jujuversion.Current
+	older.Minor--
+	err := statetesting.SetAgentVersion(s.State, older)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err = s.rawMachine.SetTargetAgentVersion(jujuversion.Current)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
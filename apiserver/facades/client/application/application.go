@@ -100,6 +100,25 @@ type APIv11 struct {
 // APIv12 provides the Application API facade for version 12.
 // It adds the UnitsInfo method.
 type APIv12 struct {
+	*APIv13
+}
+
+// APIv13 provides the Application API facade for version 13.
+// The Expose call accepts per-endpoint expose settings.
+type APIv13 struct {
+	*APIv14
+}
+
+// APIv14 provides the Application API facade for version 14.
+// It adds the PlacementPolicies and SetPlacementPolicies methods.
+type APIv14 struct {
+	*APIv15
+}
+
+// APIv15 provides the Application API facade for version 15.
+// The Deploy, SetCharm and Get calls carry the origin of charms
+// obtained from charmhub.
+type APIv15 struct {
 	*APIBase
 }
 
@@ -207,13 +226,37 @@ func NewFacadeV11(ctx facade.Context) (*APIv11, error) {
 }
 
 func NewFacadeV12(ctx facade.Context) (*APIv12, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV13(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv12{api}, nil
 }
 
+func NewFacadeV13(ctx facade.Context) (*APIv13, error) {
+	api, err := NewFacadeV14(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv13{api}, nil
+}
+
+func NewFacadeV14(ctx facade.Context) (*APIv14, error) {
+	api, err := NewFacadeV15(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv14{api}, nil
+}
+
+func NewFacadeV15(ctx facade.Context) (*APIv15, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv15{api}, nil
+}
+
 type caasBrokerInterface interface {
 	ValidateStorageClass(config map[string]interface{}) error
 	Version() (*version.Number, error)
@@ -451,7 +494,7 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return trustFields, trustDefaults, nil
+		return AddTrustSchemaAndDefaults(iaasConfigSchema())
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -462,6 +505,24 @@ func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, s
 	return AddTrustSchemaAndDefaults(configSchema, defaults)
 }
 
+// iaasConfigSchema returns the schema fields and defaults of the
+// application config specific to IAAS models.
+func iaasConfigSchema() (environschema.Fields, schema.Defaults) {
+	fields := make(environschema.Fields)
+	defaults := make(schema.Defaults)
+	for _, f := range []environschema.Fields{cloudInitFields, statelessFields} {
+		for name, field := range f {
+			fields[name] = field
+		}
+	}
+	for _, d := range []schema.Defaults{cloudInitDefaults, statelessDefaults} {
+		for name, value := range d {
+			defaults[name] = value
+		}
+	}
+	return fields, defaults
+}
+
 func splitApplicationAndCharmConfig(modelType state.ModelType, inConfig map[string]string) (
 	appCfg map[string]interface{},
 	charmCfg map[string]string,
@@ -683,6 +744,9 @@ func deployApplication(
 	for k, v := range appConfig {
 		appSettings[k] = v
 	}
+	if err := validateCloudInitUserData(appSettings); err != nil {
+		return errors.Trace(err)
+	}
 
 	var applicationConfig *application.Config
 	configSchema, defaults, err := applicationConfigSchema(modelType)
@@ -735,6 +799,7 @@ func deployApplication(
 		Series:            args.Series,
 		Charm:             stateCharm(ch),
 		Channel:           csparams.Channel(args.Channel),
+		CharmOrigin:       charmOriginFromParams(args.CharmOrigin),
 		NumUnits:          args.NumUnits,
 		ApplicationConfig: applicationConfig,
 		CharmConfig:       settings,
@@ -848,6 +913,7 @@ type setCharmParams struct {
 	AppName               string
 	Application           Application
 	Channel               csparams.Channel
+	CharmOrigin           *state.CharmOrigin
 	ConfigSettingsStrings map[string]string
 	ConfigSettingsYAML    string
 	ResourceIDs           map[string]string
@@ -860,6 +926,28 @@ type forceParams struct {
 	ForceSeries, ForceUnits, Force bool
 }
 
+func charmOriginFromParams(origin *params.CharmOrigin) *state.CharmOrigin {
+	if origin == nil {
+		return nil
+	}
+	return &state.CharmOrigin{
+		Source:   origin.Source,
+		ID:       origin.ID,
+		Revision: origin.Revision,
+	}
+}
+
+func charmOriginToParams(origin *state.CharmOrigin) *params.CharmOrigin {
+	if origin == nil {
+		return nil
+	}
+	return &params.CharmOrigin{
+		Source:   origin.Source,
+		ID:       origin.ID,
+		Revision: origin.Revision,
+	}
+}
+
 // Update updates the application attributes, including charm URL,
 // minimum number of units, charm config and constraints.
 // All parameters in params.ApplicationUpdate except the application name are optional.
@@ -1024,6 +1112,7 @@ func (api *APIBase) SetCharm(args params.ApplicationSetCharm) error {
 			AppName:               args.ApplicationName,
 			Application:           oneApplication,
 			Channel:               channel,
+			CharmOrigin:           charmOriginFromParams(args.CharmOrigin),
 			ConfigSettingsStrings: args.ConfigSettings,
 			ConfigSettingsYAML:    args.ConfigSettingsYAML,
 			ResourceIDs:           args.ResourceIDs,
@@ -1159,6 +1248,7 @@ func (api *APIBase) applicationSetCharm(
 	cfg := state.SetCharmConfig{
 		Charm:              api.stateCharm(stateCharm),
 		Channel:            params.Channel,
+		CharmOrigin:        params.CharmOrigin,
 		ConfigSettings:     settings,
 		ForceSeries:        force.ForceSeries,
 		ForceUnits:         force.ForceUnits,
@@ -1341,7 +1431,42 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 					"juju config %s %s=<value>", caas.JujuExternalHostNameKey, args.ApplicationName, caas.JujuExternalHostNameKey)
 		}
 	}
-	return app.SetExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.SetExposed()
+	}
+	if api.modelType == state.ModelTypeCAAS {
+		return errors.NotSupportedf("per-endpoint expose settings for k8s applications")
+	}
+	exposedEndpoints, err := api.mapExposedEndpointSpaces(args.ExposedEndpoints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.MergeExposeSettings(exposedEndpoints)
+}
+
+// mapExposedEndpointSpaces converts the space names in the provided
+// expose settings into space IDs.
+func (api *APIBase) mapExposedEndpointSpaces(exposedEndpoints map[string]params.ExposedEndpoint) (map[string]state.ExposedEndpoint, error) {
+	spaceInfos, err := api.backend.AllSpaceInfos()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]state.ExposedEndpoint, len(exposedEndpoints))
+	for endpoint, exposed := range exposedEndpoints {
+		var spaceIDs []string
+		for _, spaceName := range exposed.ExposeToSpaces {
+			spaceInfo := spaceInfos.GetByName(spaceName)
+			if spaceInfo == nil {
+				return nil, errors.NotFoundf("space %q", spaceName)
+			}
+			spaceIDs = append(spaceIDs, spaceInfo.ID)
+		}
+		result[endpoint] = state.ExposedEndpoint{
+			ExposeToSpaceIDs: spaceIDs,
+			ExposeToCIDRs:    exposed.ExposeToCIDRs,
+		}
+	}
+	return result, nil
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
@@ -1860,6 +1985,78 @@ func (api *APIBase) SetConstraints(args params.SetConstraints) error {
 	return app.SetConstraints(args.Constraints)
 }
 
+// PlacementPolicies isn't on the v13 API.
+func (u *APIv13) PlacementPolicies(_, _ struct{}) {}
+
+// PlacementPolicies returns the placement policies of the given
+// applications.
+func (api *APIBase) PlacementPolicies(args params.Entities) (params.ApplicationPlacementPolicyResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.ApplicationPlacementPolicyResults{}, errors.Trace(err)
+	}
+	results := params.ApplicationPlacementPolicyResults{
+		Results: make([]params.ApplicationPlacementPolicyResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		tag, err := names.ParseApplicationTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		app, err := api.backend.Application(tag.Id())
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		policy := app.PlacementPolicy()
+		results.Results[i].Result = &params.ApplicationPlacementPolicy{
+			ApplicationTag: tag.String(),
+			AntiAffinity:   policy.AntiAffinity,
+			Strategy:       string(policy.Strategy),
+		}
+	}
+	return results, nil
+}
+
+// SetPlacementPolicies isn't on the v13 API.
+func (u *APIv13) SetPlacementPolicies(_, _ struct{}) {}
+
+// SetPlacementPolicies replaces the placement policies of the given
+// applications. The policies apply to units assigned from then on.
+func (api *APIBase) SetPlacementPolicies(args params.ApplicationPlacementPolicies) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Policies)),
+	}
+	for i, arg := range args.Policies {
+		results.Results[i].Error = apiservererrors.ServerError(api.setPlacementPolicy(arg))
+	}
+	return results, nil
+}
+
+func (api *APIBase) setPlacementPolicy(arg params.ApplicationPlacementPolicy) error {
+	tag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if api.modelType == state.ModelTypeCAAS {
+		return errors.NotSupportedf("placement policies for k8s applications")
+	}
+	app, err := api.backend.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return app.SetPlacementPolicy(application.PlacementPolicy{
+		AntiAffinity: arg.AntiAffinity,
+		Strategy:     application.PlacementStrategy(arg.Strategy),
+	})
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (api *APIBase) AddRelation(args params.AddRelation) (_ params.AddRelationResults, err error) {
 	var rel Relation
@@ -2317,6 +2514,9 @@ func (api *APIBase) setApplicationConfig(arg params.ApplicationConfigSet) error
 	}
 
 	if len(appConfigAttrs) > 0 {
+		if err := validateCloudInitUserData(appConfigAttrs); err != nil {
+			return errors.Trace(err)
+		}
 		if err := app.UpdateApplicationConfig(appConfigAttrs, nil, configSchema, defaults); err != nil {
 			return errors.Annotate(err, "updating application config values")
 		}
@@ -59,7 +59,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv12
+	api          *application.APIv15
 	deployParams map[string]application.DeployApplicationParams
 }
 
@@ -90,7 +90,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.caasBroker,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv12{api}
+	s.api = &application.APIv15{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -377,6 +377,28 @@ func (s *ApplicationSuite) TestSetCharmConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestSetCharmCharmOrigin(c *gc.C) {
+	err := s.api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+		CharmOrigin: &params.CharmOrigin{
+			Source:   "charmhub",
+			ID:       "postgresql-id",
+			Revision: 3,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCall(c, 2, "SetCharm", state.SetCharmConfig{
+		Charm: &state.Charm{},
+		CharmOrigin: &state.CharmOrigin{
+			Source:   "charmhub",
+			ID:       "postgresql-id",
+			Revision: 3,
+		},
+	})
+}
+
 func (s *ApplicationSuite) TestSetCharmConfigSettingsYAML(c *gc.C) {
 	err := s.api.SetCharm(params.ApplicationSetCharm{
 		ApplicationName: "postgresql",
@@ -1708,6 +1730,38 @@ func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	s.backend.generation.CheckCall(c, 0, "AssignApplication", "postgresql")
 }
 
+func (s *ApplicationSuite) TestSetApplicationConfigCloudInitUserData(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeIAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"cloudinit-userdata": "packages: [htop]",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "UpdateApplicationConfig")
+	c.Assert(app.Calls()[0].Args[0], jc.DeepEquals, coreapplication.ConfigAttributes{
+		"cloudinit-userdata": "packages: [htop]",
+	})
+}
+
+func (s *ApplicationSuite) TestSetApplicationConfigInvalidCloudInitUserData(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeIAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"cloudinit-userdata": "runcmd: [ls]",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, "cloudinit-userdata: runcmd not allowed, use preruncmd or postruncmd instead")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{})
@@ -1847,6 +1901,109 @@ func (s *ApplicationSuite) TestCAASExposeWithHostname(c *gc.C) {
 	app.CheckCallNames(c, "ApplicationConfig", "SetExposed")
 }
 
+func (s *ApplicationSuite) TestExposeEndpoints(c *gc.C) {
+	s.backend.spaceInfos = network.SpaceInfos{
+		{ID: "1", Name: "internal"},
+	}
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {
+				ExposeToSpaces: []string{"internal"},
+				ExposeToCIDRs:  []string{"10.0.0.0/8"},
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "MergeExposeSettings")
+	app.CheckCall(c, 0, "MergeExposeSettings", map[string]state.ExposedEndpoint{
+		"db": {
+			ExposeToSpaceIDs: []string{"1"},
+			ExposeToCIDRs:    []string{"10.0.0.0/8"},
+		},
+	})
+}
+
+func (s *ApplicationSuite) TestExposeEndpointsUnknownSpace(c *gc.C) {
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {ExposeToSpaces: []string{"outer"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `space "outer" not found`)
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestCAASExposeEndpointsNotSupported(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	app := s.backend.applications["postgresql"]
+	app.config = coreapplication.ConfigAttributes{"juju-external-hostname": "exthost"}
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	app.CheckCallNames(c, "ApplicationConfig")
+}
+
+func (s *ApplicationSuite) TestPlacementPolicies(c *gc.C) {
+	s.backend.applications["postgresql"].placementPolicy = coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"ceph-osd"},
+		Strategy:     coreapplication.PlacementStrategySpread,
+	}
+	results, err := s.api.PlacementPolicies(params.Entities{Entities: []params.Entity{
+		{Tag: "application-postgresql"},
+		{Tag: "application-unknown"},
+		{Tag: "unit-postgresql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0], jc.DeepEquals, params.ApplicationPlacementPolicyResult{
+		Result: &params.ApplicationPlacementPolicy{
+			ApplicationTag: "application-postgresql",
+			AntiAffinity:   []string{"ceph-osd"},
+			Strategy:       "spread",
+		},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `application "unknown" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"unit-postgresql-0" is not a valid application tag`)
+}
+
+func (s *ApplicationSuite) TestSetPlacementPolicies(c *gc.C) {
+	results, err := s.api.SetPlacementPolicies(params.ApplicationPlacementPolicies{
+		Policies: []params.ApplicationPlacementPolicy{{
+			ApplicationTag: "application-postgresql",
+			AntiAffinity:   []string{"ceph-osd"},
+			Strategy:       "pack",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "SetPlacementPolicy")
+	app.CheckCall(c, 0, "SetPlacementPolicy", coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"ceph-osd"},
+		Strategy:     coreapplication.PlacementStrategyPack,
+	})
+}
+
+func (s *ApplicationSuite) TestCAASSetPlacementPoliciesNotSupported(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	results, err := s.api.SetPlacementPolicies(params.ApplicationPlacementPolicies{
+		Policies: []params.ApplicationPlacementPolicy{{
+			ApplicationTag: "application-postgresql",
+			Strategy:       "spread",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.Satisfies, params.IsCodeNotSupported)
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestApplicationsInfoOne(c *gc.C) {
 	entities := []params.Entity{{Tag: "application-postgresql"}}
 	result, err := s.api.ApplicationsInfo(params.Entities{entities})
@@ -75,6 +75,7 @@ type Application interface {
 	Charm() (Charm, bool, error)
 	CharmURL() (*charm.URL, bool)
 	Channel() csparams.Channel
+	CharmOrigin() *state.CharmOrigin
 	ClearExposed() error
 	CharmConfig(string) (charm.Settings, error)
 	Constraints() (constraints.Value, error)
@@ -98,6 +99,9 @@ type Application interface {
 	ChangeScale(int) (int, error)
 	AgentTools() (*tools.Tools, error)
 	MergeBindings(*state.Bindings, bool) error
+	MergeExposeSettings(map[string]state.ExposedEndpoint) error
+	PlacementPolicy() application.PlacementPolicy
+	SetPlacementPolicy(application.PlacementPolicy) error
 	Relations() ([]Relation, error)
 }
 
@@ -0,0 +1,38 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/environs/config"
+)
+
+var cloudInitFields = environschema.Fields{
+	application.CloudInitUserDataConfigOptionName: {
+		Description: "Cloud-init user data YAML merged with that of the model for machines hosting this application",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+var cloudInitDefaults = schema.Defaults{
+	application.CloudInitUserDataConfigOptionName: schema.Omit,
+}
+
+// validateCloudInitUserData returns an error if the cloud-init user data
+// in the application config attributes is not valid.
+func validateCloudInitUserData(attrs map[string]interface{}) error {
+	raw, ok := attrs[application.CloudInitUserDataConfigOptionName].(string)
+	if !ok || raw == "" {
+		return nil
+	}
+	if _, err := config.ParseCloudInitUserData(raw); err != nil {
+		return errors.Annotate(err, application.CloudInitUserDataConfigOptionName)
+	}
+	return nil
+}
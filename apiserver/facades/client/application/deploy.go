@@ -28,6 +28,7 @@ type DeployApplicationParams struct {
 	Series            string
 	Charm             *state.Charm
 	Channel           csparams.Channel
+	CharmOrigin       *state.CharmOrigin
 	ApplicationConfig *application.Config
 	CharmConfig       charm.Settings
 	Constraints       constraints.Value
@@ -73,6 +74,7 @@ func DeployApplication(st ApplicationDeployer, args DeployApplicationParams) (Ap
 		Series:            args.Series,
 		Charm:             args.Charm,
 		Channel:           args.Channel,
+		CharmOrigin:       args.CharmOrigin,
 		Storage:           stateStorageConstraints(args.Storage),
 		Devices:           stateDeviceConstraints(args.Devices),
 		AttachStorage:     args.AttachStorage,
@@ -115,6 +115,7 @@ func (api *APIBase) getConfig(
 		Constraints:       cons,
 		Series:            app.Series(),
 		Channel:           string(app.Channel()),
+		CharmOrigin:       charmOriginToParams(app.CharmOrigin()),
 		EndpointBindings:  bindingMap,
 	}, nil
 }
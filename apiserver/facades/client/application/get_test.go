@@ -29,7 +29,7 @@ import (
 type getSuite struct {
 	jujutesting.JujuConnSuite
 
-	applicationAPI *application.APIv12
+	applicationAPI *application.APIv15
 	authorizer     apiservertesting.FakeAuthorizer
 }
 
@@ -61,12 +61,12 @@ func (s *getSuite) SetUpTest(c *gc.C) {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.applicationAPI = &application.APIv12{api}
+	s.applicationAPI = &application.APIv15{api}
 }
 
 func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
 	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
-	v4 := &application.APIv4{&application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{s.applicationAPI}}}}}}}}
+	v4 := &application.APIv4{&application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{&application.APIv12{&application.APIv13{&application.APIv14{s.applicationAPI}}}}}}}}}}}
 	results, err := v4.Get(params.ApplicationGet{ApplicationName: "wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.ApplicationGetResults{
@@ -86,7 +86,7 @@ func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
 
 func (s *getSuite) TestClientApplicationGetSmokeTestV5(c *gc.C) {
 	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
-	v5 := &application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{s.applicationAPI}}}}}}}
+	v5 := &application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{&application.APIv12{&application.APIv13{&application.APIv14{s.applicationAPI}}}}}}}}}}
 	results, err := v5.Get(params.ApplicationGet{ApplicationName: "wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.ApplicationGetResults{
@@ -129,6 +129,18 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmokeTest(c *gc.C) {
 				"source":      "default",
 				"type":        environschema.Tbool,
 				"value":       false,
+			},
+			"cloudinit-userdata": map[string]interface{}{
+				"description": "Cloud-init user data YAML merged with that of the model for machines hosting this application",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"stateless": map[string]interface{}{
+				"default":     false,
+				"description": "Do units of this application keep no state on their machines, so that they are replaced on another machine when a machine pool loses theirs",
+				"source":      "default",
+				"type":        environschema.Tbool,
+				"value":       false,
 			}},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -215,7 +227,7 @@ func (s *getSuite) TestClientApplicationGetCAASModelSmokeTest(c *gc.C) {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	apiV8 := &application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{&application.APIv12{api}}}}}
+	apiV8 := &application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{&application.APIv12{&application.APIv13{&application.APIv14{&application.APIv15{api}}}}}}}}
 
 	results, err := apiV8.Get(params.ApplicationGet{ApplicationName: "dashboard4miner"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -299,6 +311,18 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"cloudinit-userdata": map[string]interface{}{
+				"description": "Cloud-init user data YAML merged with that of the model for machines hosting this application",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"stateless": map[string]interface{}{
+				"value":       false,
+				"default":     false,
+				"description": "Do units of this application keep no state on their machines, so that they are replaced on another machine when a machine pool loses theirs",
+				"source":      "default",
+				"type":        "bool",
+			},
 		},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -360,6 +384,18 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"cloudinit-userdata": map[string]interface{}{
+				"description": "Cloud-init user data YAML merged with that of the model for machines hosting this application",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"stateless": map[string]interface{}{
+				"value":       false,
+				"default":     false,
+				"description": "Do units of this application keep no state on their machines, so that they are replaced on another machine when a machine pool loses theirs",
+				"source":      "default",
+				"type":        "bool",
+			},
 		},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -380,6 +416,18 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"cloudinit-userdata": map[string]interface{}{
+				"description": "Cloud-init user data YAML merged with that of the model for machines hosting this application",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"stateless": map[string]interface{}{
+				"value":       false,
+				"default":     false,
+				"description": "Do units of this application keep no state on their machines, so that they are replaced on another machine when a machine pool loses theirs",
+				"source":      "default",
+				"type":        "bool",
+			},
 		},
 		EndpointBindings: map[string]string{
 			"":                  network.AlphaSpaceName,
@@ -101,9 +101,12 @@ type mockApplication struct {
 	config      coreapplication.ConfigAttributes
 	constraints constraints.Value
 	channel     csparams.Channel
+	charmOrigin *state.CharmOrigin
 	exposed     bool
 	remote      bool
 	agentTools  *tools.Tools
+
+	placementPolicy coreapplication.PlacementPolicy
 }
 
 func (m *mockApplication) Name() string {
@@ -116,6 +119,11 @@ func (m *mockApplication) Channel() csparams.Channel {
 	return m.channel
 }
 
+func (m *mockApplication) CharmOrigin() *state.CharmOrigin {
+	m.MethodCall(m, "CharmOrigin")
+	return m.charmOrigin
+}
+
 func (m *mockApplication) Charm() (application.Charm, bool, error) {
 	m.MethodCall(m, "Charm")
 	return m.charm, true, nil
@@ -239,6 +247,21 @@ func (a *mockApplication) SetExposed() error {
 	return a.NextErr()
 }
 
+func (a *mockApplication) MergeExposeSettings(exposedEndpoints map[string]state.ExposedEndpoint) error {
+	a.MethodCall(a, "MergeExposeSettings", exposedEndpoints)
+	return a.NextErr()
+}
+
+func (a *mockApplication) PlacementPolicy() coreapplication.PlacementPolicy {
+	a.MethodCall(a, "PlacementPolicy")
+	return a.placementPolicy
+}
+
+func (a *mockApplication) SetPlacementPolicy(policy coreapplication.PlacementPolicy) error {
+	a.MethodCall(a, "SetPlacementPolicy", policy)
+	return a.NextErr()
+}
+
 func (a *mockApplication) IsExposed() bool {
 	a.MethodCall(a, "IsExposed")
 	return a.exposed
@@ -372,6 +395,7 @@ type mockBackend struct {
 	controllers                map[string]crossmodel.ControllerInfo
 	machines                   map[string]*mockMachine
 	generation                 *mockGeneration
+	spaceInfos                 network.SpaceInfos
 }
 
 type mockFilesystemAccess struct {
@@ -449,7 +473,8 @@ func (m *mockBackend) Machine(id string) (application.Machine, error) {
 }
 
 func (m *mockBackend) AllSpaceInfos() (network.SpaceInfos, error) {
-	return nil, nil
+	m.MethodCall(m, "AllSpaceInfos")
+	return m.spaceInfos, m.NextErr()
 }
 
 func (m *mockBackend) Space(_ string) (*state.Space, error) {
@@ -557,6 +582,14 @@ func (mockPorts) OpenClosePortsOperation(unitName string, openPortRanges []netwo
 	panic("not implemented")
 }
 
+func (mockPorts) OpenClosePortsOnEndpointsOperation(unitName string, openPortRanges map[string][]network.PortRange, closePortRanges []network.PortRange) (state.ModelOperation, error) {
+	panic("not implemented")
+}
+
+func (mockPorts) PortRangeEndpointsForUnit(unitName string) map[network.PortRange]string {
+	panic("not implemented")
+}
+
 type mockMachine struct {
 	jtesting.Stub
 
@@ -0,0 +1,23 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/schema"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/core/application"
+)
+
+var statelessFields = environschema.Fields{
+	application.StatelessConfigOptionName: {
+		Description: "Do units of this application keep no state on their machines, so that they are replaced on another machine when a machine pool loses theirs",
+		Type:        environschema.Tbool,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+var statelessDefaults = schema.Defaults{
+	application.StatelessConfigOptionName: false,
+}
@@ -13,6 +13,7 @@ import (
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/state"
 )
@@ -20,7 +21,15 @@ import (
 type backend interface {
 	Charm(curl *charm.URL) (*state.Charm, error)
 	AllCharms() ([]*state.Charm, error)
+	PublishLocalCharm(curl *charm.URL, pendingIDs map[string]string) (*charm.URL, error)
+	ReleaseLocalCharm(curl *charm.URL, channel string) error
+	LocalCharmRelease(name, series, channel string) (*charm.URL, error)
+	AddCharmFromRepository(curl *charm.URL) (*state.Charm, error)
+	AddPendingResourcesFromRepository(curl *charm.URL, applicationID, userID string, exclude []string) (map[string]string, error)
+	LocalCharmReleased(curl *charm.URL) (bool, error)
+	RemoveRepositoryCharm(curl *charm.URL) error
 	ModelTag() names.ModelTag
+	ControllerTag() names.ControllerTag
 }
 
 // API implements the charms interface and is the concrete
@@ -30,6 +39,11 @@ type API struct {
 	backend    backend
 }
 
+// APIv2 provides the Charms API facade for version 2.
+type APIv2 struct {
+	*API
+}
+
 func (a *API) checkCanRead() error {
 	canRead, err := a.authorizer.HasPermission(permission.ReadAccess, a.backend.ModelTag())
 	if err != nil {
@@ -41,6 +55,38 @@ func (a *API) checkCanRead() error {
 	return nil
 }
 
+func (a *API) checkCanWrite() error {
+	canWrite, err := a.authorizer.HasPermission(permission.WriteAccess, a.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canWrite {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+func (a *API) checkIsControllerAdmin() error {
+	isAdmin, err := a.authorizer.HasPermission(permission.SuperuserAccess, a.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// NewFacadeV2 provides the signature required for facade registration
+// of version 2.
+func NewFacadeV2(ctx facade.Context) (*APIv2, error) {
+	api, err := NewFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv2{api}, nil
+}
+
 // NewFacade provides the signature required for facade registration.
 func NewFacade(ctx facade.Context) (*API, error) {
 	authorizer := ctx.Auth()
@@ -67,6 +113,12 @@ type stateShim struct {
 	*state.Model
 }
 
+// ControllerTag disambiguates the controller tag of the embedded
+// state and model.
+func (s stateShim) ControllerTag() names.ControllerTag {
+	return s.State.ControllerTag()
+}
+
 var getState = func(st *state.State, m *state.Model) backend {
 	return stateShim{st, m}
 }
@@ -152,6 +204,230 @@ func (a *API) IsMetered(args params.CharmURL) (params.IsMeteredResult, error) {
 	return params.IsMeteredResult{Metered: false}, nil
 }
 
+// PublishLocalCharms publishes local charms uploaded to the model, and
+// the pending resources uploaded for applications named after them, as
+// new revisions in the controller's charm repository. The results hold
+// the URLs of the published revisions. Publishing charms requires
+// controller superuser access, as the repository's storage is shared by
+// all of the controller's models.
+func (a *API) PublishLocalCharms(args params.PublishLocalCharmArgs) (params.StringResults, error) {
+	if err := a.checkIsControllerAdmin(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		curl, err := a.publishLocalCharm(arg)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = curl.String()
+	}
+	return results, nil
+}
+
+func (a *API) publishLocalCharm(arg params.PublishLocalCharmArg) (*charm.URL, error) {
+	curl, err := charm.ParseURL(arg.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	published, err := a.backend.PublishLocalCharm(curl, arg.Resources)
+	return published, errors.Trace(err)
+}
+
+// ReleaseLocalCharms releases local charms published to the controller's
+// charm repository to its channels, so that they can be deployed and
+// upgraded to by channel in any of the controller's models. Releasing
+// charms requires controller superuser access.
+func (a *API) ReleaseLocalCharms(args params.ReleaseLocalCharmArgs) (params.ErrorResults, error) {
+	if err := a.checkIsControllerAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		results.Results[i].Error = apiservererrors.ServerError(a.releaseLocalCharm(arg))
+	}
+	return results, nil
+}
+
+func (a *API) releaseLocalCharm(arg params.ReleaseLocalCharmArg) error {
+	curl, err := charm.ParseURL(arg.URL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	channel, err := charmhub.ParseChannel(arg.Channel)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(a.backend.ReleaseLocalCharm(curl, channel.String()))
+}
+
+// ResolveLocalCharms returns the local charms released to channels of the
+// controller's charm repository. Charms released to a more stable risk
+// of the channel's track are returned if nothing is released to it.
+func (a *API) ResolveLocalCharms(args params.ResolveLocalCharmArgs) (params.ResolveLocalCharmResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.ResolveLocalCharmResults{}, errors.Trace(err)
+	}
+	results := params.ResolveLocalCharmResults{
+		Results: make([]params.ResolveLocalCharmResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		curl, channel, err := a.resolveLocalCharm(arg)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].URL = curl.String()
+		results.Results[i].Channel = channel
+	}
+	return results, nil
+}
+
+func (a *API) resolveLocalCharm(arg params.ResolveLocalCharmArg) (*charm.URL, string, error) {
+	channel, err := charmhub.ParseChannel(arg.Channel)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	for _, ch := range channel.Fallbacks() {
+		curl, err := a.backend.LocalCharmRelease(arg.Name, arg.Series, ch.String())
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		return curl, ch.String(), nil
+	}
+	return nil, "", errors.NotFoundf("local charm %q for series %q released to channel %q", arg.Name, arg.Series, channel)
+}
+
+// AddCharmsFromRepository adds local charms published to the controller's
+// charm repository to the model, so that applications can be deployed
+// and upgraded with them. Only revisions released to a channel can be
+// added by model writers; controller superusers can add any revision.
+func (a *API) AddCharmsFromRepository(args params.CharmURLs) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.URLs)),
+	}
+	for i, arg := range args.URLs {
+		results.Results[i].Error = apiservererrors.ServerError(a.addCharmFromRepository(arg))
+	}
+	return results, nil
+}
+
+func (a *API) addCharmFromRepository(arg params.CharmURL) error {
+	curl, err := charm.ParseURL(arg.URL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := a.checkCanUseRepositoryCharm(curl); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = a.backend.AddCharmFromRepository(curl)
+	return errors.Trace(err)
+}
+
+// checkCanUseRepositoryCharm returns an error if the revision of a charm
+// in the controller's charm repository is neither released to a channel
+// nor used by a controller superuser. Unreleased revisions may still be
+// under test by whoever published them.
+func (a *API) checkCanUseRepositoryCharm(curl *charm.URL) error {
+	released, err := a.backend.LocalCharmReleased(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if released {
+		return nil
+	}
+	return errors.Trace(a.checkIsControllerAdmin())
+}
+
+// AddRepositoryResources adds the resources stored with local charms in
+// the controller's charm repository as pending resources of
+// applications, and returns their pending IDs. As with
+// AddCharmsFromRepository, only the resources of released revisions can
+// be added by model writers.
+func (a *API) AddRepositoryResources(args params.AddRepositoryResourcesArgs) (params.AddRepositoryResourcesResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.AddRepositoryResourcesResults{}, errors.Trace(err)
+	}
+	results := params.AddRepositoryResourcesResults{
+		Results: make([]params.AddRepositoryResourcesResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		pendingIDs, err := a.addRepositoryResources(arg)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].PendingIDs = pendingIDs
+	}
+	return results, nil
+}
+
+func (a *API) addRepositoryResources(arg params.AddRepositoryResourcesArg) (map[string]string, error) {
+	curl, err := charm.ParseURL(arg.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !names.IsValidApplication(arg.ApplicationName) {
+		return nil, errors.NotValidf("application name %q", arg.ApplicationName)
+	}
+	if err := a.checkCanUseRepositoryCharm(curl); err != nil {
+		return nil, errors.Trace(err)
+	}
+	pendingIDs, err := a.backend.AddPendingResourcesFromRepository(
+		curl, arg.ApplicationName, a.authorizer.GetAuthTag().Id(), arg.Exclude,
+	)
+	return pendingIDs, errors.Trace(err)
+}
+
+// RemoveLocalCharms removes revisions of local charms, and the resources
+// stored with them, from the controller's charm repository. Revisions
+// released to a channel can't be removed. Removing charms requires
+// controller superuser access.
+func (a *API) RemoveLocalCharms(args params.CharmURLs) (params.ErrorResults, error) {
+	if err := a.checkIsControllerAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.URLs)),
+	}
+	for i, arg := range args.URLs {
+		curl, err := charm.ParseURL(arg.URL)
+		if err == nil {
+			err = a.backend.RemoveRepositoryCharm(curl)
+		}
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+// PublishLocalCharms isn't on the v2 API.
+func (*APIv2) PublishLocalCharms(_, _ struct{}) {}
+
+// ReleaseLocalCharms isn't on the v2 API.
+func (*APIv2) ReleaseLocalCharms(_, _ struct{}) {}
+
+// ResolveLocalCharms isn't on the v2 API.
+func (*APIv2) ResolveLocalCharms(_, _ struct{}) {}
+
+// AddCharmsFromRepository isn't on the v2 API.
+func (*APIv2) AddCharmsFromRepository(_, _ struct{}) {}
+
+// AddRepositoryResources isn't on the v2 API.
+func (*APIv2) AddRepositoryResources(_, _ struct{}) {}
+
+// RemoveLocalCharms isn't on the v2 API.
+func (*APIv2) RemoveLocalCharms(_, _ struct{}) {}
+
 func convertCharmMeta(meta *charm.Meta) *params.CharmMeta {
 	if meta == nil {
 		return nil
@@ -4,6 +4,13 @@
 package charms_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -18,6 +25,8 @@ import (
 	"github.com/juju/juju/core/multiwatcher"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -59,6 +68,7 @@ func (s *charmsSuite) SetUpTest(c *gc.C) {
 
 	s.auth = testing.FakeAuthorizer{
 		Tag:        s.AdminUserTag(c),
+		AdminTag:   s.AdminUserTag(c),
 		Controller: true,
 	}
 
@@ -362,3 +372,199 @@ func (s *charmsSuite) TestIsMeteredTrue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(metered.Metered, jc.IsTrue)
 }
+
+// uploadLocalCharm adds the dummy charm to the model with its archive in
+// the model's storage, as uploading a local charm does.
+func (s *charmsSuite) uploadLocalCharm(c *gc.C, url string) *state.Charm {
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	data, err := ioutil.ReadFile(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL(url)
+	storagePath := "charms/" + curl.String()
+	stor := storage.NewStorage(s.State.ModelUUID(), s.State.MongoSession())
+	err = stor.Put(storagePath, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := s.State.AddCharm(state.CharmInfo{
+		Charm:       archive,
+		ID:          curl,
+		StoragePath: storagePath,
+		SHA256:      fmt.Sprintf("%x", sha256.Sum256(data)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *charmsSuite) TestPublishReleaseAndResolveLocalCharms(c *gc.C) {
+	ch := s.uploadLocalCharm(c, "local:quantal/dummy-1")
+	published, err := s.api.PublishLocalCharms(params.PublishLocalCharmArgs{
+		Args: []params.PublishLocalCharmArg{
+			{URL: ch.URL().String()},
+			{URL: "local:quantal/missing-1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(published.Results, gc.HasLen, 2)
+	c.Check(published.Results[0], jc.DeepEquals, params.StringResult{Result: "local:quantal/dummy-1"})
+	c.Check(published.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	released, err := s.api.ReleaseLocalCharms(params.ReleaseLocalCharmArgs{
+		Args: []params.ReleaseLocalCharmArg{
+			{URL: ch.URL().String(), Channel: "stable"},
+			{URL: "local:quantal/missing-1", Channel: "stable"},
+			{URL: ch.URL().String(), Channel: "latest/bogus"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released.Results, gc.HasLen, 3)
+	c.Check(released.Results[0].Error, gc.IsNil)
+	c.Check(released.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Check(released.Results[2].Error, gc.NotNil)
+
+	// Released charms stay in the repository when the model's copy
+	// of the charm is removed.
+	err = ch.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = ch.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resolved, err := s.api.ResolveLocalCharms(params.ResolveLocalCharmArgs{
+		Args: []params.ResolveLocalCharmArg{
+			{Name: "dummy", Series: "quantal", Channel: "stable"},
+			{Name: "dummy", Series: "quantal", Channel: "candidate"},
+			{Name: "dummy", Series: "quantal", Channel: "2.0/stable"},
+			{Name: "dummy", Series: "bionic", Channel: "stable"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resolved.Results, jc.DeepEquals, []params.ResolveLocalCharmResult{{
+		URL:     "local:quantal/dummy-1",
+		Channel: "latest/stable",
+	}, {
+		URL:     "local:quantal/dummy-1",
+		Channel: "latest/stable",
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: `local charm "dummy" for series "quantal" released to channel "2.0/stable" not found`,
+		},
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: `local charm "dummy" for series "bionic" released to channel "latest/stable" not found`,
+		},
+	}})
+
+	added, err := s.api.AddCharmsFromRepository(params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}, {URL: "local:quantal/missing-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added.Results, gc.HasLen, 2)
+	c.Check(added.Results[0].Error, gc.IsNil)
+	c.Check(added.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	_, err = s.State.Charm(ch.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	resources, err := s.api.AddRepositoryResources(params.AddRepositoryResourcesArgs{
+		Args: []params.AddRepositoryResourcesArg{
+			{URL: "local:quantal/dummy-1", ApplicationName: "dummy"},
+			{URL: "local:quantal/dummy-1", ApplicationName: "#bad"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources.Results, gc.HasLen, 2)
+	c.Check(resources.Results[0], jc.DeepEquals, params.AddRepositoryResourcesResult{PendingIDs: map[string]string{}})
+	c.Check(resources.Results[1].Error, gc.ErrorMatches, `application name "#bad" not valid`)
+}
+
+func (s *charmsSuite) TestPublishLocalCharmsRequiresWrite(c *gc.C) {
+	s.auth = testing.FakeAuthorizer{Tag: names.NewUserTag("read")}
+	api, err := charms.NewFacade(&charmsSuiteContext{cs: s})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.PublishLocalCharms(params.PublishLocalCharmArgs{
+		Args: []params.PublishLocalCharmArg{{URL: "local:quantal/dummy-1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.AddCharmsFromRepository(params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *charmsSuite) TestReleaseLocalCharmsRequiresSuperuser(c *gc.C) {
+	s.auth = testing.FakeAuthorizer{Tag: names.NewUserTag("write")}
+	api, err := charms.NewFacade(&charmsSuiteContext{cs: s})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.PublishLocalCharms(params.PublishLocalCharmArgs{
+		Args: []params.PublishLocalCharmArg{{URL: "local:quantal/dummy-1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.ReleaseLocalCharms(params.ReleaseLocalCharmArgs{
+		Args: []params.ReleaseLocalCharmArg{{URL: "local:quantal/dummy-1", Channel: "stable"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = api.RemoveLocalCharms(params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *charmsSuite) TestAddCharmsFromRepositoryUnreleased(c *gc.C) {
+	ch := s.uploadLocalCharm(c, "local:quantal/dummy-1")
+	_, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	otherCh := s.uploadLocalCharm(c, "local:quantal/other-1")
+	_, err = s.State.PublishLocalCharm(otherCh.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(otherCh.URL(), "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.auth = testing.FakeAuthorizer{Tag: names.NewUserTag("write")}
+	api, err := charms.NewFacade(&charmsSuiteContext{cs: s})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Model writers can only use released revisions.
+	added, err := api.AddCharmsFromRepository(params.CharmURLs{
+		URLs: []params.CharmURL{{URL: "local:quantal/dummy-1"}, {URL: "local:quantal/other-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added.Results, gc.HasLen, 2)
+	c.Check(added.Results[0].Error, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Check(added.Results[1].Error, gc.IsNil)
+
+	resources, err := api.AddRepositoryResources(params.AddRepositoryResourcesArgs{
+		Args: []params.AddRepositoryResourcesArg{{URL: "local:quantal/dummy-1", ApplicationName: "dummy"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources.Results, gc.HasLen, 1)
+	c.Check(resources.Results[0].Error, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *charmsSuite) TestRemoveLocalCharms(c *gc.C) {
+	ch := s.uploadLocalCharm(c, "local:quantal/dummy-1")
+	_, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	otherCh := s.uploadLocalCharm(c, "local:quantal/other-1")
+	_, err = s.State.PublishLocalCharm(otherCh.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(otherCh.URL(), "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	removed, err := s.api.RemoveLocalCharms(params.CharmURLs{
+		URLs: []params.CharmURL{
+			{URL: "local:quantal/dummy-1"},
+			{URL: "local:quantal/other-1"},
+			{URL: "local:quantal/missing-1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 3)
+	c.Check(removed.Results[0].Error, gc.IsNil)
+	c.Check(removed.Results[1].Error, gc.ErrorMatches, `removing charm "local:quantal/other-1": charm is released to latest/stable`)
+	c.Check(removed.Results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	released, err := s.State.LocalCharmReleased(otherCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(released, jc.IsTrue)
+}
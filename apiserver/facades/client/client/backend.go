@@ -48,6 +48,7 @@ type Backend interface {
 	APIHostPortsForClients() ([]network.SpaceHostPorts, error)
 	Application(string) (*state.Application, error)
 	Charm(*charm.URL) (*state.Charm, error)
+	CheckModelAgentVersion(version.Number, bool) error
 	ControllerConfig() (controller.Config, error)
 	ControllerNodes() ([]state.ControllerNode, error)
 	ControllerTag() names.ControllerTag
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/os"
 	"github.com/juju/os/series"
 	"github.com/juju/replicaset"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/apiserver/common"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
@@ -79,9 +80,19 @@ type Client struct {
 	openCSRepo  application.OpenCSRepoFunc
 }
 
+// ClientV3 serves the (v3) client-specific API methods.
+type ClientV3 struct {
+	*Client
+}
+
+// ClientV2 serves the (v2) client-specific API methods.
+type ClientV2 struct {
+	*ClientV3
+}
+
 // ClientV1 serves the (v1) client-specific API methods.
 type ClientV1 struct {
-	*Client
+	*ClientV2
 }
 
 func (c *Client) checkCanRead() error {
@@ -132,14 +143,32 @@ func (c *Client) checkIsAdmin() error {
 	return nil
 }
 
-// NewFacade creates a version 1 Client facade to handle API requests.
+// NewFacade creates a version 4 Client facade to handle API requests.
 func NewFacade(ctx facade.Context) (*Client, error) {
 	return newFacade(ctx)
 }
 
+// NewFacadeV3 creates a version 3 Client facade to handle API requests.
+func NewFacadeV3(ctx facade.Context) (*ClientV3, error) {
+	client, err := newFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ClientV3{client}, nil
+}
+
+// NewFacadeV2 creates a version 2 Client facade to handle API requests.
+func NewFacadeV2(ctx facade.Context) (*ClientV2, error) {
+	client, err := NewFacadeV3(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ClientV2{client}, nil
+}
+
 // NewFacadeV1 creates a version 1 Client facade to handle API requests.
 func NewFacadeV1(ctx facade.Context) (*ClientV1, error) {
-	client, err := newFacade(ctx)
+	client, err := NewFacadeV2(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -674,8 +703,86 @@ func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	// Before changing the agent version to trigger an upgrade or downgrade,
-	// we'll do a very basic check to ensure the environment is accessible.
+	for _, check := range c.upgradePrechecks() {
+		if err := check.run(); err != nil {
+			return err
+		}
+	}
+	return c.api.stateAccessor.SetModelAgentVersion(args.Version, args.IgnoreAgentVersions)
+}
+
+// StageAgentVersion stages an upgrade of the given machines to a later
+// agent version than the rest of the model. The machine agents, and the
+// unit agents on those machines, upgrade to the staged version while
+// all other agents stay on the model agent version. Setting the model
+// agent version to the staged version, or later, completes the upgrade.
+func (c *Client) StageAgentVersion(args params.StageAgentVersion) (params.ErrorResults, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for _, check := range c.upgradePrechecks() {
+		if err := check.run(); err != nil {
+			return params.ErrorResults{}, err
+		}
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	for i, arg := range args.Machines {
+		results.Results[i].Error = apiservererrors.ServerError(c.stageAgentVersion(arg.Tag, args.Version))
+	}
+	return results, nil
+}
+
+func (c *Client) stageAgentVersion(tagString string, vers version.Number) error {
+	tag, err := names.ParseMachineTag(tagString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := c.api.stateAccessor.Machine(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return machine.SetTargetAgentVersion(vers)
+}
+
+// upgradePrecheck is a named check which must pass before the model
+// agent version is changed.
+type upgradePrecheck struct {
+	name string
+	run  func() error
+}
+
+// upgradePrechecks returns the checks, in order, which are run before
+// the model agent version is changed.
+func (c *Client) upgradePrechecks() []upgradePrecheck {
+	checks := []upgradePrecheck{{
+		name: "provider-api",
+		run:  c.checkProviderAPI,
+	}}
+	// If this is the controller model, also check to make sure that the
+	// replicaset is happy and that there are no running migrations.
+	if c.api.stateAccessor.IsController() {
+		checks = append(checks, upgradePrecheck{
+			name: "replicaset",
+			run: func() error {
+				return errors.Trace(c.CheckMongoStatusForUpgrade(c.api.stateAccessor.MongoSession()))
+			},
+		}, upgradePrecheck{
+			name: "migrations",
+			run:  c.checkNoMigrations,
+		})
+	}
+	return checks
+}
+
+// checkProviderAPI does a very basic check to ensure the environment
+// is accessible.
+func (c *Client) checkProviderAPI() error {
 	envOrBroker, err := c.newEnviron()
 	if err != nil {
 		return errors.Trace(err)
@@ -692,33 +799,28 @@ func (c *Client) SetModelAgentVersion(args params.SetModelAgentVersion) error {
 			return errors.Annotate(err, "cannot make API call to provider")
 		}
 	}
-	// If this is the controller model, also check to make sure that there are
-	// no running migrations.  All models should have migration mode of None.
-	if c.api.stateAccessor.IsController() {
-		// Check to ensure that the replicaset is happy.
-		if err := c.CheckMongoStatusForUpgrade(c.api.stateAccessor.MongoSession()); err != nil {
-			return errors.Trace(err)
-		}
+	return nil
+}
 
-		modelUUIDs, err := c.api.stateAccessor.AllModelUUIDs()
+// checkNoMigrations returns an error if any model hosted by the
+// controller has a migration mode other than None.
+func (c *Client) checkNoMigrations() error {
+	modelUUIDs, err := c.api.stateAccessor.AllModelUUIDs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, modelUUID := range modelUUIDs {
+		model, release, err := c.api.pool.GetModel(modelUUID)
 		if err != nil {
 			return errors.Trace(err)
 		}
-
-		for _, modelUUID := range modelUUIDs {
-			model, release, err := c.api.pool.GetModel(modelUUID)
-			if err != nil {
-				return errors.Trace(err)
-			}
-			if mode := model.MigrationMode(); mode != state.MigrationModeNone {
-				release()
-				return errors.Errorf("model \"%s/%s\" is %s, upgrade blocked", model.Owner().Name(), model.Name(), mode)
-			}
+		if mode := model.MigrationMode(); mode != state.MigrationModeNone {
 			release()
+			return errors.Errorf("model \"%s/%s\" is %s, upgrade blocked", model.Owner().Name(), model.Name(), mode)
 		}
+		release()
 	}
-
-	return c.api.stateAccessor.SetModelAgentVersion(args.Version, args.IgnoreAgentVersions)
+	return nil
 }
 
 // CheckMongoStatusForUpgrade returns an error if the replicaset is not in a good
@@ -876,6 +978,12 @@ func (c *ClientV1) FindTools(args params.FindToolsParams) (params.FindToolsResul
 	return c.api.toolsFinder.FindTools(args)
 }
 
+// UpgradePreview isn't on the V2 API.
+func (c *ClientV2) UpgradePreview(_, _ struct{}) {}
+
+// StageAgentVersion isn't on the V3 API.
+func (c *ClientV3) StageAgentVersion(_, _ struct{}) {}
+
 // NOTE: this is necessary for the other packages that do upgrade tests.
 // Really they should be using a mocked out api server, but that is outside
 // the scope of this fix.
@@ -49,6 +49,7 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
+	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	jujuversion "github.com/juju/juju/version"
@@ -414,6 +415,142 @@ func (s *serverSuite) TestUserModelSetModelAgentVersionSkipsMongoCheck(c *gc.C)
 	s.assertModelVersion(c, otherSt, "2.0.4")
 }
 
+func (s *serverSuite) TestUpgradePreview(c *gc.C) {
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	currentVersion, ok := cfg.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+
+	machine, err := s.State.AddMachine("series", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.MustParseBinary(currentVersion.String() + "-quantal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	app, err := s.State.AddApplication(state.AddApplicationArgs{Name: "wordpress", Charm: s.AddTestingCharm(c, "wordpress")})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentVersion(version.MustParseBinary("1.0.2-quantal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	preview, err := s.client.UpgradePreview(params.SetModelAgentVersion{
+		Version: version.MustParse("9.8.7"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(preview.CurrentVersion, gc.Equals, currentVersion)
+	c.Check(preview.TargetVersion, gc.Equals, version.MustParse("9.8.7"))
+	c.Check(preview.KnownVersion, gc.Equals, jujuversion.Current)
+
+	c.Assert(preview.Prechecks, gc.HasLen, 4)
+	for i, name := range []string{"provider-api", "replicaset", "migrations"} {
+		c.Check(preview.Prechecks[i].Name, gc.Equals, name)
+		c.Check(preview.Prechecks[i].Error, gc.IsNil)
+	}
+	c.Check(preview.Prechecks[3].Name, gc.Equals, "agent-versions")
+	c.Check(preview.Prechecks[3].Error, gc.ErrorMatches, "some agents have not upgraded to the current model version .*: unit-wordpress-0")
+
+	c.Assert(preview.Agents, gc.HasLen, 2)
+	c.Check(preview.Agents[0].Tag, gc.Equals, "machine-0")
+	c.Check(preview.Agents[0].Version, gc.Equals, currentVersion)
+	c.Check(preview.Agents[1].Tag, gc.Equals, "unit-wordpress-0")
+	c.Check(preview.Agents[1].Version, gc.Equals, version.MustParse("1.0.2"))
+
+	// Nothing is changed.
+	s.assertModelVersion(c, s.State, currentVersion.String())
+}
+
+func (s *serverSuite) TestUpgradePreviewReportsFailedPrechecks(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "some-user"})
+	s.makeMigratingModel(c, "to-migrate", state.MigrationModeImporting)
+	session := &fakeSession{
+		err: errors.New("boom"),
+	}
+	client.OverrideClientBackendMongoSession(s.client, session)
+
+	preview, err := s.client.UpgradePreview(params.SetModelAgentVersion{
+		Version: version.MustParse("9.8.7"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(preview.Prechecks, gc.HasLen, 4)
+	c.Check(preview.Prechecks[0].Error, gc.IsNil)
+	c.Check(preview.Prechecks[1].Error, gc.ErrorMatches, "checking replicaset status: boom")
+	c.Check(preview.Prechecks[2].Error, gc.ErrorMatches, `model "some-user/to-migrate" is importing, upgrade blocked`)
+	c.Check(preview.Prechecks[3].Error, gc.IsNil)
+}
+
+func (s *serverSuite) TestUserModelUpgradePreviewSkipsControllerPrechecks(c *gc.C) {
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	apiserverClient := s.clientForState(c, otherSt)
+	s.newEnviron = func() (environs.BootstrapEnviron, error) {
+		return &mockEnviron{}, nil
+	}
+
+	preview, err := apiserverClient.UpgradePreview(params.SetModelAgentVersion{
+		Version: version.MustParse("2.0.4"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(preview.Prechecks, gc.HasLen, 2)
+	c.Check(preview.Prechecks[0].Name, gc.Equals, "provider-api")
+	c.Check(preview.Prechecks[1].Name, gc.Equals, "agent-versions")
+	c.Check(preview.Agents, gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestUserModelStageAgentVersion(c *gc.C) {
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	older := jujuversion.Current
+	older.Minor--
+	err := statetesting.SetAgentVersion(otherSt, older)
+	c.Assert(err, jc.ErrorIsNil)
+	staged, err := otherSt.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := otherSt.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	apiserverClient := s.clientForState(c, otherSt)
+	s.newEnviron = func() (environs.BootstrapEnviron, error) {
+		return &mockEnviron{}, nil
+	}
+	results, err := apiserverClient.StageAgentVersion(params.StageAgentVersion{
+		Version: jujuversion.Current,
+		Machines: []params.Entity{
+			{Tag: staged.Tag().String()},
+			{Tag: "machine-42"},
+			{Tag: "unit-wordpress-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `"unit-wordpress-0" is not a valid machine tag`)
+
+	err = staged.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	target, ok := staged.TargetAgentVersion()
+	c.Check(ok, jc.IsTrue)
+	c.Check(target, gc.Equals, jujuversion.Current)
+	err = other.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = other.TargetAgentVersion()
+	c.Check(ok, jc.IsFalse)
+
+	// The model agent version is unchanged.
+	s.assertModelVersion(c, otherSt, older.String())
+}
+
+func (s *serverSuite) TestStageAgentVersionRunsPrechecks(c *gc.C) {
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "some-user"})
+	s.makeMigratingModel(c, "to-migrate", state.MigrationModeExporting)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.StageAgentVersion(params.StageAgentVersion{
+		Version:  version.MustParse("9.8.7"),
+		Machines: []params.Entity{{Tag: machine.Tag().String()}},
+	})
+	c.Assert(err, gc.ErrorMatches, `model "some-user/to-migrate" is exporting, upgrade blocked`)
+}
+
 type mockEnviron struct {
 	environs.Environ
 	allInstancesCalled bool
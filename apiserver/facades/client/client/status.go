@@ -13,6 +13,7 @@ import (
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/apiserver/common"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
@@ -186,6 +187,7 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		return noStatus, errors.Annotate(err, "cannot obtain current model config")
 	}
 	context.providerType = cfg.Type()
+	context.agentVersion, _ = cfg.AgentVersion()
 
 	if context.model, err = c.api.stateAccessor.Model(); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch model")
@@ -507,6 +509,7 @@ type applicationStatusInfo struct {
 
 type statusContext struct {
 	providerType string
+	agentVersion version.Number
 	cachedModel  *cache.Model
 	model        *state.Model
 	status       *state.ModelStatus
@@ -972,6 +975,9 @@ func (c *statusContext) makeMachineStatus(machine *state.Machine, appStatusInfo
 			status.PrimaryControllerMachine = &isPrimary
 		}
 	}
+	if target, ok := machine.TargetAgentVersion(); ok && target.Compare(c.agentVersion) > 0 {
+		status.TargetAgentVersion = target.String()
+	}
 
 	// Fetch the machine instance status information
 	sInstInfo, err := c.status.MachineInstance(machineID)
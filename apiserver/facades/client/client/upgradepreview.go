@@ -0,0 +1,146 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/version"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+// UpgradePreview reports what changing the model agent version would
+// do, without changing anything. Every precheck run by
+// SetModelAgentVersion is run and its result reported, along with the
+// upgrade steps that each agent in the model would run.
+func (c *Client) UpgradePreview(args params.SetModelAgentVersion) (params.UpgradePreview, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.UpgradePreview{}, err
+	}
+
+	cfg, err := c.api.stateAccessor.ModelConfig()
+	if err != nil {
+		return params.UpgradePreview{}, errors.Trace(err)
+	}
+	currentVersion, _ := cfg.AgentVersion()
+	result := params.UpgradePreview{
+		CurrentVersion: currentVersion,
+		TargetVersion:  args.Version,
+		KnownVersion:   jujuversion.Current,
+	}
+
+	checks := append(c.upgradePrechecks(), upgradePrecheck{
+		name: "agent-versions",
+		run: func() error {
+			return c.api.stateAccessor.CheckModelAgentVersion(args.Version, args.IgnoreAgentVersions)
+		},
+	})
+	for _, check := range checks {
+		result.Prechecks = append(result.Prechecks, params.UpgradePrecheck{
+			Name:  check.name,
+			Error: apiservererrors.ServerError(check.run()),
+		})
+	}
+
+	result.Agents, err = c.agentUpgradePreviews(currentVersion, args.Version)
+	if err != nil {
+		return params.UpgradePreview{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// agentUpgradePreviews returns the upgrade steps that each machine and
+// unit agent in the model would run when upgrading to the target
+// version. Agents which have not yet reported a version are assumed to
+// be running the current model agent version.
+func (c *Client) agentUpgradePreviews(current, target version.Number) ([]params.AgentUpgradePreview, error) {
+	var primary names.MachineTag
+	if c.api.stateAccessor.IsController() {
+		var err error
+		primary, err = c.api.stateAccessor.HAPrimaryMachine()
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Debugf("cannot determine HA primary machine: %v", err)
+		}
+	}
+
+	var previews []params.AgentUpgradePreview
+	machines, err := c.api.stateAccessor.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, m := range machines {
+		from, err := agentVersion(m, current)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		targets := []upgrades.Target{upgrades.HostMachine}
+		if m.IsManager() {
+			targets = append(targets, upgrades.Controller)
+			if m.Tag() == primary {
+				targets = append(targets, upgrades.DatabaseMaster)
+			}
+		}
+		previews = append(previews, agentUpgradePreview(m.Tag(), from, target, targets))
+	}
+
+	applications, err := c.api.stateAccessor.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, app := range applications {
+		units, err := app.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, u := range units {
+			from, err := agentVersion(u, current)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			previews = append(previews, agentUpgradePreview(u.Tag(), from, target, []upgrades.Target{upgrades.HostMachine}))
+		}
+	}
+	return previews, nil
+}
+
+type agentToolsGetter interface {
+	AgentTools() (*coretools.Tools, error)
+}
+
+// agentVersion returns the version the agent is running, or the
+// fallback version if the agent has not reported one.
+func agentVersion(agent agentToolsGetter, fallback version.Number) (version.Number, error) {
+	tools, err := agent.AgentTools()
+	if errors.IsNotFound(err) {
+		return fallback, nil
+	} else if err != nil {
+		return version.Number{}, errors.Trace(err)
+	}
+	return tools.Version.Number, nil
+}
+
+func agentUpgradePreview(tag names.Tag, from, to version.Number, targets []upgrades.Target) params.AgentUpgradePreview {
+	preview := params.AgentUpgradePreview{
+		Tag:     tag.String(),
+		Version: from,
+	}
+	for _, step := range upgrades.PreviewUpgrade(from, to, targets) {
+		stepTargets := make([]string, len(step.Targets))
+		for i, t := range step.Targets {
+			stepTargets[i] = string(t)
+		}
+		preview.Steps = append(preview.Steps, params.UpgradeStepPreview{
+			Version:     step.Version,
+			Description: step.Description,
+			Targets:     stepTargets,
+			State:       step.State,
+		})
+	}
+	return preview
+}
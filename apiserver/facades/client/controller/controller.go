@@ -57,10 +57,17 @@ type ControllerAPI struct {
 	multiwatcherFactory multiwatcher.Factory
 }
 
+// ControllerAPIv9 provides the v9 Controller API. The only difference
+// between this and v10 is that v9 doesn't have the PrecheckMigration,
+// bulk migration and offline migration methods.
+type ControllerAPIv9 struct {
+	*ControllerAPI
+}
+
 // ControllerAPIv8 provides the v8 Controller API. The only difference
 // between this and v9 is that v8 doesn't have the model summary watchers.
 type ControllerAPIv8 struct {
-	*ControllerAPI
+	*ControllerAPIv9
 }
 
 // ControllerAPIv7 provides the v7 Controller API. The only difference
@@ -95,10 +102,10 @@ type ControllerAPIv3 struct {
 
 // LatestAPI is used for testing purposes to create the latest
 // controller API.
-var LatestAPI = NewControllerAPIv9
+var LatestAPI = NewControllerAPIv10
 
-// NewControllerAPIv9 creates a new ControllerAPIv9.
-func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv10 creates a new ControllerAPIv10.
+func NewControllerAPIv10(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -120,6 +127,15 @@ func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv9 creates a new ControllerAPIv9.
+func NewControllerAPIv9(ctx facade.Context) (*ControllerAPIv9, error) {
+	v10, err := NewControllerAPIv10(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv9{v10}, nil
+}
+
 // NewControllerAPIv8 creates a new ControllerAPIv8.
 func NewControllerAPIv8(ctx facade.Context) (*ControllerAPIv8, error) {
 	v9, err := NewControllerAPIv9(ctx)
@@ -601,41 +617,148 @@ func (c *ControllerAPI) InitiateMigration(reqArgs params.InitiateMigrationArgs)
 }
 
 func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string, error) {
+	if spec.Offline {
+		return c.initiateOfflineMigration(spec)
+	}
+	hostedState, targetInfo, err := c.migrationSpecState(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer hostedState.Release()
+
+	// Check if the migration is likely to succeed.
+	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// Trigger the migration.
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		TargetInfo:  targetInfo,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return mig.Id(), nil
+}
+
+// initiateOfflineMigration starts the migration of a model which is to
+// be exported to a model archive. Only the source prechecks are run; the
+// target controller runs its own when the archive is imported. The model
+// is locked against changes until the migration ends.
+func (c *ControllerAPI) initiateOfflineMigration(spec params.MigrationSpec) (string, error) {
 	modelTag, err := names.ParseModelTag(spec.ModelTag)
 	if err != nil {
 		return "", errors.Annotate(err, "model tag")
 	}
+	hostedState, err := c.statePool.Get(modelTag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer hostedState.Release()
+
+	if err := runSourcePrechecks(hostedState.State, c.statePool.SystemState(), c.presence); err != nil {
+		return "", errors.Trace(err)
+	}
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		Offline:     true,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return mig.Id(), nil
+}
+
+// PrecheckMigration runs all of the migration prechecks for one or more
+// models on the source and target controllers, without starting the
+// migrations. Every failed check is reported, rather than just the first.
+func (c *ControllerAPI) PrecheckMigration(reqArgs params.InitiateMigrationArgs) (
+	params.MigrationPrecheckReports, error,
+) {
+	out := params.MigrationPrecheckReports{
+		Results: make([]params.MigrationPrecheckReport, len(reqArgs.Specs)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, spec := range reqArgs.Specs {
+		result := &out.Results[i]
+		result.ModelTag = spec.ModelTag
+		source, target, err := c.precheckOneMigration(spec)
+		if err != nil {
+			result.Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Source = precheckFailuresToParams(source)
+		result.Target = precheckFailuresToParams(target)
+	}
+	return out, nil
+}
+
+// PrecheckMigration isn't on the v9 API.
+func (c *ControllerAPIv9) PrecheckMigration(_, _ struct{}) {}
+
+func (c *ControllerAPI) precheckOneMigration(spec params.MigrationSpec) (
+	[]coremigration.PrecheckFailure, []coremigration.PrecheckFailure, error,
+) {
+	hostedState, targetInfo, err := c.migrationSpecState(spec)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer hostedState.Release()
+	return runMigrationPrecheckReport(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence)
+}
+
+// migrationSpecState returns the state of the model to be migrated and
+// the details of the target controller given in the migration spec. The
+// caller is responsible for releasing the returned state.
+func (c *ControllerAPI) migrationSpecState(spec params.MigrationSpec) (
+	*state.PooledState, coremigration.TargetInfo, error,
+) {
+	var empty coremigration.TargetInfo
+	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	if err != nil {
+		return nil, empty, errors.Annotate(err, "model tag")
+	}
 
 	// Ensure the model exists.
 	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
-		return "", errors.Annotate(err, "reading model")
+		return nil, empty, errors.Annotate(err, "reading model")
 	} else if !modelExists {
-		return "", errors.NotFoundf("model")
+		return nil, empty, errors.NotFoundf("model")
+	}
+
+	targetInfo, err := targetInfoFromParams(spec.TargetInfo)
+	if err != nil {
+		return nil, empty, errors.Trace(err)
 	}
 
 	hostedState, err := c.statePool.Get(modelTag.Id())
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, empty, errors.Trace(err)
 	}
-	defer hostedState.Release()
+	return hostedState, targetInfo, nil
+}
 
-	// Construct target info.
-	specTarget := spec.TargetInfo
+func targetInfoFromParams(specTarget params.MigrationTargetInfo) (coremigration.TargetInfo, error) {
+	var empty coremigration.TargetInfo
 	controllerTag, err := names.ParseControllerTag(specTarget.ControllerTag)
 	if err != nil {
-		return "", errors.Annotate(err, "controller tag")
+		return empty, errors.Annotate(err, "controller tag")
 	}
 	authTag, err := names.ParseUserTag(specTarget.AuthTag)
 	if err != nil {
-		return "", errors.Annotate(err, "auth tag")
+		return empty, errors.Annotate(err, "auth tag")
 	}
 	var macs []macaroon.Slice
 	if specTarget.Macaroons != "" {
 		if err := json.Unmarshal([]byte(specTarget.Macaroons), &macs); err != nil {
-			return "", errors.Annotate(err, "invalid macaroons")
+			return empty, errors.Annotate(err, "invalid macaroons")
 		}
 	}
-	targetInfo := coremigration.TargetInfo{
+	return coremigration.TargetInfo{
 		ControllerTag:   controllerTag,
 		ControllerAlias: specTarget.ControllerAlias,
 		Addrs:           specTarget.Addrs,
@@ -643,22 +766,325 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 		AuthTag:         authTag,
 		Password:        specTarget.Password,
 		Macaroons:       macs,
-	}
+	}, nil
+}
 
-	// Check if the migration is likely to succeed.
-	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
-		return "", errors.Trace(err)
+func precheckFailuresToParams(failures []coremigration.PrecheckFailure) []params.MigrationPrecheckFailure {
+	if len(failures) == 0 {
+		return nil
 	}
+	out := make([]params.MigrationPrecheckFailure, len(failures))
+	for i, failure := range failures {
+		out[i] = params.MigrationPrecheckFailure{
+			Entity:  failure.Entity,
+			Message: failure.Message,
+		}
+	}
+	return out
+}
 
-	// Trigger the migration.
-	mig, err := hostedState.CreateMigration(state.MigrationSpec{
-		InitiatedBy: c.apiUser,
-		TargetInfo:  targetInfo,
+// InitiateBulkMigration records a request to migrate a number of models
+// to another controller. The migrations are started by the controller,
+// with no more than the requested number running at the same time.
+func (c *ControllerAPI) InitiateBulkMigration(args params.InitiateBulkMigrationArgs) (
+	params.InitiateBulkMigrationResult, error,
+) {
+	var result params.InitiateBulkMigrationResult
+	if err := c.checkIsSuperUser(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.ModelTags) > 0 && args.OwnerTag != "" {
+		return result, errors.BadRequestf("specify either models or their owner, not both")
+	}
+	modelUUIDs, err := c.bulkMigrationModels(args)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	targetInfo, err := targetInfoFromParams(args.TargetInfo)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	// The models are checked as InitiateMigration checks them. Those
+	// failing the prechecks are recorded as not started, and are
+	// reported in the bulk migration's status.
+	precheckErrors := make(map[string]string)
+	for _, modelUUID := range modelUUIDs {
+		if err := c.precheckBulkMigrationModel(modelUUID, &targetInfo); err != nil {
+			precheckErrors[modelUUID] = err.Error()
+		}
+	}
+	bulk, err := c.state.CreateBulkMigration(state.BulkMigrationSpec{
+		InitiatedBy:    c.apiUser,
+		ModelUUIDs:     modelUUIDs,
+		TargetInfo:     targetInfo,
+		MaxConcurrent:  args.MaxConcurrent,
+		PrecheckErrors: precheckErrors,
 	})
 	if err != nil {
-		return "", errors.Trace(err)
+		return result, errors.Trace(err)
 	}
-	return mig.Id(), nil
+	result.Id = bulk.Id()
+	for _, modelUUID := range modelUUIDs {
+		result.ModelTags = append(result.ModelTags, names.NewModelTag(modelUUID).String())
+	}
+	return result, nil
+}
+
+// InitiateBulkMigration isn't on the v9 API.
+func (c *ControllerAPIv9) InitiateBulkMigration(_, _ struct{}) {}
+
+// precheckBulkMigrationModel runs the migration prechecks for a model
+// of a bulk migration. Models that don't exist aren't checked; creating
+// the bulk migration fails for them.
+func (c *ControllerAPI) precheckBulkMigrationModel(modelUUID string, targetInfo *coremigration.TargetInfo) error {
+	hostedState, err := c.statePool.Get(modelUUID)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer hostedState.Release()
+	return runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), targetInfo, c.presence)
+}
+
+// bulkMigrationModels returns the UUIDs of the models to migrate.
+func (c *ControllerAPI) bulkMigrationModels(args params.InitiateBulkMigrationArgs) ([]string, error) {
+	if args.OwnerTag == "" {
+		var modelUUIDs []string
+		for _, tag := range args.ModelTags {
+			modelTag, err := names.ParseModelTag(tag)
+			if err != nil {
+				return nil, errors.Annotate(err, "model tag")
+			}
+			modelUUIDs = append(modelUUIDs, modelTag.Id())
+		}
+		return modelUUIDs, nil
+	}
+
+	owner, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return nil, errors.Annotate(err, "owner tag")
+	}
+	allUUIDs, err := c.state.AllModelUUIDs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var modelUUIDs []string
+	for _, modelUUID := range allUUIDs {
+		if modelUUID == c.state.ControllerModelUUID() {
+			continue
+		}
+		model, ph, err := c.statePool.GetModel(modelUUID)
+		if errors.IsNotFound(err) {
+			// This model could have been removed.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if model.Owner() == owner {
+			modelUUIDs = append(modelUUIDs, modelUUID)
+		}
+		ph.Release()
+	}
+	if len(modelUUIDs) == 0 {
+		return nil, errors.NotFoundf("models owned by %q", owner.Id())
+	}
+	return modelUUIDs, nil
+}
+
+// BulkMigrationStatus reports the progress of the requested bulk
+// migrations, or of all bulk migrations if none are requested.
+func (c *ControllerAPI) BulkMigrationStatus(args params.BulkMigrationStatusArgs) (
+	params.BulkMigrationStatusResults, error,
+) {
+	var results params.BulkMigrationStatusResults
+	if err := c.checkIsSuperUser(); err != nil {
+		return results, errors.Trace(err)
+	}
+	if len(args.Ids) == 0 {
+		bulks, err := c.state.AllBulkMigrations()
+		if err != nil {
+			return results, errors.Trace(err)
+		}
+		for _, bulk := range bulks {
+			results.Results = append(results.Results, c.bulkMigrationStatus(bulk))
+		}
+		return results, nil
+	}
+	results.Results = make([]params.BulkMigrationStatusResult, len(args.Ids))
+	for i, id := range args.Ids {
+		bulk, err := c.state.BulkMigration(id)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i] = c.bulkMigrationStatus(bulk)
+	}
+	return results, nil
+}
+
+// BulkMigrationStatus isn't on the v9 API.
+func (c *ControllerAPIv9) BulkMigrationStatus(_, _ struct{}) {}
+
+func (c *ControllerAPI) bulkMigrationStatus(bulk *state.BulkMigration) params.BulkMigrationStatusResult {
+	targetInfo, err := bulk.TargetInfo()
+	if err != nil {
+		return params.BulkMigrationStatusResult{Error: apiservererrors.ServerError(err)}
+	}
+	target := targetInfo.ControllerAlias
+	if target == "" {
+		target = targetInfo.ControllerTag.Id()
+	}
+	status := &params.BulkMigrationStatus{
+		Id:               bulk.Id(),
+		InitiatedBy:      bulk.InitiatedBy(),
+		StartTime:        bulk.StartTime(),
+		MaxConcurrent:    bulk.MaxConcurrent(),
+		TargetController: target,
+	}
+	for _, m := range bulk.Models() {
+		modelStatus := params.BulkMigrationModelStatus{
+			ModelTag:    names.NewModelTag(m.ModelUUID).String(),
+			ModelName:   m.ModelName,
+			OwnerTag:    names.NewUserTag(m.ModelOwner).String(),
+			MigrationId: m.MigrationId,
+			StartError:  m.StartError,
+		}
+		if m.MigrationId != "" {
+			mig, err := c.state.Migration(m.MigrationId)
+			if err != nil {
+				return params.BulkMigrationStatusResult{Error: apiservererrors.ServerError(err)}
+			}
+			phase, err := mig.Phase()
+			if err != nil {
+				return params.BulkMigrationStatusResult{Error: apiservererrors.ServerError(err)}
+			}
+			modelStatus.Phase = phase.String()
+			modelStatus.StatusMessage = mig.StatusMessage()
+		}
+		status.Models = append(status.Models, modelStatus)
+	}
+	return params.BulkMigrationStatusResult{Result: status}
+}
+
+// MigrationPhase returns the phase and latest status message of the
+// model migrations with the given ids. It is used to follow offline
+// migrations, which are driven by the client.
+func (c *ControllerAPI) MigrationPhase(args params.MigrationIds) (params.MigrationPhaseResults, error) {
+	results := params.MigrationPhaseResults{
+		Results: make([]params.MigrationPhaseResult, len(args.Ids)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		mig, err := c.state.Migration(id)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		phase, err := mig.Phase()
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Phase = phase.String()
+		results.Results[i].StatusMessage = mig.StatusMessage()
+	}
+	return results, nil
+}
+
+// MigrationPhase isn't on the v9 API.
+func (c *ControllerAPIv9) MigrationPhase(_, _ struct{}) {}
+
+// SetMigrationTarget records the controllers into which the archives of
+// offline model migrations have been imported. The migrationmaster then
+// hands the models' agents over to those controllers.
+func (c *ControllerAPI) SetMigrationTarget(args params.SetMigrationTargetArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Args {
+		err := c.withOfflineMigration(arg.MigrationId, func(mig state.ModelMigration) error {
+			controllerTag, err := names.ParseControllerTag(arg.TargetInfo.ControllerTag)
+			if err != nil {
+				return errors.Annotate(err, "controller tag")
+			}
+			authTag, err := names.ParseUserTag(arg.TargetInfo.AuthTag)
+			if err != nil {
+				return errors.Annotate(err, "auth tag")
+			}
+			return mig.SetTarget(coremigration.TargetInfo{
+				ControllerTag:   controllerTag,
+				ControllerAlias: arg.TargetInfo.ControllerAlias,
+				Addrs:           arg.TargetInfo.Addrs,
+				CACert:          arg.TargetInfo.CACert,
+				AuthTag:         authTag,
+			})
+		})
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetMigrationTarget isn't on the v9 API.
+func (c *ControllerAPIv9) SetMigrationTarget(_, _ struct{}) {}
+
+// AbortMigration aborts offline model migrations whose archives haven't
+// been imported, unlocking the models.
+func (c *ControllerAPI) AbortMigration(args params.MigrationIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		err := c.withOfflineMigration(id, func(mig state.ModelMigration) error {
+			phase, err := mig.Phase()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if phase != coremigration.QUIESCE && phase != coremigration.IMPORT {
+				return errors.Errorf("migration %q can't be aborted in the %s phase", id, phase)
+			}
+			return mig.SetPhase(coremigration.ABORT)
+		})
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+// AbortMigration isn't on the v9 API.
+func (c *ControllerAPIv9) AbortMigration(_, _ struct{}) {}
+
+// withOfflineMigration calls f with the active offline migration with
+// the given id, loaded from the migrating model's state.
+func (c *ControllerAPI) withOfflineMigration(id string, f func(state.ModelMigration) error) error {
+	mig, err := c.state.Migration(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostedState, err := c.statePool.Get(mig.ModelUUID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer hostedState.Release()
+
+	mig, err = hostedState.LatestMigration()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if mig.Id() != id {
+		return errors.NotFoundf("active migration %q", id)
+	}
+	if !mig.Offline() {
+		return errors.Errorf("migration %q is not an offline migration", id)
+	}
+	return errors.Trace(f(mig))
 }
 
 // ModifyControllerAccess changes the model access granted to users.
@@ -735,11 +1161,9 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 // ConfigSet isn't on the v4 API.
 func (c *ControllerAPIv4) ConfigSet(_, _ struct{}) {}
 
-// runMigrationPrechecks runs prechecks on the migration and updates
-// information in targetInfo as needed based on information
-// retrieved from the target controller.
-var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigration.TargetInfo, presence facade.Presence) error {
-	// Check model and source controller.
+// runSourcePrechecks runs the prechecks on the model and the source
+// controller of a migration.
+var runSourcePrechecks = func(st, ctlrSt *state.State, presence facade.Presence) error {
 	backend, err := migration.PrecheckShim(st, ctlrSt)
 	if err != nil {
 		return errors.Annotate(err, "creating backend")
@@ -749,6 +1173,17 @@ var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigrat
 	if err := migration.SourcePrecheck(backend, modelPresence, controllerPresence); err != nil {
 		return errors.Annotate(err, "source prechecks failed")
 	}
+	return nil
+}
+
+// runMigrationPrechecks runs prechecks on the migration and updates
+// information in targetInfo as needed based on information
+// retrieved from the target controller.
+var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigration.TargetInfo, presence facade.Presence) error {
+	// Check model and source controller.
+	if err := runSourcePrechecks(st, ctlrSt, presence); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Check target controller.
 	conn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
@@ -783,6 +1218,60 @@ var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigrat
 	return errors.Annotate(err, "target prechecks failed")
 }
 
+// runMigrationPrecheckReport runs all of the prechecks on the migration,
+// returning the checks which failed on the source and on the target
+// controller. Problems reaching the target controller are reported as
+// target failures, so the source checks are always reported.
+var runMigrationPrecheckReport = func(st, ctlrSt *state.State, targetInfo *coremigration.TargetInfo, presence facade.Presence) (
+	source, target []coremigration.PrecheckFailure, err error,
+) {
+	backend, err := migration.PrecheckShim(st, ctlrSt)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "creating backend")
+	}
+	modelPresence := presence.ModelPresence(st.ModelUUID())
+	controllerPresence := presence.ModelPresence(ctlrSt.ModelUUID())
+	source, err = migration.SourcePrecheckReport(backend, modelPresence, controllerPresence)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "source prechecks")
+	}
+	modelInfo, srcUserList, err := makeModelInfo(st, ctlrSt)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	targetFailure := func(err error) []coremigration.PrecheckFailure {
+		return []coremigration.PrecheckFailure{{
+			Entity:  "controller",
+			Message: err.Error(),
+		}}
+	}
+	conn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
+	if err != nil {
+		return source, targetFailure(errors.Annotate(err, "connect to target controller")), nil
+	}
+	defer conn.Close()
+	dstUserList, err := getTargetControllerUsers(conn)
+	if err != nil {
+		return source, targetFailure(errors.Annotate(err, "reading target controller users")), nil
+	}
+	if err := srcUserList.checkCompatibilityWith(dstUserList); err != nil {
+		target = append(target, targetFailure(err)...)
+	}
+
+	client := migrationtarget.NewClient(conn)
+	failures, err := client.PrecheckReport(modelInfo)
+	if errors.IsNotSupported(err) {
+		// Older target controllers only report the first failed check.
+		if err := client.Prechecks(modelInfo); err != nil {
+			failures = targetFailure(err)
+		}
+	} else if err != nil {
+		failures = targetFailure(errors.Annotate(err, "target prechecks"))
+	}
+	return source, append(target, failures...), nil
+}
+
 // userList encapsulates information about the users who have been granted
 // access to a model or the users known to a particular controller.
 type userList struct {
@@ -791,8 +1280,8 @@ type userList struct {
 }
 
 // checkCompatibilityWith ensures that the set of users granted access to
-// the model being migrated is present in the destination (migration target)
-// controller.
+// the model being migrated, directly or as members of groups, is present
+// in the destination (migration target) controller.
 func (src *userList) checkCompatibilityWith(dst userList) error {
 	srcUsers, dstUsers := src.users, dst.users
 
@@ -867,6 +1356,13 @@ func makeModelInfo(st, ctlrSt *state.State) (coremigration.ModelInfo, userList,
 	for _, u := range users {
 		ul.users.Add(u.UserName)
 	}
+	// The groups granted access to the model are migrated with it,
+	// so their members need to exist on the target too.
+	groupMembers, err := st.ModelGroupMembers()
+	if err != nil {
+		return empty, ul, errors.Trace(err)
+	}
+	ul.users = ul.users.Union(set.NewStrings(groupMembers...))
 
 	// Retrieve agent version for the model.
 	conf, err := model.ModelConfig()
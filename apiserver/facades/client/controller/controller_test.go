@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/cloud"
 	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/environs"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
@@ -617,6 +618,288 @@ func (s *controllerSuite) TestInitiateMigrationPrecheckFail(c *gc.C) {
 	c.Check(active, jc.IsFalse)
 }
 
+func (s *controllerSuite) TestPrecheckMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetPrecheckReport(s,
+		[]coremigration.PrecheckFailure{{Entity: "machine 0", Message: "machine 0 not running (stopped)"}},
+		[]coremigration.PrecheckFailure{{Entity: "controller", Message: "model with same UUID already exists"}},
+	)
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}, {
+			ModelTag: randomModelTag(), // Doesn't exist.
+		}},
+	}
+	out, err := s.controller.PrecheckMigration(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, jc.DeepEquals, []params.MigrationPrecheckReport{{
+		ModelTag: m.ModelTag().String(),
+		Source:   []params.MigrationPrecheckFailure{{Entity: "machine 0", Message: "machine 0 not running (stopped)"}},
+		Target:   []params.MigrationPrecheckFailure{{Entity: "controller", Message: "model with same UUID already exists"}},
+	}, {
+		ModelTag: args.Specs[1].ModelTag,
+		Error:    &params.Error{Message: "model not found", Code: params.CodeNotFound},
+	}})
+
+	active, err := st.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
+
+func (s *controllerSuite) TestInitiateBulkMigration(c *gc.C) {
+	controller.SetPrecheckResult(s, nil)
+	st1 := s.Factory.MakeModel(c, nil)
+	defer st1.Close()
+	st2 := s.Factory.MakeModel(c, nil)
+	defer st2.Close()
+
+	targetInfo := params.MigrationTargetInfo{
+		ControllerTag:   randomControllerTag(),
+		ControllerAlias: "target",
+		Addrs:           []string{"1.1.1.1:1111"},
+		CACert:          "cert",
+		AuthTag:         names.NewUserTag("admin").String(),
+		Password:        "secret",
+	}
+	result, err := s.controller.InitiateBulkMigration(params.InitiateBulkMigrationArgs{
+		ModelTags:     []string{names.NewModelTag(st1.ModelUUID()).String(), names.NewModelTag(st2.ModelUUID()).String()},
+		TargetInfo:    targetInfo,
+		MaxConcurrent: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Id, gc.Equals, "0")
+	c.Check(result.ModelTags, jc.DeepEquals, []string{
+		names.NewModelTag(st1.ModelUUID()).String(),
+		names.NewModelTag(st2.ModelUUID()).String(),
+	})
+
+	// The migrations are only started by the bulk migration worker.
+	active, err := st1.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+
+	statusResults, err := s.controller.BulkMigrationStatus(params.BulkMigrationStatusArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusResults.Results, gc.HasLen, 1)
+	c.Assert(statusResults.Results[0].Error, gc.IsNil)
+	status := statusResults.Results[0].Result
+	c.Check(status.Id, gc.Equals, "0")
+	c.Check(status.MaxConcurrent, gc.Equals, 1)
+	c.Check(status.TargetController, gc.Equals, "target")
+	c.Assert(status.Models, gc.HasLen, 2)
+	c.Check(status.Models[0].ModelTag, gc.Equals, names.NewModelTag(st1.ModelUUID()).String())
+	c.Check(status.Models[0].Phase, gc.Equals, "")
+	c.Check(status.Models[0].StartError, gc.Equals, "")
+}
+
+func (s *controllerSuite) TestInitiateBulkMigrationPrecheckFail(c *gc.C) {
+	controller.SetPrecheckResult(s, errors.New("boom"))
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	result, err := s.controller.InitiateBulkMigration(params.InitiateBulkMigrationArgs{
+		ModelTags: []string{names.NewModelTag(st.ModelUUID()).String()},
+		TargetInfo: params.MigrationTargetInfo{
+			ControllerTag: randomControllerTag(),
+			Addrs:         []string{"1.1.1.1:1111"},
+			CACert:        "cert",
+			AuthTag:       names.NewUserTag("admin").String(),
+			Password:      "secret",
+		},
+		MaxConcurrent: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The model's failure is recorded, so it isn't migrated.
+	bulk, err := s.State.BulkMigration(result.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	models := bulk.Models()
+	c.Assert(models, gc.HasLen, 1)
+	c.Check(models[0].StartError, gc.Equals, "boom")
+	c.Check(models[0].Queued(), jc.IsFalse)
+}
+
+func (s *controllerSuite) TestInitiateBulkMigrationByOwner(c *gc.C) {
+	controller.SetPrecheckResult(s, nil)
+	owner := s.Factory.MakeUser(c, nil)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{Owner: owner.UserTag()})
+	defer st.Close()
+	other := s.Factory.MakeModel(c, nil)
+	defer other.Close()
+
+	result, err := s.controller.InitiateBulkMigration(params.InitiateBulkMigrationArgs{
+		OwnerTag: owner.UserTag().String(),
+		TargetInfo: params.MigrationTargetInfo{
+			ControllerTag: randomControllerTag(),
+			Addrs:         []string{"1.1.1.1:1111"},
+			CACert:        "cert",
+			AuthTag:       names.NewUserTag("admin").String(),
+			Password:      "secret",
+		},
+		MaxConcurrent: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ModelTags, jc.DeepEquals, []string{names.NewModelTag(st.ModelUUID()).String()})
+}
+
+func (s *controllerSuite) TestBulkMigrationStatusNotFound(c *gc.C) {
+	results, err := s.controller.BulkMigrationStatus(params.BulkMigrationStatusArgs{Ids: []string{"42"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, `bulk migration "42" not found`)
+}
+
+func (s *controllerSuite) initiateOfflineMigration(c *gc.C, st *state.State) string {
+	controller.SetPrecheckResult(s, nil)
+	out, err := s.controller.InitiateMigration(params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: names.NewModelTag(st.ModelUUID()).String(),
+			Offline:  true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Assert(out.Results[0].Error, gc.IsNil)
+	return out.Results[0].MigrationId
+}
+
+func (s *controllerSuite) TestInitiateOfflineMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	id := s.initiateOfflineMigration(c, st)
+	c.Check(id, gc.Equals, st.ModelUUID()+":0")
+
+	mig, err := st.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mig.Offline(), jc.IsTrue)
+	targetInfo, err := mig.TargetInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*targetInfo, jc.DeepEquals, coremigration.TargetInfo{})
+
+	// The model is locked while it is exported.
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.MigrationMode(), gc.Equals, state.MigrationModeExporting)
+
+	results, err := s.controller.MigrationPhase(params.MigrationIds{Ids: []string{id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, jc.DeepEquals, []params.MigrationPhaseResult{{
+		Phase:         "QUIESCE",
+		StatusMessage: "starting",
+	}})
+}
+
+func (s *controllerSuite) TestInitiateOfflineMigrationPrecheckFail(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetPrecheckResult(s, errors.New("boom"))
+	out, err := s.controller.InitiateMigration(params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: names.NewModelTag(st.ModelUUID()).String(),
+			Offline:  true,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Check(out.Results[0].Error, gc.ErrorMatches, "boom")
+
+	active, err := st.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
+
+func (s *controllerSuite) TestSetMigrationTarget(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	id := s.initiateOfflineMigration(c, st)
+
+	target := params.MigrationTargetInfo{
+		ControllerTag:   randomControllerTag(),
+		ControllerAlias: "target",
+		Addrs:           []string{"1.1.1.1:1111"},
+		CACert:          "cert",
+		AuthTag:         names.NewUserTag("admin").String(),
+	}
+	setTarget := func() error {
+		results, err := s.controller.SetMigrationTarget(params.SetMigrationTargetArgs{
+			Args: []params.SetMigrationTargetArg{{MigrationId: id, TargetInfo: target}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		return results.OneError()
+	}
+
+	// The target can only be set once the model has been exported.
+	c.Assert(setTarget(), gc.ErrorMatches, `migration ".*" is in the QUIESCE phase, not IMPORT`)
+
+	mig, err := st.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.SetPhase(coremigration.IMPORT), jc.ErrorIsNil)
+	c.Assert(setTarget(), jc.ErrorIsNil)
+
+	mig, err = st.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	targetInfo, err := mig.TargetInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(targetInfo.ControllerTag.String(), gc.Equals, target.ControllerTag)
+	c.Check(targetInfo.ControllerAlias, gc.Equals, "target")
+	c.Check(targetInfo.Addrs, jc.DeepEquals, target.Addrs)
+	c.Check(targetInfo.CACert, gc.Equals, "cert")
+
+	c.Assert(setTarget(), gc.ErrorMatches, `target of migration ".*" is already set`)
+}
+
+func (s *controllerSuite) TestAbortMigration(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	id := s.initiateOfflineMigration(c, st)
+
+	results, err := s.controller.AbortMigration(params.MigrationIds{Ids: []string{id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	mig, err := st.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	phase, err := mig.Phase()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(phase, gc.Equals, coremigration.ABORT)
+}
+
+func (s *controllerSuite) TestAbortMigrationNotOffline(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	mig, err := st.CreateMigration(state.MigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		TargetInfo: coremigration.TargetInfo{
+			ControllerTag: names.NewControllerTag(utils.MustNewUUID().String()),
+			Addrs:         []string{"1.1.1.1:1111"},
+			CACert:        "cert",
+			AuthTag:       names.NewUserTag("admin"),
+			Password:      "secret",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.controller.AbortMigration(params.MigrationIds{Ids: []string{mig.Id()}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.OneError(), gc.ErrorMatches, `migration ".*" is not an offline migration`)
+}
+
 func randomControllerTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewControllerTag(uuid).String()
@@ -17,4 +17,15 @@ func SetPrecheckResult(p patcher, err error) {
 	p.PatchValue(&runMigrationPrechecks, func(*state.State, *state.State, *migration.TargetInfo, facade.Presence) error {
 		return err
 	})
+	p.PatchValue(&runSourcePrechecks, func(*state.State, *state.State, facade.Presence) error {
+		return err
+	})
+}
+
+func SetPrecheckReport(p patcher, source, target []migration.PrecheckFailure) {
+	p.PatchValue(&runMigrationPrecheckReport, func(*state.State, *state.State, *migration.TargetInfo, facade.Presence) (
+		[]migration.PrecheckFailure, []migration.PrecheckFailure, error,
+	) {
+		return source, target, nil
+	})
 }
@@ -101,6 +101,19 @@ type MachineManagerAPIV5 struct {
 // Version 6 of Machine Manager API.
 // Changes input parameters to DestroyMachineWithParams and ForceDestroyMachine.
 type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV7
+}
+
+// Version 7 of Machine Manager API.
+// Adds AddMachinePools, MachinePools, SetMachinePoolSizes and
+// RemoveMachinePools.
+type MachineManagerAPIV7 struct {
+	*MachineManagerAPIV8
+}
+
+// Version 8 of Machine Manager API.
+// AddMachines accepts cloud-init user data for each machine.
+type MachineManagerAPIV8 struct {
 	*MachineManagerAPI
 }
 
@@ -124,11 +137,29 @@ func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
 
 // NewFacadeV6 creates a new server-side MachineManager API facade.
 func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIv7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIv7}, nil
+}
+
+// NewFacadeV7 creates a new server-side MachineManager API facade.
+func NewFacadeV7(ctx facade.Context) (*MachineManagerAPIV7, error) {
+	machineManagerAPIv8, err := NewFacadeV8(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV7{machineManagerAPIv8}, nil
+}
+
+// NewFacadeV8 creates a new server-side MachineManager API facade.
+func NewFacadeV8(ctx facade.Context) (*MachineManagerAPIV8, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV6{machineManagerAPI}, nil
+	return &MachineManagerAPIV8{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -205,12 +236,18 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	if p.ContainerType != "" && p.Placement != nil {
 		return nil, fmt.Errorf("container type and placement are mutually exclusive")
 	}
+	// Extract container type and parent from container placement directives.
+	// A zone directive places the container on a new host in that zone.
+	var hostPlacement string
 	if p.Placement != nil {
-		// Extract container type and parent from container placement directives.
 		containerType, err := instance.ParseContainerType(p.Placement.Scope)
 		if err == nil {
 			p.ContainerType = containerType
-			p.ParentId = p.Placement.Directive
+			if instance.IsZoneDirective(p.Placement.Directive) {
+				hostPlacement = p.Placement.Directive
+			} else {
+				p.ParentId = p.Placement.Directive
+			}
 			p.Placement = nil
 		}
 	}
@@ -290,6 +327,7 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		HardwareCharacteristics: p.HardwareCharacteristics,
 		Addresses:               sAddrs,
 		Placement:               placementDirective,
+		CloudInitUserData:       p.CloudInitUserData,
 	}
 	if p.ContainerType == "" {
 		return mm.st.AddOneMachine(template)
@@ -297,7 +335,21 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	if p.ParentId != "" {
 		return mm.st.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
 	}
-	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
+	// The cloud-init user data is for the container, not its new host.
+	parentTemplate := template
+	parentTemplate.CloudInitUserData = ""
+	parentTemplate.Placement = hostPlacement
+	return mm.st.AddMachineInsideNewMachine(template, parentTemplate, p.ContainerType)
+}
+
+// AddMachines adds new machines with the supplied parameters. Version 7
+// and earlier of the API do not support cloud-init user data, so it is
+// ignored.
+func (mm *MachineManagerAPIV7) AddMachines(args params.AddMachines) (params.AddMachinesResults, error) {
+	for i := range args.MachineParams {
+		args.MachineParams[i].CloudInitUserData = ""
+	}
+	return mm.MachineManagerAPIV8.AddMachines(args)
 }
 
 // DestroyMachine removes a set of machines from the model.
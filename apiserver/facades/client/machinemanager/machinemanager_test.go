@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
@@ -160,6 +161,63 @@ func (s *MachineManagerSuite) TestAddMachines(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestAddMachinesCloudInitUserData(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	apiParams := []params.AddMachineParams{{
+		Series:            "trusty",
+		Jobs:              []model.MachineJob{model.JobHostUnits},
+		CloudInitUserData: "packages: [htop]",
+	}}
+	machines, err := s.api.AddMachines(params.AddMachines{MachineParams: apiParams})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines.Machines, gc.HasLen, 1)
+	c.Assert(s.st.machineTemplates, gc.HasLen, 1)
+	c.Assert(s.st.machineTemplates[0].CloudInitUserData, gc.Equals, "packages: [htop]")
+}
+
+func (s *MachineManagerSuite) TestAddMachinesCloudInitUserDataV7(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	apiV7 := &machinemanager.MachineManagerAPIV7{&machinemanager.MachineManagerAPIV8{s.api}}
+	apiParams := []params.AddMachineParams{{
+		Series:            "trusty",
+		Jobs:              []model.MachineJob{model.JobHostUnits},
+		CloudInitUserData: "packages: [htop]",
+	}}
+	machines, err := apiV7.AddMachines(params.AddMachines{MachineParams: apiParams})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines.Machines, gc.HasLen, 1)
+	c.Assert(s.st.machineTemplates, gc.HasLen, 1)
+	c.Assert(s.st.machineTemplates[0].CloudInitUserData, gc.Equals, "")
+}
+
+func (s *MachineManagerSuite) TestAddMachinesContainerZonePlacement(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	apiParams := []params.AddMachineParams{{
+		Series:    "trusty",
+		Jobs:      []model.MachineJob{model.JobHostUnits},
+		Placement: &instance.Placement{Scope: "lxd", Directive: "zone=node02"},
+	}}
+	machines, err := s.api.AddMachines(params.AddMachines{MachineParams: apiParams})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines.Machines, gc.HasLen, 1)
+	c.Assert(machines.Machines[0].Error, gc.IsNil)
+	s.st.CheckCall(c, len(s.st.Calls())-1, "AddMachineInsideNewMachine",
+		state.MachineTemplate{
+			Series: "trusty",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		},
+		state.MachineTemplate{
+			Series:    "trusty",
+			Jobs:      []state.MachineJob{state.JobHostUnits},
+			Placement: "zone=node02",
+		},
+		instance.LXD,
+	)
+}
+
 func (s *MachineManagerSuite) TestNewMachineManagerAPINonClient(c *gc.C) {
 	tag := names.NewUnitTag("mysql/0")
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
@@ -522,7 +580,7 @@ func (s *MachineManagerSuite) setupUpgradeSeries(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) apiV5() machinemanager.MachineManagerAPIV5 {
-	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{s.api}}
+	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{&machinemanager.MachineManagerAPIV7{&machinemanager.MachineManagerAPIV8{s.api}}}}
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesValidateOK(c *gc.C) {
@@ -944,6 +1002,15 @@ func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machi
 	return &m, st.err
 }
 
+func (st *mockState) AddMachineInsideNewMachine(
+	template, parentTemplate state.MachineTemplate, containerType instance.ContainerType,
+) (*state.Machine, error) {
+	st.MethodCall(st, "AddMachineInsideNewMachine", template, parentTemplate, containerType)
+	st.calls++
+	st.machineTemplates = append(st.machineTemplates, template)
+	return &state.Machine{}, st.err
+}
+
 func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	st.MethodCall(st, "GetBlockForType", t)
 	if st.block == t {
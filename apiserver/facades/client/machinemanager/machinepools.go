@@ -0,0 +1,138 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// AddMachinePools adds the given machine pools to the model. The
+// provisioner starts the pools' machines when it next reconciles them.
+func (mm *MachineManagerAPI) AddMachinePools(args params.MachinePools) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Pools)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, p := range args.Pools {
+		err := mm.addMachinePool(p)
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) addMachinePool(p params.MachinePool) error {
+	if p.Series == "" {
+		model, err := mm.st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		conf, err := model.Config()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		p.Series = config.PreferredSeries(conf)
+	}
+	_, err := mm.st.AddMachinePool(state.MachinePoolArgs{
+		Name:        p.Name,
+		Series:      p.Series,
+		Constraints: p.Constraints,
+		Zones:       p.Zones,
+		TargetSize:  p.TargetSize,
+	})
+	return errors.Trace(err)
+}
+
+// MachinePools returns the machine pools in the model.
+func (mm *MachineManagerAPI) MachinePools() (params.MachinePools, error) {
+	result := params.MachinePools{}
+	if err := mm.checkCanRead(); err != nil {
+		return result, err
+	}
+	pools, err := mm.st.AllMachinePools()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Pools = make([]params.MachinePool, len(pools))
+	for i, pool := range pools {
+		cons, err := pool.Constraints()
+		if err != nil {
+			return params.MachinePools{}, errors.Trace(err)
+		}
+		result.Pools[i] = params.MachinePool{
+			Name:        pool.Name(),
+			Series:      pool.Series(),
+			Constraints: cons,
+			Zones:       pool.Zones(),
+			TargetSize:  pool.TargetSize(),
+			Machines:    pool.MachineIds(),
+		}
+	}
+	return result, nil
+}
+
+// SetMachinePoolSizes sets the number of machines that each of the
+// given machine pools is kept at.
+func (mm *MachineManagerAPI) SetMachinePoolSizes(args params.MachinePoolSizes) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Sizes)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Sizes {
+		pool, err := mm.st.MachinePool(arg.Name)
+		if err == nil {
+			err = pool.SetTargetSize(arg.TargetSize)
+		}
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveMachinePools removes the given machine pools. Their machines
+// are left in place.
+func (mm *MachineManagerAPI) RemoveMachinePools(args params.MachinePoolNames) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.RemoveAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, name := range args.Names {
+		pool, err := mm.st.MachinePool(name)
+		if err == nil {
+			err = pool.Remove()
+		}
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+// AddMachinePools isn't on the v6 API.
+func (mm *MachineManagerAPIV6) AddMachinePools(_, _ struct{}) {}
+
+// MachinePools isn't on the v6 API.
+func (mm *MachineManagerAPIV6) MachinePools(_, _ struct{}) {}
+
+// SetMachinePoolSizes isn't on the v6 API.
+func (mm *MachineManagerAPIV6) SetMachinePoolSizes(_, _ struct{}) {}
+
+// RemoveMachinePools isn't on the v6 API.
+func (mm *MachineManagerAPIV6) RemoveMachinePools(_, _ struct{}) {}
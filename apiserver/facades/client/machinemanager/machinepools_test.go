@@ -0,0 +1,212 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&MachinePoolsSuite{})
+
+type MachinePoolsSuite struct {
+	coretesting.BaseSuite
+	st         *mockMachinePoolState
+	authorizer *apiservertesting.FakeAuthorizer
+	api        *machinemanager.MachineManagerAPI
+}
+
+func (s *MachinePoolsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &mockMachinePoolState{
+		mockState: &mockState{machines: make(map[string]*mockMachine)},
+		pools:     make(map[string]*mockMachinePool),
+	}
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	s.setAPIUser(c, names.NewUserTag("admin"))
+}
+
+func (s *MachinePoolsSuite) setAPIUser(c *gc.C, user names.UserTag) {
+	s.authorizer.Tag = user
+	var err error
+	s.api, err = machinemanager.NewMachineManagerAPI(s.st,
+		s.st,
+		&mockPool{},
+		s.authorizer,
+		s.st.ModelTag(),
+		context.NewCloudCallContext(),
+		common.NewResources(),
+		nil,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachinePoolsSuite) TestAddMachinePools(c *gc.C) {
+	s.st.pools["batch"] = &mockMachinePool{name: "batch"}
+	results, err := s.api.AddMachinePools(params.MachinePools{Pools: []params.MachinePool{{
+		Name:        "workers",
+		Series:      "focal",
+		Constraints: constraints.MustParse("mem=4G"),
+		Zones:       []string{"a", "b"},
+		TargetSize:  3,
+	}, {
+		Name:   "batch",
+		Series: "focal",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `machine pool "batch" already exists`)
+	c.Assert(results.Results[1].Error.Code, gc.Equals, params.CodeAlreadyExists)
+	c.Assert(s.st.pools["workers"], jc.DeepEquals, &mockMachinePool{
+		name:   "workers",
+		series: "focal",
+		cons:   constraints.MustParse("mem=4G"),
+		zones:  []string{"a", "b"},
+		size:   3,
+	})
+}
+
+func (s *MachinePoolsSuite) TestAddMachinePoolsPermission(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("bob"))
+	_, err := s.api.AddMachinePools(params.MachinePools{Pools: []params.MachinePool{{Name: "workers"}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachinePoolsSuite) TestAddMachinePoolsBlocked(c *gc.C) {
+	s.st.blockMsg = "TestAddMachinePoolsBlocked"
+	s.st.block = state.ChangeBlock
+	_, err := s.api.AddMachinePools(params.MachinePools{Pools: []params.MachinePool{{Name: "workers"}}})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+}
+
+func (s *MachinePoolsSuite) TestMachinePools(c *gc.C) {
+	s.st.pools["workers"] = &mockMachinePool{
+		name:     "workers",
+		series:   "focal",
+		cons:     constraints.MustParse("mem=4G"),
+		zones:    []string{"a"},
+		size:     2,
+		machines: []string{"1", "2"},
+	}
+	result, err := s.api.MachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachinePools{Pools: []params.MachinePool{{
+		Name:        "workers",
+		Series:      "focal",
+		Constraints: constraints.MustParse("mem=4G"),
+		Zones:       []string{"a"},
+		TargetSize:  2,
+		Machines:    []string{"1", "2"},
+	}}})
+}
+
+func (s *MachinePoolsSuite) TestSetMachinePoolSizes(c *gc.C) {
+	pool := &mockMachinePool{name: "workers", size: 1}
+	s.st.pools["workers"] = pool
+	results, err := s.api.SetMachinePoolSizes(params.MachinePoolSizes{Sizes: []params.MachinePoolSize{
+		{Name: "workers", TargetSize: 4},
+		{Name: "missing", TargetSize: 1},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(pool.size, gc.Equals, 4)
+}
+
+func (s *MachinePoolsSuite) TestRemoveMachinePools(c *gc.C) {
+	pool := &mockMachinePool{name: "workers"}
+	s.st.pools["workers"] = pool
+	results, err := s.api.RemoveMachinePools(params.MachinePoolNames{Names: []string{"workers", "missing"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(pool.removed, jc.IsTrue)
+}
+
+func (s *MachinePoolsSuite) TestRemoveMachinePoolsBlocked(c *gc.C) {
+	s.st.blockMsg = "TestRemoveMachinePoolsBlocked"
+	s.st.block = state.RemoveBlock
+	_, err := s.api.RemoveMachinePools(params.MachinePoolNames{Names: []string{"workers"}})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+}
+
+type mockMachinePoolState struct {
+	*mockState
+	pools map[string]*mockMachinePool
+}
+
+func (st *mockMachinePoolState) AddMachinePool(args state.MachinePoolArgs) (machinemanager.MachinePool, error) {
+	st.MethodCall(st, "AddMachinePool", args)
+	if _, ok := st.pools[args.Name]; ok {
+		return nil, errors.AlreadyExistsf("machine pool %q", args.Name)
+	}
+	pool := &mockMachinePool{
+		name:   args.Name,
+		series: args.Series,
+		cons:   args.Constraints,
+		zones:  args.Zones,
+		size:   args.TargetSize,
+	}
+	st.pools[args.Name] = pool
+	return pool, nil
+}
+
+func (st *mockMachinePoolState) MachinePool(name string) (machinemanager.MachinePool, error) {
+	st.MethodCall(st, "MachinePool", name)
+	pool, ok := st.pools[name]
+	if !ok {
+		return nil, errors.NotFoundf("machine pool %q", name)
+	}
+	return pool, nil
+}
+
+func (st *mockMachinePoolState) AllMachinePools() ([]machinemanager.MachinePool, error) {
+	st.MethodCall(st, "AllMachinePools")
+	var pools []machinemanager.MachinePool
+	for _, pool := range st.pools {
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+type mockMachinePool struct {
+	name     string
+	series   string
+	cons     constraints.Value
+	zones    []string
+	size     int
+	machines []string
+	removed  bool
+}
+
+func (p *mockMachinePool) Name() string                            { return p.name }
+func (p *mockMachinePool) Series() string                          { return p.series }
+func (p *mockMachinePool) Zones() []string                         { return p.zones }
+func (p *mockMachinePool) TargetSize() int                         { return p.size }
+func (p *mockMachinePool) MachineIds() []string                    { return p.machines }
+func (p *mockMachinePool) Constraints() (constraints.Value, error) { return p.cons, nil }
+
+func (p *mockMachinePool) SetTargetSize(size int) error {
+	p.size = size
+	return nil
+}
+
+func (p *mockMachinePool) Remove() error {
+	p.removed = true
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/apiserver/common/storagecommon"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
@@ -27,6 +28,21 @@ type Backend interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	AddMachinePool(state.MachinePoolArgs) (MachinePool, error)
+	MachinePool(string) (MachinePool, error)
+	AllMachinePools() ([]MachinePool, error)
+}
+
+// MachinePool represents a machine pool from the state package.
+type MachinePool interface {
+	Name() string
+	Series() string
+	Zones() []string
+	TargetSize() int
+	MachineIds() []string
+	Constraints() (constraints.Value, error)
+	SetTargetSize(int) error
+	Remove() error
 }
 
 type Pool interface {
@@ -74,6 +90,26 @@ func (s stateShim) Model() (Model, error) {
 	return s.State.Model()
 }
 
+func (s stateShim) AddMachinePool(args state.MachinePoolArgs) (MachinePool, error) {
+	return s.State.AddMachinePool(args)
+}
+
+func (s stateShim) MachinePool(name string) (MachinePool, error) {
+	return s.State.MachinePool(name)
+}
+
+func (s stateShim) AllMachinePools() ([]MachinePool, error) {
+	pools, err := s.State.AllMachinePools()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MachinePool, len(pools))
+	for i, pool := range pools {
+		out[i] = pool
+	}
+	return out, nil
+}
+
 type poolShim struct {
 	pool *state.StatePool
 }
@@ -0,0 +1,62 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rolemanager
+
+import (
+	"time"
+
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state methods used by the role manager facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	AddRole(name string, permissions []string, createdBy names.UserTag) (Role, error)
+	Role(name string) (Role, error)
+	AllRoles() ([]Role, error)
+	RemoveRole(name string) error
+	AssignRole(user names.UserTag, target names.Tag, role string) error
+	UnassignRole(user names.UserTag, target names.Tag) error
+	RoleAssignments(role string) ([]state.RoleAssignment, error)
+}
+
+// Role defines the custom role methods used by the role manager
+// facade.
+type Role interface {
+	Name() string
+	Permissions() []string
+	CreatedBy() string
+	DateCreated() time.Time
+}
+
+type stateShim struct {
+	*state.State
+}
+
+// NewStateBackend returns a Backend backed by the given state.
+func NewStateBackend(st *state.State) Backend {
+	return stateShim{st}
+}
+
+func (s stateShim) AddRole(name string, permissions []string, createdBy names.UserTag) (Role, error) {
+	return s.State.AddRole(name, permissions, createdBy)
+}
+
+func (s stateShim) Role(name string) (Role, error) {
+	return s.State.Role(name)
+}
+
+func (s stateShim) AllRoles() ([]Role, error) {
+	roles, err := s.State.AllRoles()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Role, len(roles))
+	for i, role := range roles {
+		result[i] = role
+	}
+	return result, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rolemanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+)
+
+// BlockChecker defines the block checks used by the role manager
+// facade.
+type BlockChecker interface {
+	ChangeAllowed() error
+}
+
+// API implements the role manager facade, which manages the custom
+// roles that restrict the API calls users can make.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	check      BlockChecker
+	apiUser    names.UserTag
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	return NewAPI(NewStateBackend(st), common.NewBlockChecker(st), ctx.Auth())
+}
+
+// NewAPI returns a new role manager facade.
+func NewAPI(backend Backend, check BlockChecker, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	// Since we know this is a user tag (because AuthClient is true),
+	// we just do the type assertion to the UserTag.
+	apiUser, _ := authorizer.GetAuthTag().(names.UserTag)
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		check:      check,
+		apiUser:    apiUser,
+	}, nil
+}
+
+func (api *API) isSuperUser() (bool, error) {
+	return api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+}
+
+// checkCanManageRoles returns an error unless the user is a
+// controller superuser, and changes are allowed.
+func (api *API) checkCanManageRoles() error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	isSuperUser, err := api.isSuperUser()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// AddRole adds the given custom roles to the controller.
+func (api *API) AddRole(args params.AddRoles) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Roles)),
+	}
+	if err := api.checkCanManageRoles(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Roles {
+		_, err := api.backend.AddRole(arg.Name, arg.Permissions, api.apiUser)
+		if err != nil {
+			err = errors.Annotate(err, "failed to create role")
+		}
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveRole removes the named custom roles, along with their
+// assignments to users.
+func (api *API) RemoveRole(args params.RoleNames) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	if err := api.checkCanManageRoles(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, name := range args.Names {
+		result.Results[i].Error = apiservererrors.ServerError(api.backend.RemoveRole(name))
+	}
+	return result, nil
+}
+
+// Roles returns information on the named custom roles, or on all
+// roles if no names are given. Role assignments are only returned
+// to controller superusers.
+func (api *API) Roles(args params.RoleNames) (params.RoleInfoResults, error) {
+	var result params.RoleInfoResults
+	isSuperUser, err := api.isSuperUser()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	roleInfo := func(role Role) (*params.RoleInfo, error) {
+		info := &params.RoleInfo{
+			Name:        role.Name(),
+			Permissions: role.Permissions(),
+			CreatedBy:   role.CreatedBy(),
+			DateCreated: role.DateCreated(),
+		}
+		if !isSuperUser {
+			return info, nil
+		}
+		assignments, err := api.backend.RoleAssignments(role.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, a := range assignments {
+			info.Assignments = append(info.Assignments, params.RoleAssignment{
+				UserTag:   a.User.String(),
+				TargetTag: a.Target.String(),
+			})
+		}
+		return info, nil
+	}
+
+	if len(args.Names) == 0 {
+		roles, err := api.backend.AllRoles()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		result.Results = make([]params.RoleInfoResult, len(roles))
+		for i, role := range roles {
+			info, err := roleInfo(role)
+			result.Results[i] = params.RoleInfoResult{
+				Result: info,
+				Error:  apiservererrors.ServerError(err),
+			}
+		}
+		return result, nil
+	}
+
+	result.Results = make([]params.RoleInfoResult, len(args.Names))
+	for i, name := range args.Names {
+		role, err := api.backend.Role(name)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		info, err := roleInfo(role)
+		result.Results[i] = params.RoleInfoResult{
+			Result: info,
+			Error:  apiservererrors.ServerError(err),
+		}
+	}
+	return result, nil
+}
+
+// ModifyRoleAssignments assigns roles to, or unassigns roles from,
+// users on models and controllers.
+func (api *API) ModifyRoleAssignments(args params.ModifyRoleAssignments) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if err := api.checkCanManageRoles(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		result.Results[i].Error = apiservererrors.ServerError(api.modifyRoleAssignment(arg))
+	}
+	return result, nil
+}
+
+func (api *API) modifyRoleAssignment(arg params.ModifyRoleAssignment) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	target, err := names.ParseTag(arg.TargetTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch arg.Action {
+	case params.AssignRole:
+		return errors.Annotatef(api.backend.AssignRole(user, target, arg.Role), "assigning role to %q", user.Id())
+	case params.UnassignRole:
+		return errors.Annotatef(api.backend.UnassignRole(user, target), "unassigning role from %q", user.Id())
+	}
+	return errors.NotValidf("role assignment action %q", arg.Action)
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rolemanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/rolemanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type roleManagerSuite struct {
+	coretesting.BaseSuite
+
+	backend    *mockBackend
+	check      *mockBlockChecker
+	authorizer apiservertesting.FakeAuthorizer
+	api        *rolemanager.API
+}
+
+var _ = gc.Suite(&roleManagerSuite{})
+
+func (s *roleManagerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{Stub: &testing.Stub{}}
+	s.check = &mockBlockChecker{}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+	api, err := rolemanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *roleManagerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := rolemanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *roleManagerSuite) TestAddRole(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotValidf(`role permission "fly"`))
+	result, err := s.api.AddRole(params.AddRoles{
+		Roles: []params.RoleDefinition{
+			{Name: "operator", Permissions: []string{"ssh", "run-actions"}},
+			{Name: "flyer", Permissions: []string{"fly"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `failed to create role: role permission "fly" not valid`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"AddRole", []interface{}{"operator", []string{"ssh", "run-actions"}, names.NewUserTag("admin")}},
+		{"AddRole", []interface{}{"flyer", []string{"fly"}, names.NewUserTag("admin")}},
+	})
+}
+
+func (s *roleManagerSuite) TestAddRoleNotSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("alice")
+	api, err := rolemanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.AddRole(params.AddRoles{
+		Roles: []params.RoleDefinition{{Name: "operator", Permissions: []string{"ssh"}}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *roleManagerSuite) TestAddRoleBlocked(c *gc.C) {
+	s.check.err = errors.New("changes blocked")
+	_, err := s.api.AddRole(params.AddRoles{
+		Roles: []params.RoleDefinition{{Name: "operator", Permissions: []string{"ssh"}}},
+	})
+	c.Assert(err, gc.ErrorMatches, "changes blocked")
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *roleManagerSuite) TestRemoveRole(c *gc.C) {
+	result, err := s.api.RemoveRole(params.RoleNames{Names: []string{"operator"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.backend.CheckCall(c, 1, "RemoveRole", "operator")
+}
+
+func (s *roleManagerSuite) TestRoles(c *gc.C) {
+	s.backend.roles = []rolemanager.Role{&mockRole{
+		name:        "operator",
+		permissions: []string{"ssh"},
+	}}
+	s.backend.assignments = []state.RoleAssignment{{
+		User:   names.NewUserTag("alice"),
+		Target: coretesting.ModelTag,
+		Role:   "operator",
+	}}
+	result, err := s.api.Roles(params.RoleNames{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.RoleInfoResult{{
+		Result: &params.RoleInfo{
+			Name:        "operator",
+			Permissions: []string{"ssh"},
+			CreatedBy:   "admin",
+			Assignments: []params.RoleAssignment{{
+				UserTag:   "user-alice",
+				TargetTag: coretesting.ModelTag.String(),
+			}},
+		},
+	}})
+}
+
+func (s *roleManagerSuite) TestRolesNotSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("alice")
+	api, err := rolemanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.roles = []rolemanager.Role{&mockRole{
+		name:        "operator",
+		permissions: []string{"ssh"},
+	}}
+	result, err := api.Roles(params.RoleNames{Names: []string{"operator"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.RoleInfoResult{{
+		Result: &params.RoleInfo{
+			Name:        "operator",
+			Permissions: []string{"ssh"},
+			CreatedBy:   "admin",
+		},
+	}})
+	s.backend.CheckCallNames(c, "ControllerTag", "Role")
+}
+
+func (s *roleManagerSuite) TestModifyRoleAssignments(c *gc.C) {
+	result, err := s.api.ModifyRoleAssignments(params.ModifyRoleAssignments{
+		Changes: []params.ModifyRoleAssignment{{
+			Action:    params.AssignRole,
+			Role:      "operator",
+			UserTag:   "user-alice",
+			TargetTag: coretesting.ModelTag.String(),
+		}, {
+			Action:    params.UnassignRole,
+			UserTag:   "user-bob",
+			TargetTag: coretesting.ControllerTag.String(),
+		}, {
+			Action:    "promote",
+			UserTag:   "user-bob",
+			TargetTag: coretesting.ControllerTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `role assignment action "promote" not valid`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"AssignRole", []interface{}{names.NewUserTag("alice"), coretesting.ModelTag, "operator"}},
+		{"UnassignRole", []interface{}{names.NewUserTag("bob"), coretesting.ControllerTag}},
+	})
+}
+
+type mockBackend struct {
+	*testing.Stub
+	roles       []rolemanager.Role
+	assignments []state.RoleAssignment
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) AddRole(name string, permissions []string, createdBy names.UserTag) (rolemanager.Role, error) {
+	b.MethodCall(b, "AddRole", name, permissions, createdBy)
+	return &mockRole{name: name, permissions: permissions}, b.NextErr()
+}
+
+func (b *mockBackend) Role(name string) (rolemanager.Role, error) {
+	b.MethodCall(b, "Role", name)
+	for _, role := range b.roles {
+		if role.Name() == name {
+			return role, b.NextErr()
+		}
+	}
+	return nil, errors.NotFoundf("role %q", name)
+}
+
+func (b *mockBackend) AllRoles() ([]rolemanager.Role, error) {
+	b.MethodCall(b, "AllRoles")
+	return b.roles, b.NextErr()
+}
+
+func (b *mockBackend) RemoveRole(name string) error {
+	b.MethodCall(b, "RemoveRole", name)
+	return b.NextErr()
+}
+
+func (b *mockBackend) AssignRole(user names.UserTag, target names.Tag, role string) error {
+	b.MethodCall(b, "AssignRole", user, target, role)
+	return b.NextErr()
+}
+
+func (b *mockBackend) UnassignRole(user names.UserTag, target names.Tag) error {
+	b.MethodCall(b, "UnassignRole", user, target)
+	return b.NextErr()
+}
+
+func (b *mockBackend) RoleAssignments(role string) ([]state.RoleAssignment, error) {
+	b.MethodCall(b, "RoleAssignments", role)
+	return b.assignments, b.NextErr()
+}
+
+type mockRole struct {
+	name        string
+	permissions []string
+}
+
+func (r *mockRole) Name() string           { return r.name }
+func (r *mockRole) Permissions() []string  { return r.permissions }
+func (r *mockRole) CreatedBy() string      { return "admin" }
+func (r *mockRole) DateCreated() time.Time { return time.Time{} }
+
+type mockBlockChecker struct {
+	err error
+}
+
+func (c *mockBlockChecker) ChangeAllowed() error {
+	return c.err
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tokenmanager
+
+import (
+	"time"
+
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state methods used by the token manager facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	AddAPIToken(args state.APITokenArgs) (APIToken, string, error)
+	APITokens(account string) ([]APIToken, error)
+	RevokeAPIToken(id string) error
+}
+
+// APIToken defines the API token methods used by the token manager
+// facade.
+type APIToken interface {
+	Id() string
+	Account() string
+	ModelUUIDs() []string
+	Access() permission.Access
+	CreatedBy() string
+	DateCreated() time.Time
+	Expires() time.Time
+}
+
+type stateShim struct {
+	*state.State
+}
+
+// NewStateBackend returns a Backend backed by the given state.
+func NewStateBackend(st *state.State) Backend {
+	return stateShim{st}
+}
+
+func (s stateShim) AddAPIToken(args state.APITokenArgs) (APIToken, string, error) {
+	token, value, err := s.State.AddAPIToken(args)
+	if err != nil {
+		return nil, "", err
+	}
+	return token, value, nil
+}
+
+func (s stateShim) APITokens(account string) ([]APIToken, error) {
+	tokens, err := s.State.APITokens(account)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]APIToken, len(tokens))
+	for i, token := range tokens {
+		result[i] = token
+	}
+	return result, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tokenmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// BlockChecker defines the block checks used by the token manager
+// facade.
+type BlockChecker interface {
+	ChangeAllowed() error
+}
+
+// API implements the token manager facade, which issues and revokes
+// the API tokens used by service accounts to log in.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	check      BlockChecker
+	apiUser    names.UserTag
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	return NewAPI(NewStateBackend(st), common.NewBlockChecker(st), ctx.Auth())
+}
+
+// NewAPI returns a new token manager facade. Only controller
+// superusers can use it.
+func NewAPI(backend Backend, check BlockChecker, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	isSuperUser, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isSuperUser {
+		return nil, apiservererrors.ErrPerm
+	}
+	// Since we know this is a user tag (because AuthClient is true),
+	// we just do the type assertion to the UserTag.
+	apiUser, _ := authorizer.GetAuthTag().(names.UserTag)
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		check:      check,
+		apiUser:    apiUser,
+	}, nil
+}
+
+// AddAPIToken issues API tokens to service accounts. The value of
+// each token is only ever returned here.
+func (api *API) AddAPIToken(args params.AddAPITokens) (params.AddAPITokenResults, error) {
+	result := params.AddAPITokenResults{
+		Results: make([]params.AddAPITokenResult, len(args.Tokens)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Tokens {
+		token, value, err := api.addAPIToken(arg)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(errors.Annotate(err, "failed to create API token"))
+			continue
+		}
+		result.Results[i].Token = value
+		result.Results[i].Info = apiTokenInfo(token)
+	}
+	return result, nil
+}
+
+func (api *API) addAPIToken(arg params.AddAPIToken) (APIToken, string, error) {
+	tokenArgs := state.APITokenArgs{
+		Account:   arg.Account,
+		Access:    permission.Access(arg.Access),
+		Expires:   arg.Expires,
+		CreatedBy: api.apiUser,
+	}
+	for _, tag := range arg.ModelTags {
+		modelTag, err := names.ParseModelTag(tag)
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		tokenArgs.Models = append(tokenArgs.Models, modelTag)
+	}
+	return api.backend.AddAPIToken(tokenArgs)
+}
+
+// APITokens returns information on the API tokens issued to the
+// service account, or on all tokens if no account is given.
+func (api *API) APITokens(args params.APITokenFilter) (params.APITokensResult, error) {
+	var result params.APITokensResult
+	tokens, err := api.backend.APITokens(args.Account)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Tokens = make([]params.APITokenInfo, len(tokens))
+	for i, token := range tokens {
+		result.Tokens[i] = *apiTokenInfo(token)
+	}
+	return result, nil
+}
+
+// RevokeAPIToken revokes the API tokens with the given IDs.
+func (api *API) RevokeAPIToken(args params.APITokenIDs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.IDs)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, id := range args.IDs {
+		result.Results[i].Error = apiservererrors.ServerError(api.backend.RevokeAPIToken(id))
+	}
+	return result, nil
+}
+
+func apiTokenInfo(token APIToken) *params.APITokenInfo {
+	info := &params.APITokenInfo{
+		ID:          token.Id(),
+		Account:     token.Account(),
+		Access:      string(token.Access()),
+		CreatedBy:   token.CreatedBy(),
+		DateCreated: token.DateCreated(),
+		Expires:     token.Expires(),
+	}
+	for _, uuid := range token.ModelUUIDs() {
+		info.ModelTags = append(info.ModelTags, names.NewModelTag(uuid).String())
+	}
+	return info
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tokenmanager_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/tokenmanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type tokenManagerSuite struct {
+	coretesting.BaseSuite
+
+	backend    *mockBackend
+	check      *mockBlockChecker
+	authorizer apiservertesting.FakeAuthorizer
+	api        *tokenmanager.API
+	expires    time.Time
+}
+
+var _ = gc.Suite(&tokenManagerSuite{})
+
+func (s *tokenManagerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.expires = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.backend = &mockBackend{Stub: &testing.Stub{}, expires: s.expires}
+	s.check = &mockBlockChecker{}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+	api, err := tokenmanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+	s.backend.ResetCalls()
+}
+
+func (s *tokenManagerSuite) TestNewAPINotSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("alice")
+	_, err := tokenmanager.NewAPI(s.backend, s.check, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *tokenManagerSuite) TestAddAPIToken(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotValidf(`service account name "not/valid"`))
+	result, err := s.api.AddAPIToken(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			Account:   "ci",
+			ModelTags: []string{coretesting.ModelTag.String()},
+			Access:    "write",
+			Expires:   s.expires,
+		}, {
+			Account:   "not/valid",
+			ModelTags: []string{coretesting.ModelTag.String()},
+			Access:    "read",
+			Expires:   s.expires,
+		}, {
+			Account:   "ci",
+			ModelTags: []string{"machine-0"},
+			Access:    "read",
+			Expires:   s.expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Token, gc.Equals, "jujutoken.1234.secret")
+	c.Assert(result.Results[0].Info, jc.DeepEquals, &params.APITokenInfo{
+		ID:        "1234",
+		Account:   "ci",
+		ModelTags: []string{coretesting.ModelTag.String()},
+		Access:    "write",
+		CreatedBy: "admin",
+		Expires:   s.expires,
+	})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `failed to create API token: service account name "not/valid" not valid`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `failed to create API token: "machine-0" is not a valid model tag`)
+	s.backend.CheckCallNames(c, "AddAPIToken", "AddAPIToken")
+	s.backend.CheckCall(c, 0, "AddAPIToken", state.APITokenArgs{
+		Account:   "ci",
+		Models:    []names.ModelTag{coretesting.ModelTag},
+		Access:    permission.WriteAccess,
+		Expires:   s.expires,
+		CreatedBy: names.NewUserTag("admin"),
+	})
+}
+
+func (s *tokenManagerSuite) TestAddAPITokenBlocked(c *gc.C) {
+	s.check.err = errors.New("changes blocked")
+	_, err := s.api.AddAPIToken(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{Account: "ci"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "changes blocked")
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *tokenManagerSuite) TestAPITokens(c *gc.C) {
+	result, err := s.api.APITokens(params.APITokenFilter{Account: "ci"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tokens, jc.DeepEquals, []params.APITokenInfo{{
+		ID:        "1234",
+		Account:   "ci",
+		ModelTags: []string{coretesting.ModelTag.String()},
+		Access:    "read",
+		CreatedBy: "admin",
+		Expires:   s.expires,
+	}})
+	s.backend.CheckCall(c, 0, "APITokens", "ci")
+}
+
+func (s *tokenManagerSuite) TestRevokeAPIToken(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotFoundf(`API token "5678"`))
+	result, err := s.api.RevokeAPIToken(params.APITokenIDs{IDs: []string{"1234", "5678"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `API token "5678" not found`)
+	s.backend.CheckCall(c, 0, "RevokeAPIToken", "1234")
+	s.backend.CheckCall(c, 1, "RevokeAPIToken", "5678")
+}
+
+type mockBackend struct {
+	*testing.Stub
+	expires time.Time
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) AddAPIToken(args state.APITokenArgs) (tokenmanager.APIToken, string, error) {
+	b.MethodCall(b, "AddAPIToken", args)
+	if err := b.NextErr(); err != nil {
+		return nil, "", err
+	}
+	token := &mockAPIToken{
+		account: args.Account,
+		access:  args.Access,
+		expires: args.Expires,
+	}
+	for _, model := range args.Models {
+		token.models = append(token.models, model.Id())
+	}
+	return token, "jujutoken.1234.secret", nil
+}
+
+func (b *mockBackend) APITokens(account string) ([]tokenmanager.APIToken, error) {
+	b.MethodCall(b, "APITokens", account)
+	return []tokenmanager.APIToken{&mockAPIToken{
+		account: "ci",
+		models:  []string{coretesting.ModelTag.Id()},
+		access:  permission.ReadAccess,
+		expires: b.expires,
+	}}, b.NextErr()
+}
+
+func (b *mockBackend) RevokeAPIToken(id string) error {
+	b.MethodCall(b, "RevokeAPIToken", id)
+	return b.NextErr()
+}
+
+type mockAPIToken struct {
+	account string
+	models  []string
+	access  permission.Access
+	expires time.Time
+}
+
+func (t *mockAPIToken) Id() string                { return "1234" }
+func (t *mockAPIToken) Account() string           { return t.account }
+func (t *mockAPIToken) ModelUUIDs() []string      { return t.models }
+func (t *mockAPIToken) Access() permission.Access { return t.access }
+func (t *mockAPIToken) CreatedBy() string         { return "admin" }
+func (t *mockAPIToken) DateCreated() time.Time    { return time.Time{} }
+func (t *mockAPIToken) Expires() time.Time        { return t.expires }
+
+type mockBlockChecker struct {
+	err error
+}
+
+func (c *mockBlockChecker) ChangeAllowed() error {
+	return c.err
+}
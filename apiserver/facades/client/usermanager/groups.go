@@ -0,0 +1,303 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// AddGroup adds the named groups to the controller.
+func (api *UserManagerAPI) AddGroup(args params.Groups) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	if err := api.checkCanManageGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, name := range args.Names {
+		_, err := api.state.AddGroup(name, api.apiUser)
+		if err != nil {
+			err = errors.Annotate(err, "failed to create group")
+		}
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveGroup removes the named groups, along with all the
+// access granted to them.
+func (api *UserManagerAPI) RemoveGroup(args params.Groups) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	if err := api.checkCanManageGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, name := range args.Names {
+		result.Results[i].Error = apiservererrors.ServerError(api.state.RemoveGroup(name))
+	}
+	return result, nil
+}
+
+// GroupInfo returns information on the named groups. If no names
+// are given, all groups are returned. Users that aren't controller
+// superusers can only see the groups they are a member of.
+func (api *UserManagerAPI) GroupInfo(args params.Groups) (params.GroupInfoResults, error) {
+	var result params.GroupInfoResults
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	visible := func(g *state.Group) bool {
+		if isSuperUser {
+			return true
+		}
+		for _, member := range g.Members() {
+			if member.Id() == api.apiUser.Id() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(args.Names) == 0 {
+		groups, err := api.state.AllGroups()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		for _, g := range groups {
+			if visible(g) {
+				result.Results = append(result.Results, params.GroupInfoResult{
+					Result: groupInfo(g),
+				})
+			}
+		}
+		return result, nil
+	}
+
+	result.Results = make([]params.GroupInfoResult, len(args.Names))
+	for i, name := range args.Names {
+		g, err := api.state.Group(name)
+		if err == nil && !visible(g) {
+			err = apiservererrors.ErrPerm
+		}
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = groupInfo(g)
+	}
+	return result, nil
+}
+
+func groupInfo(g *state.Group) *params.GroupInfo {
+	members := g.Members()
+	info := &params.GroupInfo{
+		Name:        g.Name(),
+		CreatedBy:   g.CreatedBy(),
+		DateCreated: g.DateCreated(),
+		Members:     make([]string, len(members)),
+	}
+	for i, member := range members {
+		info.Members[i] = member.String()
+	}
+	return info
+}
+
+// ModifyGroupMembers adds users to, or removes users from, groups.
+func (api *UserManagerAPI) ModifyGroupMembers(args params.ModifyGroupMembers) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if err := api.checkCanManageGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		result.Results[i].Error = apiservererrors.ServerError(api.modifyGroupMember(arg))
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyGroupMember(arg params.ModifyGroupMember) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch arg.Action {
+	case params.AddGroupMember:
+		return errors.Annotatef(api.state.AddGroupMembers(arg.Group, user), "adding %q to group", user.Id())
+	case params.RemoveGroupMember:
+		return errors.Annotatef(api.state.RemoveGroupMembers(arg.Group, user), "removing %q from group", user.Id())
+	}
+	return errors.NotValidf("group member action %q", arg.Action)
+}
+
+// ModifyGroupAccess grants or revokes the access of groups to
+// controllers, clouds, models and offers.
+func (api *UserManagerAPI) ModifyGroupAccess(args params.ModifyGroupAccessRequest) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if err := api.checkCanManageGroups(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := api.modifyGroupAccess(arg)
+		if err != nil {
+			err = errors.Annotate(err, "could not modify group access")
+		}
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) modifyGroupAccess(arg params.ModifyGroupAccess) error {
+	access := permission.Access(arg.Access)
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	st := api.state
+	var target names.Tag
+	if arg.OfferURL != "" {
+		offerState, offerTag, release, err := api.offerState(arg.OfferURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer release()
+		st, target = offerState, offerTag
+	} else {
+		var err error
+		if target, err = names.ParseTag(arg.TargetTag); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	current, err := st.GroupAccess(arg.Group, target)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	switch arg.Action {
+	case params.GrantGroupAccess:
+		// Only set access if greater access is being granted.
+		if current != permission.NoAccess && !greaterAccess(target.Kind(), access, current) {
+			return errors.Errorf("group already has %q access or greater", current)
+		}
+		return errors.Trace(st.SetGroupAccess(arg.Group, target, access))
+	case params.RevokeGroupAccess:
+		if current == permission.NoAccess {
+			return errors.Errorf("group %q has no access to %s", arg.Group, names.ReadableString(target))
+		}
+		if greaterAccess(target.Kind(), access, current) {
+			return errors.Errorf("group %q does not have %q access", arg.Group, access)
+		}
+		lower := lowerAccess(target.Kind(), access)
+		if lower == permission.NoAccess {
+			return errors.Trace(st.RemoveGroupAccess(arg.Group, target))
+		}
+		return errors.Trace(st.SetGroupAccess(arg.Group, target, lower))
+	}
+	return errors.NotValidf("group access action %q", arg.Action)
+}
+
+// accessLevels returns the access levels for the kind of target,
+// from lowest to highest.
+func accessLevels(kind string) []permission.Access {
+	switch kind {
+	case names.ControllerTagKind:
+		return []permission.Access{permission.LoginAccess, permission.SuperuserAccess}
+	case names.CloudTagKind:
+		return []permission.Access{permission.AddModelAccess, permission.AdminAccess}
+	case names.ModelTagKind:
+		return []permission.Access{permission.ReadAccess, permission.WriteAccess, permission.AdminAccess}
+	case names.ApplicationOfferTagKind:
+		return []permission.Access{permission.ReadAccess, permission.ConsumeAccess, permission.AdminAccess}
+	}
+	return nil
+}
+
+// greaterAccess reports whether access a is greater than access b
+// for the kind of target.
+func greaterAccess(kind string, a, b permission.Access) bool {
+	ia, ib := -1, -1
+	for i, level := range accessLevels(kind) {
+		if level == a {
+			ia = i
+		}
+		if level == b {
+			ib = i
+		}
+	}
+	return ia > ib
+}
+
+// lowerAccess returns the access level below the given one for the
+// kind of target, which is what remains when the access is revoked.
+func lowerAccess(kind string, access permission.Access) permission.Access {
+	levels := accessLevels(kind)
+	for i, level := range levels {
+		if level == access && i > 0 {
+			return levels[i-1]
+		}
+	}
+	return permission.NoAccess
+}
+
+// offerState returns the state of the model hosting the offer with
+// the given URL, along with the offer's tag.
+func (api *UserManagerAPI) offerState(offerURL string) (*state.State, names.Tag, func(), error) {
+	if api.pool == nil {
+		return nil, nil, nil, errors.NotSupportedf("offer access for groups")
+	}
+	url, err := crossmodel.ParseOfferURL(offerURL)
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	owner := url.User
+	if owner == "" {
+		owner = api.apiUser.Id()
+	}
+	uuids, err := api.state.AllModelUUIDs()
+	if err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	for _, uuid := range uuids {
+		st, err := api.pool.Get(uuid)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		model, err := st.Model()
+		if err != nil {
+			st.Release()
+			return nil, nil, nil, errors.Trace(err)
+		}
+		if model.Name() == url.ModelName && model.Owner().Id() == owner {
+			return st.State, names.NewApplicationOfferTag(url.ApplicationName), func() { st.Release() }, nil
+		}
+		st.Release()
+	}
+	return nil, nil, nil, errors.NotFoundf("model %s/%s", owner, url.ModelName)
+}
+
+// checkCanManageGroups returns an error unless the user is a
+// controller superuser, and changes are allowed.
+func (api *UserManagerAPI) checkCanManageGroups() error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperUser {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package usermanager_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+func (s *userManagerSuite) TestAddGroup(c *gc.C) {
+	result, err := s.usermanager.AddGroup(params.Groups{Names: []string{"engineers", "not/valid"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `failed to create group: group name "not/valid" not valid`)
+
+	group, err := s.State.Group("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.CreatedBy(), gc.Equals, s.adminName)
+}
+
+func (s *userManagerSuite) TestAddGroupNotSuperuser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"})
+	s.authorizer.Tag = user.UserTag()
+	api, err := usermanager.NewUserManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.AddGroup(params.Groups{Names: []string{"engineers"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestAddGroupBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestAddGroupBlocked")
+	_, err := s.usermanager.AddGroup(params.Groups{Names: []string{"engineers"}})
+	s.AssertBlocked(c, err, "TestAddGroupBlocked")
+}
+
+func (s *userManagerSuite) TestGroupMembersAndInfo(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	_, err := s.State.AddGroup("engineers", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddGroup("testers", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.usermanager.ModifyGroupMembers(params.ModifyGroupMembers{
+		Changes: []params.ModifyGroupMember{{
+			Action:  params.AddGroupMember,
+			Group:   "engineers",
+			UserTag: alice.String(),
+		}, {
+			Action:  params.AddGroupMember,
+			Group:   "engineers",
+			UserTag: "user-nobody",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `adding "nobody" to group: .*`)
+
+	info, err := s.usermanager.GroupInfo(params.Groups{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 2)
+	c.Assert(info.Results[0].Result.Name, gc.Equals, "engineers")
+	c.Assert(info.Results[0].Result.Members, jc.DeepEquals, []string{alice.String()})
+
+	// Users that aren't superusers only see their own groups.
+	s.authorizer.Tag = alice
+	api, err := usermanager.NewUserManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = api.GroupInfo(params.Groups{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results, gc.HasLen, 1)
+	c.Assert(info.Results[0].Result.Name, gc.Equals, "engineers")
+	info, err = api.GroupInfo(params.Groups{Names: []string{"testers"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestModifyGroupAccess(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", NoModelUser: true}).UserTag()
+	_, err := s.State.AddGroup("engineers", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.AddGroupMembers("engineers", alice), jc.ErrorIsNil)
+	modelTag := s.Model.ModelTag()
+
+	modify := func(action params.GroupAccessAction, access permission.Access) error {
+		result, err := s.usermanager.ModifyGroupAccess(params.ModifyGroupAccessRequest{
+			Changes: []params.ModifyGroupAccess{{
+				Action:    action,
+				Group:     "engineers",
+				Access:    string(access),
+				TargetTag: modelTag.String(),
+			}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		return result.OneError()
+	}
+
+	c.Assert(modify(params.GrantGroupAccess, permission.WriteAccess), jc.ErrorIsNil)
+	access, err := s.State.UserPermission(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	err = modify(params.GrantGroupAccess, permission.ReadAccess)
+	c.Assert(err, gc.ErrorMatches, `could not modify group access: group already has "write" access or greater`)
+
+	// Revoking write access leaves read access.
+	c.Assert(modify(params.RevokeGroupAccess, permission.WriteAccess), jc.ErrorIsNil)
+	access, err = s.State.GroupAccess("engineers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ReadAccess)
+
+	err = modify(params.RevokeGroupAccess, permission.AdminAccess)
+	c.Assert(err, gc.ErrorMatches, `could not modify group access: group "engineers" does not have "admin" access`)
+
+	c.Assert(modify(params.RevokeGroupAccess, permission.ReadAccess), jc.ErrorIsNil)
+	_, err = s.State.UserPermission(alice, names.NewModelTag(modelTag.Id()))
+	c.Assert(err, gc.ErrorMatches, `.*not found`)
+}
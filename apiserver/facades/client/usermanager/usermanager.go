@@ -28,8 +28,57 @@ type UserManagerAPI struct {
 	check      *common.BlockChecker
 	apiUser    names.UserTag
 	isAdmin    bool
+
+	// pool is used to find the models hosting offers. It is nil
+	// for facade versions without group support.
+	pool *state.StatePool
+}
+
+// UserManagerAPIV2 implements the user manager V2 API. It lacks
+// the group methods added in V3.
+type UserManagerAPIV2 struct {
+	*UserManagerAPI
 }
 
+// NewUserManagerAPIV3 creates a new instance of the V3 UserManager API,
+// which adds groups.
+func NewUserManagerAPIV3(ctx facade.Context) (*UserManagerAPI, error) {
+	api, err := NewUserManagerAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.pool = ctx.StatePool()
+	return api, nil
+}
+
+// NewUserManagerAPIV2 creates a new instance of the V2 UserManager API.
+func NewUserManagerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIV2, error) {
+	api, err := NewUserManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV2{api}, nil
+}
+
+// AddGroup is not available in V2.
+func (*UserManagerAPIV2) AddGroup(_, _ struct{}) {}
+
+// RemoveGroup is not available in V2.
+func (*UserManagerAPIV2) RemoveGroup(_, _ struct{}) {}
+
+// GroupInfo is not available in V2.
+func (*UserManagerAPIV2) GroupInfo(_, _ struct{}) {}
+
+// ModifyGroupMembers is not available in V2.
+func (*UserManagerAPIV2) ModifyGroupMembers(_, _ struct{}) {}
+
+// ModifyGroupAccess is not available in V2.
+func (*UserManagerAPIV2) ModifyGroupAccess(_, _ struct{}) {}
+
 // NewUserManagerAPI provides the signature required for facade registration.
 func NewUserManagerAPI(
 	st *state.State,
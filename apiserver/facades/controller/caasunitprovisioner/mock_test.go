@@ -87,7 +87,10 @@ func (st *mockState) ResolveConstraints(cons constraints.Value) (constraints.Val
 
 func (m *mockState) AllSpaceInfos() (network.SpaceInfos, error) {
 	m.MethodCall(m, "AllSpaceInfos")
-	return network.SpaceInfos{}, nil
+	return network.SpaceInfos{
+		{ID: network.AlphaSpaceId, Name: network.AlphaSpaceName},
+		{ID: "1", Name: "telco"},
+	}, nil
 }
 
 type mockModel struct {
@@ -152,6 +155,7 @@ type mockApplication struct {
 	providerId string
 	addresses  []network.SpaceAddress
 	charm      *mockCharm
+	bindings   map[string]string
 }
 
 func (a *mockApplication) Tag() names.Tag {
@@ -194,6 +198,27 @@ func (a *mockApplication) CharmModifiedVersion() int {
 	return 888
 }
 
+func (a *mockApplication) EndpointBindings() (caasunitprovisioner.Bindings, error) {
+	a.MethodCall(a, "EndpointBindings")
+	return &mockBindings{bindings: a.bindings}, nil
+}
+
+type mockBindings struct {
+	bindings map[string]string
+}
+
+func (b *mockBindings) MapWithSpaceNames(spaceInfos network.SpaceInfos) (map[string]string, error) {
+	result := make(map[string]string)
+	for endpoint, spaceID := range b.bindings {
+		space := spaceInfos.GetByID(spaceID)
+		if space == nil {
+			return nil, errors.NotFoundf("space with ID %q", spaceID)
+		}
+		result[endpoint] = string(space.Name)
+	}
+	return result, nil
+}
+
 func (a *mockApplication) StorageConstraints() (map[string]state.StorageConstraints, error) {
 	return map[string]state.StorageConstraints{
 		"data": {
@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/cloudconfig/podcfg"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tags"
@@ -350,6 +351,10 @@ func (f *Facade) provisioningInfo(model Model, tagString string) (*params.Kubern
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	bindings, err := f.endpointBindings(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	resourceTags := tags.ResourceTags(
 		names.NewModelTag(modelConfig.UUID()),
 		names.NewControllerTag(controllerCfg.ControllerUUID()),
@@ -370,6 +375,7 @@ func (f *Facade) provisioningInfo(model Model, tagString string) (*params.Kubern
 		Tags:                 resourceTags,
 		OperatorImagePath:    operatorImagePath,
 		CharmModifiedVersion: app.CharmModifiedVersion(),
+		EndpointBindings:     bindings,
 	}
 	deployInfo := ch.Meta().Deployment
 	if deployInfo != nil {
@@ -381,6 +387,20 @@ func (f *Facade) provisioningInfo(model Model, tagString string) (*params.Kubern
 	return info, nil
 }
 
+// endpointBindings returns the names of the spaces to which
+// each of the application's endpoints is bound.
+func (f *Facade) endpointBindings(app Application) (map[string]string, error) {
+	bindings, err := app.EndpointBindings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaceInfos, err := f.state.AllSpaceInfos()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return bindings.MapWithSpaceNames(spaceInfos)
+}
+
 func filesystemParams(
 	app Application,
 	cons state.StorageConstraints,
@@ -820,6 +840,38 @@ type volumeInfo struct {
 	volumeId   string
 }
 
+// cloudPodSpaceInfos returns the model's spaces if any of the cloud pods
+// report addresses on secondary networks.
+func (a *Facade) cloudPodSpaceInfos(unitInfo *updateStateUnitParams) (network.SpaceInfos, error) {
+	for _, pods := range [][]params.ApplicationUnitParams{unitInfo.addedCloudPods, unitInfo.existingCloudPods} {
+		for _, p := range pods {
+			if len(p.SpaceAddresses) > 0 {
+				return a.state.AllSpaceInfos()
+			}
+		}
+	}
+	return nil, nil
+}
+
+// podSpaceAddresses converts the addresses of a pod on secondary networks,
+// keyed by space name, into addresses associated with the space IDs.
+// Addresses in unknown spaces are ignored.
+func podSpaceAddresses(spaceInfos network.SpaceInfos, addresses map[string]string) network.SpaceAddresses {
+	var result network.SpaceAddresses
+	for spaceName, value := range addresses {
+		space := spaceInfos.GetByName(spaceName)
+		if space == nil {
+			logger.Warningf("ignoring address %q in unknown space %q", value, spaceName)
+			continue
+		}
+		addr := network.NewSpaceAddress(value)
+		addr.SpaceID = space.ID
+		result = append(result, addr)
+	}
+	network.SortAddresses(result)
+	return result
+}
+
 func (a *Facade) updateStateUnits(app Application, unitInfo *updateStateUnitParams) error {
 
 	if app.Life() != state.Alive {
@@ -878,11 +930,18 @@ func (a *Facade) updateStateUnits(app Application, unitInfo *updateStateUnitPara
 			u.UpdateOperation(updateProps))
 	}
 
+	spaceInfos, err := a.cloudPodSpaceInfos(unitInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	processUnitParams := func(unitParams params.ApplicationUnitParams) *state.UnitUpdateProperties {
 		agentStatus, cloudContainerStatus := a.updateStatus(unitParams)
+		spaceAddresses := podSpaceAddresses(spaceInfos, unitParams.SpaceAddresses)
 		return &state.UnitUpdateProperties{
 			ProviderId:           &unitParams.ProviderId,
 			Address:              &unitParams.Address,
+			SpaceAddresses:       &spaceAddresses,
 			Ports:                &unitParams.Ports,
 			AgentStatus:          agentStatus,
 			CloudContainerStatus: cloudContainerStatus,
@@ -1025,7 +1084,7 @@ func (a *Facade) updateStateUnits(app Application, unitInfo *updateStateUnitPara
 		unitUpdate.Adds = append(unitUpdate.Adds,
 			app.AddOperation(*updateProps))
 	}
-	err := app.UpdateUnits(&unitUpdate)
+	err = app.UpdateUnits(&unitUpdate)
 	// We ignore any updates for dying applications.
 	if stateerrors.IsNotAlive(err) {
 		return nil
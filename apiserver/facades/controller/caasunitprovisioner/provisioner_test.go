@@ -70,6 +70,10 @@ func (s *CAASProvisionerSuite) SetUpTest(c *gc.C) {
 			life:         state.Alive,
 			scaleWatcher: statetesting.NewMockNotifyWatcher(s.scaleChanges),
 			scale:        5,
+			bindings: map[string]string{
+				"":         network.AlphaSpaceId,
+				"database": "1",
+			},
 		},
 		applicationsWatcher: statetesting.NewMockStringsWatcher(s.applicationsChanges),
 		model: mockModel{
@@ -275,12 +279,16 @@ func (s *CAASProvisionerSuite) assertProvisioningInfo(c *gc.C, isRawK8sSpec bool
 	c.Assert(obtained.Devices, jc.DeepEquals, expectedResult.Devices)
 	c.Assert(obtained.Constraints, jc.DeepEquals, expectedResult.Constraints)
 	c.Assert(obtained.Tags, jc.DeepEquals, expectedResult.Tags)
+	c.Assert(obtained.EndpointBindings, jc.DeepEquals, map[string]string{
+		"":         network.AlphaSpaceName,
+		"database": "telco",
+	})
 	c.Assert(results.Results[1], jc.DeepEquals, params.KubernetesProvisioningInfoResult{
 		Error: &params.Error{
 			Message: `"unit-gitlab-0" is not a valid application tag`,
 		},
 	})
-	s.st.CheckCallNames(c, "Model", "Application", "ControllerConfig", "ResolveConstraints")
+	s.st.CheckCallNames(c, "Model", "Application", "ControllerConfig", "ResolveConstraints", "AllSpaceInfos")
 	s.st.CheckCall(c, 3, "ResolveConstraints", constraints.MustParse("mem=64G"))
 	s.storagePoolManager.CheckCallNames(c, "Get", "Get")
 }
@@ -459,6 +467,7 @@ func (s *CAASProvisionerSuite) assertUpdateApplicationsStatelessUnits(c *gc.C, w
 	s.st.application.CheckCall(c, 1, "AddOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("really-new-uuid"),
 		Address:    strPtr("really-new-address"), Ports: &[]string{"really-new-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "really new message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Idle},
 	})
@@ -467,6 +476,7 @@ func (s *CAASProvisionerSuite) assertUpdateApplicationsStatelessUnits(c *gc.C, w
 	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("uuid"),
 		Address:    strPtr("address"), Ports: &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: ""},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating},
 	})
@@ -475,6 +485,7 @@ func (s *CAASProvisionerSuite) assertUpdateApplicationsStatelessUnits(c *gc.C, w
 	s.st.application.units[1].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("another-uuid"),
 		Address:    strPtr("another-address"), Ports: &[]string{"another-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: "another message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating, Message: "another message"},
 	})
@@ -487,6 +498,7 @@ func (s *CAASProvisionerSuite) assertUpdateApplicationsStatelessUnits(c *gc.C, w
 	s.st.application.units[3].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("new-uuid"),
 		Address:    strPtr("new-address"), Ports: &[]string{"new-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "new message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Idle},
 	})
@@ -534,6 +546,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsScaleChange(c *gc.C) {
 	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("uuid"),
 		Address:    strPtr("address"), Ports: &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: ""},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating},
 	})
@@ -542,6 +555,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsScaleChange(c *gc.C) {
 	s.st.application.units[1].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("another-uuid"),
 		Address:    strPtr("another-address"), Ports: &[]string{"another-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: "another message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating, Message: "another message"},
 	})
@@ -585,6 +599,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnknownScale(c *gc.C) {
 	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("uuid"),
 		Address:    strPtr("address"), Ports: &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: ""},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating},
 	})
@@ -593,6 +608,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnknownScale(c *gc.C) {
 	s.st.application.units[1].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("another-uuid"),
 		Address:    strPtr("another-address"), Ports: &[]string{"another-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Waiting, Message: "another message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Allocating, Message: "another message"},
 	})
@@ -636,6 +652,40 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsNotAlive(c *gc.C) {
 	s.st.application.units[2].(*mockUnit).CheckCallNames(c, "Life")
 }
 
+func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsWithSpaceAddresses(c *gc.C) {
+	s.st.application.units = []caasunitprovisioner.Unit{
+		&mockUnit{name: "gitlab/0", containerInfo: &mockContainerInfo{providerId: "uuid"}, life: state.Alive},
+	}
+	s.st.application.scale = 1
+
+	units := []params.ApplicationUnitParams{
+		{ProviderId: "uuid", Address: "address", Ports: []string{"port"},
+			SpaceAddresses: map[string]string{"telco": "10.10.0.5", "unknown": "10.20.0.5"},
+			Status:         "running", Info: "message"},
+	}
+	args := params.UpdateApplicationUnitArgs{
+		Args: []params.UpdateApplicationUnits{
+			{ApplicationTag: "application-gitlab", Units: units, Scale: intPtr(1), Generation: int64Ptr(1)},
+		},
+	}
+	results, err := s.facade.UpdateApplicationsUnits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.st.CheckCall(c, 1, "AllSpaceInfos")
+
+	expectedAddr := network.NewSpaceAddress("10.10.0.5")
+	expectedAddr.SpaceID = "1"
+	s.st.application.units[0].(*mockUnit).CheckCallNames(c, "Life", "UpdateOperation")
+	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
+		ProviderId:           strPtr("uuid"),
+		Address:              strPtr("address"),
+		Ports:                &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{expectedAddr},
+		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "message"},
+		AgentStatus:          &status.StatusInfo{Status: status.Idle},
+	})
+}
+
 func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsWithStorage(c *gc.C) {
 	s.st.application.units = []caasunitprovisioner.Unit{
 		&mockUnit{name: "gitlab/0", containerInfo: &mockContainerInfo{providerId: "uuid"}, life: state.Alive},
@@ -701,6 +751,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsWithStorage(c *gc.C) {
 	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("uuid"),
 		Address:    strPtr("address"), Ports: &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Idle},
 	})
@@ -708,6 +759,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsWithStorage(c *gc.C) {
 	s.st.application.units[1].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("another-uuid"),
 		Address:    strPtr("another-address"), Ports: &[]string{"another-port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "another message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Idle},
 	})
@@ -835,6 +887,7 @@ func (s *CAASProvisionerSuite) TestUpdateApplicationsUnitsWithStorageNoBackingVo
 	s.st.application.units[0].(*mockUnit).CheckCall(c, 1, "UpdateOperation", state.UnitUpdateProperties{
 		ProviderId: strPtr("uuid"),
 		Address:    strPtr("address"), Ports: &[]string{"port"},
+		SpaceAddresses:       &network.SpaceAddresses{},
 		CloudContainerStatus: &status.StatusInfo{Status: status.Running, Message: "message"},
 		AgentStatus:          &status.StatusInfo{Status: status.Idle},
 	})
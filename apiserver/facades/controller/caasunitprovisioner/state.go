@@ -92,6 +92,13 @@ type Application interface {
 	Charm() (Charm, bool, error)
 	ClearResources() error
 	CharmModifiedVersion() int
+	EndpointBindings() (Bindings, error)
+}
+
+// Bindings provides the subset of endpoint binding state
+// required by the CAAS unit provisioner facade.
+type Bindings interface {
+	MapWithSpaceNames(network.SpaceInfos) (map[string]string, error)
 }
 
 type stateShim struct {
@@ -134,6 +141,10 @@ func (a applicationShim) Charm() (Charm, bool, error) {
 	return a.Application.Charm()
 }
 
+func (a applicationShim) EndpointBindings() (Bindings, error) {
+	return a.Application.EndpointBindings()
+}
+
 type Charm interface {
 	Meta() *charm.Meta
 }
@@ -54,6 +54,12 @@ type FirewallerAPIV5 struct {
 	*FirewallerAPIV4
 }
 
+// FirewallerAPIV6 provides access to the Firewaller v6 API facade.
+// It adds the GetExposeInfo and WatchSpaceSubnets methods.
+type FirewallerAPIV6 struct {
+	*FirewallerAPIV5
+}
+
 // NewStateFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -97,6 +103,17 @@ func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV6 creates a new server-side FirewallerAPIV6 facade.
+func NewStateFirewallerAPIV6(context facade.Context) (*FirewallerAPIV6, error) {
+	facadev5, err := NewStateFirewallerAPIV5(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV6{
+		FirewallerAPIV5: facadev5,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -253,10 +270,12 @@ func (f *FirewallerAPIV3) GetMachinePorts(args params.MachinePortsParams) (param
 			var rangeList []params.MachinePortRange
 			for unitName, portRanges := range openedPortsInSubnet.PortRangesByUnit() {
 				unitTag := names.NewUnitTag(unitName).String()
+				endpoints := openedPortsInSubnet.PortRangeEndpointsForUnit(unitName)
 				for _, pr := range portRanges {
 					rangeList = append(rangeList, params.MachinePortRange{
 						UnitTag:   unitTag,
 						PortRange: params.FromNetworkPortRange(pr),
+						Endpoint:  endpoints[pr],
 					})
 				}
 			}
@@ -337,6 +356,76 @@ func (f *FirewallerAPIV3) GetExposed(args params.Entities) (params.BoolResults,
 	return result, nil
 }
 
+// GetExposeInfo returns the exposed flag and the per-endpoint expose
+// settings for each given application. The spaces in the settings are
+// resolved into the CIDRs of their subnets.
+func (f *FirewallerAPIV6) GetExposeInfo(args params.Entities) (params.ExposeInfoResults, error) {
+	result := params.ExposeInfoResults{
+		Results: make([]params.ExposeInfoResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.ExposeInfoResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Exposed = application.IsExposed()
+		exposedEndpoints, err := f.exposedEndpoints(application.ExposedEndpoints())
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].ExposedEndpoints = exposedEndpoints
+	}
+	return result, nil
+}
+
+// WatchSpaceSubnets returns a NotifyWatcher which triggers whenever the
+// subnets of the model's spaces change, and with them the CIDRs returned
+// by GetExposeInfo for applications exposed to spaces.
+func (f *FirewallerAPIV6) WatchSpaceSubnets() (params.NotifyWatchResult, error) {
+	watch := f.st.WatchSpaceSubnets()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: f.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: apiservererrors.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
+
+func (f *FirewallerAPIV3) exposedEndpoints(in map[string]state.ExposedEndpoint) (map[string]params.ExposedEndpoint, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]params.ExposedEndpoint, len(in))
+	for endpoint, exposed := range in {
+		cidrs := append([]string(nil), exposed.ExposeToCIDRs...)
+		for _, spaceID := range exposed.ExposeToSpaceIDs {
+			subnetCIDRs, err := f.st.SpaceSubnetCIDRs(spaceID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			cidrs = append(cidrs, subnetCIDRs...)
+		}
+		out[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaceIDs: exposed.ExposeToSpaceIDs,
+			ExposeToCIDRs:    cidrs,
+		}
+	}
+	return out, nil
+}
+
 // GetAssignedMachine returns the assigned machine tag (if any) for
 // each given unit.
 func (f *FirewallerAPIV3) GetAssignedMachine(args params.Entities) (params.StringResults, error) {
@@ -98,6 +98,84 @@ func (s *firewallerSuite) TestGetExposed(c *gc.C) {
 	s.testGetExposed(c, s.firewaller)
 }
 
+func (s *firewallerSuite) TestGetExposeInfo(c *gc.C) {
+	space, err := s.State.AddSpace("dmz", "", []string{s.subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"":   {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"db": {ExposeToSpaceIDs: []string{space.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	apiv6 := &firewaller.FirewallerAPIV6{
+		&firewaller.FirewallerAPIV5{
+			&firewaller.FirewallerAPIV4{
+				FirewallerAPIV3: s.firewaller,
+			}}}
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	result, err := apiv6.GetExposeInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposeInfoResults{
+		Results: []params.ExposeInfoResult{
+			{
+				Exposed: true,
+				ExposedEndpoints: map[string]params.ExposedEndpoint{
+					"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+					"db": {
+						ExposeToSpaceIDs: []string{space.Id()},
+						ExposeToCIDRs:    []string{s.subnet.CIDR()},
+					},
+				},
+			},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`application "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Unexposing the application clears its expose settings.
+	err = s.application.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = apiv6.GetExposeInfo(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposeInfoResults{
+		Results: []params.ExposeInfoResult{{}},
+	})
+}
+
+func (s *firewallerSuite) TestWatchSpaceSubnets(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	apiv6 := &firewaller.FirewallerAPIV6{
+		&firewaller.FirewallerAPIV5{
+			&firewaller.FirewallerAPIV4{
+				FirewallerAPIV3: s.firewaller,
+			}}}
+	result, err := apiv6.WatchSpaceSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	// Moving a subnet into a space changes the CIDRs of the space.
+	_, err = s.State.AddSpace("dmz", "", []string{s.subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *firewallerSuite) TestGetAssignedMachine(c *gc.C) {
 	s.testGetAssignedMachine(c, s.firewaller)
 }
@@ -204,6 +282,32 @@ func (s *firewallerSuite) TestGetMachinePorts(c *gc.C) {
 
 }
 
+func (s *firewallerSuite) TestGetMachinePortsOnEndpoint(c *gc.C) {
+	op, err := s.units[0].OpenClosePortsOnEndpointsOperation(map[string][]network.PortRange{
+		"url": {network.MustParsePortRange("80/tcp")},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.MachinePortsParams{
+		Params: []params.MachinePorts{
+			{MachineTag: s.machines[0].Tag().String(), SubnetTag: ""},
+		},
+	}
+	result, err := s.firewaller.GetMachinePorts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachinePortsResults{
+		Results: []params.MachinePortsResult{{
+			Ports: []params.MachinePortRange{{
+				UnitTag:   s.units[0].Tag().String(),
+				PortRange: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				Endpoint:  "url",
+			}},
+		}},
+	})
+}
+
 func (s *firewallerSuite) TestGetMachineActiveSubnets(c *gc.C) {
 	s.openPorts(c)
 
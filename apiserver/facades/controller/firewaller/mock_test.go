@@ -127,6 +127,16 @@ func (st *mockState) Subnet(id string) (firewaller.Subnet, error) {
 	return nil, errors.NotImplementedf("Subnet")
 }
 
+func (st *mockState) WatchSpaceSubnets() state.NotifyWatcher {
+	st.MethodCall(st, "WatchSpaceSubnets")
+	// TODO - implement when remaining firewaller tests become unit tests
+	return nil
+}
+
+func (st *mockState) SpaceSubnetCIDRs(spaceID string) ([]string, error) {
+	return nil, errors.NotImplementedf("SpaceSubnetCIDRs")
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
@@ -4,6 +4,7 @@
 package firewaller
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"gopkg.in/macaroon.v2"
 
@@ -30,6 +31,10 @@ type State interface {
 	Subnet(id string) (Subnet, error)
 
 	SubnetByCIDR(cidr string) (Subnet, error)
+
+	SpaceSubnetCIDRs(spaceID string) ([]string, error)
+
+	WatchSpaceSubnets() state.NotifyWatcher
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
@@ -76,3 +81,27 @@ func (st stateShim) Subnet(id string) (Subnet, error) {
 func (st stateShim) SubnetByCIDR(cidr string) (Subnet, error) {
 	return st.st.SubnetByCIDR(cidr)
 }
+
+// WatchSpaceSubnets returns a NotifyWatcher which triggers whenever
+// the subnets of the model's spaces change.
+func (st stateShim) WatchSpaceSubnets() state.NotifyWatcher {
+	return st.st.WatchSpaceSubnets()
+}
+
+// SpaceSubnetCIDRs returns the CIDRs of the subnets in the space
+// with the specified ID.
+func (st stateShim) SpaceSubnetCIDRs(spaceID string) ([]string, error) {
+	space, err := st.st.Space(spaceID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cidrs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		cidrs[i] = subnet.CIDR()
+	}
+	return cidrs, nil
+}
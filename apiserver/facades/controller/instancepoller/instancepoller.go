@@ -354,6 +354,12 @@ func (a *InstancePollerAPI) SetInstanceStatus(args params.SetStatus) (params.Err
 	}
 	for i, arg := range args.Entities {
 		machine, err := a.getOneMachine(arg.Tag, canAccess)
+		var wasInterrupting bool
+		if err == nil && status.Status(arg.Status) == status.Running {
+			var current status.StatusInfo
+			current, err = machine.InstanceStatus()
+			wasInterrupting = current.Status == status.Interrupting
+		}
 		if err == nil {
 			now := a.clock.Now()
 			s := status.StatusInfo{
@@ -369,12 +375,68 @@ func (a *InstancePollerAPI) SetInstanceStatus(args params.SetStatus) (params.Err
 					err = machine.SetStatus(s)
 				}
 			}
+			if err == nil {
+				switch status.Status(arg.Status) {
+				case status.Interrupting:
+					err = a.machineInterrupting(machine)
+				case status.Interrupted:
+					err = a.machineInterrupted(machine, s)
+				case status.Running:
+					if wasInterrupting {
+						err = a.machineNotInterrupting(machine)
+					}
+				}
+			}
 		}
 		result.Results[i].Error = apiservererrors.ServerError(err)
 	}
 	return result, nil
 }
 
+// machineInterrupting records that the cloud has given notice that it
+// will reclaim the machine's spot or preemptible instance on each of
+// the units on the machine, so that their agents run the stop hooks
+// while the instance is still running. The units are left in place.
+func (a *InstancePollerAPI) machineInterrupting(machine StateMachine) error {
+	units, err := machine.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		logger.Infof("stopping unit %q on interrupting machine %q", unit.Name(), machine.Id())
+		if err := unit.SetInterrupting(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// machineNotInterrupting records that the cloud has withdrawn its
+// notice that it will reclaim the machine's instance, so that the agents
+// of the units on the machine start them again.
+func (a *InstancePollerAPI) machineNotInterrupting(machine StateMachine) error {
+	units, err := machine.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		logger.Infof("starting unit %q on machine %q no longer interrupting", unit.Name(), machine.Id())
+		if err := unit.ClearInterrupting(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// machineInterrupted records that the cloud has reclaimed the
+// machine's spot or preemptible instance. The units on the machine are
+// left in place, for the operator to remove or move.
+func (a *InstancePollerAPI) machineInterrupted(machine StateMachine, s status.StatusInfo) error {
+	s.Status = status.Error
+	s.Message = "instance interrupted by the cloud"
+	return errors.Trace(machine.SetStatus(s))
+}
+
 // AreManuallyProvisioned returns whether each given entity is
 // manually provisioned or not. Only machine tags are accepted.
 func (a *InstancePollerAPI) AreManuallyProvisioned(args params.Entities) (params.BoolResults, error) {
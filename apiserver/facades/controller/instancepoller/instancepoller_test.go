@@ -692,6 +692,99 @@ func (s *InstancePollerSuite) TestSetInstanceStatusFailure(c *gc.C) {
 	s.st.CheckMachineCall(c, 3, "3")
 }
 
+func (s *InstancePollerSuite) TestSetInstanceStatusInterrupting(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{
+		id:             "1",
+		instanceStatus: statusInfo("running"),
+		units: []instancepoller.StateUnit{
+			&mockUnit{Stub: s.st.Stub, name: "mysql/0"},
+			&mockUnit{Stub: s.st.Stub, name: "logging/0"},
+		},
+	})
+
+	result, err := s.api.SetInstanceStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "machine-1", Status: "interrupting", Info: "spot instance termination notice"},
+		}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	// The units are asked to stop, but not destroyed.
+	s.st.CheckCallNames(c, "Machine", "SetInstanceStatus", "Units", "Id", "SetInterrupting", "Id", "SetInterrupting")
+}
+
+func (s *InstancePollerSuite) TestSetInstanceStatusNoLongerInterrupting(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{
+		id:             "1",
+		instanceStatus: statusInfo("interrupting"),
+		units: []instancepoller.StateUnit{
+			&mockUnit{Stub: s.st.Stub, name: "mysql/0"},
+		},
+	})
+	s.st.SetMachineInfo(c, machineInfo{
+		id:             "2",
+		instanceStatus: statusInfo("pending"),
+		units: []instancepoller.StateUnit{
+			&mockUnit{Stub: s.st.Stub, name: "logging/0"},
+		},
+	})
+
+	result, err := s.api.SetInstanceStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "machine-1", Status: "running"},
+			{Tag: "machine-2", Status: "running"},
+		}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {}},
+	})
+
+	// Only the units of the machine that was interrupting are
+	// started again.
+	s.st.CheckCallNames(c,
+		"Machine", "InstanceStatus", "SetInstanceStatus", "Units", "Id", "ClearInterrupting",
+		"Machine", "InstanceStatus", "SetInstanceStatus",
+	)
+}
+
+func (s *InstancePollerSuite) TestSetInstanceStatusInterrupted(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{
+		id:             "1",
+		instanceStatus: statusInfo("running"),
+		units: []instancepoller.StateUnit{
+			&mockUnit{Stub: s.st.Stub, name: "mysql/0"},
+		},
+	})
+
+	result, err := s.api.SetInstanceStatus(params.SetStatus{
+		Entities: []params.EntityStatusArgs{
+			{Tag: "machine-1", Status: "interrupted", Info: "spot instance reclaimed"},
+		}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+
+	// The units are left in place.
+	now := s.clock.Now()
+	s.st.CheckCallNames(c, "Machine", "SetInstanceStatus", "SetStatus")
+	s.st.CheckCall(c, 1, "SetInstanceStatus", status.StatusInfo{
+		Status:  status.Interrupted,
+		Message: "spot instance reclaimed",
+		Since:   &now,
+	})
+	s.st.CheckCall(c, 2, "SetStatus", status.StatusInfo{
+		Status:  status.Error,
+		Message: "instance interrupted by the cloud",
+		Since:   &now,
+	})
+}
+
 func (s *InstancePollerSuite) TestAreManuallyProvisionedSuccess(c *gc.C) {
 	s.st.SetMachineInfo(c, machineInfo{id: "1", isManual: true})
 	s.st.SetMachineInfo(c, machineInfo{id: "2", isManual: false})
@@ -271,6 +271,7 @@ type machineInfo struct {
 	providerAddresses []network.SpaceAddress
 	life              state.Life
 	isManual          bool
+	units             []instancepoller.StateUnit
 
 	// See package_mock_test.go for these mocks.
 	linkLayerDevices []networkingcommon.LinkLayerDevice
@@ -385,6 +386,31 @@ func (m *mockMachine) SetInstanceStatus(instanceStatus status.StatusInfo) error
 	return nil
 }
 
+// SetStatus implements StateMachine.
+func (m *mockMachine) SetStatus(machineStatus status.StatusInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "SetStatus", machineStatus)
+	if err := m.NextErr(); err != nil {
+		return err
+	}
+	m.status = machineStatus
+	return nil
+}
+
+// Units implements StateMachine.
+func (m *mockMachine) Units() ([]instancepoller.StateUnit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "Units")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.units, nil
+}
+
 // Life implements StateMachine.
 func (m *mockMachine) Life() state.Life {
 	m.mu.Lock()
@@ -422,6 +448,31 @@ func (m *mockMachine) AssertAliveOp() txn.Op {
 	return txn.Op{C: "machine-alive"}
 }
 
+type mockUnit struct {
+	*testing.Stub
+
+	name string
+}
+
+var _ instancepoller.StateUnit = (*mockUnit)(nil)
+
+// Name implements StateUnit.
+func (u *mockUnit) Name() string {
+	return u.name
+}
+
+// SetInterrupting implements StateUnit.
+func (u *mockUnit) SetInterrupting() error {
+	u.MethodCall(u, "SetInterrupting")
+	return u.NextErr()
+}
+
+// ClearInterrupting implements StateUnit.
+func (u *mockUnit) ClearInterrupting() error {
+	u.MethodCall(u, "ClearInterrupting")
+	return u.NextErr()
+}
+
 type mockBaseWatcher struct {
 	err error
 
@@ -27,6 +27,14 @@ type StateMachine interface {
 	Life() state.Life
 	Status() (status.StatusInfo, error)
 	IsManual() (bool, error)
+	Units() ([]StateUnit, error)
+}
+
+// StateUnit represents a unit from state package.
+type StateUnit interface {
+	Name() string
+	SetInterrupting() error
+	ClearInterrupting() error
 }
 
 type StateInterface interface {
@@ -73,6 +81,20 @@ func (s machineShim) AllAddresses() ([]networkingcommon.LinkLayerAddress, error)
 	return out, nil
 }
 
+func (s machineShim) Units() ([]StateUnit, error) {
+	units, err := s.Machine.Units()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]StateUnit, len(units))
+	for i, unit := range units {
+		out[i] = unit
+	}
+
+	return out, nil
+}
+
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
 // removed once all relevant methods are moved from state to model.
 type stateShim struct {
@@ -125,17 +125,24 @@ func (api *API) MigrationStatus() (params.MasterMigrationStatus, error) {
 	if err != nil {
 		return empty, errors.Annotate(err, "marshalling macaroons")
 	}
+	var targetInfo params.MigrationTargetInfo
+	if target.ControllerTag.Id() != "" {
+		// The target of an offline migration is only known once the
+		// model has been imported.
+		targetInfo = params.MigrationTargetInfo{
+			ControllerTag: target.ControllerTag.String(),
+			Addrs:         target.Addrs,
+			CACert:        target.CACert,
+			AuthTag:       target.AuthTag.String(),
+			Password:      target.Password,
+			Macaroons:     string(macsJSON),
+		}
+	}
 	return params.MasterMigrationStatus{
 		Spec: params.MigrationSpec{
-			ModelTag: names.NewModelTag(mig.ModelUUID()).String(),
-			TargetInfo: params.MigrationTargetInfo{
-				ControllerTag: target.ControllerTag.String(),
-				Addrs:         target.Addrs,
-				CACert:        target.CACert,
-				AuthTag:       target.AuthTag.String(),
-				Password:      target.Password,
-				Macaroons:     string(macsJSON),
-			},
+			ModelTag:   names.NewModelTag(mig.ModelUUID()).String(),
+			TargetInfo: targetInfo,
+			Offline:    mig.Offline(),
 		},
 		MigrationId:      mig.Id(),
 		Phase:            phase.String(),
@@ -126,6 +126,7 @@ func (s *Suite) TestMigrationStatus(c *gc.C) {
 	exp.Phase().Return(coremigration.IMPORT, nil)
 	exp.ModelUUID().Return(s.modelUUID)
 	exp.Id().Return("ID")
+	exp.Offline().Return(false)
 	now := time.Now()
 	exp.PhaseChangedTime().Return(now)
 
@@ -153,6 +154,37 @@ func (s *Suite) TestMigrationStatus(c *gc.C) {
 	})
 }
 
+func (s *Suite) TestMigrationStatusOfflineNoTarget(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+
+	mig := mocks.NewMockModelMigration(ctrl)
+	exp := mig.EXPECT()
+	exp.TargetInfo().Return(&coremigration.TargetInfo{}, nil)
+	exp.Phase().Return(coremigration.IMPORT, nil)
+	exp.ModelUUID().Return(s.modelUUID)
+	exp.Id().Return("ID")
+	exp.Offline().Return(true)
+	now := time.Now()
+	exp.PhaseChangedTime().Return(now)
+
+	s.backend.EXPECT().LatestMigration().Return(mig, nil)
+
+	api := s.mustMakeAPI(c)
+	status, err := api.MigrationStatus()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(status, gc.DeepEquals, params.MasterMigrationStatus{
+		Spec: params.MigrationSpec{
+			ModelTag: names.NewModelTag(s.modelUUID).String(),
+			Offline:  true,
+		},
+		MigrationId:      "ID",
+		Phase:            "IMPORT",
+		PhaseChangedTime: now,
+	})
+}
+
 func (s *Suite) TestModelInfo(c *gc.C) {
 	defer s.setupMocks(c).Finish()
 
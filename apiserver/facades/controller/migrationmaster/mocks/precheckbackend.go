@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	charm "github.com/juju/charm/v7"
 	migration "github.com/juju/juju/migration"
 	resource "github.com/juju/juju/resource"
 	state "github.com/juju/juju/state"
@@ -113,6 +114,21 @@ func (mr *MockPrecheckBackendMockRecorder) AllRelations() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllRelations", reflect.TypeOf((*MockPrecheckBackend)(nil).AllRelations))
 }
 
+// Charm mocks base method
+func (m *MockPrecheckBackend) Charm(arg0 *charm.URL) (migration.PrecheckCharm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Charm", arg0)
+	ret0, _ := ret[0].(migration.PrecheckCharm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Charm indicates an expected call of Charm
+func (mr *MockPrecheckBackendMockRecorder) Charm(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Charm", reflect.TypeOf((*MockPrecheckBackend)(nil).Charm), arg0)
+}
+
 // CloudCredential mocks base method
 func (m *MockPrecheckBackend) CloudCredential(arg0 names_v3.CloudCredentialTag) (state.Credential, error) {
 	m.ctrl.T.Helper()
@@ -128,6 +144,21 @@ func (mr *MockPrecheckBackendMockRecorder) CloudCredential(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudCredential", reflect.TypeOf((*MockPrecheckBackend)(nil).CloudCredential), arg0)
 }
 
+// ExportBlockers mocks base method
+func (m *MockPrecheckBackend) ExportBlockers() ([]state.ExportBlocker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBlockers")
+	ret0, _ := ret[0].([]state.ExportBlocker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBlockers indicates an expected call of ExportBlockers
+func (mr *MockPrecheckBackendMockRecorder) ExportBlockers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBlockers", reflect.TypeOf((*MockPrecheckBackend)(nil).ExportBlockers))
+}
+
 // ControllerBackend mocks base method
 func (m *MockPrecheckBackend) ControllerBackend() (migration.PrecheckBackend, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +219,21 @@ func (mr *MockPrecheckBackendMockRecorder) ListPendingResources(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingResources", reflect.TypeOf((*MockPrecheckBackend)(nil).ListPendingResources), arg0)
 }
 
+// ListResources mocks base method
+func (m *MockPrecheckBackend) ListResources(arg0 string) (resource.ApplicationResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResources", arg0)
+	ret0, _ := ret[0].(resource.ApplicationResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResources indicates an expected call of ListResources
+func (mr *MockPrecheckBackendMockRecorder) ListResources(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockPrecheckBackend)(nil).ListResources), arg0)
+}
+
 // Model mocks base method
 func (m *MockPrecheckBackend) Model() (migration.PrecheckModel, error) {
 	m.ctrl.T.Helper()
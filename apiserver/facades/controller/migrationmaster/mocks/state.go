@@ -138,6 +138,20 @@ func (mr *MockModelMigrationMockRecorder) ModelUserAccess(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelUserAccess", reflect.TypeOf((*MockModelMigration)(nil).ModelUserAccess), arg0)
 }
 
+// Offline mocks base method
+func (m *MockModelMigration) Offline() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Offline")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Offline indicates an expected call of Offline
+func (mr *MockModelMigrationMockRecorder) Offline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Offline", reflect.TypeOf((*MockModelMigration)(nil).Offline))
+}
+
 // Phase mocks base method
 func (m *MockModelMigration) Phase() (migration.Phase, error) {
 	m.ctrl.T.Helper()
@@ -209,6 +223,20 @@ func (mr *MockModelMigrationMockRecorder) SetStatusMessage(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatusMessage", reflect.TypeOf((*MockModelMigration)(nil).SetStatusMessage), arg0)
 }
 
+// SetTarget mocks base method
+func (m *MockModelMigration) SetTarget(arg0 migration.TargetInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTarget", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTarget indicates an expected call of SetTarget
+func (mr *MockModelMigrationMockRecorder) SetTarget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTarget", reflect.TypeOf((*MockModelMigration)(nil).SetTarget), arg0)
+}
+
 // StartTime mocks base method
 func (m *MockModelMigration) StartTime() time.Time {
 	m.ctrl.T.Helper()
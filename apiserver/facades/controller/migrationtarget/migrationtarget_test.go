@@ -4,6 +4,7 @@
 package migrationtarget_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"time"
 
@@ -142,6 +143,29 @@ func (s *Suite) TestPrechecksFail(c *gc.C) {
 	c.Assert(err, gc.NotNil)
 }
 
+func (s *Suite) TestPrecheckReport(c *gc.C) {
+	controllerVersion := s.controllerVersion(c)
+
+	// Set the model version ahead of the controller.
+	modelVersion := controllerVersion
+	modelVersion.Minor++
+
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           modelVersion,
+		ControllerAgentVersion: controllerVersion,
+	}
+	result, err := api.PrecheckReport(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Failures, jc.DeepEquals, []params.MigrationPrecheckFailure{{
+		Entity:  "model",
+		Message: fmt.Sprintf("model has higher version than target controller (%s > %s)", modelVersion, controllerVersion),
+	}})
+}
+
 func (s *Suite) TestImport(c *gc.C) {
 	api := s.mustNewAPI(c)
 	tag := s.importModel(c, api)
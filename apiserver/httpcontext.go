@@ -7,15 +7,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -59,9 +62,34 @@ func (ctxt *httpContext) stateForRequestAuthenticated(r *http.Request) (
 			st.Release()
 		}
 	}()
+	if err := checkTokenModelScope(authInfo.Entity, st.ModelUUID()); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	return st, authInfo.Entity, nil
 }
 
+// entityHasPermission returns true if the authenticated entity can
+// perform <operation> on <target>. Like apiHandler.HasPermission, it
+// limits entities authenticated with an API token to the token's scope.
+func entityHasPermission(
+	st *state.State, entity httpcontext.Entity, operation permission.Access, target names.Tag,
+) (bool, error) {
+	if tokenEntity, ok := entity.(*authentication.APITokenEntity); ok && !tokenEntity.Token.Allows(operation, target) {
+		return false, nil
+	}
+	return common.HasPermission(st.UserPermission, entity.Tag(), operation, target)
+}
+
+// checkTokenModelScope returns an error if the entity was
+// authenticated with an API token that isn't scoped to the model.
+func checkTokenModelScope(entity httpcontext.Entity, modelUUID string) error {
+	tokenEntity, ok := entity.(*authentication.APITokenEntity)
+	if !ok || tokenEntity.Token.Allows(permission.ReadAccess, names.NewModelTag(modelUUID)) {
+		return nil
+	}
+	return errors.Annotatef(apiservererrors.ErrPerm, "API token not valid for model %q", modelUUID)
+}
+
 // checkPermissions verifies that given tag passes authentication check.
 // For example, if only user tags are accepted, all other tags will be denied access.
 func checkPermissions(tag names.Tag, acceptFunc common.GetAuthFunc) (bool, error) {
@@ -156,6 +184,68 @@ func (ctxt *httpContext) stateForRequestAuthenticatedTag(r *http.Request, kinds
 	return st, entity, nil
 }
 
+// checkRole returns an error if the authenticated entity is a user
+// with a custom role that doesn't allow requests to the named HTTP
+// endpoint with the request's HTTP method.
+func (ctxt *httpContext) checkRole(r *http.Request, entity httpcontext.Entity, endpoint string) error {
+	userTag, ok := entity.Tag().(names.UserTag)
+	if !ok {
+		return nil
+	}
+	st, err := ctxt.stateForRequestUnauthenticated(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	modelTag := names.NewModelTag(st.ModelUUID())
+	role, err := userRole(st.State, userTag, &modelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if role == nil {
+		return nil
+	}
+	method := httpRoleMethod(r.Method, endpoint)
+	if endpoint == "" || !role.Allows(permission.HTTPFacade, method) {
+		return errors.Annotatef(apiservererrors.ErrPerm,
+			"role %q does not allow %s.%s", role.Name, permission.HTTPFacade, method)
+	}
+	return nil
+}
+
+// httpRoleMethod returns the method name under which a request to
+// the named HTTP endpoint is checked against roles.
+func httpRoleMethod(httpMethod, endpoint string) string {
+	if httpMethod == "" {
+		return endpoint
+	}
+	return strings.ToUpper(httpMethod[:1]) + strings.ToLower(httpMethod[1:]) + endpoint
+}
+
+// roleCheckHandler is an http.Handler that denies authenticated
+// requests from users whose custom role doesn't allow the endpoint.
+type roleCheckHandler struct {
+	http.Handler
+	ctxt httpContext
+	// endpoint names the endpoint in role permissions. Users with a
+	// custom role are always denied if it's empty.
+	endpoint string
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *roleCheckHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if authInfo, ok := httpcontext.RequestAuthInfo(req); ok {
+		if err := h.ctxt.checkRole(req, authInfo.Entity, h.endpoint); err != nil {
+			http.Error(w,
+				fmt.Sprintf("authorization failed: %s", err),
+				http.StatusForbidden,
+			)
+			return
+		}
+	}
+	h.Handler.ServeHTTP(w, req)
+}
+
 // stop returns a channel which will be closed when a handler should
 // exit.
 func (ctxt *httpContext) stop() <-chan struct{} {
@@ -221,11 +311,10 @@ type controllerAdminAuthorizer struct {
 
 // Authorize is part of the httpcontext.Authorizer interface.
 func (a controllerAdminAuthorizer) Authorize(authInfo httpcontext.AuthInfo) error {
-	userTag, ok := authInfo.Entity.Tag().(names.UserTag)
-	if !ok {
+	if _, ok := authInfo.Entity.Tag().(names.UserTag); !ok {
 		return errors.Errorf("%s is not a user", names.ReadableString(authInfo.Entity.Tag()))
 	}
-	admin, err := a.st.IsControllerAdmin(userTag)
+	admin, err := entityHasPermission(a.st, authInfo.Entity, permission.SuperuserAccess, a.st.ControllerTag())
 	if err != nil {
 		return errors.Trace(err)
 	}
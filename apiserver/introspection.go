@@ -6,7 +6,6 @@ package apiserver
 import (
 	"net/http"
 
-	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/names/v4"
@@ -41,9 +40,9 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 	// or "read" access on the controller model, can
 	// access these endpoints.
 
-	ok, err := common.HasPermission(
-		st.UserPermission,
-		entity.Tag(),
+	ok, err := entityHasPermission(
+		st.State,
+		entity,
 		permission.SuperuserAccess,
 		st.ControllerTag(),
 	)
@@ -54,9 +53,9 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 		return nil
 	}
 
-	ok, err = common.HasPermission(
-		st.UserPermission,
-		entity.Tag(),
+	ok, err = entityHasPermission(
+		st.State,
+		entity,
 		permission.ReadAccess,
 		names.NewModelTag(st.ControllerModelUUID()),
 	)
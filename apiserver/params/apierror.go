@@ -123,6 +123,23 @@ func (e RedirectErrorInfo) AsMap() map[string]interface{} {
 	return serializeToMap(e)
 }
 
+// OIDCLoginRequiredErrorInfo provides additional information for
+// OIDCLoginRequired errors.
+type OIDCLoginRequiredErrorInfo struct {
+	// IssuerURL holds the URL of the OpenID Connect issuer
+	// trusted by the controller.
+	IssuerURL string `json:"issuer-url"`
+
+	// ClientID holds the client ID that ID tokens must be
+	// issued to.
+	ClientID string `json:"client-id"`
+}
+
+// AsMap encodes the error info as a map that can be attached to an Error.
+func (e OIDCLoginRequiredErrorInfo) AsMap() map[string]interface{} {
+	return serializeToMap(e)
+}
+
 // serializeToMap is a convenience function for marshaling v into a
 // map[string]interface{}. It works by marshalling v into json and then
 // unmarshaling back to a map.
@@ -186,6 +203,8 @@ const (
 	CodeCloudRegionRequired       = "cloud region required"
 	CodeIncompatibleClouds        = "incompatible clouds"
 	CodeQuotaLimitExceeded        = "quota limit exceeded"
+	CodeOIDCLoginRequired         = "oidc login required"
+	CodePlacementPolicyViolated   = "placement policy violated"
 )
 
 // ErrCode returns the error code associated with
@@ -348,6 +367,10 @@ func IsRedirect(err error) bool {
 	return ErrCode(err) == CodeRedirect
 }
 
+func IsCodeOIDCLoginRequired(err error) bool {
+	return ErrCode(err) == CodeOIDCLoginRequired
+}
+
 func IsCodeIncompatibleSeries(err error) bool {
 	return ErrCode(err) == CodeIncompatibleSeries
 }
@@ -360,6 +383,12 @@ func IsCodeCloudRegionRequired(err error) bool {
 	return ErrCode(err) == CodeCloudRegionRequired
 }
 
+// IsCodePlacementPolicyViolated returns true if err includes a
+// PlacementPolicyViolated error code.
+func IsCodePlacementPolicyViolated(err error) bool {
+	return ErrCode(err) == CodePlacementPolicyViolated
+}
+
 // IsCodeQuotaLimitExceeded returns true if err includes a QuotaLimitExceeded
 // error code.
 func IsCodeQuotaLimitExceeded(err error) bool {
@@ -23,6 +23,7 @@ type ApplicationDeploy struct {
 	Series           string                         `json:"series"`
 	CharmURL         string                         `json:"charm-url"`
 	Channel          string                         `json:"channel"`
+	CharmOrigin      *CharmOrigin                   `json:"charm-origin,omitempty"`
 	NumUnits         int                            `json:"num-units"`
 	Config           map[string]string              `json:"config,omitempty"`
 	ConfigYAML       string                         `json:"config-yaml"` // Takes precedence over config if both are present.
@@ -36,6 +37,20 @@ type ApplicationDeploy struct {
 	Resources        map[string]string              `json:"resources,omitempty"`
 }
 
+// CharmOrigin records where a charm added to the model as a local
+// charm was obtained from, e.g. charmhub, so that the application
+// can later be refreshed from the same source.
+type CharmOrigin struct {
+	// Source is where the charm was obtained from, e.g. "charmhub".
+	Source string `json:"source"`
+
+	// ID is the charm's ID in the source.
+	ID string `json:"id"`
+
+	// Revision is the charm's revision in the source.
+	Revision int `json:"revision"`
+}
+
 // ApplicationsDeployV5 holds the parameters for deploying one or more applications.
 type ApplicationsDeployV5 struct {
 	Applications []ApplicationDeployV5 `json:"applications"`
@@ -115,6 +130,11 @@ type ApplicationSetCharm struct {
 	// Channel is the charm store channel from which the charm came.
 	Channel string `json:"channel"`
 
+	// CharmOrigin records where the charm came from, when that can't
+	// be told from its URL. This field is only understood by
+	// Application facade version 15 and greater.
+	CharmOrigin *CharmOrigin `json:"charm-origin,omitempty"`
+
 	// ConfigSettings is the charm settings to set during the upgrade.
 	// This field is only understood by Application facade version 2
 	// and greater.
@@ -154,6 +174,61 @@ type ApplicationSetCharm struct {
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints maps endpoint names to the sources allowed to
+	// access them, to be merged with any existing expose settings. An
+	// empty endpoint name applies to all of the application's endpoints.
+	// This field is only understood by Application facade version 13
+	// and greater.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
+
+// ExposedEndpoint describes the sources allowed to access the ports
+// opened by the units of an exposed application endpoint.
+type ExposedEndpoint struct {
+	// ExposeToSpaces lists the names of the spaces allowed access.
+	// It is set by clients of the Application facade.
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty"`
+
+	// ExposeToSpaceIDs lists the IDs of the spaces allowed access.
+	// It is set by the Firewaller facade.
+	ExposeToSpaceIDs []string `json:"expose-to-space-ids,omitempty"`
+
+	// ExposeToCIDRs lists the CIDRs allowed access.
+	ExposeToCIDRs []string `json:"expose-to-cidrs,omitempty"`
+}
+
+// ApplicationPlacementPolicy holds the placement policy of an
+// application.
+type ApplicationPlacementPolicy struct {
+	ApplicationTag string `json:"application-tag"`
+
+	// AntiAffinity lists the applications whose units must never
+	// share a host with the units of the application.
+	AntiAffinity []string `json:"anti-affinity,omitempty"`
+
+	// Strategy is "spread", "pack", or empty for the default
+	// placement of units.
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// ApplicationPlacementPolicies holds the placement policies to set
+// on applications.
+type ApplicationPlacementPolicies struct {
+	Policies []ApplicationPlacementPolicy `json:"policies"`
+}
+
+// ApplicationPlacementPolicyResult holds the placement policy of an
+// application, or an error.
+type ApplicationPlacementPolicyResult struct {
+	Result *ApplicationPlacementPolicy `json:"result,omitempty"`
+	Error  *Error                      `json:"error,omitempty"`
+}
+
+// ApplicationPlacementPolicyResults holds the results of a
+// PlacementPolicies call.
+type ApplicationPlacementPolicyResults struct {
+	Results []ApplicationPlacementPolicyResult `json:"results"`
 }
 
 // ApplicationSet holds the parameters for an application Set
@@ -206,6 +281,7 @@ type ApplicationGetResults struct {
 	Constraints       constraints.Value      `json:"constraints"`
 	Series            string                 `json:"series"`
 	Channel           string                 `json:"channel"`
+	CharmOrigin       *CharmOrigin           `json:"charm-origin,omitempty"`
 	EndpointBindings  map[string]string      `json:"endpoint-bindings,omitempty"`
 }
 
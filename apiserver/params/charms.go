@@ -53,6 +53,87 @@ type IsMeteredResult struct {
 	Metered bool `json:"metered"`
 }
 
+// PublishLocalCharmArgs holds the uploaded local charms to publish to the
+// controller's charm repository.
+type PublishLocalCharmArgs struct {
+	Args []PublishLocalCharmArg `json:"args"`
+}
+
+// PublishLocalCharmArg holds an uploaded local charm, and the pending IDs
+// of the resources uploaded to store with it, keyed on resource name.
+type PublishLocalCharmArg struct {
+	URL       string            `json:"url"`
+	Resources map[string]string `json:"resources,omitempty"`
+}
+
+// AddRepositoryResourcesArgs holds the charms from the controller's charm
+// repository whose resources are to be added to applications.
+type AddRepositoryResourcesArgs struct {
+	Args []AddRepositoryResourcesArg `json:"args"`
+}
+
+// AddRepositoryResourcesArg holds a charm from the controller's charm
+// repository, the application to add its resources to as pending
+// resources, and the names of resources not to add.
+type AddRepositoryResourcesArg struct {
+	URL             string   `json:"url"`
+	ApplicationName string   `json:"application-name"`
+	Exclude         []string `json:"exclude,omitempty"`
+}
+
+// AddRepositoryResourcesResults holds the results of an
+// AddRepositoryResources call.
+type AddRepositoryResourcesResults struct {
+	Results []AddRepositoryResourcesResult `json:"results"`
+}
+
+// AddRepositoryResourcesResult holds the pending IDs of the resources
+// added to an application, keyed on resource name.
+type AddRepositoryResourcesResult struct {
+	PendingIDs map[string]string `json:"pending-ids,omitempty"`
+	Error      *Error            `json:"error,omitempty"`
+}
+
+// ReleaseLocalCharmArgs holds the local charms to release to channels of
+// the controller's charm repository.
+type ReleaseLocalCharmArgs struct {
+	Args []ReleaseLocalCharmArg `json:"args"`
+}
+
+// ReleaseLocalCharmArg holds a local charm published to the controller's
+// charm repository and the channel to release it to.
+type ReleaseLocalCharmArg struct {
+	URL     string `json:"url"`
+	Channel string `json:"channel"`
+}
+
+// ResolveLocalCharmArgs holds the local charms to resolve from the
+// controller's charm repository.
+type ResolveLocalCharmArgs struct {
+	Args []ResolveLocalCharmArg `json:"args"`
+}
+
+// ResolveLocalCharmArg identifies a local charm by name and series, and
+// the channel to resolve its revision from.
+type ResolveLocalCharmArg struct {
+	Name    string `json:"name"`
+	Series  string `json:"series"`
+	Channel string `json:"channel"`
+}
+
+// ResolveLocalCharmResults holds the results of a ResolveLocalCharms call.
+type ResolveLocalCharmResults struct {
+	Results []ResolveLocalCharmResult `json:"results"`
+}
+
+// ResolveLocalCharmResult holds the URL of a resolved local charm and the
+// channel it was released to.
+type ResolveLocalCharmResult struct {
+	URL     string `json:"url,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Error   *Error `json:"error,omitempty"`
+}
+
 // CharmOption mirrors charm.Option
 type CharmOption struct {
 	Type        string      `json:"type"`
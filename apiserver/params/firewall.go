@@ -60,3 +60,23 @@ func (v KnownServiceValue) Validate() error {
 	}
 	return errors.NotValidf("known service %q", v)
 }
+
+// ExposeInfoResults holds the results of retrieving the expose
+// settings of one or more applications.
+type ExposeInfoResults struct {
+	Results []ExposeInfoResult `json:"results"`
+}
+
+// ExposeInfoResult holds the expose settings of an application.
+type ExposeInfoResult struct {
+	Error *Error `json:"error,omitempty"`
+
+	// Exposed is true when the application is exposed.
+	Exposed bool `json:"exposed,omitempty"`
+
+	// ExposedEndpoints maps endpoint names to the sources allowed to
+	// access them, with the space IDs in each ExposeToSpaceIDs resolved
+	// into the CIDRs of their subnets in ExposeToCIDRs. It is empty
+	// when an exposed application is accessible from everywhere.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
@@ -972,6 +972,10 @@ type UnitRefreshResult struct {
 	Resolved   ResolvedMode
 	Error      *Error
 	ProviderID string `json:"provider-id,omitempty"`
+
+	// Interrupting is true if the cloud has given notice that it will
+	// reclaim the instance hosting the unit.
+	Interrupting bool `json:"interrupting,omitempty"`
 }
 
 // UnitRefreshResults holds the results for any API call which ends
@@ -27,6 +27,7 @@ type KubernetesProvisioningInfo struct {
 	Devices              []KubernetesDeviceParams     `json:"devices,omitempty"`
 	OperatorImagePath    string                       `json:"operator-image-path,omitempty"`
 	CharmModifiedVersion int                          `json:"charm-modified-version,omitempty"`
+	EndpointBindings     map[string]string            `json:"endpoint-bindings,omitempty"`
 }
 
 // KubernetesProvisioningInfoResult holds unit provisioning info or an error.
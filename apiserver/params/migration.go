@@ -21,10 +21,12 @@ type InitiateMigrationArgs struct {
 }
 
 // MigrationSpec holds the details required to start the migration of
-// a single model.
+// a single model. TargetInfo is left empty for an offline migration,
+// where the model is exported to a model archive.
 type MigrationSpec struct {
 	ModelTag   string              `json:"model-tag"`
 	TargetInfo MigrationTargetInfo `json:"target-info"`
+	Offline    bool                `json:"offline,omitempty"`
 }
 
 // MigrationTargetInfo holds the details required to connect to and
@@ -53,6 +55,128 @@ type InitiateMigrationResult struct {
 	MigrationId string `json:"migration-id"`
 }
 
+// MigrationPrecheckFailure describes a migration precheck which failed.
+type MigrationPrecheckFailure struct {
+	Entity  string `json:"entity"`
+	Message string `json:"message"`
+}
+
+// MigrationPrecheckFailures holds the migration prechecks which failed
+// on a controller.
+type MigrationPrecheckFailures struct {
+	Failures []MigrationPrecheckFailure `json:"failures,omitempty"`
+}
+
+// MigrationPrecheckReports holds the results of running the migration
+// prechecks for a number of models.
+type MigrationPrecheckReports struct {
+	Results []MigrationPrecheckReport `json:"results"`
+}
+
+// MigrationPrecheckReport holds the prechecks which failed for one
+// model migration, on the source and on the target controller. Error
+// is set when the prechecks could not be run.
+type MigrationPrecheckReport struct {
+	ModelTag string                     `json:"model-tag"`
+	Source   []MigrationPrecheckFailure `json:"source,omitempty"`
+	Target   []MigrationPrecheckFailure `json:"target,omitempty"`
+	Error    *Error                     `json:"error,omitempty"`
+}
+
+// InitiateBulkMigrationArgs holds a request to migrate a number of
+// models to another controller. The models are either listed by tag,
+// or are all the models owned by OwnerTag.
+type InitiateBulkMigrationArgs struct {
+	ModelTags     []string            `json:"model-tags,omitempty"`
+	OwnerTag      string              `json:"owner-tag,omitempty"`
+	TargetInfo    MigrationTargetInfo `json:"target-info"`
+	MaxConcurrent int                 `json:"max-concurrent"`
+}
+
+// InitiateBulkMigrationResult is used to return the result of a bulk
+// migration request.
+type InitiateBulkMigrationResult struct {
+	Id        string   `json:"id"`
+	ModelTags []string `json:"model-tags"`
+}
+
+// BulkMigrationStatusArgs holds the ids of the bulk migrations to
+// report on. The status of all bulk migrations is reported if no ids
+// are given.
+type BulkMigrationStatusArgs struct {
+	Ids []string `json:"ids,omitempty"`
+}
+
+// BulkMigrationStatusResults holds the status of a number of bulk
+// migrations.
+type BulkMigrationStatusResults struct {
+	Results []BulkMigrationStatusResult `json:"results"`
+}
+
+// BulkMigrationStatusResult holds the status of a bulk migration, or
+// the error retrieving it.
+type BulkMigrationStatusResult struct {
+	Result *BulkMigrationStatus `json:"result,omitempty"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// BulkMigrationStatus describes the progress of a bulk migration.
+type BulkMigrationStatus struct {
+	Id               string                     `json:"id"`
+	InitiatedBy      string                     `json:"initiated-by"`
+	StartTime        time.Time                  `json:"start-time"`
+	MaxConcurrent    int                        `json:"max-concurrent"`
+	TargetController string                     `json:"target-controller"`
+	Models           []BulkMigrationModelStatus `json:"models"`
+}
+
+// MigrationIds holds the ids of a number of model migrations.
+type MigrationIds struct {
+	Ids []string `json:"ids"`
+}
+
+// MigrationPhaseResults holds the phases of a number of model
+// migrations.
+type MigrationPhaseResults struct {
+	Results []MigrationPhaseResult `json:"results"`
+}
+
+// MigrationPhaseResult holds the phase of a model migration and its
+// latest status message, or the error retrieving them.
+type MigrationPhaseResult struct {
+	Phase         string `json:"phase,omitempty"`
+	StatusMessage string `json:"status-message,omitempty"`
+	Error         *Error `json:"error,omitempty"`
+}
+
+// SetMigrationTargetArgs holds the targets of a number of offline
+// model migrations.
+type SetMigrationTargetArgs struct {
+	Args []SetMigrationTargetArg `json:"args"`
+}
+
+// SetMigrationTargetArg holds the controller an offline model
+// migration's archive has been imported into. No credentials are
+// needed for the target controller.
+type SetMigrationTargetArg struct {
+	MigrationId string              `json:"migration-id"`
+	TargetInfo  MigrationTargetInfo `json:"target-info"`
+}
+
+// BulkMigrationModelStatus describes the progress of the migration of
+// a model which is part of a bulk migration. Phase is empty if the
+// model's migration has not been started yet; StartError is set if it
+// could not be started.
+type BulkMigrationModelStatus struct {
+	ModelTag      string `json:"model-tag"`
+	ModelName     string `json:"model-name"`
+	OwnerTag      string `json:"owner-tag"`
+	MigrationId   string `json:"migration-id,omitempty"`
+	Phase         string `json:"phase,omitempty"`
+	StatusMessage string `json:"status-message,omitempty"`
+	StartError    string `json:"start-error,omitempty"`
+}
+
 // SetMigrationPhaseArgs provides a migration phase to the
 // migrationmaster.SetPhase API method.
 type SetMigrationPhaseArgs struct {
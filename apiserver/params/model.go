@@ -128,6 +128,61 @@ type SetModelAgentVersion struct {
 	IgnoreAgentVersions bool           `json:"force,omitempty"`
 }
 
+// StageAgentVersion contains the arguments for staging an upgrade of
+// some machines to a later agent version than the rest of the model.
+type StageAgentVersion struct {
+	Version  version.Number `json:"version"`
+	Machines []Entity       `json:"machines"`
+}
+
+// UpgradePreview describes what changing a model's agent version would
+// do, without changing anything.
+type UpgradePreview struct {
+	// CurrentVersion is the model's current agent version.
+	CurrentVersion version.Number `json:"current-version"`
+
+	// TargetVersion is the agent version previewed.
+	TargetVersion version.Number `json:"target-version"`
+
+	// KnownVersion is the most recent version of Juju for which the
+	// controller knows the upgrade steps. Steps for later versions are
+	// not included in the preview.
+	KnownVersion version.Number `json:"known-version"`
+
+	// Agents holds the upgrade steps that each agent in the model would
+	// run.
+	Agents []AgentUpgradePreview `json:"agents"`
+
+	// Prechecks holds the results of the checks made before the agent
+	// version is changed.
+	Prechecks []UpgradePrecheck `json:"prechecks"`
+}
+
+// AgentUpgradePreview holds the upgrade steps that a machine or unit
+// agent would run.
+type AgentUpgradePreview struct {
+	Tag     string               `json:"tag"`
+	Version version.Number       `json:"version"`
+	Steps   []UpgradeStepPreview `json:"steps,omitempty"`
+}
+
+// UpgradeStepPreview describes an upgrade step that would be run.
+type UpgradeStepPreview struct {
+	Version     version.Number `json:"version"`
+	Description string         `json:"description"`
+	Targets     []string       `json:"targets"`
+
+	// State is true if the step upgrades the database.
+	State bool `json:"state,omitempty"`
+}
+
+// UpgradePrecheck holds the result of a check made before the agent
+// version of a model is changed.
+type UpgradePrecheck struct {
+	Name  string `json:"name"`
+	Error *Error `json:"error,omitempty"`
+}
+
 // ModelMigrationStatus holds information about the progress of a (possibly
 // failed) migration.
 type ModelMigrationStatus struct {
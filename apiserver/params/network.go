@@ -402,6 +402,10 @@ type EntityPortRange struct {
 	Protocol string `json:"protocol"`
 	FromPort int    `json:"from-port"`
 	ToPort   int    `json:"to-port"`
+
+	// Endpoint is the name of the unit endpoint a port range is
+	// opened for, or empty if it is opened for all endpoints.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // EntitiesPortRanges holds the parameters for making an OpenPorts or
@@ -703,6 +707,10 @@ type MachinePortRange struct {
 	UnitTag     string    `json:"unit-tag"`
 	RelationTag string    `json:"relation-tag"`
 	PortRange   PortRange `json:"port-range"`
+
+	// Endpoint is the name of the unit endpoint the port range is
+	// opened for, or empty if it is opened for all endpoints.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // MachinePorts holds a machine and subnet tags. It's used when referring to
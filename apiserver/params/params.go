@@ -204,6 +204,11 @@ type AddMachineParams struct {
 	// that will be used to decide how to instantiate the machine.
 	Placement *instance.Placement `json:"placement,omitempty"`
 
+	// CloudInitUserData optionally holds cloud-init user data YAML for
+	// the machine, which is merged with that of the model and of the
+	// applications deployed to it when the machine is provisioned.
+	CloudInitUserData string `json:"cloudinit-userdata,omitempty"`
+
 	// If ParentId is non-empty, it specifies the id of the
 	// parent machine within which the new machine will
 	// be created. In that case, ContainerType must also be
@@ -246,6 +251,42 @@ type AddMachinesResult struct {
 	Error   *Error `json:"error,omitempty"`
 }
 
+// MachinePool describes a named group of machines, started with the
+// same constraints, that the provisioner keeps at a target size.
+type MachinePool struct {
+	Name        string            `json:"name"`
+	Series      string            `json:"series,omitempty"`
+	Constraints constraints.Value `json:"constraints"`
+	Zones       []string          `json:"zones,omitempty"`
+	TargetSize  int               `json:"target-size"`
+
+	// Machines holds the ids of the pool's machines. It is ignored
+	// when adding a pool.
+	Machines []string `json:"machines,omitempty"`
+}
+
+// MachinePools holds a set of machine pools.
+type MachinePools struct {
+	Pools []MachinePool `json:"pools"`
+}
+
+// MachinePoolSize holds the target size of a machine pool.
+type MachinePoolSize struct {
+	Name       string `json:"name"`
+	TargetSize int    `json:"target-size"`
+}
+
+// MachinePoolSizes holds the parameters for the SetMachinePoolSizes
+// call.
+type MachinePoolSizes struct {
+	Sizes []MachinePoolSize `json:"sizes"`
+}
+
+// MachinePoolNames holds the names of a set of machine pools.
+type MachinePoolNames struct {
+	Names []string `json:"names"`
+}
+
 // DestroyMachines holds parameters for the DestroyMachines call.
 // This is the legacy params struct used with the client facade.
 // TODO(wallyworld) - remove in Juju 3.0
@@ -430,6 +471,7 @@ type ApplicationUnitParams struct {
 	ProviderId     string                     `json:"provider-id"`
 	UnitTag        string                     `json:"unit-tag"`
 	Address        string                     `json:"address"`
+	SpaceAddresses map[string]string          `json:"space-addresses,omitempty"`
 	Ports          []string                   `json:"ports"`
 	Stateful       bool                       `json:"stateful,omitempty"`
 	FilesystemInfo []KubernetesFilesystemInfo `json:"filesystem-info,omitempty"`
@@ -899,6 +941,14 @@ type FindToolsResult struct {
 	Error *Error     `json:"error,omitempty"`
 }
 
+// AgentMirrorResult holds the result of importing an agent mirror
+// bundle into the controller.
+type AgentMirrorResult struct {
+	ToolsList tools.List `json:"tools,omitempty"`
+	Images    int        `json:"images,omitempty"`
+	Error     *Error     `json:"error,omitempty"`
+}
+
 // ImageFilterParams holds the parameters used to specify images to delete.
 type ImageFilterParams struct {
 	Images []ImageSpec `json:"images"`
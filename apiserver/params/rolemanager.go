@@ -0,0 +1,77 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// RoleDefinition holds the name and permissions of a custom role.
+type RoleDefinition struct {
+	Name string `json:"name"`
+	// Permissions holds capabilities and facade method patterns.
+	Permissions []string `json:"permissions"`
+}
+
+// AddRoles holds the parameters for adding custom roles.
+type AddRoles struct {
+	Roles []RoleDefinition `json:"roles"`
+}
+
+// RoleNames holds the names of a number of custom roles.
+type RoleNames struct {
+	Names []string `json:"names"`
+}
+
+// RoleInfo holds information on a custom role.
+type RoleInfo struct {
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+	// Assignments is only filled in for controller superusers.
+	Assignments []RoleAssignment `json:"assignments,omitempty"`
+}
+
+// RoleAssignment describes the assignment of a role to a user on a
+// model or controller.
+type RoleAssignment struct {
+	UserTag   string `json:"user-tag"`
+	TargetTag string `json:"target-tag"`
+}
+
+// RoleInfoResult holds the result of a Roles call.
+type RoleInfoResult struct {
+	Result *RoleInfo `json:"result,omitempty"`
+	Error  *Error    `json:"error,omitempty"`
+}
+
+// RoleInfoResults holds the result of a bulk Roles API call.
+type RoleInfoResults struct {
+	Results []RoleInfoResult `json:"results"`
+}
+
+// RoleAssignmentAction is an action that can be performed on the
+// roles assigned to users.
+type RoleAssignmentAction string
+
+// Actions that can be performed on the roles assigned to users.
+const (
+	AssignRole   RoleAssignmentAction = "assign"
+	UnassignRole RoleAssignmentAction = "unassign"
+)
+
+// ModifyRoleAssignments holds the parameters for changing the roles
+// assigned to users.
+type ModifyRoleAssignments struct {
+	Changes []ModifyRoleAssignment `json:"changes"`
+}
+
+// ModifyRoleAssignment holds the parameters for assigning a role to,
+// or unassigning a role from, a user on a model or controller. Role
+// is ignored when unassigning.
+type ModifyRoleAssignment struct {
+	Action    RoleAssignmentAction `json:"action"`
+	Role      string               `json:"role,omitempty"`
+	UserTag   string               `json:"user-tag"`
+	TargetTag string               `json:"target-tag"`
+}
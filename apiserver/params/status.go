@@ -129,6 +129,10 @@ type MachineStatus struct {
 	// PrimaryControllerMachine indicates whether this machine has a primary mongo instance in replicaset and,
 	//	// thus, can be considered a primary controller machine in HA setup.
 	PrimaryControllerMachine *bool `json:"primary-controller-machine,omitempty"`
+
+	// TargetAgentVersion holds the agent version this machine has been
+	// staged to upgrade to ahead of the rest of the model.
+	TargetAgentVersion string `json:"target-agent-version,omitempty"`
 }
 
 // LXDProfile holds status info about a LXDProfile
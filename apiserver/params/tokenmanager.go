@@ -0,0 +1,61 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AddAPIToken holds the parameters for issuing an API token to a
+// service account.
+type AddAPIToken struct {
+	Account   string    `json:"account"`
+	ModelTags []string  `json:"model-tags"`
+	Access    string    `json:"access"`
+	Expires   time.Time `json:"expires"`
+}
+
+// AddAPITokens holds the parameters for issuing a number of API
+// tokens.
+type AddAPITokens struct {
+	Tokens []AddAPIToken `json:"tokens"`
+}
+
+// AddAPITokenResult holds the result of issuing an API token. The
+// token value is only ever returned here.
+type AddAPITokenResult struct {
+	Token string        `json:"token,omitempty"`
+	Info  *APITokenInfo `json:"info,omitempty"`
+	Error *Error        `json:"error,omitempty"`
+}
+
+// AddAPITokenResults holds the results of a bulk AddAPIToken call.
+type AddAPITokenResults struct {
+	Results []AddAPITokenResult `json:"results"`
+}
+
+// APITokenInfo holds information on an API token, without its value.
+type APITokenInfo struct {
+	ID          string    `json:"id"`
+	Account     string    `json:"account"`
+	ModelTags   []string  `json:"model-tags"`
+	Access      string    `json:"access"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+	Expires     time.Time `json:"expires"`
+}
+
+// APITokenFilter holds the service account whose API tokens are
+// wanted. All tokens are wanted if no account is given.
+type APITokenFilter struct {
+	Account string `json:"account,omitempty"`
+}
+
+// APITokensResult holds the result of an APITokens call.
+type APITokensResult struct {
+	Tokens []APITokenInfo `json:"tokens"`
+}
+
+// APITokenIDs holds the IDs of a number of API tokens.
+type APITokenIDs struct {
+	IDs []string `json:"ids"`
+}
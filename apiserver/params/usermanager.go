@@ -67,3 +67,79 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// Groups holds the names of a number of groups.
+type Groups struct {
+	Names []string `json:"names"`
+}
+
+// GroupInfo holds information on a group.
+type GroupInfo struct {
+	Name        string    `json:"name"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+	// Members holds the tags of the users in the group.
+	Members []string `json:"members"`
+}
+
+// GroupInfoResult holds the result of a GroupInfo call.
+type GroupInfoResult struct {
+	Result *GroupInfo `json:"result,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// GroupInfoResults holds the result of a bulk GroupInfo API call.
+type GroupInfoResults struct {
+	Results []GroupInfoResult `json:"results"`
+}
+
+// GroupMemberAction is an action that can be performed on the
+// members of a group.
+type GroupMemberAction string
+
+// Actions that can be performed on the members of a group.
+const (
+	AddGroupMember    GroupMemberAction = "add"
+	RemoveGroupMember GroupMemberAction = "remove"
+)
+
+// ModifyGroupMembers holds the parameters for changing the
+// members of groups.
+type ModifyGroupMembers struct {
+	Changes []ModifyGroupMember `json:"changes"`
+}
+
+// ModifyGroupMember holds the parameters for adding a user to,
+// or removing a user from, a group.
+type ModifyGroupMember struct {
+	Action  GroupMemberAction `json:"action"`
+	Group   string            `json:"group"`
+	UserTag string            `json:"user-tag"`
+}
+
+// GroupAccessAction is an action that can be performed on the
+// access of a group.
+type GroupAccessAction string
+
+// Actions that can be performed on the access of a group.
+const (
+	GrantGroupAccess  GroupAccessAction = "grant"
+	RevokeGroupAccess GroupAccessAction = "revoke"
+)
+
+// ModifyGroupAccessRequest holds the parameters for changing
+// the access of groups.
+type ModifyGroupAccessRequest struct {
+	Changes []ModifyGroupAccess `json:"changes"`
+}
+
+// ModifyGroupAccess holds the parameters for granting or revoking
+// a group's access to a controller, cloud, model or offer. The target
+// is given by TargetTag, or by OfferURL for offers.
+type ModifyGroupAccess struct {
+	Action    GroupAccessAction `json:"action"`
+	Group     string            `json:"group"`
+	Access    string            `json:"access"`
+	TargetTag string            `json:"target-tag,omitempty"`
+	OfferURL  string            `json:"offer-url,omitempty"`
+}
@@ -22,6 +22,8 @@ var controllerFacadeNames = set.NewStrings(
 	"MigrationTarget",
 	"ModelManager",
 	"ModelSummaryWatcher",
+	"RoleManager",
+	"TokenManager",
 	"UserManager",
 )
 
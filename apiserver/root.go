@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/rpcreflect"
 
 	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/cache"
@@ -181,6 +183,10 @@ type apiRoot struct {
 	authorizer  facade.Authorizer
 	objectMutex sync.RWMutex
 	objectCache map[objectKey]reflect.Value
+
+	// role, if set, is the custom role restricting the facade
+	// methods that can be called through this root.
+	role *permission.Role
 }
 
 // newAPIRoot returns a new apiRoot.
@@ -310,6 +316,9 @@ func (r *apiRoot) Kill() {
 // For more information about how FindMethod should work, see rpc/server.go and
 // rpc/rpcreflect/value.go
 func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	if err := r.checkRole(rootName, methodName); err != nil {
+		return nil, err
+	}
 	goType, objMethod, err := r.lookupMethod(rootName, version, methodName)
 	if err != nil {
 		return nil, err
@@ -393,6 +402,21 @@ func (r *apiRoot) lookupMethod(rootName string, version int, methodName string)
 	return goType, objMethod, nil
 }
 
+// checkRole returns an error if the custom role assigned to the
+// logged in user does not allow calls to the facade method. Pings and
+// calls to watchers are always allowed, as a watcher can only be
+// obtained through a call the role already allows. Requests to the
+// HTTP endpoints are checked by roleCheckHandler.
+func (r *apiRoot) checkRole(rootName, methodName string) error {
+	if r.role == nil || rootName == "Pinger" || strings.HasSuffix(rootName, "Watcher") {
+		return nil
+	}
+	if r.role.Allows(rootName, methodName) {
+		return nil
+	}
+	return errors.Annotatef(apiservererrors.ErrPerm, "role %q does not allow %s.%s", r.role.Name, rootName, methodName)
+}
+
 func (r *apiRoot) dispose(key objectKey) {
 	r.objectMutex.Lock()
 	defer r.objectMutex.Unlock()
@@ -663,7 +687,9 @@ func (r *apiHandler) ConnectedModel() string {
 
 // HasPermission returns true if the logged in user can perform <operation> on <target>.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
+	// A service account's other tokens may give it more access than
+	// the token it logged in with, so the token's scope is checked.
+	return entityHasPermission(r.state, r.entity, operation, target)
 }
 
 // UserHasPermission returns true if the passed in user can perform <operation> on <target>.
@@ -9,11 +9,16 @@ import (
 	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
 
@@ -124,3 +129,66 @@ func (s *facadeContextSuite) TestCachedModelTimeout(c *gc.C) {
 		c.Error("CachedModel didn't return")
 	}
 }
+
+type rootRoleSuite struct{}
+
+var _ = gc.Suite(&rootRoleSuite{})
+
+func (s *rootRoleSuite) TestCheckRoleNoRole(c *gc.C) {
+	r := &apiRoot{}
+	c.Assert(r.checkRole("Application", "Deploy"), jc.ErrorIsNil)
+}
+
+func (s *rootRoleSuite) TestCheckRole(c *gc.C) {
+	r := &apiRoot{
+		role: &permission.Role{
+			Name:        "operator",
+			Permissions: []string{permission.SSHCapability, permission.RunActionsCapability},
+		},
+	}
+	c.Check(r.checkRole("SSHClient", "PublicAddress"), jc.ErrorIsNil)
+	c.Check(r.checkRole("Action", "Enqueue"), jc.ErrorIsNil)
+	c.Check(r.checkRole("Pinger", "Ping"), jc.ErrorIsNil)
+	c.Check(r.checkRole("AllWatcher", "Next"), jc.ErrorIsNil)
+
+	err := r.checkRole("Application", "Deploy")
+	c.Assert(err, gc.ErrorMatches, `role "operator" does not allow Application.Deploy: permission denied`)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrPerm)
+}
+
+func (s *rootRoleSuite) TestHTTPRoleMethod(c *gc.C) {
+	c.Check(httpRoleMethod("GET", "DebugLog"), gc.Equals, "GetDebugLog")
+	c.Check(httpRoleMethod("POST", "Charms"), gc.Equals, "PostCharms")
+	c.Check(httpRoleMethod("PUT", "Resources"), gc.Equals, "PutResources")
+
+	viewer := permission.Role{Name: "viewer", Permissions: []string{permission.ViewCapability}}
+	c.Check(viewer.Allows(permission.HTTPFacade, httpRoleMethod("GET", "DebugLog")), jc.IsTrue)
+	c.Check(viewer.Allows(permission.HTTPFacade, httpRoleMethod("POST", "Charms")), jc.IsFalse)
+	c.Check(viewer.Allows(permission.HTTPFacade, httpRoleMethod("POST", "Backups")), jc.IsFalse)
+}
+
+type scopedToken struct {
+	authentication.APIToken
+	modelUUID string
+}
+
+func (t scopedToken) UserTag() names.UserTag {
+	return names.NewUserTag("ci-bot")
+}
+
+func (t scopedToken) Allows(operation permission.Access, target names.Tag) bool {
+	return target == names.NewModelTag(t.modelUUID) && operation == permission.ReadAccess
+}
+
+func (s *rootRoleSuite) TestCheckTokenModelScope(c *gc.C) {
+	const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	entity := &authentication.APITokenEntity{Token: scopedToken{modelUUID: modelUUID}}
+	c.Check(checkTokenModelScope(entity, modelUUID), jc.ErrorIsNil)
+
+	err := checkTokenModelScope(entity, "c0ffee00-0bad-400d-8000-4b1d0d06f00d")
+	c.Assert(err, gc.ErrorMatches, `API token not valid for model "c0ffee00-0bad-400d-8000-4b1d0d06f00d": permission denied`)
+	c.Assert(errors.Cause(err), gc.Equals, apiservererrors.ErrPerm)
+
+	user := &state.User{}
+	c.Check(checkTokenModelScope(user, "c0ffee00-0bad-400d-8000-4b1d0d06f00d"), jc.ErrorIsNil)
+}
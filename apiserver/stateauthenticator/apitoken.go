@@ -0,0 +1,35 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateauthenticator
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/state"
+)
+
+// apiTokenAuth returns an authenticator that can authenticate logins
+// for service accounts presenting an API token.
+func (ctxt *authContext) apiTokenAuth() *authentication.APITokenAuthenticator {
+	return &authentication.APITokenAuthenticator{
+		Tokens: apiTokenGetter{ctxt.st},
+		Clock:  ctxt.clock,
+	}
+}
+
+// apiTokenGetter implements authentication.APITokenGetter by getting
+// the tokens from state.
+type apiTokenGetter struct {
+	st *state.State
+}
+
+// APIToken implements authentication.APITokenGetter.
+func (g apiTokenGetter) APIToken(id string) (authentication.APIToken, error) {
+	token, err := g.st.APIToken(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token, nil
+}
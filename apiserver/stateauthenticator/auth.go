@@ -51,6 +51,7 @@ func NewAuthenticator(statePool *state.StatePool, clock clock.Clock) (*Authentic
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	authContext.oidcAccessUpdater = oidcAccessUpdater{pool: statePool}
 	return &Authenticator{
 		statePool:   statePool,
 		authContext: authContext,
@@ -131,7 +132,9 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	authenticator := a.authContext.authenticator(serverHost)
 	authInfo, err := a.checkCreds(ctx, st.State, req, authTag, true, authenticator)
 	if err != nil {
-		if apiservererrors.IsDischargeRequiredError(err) || errors.IsNotProvisioned(err) {
+		if apiservererrors.IsDischargeRequiredError(err) ||
+			apiservererrors.IsOIDCLoginRequiredError(err) ||
+			errors.IsNotProvisioned(err) {
 			// TODO(axw) move out of common?
 			return httpcontext.AuthInfo{}, errors.Trace(err)
 		}
@@ -53,6 +53,14 @@ type authContext struct {
 	// authentication interactions.
 	localUserInteractions *authentication.Interactions
 
+	// oidcAccessUpdater records the access given to OpenID Connect
+	// users through their group claims.
+	oidcAccessUpdater authentication.OIDCAccessUpdater
+
+	// oidcMu guards oidcProvider.
+	oidcMu       sync.Mutex
+	oidcProvider *authentication.OIDCProvider
+
 	// macaroonAuthOnce guards the fields below it.
 	macaroonAuthOnce   sync.Once
 	_macaroonAuth      *authentication.ExternalMacaroonAuthenticator
@@ -185,13 +193,44 @@ func (a authenticator) Authenticate(
 	tag names.Tag,
 	req params.LoginRequest,
 ) (state.Entity, error) {
-	auth, err := a.authenticatorForTag(tag)
+	auth, err := a.authenticatorForRequest(tag, req)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return auth.Authenticate(ctx, entityFinder, tag, req)
 }
 
+// authenticatorForRequest returns the authenticator appropriate
+// to use for the given login request with the given possibly-nil tag.
+func (a authenticator) authenticatorForRequest(tag names.Tag, req params.LoginRequest) (authentication.EntityAuthenticator, error) {
+	if authentication.IsAPITokenLogin(req) {
+		return a.ctxt.apiTokenAuth(), nil
+	}
+	if isOIDCLogin(tag, req) {
+		auth, err := a.ctxt.oidcAuth()
+		if err == errOIDCAuthNotConfigured {
+			err = errors.Trace(apiservererrors.ErrBadCreds)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return auth, nil
+	}
+	auth, err := a.authenticatorForTag(tag)
+	if errors.Cause(err) == apiservererrors.ErrNoCreds {
+		// There is no external identity manager; if the
+		// controller trusts an OpenID Connect issuer, tell
+		// the client to log in with that instead.
+		if oidcErr := a.ctxt.oidcLoginRequired(); oidcErr != nil {
+			return nil, errors.Trace(oidcErr)
+		}
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return auth, nil
+}
+
 // authenticatorForTag returns the authenticator appropriate
 // to use for a login with the given possibly-nil tag.
 func (a authenticator) authenticatorForTag(tag names.Tag) (authentication.EntityAuthenticator, error) {
@@ -7,6 +7,7 @@ import (
 	"gopkg.in/macaroon-bakery.v2/bakery/identchecker"
 
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/state"
 	"github.com/juju/names/v4"
 )
 
@@ -23,3 +24,7 @@ func ServerBakery(a *Authenticator, identClient identchecker.IdentityClient) (*i
 	}
 	return auth.(*authentication.ExternalMacaroonAuthenticator).Bakery, nil
 }
+
+func NewOIDCAccessUpdater(pool *state.StatePool) authentication.OIDCAccessUpdater {
+	return oidcAccessUpdater{pool: pool}
+}
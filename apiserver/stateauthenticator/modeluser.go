@@ -67,7 +67,15 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 			}
 		}
 		if permission.IsEmptyUserAccess(controllerUser) {
-			return nil, errors.NotFoundf("model or controller user")
+			// The user may still have been granted access through
+			// one of their groups.
+			hasGroupAccess, err := f.hasGroupAccess(utag, model.ModelTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !hasGroupAccess {
+				return nil, errors.NotFoundf("model or controller user")
+			}
 		}
 	}
 
@@ -86,6 +94,21 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 	return u, nil
 }
 
+// hasGroupAccess reports whether the user has been granted access to
+// the model or the controller through any of their groups.
+func (f modelUserEntityFinder) hasGroupAccess(utag names.UserTag, modelTag names.ModelTag) (bool, error) {
+	for _, target := range []names.Tag{modelTag, f.st.ControllerTag()} {
+		access, err := f.st.GroupPermission(utag, target)
+		if err != nil {
+			return false, errors.Annotate(err, "obtaining group access")
+		}
+		if access != permission.NoAccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // modelUserEntity encapsulates an model user
 // and, if the user is local, the local state user
 // as well. This enables us to implement FindEntity
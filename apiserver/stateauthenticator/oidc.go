@@ -0,0 +1,167 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateauthenticator
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/authentication"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// oidcGrantor is recorded as the creator of the controller and model
+// access given to OpenID Connect users through their group claims.
+// Only access created by oidcGrantor is changed or removed when the
+// group claims change; access granted with "juju grant" is left alone.
+var oidcGrantor = names.NewUserTag("group-claims").WithDomain(authentication.OIDCUserDomain)
+
+var errOIDCAuthNotConfigured = errors.New("OpenID Connect authentication is not configured")
+
+// isOIDCLogin reports whether the login request is for an OpenID
+// Connect user: either the tag is in the OIDC user domain, or no tag
+// is given but an ID token is presented as the credentials.
+func isOIDCLogin(tag names.Tag, req params.LoginRequest) bool {
+	if tag == nil {
+		return req.Credentials != ""
+	}
+	userTag, ok := tag.(names.UserTag)
+	return ok && userTag.Domain() == authentication.OIDCUserDomain
+}
+
+// oidcAuth returns an authenticator that can authenticate logins
+// for OpenID Connect users. The issuer's signing keys are cached
+// for as long as the issuer and client ID in the controller
+// config are unchanged.
+func (ctxt *authContext) oidcAuth() (*authentication.OIDCAuthenticator, error) {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get controller config")
+	}
+	issuerURL := controllerCfg.OIDCIssuerURL()
+	if issuerURL == "" {
+		return nil, errOIDCAuthNotConfigured
+	}
+
+	ctxt.oidcMu.Lock()
+	defer ctxt.oidcMu.Unlock()
+	provider := ctxt.oidcProvider
+	if provider == nil || provider.IssuerURL != issuerURL || provider.ClientID != controllerCfg.OIDCClientID() {
+		provider = &authentication.OIDCProvider{
+			IssuerURL: issuerURL,
+			ClientID:  controllerCfg.OIDCClientID(),
+			Clock:     ctxt.clock,
+		}
+		ctxt.oidcProvider = provider
+	}
+	return &authentication.OIDCAuthenticator{
+		Verifier:      provider,
+		GroupsClaim:   controllerCfg.OIDCGroupsClaim(),
+		GroupAccess:   controllerCfg.OIDCGroupAccess(),
+		AccessUpdater: ctxt.oidcAccessUpdater,
+	}, nil
+}
+
+// oidcLoginRequired returns an *apiservererrors.OIDCLoginRequiredError
+// telling the client where to obtain an ID token if OpenID Connect
+// authentication is configured, or nil otherwise.
+func (ctxt *authContext) oidcLoginRequired() error {
+	controllerCfg, err := ctxt.st.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot get controller config")
+	}
+	if controllerCfg.OIDCIssuerURL() == "" {
+		return nil
+	}
+	return &apiservererrors.OIDCLoginRequiredError{
+		IssuerURL: controllerCfg.OIDCIssuerURL(),
+		ClientID:  controllerCfg.OIDCClientID(),
+	}
+}
+
+// oidcAccessUpdater implements authentication.OIDCAccessUpdater
+// by recording access as controller and model users in state.
+type oidcAccessUpdater struct {
+	pool *state.StatePool
+}
+
+// UpdateOIDCAccess implements authentication.OIDCAccessUpdater.
+func (u oidcAccessUpdater) UpdateOIDCAccess(
+	user names.UserTag, displayName string, controllerAccess permission.Access, modelAccess map[string]permission.Access,
+) error {
+	st := u.pool.SystemState()
+	if err := updateOIDCAccess(st, user, displayName, st.ControllerTag(), controllerAccess, st.AddControllerUser); err != nil {
+		return errors.Annotate(err, "updating controller access")
+	}
+	for modelUUID, access := range modelAccess {
+		if err := u.updateModelAccess(user, displayName, modelUUID, access); err != nil {
+			return errors.Annotatef(err, "updating access to model %q", modelUUID)
+		}
+	}
+	return nil
+}
+
+func (u oidcAccessUpdater) updateModelAccess(user names.UserTag, displayName, modelUUID string, access permission.Access) error {
+	st, err := u.pool.Get(modelUUID)
+	if errors.IsNotFound(err) {
+		logger.Debugf("ignoring group claim access to unknown model %q", modelUUID)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	model, err := st.Model()
+	if errors.IsNotFound(err) {
+		logger.Debugf("ignoring group claim access to unknown model %q", modelUUID)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(updateOIDCAccess(st.State, user, displayName, model.ModelTag(), access, model.AddUser))
+}
+
+// updateOIDCAccess makes the access of user on target match the access
+// given by their group claims, unless the user was granted access
+// to the target explicitly.
+func updateOIDCAccess(
+	st *state.State,
+	user names.UserTag,
+	displayName string,
+	target names.Tag,
+	access permission.Access,
+	addUser func(state.UserAccessSpec) (permission.UserAccess, error),
+) error {
+	existing, err := st.UserAccess(user, target)
+	if errors.IsNotFound(err) {
+		if access == permission.NoAccess {
+			return nil
+		}
+		_, err := addUser(state.UserAccessSpec{
+			User:        user,
+			CreatedBy:   oidcGrantor,
+			DisplayName: displayName,
+			Access:      access,
+		})
+		if errors.IsAlreadyExists(err) {
+			// A concurrent login by the same user got there first.
+			return nil
+		}
+		return errors.Trace(err)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if existing.CreatedBy != oidcGrantor {
+		return nil
+	}
+	switch {
+	case access == permission.NoAccess:
+		err = st.RemoveUserAccess(user, target)
+	case access != existing.Access:
+		_, err = st.SetUserAccess(user, target, access)
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateauthenticator_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type oidcAccessUpdaterSuite struct {
+	statetesting.StateSuite
+	updater authentication.OIDCAccessUpdater
+	alice   names.UserTag
+}
+
+var _ = gc.Suite(&oidcAccessUpdaterSuite{})
+
+func (s *oidcAccessUpdaterSuite) SetUpTest(c *gc.C) {
+	s.StateSuite.SetUpTest(c)
+	s.updater = stateauthenticator.NewOIDCAccessUpdater(s.StatePool)
+	s.alice = names.NewUserTag("alice@oidc")
+}
+
+func (s *oidcAccessUpdaterSuite) update(c *gc.C, controllerAccess, modelAccess permission.Access) {
+	err := s.updater.UpdateOIDCAccess(s.alice, "Alice", controllerAccess, map[string]permission.Access{
+		s.Model.UUID(): modelAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *oidcAccessUpdaterSuite) assertAccess(c *gc.C, target names.Tag, expect permission.Access) {
+	access, err := s.State.UserAccess(s.alice, target)
+	if expect == permission.NoAccess {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+		return
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.Access, gc.Equals, expect)
+}
+
+func (s *oidcAccessUpdaterSuite) TestGrantsAndRevokesGroupAccess(c *gc.C) {
+	s.update(c, permission.LoginAccess, permission.WriteAccess)
+	s.assertAccess(c, s.State.ControllerTag(), permission.LoginAccess)
+	s.assertAccess(c, s.Model.ModelTag(), permission.WriteAccess)
+	access, err := s.State.UserAccess(s.alice, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.DisplayName, gc.Equals, "Alice")
+
+	s.update(c, permission.SuperuserAccess, permission.ReadAccess)
+	s.assertAccess(c, s.State.ControllerTag(), permission.SuperuserAccess)
+	s.assertAccess(c, s.Model.ModelTag(), permission.ReadAccess)
+
+	s.update(c, permission.NoAccess, permission.NoAccess)
+	s.assertAccess(c, s.State.ControllerTag(), permission.NoAccess)
+	s.assertAccess(c, s.Model.ModelTag(), permission.NoAccess)
+}
+
+func (s *oidcAccessUpdaterSuite) TestExplicitGrantsUntouched(c *gc.C) {
+	_, err := s.State.AddControllerUser(state.UserAccessSpec{
+		User:      s.alice,
+		CreatedBy: s.Owner,
+		Access:    permission.SuperuserAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.update(c, permission.NoAccess, permission.ReadAccess)
+	s.assertAccess(c, s.State.ControllerTag(), permission.SuperuserAccess)
+	s.assertAccess(c, s.Model.ModelTag(), permission.ReadAccess)
+}
+
+func (s *oidcAccessUpdaterSuite) TestUnknownModelIgnored(c *gc.C) {
+	err := s.updater.UpdateOIDCAccess(s.alice, "Alice", permission.LoginAccess, map[string]permission.Access{
+		"deadbeef-0bad-400d-8000-4b1d0d06f00d": permission.WriteAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertAccess(c, s.State.ControllerTag(), permission.LoginAccess)
+}
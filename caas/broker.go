@@ -151,6 +151,11 @@ type ServiceParams struct {
 
 	// CharmModifiedVersion increases when the charm changes in some way.
 	CharmModifiedVersion int
+
+	// EndpointBindings maps the application's endpoints to the names
+	// of the spaces they are bound to. Pods are attached to a secondary
+	// network for each space other than the default one.
+	EndpointBindings map[string]string
 }
 
 // OperatorState is returned by the OperatorExists call.
@@ -361,6 +366,10 @@ type Unit struct {
 	Stateful       bool
 	Status         status.StatusInfo
 	FilesystemInfo []FilesystemInfo
+
+	// SpaceAddresses holds the unit's addresses on any secondary
+	// networks, keyed by the name of the space for the network.
+	SpaceAddresses map[string]string
 }
 
 // Operator represents information about the status of an "operator pod".
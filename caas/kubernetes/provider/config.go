@@ -28,6 +28,7 @@ const (
 	ingressSSLRedirectKey    = "kubernetes-ingress-ssl-redirect"
 	ingressSSLPassthroughKey = "kubernetes-ingress-ssl-passthrough"
 	ingressAllowHTTPKey      = "kubernetes-ingress-allow-http"
+	ingressTLSSecretKey      = "kubernetes-ingress-tls-secret"
 )
 
 var configFields = environschema.Fields{
@@ -86,6 +87,11 @@ var configFields = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.ProviderGroup,
 	},
+	ingressTLSSecretKey: {
+		Description: "the name of the secret holding the TLS certificate for the external hostname",
+		Type:        environschema.Tstring,
+		Group:       environschema.ProviderGroup,
+	},
 }
 
 var schemaDefaults = schema.Defaults{
@@ -95,6 +101,7 @@ var schemaDefaults = schema.Defaults{
 	ingressSSLRedirectKey:    defaultIngressSSLRedirect,
 	ingressSSLPassthroughKey: defaultIngressSSLPassthrough,
 	ingressAllowHTTPKey:      defaultIngressAllowHTTPKey,
+	ingressTLSSecretKey:      schema.Omit,
 }
 
 // ConfigSchema returns the configuration schema for
@@ -19,9 +19,24 @@ const (
 	CredAttrClientKeyData         = "ClientKeyData"
 	CredAttrToken                 = "Token"
 
+	// CredAttrRegistryCredentials holds docker config JSON with the
+	// credentials used to pull images from private registries.
+	CredAttrRegistryCredentials = "registry-credentials"
+
 	RBACLabelKeyName = "rbac-id"
 )
 
+// registryCredentialsAttr is accepted by all auth types, allowing model
+// workloads and operators to pull images from private registries.
+var registryCredentialsAttr = cloud.NamedCredentialAttr{
+	Name: CredAttrRegistryCredentials,
+	CredentialAttr: cloud.CredentialAttr{
+		Optional:    true,
+		Description: "docker config JSON with credentials for private image registries",
+		Hidden:      true,
+	},
+}
+
 var k8sCredentialSchemas = map[cloud.AuthType]cloud.CredentialSchema{
 	cloud.UserPassAuthType: {
 		{
@@ -34,6 +49,7 @@ var k8sCredentialSchemas = map[cloud.AuthType]cloud.CredentialSchema{
 				Hidden:      true,
 			},
 		},
+		registryCredentialsAttr,
 	},
 	cloud.OAuth2WithCertAuthType: {
 		{
@@ -56,6 +72,7 @@ var k8sCredentialSchemas = map[cloud.AuthType]cloud.CredentialSchema{
 				Hidden:      true,
 			},
 		},
+		registryCredentialsAttr,
 	},
 	cloud.CertificateAuthType: {
 		{
@@ -78,6 +95,7 @@ var k8sCredentialSchemas = map[cloud.AuthType]cloud.CredentialSchema{
 				Description: "the unique ID key name of the rbac resources",
 			},
 		},
+		registryCredentialsAttr,
 	},
 }
 
@@ -45,9 +45,9 @@ func (s *credentialsSuite) TestCredentialsValid(c *gc.C) {
 }
 
 func (s *credentialsSuite) TestHiddenAttributes(c *gc.C) {
-	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "userpass", "password")
-	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "oauth2withcert", "Token", "ClientKeyData")
-	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "certificate", "Token")
+	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "userpass", "password", "registry-credentials")
+	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "oauth2withcert", "Token", "ClientKeyData", "registry-credentials")
+	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "certificate", "Token", "registry-credentials")
 }
 
 var singleConfigYAML = `
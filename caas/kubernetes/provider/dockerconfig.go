@@ -7,7 +7,10 @@ import (
 	// Import shas that are used for docker image validation.
 	_ "crypto/sha256"
 	_ "crypto/sha512"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/docker/distribution/reference"
 	"github.com/juju/errors"
@@ -34,6 +37,14 @@ type DockerConfigEntry struct {
 	Username string
 	Password string
 	Email    string
+
+	// Auth holds the base64 encoded "username:password", which the
+	// docker CLI writes instead of the username and password.
+	Auth string `json:"auth,omitempty"`
+
+	// IdentityToken holds a token used to obtain an access token
+	// for the registry, in place of a username and password.
+	IdentityToken string `json:"identitytoken,omitempty"`
 }
 
 func createDockerConfigJSON(imageDetails *specs.ImageDetails) ([]byte, error) {
@@ -54,6 +65,50 @@ func createDockerConfigJSON(imageDetails *specs.ImageDetails) ([]byte, error) {
 	return json.Marshal(dockerConfig)
 }
 
+// parseRegistryCredentials validates docker config JSON supplied through the
+// registry-credentials cloud credential attribute, returning the content
+// to store in the model's image pull secret.
+func parseRegistryCredentials(in string) ([]byte, error) {
+	if in == "" {
+		return nil, nil
+	}
+	var cfg DockerConfigJSON
+	if err := json.Unmarshal([]byte(in), &cfg); err != nil {
+		return nil, errors.NewNotValid(err, "registry credentials")
+	}
+	if len(cfg.Auths) == 0 {
+		return nil, errors.NotValidf("registry credentials without any registries")
+	}
+	for registryURL, entry := range cfg.Auths {
+		if entry.Auth != "" && entry.Username == "" && entry.Password == "" {
+			username, password, err := decodeDockerConfigAuth(entry.Auth)
+			if err != nil {
+				return nil, errors.NewNotValid(err, fmt.Sprintf("registry credentials for %q", registryURL))
+			}
+			entry.Username, entry.Password = username, password
+			cfg.Auths[registryURL] = entry
+		}
+		if entry.IdentityToken == "" && (entry.Username == "" || entry.Password == "") {
+			return nil, errors.NotValidf("registry credentials for %q without username and password", registryURL)
+		}
+	}
+	return json.Marshal(cfg)
+}
+
+// decodeDockerConfigAuth returns the username and password held in the
+// base64 encoded "username:password" auth of a docker config entry.
+func decodeDockerConfigAuth(auth string) (string, string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", errors.Annotate(err, "decoding auth")
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New(`auth is not a base64 encoded "username:password"`)
+	}
+	return parts[0], parts[1], nil
+}
+
 // extractRegistryName returns the registry URL part of an images path
 func extractRegistryURL(imagePath string) (string, error) {
 	imageNamed, err := reference.ParseNormalizedNamed(imagePath)
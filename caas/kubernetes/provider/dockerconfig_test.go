@@ -6,6 +6,7 @@ package provider_test
 import (
 	"encoding/json"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -67,3 +68,46 @@ func (s *DockerConfigSuite) TestCreateDockerConfigJSON(c *gc.C) {
 		},
 	})
 }
+
+func (s *DockerConfigSuite) TestParseRegistryCredentialsAuth(c *gc.C) {
+	// "Ym9iOmh1bnRlcjI=" is the base64 encoding of "bob:hunter2".
+	data, err := provider.ParseRegistryCredentials(`{"auths":{"registry.example.com":{"auth":"Ym9iOmh1bnRlcjI="}}}`)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var result provider.DockerConfigJSON
+	err = json.Unmarshal(data, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, provider.DockerConfigJSON{
+		Auths: map[string]provider.DockerConfigEntry{
+			"registry.example.com": {
+				Username: "bob",
+				Password: "hunter2",
+				Auth:     "Ym9iOmh1bnRlcjI=",
+			},
+		},
+	})
+}
+
+func (s *DockerConfigSuite) TestParseRegistryCredentialsIdentityToken(c *gc.C) {
+	data, err := provider.ParseRegistryCredentials(`{"auths":{"registry.example.com":{"identitytoken":"token"}}}`)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var result provider.DockerConfigJSON
+	err = json.Unmarshal(data, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, provider.DockerConfigJSON{
+		Auths: map[string]provider.DockerConfigEntry{
+			"registry.example.com": {IdentityToken: "token"},
+		},
+	})
+}
+
+func (s *DockerConfigSuite) TestParseRegistryCredentialsInvalidAuth(c *gc.C) {
+	// "Ym9i" is the base64 encoding of "bob".
+	_, err := provider.ParseRegistryCredentials(`{"auths":{"registry.example.com":{"auth":"Ym9i"}}}`)
+	c.Assert(err, gc.ErrorMatches, `registry credentials for "registry.example.com": auth is not a base64 encoded "username:password"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = provider.ParseRegistryCredentials(`{"auths":{"registry.example.com":{"auth":"!"}}}`)
+	c.Assert(err, gc.ErrorMatches, `registry credentials for "registry.example.com": decoding auth: .*`)
+}
@@ -46,11 +46,12 @@ type client struct {
 	podGetter typedcorev1.PodInterface
 }
 
-// Executor provides the API to exec or cp on a pod inside the cluster.
+// Executor provides the API to exec, cp or read the logs of a pod inside the cluster.
 type Executor interface {
 	Status(params StatusParams) (*Status, error)
 	Exec(params ExecParams, cancel <-chan struct{}) error
 	Copy(params CopyParams, cancel <-chan struct{}) error
+	Logs(params LogsParams, cancel <-chan struct{}) error
 }
 
 // NewInCluster returns a in-cluster exec client.
@@ -0,0 +1,179 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package exec
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogsParams holds all the necessary parameters for Logs.
+type LogsParams struct {
+	PodName string
+	// ContainerNames limits the logs to the named containers.
+	// The logs of all of the pod's containers are read when empty.
+	ContainerNames []string
+
+	// Follow keeps the log streams open, waiting for new lines
+	// until cancelled.
+	Follow bool
+	// TailLines, if set, is the number of lines from the end of
+	// each container's log to start from.
+	TailLines *int64
+	// SinceTime, if set, skips the log lines written before it.
+	SinceTime *time.Time
+
+	// Lines receives each line read from the container logs.
+	Lines chan<- LogLine
+}
+
+func (p *LogsParams) validate() error {
+	if p.PodName == "" {
+		return errors.New("pod name not specified")
+	}
+	if p.Lines == nil {
+		return errors.New("lines channel not specified")
+	}
+	return nil
+}
+
+// LogLine is a single line of a container log.
+type LogLine struct {
+	ContainerName string
+	Timestamp     time.Time
+	Message       string
+}
+
+// Logs streams the logs of the containers of a pod to params.Lines,
+// returning once all the streams are exhausted or cancel is closed.
+func (c client) Logs(params LogsParams, cancel <-chan struct{}) error {
+	if err := params.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	pod, err := getValidatedPod(c.podGetter, params.PodName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	containerNames, err := logContainerNames(pod, params.ContainerNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var streams []io.ReadCloser
+	closeStreams := func() {
+		for _, stream := range streams {
+			_ = stream.Close()
+		}
+	}
+	for _, name := range containerNames {
+		opts := &core.PodLogOptions{
+			Container:  name,
+			Follow:     params.Follow,
+			TailLines:  params.TailLines,
+			Timestamps: true,
+		}
+		if params.SinceTime != nil {
+			since := metav1.NewTime(*params.SinceTime)
+			opts.SinceTime = &since
+		}
+		stream, err := c.podGetter.GetLogs(pod.Name, opts).Stream()
+		if err != nil {
+			closeStreams()
+			return errors.Annotatef(err, "streaming logs of container %q", name)
+		}
+		streams = append(streams, stream)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-cancel:
+		case <-done:
+		}
+		closeStreams()
+	}()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(streams))
+	for i := range streams {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = readLogLines(containerNames[i], streams[i], params.Lines, cancel)
+		}(i)
+	}
+	wg.Wait()
+
+	select {
+	case <-cancel:
+		return nil
+	default:
+	}
+	for i, err := range errs {
+		if err != nil {
+			return errors.Annotatef(err, "reading logs of container %q", containerNames[i])
+		}
+	}
+	return nil
+}
+
+func logContainerNames(pod *core.Pod, names []string) ([]string, error) {
+	if len(names) == 0 {
+		for _, container := range pod.Spec.Containers {
+			names = append(names, container.Name)
+		}
+		return names, nil
+	}
+	for _, name := range names {
+		found := false
+		for _, container := range pod.Spec.InitContainers {
+			found = found || container.Name == name
+		}
+		for _, container := range pod.Spec.Containers {
+			found = found || container.Name == name
+		}
+		if !found {
+			return nil, errors.NotFoundf("container %q", name)
+		}
+	}
+	return names, nil
+}
+
+func readLogLines(containerName string, r io.Reader, out chan<- LogLine, cancel <-chan struct{}) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case out <- parseLogLine(containerName, scanner.Text()):
+		case <-cancel:
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// parseLogLine splits the RFC3339 timestamp that Kubernetes prefixes to
+// each log line when timestamps are requested from the message.
+func parseLogLine(containerName, line string) LogLine {
+	result := LogLine{
+		ContainerName: containerName,
+		Message:       line,
+	}
+	parts := strings.SplitN(line, " ", 2)
+	if t, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+		result.Timestamp = t
+		result.Message = ""
+		if len(parts) == 2 {
+			result.Message = parts[1]
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package exec_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/rest/fake"
+
+	"github.com/juju/juju/caas/kubernetes/provider/exec"
+)
+
+type logsSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&logsSuite{})
+
+func logsRequest(body string) *rest.Request {
+	client := fake.CreateHTTPClient(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+	return rest.NewRequestWithClient(&url.URL{Scheme: "https", Host: "localhost"}, "", rest.ClientContentConfig{}, client)
+}
+
+func (s *logsSuite) pod() *core.Pod {
+	pod := &core.Pod{
+		Spec: core.PodSpec{
+			Containers: []core.Container{
+				{Name: "gitlab-container"},
+			},
+		},
+	}
+	pod.SetName("gitlab-k8s-0")
+	return pod
+}
+
+func (s *logsSuite) TestLogs(c *gc.C) {
+	ctrl := s.setupExecClient(c)
+	defer ctrl.Finish()
+
+	tailLines := int64(10)
+	since := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	sinceTime := metav1.NewTime(since)
+	gomock.InOrder(
+		s.mockPodGetter.EXPECT().Get("gitlab-k8s-0", metav1.GetOptions{}).
+			Return(s.pod(), nil),
+		s.mockPodGetter.EXPECT().GetLogs("gitlab-k8s-0", &core.PodLogOptions{
+			Container:  "gitlab-container",
+			TailLines:  &tailLines,
+			SinceTime:  &sinceTime,
+			Timestamps: true,
+		}).Return(logsRequest(
+			"2020-05-01T10:00:01.5Z started\n"+
+				"2020-05-01T10:00:02Z listening on :80\n"+
+				"no timestamp\n",
+		)),
+	)
+
+	lines := make(chan exec.LogLine, 3)
+	err := s.execClient.Logs(exec.LogsParams{
+		PodName:   "gitlab-k8s-0",
+		TailLines: &tailLines,
+		SinceTime: &since,
+		Lines:     lines,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	close(lines)
+
+	var result []exec.LogLine
+	for line := range lines {
+		result = append(result, line)
+	}
+	c.Assert(result, jc.DeepEquals, []exec.LogLine{{
+		ContainerName: "gitlab-container",
+		Timestamp:     time.Date(2020, 5, 1, 10, 0, 1, 500000000, time.UTC),
+		Message:       "started",
+	}, {
+		ContainerName: "gitlab-container",
+		Timestamp:     time.Date(2020, 5, 1, 10, 0, 2, 0, time.UTC),
+		Message:       "listening on :80",
+	}, {
+		ContainerName: "gitlab-container",
+		Message:       "no timestamp",
+	}})
+}
+
+func (s *logsSuite) TestLogsContainerNotFound(c *gc.C) {
+	ctrl := s.setupExecClient(c)
+	defer ctrl.Finish()
+
+	s.mockPodGetter.EXPECT().Get("gitlab-k8s-0", metav1.GetOptions{}).
+		Return(s.pod(), nil)
+
+	err := s.execClient.Logs(exec.LogsParams{
+		PodName:        "gitlab-k8s-0",
+		ContainerNames: []string{"sidecar"},
+		Lines:          make(chan exec.LogLine),
+	}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `container "sidecar" not found`)
+}
+
+func (s *logsSuite) TestLogsCancelled(c *gc.C) {
+	ctrl := s.setupExecClient(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		s.mockPodGetter.EXPECT().Get("gitlab-k8s-0", metav1.GetOptions{}).
+			Return(s.pod(), nil),
+		s.mockPodGetter.EXPECT().GetLogs("gitlab-k8s-0", &core.PodLogOptions{
+			Container:  "gitlab-container",
+			Follow:     true,
+			Timestamps: true,
+		}).Return(logsRequest("2020-05-01T10:00:01Z started\n")),
+	)
+
+	// Nothing reads the lines channel, so Logs blocks until cancelled.
+	cancel := make(chan struct{})
+	close(cancel)
+	err := s.execClient.Logs(exec.LogsParams{
+		PodName: "gitlab-k8s-0",
+		Follow:  true,
+		Lines:   make(chan exec.LogLine),
+	}, cancel)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *logsSuite) TestLogsValidation(c *gc.C) {
+	ctrl := s.setupExecClient(c)
+	defer ctrl.Finish()
+
+	err := s.execClient.Logs(exec.LogsParams{Lines: make(chan exec.LogLine)}, nil)
+	c.Assert(err, gc.ErrorMatches, "pod name not specified")
+	err = s.execClient.Logs(exec.LogsParams{PodName: "gitlab-k8s-0"}, nil)
+	c.Assert(err, gc.ErrorMatches, "lines channel not specified")
+}
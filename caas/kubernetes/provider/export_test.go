@@ -26,20 +26,21 @@ import (
 )
 
 var (
-	PrepareWorkloadSpec    = prepareWorkloadSpec
-	OperatorPod            = operatorPod
-	ExtractRegistryURL     = extractRegistryURL
-	CreateDockerConfigJSON = createDockerConfigJSON
-	NewStorageConfig       = newStorageConfig
-	ControllerCorelation   = controllerCorelation
-	GetLocalMicroK8sConfig = getLocalMicroK8sConfig
-	AttemptMicroK8sCloud   = attemptMicroK8sCloudInternal
-	EnsureMicroK8sSuitable = ensureMicroK8sSuitable
-	NewK8sBroker           = newK8sBroker
-	ToYaml                 = toYaml
-	Indent                 = indent
-	ProcessSecretData      = processSecretData
-	PushUniqueVolume       = pushUniqueVolume
+	PrepareWorkloadSpec      = prepareWorkloadSpec
+	OperatorPod              = operatorPod
+	ExtractRegistryURL       = extractRegistryURL
+	CreateDockerConfigJSON   = createDockerConfigJSON
+	ParseRegistryCredentials = parseRegistryCredentials
+	NewStorageConfig         = newStorageConfig
+	ControllerCorelation     = controllerCorelation
+	GetLocalMicroK8sConfig   = getLocalMicroK8sConfig
+	AttemptMicroK8sCloud     = attemptMicroK8sCloudInternal
+	EnsureMicroK8sSuitable   = ensureMicroK8sSuitable
+	NewK8sBroker             = newK8sBroker
+	ToYaml                   = toYaml
+	Indent                   = indent
+	ProcessSecretData        = processSecretData
+	PushUniqueVolume         = pushUniqueVolume
 
 	CompileK8sCloudCheckers                    = compileK8sCloudCheckers
 	CompileLifecycleApplicationRemovalSelector = compileLifecycleApplicationRemovalSelector
@@ -10,13 +10,35 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
 	k8sannotations "github.com/juju/juju/core/annotations"
 )
 
+const (
+	// ingressV1GroupVersion is the group version serving the GA
+	// ingress resource, available from kubernetes 1.19.
+	ingressV1GroupVersion = "networking.k8s.io/v1"
+
+	// defaultIngressPathType is the path type used for networking.k8s.io/v1
+	// ingress paths, which require one to be set explicitly.
+	defaultIngressPathType = "ImplementationSpecific"
+)
+
+var ingressV1Resource = schema.GroupVersionResource{
+	Group:    "networking.k8s.io",
+	Version:  "v1",
+	Resource: "ingresses",
+}
+
 func (k *kubernetesClient) getIngressLabels(appName string) map[string]string {
 	return map[string]string{
 		labelApplication: appName,
@@ -72,8 +94,71 @@ func (k *kubernetesClient) ensureIngress(appName string, spec *v1beta1.Ingress,
 	return cleanUp, errors.Trace(err)
 }
 
+// supportsIngressV1 reports whether the cluster serves the
+// networking.k8s.io/v1 ingress resource. Newer clusters no longer serve
+// the extensions/v1beta1 ingress resource, so the v1 API is preferred
+// whenever it is available. The answer is cached until the client
+// changes.
+func (k *kubernetesClient) supportsIngressV1() (bool, error) {
+	k.lock.Lock()
+	client, cached := k.clientUnlocked, k.ingressV1SupportedUnlocked
+	k.lock.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	supported, err := discoverIngressV1(client)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if k.clientUnlocked == client {
+		k.ingressV1SupportedUnlocked = &supported
+	}
+	return supported, nil
+}
+
+func discoverIngressV1(client kubernetes.Interface) (bool, error) {
+	resources, err := client.Discovery().ServerResourcesForGroupVersion(ingressV1GroupVersion)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Annotatef(err, "checking for %q ingress support", ingressV1GroupVersion)
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == ingressV1Resource.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (k *kubernetesClient) ingressV1Client() dynamic.ResourceInterface {
+	return k.dynamicClient().Resource(ingressV1Resource).Namespace(k.namespace)
+}
+
 func (k *kubernetesClient) createIngress(ingress *v1beta1.Ingress) (*v1beta1.Ingress, error) {
 	purifyResource(ingress)
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if v1Supported {
+		in, err := ingressToV1(ingress)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		out, err := k.ingressV1Client().Create(in, v1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			return nil, errors.AlreadyExistsf("ingress resource %q", ingress.GetName())
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ingressFromV1(out)
+	}
 	out, err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).Create(ingress)
 	if k8serrors.IsAlreadyExists(err) {
 		return nil, errors.AlreadyExistsf("ingress resource %q", ingress.GetName())
@@ -82,6 +167,20 @@ func (k *kubernetesClient) createIngress(ingress *v1beta1.Ingress) (*v1beta1.Ing
 }
 
 func (k *kubernetesClient) getIngress(name string) (*v1beta1.Ingress, error) {
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if v1Supported {
+		out, err := k.ingressV1Client().Get(name, v1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.NotFoundf("ingress resource %q", name)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ingressFromV1(out)
+	}
 	out, err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).Get(name, v1.GetOptions{})
 	if k8serrors.IsNotFound(err) {
 		return nil, errors.NotFoundf("ingress resource %q", name)
@@ -90,6 +189,24 @@ func (k *kubernetesClient) getIngress(name string) (*v1beta1.Ingress, error) {
 }
 
 func (k *kubernetesClient) updateIngress(ingress *v1beta1.Ingress) (*v1beta1.Ingress, error) {
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if v1Supported {
+		in, err := ingressToV1(ingress)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		out, err := k.ingressV1Client().Update(in, v1.UpdateOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil, errors.NotFoundf("ingress resource %q", ingress.GetName())
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return ingressFromV1(out)
+	}
 	out, err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).Update(ingress)
 	if k8serrors.IsNotFound(err) {
 		return nil, errors.NotFoundf("ingress resource %q", ingress.GetName())
@@ -98,7 +215,15 @@ func (k *kubernetesClient) updateIngress(ingress *v1beta1.Ingress) (*v1beta1.Ing
 }
 
 func (k *kubernetesClient) deleteIngress(name string, uid k8stypes.UID) error {
-	err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).Delete(name, newPreconditionDeleteOptions(uid))
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if v1Supported {
+		err = k.ingressV1Client().Delete(name, newPreconditionDeleteOptions(uid))
+	} else {
+		err = k.client().ExtensionsV1beta1().Ingresses(k.namespace).Delete(name, newPreconditionDeleteOptions(uid))
+	}
 	if k8serrors.IsNotFound(err) {
 		return nil
 	}
@@ -109,6 +234,28 @@ func (k *kubernetesClient) listIngressResources(labels map[string]string) ([]v1b
 	listOps := v1.ListOptions{
 		LabelSelector: labelSetToSelector(labels).String(),
 	}
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if v1Supported {
+		list, err := k.ingressV1Client().List(listOps)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(list.Items) == 0 {
+			return nil, errors.NotFoundf("ingress with labels %v", labels)
+		}
+		out := make([]v1beta1.Ingress, len(list.Items))
+		for i := range list.Items {
+			ing, err := ingressFromV1(&list.Items[i])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			out[i] = *ing
+		}
+		return out, nil
+	}
 	ingList, err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).List(listOps)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -120,13 +267,161 @@ func (k *kubernetesClient) listIngressResources(labels map[string]string) ([]v1b
 }
 
 func (k *kubernetesClient) deleteIngressResources(appName string) error {
-	err := k.client().ExtensionsV1beta1().Ingresses(k.namespace).DeleteCollection(&v1.DeleteOptions{
+	v1Supported, err := k.supportsIngressV1()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	deleteOpts := &v1.DeleteOptions{
 		PropagationPolicy: &defaultPropagationPolicy,
-	}, v1.ListOptions{
+	}
+	listOpts := v1.ListOptions{
 		LabelSelector: labelSetToSelector(k.getIngressLabels(appName)).String(),
-	})
+	}
+	if v1Supported {
+		err = k.ingressV1Client().DeleteCollection(deleteOpts, listOpts)
+	} else {
+		err = k.client().ExtensionsV1beta1().Ingresses(k.namespace).DeleteCollection(deleteOpts, listOpts)
+	}
 	if k8serrors.IsNotFound(err) {
 		return nil
 	}
 	return errors.Trace(err)
 }
+
+// ingressV1 mirrors the networking.k8s.io/v1 ingress resource, which is not
+// available in the vendored k8s.io/api package. It is only used to marshal
+// to and from the unstructured objects handled by the dynamic client.
+type ingressV1 struct {
+	v1.TypeMeta   `json:",inline"`
+	v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec          ingressSpecV1 `json:"spec,omitempty"`
+}
+
+type ingressSpecV1 struct {
+	DefaultBackend *ingressBackendV1    `json:"defaultBackend,omitempty"`
+	TLS            []v1beta1.IngressTLS `json:"tls,omitempty"`
+	Rules          []ingressRuleV1      `json:"rules,omitempty"`
+}
+
+type ingressRuleV1 struct {
+	Host string                  `json:"host,omitempty"`
+	HTTP *httpIngressRuleValueV1 `json:"http,omitempty"`
+}
+
+type httpIngressRuleValueV1 struct {
+	Paths []httpIngressPathV1 `json:"paths"`
+}
+
+type httpIngressPathV1 struct {
+	Path     string           `json:"path,omitempty"`
+	PathType string           `json:"pathType"`
+	Backend  ingressBackendV1 `json:"backend"`
+}
+
+type ingressBackendV1 struct {
+	Service *ingressServiceBackendV1 `json:"service,omitempty"`
+}
+
+type ingressServiceBackendV1 struct {
+	Name string               `json:"name"`
+	Port serviceBackendPortV1 `json:"port,omitempty"`
+}
+
+type serviceBackendPortV1 struct {
+	Name   string `json:"name,omitempty"`
+	Number int32  `json:"number,omitempty"`
+}
+
+func ingressBackendToV1(in v1beta1.IngressBackend) ingressBackendV1 {
+	port := serviceBackendPortV1{}
+	if in.ServicePort.Type == intstr.String {
+		port.Name = in.ServicePort.StrVal
+	} else {
+		port.Number = in.ServicePort.IntVal
+	}
+	return ingressBackendV1{
+		Service: &ingressServiceBackendV1{Name: in.ServiceName, Port: port},
+	}
+}
+
+func ingressBackendFromV1(in ingressBackendV1) v1beta1.IngressBackend {
+	if in.Service == nil {
+		return v1beta1.IngressBackend{}
+	}
+	port := intstr.FromInt(int(in.Service.Port.Number))
+	if in.Service.Port.Name != "" {
+		port = intstr.FromString(in.Service.Port.Name)
+	}
+	return v1beta1.IngressBackend{ServiceName: in.Service.Name, ServicePort: port}
+}
+
+// ingressToV1 converts an extensions/v1beta1 ingress into the equivalent
+// networking.k8s.io/v1 ingress.
+func ingressToV1(in *v1beta1.Ingress) (*unstructured.Unstructured, error) {
+	out := ingressV1{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: ingressV1GroupVersion,
+			Kind:       "Ingress",
+		},
+		ObjectMeta: in.ObjectMeta,
+		Spec: ingressSpecV1{
+			TLS: in.Spec.TLS,
+		},
+	}
+	if in.Spec.Backend != nil {
+		backend := ingressBackendToV1(*in.Spec.Backend)
+		out.Spec.DefaultBackend = &backend
+	}
+	for _, rule := range in.Spec.Rules {
+		ruleV1 := ingressRuleV1{Host: rule.Host}
+		if rule.HTTP != nil {
+			ruleV1.HTTP = &httpIngressRuleValueV1{}
+			for _, path := range rule.HTTP.Paths {
+				ruleV1.HTTP.Paths = append(ruleV1.HTTP.Paths, httpIngressPathV1{
+					Path:     path.Path,
+					PathType: defaultIngressPathType,
+					Backend:  ingressBackendToV1(path.Backend),
+				})
+			}
+		}
+		out.Spec.Rules = append(out.Spec.Rules, ruleV1)
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&out)
+	if err != nil {
+		return nil, errors.Annotatef(err, "converting ingress %q to %s", in.GetName(), ingressV1GroupVersion)
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// ingressFromV1 converts a networking.k8s.io/v1 ingress back into the
+// extensions/v1beta1 representation used by the rest of the provider.
+func ingressFromV1(in *unstructured.Unstructured) (*v1beta1.Ingress, error) {
+	var ing ingressV1
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(in.UnstructuredContent(), &ing); err != nil {
+		return nil, errors.Annotatef(err, "parsing %s ingress %q", ingressV1GroupVersion, in.GetName())
+	}
+	out := &v1beta1.Ingress{
+		ObjectMeta: ing.ObjectMeta,
+		Spec: v1beta1.IngressSpec{
+			TLS: ing.Spec.TLS,
+		},
+	}
+	if ing.Spec.DefaultBackend != nil {
+		backend := ingressBackendFromV1(*ing.Spec.DefaultBackend)
+		out.Spec.Backend = &backend
+	}
+	for _, rule := range ing.Spec.Rules {
+		ruleV1beta1 := v1beta1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			ruleV1beta1.HTTP = &v1beta1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				ruleV1beta1.HTTP.Paths = append(ruleV1beta1.HTTP.Paths, v1beta1.HTTPIngressPath{
+					Path:    path.Path,
+					Backend: ingressBackendFromV1(path.Backend),
+				})
+			}
+		}
+		out.Spec.Rules = append(out.Spec.Rules, ruleV1beta1)
+	}
+	return out, nil
+}
@@ -12,6 +12,8 @@ import (
 	core "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/juju/juju/caas"
@@ -177,6 +179,7 @@ func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesCreate(c *gc.C) {
 	}
 	s.assertIngressResources(
 		c, IngressResources, "",
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(nil, s.k8sNotFoundError()),
 		s.mockIngressInterface.EXPECT().Create(ingress).Return(ingress, nil),
 	)
 }
@@ -232,6 +235,7 @@ func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesUpdate(c *gc.C) {
 	}
 	s.assertIngressResources(
 		c, IngressResources, "",
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(nil, s.k8sNotFoundError()),
 		s.mockIngressInterface.EXPECT().Create(ingress).Return(nil, s.k8sAlreadyExistsError()),
 		s.mockIngressInterface.EXPECT().Get("test-ingress", v1.GetOptions{}).Return(ingress, nil),
 		s.mockIngressInterface.EXPECT().Update(ingress).Return(ingress, nil),
@@ -295,6 +299,7 @@ func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesUpdateConflictWithExis
 	existingNonJujuManagedIngress.SetLabels(map[string]string{})
 	s.assertIngressResources(
 		c, IngressResources, `creating or updating ingress resources: existing ingress "test-ingress" found which does not belong to "app-name"`,
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(nil, s.k8sNotFoundError()),
 		s.mockIngressInterface.EXPECT().Create(ingress).Return(nil, s.k8sAlreadyExistsError()),
 		s.mockIngressInterface.EXPECT().Get("test-ingress", v1.GetOptions{}).Return(existingNonJujuManagedIngress, nil),
 	)
@@ -337,3 +342,186 @@ func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesUpdateConflictWithIngr
 		c, IngressResources, `creating or updating ingress resources: ingress name "app-name" is reserved for juju expose not valid`,
 	)
 }
+
+func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesCreateV1(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	ingress1Rule1 := extensionsv1beta1.IngressRule{
+		Host: "test.com",
+		IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+			HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+				Paths: []extensionsv1beta1.HTTPIngressPath{
+					{
+						Path: "/testpath",
+						Backend: extensionsv1beta1.IngressBackend{
+							ServiceName: "test",
+							ServicePort: intstr.FromInt(80),
+						},
+					},
+				},
+			},
+		},
+	}
+	ingress1 := k8sspecs.K8sIngressSpec{
+		Name: "test-ingress",
+		Labels: map[string]string{
+			"foo": "bar",
+		},
+		Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/rewrite-target": "/",
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{ingress1Rule1},
+		},
+	}
+
+	ingressV1 := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata": map[string]interface{}{
+				"name":              "test-ingress",
+				"creationTimestamp": nil,
+				"labels": map[string]interface{}{
+					"foo":      "bar",
+					"juju-app": "app-name",
+				},
+				"annotations": map[string]interface{}{
+					"nginx.ingress.kubernetes.io/rewrite-target": "/",
+					"juju.io/controller":                         "deadbeef-1bad-500d-9000-4b1d0d06f00d",
+				},
+			},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"host": "test.com",
+						"http": map[string]interface{}{
+							"paths": []interface{}{
+								map[string]interface{}{
+									"path":     "/testpath",
+									"pathType": "ImplementationSpecific",
+									"backend": map[string]interface{}{
+										"service": map[string]interface{}{
+											"name": "test",
+											"port": map[string]interface{}{
+												"number": int64(80),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.assertIngressResources(
+		c, []k8sspecs.K8sIngressSpec{ingress1}, "",
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(&v1.APIResourceList{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []v1.APIResource{{Name: "networkpolicies"}, {Name: "ingresses"}},
+		}, nil),
+		s.mockDynamicClient.EXPECT().Resource(
+			schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+		).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Create(ingressV1, v1.CreateOptions{}).Return(ingressV1, nil),
+	)
+}
+
+func (s *K8sBrokerSuite) TestEnsureServiceIngressResourcesV1ParseError(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	ingress1 := k8sspecs.K8sIngressSpec{
+		Name: "test-ingress",
+		Spec: extensionsv1beta1.IngressSpec{
+			Backend: &extensionsv1beta1.IngressBackend{ServiceName: "test", ServicePort: intstr.FromInt(80)},
+		},
+	}
+	// An existing ingress whose spec can not be read must not be
+	// treated as one with an empty spec, as updating it would wipe
+	// the spec.
+	existing := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "Ingress",
+			"metadata": map[string]interface{}{
+				"name":   "test-ingress",
+				"labels": map[string]interface{}{"juju-app": "app-name"},
+			},
+			"spec": map[string]interface{}{
+				"rules": "not-a-list",
+			},
+		},
+	}
+	ingressResource := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	s.assertIngressResources(
+		c, []k8sspecs.K8sIngressSpec{ingress1},
+		`creating or updating ingress resources: parsing networking.k8s.io/v1 ingress "test-ingress": .*`,
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(&v1.APIResourceList{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []v1.APIResource{{Name: "ingresses"}},
+		}, nil),
+		s.mockDynamicClient.EXPECT().Resource(ingressResource).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Create(gomock.Any(), v1.CreateOptions{}).Return(nil, s.k8sAlreadyExistsError()),
+		s.mockDynamicClient.EXPECT().Resource(ingressResource).Return(s.mockNamespaceableResourceClient),
+		s.mockResourceClient.EXPECT().Get("test-ingress", v1.GetOptions{}).Return(existing, nil),
+	)
+}
+
+func (s *K8sBrokerSuite) TestExposeServiceWithTLSSecret(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	svc := &core.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "app-name"},
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{{Port: 80, TargetPort: intstr.FromInt(8080), Protocol: "TCP"}},
+		},
+	}
+	ingress := &extensionsv1beta1.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "app-name",
+			Labels: map[string]string{"juju-app": "app-name", "foo": "bar"},
+			Annotations: map[string]string{
+				"ingress.kubernetes.io/rewrite-target":  "",
+				"ingress.kubernetes.io/ssl-redirect":    "false",
+				"kubernetes.io/ingress.class":           "nginx",
+				"kubernetes.io/ingress.allow-http":      "false",
+				"ingress.kubernetes.io/ssl-passthrough": "false",
+			},
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			TLS: []extensionsv1beta1.IngressTLS{{
+				Hosts:      []string{"app.example.com"},
+				SecretName: "app-tls",
+			}},
+			Rules: []extensionsv1beta1.IngressRule{{
+				Host: "app.example.com",
+				IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+					HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+						Paths: []extensionsv1beta1.HTTPIngressPath{{
+							Path: "/app-name",
+							Backend: extensionsv1beta1.IngressBackend{
+								ServiceName: "app-name", ServicePort: intstr.FromInt(8080)},
+						}}},
+				}}},
+		},
+	}
+	gomock.InOrder(
+		s.mockStatefulSets.EXPECT().Get("juju-operator-app-name", v1.GetOptions{}).
+			Return(nil, s.k8sNotFoundError()),
+		s.mockServices.EXPECT().Get("app-name", v1.GetOptions{}).Return(svc, nil),
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(nil, s.k8sNotFoundError()),
+		s.mockIngressInterface.EXPECT().Create(ingress).Return(ingress, nil),
+	)
+
+	err := s.broker.ExposeService("app-name", map[string]string{"foo": "bar"}, application.ConfigAttributes{
+		"juju-external-hostname":        "app.example.com",
+		"juju-application-path":         "$appname",
+		"kubernetes-ingress-tls-secret": "app-tls",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -43,6 +43,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/caas/kubernetes/provider/exec"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
 	"github.com/juju/juju/caas/specs"
 	"github.com/juju/juju/cloudconfig/podcfg"
@@ -139,6 +140,14 @@ type kubernetesClient struct {
 	apiextensionsClientUnlocked apiextensionsclientset.Interface
 	dynamicClientUnlocked       dynamic.Interface
 
+	// registryCredentialsUnlocked holds the docker config JSON used for
+	// the model image pull secret, if any.
+	registryCredentialsUnlocked []byte
+
+	// ingressV1SupportedUnlocked caches whether the cluster behind
+	// clientUnlocked serves networking.k8s.io/v1 ingresses.
+	ingressV1SupportedUnlocked *bool
+
 	newClient     NewK8sClientFunc
 	newRestClient k8sspecs.NewK8sRestClientFunc
 
@@ -297,6 +306,12 @@ func (k *kubernetesClient) extendedClient() apiextensionsclientset.Interface {
 	return client
 }
 
+func (k *kubernetesClient) registryCredentials() []byte {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return k.registryCredentialsUnlocked
+}
+
 func (k *kubernetesClient) dynamicClient() dynamic.Interface {
 	k.lock.Lock()
 	defer k.lock.Unlock()
@@ -332,6 +347,24 @@ func (k *kubernetesClient) SetConfig(cfg *config.Config) error {
 
 // SetCloudSpec is specified in the environs.Environ interface.
 func (k *kubernetesClient) SetCloudSpec(spec environscloudspec.CloudSpec) error {
+	if err := k.setCloudSpec(spec); err != nil {
+		return errors.Trace(err)
+	}
+	// Propagate any rotated registry credentials to the model image pull
+	// secret, which is referenced by name from existing pods.
+	exists, err := k.ensureModelImagePullSecret()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !exists {
+		// Any secret left from earlier credentials, possibly set
+		// before this operator started, is removed.
+		return errors.Annotate(k.deleteSecret(modelImagePullSecretName, ""), "deleting model image pull secret")
+	}
+	return nil
+}
+
+func (k *kubernetesClient) setCloudSpec(spec environscloudspec.CloudSpec) error {
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
@@ -339,12 +372,18 @@ func (k *kubernetesClient) SetCloudSpec(spec environscloudspec.CloudSpec) error
 	if err != nil {
 		return errors.Annotate(err, "cannot set cloud spec")
 	}
+	registryCredentials, err := registryCredentialsFromCloudSpec(spec)
+	if err != nil {
+		return errors.Annotate(err, "cannot set cloud spec")
+	}
 
 	k.clientUnlocked, k.apiextensionsClientUnlocked, k.dynamicClientUnlocked, err = k.newClient(k8sRestConfig)
 	if err != nil {
 		return errors.Annotate(err, "cannot set cloud spec")
 	}
 	k.k8sCfgUnlocked = rest.CopyConfig(k8sRestConfig)
+	k.registryCredentialsUnlocked = registryCredentials
+	k.ingressV1SupportedUnlocked = nil
 
 	k.informerFactoryUnlocked = informers.NewSharedInformerFactoryWithOptions(
 		k.clientUnlocked,
@@ -1111,6 +1150,9 @@ func (k *kubernetesClient) ensureService(
 		}
 		cleanups = append(cleanups, func() { _ = k.deleteSecret(imageSecretName, "") })
 	}
+	if err := k.addModelImagePullSecret(&workloadSpec.Pod); err != nil {
+		return errors.Trace(err)
+	}
 	// Add a deployment controller or stateful set configured to create the specified number of units/pods.
 	// Defensively check to see if a stateful set is already used.
 	if params.Deployment.DeploymentType == "" {
@@ -1178,6 +1220,13 @@ func (k *kubernetesClient) ensureService(
 		// charm files to workload pods via init container when charm was upgraded.
 		// This approach was inspired from `kubectl rollout restart`.
 		Add(annotationCharmModifiedVersionKey, strconv.Itoa(params.CharmModifiedVersion))
+	if spaces := secondaryNetworkSpaces(params.EndpointBindings); len(spaces) > 0 {
+		networks, err := k.ensureNetworkAttachments(spaces)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		workloadResourceAnnotations.Add(multusNetworksKey, networks)
+	}
 
 	switch params.Deployment.DeploymentType {
 	case caas.DeploymentStateful:
@@ -1862,6 +1911,12 @@ func (k *kubernetesClient) ExposeService(appName string, resourceTags map[string
 				}}},
 		},
 	}
+	if tlsSecret := config.GetString(ingressTLSSecretKey, ""); tlsSecret != "" {
+		spec.Spec.TLS = []v1beta1.IngressTLS{{
+			Hosts:      []string{host},
+			SecretName: tlsSecret,
+		}}
+	}
 	// TODO(caas): refactor juju expose to solve potential conflict with ingress definition in podspec.
 	// https://bugs.launchpad.net/juju/+bug/1854123
 	_, err = k.ensureIngress(appName, spec, true)
@@ -2096,12 +2151,17 @@ func (k *kubernetesClient) Units(appName string, mode caas.DeploymentMode) ([]ca
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		spaceAddresses, err := podSpaceAddresses(k.namespace, p.Annotations)
+		if err != nil {
+			logger.Warningf("ignoring network status of pod %q: %v", p.Name, err)
+		}
 		unitInfo := caas.Unit{
-			Id:       providerID(&p),
-			Address:  p.Status.PodIP,
-			Ports:    ports,
-			Dying:    terminated,
-			Stateful: isStateful(&p),
+			Id:             providerID(&p),
+			Address:        p.Status.PodIP,
+			Ports:          ports,
+			Dying:          terminated,
+			Stateful:       isStateful(&p),
+			SpaceAddresses: spaceAddresses,
 			Status: status.StatusInfo{
 				Status:  unitStatus,
 				Message: statusMessage,
@@ -2629,3 +2689,8 @@ func isStateful(pod *core.Pod) bool {
 	}
 	return false
 }
+
+// ExecClient returns an exec client for the pods in the model namespace.
+func (k *kubernetesClient) ExecClient() exec.Executor {
+	return exec.New(k.namespace, k.client(), k.k8sConfig())
+}
@@ -1923,6 +1923,7 @@ func (s *K8sBrokerSuite) TestDeleteServiceForApplication(c *gc.C) {
 		).Return(nil),
 
 		// delete all ingress resources.
+		s.mockDiscovery.EXPECT().ServerResourcesForGroupVersion("networking.k8s.io/v1").Return(nil, s.k8sNotFoundError()),
 		s.mockIngressInterface.EXPECT().DeleteCollection(
 			s.deleteOptions(v1.DeletePropagationForeground, ""),
 			v1.ListOptions{LabelSelector: "juju-app=test"},
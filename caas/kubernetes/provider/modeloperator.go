@@ -177,7 +177,12 @@ func (k *kubernetesClient) EnsureModelOperator(
 			_, err := k.ensureConfigMap(c)
 			return err
 		},
-		ensureDeployment: k.ensureDeployment,
+		ensureDeployment: func(d *apps.Deployment) error {
+			if err := k.addModelImagePullSecret(&d.Spec.Template.Spec); err != nil {
+				return errors.Trace(err)
+			}
+			return k.ensureDeployment(d)
+		},
 		ensureService: func(svc *core.Service) error {
 			_, err := k.ensureK8sService(svc)
 			return err
@@ -0,0 +1,121 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/juju/juju/core/network"
+)
+
+const (
+	// multusNetworksKey is the pod annotation read by Multus to attach
+	// additional network interfaces to a pod.
+	multusNetworksKey = "k8s.v1.cni.cncf.io/networks"
+
+	// multusNetworkStatusKey is the pod annotation written by Multus
+	// recording the interfaces attached to a pod.
+	multusNetworkStatusKey = "k8s.v1.cni.cncf.io/network-status"
+
+	// multusLegacyNetworkStatusKey is the network status annotation
+	// written by Multus releases prior to 3.7.
+	multusLegacyNetworkStatusKey = "k8s.v1.cni.cncf.io/networks-status"
+)
+
+// networkAttachmentDefinitionResource is the resource defined by the
+// Multus NetworkAttachmentDefinition CRD.
+var networkAttachmentDefinitionResource = schema.GroupVersionResource{
+	Group:    "k8s.cni.cncf.io",
+	Version:  "v1",
+	Resource: "network-attachment-definitions",
+}
+
+// multusNetworkStatus is a single entry of the Multus network status annotation.
+type multusNetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// secondaryNetworkSpaces returns the sorted names of the spaces, other
+// than the default one, that the specified endpoint bindings require.
+// Each space is mapped to a NetworkAttachmentDefinition of the same
+// name in the model namespace.
+func secondaryNetworkSpaces(bindings map[string]string) []string {
+	spaces := set.NewStrings()
+	for _, spaceName := range bindings {
+		if spaceName == "" || spaceName == network.AlphaSpaceName {
+			continue
+		}
+		spaces.Add(spaceName)
+	}
+	return spaces.SortedValues()
+}
+
+// ensureNetworkAttachments checks that a NetworkAttachmentDefinition exists
+// for each of the spaces and returns the value of the Multus networks pod
+// annotation attaching them.
+func (k *kubernetesClient) ensureNetworkAttachments(spaces []string) (string, error) {
+	api := k.dynamicClient().Resource(networkAttachmentDefinitionResource).Namespace(k.namespace)
+	for _, spaceName := range spaces {
+		_, err := api.Get(spaceName, v1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return "", errors.NotFoundf("network attachment definition for space %q", spaceName)
+		}
+		if err != nil {
+			return "", errors.Annotatef(err, "getting network attachment definition for space %q", spaceName)
+		}
+	}
+	return strings.Join(spaces, ","), nil
+}
+
+// podSpaceAddresses returns the addresses of the secondary network interfaces
+// attached to a pod by Multus, keyed by the name of the space for the network.
+func podSpaceAddresses(namespace string, annotations map[string]string) (map[string]string, error) {
+	raw, ok := annotations[multusNetworkStatusKey]
+	if !ok {
+		raw, ok = annotations[multusLegacyNetworkStatusKey]
+	}
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	var statuses []multusNetworkStatus
+	if err := json.Unmarshal([]byte(raw), &statuses); err != nil {
+		return nil, errors.Annotate(err, "parsing multus network status")
+	}
+	// Sort by interface name so that the address reported for a space
+	// attached more than once is stable.
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Interface < statuses[j].Interface
+	})
+	var result map[string]string
+	for _, s := range statuses {
+		if s.Default || len(s.IPs) == 0 {
+			continue
+		}
+		name := s.Name
+		if parts := strings.SplitN(name, "/", 2); len(parts) == 2 {
+			if parts[0] != namespace {
+				continue
+			}
+			name = parts[1]
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		if _, ok := result[name]; !ok {
+			result[name] = s.IPs[0]
+		}
+	}
+	return result, nil
+}
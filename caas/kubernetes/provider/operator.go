@@ -216,6 +216,9 @@ func (k *kubernetesClient) EnsureOperator(appName, agentPath string, config *caa
 	if err != nil {
 		return errors.Annotate(err, "generating operator podspec")
 	}
+	if err := k.addModelImagePullSecret(&pod.Spec); err != nil {
+		return errors.Trace(err)
+	}
 	// Take a copy for use with statefulset.
 	podWithoutStorage := pod
 
@@ -107,6 +107,15 @@ func CloudSpecToK8sRestConfig(cloudSpec environscloudspec.CloudSpec) (*rest.Conf
 	}, nil
 }
 
+// registryCredentialsFromCloudSpec returns the validated docker config JSON
+// held by the cloud credential, if any.
+func registryCredentialsFromCloudSpec(cloudSpec environscloudspec.CloudSpec) ([]byte, error) {
+	if cloudSpec.Credential == nil {
+		return nil, nil
+	}
+	return parseRegistryCredentials(cloudSpec.Credential.Attributes()[CredAttrRegistryCredentials])
+}
+
 func newRestClient(cfg *rest.Config) (rest.Interface, error) {
 	return rest.RESTClientFor(cfg)
 }
@@ -121,6 +130,10 @@ func (p kubernetesEnvironProvider) Open(args environs.OpenParams) (caas.Broker,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	registryCredentials, err := registryCredentialsFromCloudSpec(args.Cloud)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// Guinea Pig broker to hunt for the namespace where a controller lives. We
 	// disregard this one in favour of a new one pinned to the correct
@@ -132,6 +145,7 @@ func (p kubernetesEnvironProvider) Open(args environs.OpenParams) (caas.Broker,
 	if err != nil {
 		return nil, err
 	}
+	broker.registryCredentialsUnlocked = registryCredentials
 
 	if args.Config.Name() != environsbootstrap.ControllerModelName {
 		return broker, nil
@@ -144,10 +158,15 @@ func (p kubernetesEnvironProvider) Open(args environs.OpenParams) (caas.Broker,
 		return broker, err
 	}
 
-	return newK8sBroker(
+	broker, err = newK8sBroker(
 		args.ControllerUUID, k8sRestConfig, args.Config, ns,
 		newK8sClient, newRestClient, newKubernetesNotifyWatcher, newKubernetesStringsWatcher,
 		randomPrefix, jujuclock.WallClock)
+	if err != nil {
+		return nil, err
+	}
+	broker.registryCredentialsUnlocked = registryCredentials
+	return broker, nil
 }
 
 // CloudSchema returns the schema for adding new clouds of this type.
@@ -17,6 +17,11 @@ import (
 	k8sannotations "github.com/juju/juju/core/annotations"
 )
 
+// modelImagePullSecretName is the name of the secret holding the model level
+// registry credentials. Its name never changes so that rotated credentials
+// are picked up by existing pods the next time they pull an image.
+const modelImagePullSecretName = "juju-image-pull-secret"
+
 func (k *kubernetesClient) getSecretLabels(appName string) map[string]string {
 	return map[string]string{
 		labelApplication: appName,
@@ -90,6 +95,51 @@ func (k *kubernetesClient) ensureOCIImageSecret(
 	return errors.Trace(err)
 }
 
+// ensureModelImagePullSecret ensures the model image pull secret reflects the
+// registry credentials of the model's cloud credential. It returns false if
+// there are no registry credentials configured.
+func (k *kubernetesClient) ensureModelImagePullSecret() (bool, error) {
+	secretData := k.registryCredentials()
+	if len(secretData) == 0 {
+		return false, nil
+	}
+	newSecret := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      modelImagePullSecretName,
+			Namespace: k.namespace,
+			Labels: map[string]string{
+				labelResourceLifeCycleKey: labelResourceLifeCycleValueModel,
+			},
+			Annotations: k.annotations.ToMap(),
+		},
+		Type: core.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			core.DockerConfigJsonKey: secretData,
+		},
+	}
+	logger.Debugf("ensuring model image pull secret %q", modelImagePullSecretName)
+	if _, err := k.ensureSecret(newSecret); err != nil {
+		return false, errors.Annotate(err, "ensuring model image pull secret")
+	}
+	return true, nil
+}
+
+// addModelImagePullSecret adds the model image pull secret, if
+// there is one, to the image pull secrets of the specified pod.
+func (k *kubernetesClient) addModelImagePullSecret(pod *core.PodSpec) error {
+	exists, err := k.ensureModelImagePullSecret()
+	if err != nil || !exists {
+		return errors.Trace(err)
+	}
+	for _, ref := range pod.ImagePullSecrets {
+		if ref.Name == modelImagePullSecretName {
+			return nil
+		}
+	}
+	pod.ImagePullSecrets = append(pod.ImagePullSecrets, core.LocalObjectReference{Name: modelImagePullSecretName})
+	return nil
+}
+
 func (k *kubernetesClient) ensureSecret(sec *core.Secret) (func(), error) {
 	cleanUp := func() {}
 	out, err := k.createSecret(sec)
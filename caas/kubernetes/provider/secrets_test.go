@@ -4,10 +4,16 @@
 package provider_test
 
 import (
+	"github.com/golang/mock/gomock"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	core "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/cloud"
+	environscloudspec "github.com/juju/juju/environs/cloudspec"
+	"github.com/juju/juju/testing"
 )
 
 var _ = gc.Suite(&secretsSuite{})
@@ -29,3 +35,82 @@ func (s *secretsSuite) TestProcessSecretData(c *gc.C) {
 		"password": []byte("1f2d1e2e67df"),
 	})
 }
+
+func (s *secretsSuite) cloudSpec(c *gc.C, registryCredentials string) environscloudspec.CloudSpec {
+	attrs := map[string]string{
+		"username":              "fred",
+		"password":              "secret",
+		"ClientCertificateData": "cert-data",
+		"ClientKeyData":         "cert-key",
+	}
+	if registryCredentials != "" {
+		attrs["registry-credentials"] = registryCredentials
+	}
+	cred := cloud.NewCredential(cloud.UserPassAuthType, attrs)
+	return environscloudspec.CloudSpec{
+		Endpoint:       "some-host",
+		Credential:     &cred,
+		CACertificates: []string{testing.CACert},
+	}
+}
+
+func (s *secretsSuite) TestSetCloudSpecRotatesModelImagePullSecret(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	registryCredentials := `{"auths":{"registry.example.com":{"username":"bob","password":"hunter2"}}}`
+	secret := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "juju-image-pull-secret",
+			Namespace: "test",
+			Labels:    map[string]string{"juju-resource-lifecycle": "model"},
+			Annotations: map[string]string{
+				"juju.io/model":      testing.ModelTag.Id(),
+				"juju.io/controller": testing.ControllerTag.Id(),
+			},
+		},
+		Type: core.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			core.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":{"Username":"bob","Password":"hunter2","Email":""}}}`),
+		},
+	}
+	gomock.InOrder(
+		s.mockSecrets.EXPECT().Create(secret).Return(secret, nil),
+		s.mockSecrets.EXPECT().Delete("juju-image-pull-secret", s.deleteOptions(v1.DeletePropagationForeground, "")).
+			Return(nil),
+	)
+
+	err := s.broker.SetCloudSpec(s.cloudSpec(c, registryCredentials))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Removing the registry credentials removes the secret.
+	err = s.broker.SetCloudSpec(s.cloudSpec(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestSetCloudSpecDeletesStaleModelImagePullSecret(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	// A secret left by an earlier operator is deleted, and a missing
+	// secret is not an error.
+	gomock.InOrder(
+		s.mockSecrets.EXPECT().Delete("juju-image-pull-secret", s.deleteOptions(v1.DeletePropagationForeground, "")).
+			Return(nil),
+		s.mockSecrets.EXPECT().Delete("juju-image-pull-secret", s.deleteOptions(v1.DeletePropagationForeground, "")).
+			Return(s.k8sNotFoundError()),
+	)
+
+	err := s.broker.SetCloudSpec(s.cloudSpec(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.broker.SetCloudSpec(s.cloudSpec(c, ""))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secretsSuite) TestSetCloudSpecInvalidRegistryCredentials(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	err := s.broker.SetCloudSpec(s.cloudSpec(c, `{"auths":{"registry.example.com":{"username":"bob"}}}`))
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud spec: registry credentials for "registry.example.com" without username and password not valid`)
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Risk describes the stability of the charms released to a channel.
+type Risk string
+
+const (
+	Stable    Risk = "stable"
+	Candidate Risk = "candidate"
+	Beta      Risk = "beta"
+	Edge      Risk = "edge"
+)
+
+func isRisk(s string) bool {
+	switch Risk(s) {
+	case Stable, Candidate, Beta, Edge:
+		return true
+	}
+	return false
+}
+
+// DefaultTrack is the track used when a channel does not specify one.
+const DefaultTrack = "latest"
+
+// Channel identifies the charms released with a given risk on a given
+// track, optionally on a short lived branch of that track.
+type Channel struct {
+	Track  string
+	Risk   Risk
+	Branch string
+}
+
+// ParseChannel parses a channel of the form [track/]risk[/branch]. A
+// channel consisting only of a track selects the stable risk of that
+// track, and the default track is used when none is specified.
+func ParseChannel(s string) (Channel, error) {
+	if s == "" {
+		return Channel{}, errors.NotValidf("empty channel")
+	}
+	parts := strings.Split(s, "/")
+	for _, part := range parts {
+		if part == "" {
+			return Channel{}, errors.NotValidf("channel %q", s)
+		}
+	}
+	var ch Channel
+	switch len(parts) {
+	case 1:
+		if isRisk(parts[0]) {
+			ch = Channel{Risk: Risk(parts[0])}
+		} else {
+			ch = Channel{Track: parts[0], Risk: Stable}
+		}
+	case 2:
+		if isRisk(parts[0]) {
+			ch = Channel{Risk: Risk(parts[0]), Branch: parts[1]}
+		} else if isRisk(parts[1]) {
+			ch = Channel{Track: parts[0], Risk: Risk(parts[1])}
+		} else {
+			return Channel{}, errors.NotValidf("risk in channel %q", s)
+		}
+	case 3:
+		if !isRisk(parts[1]) {
+			return Channel{}, errors.NotValidf("risk in channel %q", s)
+		}
+		ch = Channel{Track: parts[0], Risk: Risk(parts[1]), Branch: parts[2]}
+	default:
+		return Channel{}, errors.NotValidf("channel %q", s)
+	}
+	if ch.Track == "" {
+		ch.Track = DefaultTrack
+	}
+	return ch, nil
+}
+
+// String returns the fully qualified channel, as understood by charmhub.
+func (ch Channel) String() string {
+	path := ch.Track + "/" + string(ch.Risk)
+	if ch.Branch != "" {
+		path += "/" + ch.Branch
+	}
+	return path
+}
+
+// risks holds the channel risks, from the most to the least stable.
+var risks = []Risk{Stable, Candidate, Beta, Edge}
+
+// Fallbacks returns the channels to search, in order, for a revision
+// released to the channel. Revisions released with a more stable risk on
+// the same track are also available to a channel without a branch.
+func (ch Channel) Fallbacks() []Channel {
+	if ch.Branch != "" {
+		return []Channel{ch}
+	}
+	var result []Channel
+	for i := len(risks) - 1; i >= 0; i-- {
+		if len(result) == 0 && risks[i] != ch.Risk {
+			continue
+		}
+		result = append(result, Channel{Track: ch.Track, Risk: risks[i]})
+	}
+	return result
+}
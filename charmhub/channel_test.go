@@ -0,0 +1,84 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type ChannelSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ChannelSuite{})
+
+func (s *ChannelSuite) TestParseChannel(c *gc.C) {
+	tests := []struct {
+		channel  string
+		expected Channel
+		str      string
+	}{{
+		channel:  "stable",
+		expected: Channel{Track: "latest", Risk: Stable},
+		str:      "latest/stable",
+	}, {
+		channel:  "2.0",
+		expected: Channel{Track: "2.0", Risk: Stable},
+		str:      "2.0/stable",
+	}, {
+		channel:  "2.0/edge",
+		expected: Channel{Track: "2.0", Risk: Edge},
+		str:      "2.0/edge",
+	}, {
+		channel:  "candidate/fix-1234",
+		expected: Channel{Track: "latest", Risk: Candidate, Branch: "fix-1234"},
+		str:      "latest/candidate/fix-1234",
+	}, {
+		channel:  "2.0/beta/fix-1234",
+		expected: Channel{Track: "2.0", Risk: Beta, Branch: "fix-1234"},
+		str:      "2.0/beta/fix-1234",
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %q", i, test.channel)
+		ch, err := ParseChannel(test.channel)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(ch, gc.Equals, test.expected)
+		c.Check(ch.String(), gc.Equals, test.str)
+	}
+}
+
+func (s *ChannelSuite) TestParseChannelInvalid(c *gc.C) {
+	tests := []struct {
+		channel string
+		err     string
+	}{
+		{"", "empty channel not valid"},
+		{"2.0/", `channel "2.0/" not valid`},
+		{"2.0/foo", `risk in channel "2.0/foo" not valid`},
+		{"2.0/foo/fix-1234", `risk in channel "2.0/foo/fix-1234" not valid`},
+		{"2.0/edge/fix/1234", `channel "2.0/edge/fix/1234" not valid`},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %q", i, test.channel)
+		_, err := ParseChannel(test.channel)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ChannelSuite) TestFallbacks(c *gc.C) {
+	ch := Channel{Track: "2.0", Risk: Beta}
+	c.Assert(ch.Fallbacks(), jc.DeepEquals, []Channel{
+		{Track: "2.0", Risk: Beta},
+		{Track: "2.0", Risk: Candidate},
+		{Track: "2.0", Risk: Stable},
+	})
+
+	ch = Channel{Track: "latest", Risk: Stable}
+	c.Assert(ch.Fallbacks(), jc.DeepEquals, []Channel{ch})
+
+	ch = Channel{Track: "latest", Risk: Edge, Branch: "fix-1234"}
+	c.Assert(ch.Fallbacks(), jc.DeepEquals, []Channel{ch})
+}
@@ -0,0 +1,448 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmhubtest provides an in-process fake of the charmhub API
+// for use in tests.
+package charmhubtest
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+const (
+	apiPrefix      = "/" + charmhub.CharmhubServerVersion + "/" + charmhub.CharmhubServerEntity + "/"
+	downloadPrefix = "/download/"
+)
+
+// risks holds the charmhub risks, from the most to the least stable. A
+// channel with nothing released to it follows the next more stable risk
+// of the same track.
+var risks = []charmhub.Risk{
+	charmhub.Stable,
+	charmhub.Candidate,
+	charmhub.Beta,
+	charmhub.Edge,
+}
+
+// Server is a fake charmhub serving the info, find and refresh endpoints
+// of the API, along with the charm archives it refers to. Charm revisions
+// are added with AddRevision and then released to channels with Release.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	charms map[string]*fakeCharm
+	// actions records the refresh actions requested, in order.
+	actions []string
+}
+
+type fakeCharm struct {
+	id        string
+	name      string
+	revisions map[int]*fakeRevision
+	// releases maps each fully qualified channel to the revisions
+	// released to it.
+	releases map[string][]int
+}
+
+type fakeRevision struct {
+	revision  int
+	version   string
+	platforms []transport.Platform
+	archive   []byte
+	hash      string
+}
+
+// NewServer starts and returns a new fake charmhub server. The server
+// must be closed when it is no longer needed. Clients connect to it
+// using charmhub.CharmhubConfigFromURL(server.URL).
+func NewServer() *Server {
+	s := &Server{
+		charms: make(map[string]*fakeCharm),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiPrefix+"info/", s.serveInfo)
+	mux.HandleFunc(apiPrefix+"find", s.serveFind)
+	mux.HandleFunc(apiPrefix+"refresh", s.serveRefresh)
+	mux.HandleFunc(downloadPrefix, s.serveDownload)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddRevision adds a revision of the named charm, built for the specified
+// platforms, with the contents of a charm archive. A platform series or
+// architecture of "all" matches any series or architecture.
+func (s *Server) AddRevision(name string, revision int, version string, platforms []transport.Platform, archive []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch, ok := s.charms[name]
+	if !ok {
+		ch = &fakeCharm{
+			id:        fmt.Sprintf("charm-id-%s", name),
+			name:      name,
+			revisions: make(map[int]*fakeRevision),
+			releases:  make(map[string][]int),
+		}
+		s.charms[name] = ch
+	}
+	hash := sha512.Sum384(archive)
+	ch.revisions[revision] = &fakeRevision{
+		revision:  revision,
+		version:   version,
+		platforms: platforms,
+		archive:   archive,
+		hash:      hex.EncodeToString(hash[:]),
+	}
+}
+
+// Release releases a revision of the named charm to a channel.
+func (s *Server) Release(name string, revision int, channel string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch, ok := s.charms[name]
+	if !ok {
+		return errors.NotFoundf("charm %q", name)
+	}
+	if _, ok := ch.revisions[revision]; !ok {
+		return errors.NotFoundf("revision %d of charm %q", revision, name)
+	}
+	parsed, err := charmhub.ParseChannel(channel)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch.releases[parsed.String()] = append(ch.releases[parsed.String()], revision)
+	return nil
+}
+
+// ArchiveHash returns the SHA-384 hash of the archive of a charm revision.
+func (s *Server) ArchiveHash(name string, revision int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ch, ok := s.charms[name]; ok {
+		if rev, ok := ch.revisions[revision]; ok {
+			return rev.hash
+		}
+	}
+	return ""
+}
+
+// RefreshActions returns the actions requested of the refresh endpoint,
+// in the order they were requested.
+func (s *Server) RefreshActions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.actions...)
+}
+
+// CorruptArchive replaces the contents of the archive of a charm revision
+// without updating its hash, for testing download verification.
+func (s *Server) CorruptArchive(name string, revision int, archive []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.charms[name].revisions[revision].archive = archive
+}
+
+func (s *Server) serveInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, apiPrefix+"info/")
+	ch, ok := s.charms[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not-found", fmt.Sprintf("No charm or bundle with name %q.", name))
+		return
+	}
+	resp := transport.InfoResponse{
+		Type: "charm",
+		ID:   ch.id,
+		Name: ch.name,
+	}
+	for _, channel := range ch.channels() {
+		parsed, _ := charmhub.ParseChannel(channel)
+		for _, rev := range ch.releasedRevisions(channel) {
+			for _, platform := range rev.platforms {
+				resp.ChannelMap = append(resp.ChannelMap, transport.ChannelMap{
+					Channel: transport.Channel{
+						Name:     channel,
+						Platform: platform,
+						Risk:     string(parsed.Risk),
+						Track:    parsed.Track,
+					},
+					Revision: s.transportRevision(ch, rev),
+				})
+			}
+		}
+	}
+	if len(resp.ChannelMap) > 0 {
+		resp.DefaultRelease = resp.ChannelMap[0]
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveFind(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query().Get("q")
+	var names []string
+	for name := range s.charms {
+		if strings.Contains(name, query) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	resp := transport.FindResponses{Results: []transport.FindResponse{}}
+	for _, name := range names {
+		ch := s.charms[name]
+		resp.Results = append(resp.Results, transport.FindResponse{
+			Type: "charm",
+			ID:   ch.id,
+			Name: ch.name,
+		})
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "method-not-allowed", "refresh requires POST")
+		return
+	}
+	var req transport.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contexts := make(map[string]transport.RefreshRequestContext)
+	for _, ctx := range req.Context {
+		contexts[ctx.InstanceKey] = ctx
+	}
+	resp := transport.RefreshResponses{Results: []transport.RefreshResponse{}}
+	for _, action := range req.Actions {
+		s.actions = append(s.actions, action.Action)
+		resp.Results = append(resp.Results, s.refreshAction(action, contexts))
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) refreshAction(
+	action transport.RefreshRequestAction,
+	contexts map[string]transport.RefreshRequestContext,
+) transport.RefreshResponse {
+	result := transport.RefreshResponse{
+		InstanceKey: action.InstanceKey,
+		Result:      action.Action,
+	}
+	fail := func(code, message string) transport.RefreshResponse {
+		result.Result = "error"
+		result.Error = &transport.APIError{Code: code, Message: message}
+		return result
+	}
+
+	var (
+		ch       *fakeCharm
+		channel  string
+		revision *int
+		platform transport.Platform
+	)
+	switch action.Action {
+	case "install", "download":
+		if action.Name == nil || action.Platform == nil {
+			return fail("invalid-request", "name and platform required")
+		}
+		ch = s.charms[*action.Name]
+		if ch == nil {
+			return fail("name-not-found", fmt.Sprintf("Name %q not found in the charm store.", *action.Name))
+		}
+		if action.Channel != nil {
+			channel = *action.Channel
+		}
+		revision = action.Revision
+		platform = *action.Platform
+	case "refresh":
+		ctx, ok := contexts[action.InstanceKey]
+		if !ok {
+			return fail("invalid-request", fmt.Sprintf("no context for instance key %q", action.InstanceKey))
+		}
+		ch = s.charmByID(ctx.ID)
+		if ch == nil {
+			return fail("id-not-found", fmt.Sprintf("ID %q not found in the charm store.", ctx.ID))
+		}
+		channel = ctx.TrackingChannel
+		platform = ctx.Platform
+	default:
+		return fail("invalid-request", fmt.Sprintf("unknown action %q", action.Action))
+	}
+
+	var rev *fakeRevision
+	if revision != nil {
+		rev = ch.revisions[*revision]
+		if rev == nil || !supportsPlatform(rev, platform) {
+			return fail("revision-not-found", fmt.Sprintf("Revision %d of %q not found for %s/%s.",
+				*revision, ch.name, platform.Series, platform.Architecture))
+		}
+	} else {
+		var err error
+		rev, channel, err = ch.resolveChannel(channel, platform)
+		if err != nil {
+			return fail("channel-not-found", err.Error())
+		}
+	}
+
+	result.ID = ch.id
+	result.Name = ch.name
+	result.EffectiveChannel = channel
+	result.Entity = transport.RefreshEntity{
+		Download:  s.transportRevision(ch, rev).Download,
+		ID:        ch.id,
+		Name:      ch.name,
+		Platforms: rev.platforms,
+		Revision:  rev.revision,
+		Type:      "charm",
+		Version:   rev.version,
+	}
+	return result
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var name string
+	var revision int
+	file := strings.TrimPrefix(r.URL.Path, downloadPrefix)
+	if _, err := fmt.Sscanf(strings.Replace(file, "_", " ", -1), "%s %d.charm", &name, &revision); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	ch, ok := s.charms[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rev, ok := ch.revisions[revision]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(rev.archive)
+}
+
+func (s *Server) charmByID(id string) *fakeCharm {
+	for _, ch := range s.charms {
+		if ch.id == id {
+			return ch
+		}
+	}
+	return nil
+}
+
+func (s *Server) transportRevision(ch *fakeCharm, rev *fakeRevision) transport.Revision {
+	return transport.Revision{
+		Download: transport.Download{
+			HashSHA384: rev.hash,
+			Size:       len(rev.archive),
+			URL:        fmt.Sprintf("%s%s%s_%d.charm", s.URL, downloadPrefix, ch.name, rev.revision),
+		},
+		Platforms: rev.platforms,
+		Revision:  rev.revision,
+		Version:   rev.version,
+	}
+}
+
+func (ch *fakeCharm) channels() []string {
+	var channels []string
+	for channel := range ch.releases {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func (ch *fakeCharm) releasedRevisions(channel string) []*fakeRevision {
+	var revs []*fakeRevision
+	for _, revision := range ch.releases[channel] {
+		revs = append(revs, ch.revisions[revision])
+	}
+	return revs
+}
+
+// resolveChannel returns the latest revision supporting the platform
+// released to the channel, following the more stable risks of the
+// channel's track when nothing suitable is released to it. The channel
+// the revision was released to is also returned.
+func (ch *fakeCharm) resolveChannel(channel string, platform transport.Platform) (*fakeRevision, string, error) {
+	if channel == "" {
+		channel = string(charmhub.Stable)
+	}
+	parsed, err := charmhub.ParseChannel(channel)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	candidates := []charmhub.Channel{parsed}
+	if parsed.Branch == "" {
+		candidates = nil
+		for i := len(risks) - 1; i >= 0; i-- {
+			if risks[i] == parsed.Risk || len(candidates) > 0 {
+				candidates = append(candidates, charmhub.Channel{Track: parsed.Track, Risk: risks[i]})
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		var latest *fakeRevision
+		for _, rev := range ch.releasedRevisions(candidate.String()) {
+			if supportsPlatform(rev, platform) && (latest == nil || rev.revision > latest.revision) {
+				latest = rev
+			}
+		}
+		if latest != nil {
+			return latest, candidate.String(), nil
+		}
+	}
+	return nil, "", errors.Errorf("No revision of %q released to %q for %s/%s.",
+		ch.name, parsed.String(), platform.Series, platform.Architecture)
+}
+
+func supportsPlatform(rev *fakeRevision, platform transport.Platform) bool {
+	for _, p := range rev.platforms {
+		if (p.Series == "all" || p.Series == platform.Series) &&
+			(p.Architecture == "all" || p.Architecture == platform.Architecture) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(transport.APIError{Code: code, Message: message})
+}
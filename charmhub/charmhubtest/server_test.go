@@ -0,0 +1,168 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhubtest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/charmhubtest"
+	"github.com/juju/juju/charmhub/transport"
+	"github.com/juju/juju/testcharms"
+)
+
+type ServerSuite struct {
+	testing.IsolationSuite
+
+	server  *charmhubtest.Server
+	client  *charmhub.Client
+	archive []byte
+}
+
+var _ = gc.Suite(&ServerSuite{})
+
+var bionicAMD64 = charmhub.RefreshPlatform{
+	Architecture: "amd64",
+	Series:       "bionic",
+}
+
+func (s *ServerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.server = charmhubtest.NewServer()
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	var err error
+	s.client, err = charmhub.NewClient(charmhub.CharmhubConfigFromURL(s.server.URL))
+	c.Assert(err, jc.ErrorIsNil)
+
+	archivePath := testcharms.Repo.CharmArchivePath(c.MkDir(), "dummy")
+	s.archive, err = ioutil.ReadFile(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	bionic := []transport.Platform{{Architecture: "all", OS: "ubuntu", Series: "bionic"}}
+	focal := []transport.Platform{{Architecture: "amd64", OS: "ubuntu", Series: "focal"}}
+	s.server.AddRevision("dummy", 1, "1.0", bionic, s.archive)
+	s.server.AddRevision("dummy", 2, "1.1", bionic, s.archive)
+	s.server.AddRevision("dummy", 3, "2.0", focal, s.archive)
+	c.Assert(s.server.Release("dummy", 1, "stable"), jc.ErrorIsNil)
+	c.Assert(s.server.Release("dummy", 2, "beta"), jc.ErrorIsNil)
+	c.Assert(s.server.Release("dummy", 3, "stable"), jc.ErrorIsNil)
+}
+
+// refresh returns a function making a refresh call with the config
+// returned by the refresh config constructors.
+func (s *ServerSuite) refresh(c *gc.C) func(charmhub.RefreshConfig, error) (transport.RefreshResponse, error) {
+	return func(config charmhub.RefreshConfig, err error) (transport.RefreshResponse, error) {
+		c.Assert(err, jc.ErrorIsNil)
+		responses, err := s.client.Refresh(context.TODO(), config)
+		if err != nil {
+			return transport.RefreshResponse{}, err
+		}
+		c.Assert(responses, gc.HasLen, 1)
+		return responses[0], nil
+	}
+}
+
+func (s *ServerSuite) TestInstallFromChannel(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.InstallOneFromChannel("dummy", "stable", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Name, gc.Equals, "dummy")
+	c.Assert(resp.EffectiveChannel, gc.Equals, "latest/stable")
+	c.Assert(resp.Entity.Revision, gc.Equals, 1)
+	c.Assert(resp.Entity.Download.HashSHA384, gc.Equals, s.server.ArchiveHash("dummy", 1))
+}
+
+func (s *ServerSuite) TestInstallFromChannelFollowsMoreStableRisks(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.InstallOneFromChannel("dummy", "edge", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.EffectiveChannel, gc.Equals, "latest/beta")
+	c.Assert(resp.Entity.Revision, gc.Equals, 2)
+}
+
+func (s *ServerSuite) TestInstallFromChannelSelectsPlatform(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.InstallOneFromChannel("dummy", "stable", charmhub.RefreshPlatform{
+		Architecture: "amd64",
+		Series:       "focal",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Entity.Revision, gc.Equals, 3)
+
+	_, err = s.refresh(c)(charmhub.InstallOneFromChannel("dummy", "stable", charmhub.RefreshPlatform{
+		Architecture: "arm64",
+		Series:       "focal",
+	}))
+	c.Assert(err, gc.ErrorMatches, `No revision of "dummy" released to "latest/stable" for focal/arm64.`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServerSuite) TestInstallFromRevision(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.InstallOneFromRevision("dummy", 2, bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Entity.Revision, gc.Equals, 2)
+	c.Assert(resp.Entity.Version, gc.Equals, "1.1")
+
+	_, err = s.refresh(c)(charmhub.InstallOneFromRevision("dummy", 3, bionicAMD64))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServerSuite) TestInstallUnknownCharm(c *gc.C) {
+	_, err := s.refresh(c)(charmhub.InstallOneFromChannel("unknown", "stable", bionicAMD64))
+	c.Assert(err, gc.ErrorMatches, `Name "unknown" not found in the charm store.`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ServerSuite) TestRefresh(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.InstallOneFromChannel("dummy", "stable", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err = s.refresh(c)(charmhub.RefreshOne(resp.ID, 1, "beta", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Result, gc.Equals, "refresh")
+	c.Assert(resp.Entity.Revision, gc.Equals, 2)
+}
+
+func (s *ServerSuite) TestDownloadAndRead(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.DownloadOneFromChannel("dummy", "stable", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+
+	resourceURL, err := url.Parse(resp.Entity.Download.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	archivePath := filepath.Join(c.MkDir(), "dummy.charm")
+	archive, err := s.client.DownloadAndRead(context.TODO(), resourceURL, archivePath, resp.Entity.Download.HashSHA384)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive.Meta().Name, gc.Equals, "dummy")
+}
+
+func (s *ServerSuite) TestDownloadVerifiesHash(c *gc.C) {
+	resp, err := s.refresh(c)(charmhub.DownloadOneFromChannel("dummy", "stable", bionicAMD64))
+	c.Assert(err, jc.ErrorIsNil)
+	s.server.CorruptArchive("dummy", 1, []byte("corrupt"))
+
+	resourceURL, err := url.Parse(resp.Entity.Download.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	archivePath := filepath.Join(c.MkDir(), "dummy.charm")
+	_, err = s.client.DownloadAndRead(context.TODO(), resourceURL, archivePath, resp.Entity.Download.HashSHA384)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ServerSuite) TestInfoAndFind(c *gc.C) {
+	info, err := s.client.Info(context.TODO(), "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Name, gc.Equals, "dummy")
+	c.Assert(info.ChannelMap, gc.HasLen, 3)
+
+	found, err := s.client.Find(context.TODO(), "dum")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 1)
+	c.Assert(found[0].Name, gc.Equals, "dummy")
+}
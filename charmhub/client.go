@@ -22,6 +22,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/juju/charm/v7"
 	"github.com/juju/errors"
 
 	charmhubpath "github.com/juju/juju/charmhub/path"
@@ -87,9 +88,11 @@ func (c Config) BasePath() (charmhubpath.Path, error) {
 
 // Client represents the client side of a charm store.
 type Client struct {
-	url        string
-	infoClient *InfoClient
-	findClient *FindClient
+	url            string
+	infoClient     *InfoClient
+	findClient     *FindClient
+	refreshClient  *RefreshClient
+	downloadClient *DownloadClient
 }
 
 // NewClient creates a new charmhub client from the supplied configuration.
@@ -109,14 +112,21 @@ func NewClient(config Config) (*Client, error) {
 		return nil, errors.Annotate(err, "constructing find path")
 	}
 
+	refreshPath, err := base.Join("refresh")
+	if err != nil {
+		return nil, errors.Annotate(err, "constructing refresh path")
+	}
+
 	httpClient := DefaultHTTPTransport()
 	apiRequester := NewAPIRequester(httpClient)
 	restClient := NewHTTPRESTClient(apiRequester)
 
 	return &Client{
-		url:        base.String(),
-		infoClient: NewInfoClient(infoPath, restClient),
-		findClient: NewFindClient(findPath, restClient),
+		url:            base.String(),
+		infoClient:     NewInfoClient(infoPath, restClient),
+		findClient:     NewFindClient(findPath, restClient),
+		refreshClient:  NewRefreshClient(refreshPath, restClient),
+		downloadClient: NewDownloadClient(apiRequester),
 	}, nil
 }
 
@@ -134,3 +144,22 @@ func (c *Client) Info(ctx context.Context, name string) (transport.InfoResponse,
 func (c *Client) Find(ctx context.Context, name string) ([]transport.FindResponse, error) {
 	return c.findClient.Find(ctx, name)
 }
+
+// Refresh defines a client for making refresh API calls, which select the
+// revisions of charms to install, refresh or download.
+func (c *Client) Refresh(ctx context.Context, config RefreshConfig) ([]transport.RefreshResponse, error) {
+	return c.refreshClient.Refresh(ctx, config)
+}
+
+// Download downloads the charm archive at the resource URL into the
+// archive path, verifying it against the required SHA-384 hash.
+func (c *Client) Download(ctx context.Context, resourceURL *url.URL, archivePath, hashSHA384 string) error {
+	return c.downloadClient.Download(ctx, resourceURL, archivePath, hashSHA384)
+}
+
+// DownloadAndRead downloads the charm archive at the resource URL into
+// the archive path, verifying it against the required SHA-384 hash, and
+// reads the charm from it.
+func (c *Client) DownloadAndRead(ctx context.Context, resourceURL *url.URL, archivePath, hashSHA384 string) (*charm.CharmArchive, error) {
+	return c.downloadClient.DownloadAndRead(ctx, resourceURL, archivePath, hashSHA384)
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/errors"
+)
+
+// DownloadClient defines a client for downloading charm archives.
+type DownloadClient struct {
+	transport Transport
+}
+
+// NewDownloadClient creates a DownloadClient for downloading charm archives.
+func NewDownloadClient(transport Transport) *DownloadClient {
+	return &DownloadClient{
+		transport: transport,
+	}
+}
+
+// DownloadAndRead downloads the charm archive at the resource URL into
+// the archive path, verifying it against the required SHA-384 hash, and
+// reads the charm from it.
+func (c *DownloadClient) DownloadAndRead(ctx context.Context, resourceURL *url.URL, archivePath, hashSHA384 string) (*charm.CharmArchive, error) {
+	if err := c.Download(ctx, resourceURL, archivePath, hashSHA384); err != nil {
+		return nil, errors.Trace(err)
+	}
+	archive, err := charm.ReadCharmArchive(archivePath)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading charm archive %q", archivePath)
+	}
+	return archive, nil
+}
+
+// Download downloads the charm archive at the resource URL into the
+// archive path. The contents of the archive are verified against the
+// SHA-384 hash, which is required, and the archive is removed if they
+// don't match.
+func (c *DownloadClient) Download(ctx context.Context, resourceURL *url.URL, archivePath, hashSHA384 string) (err error) {
+	if hashSHA384 == "" {
+		return errors.NotValidf("missing SHA-384 hash for %q,", resourceURL.String())
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = f.Close()
+		if err != nil {
+			_ = os.Remove(archivePath)
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", resourceURL.String(), nil)
+	if err != nil {
+		return errors.Annotate(err, "can not make new request")
+	}
+	resp, err := c.transport.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Ensure that we get a valid response from the server.
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("cannot retrieve %q: got http status %d", resourceURL.String(), resp.StatusCode)
+	}
+
+	hash := sha512.New384()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		return errors.Annotatef(err, "downloading %q", resourceURL.String())
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != hashSHA384 {
+		return errors.NotValidf("downloaded archive with SHA-384 %q, expected %q,", actual, hashSHA384)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type DownloadSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&DownloadSuite{})
+
+func (s *DownloadSuite) serve(c *gc.C, content string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(content))
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	return server
+}
+
+func (s *DownloadSuite) TestDownload(c *gc.C) {
+	server := s.serve(c, "archive")
+	hash := sha512.Sum384([]byte("archive"))
+	archivePath := filepath.Join(c.MkDir(), "archive.charm")
+
+	client := NewDownloadClient(NewAPIRequester(DefaultHTTPTransport()))
+	err := client.Download(context.TODO(), MustParseURL(c, server.URL), archivePath, hex.EncodeToString(hash[:]))
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+}
+
+func (s *DownloadSuite) TestDownloadWithoutHash(c *gc.C) {
+	server := s.serve(c, "archive")
+	archivePath := filepath.Join(c.MkDir(), "archive.charm")
+
+	client := NewDownloadClient(NewAPIRequester(DefaultHTTPTransport()))
+	err := client.Download(context.TODO(), MustParseURL(c, server.URL), archivePath, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `missing SHA-384 hash for ".*", not valid`)
+
+	_, err = os.Stat(archivePath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *DownloadSuite) TestDownloadHashMismatch(c *gc.C) {
+	server := s.serve(c, "tampered")
+	hash := sha512.Sum384([]byte("archive"))
+	archivePath := filepath.Join(c.MkDir(), "archive.charm")
+
+	client := NewDownloadClient(NewAPIRequester(DefaultHTTPTransport()))
+	err := client.Download(context.TODO(), MustParseURL(c, server.URL), archivePath, hex.EncodeToString(hash[:]))
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `downloaded archive with SHA-384 ".*", expected ".*", not valid`)
+
+	_, err = os.Stat(archivePath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
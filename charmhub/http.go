@@ -4,6 +4,7 @@
 package charmhub
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io/ioutil"
@@ -84,6 +85,9 @@ func (t *APIRequester) Do(req *http.Request) (*http.Response, error) {
 type RESTClient interface {
 	// Get performs GET requests to a given Path.
 	Get(context.Context, path.Path, interface{}) error
+	// Post performs POST requests to a given Path, sending the request
+	// body as JSON.
+	Post(context.Context, path.Path, interface{}, interface{}) error
 }
 
 // HTTPRESTClient represents a RESTClient that expects to interact with a
@@ -121,3 +125,29 @@ func (c *HTTPRESTClient) Get(ctx context.Context, path path.Path, result interfa
 	}
 	return nil
 }
+
+// Post makes a POST request to the given path in the charmhub, sending
+// the body encoded as JSON and parsing the result as JSON into the given
+// result value, which should be a pointer to the expected data.
+func (c *HTTPRESTClient) Post(ctx context.Context, path path.Path, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errors.Annotate(err, "can not marshal request body")
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", path.String(), bytes.NewReader(data))
+	if err != nil {
+		return errors.Annotate(err, "can not make new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.transport.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Parse the response.
+	if err := httprequest.UnmarshalJSONResponse(resp, result); err != nil {
+		return errors.Annotate(err, "charm hub client post")
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/charmhub/path"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+// Refresh actions understood by the charmhub refresh endpoint.
+const (
+	installAction  = "install"
+	refreshAction  = "refresh"
+	downloadAction = "download"
+)
+
+// RefreshPlatform describes the platform a charm is to be deployed on,
+// which is used to select the revision of the charm built for it.
+type RefreshPlatform struct {
+	Architecture string
+	OS           string
+	Series       string
+}
+
+func (p RefreshPlatform) validate() error {
+	if p.Architecture == "" {
+		return errors.NotValidf("platform with empty architecture")
+	}
+	if p.Series == "" {
+		return errors.NotValidf("platform with empty series")
+	}
+	return nil
+}
+
+func (p RefreshPlatform) transport() transport.Platform {
+	os := p.OS
+	if os == "" {
+		os = "ubuntu"
+	}
+	return transport.Platform{
+		Architecture: p.Architecture,
+		OS:           os,
+		Series:       p.Series,
+	}
+}
+
+// RefreshConfig defines a type for building the request of a refresh call.
+type RefreshConfig interface {
+	// Build a refresh request for sending to the API.
+	Build() (transport.RefreshRequest, error)
+}
+
+// refreshOne holds the config for refreshing a single installed charm.
+type refreshOne struct {
+	instanceKey string
+	id          string
+	revision    int
+	channel     Channel
+	platform    RefreshPlatform
+}
+
+// RefreshOne creates a request config for requesting the latest revision
+// of the installed charm with the specified ID and revision, released to
+// the channel it tracks.
+func RefreshOne(id string, revision int, channel string, platform RefreshPlatform) (RefreshConfig, error) {
+	if id == "" {
+		return nil, errors.NotValidf("empty charm ID")
+	}
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := platform.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	instanceKey, err := newInstanceKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return refreshOne{
+		instanceKey: instanceKey,
+		id:          id,
+		revision:    revision,
+		channel:     ch,
+		platform:    platform,
+	}, nil
+}
+
+// Build is part of the RefreshConfig interface.
+func (c refreshOne) Build() (transport.RefreshRequest, error) {
+	return transport.RefreshRequest{
+		Context: []transport.RefreshRequestContext{{
+			InstanceKey:     c.instanceKey,
+			ID:              c.id,
+			Revision:        c.revision,
+			Platform:        c.platform.transport(),
+			TrackingChannel: c.channel.String(),
+		}},
+		Actions: []transport.RefreshRequestAction{{
+			Action:      refreshAction,
+			InstanceKey: c.instanceKey,
+			ID:          &c.id,
+		}},
+	}, nil
+}
+
+// executeOne holds the config for installing or downloading a single
+// charm which is not yet installed.
+type executeOne struct {
+	action      string
+	instanceKey string
+	name        string
+	// Exactly one of revision or channel is set.
+	revision *int
+	channel  *Channel
+	platform RefreshPlatform
+}
+
+// InstallOneFromRevision creates a request config for installing the
+// specified revision of the named charm.
+func InstallOneFromRevision(name string, revision int, platform RefreshPlatform) (RefreshConfig, error) {
+	return newExecuteOneFromRevision(installAction, name, revision, platform)
+}
+
+// InstallOneFromChannel creates a request config for installing the
+// latest revision of the named charm released to the specified channel.
+func InstallOneFromChannel(name string, channel string, platform RefreshPlatform) (RefreshConfig, error) {
+	return newExecuteOneFromChannel(installAction, name, channel, platform)
+}
+
+// DownloadOneFromRevision creates a request config for downloading the
+// specified revision of the named charm without installing it.
+func DownloadOneFromRevision(name string, revision int, platform RefreshPlatform) (RefreshConfig, error) {
+	return newExecuteOneFromRevision(downloadAction, name, revision, platform)
+}
+
+// DownloadOneFromChannel creates a request config for downloading the
+// latest revision of the named charm released to the specified channel,
+// without installing it.
+func DownloadOneFromChannel(name string, channel string, platform RefreshPlatform) (RefreshConfig, error) {
+	return newExecuteOneFromChannel(downloadAction, name, channel, platform)
+}
+
+func newExecuteOneFromRevision(action, name string, revision int, platform RefreshPlatform) (RefreshConfig, error) {
+	if revision < 0 {
+		return nil, errors.NotValidf("revision %d", revision)
+	}
+	c, err := newExecuteOne(action, name, platform)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.revision = &revision
+	return c, nil
+}
+
+func newExecuteOneFromChannel(action, name, channel string, platform RefreshPlatform) (RefreshConfig, error) {
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c, err := newExecuteOne(action, name, platform)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.channel = &ch
+	return c, nil
+}
+
+func newExecuteOne(action, name string, platform RefreshPlatform) (executeOne, error) {
+	if name == "" {
+		return executeOne{}, errors.NotValidf("empty charm name")
+	}
+	if err := platform.validate(); err != nil {
+		return executeOne{}, errors.Trace(err)
+	}
+	instanceKey, err := newInstanceKey()
+	if err != nil {
+		return executeOne{}, errors.Trace(err)
+	}
+	return executeOne{
+		action:      action,
+		instanceKey: instanceKey,
+		name:        name,
+		platform:    platform,
+	}, nil
+}
+
+// Build is part of the RefreshConfig interface.
+func (c executeOne) Build() (transport.RefreshRequest, error) {
+	platform := c.platform.transport()
+	action := transport.RefreshRequestAction{
+		Action:      c.action,
+		InstanceKey: c.instanceKey,
+		Name:        &c.name,
+		Revision:    c.revision,
+		Platform:    &platform,
+	}
+	if c.channel != nil {
+		channel := c.channel.String()
+		action.Channel = &channel
+	}
+	return transport.RefreshRequest{
+		// Context is required here, even if it looks optional.
+		Context: []transport.RefreshRequestContext{},
+		Actions: []transport.RefreshRequestAction{action},
+	}, nil
+}
+
+func newInstanceKey() (string, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Annotate(err, "generating instance key")
+	}
+	return uuid.String(), nil
+}
+
+// RefreshClient defines a client for refresh requests.
+type RefreshClient struct {
+	path   path.Path
+	client RESTClient
+}
+
+// NewRefreshClient creates a RefreshClient for requesting the charms to
+// install or refresh.
+func NewRefreshClient(path path.Path, client RESTClient) *RefreshClient {
+	return &RefreshClient{
+		path:   path,
+		client: client,
+	}
+}
+
+// Refresh is used to install, refresh or download charms, selecting the
+// revision of each charm to use based on the provided config.
+func (c *RefreshClient) Refresh(ctx context.Context, config RefreshConfig) ([]transport.RefreshResponse, error) {
+	req, err := config.Build()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var resp transport.RefreshResponses
+	if err := c.client.Post(ctx, c.path, req, &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if len(resp.ErrorList) > 0 {
+		var combined []string
+		for _, err := range resp.ErrorList {
+			if err.Message != "" {
+				combined = append(combined, err.Message)
+			}
+		}
+		return nil, errors.Errorf(strings.Join(combined, "\n"))
+	}
+
+	for _, result := range resp.Results {
+		if result.Error == nil {
+			continue
+		}
+		// Charmhub reports missing charms, revisions and channels
+		// with codes such as "revision-not-found".
+		if strings.HasSuffix(result.Error.Code, "not-found") {
+			return nil, errors.NewNotFound(nil, result.Error.Message)
+		}
+		return nil, errors.Errorf(result.Error.Message)
+	}
+	return resp.Results, nil
+}
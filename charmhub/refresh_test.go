@@ -0,0 +1,215 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	path "github.com/juju/juju/charmhub/path"
+	"github.com/juju/juju/charmhub/transport"
+)
+
+type RefreshSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RefreshSuite{})
+
+var bionicAMD64 = RefreshPlatform{
+	Architecture: "amd64",
+	Series:       "bionic",
+}
+
+func (s *RefreshSuite) TestRefresh(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	baseURL := MustParseURL(c, "http://api.foo.bar")
+	refreshPath := path.MakePath(baseURL)
+
+	config, err := InstallOneFromChannel("wordpress", "edge", bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restClient := NewMockRESTClient(ctrl)
+	restClient.EXPECT().Post(gomock.Any(), refreshPath, gomock.Any(), gomock.Any()).Do(
+		func(_ context.Context, _ path.Path, req transport.RefreshRequest, resp *transport.RefreshResponses) {
+			c.Assert(req.Actions, gc.HasLen, 1)
+			resp.Results = []transport.RefreshResponse{{
+				InstanceKey: req.Actions[0].InstanceKey,
+				Name:        "wordpress",
+				Result:      "install",
+			}}
+		},
+	).Return(nil)
+
+	client := NewRefreshClient(refreshPath, restClient)
+	responses, err := client.Refresh(context.TODO(), config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(responses, gc.HasLen, 1)
+	c.Assert(responses[0].Name, gc.Equals, "wordpress")
+}
+
+func (s *RefreshSuite) TestRefreshFailure(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	config, err := InstallOneFromChannel("wordpress", "edge", bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restClient := NewMockRESTClient(ctrl)
+	restClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.Errorf("boom"))
+
+	client := NewRefreshClient(MustMakePath(c, "http://api.foo.bar"), restClient)
+	_, err = client.Refresh(context.TODO(), config)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *RefreshSuite) TestRefreshResultNotFound(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	config, err := InstallOneFromRevision("wordpress", 42, bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restClient := NewMockRESTClient(ctrl)
+	restClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ context.Context, _ path.Path, _ transport.RefreshRequest, resp *transport.RefreshResponses) {
+			resp.Results = []transport.RefreshResponse{{
+				Result: "error",
+				Error: &transport.APIError{
+					Code:    "revision-not-found",
+					Message: "Revision 42 not found.",
+				},
+			}}
+		},
+	).Return(nil)
+
+	client := NewRefreshClient(MustMakePath(c, "http://api.foo.bar"), restClient)
+	_, err = client.Refresh(context.TODO(), config)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "Revision 42 not found.")
+}
+
+func (s *RefreshSuite) TestRefreshErrorList(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	config, err := InstallOneFromRevision("wordpress", 42, bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restClient := NewMockRESTClient(ctrl)
+	restClient.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ context.Context, _ path.Path, _ transport.RefreshRequest, resp *transport.RefreshResponses) {
+			resp.ErrorList = []transport.APIError{{Message: "foo"}, {Message: "bar"}}
+		},
+	).Return(nil)
+
+	client := NewRefreshClient(MustMakePath(c, "http://api.foo.bar"), restClient)
+	_, err = client.Refresh(context.TODO(), config)
+	c.Assert(err, gc.ErrorMatches, "foo\nbar")
+}
+
+func (s *RefreshSuite) TestRefreshOneBuild(c *gc.C) {
+	config, err := RefreshOne("foo-id", 1, "2.0/edge", bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	req, err := config.Build()
+	c.Assert(err, jc.ErrorIsNil)
+	instanceKey := config.(refreshOne).instanceKey
+	c.Assert(instanceKey, gc.Not(gc.Equals), "")
+	id := "foo-id"
+	c.Assert(req, jc.DeepEquals, transport.RefreshRequest{
+		Context: []transport.RefreshRequestContext{{
+			InstanceKey: instanceKey,
+			ID:          "foo-id",
+			Revision:    1,
+			Platform: transport.Platform{
+				Architecture: "amd64",
+				OS:           "ubuntu",
+				Series:       "bionic",
+			},
+			TrackingChannel: "2.0/edge",
+		}},
+		Actions: []transport.RefreshRequestAction{{
+			Action:      "refresh",
+			InstanceKey: instanceKey,
+			ID:          &id,
+		}},
+	})
+}
+
+func (s *RefreshSuite) TestInstallOneFromRevisionBuild(c *gc.C) {
+	config, err := InstallOneFromRevision("foo", 7, bionicAMD64)
+	c.Assert(err, jc.ErrorIsNil)
+
+	req, err := config.Build()
+	c.Assert(err, jc.ErrorIsNil)
+	instanceKey := config.(executeOne).instanceKey
+	name := "foo"
+	revision := 7
+	c.Assert(req, jc.DeepEquals, transport.RefreshRequest{
+		Context: []transport.RefreshRequestContext{},
+		Actions: []transport.RefreshRequestAction{{
+			Action:      "install",
+			InstanceKey: instanceKey,
+			Name:        &name,
+			Revision:    &revision,
+			Platform: &transport.Platform{
+				Architecture: "amd64",
+				OS:           "ubuntu",
+				Series:       "bionic",
+			},
+		}},
+	})
+}
+
+func (s *RefreshSuite) TestDownloadOneFromChannelBuild(c *gc.C) {
+	config, err := DownloadOneFromChannel("foo", "candidate", RefreshPlatform{
+		Architecture: "arm64",
+		OS:           "ubuntu",
+		Series:       "focal",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	req, err := config.Build()
+	c.Assert(err, jc.ErrorIsNil)
+	instanceKey := config.(executeOne).instanceKey
+	name := "foo"
+	channel := "latest/candidate"
+	c.Assert(req, jc.DeepEquals, transport.RefreshRequest{
+		Context: []transport.RefreshRequestContext{},
+		Actions: []transport.RefreshRequestAction{{
+			Action:      "download",
+			InstanceKey: instanceKey,
+			Name:        &name,
+			Channel:     &channel,
+			Platform: &transport.Platform{
+				Architecture: "arm64",
+				OS:           "ubuntu",
+				Series:       "focal",
+			},
+		}},
+	})
+}
+
+func (s *RefreshSuite) TestConfigValidation(c *gc.C) {
+	_, err := RefreshOne("", 1, "stable", bionicAMD64)
+	c.Check(err, gc.ErrorMatches, "empty charm ID not valid")
+	_, err = InstallOneFromChannel("", "stable", bionicAMD64)
+	c.Check(err, gc.ErrorMatches, "empty charm name not valid")
+	_, err = InstallOneFromChannel("foo", "2.0/foo", bionicAMD64)
+	c.Check(err, gc.ErrorMatches, `risk in channel "2.0/foo" not valid`)
+	_, err = InstallOneFromRevision("foo", -1, bionicAMD64)
+	c.Check(err, gc.ErrorMatches, "revision -1 not valid")
+	_, err = InstallOneFromRevision("foo", 1, RefreshPlatform{Series: "bionic"})
+	c.Check(err, gc.ErrorMatches, "platform with empty architecture not valid")
+	_, err = InstallOneFromRevision("foo", 1, RefreshPlatform{Architecture: "amd64"})
+	c.Check(err, gc.ErrorMatches, "platform with empty series not valid")
+}
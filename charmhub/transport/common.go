@@ -37,6 +37,7 @@ type Revision struct {
 
 type Download struct {
 	HashSHA265 string `json:"hash-sha-265"`
+	HashSHA384 string `json:"hash-sha-384,omitempty"`
 	Size       int    `json:"size"`
 	URL        string `json:"url"`
 }
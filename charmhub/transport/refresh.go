@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package transport
+
+// The following contains the DTOs for the refresh endpoint, which is
+// used both to install a charm for the first time and to refresh an
+// already installed charm.
+
+// RefreshRequest describes the charms which are installed, in Context,
+// and what is to be done with them, or with new charms, in Actions.
+type RefreshRequest struct {
+	Context []RefreshRequestContext `json:"context"`
+	Actions []RefreshRequestAction  `json:"actions"`
+}
+
+// RefreshRequestContext describes a charm which is already installed.
+type RefreshRequestContext struct {
+	InstanceKey     string   `json:"instance-key"`
+	ID              string   `json:"id"`
+	Revision        int      `json:"revision"`
+	Platform        Platform `json:"platform"`
+	TrackingChannel string   `json:"tracking-channel"`
+}
+
+// RefreshRequestAction describes an install, refresh or download of a
+// charm. Install and download actions identify the charm by Name, while
+// refresh actions identify an installed charm by ID and InstanceKey.
+// Setting Revision pins the action to that revision, otherwise the
+// latest revision released to Channel is selected.
+type RefreshRequestAction struct {
+	Action      string    `json:"action"`
+	InstanceKey string    `json:"instance-key"`
+	ID          *string   `json:"id,omitempty"`
+	Name        *string   `json:"name,omitempty"`
+	Channel     *string   `json:"channel,omitempty"`
+	Revision    *int      `json:"revision,omitempty"`
+	Platform    *Platform `json:"platform,omitempty"`
+}
+
+type RefreshResponses struct {
+	Results   []RefreshResponse `json:"results"`
+	ErrorList []APIError        `json:"error-list"`
+}
+
+type RefreshResponse struct {
+	Entity           RefreshEntity `json:"charm"`
+	EffectiveChannel string        `json:"effective-channel"`
+	Error            *APIError     `json:"error,omitempty"`
+	ID               string        `json:"id"`
+	InstanceKey      string        `json:"instance-key"`
+	Name             string        `json:"name"`
+	ReleasedAt       string        `json:"released-at"`
+	Result           string        `json:"result"`
+}
+
+type RefreshEntity struct {
+	CreatedAt string     `json:"created-at"`
+	Download  Download   `json:"download"`
+	ID        string     `json:"id"`
+	License   string     `json:"license"`
+	Name      string     `json:"name"`
+	Platforms []Platform `json:"platforms"`
+	Revision  int        `json:"revision"`
+	Summary   string     `json:"summary"`
+	Type      string     `json:"type"`
+	Version   string     `json:"version"`
+}
@@ -99,7 +99,8 @@ type InstanceConfig struct {
 	CloudInitOutputLog string
 
 	// CloudInitUserData defines key/value pairs from the model-config
-	// specified by the user.
+	// specified by the user, merged with those specified for the
+	// machine and the applications it hosts.
 	CloudInitUserData map[string]interface{}
 
 	// MachineId identifies the new machine.
@@ -959,6 +960,12 @@ func PopulateInstanceConfig(icfg *InstanceConfig,
 // redeeming feature.
 func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot complete machine configuration")
+	// The provisioner supplies user data that already includes the
+	// model's, merged with that of the machine and its applications.
+	cloudInitUserData := icfg.CloudInitUserData
+	if cloudInitUserData == nil {
+		cloudInitUserData = cfg.CloudInitUserData()
+	}
 	if err := PopulateInstanceConfig(
 		icfg,
 		cfg.Type(),
@@ -967,7 +974,7 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 		proxyConfigurationFromEnv(cfg),
 		cfg.EnableOSRefreshUpdate(),
 		cfg.EnableOSUpgrade(),
-		cfg.CloudInitUserData(),
+		cloudInitUserData,
 		nil,
 	); err != nil {
 		return errors.Trace(err)
@@ -95,6 +95,9 @@ func (s *CloudInitSuite) TestFinishInstanceConfig(c *gc.C) {
 		"enable-os-upgrade":        false,
 	}))
 	c.Assert(err, jc.ErrorIsNil)
+	icfg = &instancecfg.InstanceConfig{
+		APIInfo: &api.Info{Tag: userTag},
+	}
 	err = instancecfg.FinishInstanceConfig(icfg, cfg)
 	c.Assert(err, jc.ErrorIsNil)
 	expectedMcfg.EnableOSRefreshUpdate = false
@@ -103,6 +106,25 @@ func (s *CloudInitSuite) TestFinishInstanceConfig(c *gc.C) {
 	c.Assert(icfg, jc.DeepEquals, expectedMcfg)
 }
 
+func (s *CloudInitSuite) TestFinishInstanceConfigKeepsCloudInitUserData(c *gc.C) {
+	cfg, err := config.New(config.NoDefaults, dummySampleConfig().Merge(testing.Attrs{
+		"authorized-keys":    "we-are-the-keys",
+		"cloudinit-userdata": validCloudInitUserData,
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// User data supplied by the provisioner is already merged with
+	// the model's, so it is not replaced.
+	userData := map[string]interface{}{"packages": []interface{}{"htop"}}
+	icfg := &instancecfg.InstanceConfig{
+		APIInfo:           &api.Info{Tag: names.NewLocalUserTag("not-touched")},
+		CloudInitUserData: userData,
+	}
+	err = instancecfg.FinishInstanceConfig(icfg, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(icfg.CloudInitUserData, jc.DeepEquals, userData)
+}
+
 func (s *CloudInitSuite) TestFinishInstanceConfigNonDefault(c *gc.C) {
 	userTag := names.NewLocalUserTag("not-touched")
 	attrs := dummySampleConfig().Merge(testing.Attrs{
@@ -0,0 +1,234 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/transport"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs/config"
+)
+
+// charmHubPrefix identifies charm references which are resolved against
+// charmhub rather than the charm store, for example "ch:bionic/wordpress".
+const charmHubPrefix = "ch:"
+
+// charmHubOriginSource is the source of the charm origin recorded for
+// applications deployed from charmhub.
+const charmHubOriginSource = "charmhub"
+
+// CharmHubClient defines the subset of the charmhub client required to
+// deploy and upgrade charms from charmhub.
+type CharmHubClient interface {
+	Refresh(ctx context.Context, config charmhub.RefreshConfig) ([]transport.RefreshResponse, error)
+	DownloadAndRead(ctx context.Context, resourceURL *url.URL, archivePath, hashSHA384 string) (*charm.CharmArchive, error)
+}
+
+// NewCharmHubClientFunc returns a charmhub client for the charmhub
+// located at the given URL.
+type NewCharmHubClientFunc func(url string) (CharmHubClient, error)
+
+func newCharmHubClient(url string) (CharmHubClient, error) {
+	return charmhub.NewClient(charmhub.CharmhubConfigFromURL(url))
+}
+
+// getCharmHubURL returns the charmhub URL configured for the model.
+func getCharmHubURL(apiRoot base.APICallCloser) (string, error) {
+	attrs, err := modelconfig.NewClient(apiRoot).ModelGet()
+	if err != nil {
+		return "", errors.Wrap(err, errors.New("cannot fetch model settings"))
+	}
+	modelCfg, err := config.New(config.NoDefaults, attrs)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	url, _ := modelCfg.CharmhubURL()
+	return url, nil
+}
+
+// isCharmHubRef reports whether the charm reference is a charmhub one.
+func isCharmHubRef(ref string) bool {
+	return strings.HasPrefix(ref, charmHubPrefix)
+}
+
+// parseCharmHubRef parses a charmhub reference of the form
+// "ch:[series/]name[-revision]". The revision of the returned URL is -1
+// when none is given.
+func parseCharmHubRef(ref string) (*charm.URL, error) {
+	if !isCharmHubRef(ref) {
+		return nil, errors.NotValidf("charmhub reference %q", ref)
+	}
+	// Charmhub references share the charm store URL syntax, without a user.
+	curl, err := charm.ParseURL("cs:" + strings.TrimPrefix(ref, charmHubPrefix))
+	if err != nil {
+		return nil, errors.NotValidf("charmhub reference %q", ref)
+	}
+	if curl.User != "" {
+		return nil, errors.NotValidf("charmhub reference %q with user", ref)
+	}
+	return curl, nil
+}
+
+// charmHubArch returns the architecture to request charms for, based on
+// the constraints they will be deployed with.
+func charmHubArch(cons constraints.Value) string {
+	if cons.HasArch() {
+		return *cons.Arch
+	}
+	return arch.AMD64
+}
+
+// charmHubCharm holds a charm downloaded from charmhub.
+type charmHubCharm struct {
+	// URL identifies the charm when added to the model. Charmhub charms
+	// are uploaded to the controller as local charms, and the Origin
+	// recorded against the application identifies them in charmhub.
+	URL *charm.URL
+
+	// Archive is the downloaded charm archive.
+	Archive *charm.CharmArchive
+
+	// Origin identifies the downloaded revision of the charm in charmhub.
+	Origin *params.CharmOrigin
+
+	// Channel is the channel charmhub resolved the charm from, which
+	// may be empty if a specific revision was requested.
+	Channel string
+}
+
+// charmHubInstallConfig returns the refresh config to install the charm
+// referenced by curl built for the platform, either the revision pinned
+// in the reference or the latest released to the channel.
+func charmHubInstallConfig(curl *charm.URL, channel string, platform charmhub.RefreshPlatform) (charmhub.RefreshConfig, error) {
+	if curl.Revision >= 0 {
+		return charmhub.InstallOneFromRevision(curl.Name, curl.Revision, platform)
+	}
+	if channel == "" {
+		channel = string(charmhub.Stable)
+	}
+	return charmhub.InstallOneFromChannel(curl.Name, channel, platform)
+}
+
+// charmHubRefreshConfig returns the refresh config to upgrade the
+// installed charm with the given origin. The latest revision released
+// to the channel is requested, unless curl pins a revision.
+func charmHubRefreshConfig(
+	curl *charm.URL,
+	origin *params.CharmOrigin,
+	channel string,
+	platform charmhub.RefreshPlatform,
+) (charmhub.RefreshConfig, error) {
+	if curl.Revision >= 0 {
+		return charmHubInstallConfig(curl, channel, platform)
+	}
+	if channel == "" {
+		channel = string(charmhub.Stable)
+	}
+	return charmhub.RefreshOne(origin.ID, origin.Revision, channel, platform)
+}
+
+// downloadCharmHubCharm resolves the charm named name using the refresh
+// config, and downloads it into dir after verifying its hash.
+func downloadCharmHubCharm(
+	ctx context.Context,
+	client CharmHubClient,
+	name string,
+	refreshConfig charmhub.RefreshConfig,
+	platform charmhub.RefreshPlatform,
+	dir string,
+) (charmHubCharm, error) {
+	results, err := client.Refresh(ctx, refreshConfig)
+	if err != nil {
+		return charmHubCharm{}, errors.Annotatef(err, "resolving charm %q", name)
+	}
+	if len(results) != 1 {
+		return charmHubCharm{}, errors.Errorf("expected 1 result resolving charm %q, got %d", name, len(results))
+	}
+	entity := results[0].Entity
+	if entity.Name != name {
+		return charmHubCharm{}, errors.Errorf("resolved charm %q, expected %q", entity.Name, name)
+	}
+
+	resourceURL, err := url.Parse(entity.Download.URL)
+	if err != nil {
+		return charmHubCharm{}, errors.Annotatef(err, "parsing download URL for charm %q", name)
+	}
+	archivePath := filepath.Join(dir, fmt.Sprintf("%s_%d.charm", entity.Name, entity.Revision))
+	archive, err := client.DownloadAndRead(ctx, resourceURL, archivePath, entity.Download.HashSHA384)
+	if err != nil {
+		return charmHubCharm{}, errors.Annotatef(err, "downloading charm %q", name)
+	}
+	if archive.Meta().Name != entity.Name {
+		return charmHubCharm{}, errors.Errorf("downloaded charm %q, expected %q", archive.Meta().Name, entity.Name)
+	}
+
+	return charmHubCharm{
+		URL: &charm.URL{
+			Schema:   "local",
+			Name:     entity.Name,
+			Revision: entity.Revision,
+			Series:   platform.Series,
+		},
+		Archive: archive,
+		Origin: &params.CharmOrigin{
+			Source:   charmHubOriginSource,
+			ID:       entity.ID,
+			Revision: entity.Revision,
+		},
+		Channel: results[0].EffectiveChannel,
+	}, nil
+}
+
+// interruptibleContext returns a context which is cancelled when the
+// command is interrupted, and a function which must be called to
+// release it.
+func interruptibleContext(ctx *cmd.Context) (context.Context, func()) {
+	stdCtx, cancel := context.WithCancel(context.Background())
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	go func() {
+		select {
+		case <-interrupted:
+			cancel()
+		case <-stdCtx.Done():
+		}
+	}()
+	return stdCtx, func() {
+		ctx.StopInterruptNotify(interrupted)
+		cancel()
+	}
+}
+
+// newCharmHubDownloadDir creates the directory charmhub charms are
+// downloaded into. Separated into a variable for easy overrides.
+var newCharmHubDownloadDir = func() (string, error) {
+	return ioutil.TempDir("", "charmhub")
+}
+
+// withCharmHubDownloadDir calls f with a temporary directory to download
+// charms into, removing it once f returns.
+func withCharmHubDownloadDir(f func(dir string) error) error {
+	dir, err := newCharmHubDownloadDir()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	return f(dir)
+}
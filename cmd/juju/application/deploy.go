@@ -36,6 +36,7 @@ import (
 	"github.com/juju/juju/api/spaces"
 	app "github.com/juju/juju/apiserver/facades/client/application"
 	apiparams "github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/charmstore"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -62,7 +63,7 @@ type ApplicationAPI interface {
 	AddMachines(machineParams []apiparams.AddMachineParams) ([]apiparams.AddMachinesResult, error)
 	AddRelation(endpoints, viaCIDRs []string) (*apiparams.AddRelationResults, error)
 	AddUnits(application.AddUnitsParams) ([]string, error)
-	Expose(application string) error
+	Expose(application string, exposedEndpoints map[string]apiparams.ExposedEndpoint) error
 	GetAnnotations(tags []string) ([]apiparams.AnnotationsGetResult, error)
 	GetConfig(branchName string, appNames ...string) ([]map[string]interface{}, error)
 	GetConstraints(appNames ...string) ([]constraints.Value, error)
@@ -91,6 +92,9 @@ type MeteredDeployAPI interface {
 // command needs for charms.
 type CharmDeployAPI interface {
 	CharmInfo(string) (*apicharms.CharmInfo, error)
+	ResolveLocalCharm(name, series, channel string) (*charm.URL, string, error)
+	AddCharmFromRepository(curl *charm.URL) error
+	AddRepositoryResources(curl *charm.URL, applicationName string, exclude []string) (map[string]string, error)
 }
 
 // OfferAPI represents the methods of the API the deploy command needs
@@ -260,9 +264,10 @@ func newDeployCommand() *DeployCommand {
 		&ValidateLXDProfileCharm{},
 	}
 	deployCmd := &DeployCommand{
-		Steps:           steps,
-		DeployResources: resourceadapters.DeployResources,
-		clock:           jujuclock.WallClock,
+		Steps:             steps,
+		DeployResources:   resourceadapters.DeployResources,
+		NewCharmHubClient: newCharmHubClient,
+		clock:             jujuclock.WallClock,
 	}
 	deployCmd.NewCharmRepo = func() (*charmStoreAdaptor, error) {
 		controllerAPIRoot, err := deployCmd.NewControllerAPIRoot()
@@ -391,6 +396,9 @@ type DeployCommand struct {
 	// NewCharmRepo stores a function which returns a charm store client.
 	NewCharmRepo func() (*charmStoreAdaptor, error)
 
+	// NewCharmHubClient stores a function which returns a charmhub client.
+	NewCharmHubClient NewCharmHubClientFunc
+
 	// NewConsumeDetailsAPI stores a function which will return a new API
 	// for consume details API using the url as the source.
 	NewConsumeDetailsAPI func(url *charm.OfferURL) (ConsumeDetails, error)
@@ -439,6 +447,32 @@ remote charm:
   juju deploy ./pig
   juju deploy cs:pig
 
+A charm published to charmhub is deployed by prefixing its name with 'ch:'. The
+latest revision released to the '--channel' option (by default 'stable') and
+built for the charm's series and architecture is used, unless a revision is
+given:
+
+  juju deploy ch:postgresql
+  juju deploy ch:bionic/postgresql --channel candidate
+  juju deploy ch:postgresql-5 --series bionic
+
+Charmhub charms are downloaded by the client, verified against their published
+hash, and added to the model in the same way as local charms. The series must
+be known when the charm is resolved, so it is taken from the '--series' option,
+the charm reference or the 'default-series' model key.
+
+Charms uploaded to the controller with "juju upload-charm" are deployed by
+their local charm URL. Without a revision, the latest revision released to the
+'--channel' option (by default 'stable') of the controller's charm repository
+is used, which allows charms to be deployed and upgraded in any of the
+controller's models without access to the charm store or charmhub. Revisions
+that aren't released to a channel can only be deployed by controller
+superusers. Resources stored with the charm in the repository are used unless
+they are given with '--resource':
+
+  juju deploy local:postgresql --channel edge
+  juju deploy local:bionic/postgresql-3
+
 An error is emitted if the determined series is not supported by the charm. Use
 the '--force' option to override this check:
 
@@ -613,6 +647,11 @@ Deploy to a specific availability zone (provider-dependent):
 
     juju deploy mysql --to zone=us-east-1a
 
+Deploy to a new LXD container on a new machine in a specific availability
+zone, such as an LXD cluster member:
+
+    juju deploy mysql --to lxd:zone=node02
+
 Deploy to a specific MAAS node:
 
     juju deploy mysql --to host.maas
@@ -711,7 +750,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.UnitCommandBase.SetFlags(f)
 	c.ModelCommandBase.SetFlags(f)
 	f.IntVar(&c.NumUnits, "n", 1, "Number of application units to deploy for principal charms")
-	f.StringVar((*string)(&c.Channel), "channel", "", "Channel to use when getting the charm or bundle from the charm store")
+	f.StringVar((*string)(&c.Channel), "channel", "", "Channel to use when getting the charm or bundle from the charm store, charmhub or the controller's charm repository")
 	f.Var(&c.ConfigOptions, "config", "Either a path to yaml-formatted application config file or a key=value pair ")
 
 	f.BoolVar(&c.Trust, "trust", false, "Allows charm to run hooks that require access credentials")
@@ -958,6 +997,8 @@ Please repeat the deploy command with the --trust argument if you consent to tru
 func (c *DeployCommand) deployCharm(
 	id charmstore.CharmID,
 	csMac *macaroon.Macaroon,
+	origin *apiparams.CharmOrigin,
+	fromRepository bool,
 	series string,
 	ctx *cmd.Context,
 	apiRoot DeployAPI,
@@ -1099,17 +1140,46 @@ func (c *DeployCommand) deployCharm(
 			strings.Join(charmInfo.Meta.Terms, " "))
 	}
 
+	resources := charmInfo.Meta.Resources
+	var repositoryIDs map[string]string
+	if fromRepository {
+		// Resources stored with the charm in the controller's charm
+		// repository are used unless others are given.
+		exclude := make([]string, 0, len(c.Resources))
+		for name := range c.Resources {
+			exclude = append(exclude, name)
+		}
+		sort.Strings(exclude)
+		repositoryIDs, err = apiRoot.AddRepositoryResources(id.URL, applicationName, exclude)
+		if err != nil {
+			return errors.Annotate(err, "adding resources from the charm repository")
+		}
+		resources = make(map[string]resource.Meta)
+		for name, meta := range charmInfo.Meta.Resources {
+			if _, ok := repositoryIDs[name]; !ok {
+				resources[name] = meta
+			}
+		}
+	}
 	ids, err := c.DeployResources(
 		applicationName,
 		id,
 		csMac,
 		c.Resources,
-		charmInfo.Meta.Resources,
+		resources,
 		apiRoot,
 	)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if len(repositoryIDs) > 0 {
+		if ids == nil {
+			ids = make(map[string]string)
+		}
+		for name, pendingID := range repositoryIDs {
+			ids[name] = pendingID
+		}
+	}
 
 	if len(appConfig) == 0 {
 		appConfig = nil
@@ -1129,6 +1199,7 @@ func (c *DeployCommand) deployCharm(
 		AttachStorage:    c.AttachStorage,
 		Resources:        ids,
 		EndpointBindings: c.Bindings,
+		CharmOrigin:      origin,
 	}
 	return errors.Trace(apiRoot.Deploy(args))
 }
@@ -1168,6 +1239,7 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	deploy, err := findDeployerFIFO(
 		func() (deployFn, error) { return c.maybeReadLocalBundle(ctx) },
 		func() (deployFn, error) { return c.maybeReadLocalCharm(apiRoot) },
+		c.maybeCharmHubCharm,
 		c.maybePredeployedLocalCharm,
 		c.maybeReadCharmstoreBundleFn(cstoreAPI),
 		c.charmStoreCharm, // This always returns a deployer
@@ -1298,34 +1370,91 @@ func (c *DeployCommand) maybePredeployedLocalCharm() (deployFn, error) {
 			return errors.Trace(err)
 		}
 
+		curl := userCharmURL
+		channel := c.Channel
+		var fromRepository bool
+		if curl.Revision < 0 {
+			// Without a revision, the charm is resolved from the
+			// revisions released to the controller's charm repository.
+			if curl, channel, err = c.resolveLocalCharm(apiRoot, userCharmURL, modelCfg); err != nil {
+				return errors.Trace(err)
+			}
+			if err := apiRoot.AddCharmFromRepository(curl); err != nil {
+				return errors.Annotatef(err, "adding charm %q from the charm repository", curl)
+			}
+			fromRepository = true
+			ctx.Infof("Located charm %q in the charm repository, channel %s.", curl.String(), channel)
+		} else {
+			// The revision may have been uploaded to the model, or
+			// published to the charm repository from another model.
+			err := apiRoot.AddCharmFromRepository(curl)
+			switch {
+			case err == nil:
+				fromRepository = true
+			case errors.IsNotFound(err), errors.IsNotSupported(err), errors.IsAlreadyExists(err):
+			default:
+				return errors.Annotatef(err, "adding charm %q from the charm repository", curl)
+			}
+			ctx.Infof("Located charm %q.", curl.String())
+		}
+
 		// Avoid deploying charm if it's not valid for the model.
-		if err := c.validateCharmSeriesWithName(userCharmURL.Series, userCharmURL.Name, modelCfg.ImageStream()); err != nil {
+		if err := c.validateCharmSeriesWithName(curl.Series, curl.Name, modelCfg.ImageStream()); err != nil {
 			return errors.Trace(err)
 		}
 
 		if err := c.validateCharmFlags(); err != nil {
 			return errors.Trace(err)
 		}
-		charmInfo, err := apiRoot.CharmInfo(userCharmURL.String())
+		charmInfo, err := apiRoot.CharmInfo(curl.String())
 		if err != nil {
 			return err
 		}
 		if err := c.validateResourcesNeededForLocalDeploy(charmInfo.Meta); err != nil {
 			return errors.Trace(err)
 		}
-		formattedCharmURL := userCharmURL.String()
-		ctx.Infof("Located charm %q.", formattedCharmURL)
-		ctx.Infof("Deploying charm %q.", formattedCharmURL)
+		ctx.Infof("Deploying charm %q.", curl.String())
 		return errors.Trace(c.deployCharm(
-			charmstore.CharmID{URL: userCharmURL},
+			charmstore.CharmID{URL: curl, Channel: channel},
 			(*macaroon.Macaroon)(nil),
-			userCharmURL.Series,
+			nil,
+			fromRepository,
+			curl.Series,
 			ctx,
 			apiRoot,
 		))
 	}, nil
 }
 
+// resolveLocalCharm returns the revision of the local charm released to
+// the requested channel of the controller's charm repository, along with
+// the channel it was found in.
+func (c *DeployCommand) resolveLocalCharm(apiRoot DeployAPI, userCharmURL *charm.URL, modelCfg *config.Config) (*charm.URL, params.Channel, error) {
+	series := userCharmURL.Series
+	if series == "" {
+		series = c.Series
+	}
+	if series == "" {
+		series, _ = modelCfg.DefaultSeries()
+	}
+	if series == "" {
+		return nil, "", errors.Errorf("series not specified for charm %q; use --series", userCharmURL.Name)
+	}
+	channel := string(c.Channel)
+	if channel == "" {
+		channel = string(params.StableChannel)
+	}
+	curl, resolvedChannel, err := apiRoot.ResolveLocalCharm(userCharmURL.Name, series, channel)
+	if errors.IsNotSupported(err) {
+		return nil, "", errors.Errorf("deploying local charm %q requires a revision", userCharmURL.Name)
+	} else if errors.IsNotFound(err) {
+		return nil, "", errors.Errorf("no revision of local charm %q for series %q released to channel %q", userCharmURL.Name, series, channel)
+	} else if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return curl, params.Channel(resolvedChannel), nil
+}
+
 func (c *DeployCommand) maybeReadLocalBundle(ctx *cmd.Context) (deployFn, error) {
 	bundleFile := c.CharmOrBundle
 	_, statErr := os.Stat(bundleFile)
@@ -1469,6 +1598,8 @@ func (c *DeployCommand) maybeReadLocalCharm(apiRoot DeployAPI) (deployFn, error)
 		return errors.Trace(c.deployCharm(
 			id,
 			(*macaroon.Macaroon)(nil), // local charms don't need one.
+			nil,
+			false,
 			curl.Series,
 			ctx,
 			apiRoot,
@@ -1573,6 +1704,114 @@ func (c *DeployCommand) getMeteringAPIURL(controllerAPIRoot api.Connection) (str
 	return controllerCfg.MeteringURL(), nil
 }
 
+func (c *DeployCommand) maybeCharmHubCharm() (deployFn, error) {
+	if !isCharmHubRef(c.CharmOrBundle) {
+		return nil, nil
+	}
+	userRequestedURL, err := parseCharmHubRef(c.CharmOrBundle)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if userRequestedURL.Series == "bundle" {
+		return nil, errors.NotSupportedf("deploying bundles from charmhub")
+	}
+
+	return func(ctx *cmd.Context, apiRoot DeployAPI, deployResources resourceadapters.DeployResourcesFunc, cstore *charmStoreAdaptor) error {
+		if err := c.validateCharmFlags(); err != nil {
+			return errors.Trace(err)
+		}
+
+		modelCfg, err := getModelConfig(apiRoot)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		// The series selects the revision of the charm to download, so
+		// it must be known up front.
+		series := c.Series
+		if series == "" {
+			series = userRequestedURL.Series
+		}
+		if series == "" {
+			series, _ = modelCfg.DefaultSeries()
+		}
+		if series == "" {
+			return errors.Errorf("series not specified for charm %q; use --series", userRequestedURL.Name)
+		}
+		imageStream := modelCfg.ImageStream()
+		if err := c.validateCharmSeriesWithName(series, userRequestedURL.Name, imageStream); err != nil {
+			return errors.Trace(err)
+		}
+
+		charmHubURL, _ := modelCfg.CharmhubURL()
+		client, err := c.NewCharmHubClient(charmHubURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		platform := charmhub.RefreshPlatform{
+			Architecture: charmHubArch(c.Constraints),
+			Series:       series,
+		}
+
+		if apiRoot.BestFacadeVersion("Application") < 15 {
+			return errors.New("this juju controller does not support deploying charms from charmhub")
+		}
+		refreshConfig, err := charmHubInstallConfig(userRequestedURL, string(c.Channel), platform)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		stdCtx, cancel := interruptibleContext(ctx)
+		defer cancel()
+		return withCharmHubDownloadDir(func(dir string) error {
+			ch, err := downloadCharmHubCharm(stdCtx, client, userRequestedURL.Name, refreshConfig, platform, dir)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if ch.Channel != "" {
+				ctx.Infof("Located charm %q in charmhub, revision %d, channel %s.", ch.URL.Name, ch.URL.Revision, ch.Channel)
+			} else {
+				ctx.Infof("Located charm %q in charmhub, revision %d.", ch.URL.Name, ch.URL.Revision)
+			}
+
+			meta := ch.Archive.Meta()
+			if !c.Force {
+				if _, err := charm.SeriesForCharm(series, meta.Series); charm.IsUnsupportedSeriesError(err) {
+					return errors.Errorf("%v. Use --force to deploy the charm anyway.", err)
+				}
+			}
+			if err := c.validateResourcesNeededForLocalDeploy(meta); err != nil {
+				return errors.Trace(err)
+			}
+
+			curl, err := apiRoot.AddLocalCharm(ch.URL, ch.Archive, c.Force)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			ctx.Infof("Deploying charm %q.", curl.String())
+			// The channel and origin are recorded against the
+			// application so that it can be refreshed from charmhub.
+			id := charmstore.CharmID{
+				URL:     curl,
+				Channel: c.Channel,
+			}
+			if ch.Channel != "" {
+				id.Channel = params.Channel(ch.Channel)
+			}
+			return errors.Trace(c.deployCharm(
+				id,
+				(*macaroon.Macaroon)(nil),
+				ch.Origin,
+				false,
+				series,
+				ctx,
+				apiRoot,
+			))
+		})
+	}, nil
+}
+
 func (c *DeployCommand) charmStoreCharm() (deployFn, error) {
 	userRequestedURL, err := charm.ParseURL(c.CharmOrBundle)
 	if err != nil {
@@ -1678,6 +1917,8 @@ func (c *DeployCommand) charmStoreCharm() (deployFn, error) {
 		return errors.Trace(c.deployCharm(
 			id,
 			csMac,
+			nil,
+			false,
 			series,
 			ctx,
 			apiRoot,
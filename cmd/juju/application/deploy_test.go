@@ -42,6 +42,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	k8sprovider "github.com/juju/juju/caas/kubernetes/provider"
+	"github.com/juju/juju/charmhub/charmhubtest"
+	"github.com/juju/juju/charmhub/transport"
 	jjcharmstore "github.com/juju/juju/charmstore"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -2104,6 +2106,187 @@ func (s *BundleDeployCharmStoreSuite) TestDeployBundle_OutputsCorrectMessage(c *
 	)
 }
 
+func (s *DeployUnitTestSuite) charmHubServer(c *gc.C) (*charmhubtest.Server, []byte) {
+	archivePath := testcharms.RepoWithSeries("bionic").CharmArchivePath(c.MkDir(), "dummy")
+	archive, err := ioutil.ReadFile(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	srv := charmhubtest.NewServer()
+	s.AddCleanup(func(*gc.C) { srv.Close() })
+	srv.AddRevision("dummy", 3, "1.0", []transport.Platform{
+		{Architecture: "amd64", OS: "ubuntu", Series: "bionic"},
+	}, archive)
+	srv.AddRevision("dummy", 4, "1.1", []transport.Platform{
+		{Architecture: "amd64", OS: "ubuntu", Series: "bionic"},
+	}, archive)
+	c.Assert(srv.Release("dummy", 3, "stable"), jc.ErrorIsNil)
+	c.Assert(srv.Release("dummy", 4, "candidate"), jc.ErrorIsNil)
+	return srv, archive
+}
+
+// expectCharmHubDownload arranges for charmhub charms to be downloaded into
+// a known directory, returning the archive expected to be downloaded there.
+func (s *DeployUnitTestSuite) expectCharmHubDownload(c *gc.C, archive []byte, revision int) *charm.CharmArchive {
+	dir := c.MkDir()
+	s.PatchValue(&newCharmHubDownloadDir, func() (string, error) {
+		return dir, nil
+	})
+	archivePath := filepath.Join(dir, fmt.Sprintf("dummy_%d.charm", revision))
+	err := ioutil.WriteFile(archivePath, archive, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmArchive(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharm(c *gc.C) {
+	srv, archive := s.charmHubServer(c)
+	ch := s.expectCharmHubDownload(c, archive, 3)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	withCharmHubCharmDeployable(fakeAPI, charm.MustParseURL("local:bionic/dummy-3"), ch, "latest/stable")
+
+	ctx, err := s.runDeploy(c, fakeAPI, "ch:dummy", "--series", "bionic")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Located charm "dummy" in charmhub, revision 3, channel latest/stable.`+"\n"+
+		`Deploying charm "local:bionic/dummy-3".`+"\n",
+	)
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmFromChannel(c *gc.C) {
+	srv, archive := s.charmHubServer(c)
+	ch := s.expectCharmHubDownload(c, archive, 4)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	cfgAttrs["default-series"] = "bionic"
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	withCharmHubCharmDeployable(fakeAPI, charm.MustParseURL("local:bionic/dummy-4"), ch, "latest/candidate")
+
+	_, err := s.runDeploy(c, fakeAPI, "ch:dummy", "--channel", "candidate")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmRevision(c *gc.C) {
+	srv, archive := s.charmHubServer(c)
+	ch := s.expectCharmHubDownload(c, archive, 4)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	withCharmHubCharmDeployable(fakeAPI, charm.MustParseURL("local:bionic/dummy-4"), ch, "")
+
+	ctx, err := s.runDeploy(c, fakeAPI, "ch:bionic/dummy-4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Located charm "dummy" in charmhub, revision 4.`+"\n"+
+		`Deploying charm "local:bionic/dummy-4".`+"\n",
+	)
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmFacadeTooOld(c *gc.C) {
+	srv, _ := s.charmHubServer(c)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+
+	_, err := s.runDeploy(c, fakeAPI, "ch:dummy", "--series", "bionic")
+	c.Assert(err, gc.ErrorMatches, "this juju controller does not support deploying charms from charmhub")
+	for _, call := range fakeAPI.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "AddLocalCharm")
+	}
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmNoSeries(c *gc.C) {
+	srv, _ := s.charmHubServer(c)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+
+	_, err := s.runDeploy(c, fakeAPI, "ch:dummy")
+	c.Assert(err, gc.ErrorMatches, `series not specified for charm "dummy"; use --series`)
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmNotReleased(c *gc.C) {
+	srv, _ := s.charmHubServer(c)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	fakeAPI.Call("BestFacadeVersion", "Application").Returns(15)
+
+	_, err := s.runDeploy(c, fakeAPI, "ch:dummy", "--series", "bionic", "--channel", "edge/hotfix")
+	c.Assert(err, gc.ErrorMatches, `resolving charm "dummy": No revision of "dummy" released to "latest/edge/hotfix" for bionic/amd64.`)
+}
+
+func (s *DeployUnitTestSuite) TestDeployCharmHubCharmHashMismatch(c *gc.C) {
+	srv, _ := s.charmHubServer(c)
+	srv.CorruptArchive("dummy", 3, []byte("not the archive"))
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charmhub-url"] = srv.URL
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	fakeAPI.Call("BestFacadeVersion", "Application").Returns(15)
+
+	_, err := s.runDeploy(c, fakeAPI, "ch:dummy", "--series", "bionic")
+	c.Assert(err, gc.ErrorMatches, `downloading charm "dummy": downloaded archive with SHA-384 .* not valid`)
+	for _, call := range fakeAPI.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "AddLocalCharm")
+	}
+}
+
+func (s *DeployUnitTestSuite) TestDeployLocalRepositoryCharm(c *gc.C) {
+	charmDir := testcharms.RepoWithSeries("bionic").CharmDir("dummy")
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["default-series"] = "bionic"
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	dummyURL := charm.MustParseURL("local:bionic/dummy-3")
+	fakeAPI.Call("ResolveLocalCharm", "dummy", "bionic", "candidate").Returns(dummyURL, "latest/stable", error(nil))
+	fakeAPI.Call("AddCharmFromRepository", dummyURL).Returns(error(nil))
+	fakeAPI.Call("AddRepositoryResources", dummyURL, "dummy", []string{}).Returns(
+		map[string]string{"data": "pending-data"}, error(nil),
+	)
+	withCharmDeployable(fakeAPI, dummyURL, "bionic", charmDir.Meta(), charmDir.Metrics(), false, false, 1, nil, nil)
+	fakeAPI.Call("Deploy", application.DeployArgs{
+		CharmID:         jjcharmstore.CharmID{URL: dummyURL, Channel: "latest/stable"},
+		ApplicationName: "dummy",
+		Series:          "bionic",
+		NumUnits:        1,
+		Resources:       map[string]string{"data": "pending-data"},
+	}).Returns(error(nil))
+
+	ctx, err := s.runDeploy(c, fakeAPI, "local:dummy", "--channel", "candidate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Located charm "local:bionic/dummy-3" in the charm repository, channel latest/stable.`+"\n"+
+		`Deploying charm "local:bionic/dummy-3".`+"\n",
+	)
+}
+
+func (s *DeployUnitTestSuite) TestDeployLocalRepositoryCharmNotAdded(c *gc.C) {
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["default-series"] = "bionic"
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+	dummyURL := charm.MustParseURL("local:bionic/dummy-3")
+	fakeAPI.Call("ResolveLocalCharm", "dummy", "bionic", "stable").Returns(dummyURL, "latest/stable", error(nil))
+	fakeAPI.Call("AddCharmFromRepository", dummyURL).Returns(errors.AlreadyExistsf("different charm %q in the model", dummyURL))
+
+	_, err := s.runDeploy(c, fakeAPI, "local:dummy")
+	c.Assert(err, gc.ErrorMatches, `adding charm "local:bionic/dummy-3" from the charm repository: different charm "local:bionic/dummy-3" in the model already exists`)
+}
+
+func (s *DeployUnitTestSuite) TestDeployLocalRepositoryCharmNotReleased(c *gc.C) {
+	fakeAPI := vanillaFakeModelAPI(s.cfgAttrs())
+
+	_, err := s.runDeploy(c, fakeAPI, "local:bionic/dummy")
+	c.Assert(err, gc.ErrorMatches, `no revision of local charm "dummy" for series "bionic" released to channel "stable"`)
+}
+
 func (s *DeployUnitTestSuite) TestDeployAttachStorage(c *gc.C) {
 	charmsPath := c.MkDir()
 	charmDir := testcharms.RepoWithSeries("bionic").ClonedDir(charmsPath, "dummy")
@@ -2278,6 +2461,30 @@ func (f *fakeDeployAPI) CharmInfo(url string) (*charms.CharmInfo, error) {
 	return results[0].(*charms.CharmInfo), jujutesting.TypeAssertError(results[1])
 }
 
+func (f *fakeDeployAPI) ResolveLocalCharm(name, series, channel string) (*charm.URL, string, error) {
+	results := f.MethodCall(f, "ResolveLocalCharm", name, series, channel)
+	if results == nil {
+		return nil, "", errors.NotFoundf("local charm %q", name)
+	}
+	return results[0].(*charm.URL), results[1].(string), jujutesting.TypeAssertError(results[2])
+}
+
+func (f *fakeDeployAPI) AddCharmFromRepository(curl *charm.URL) error {
+	results := f.MethodCall(f, "AddCharmFromRepository", curl)
+	if results == nil {
+		return errors.NotFoundf("charm %q in the charm repository", curl)
+	}
+	return jujutesting.TypeAssertError(results[0])
+}
+
+func (f *fakeDeployAPI) AddRepositoryResources(curl *charm.URL, applicationName string, exclude []string) (map[string]string, error) {
+	results := f.MethodCall(f, "AddRepositoryResources", curl, applicationName, exclude)
+	if results == nil {
+		return nil, nil
+	}
+	return results[0].(map[string]string), jujutesting.TypeAssertError(results[1])
+}
+
 func (f *fakeDeployAPI) Deploy(args application.DeployArgs) error {
 	results := f.MethodCall(f, "Deploy", args)
 	if len(results) != 1 {
@@ -2331,7 +2538,7 @@ func (f *fakeDeployAPI) AddUnits(args application.AddUnitsParams) ([]string, err
 	return results[0].([]string), jujutesting.TypeAssertError(results[1])
 }
 
-func (f *fakeDeployAPI) Expose(application string) error {
+func (f *fakeDeployAPI) Expose(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
 	results := f.MethodCall(f, "Expose", application)
 	return jujutesting.TypeAssertError(results[0])
 }
@@ -2420,6 +2627,29 @@ func withLocalCharmDeployable(
 	fakeAPI.Call("AddLocalCharm", url, c, force).Returns(url, error(nil))
 }
 
+// withCharmHubCharmDeployable arranges for the charmhub charm ch to be
+// deployed as the local charm url, recording its charmhub origin and the
+// channel it was resolved from.
+func withCharmHubCharmDeployable(fakeAPI *fakeDeployAPI, url *charm.URL, ch charm.Charm, channel string) {
+	withLocalCharmDeployable(fakeAPI, url, ch, false)
+	withCharmDeployable(fakeAPI, url, url.Series, ch.Meta(), ch.Metrics(), false, false, 1, nil, nil)
+	fakeAPI.Call("BestFacadeVersion", "Application").Returns(15)
+	fakeAPI.Call("Deploy", application.DeployArgs{
+		CharmID: jjcharmstore.CharmID{
+			URL:     url,
+			Channel: csclientparams.Channel(channel),
+		},
+		ApplicationName: url.Name,
+		Series:          url.Series,
+		NumUnits:        1,
+		CharmOrigin: &params.CharmOrigin{
+			Source:   "charmhub",
+			ID:       "charm-id-" + url.Name,
+			Revision: url.Revision,
+		},
+	}).Returns(error(nil))
+}
+
 func withCharmDeployable(
 	fakeAPI *fakeDeployAPI,
 	url *charm.URL,
@@ -47,7 +47,8 @@ func NewDeployCommandForTest(fakeApi *fakeDeployAPI) modelcmd.ModelCommand {
 		NewCharmRepo: func() (*charmStoreAdaptor, error) {
 			return fakeApi.charmStoreAdaptor, nil
 		},
-		clock: jujuclock.WallClock,
+		NewCharmHubClient: newCharmHubClient,
+		clock:             jujuclock.WallClock,
 	}
 	if fakeApi == nil {
 		deployCmd.NewAPIRoot = func() (DeployAPI, error) {
@@ -109,6 +110,7 @@ func NewUpgradeCharmCommandForTest(
 	newResourceLister func(base.APICallCloser) (ResourceLister, error),
 	charmStoreURLGetter func(base.APICallCloser) (string, error),
 	newSpacesClient func(base.APICallCloser) SpacesAPI,
+	charmHubURLGetter func(base.APICallCloser) (string, error),
 ) cmd.Command {
 	cmd := &upgradeCharmCommand{
 		DeployResources:       deployResources,
@@ -120,6 +122,8 @@ func NewUpgradeCharmCommandForTest(
 		CharmStoreURLGetter:   charmStoreURLGetter,
 		NewSpacesClient:       newSpacesClient,
 		NewCharmStore:         newCharmStore,
+		CharmHubURLGetter:     charmHubURLGetter,
+		NewCharmHubClient:     newCharmHubClient,
 	}
 	cmd.SetClientStore(store)
 	cmd.SetAPIOpen(apiOpen)
@@ -236,6 +240,21 @@ func NewSetSeriesCommandForTest(
 	return modelcmd.Wrap(cmd)
 }
 
+// NewUploadCharmCommandForTest returns an upload-charm command for testing.
+func NewUploadCharmCommandForTest(api UploadCharmAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &uploadCharmCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRemoveUploadedCharmCommandForTest returns a remove-uploaded-charm
+// command for testing.
+func NewRemoveUploadedCharmCommandForTest(api RemoveUploadedCharmAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &removeUploadedCharmCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewSuspendRelationCommandForTest returns a SuspendRelationCommand with the api provided as specified.
 func NewSuspendRelationCommandForTest(api SetRelationSuspendedAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
 	cmd := &suspendRelationCommand{newAPIFunc: func() (SetRelationSuspendedAPI, error) {
@@ -309,3 +328,21 @@ func (s *RepoSuiteBaseSuite) SetUpTest(c *gc.C) {
 		return defaultSupportedJujuSeries, nil
 	})
 }
+
+// NewPlacementPolicyCommandForTest returns a placement-policy command
+// with the api provided as specified.
+func NewPlacementPolicyCommandForTest(api applicationPlacementPolicyAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &placementPolicyCommand{}
+	cmd.api = api
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetPlacementPolicyCommandForTest returns a set-placement-policy
+// command with the api provided as specified.
+func NewSetPlacementPolicyCommandForTest(api applicationPlacementPolicyAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &setPlacementPolicyCommand{}
+	cmd.api = api
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
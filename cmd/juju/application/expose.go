@@ -4,10 +4,14 @@
 package application
 
 import (
+	"net"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -20,9 +24,27 @@ var usageExposeDetails = `
 Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to allow public access to the application.
 
+By default, the ports opened by the application's units are accessible
+from everywhere. Access can instead be limited to the subnets of one or
+more spaces with --to-spaces, and to one or more CIDRs with --to-cidrs.
+The --endpoints option applies these limits to the named endpoints of
+the application only; without it, they apply to all of its endpoints.
+Running expose again merges the new settings for the specified
+endpoints with the existing ones, while unexpose clears them all.
+
+As opened ports are not tracked per endpoint, machine-based clouds
+allow the sources of all of the application's exposed endpoints to
+access each of its opened ports. Space and CIDR limits are not
+supported for k8s applications.
+
 Examples:
     juju expose wordpress
 
+Allow access to the ports of the "db" endpoint of mysql only from the
+10.0.0.0/8 CIDR and the subnets of the "internal" space:
+
+    juju expose mysql --endpoints db --to-cidrs 10.0.0.0/8 --to-spaces internal
+
 See also: 
     unexpose`[1:]
 
@@ -35,6 +57,12 @@ func NewExposeCommand() modelcmd.ModelCommand {
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+
+	// Endpoints, ExposeToSpaces and ExposeToCIDRs hold the
+	// optional per-endpoint expose settings.
+	Endpoints      []string
+	ExposeToSpaces []string
+	ExposeToCIDRs  []string
 }
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -46,17 +74,50 @@ func (c *exposeCommand) Info() *cmd.Info {
 	})
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.Endpoints), "endpoints", "Comma-delimited list of the endpoints to apply the expose settings to")
+	f.Var(cmd.NewAppendStringsValue(&c.ExposeToSpaces), "to-spaces", "Comma-delimited list of spaces whose subnets may access the exposed ports")
+	f.Var(cmd.NewAppendStringsValue(&c.ExposeToCIDRs), "to-cidrs", "Comma-delimited list of CIDRs which may access the exposed ports")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
 	}
 	c.ApplicationName = args[0]
+	for _, cidr := range c.ExposeToCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
+// exposedEndpoints returns the per-endpoint expose settings specified
+// on the command line, or nil if there are none.
+func (c *exposeCommand) exposedEndpoints() map[string]params.ExposedEndpoint {
+	if len(c.Endpoints) == 0 && len(c.ExposeToSpaces) == 0 && len(c.ExposeToCIDRs) == 0 {
+		return nil
+	}
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 {
+		// An empty endpoint name applies to all endpoints.
+		endpoints = []string{""}
+	}
+	exposedEndpoints := make(map[string]params.ExposedEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		exposedEndpoints[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaces: c.ExposeToSpaces,
+			ExposeToCIDRs:  c.ExposeToCIDRs,
+		}
+	}
+	return exposedEndpoints
+}
+
 type applicationExposeAPI interface {
 	Close() error
-	Expose(applicationName string) error
+	Expose(applicationName string, exposedEndpoints map[string]params.ExposedEndpoint) error
 	Unexpose(applicationName string) error
 }
 
@@ -76,5 +137,6 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	err = client.Expose(c.ApplicationName, c.exposedEndpoints())
+	return block.ProcessBlockedError(err, block.BlockChange)
 }
@@ -11,6 +11,7 @@ import (
 
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -55,6 +56,32 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	})
 }
 
+func (s *ExposeSuite) TestExposeToCIDRs(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
+
+	err := runExpose(c, "some-application-name", "--to-cidrs", "10.0.0.0/8,192.168.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-application-name")
+
+	app, err := s.State.Application("some-application-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/24"}},
+	})
+}
+
+func (s *ExposeSuite) TestExposeToUnknownSpace(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
+
+	err := runExpose(c, "some-application-name", "--to-spaces", "outer")
+	c.Assert(err, gc.ErrorMatches, `space "outer" not found`)
+}
+
+func (s *ExposeSuite) TestExposeInvalidCIDR(c *gc.C) {
+	err := runExpose(c, "some-application-name", "--to-cidrs", "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `CIDR "10.0.0.0" not valid`)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
 
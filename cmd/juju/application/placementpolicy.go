@@ -0,0 +1,227 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/application"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	coreapplication "github.com/juju/juju/core/application"
+)
+
+var usagePlacementPolicySummary = `
+Displays the placement policy of an application.`[1:]
+
+var usagePlacementPolicyDetails = `
+Shows the placement policy set for an application with ` + "`juju set-\nplacement-policy`" + `.
+
+Examples:
+    juju placement-policy mysql
+    juju placement-policy --format yaml ceph-osd
+
+See also:
+    set-placement-policy`
+
+var usageSetPlacementPolicySummary = `
+Sets the placement policy of an application.`[1:]
+
+var usageSetPlacementPolicyDetails = `
+Sets the rules used to choose machines for the units of an application.
+The policy applies to units assigned from now on; units that are already
+on machines are not moved. The rules are:
+
+    anti-affinity=<application>[,<application>...]
+        The units of the application never share a host machine, or
+        containers on it, with units of the listed applications. The
+        rule applies in both directions. The machines provisioned for
+        the application are also distributed across availability zones
+        away from those of the listed applications.
+
+    strategy=spread
+        No two units of the application share a host machine, whether
+        directly or in containers.
+
+    strategy=pack
+        Units placed in new containers without a host machine go into
+        containers on a host already running units of the application,
+        and machines for the application are not distributed across
+        availability zones.
+
+A unit that cannot be placed without breaking a placement policy stays
+unassigned, and the reason is shown as its agent status by ` + "`juju status`" + `.
+An empty value clears a rule; a rule not given is cleared too.
+
+Examples:
+    juju set-placement-policy mysql anti-affinity=ceph-osd
+    juju set-placement-policy wordpress strategy=spread anti-affinity=mysql,ceph-osd
+    juju set-placement-policy wordpress strategy=
+
+See also:
+    placement-policy
+    add-unit
+    deploy`
+
+type applicationPlacementPolicyAPI interface {
+	Close() error
+	PlacementPolicy(string) (coreapplication.PlacementPolicy, error)
+	SetPlacementPolicy(string, coreapplication.PlacementPolicy) error
+}
+
+type applicationPlacementPolicyCommand struct {
+	modelcmd.ModelCommandBase
+	ApplicationName string
+	api             applicationPlacementPolicyAPI
+}
+
+func (c *applicationPlacementPolicyCommand) getAPI() (applicationPlacementPolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+func (c *applicationPlacementPolicyCommand) parseApplicationName(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.Errorf("no application name specified")
+	}
+	if !names.IsValidApplication(args[0]) {
+		return nil, errors.Errorf("invalid application name %q", args[0])
+	}
+	c.ApplicationName = args[0]
+	return args[1:], nil
+}
+
+// NewPlacementPolicyCommand returns a command which shows the
+// placement policy of an application.
+func NewPlacementPolicyCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&placementPolicyCommand{})
+}
+
+type placementPolicyCommand struct {
+	applicationPlacementPolicyCommand
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *placementPolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "placement-policy",
+		Args:    "<application>",
+		Purpose: usagePlacementPolicySummary,
+		Doc:     usagePlacementPolicyDetails,
+	})
+}
+
+// placementPolicy is the serialised form of a placement policy.
+type placementPolicy struct {
+	AntiAffinity []string `yaml:"anti-affinity,omitempty" json:"anti-affinity,omitempty"`
+	Strategy     string   `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+}
+
+func formatPlacementPolicy(writer io.Writer, value interface{}) error {
+	policy := value.(placementPolicy)
+	fmt.Fprint(writer, coreapplication.PlacementPolicy{
+		AntiAffinity: policy.AntiAffinity,
+		Strategy:     coreapplication.PlacementStrategy(policy.Strategy),
+	}.String())
+	return nil
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *placementPolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "policy", map[string]cmd.Formatter{
+		"policy": formatPlacementPolicy,
+		"yaml":   cmd.FormatYaml,
+		"json":   cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *placementPolicyCommand) Init(args []string) error {
+	args, err := c.parseApplicationName(args)
+	if err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *placementPolicyCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+
+	policy, err := apiclient.PlacementPolicy(c.ApplicationName)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, placementPolicy{
+		AntiAffinity: policy.AntiAffinity,
+		Strategy:     string(policy.Strategy),
+	})
+}
+
+// NewSetPlacementPolicyCommand returns a command which sets the
+// placement policy of an application.
+func NewSetPlacementPolicyCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&setPlacementPolicyCommand{})
+}
+
+type setPlacementPolicyCommand struct {
+	applicationPlacementPolicyCommand
+	Policy coreapplication.PlacementPolicy
+}
+
+// Info implements Command.Info.
+func (c *setPlacementPolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-placement-policy",
+		Args:    "<application> <rule>=<value> ...",
+		Purpose: usageSetPlacementPolicySummary,
+		Doc:     usageSetPlacementPolicyDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *setPlacementPolicyCommand) Init(args []string) error {
+	args, err := c.parseApplicationName(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.Errorf("no placement policy specified")
+	}
+	if c.Policy, err = coreapplication.ParsePlacementPolicy(args...); err != nil {
+		return err
+	}
+	return c.Policy.Validate(c.ApplicationName)
+}
+
+// Run implements Command.Run.
+func (c *setPlacementPolicyCommand) Run(_ *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+
+	err = apiclient.SetPlacementPolicy(c.ApplicationName, c.Policy)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
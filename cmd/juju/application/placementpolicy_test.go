@@ -0,0 +1,129 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	coreapplication "github.com/juju/juju/core/application"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type PlacementPolicyCommandsSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakePlacementPolicyAPI
+}
+
+var _ = gc.Suite(&PlacementPolicyCommandsSuite{})
+
+func (s *PlacementPolicyCommandsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakePlacementPolicyAPI{}
+}
+
+func (s *PlacementPolicyCommandsSuite) TestSetInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no application name specified`,
+	}, {
+		args: []string{"strategy=spread"},
+		err:  `invalid application name "strategy=spread"`,
+	}, {
+		args: []string{"mysql"},
+		err:  `no placement policy specified`,
+	}, {
+		args: []string{"mysql", "affinity=wordpress"},
+		err:  `placement policy "affinity" not valid`,
+	}, {
+		args: []string{"mysql", "strategy=scatter"},
+		err:  `placement strategy "scatter" not valid`,
+	}, {
+		args: []string{"mysql", "anti-affinity=mysql"},
+		err:  `anti-affinity of "mysql" with itself not valid`,
+	}, {
+		args: []string{"mysql", "anti-affinity=ceph-osd", "strategy=spread"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		cmd := application.NewSetPlacementPolicyCommandForTest(s.api, jujuclienttesting.MinimalStore())
+		err := cmdtesting.InitCommand(cmd, test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *PlacementPolicyCommandsSuite) TestSet(c *gc.C) {
+	cmd := application.NewSetPlacementPolicyCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "strategy=spread anti-affinity=ceph-osd")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "SetPlacementPolicy", "mysql", coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"ceph-osd"},
+		Strategy:     coreapplication.PlacementStrategySpread,
+	})
+	s.api.CheckCall(c, 1, "Close")
+}
+
+func (s *PlacementPolicyCommandsSuite) TestGetInit(c *gc.C) {
+	cmd := application.NewPlacementPolicyCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	err := cmdtesting.InitCommand(cmd, []string{})
+	c.Check(err, gc.ErrorMatches, `no application name specified`)
+	err = cmdtesting.InitCommand(cmd, []string{"mysql", "extra"})
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *PlacementPolicyCommandsSuite) TestGet(c *gc.C) {
+	s.api.policy = coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"ceph-osd", "mysql"},
+		Strategy:     coreapplication.PlacementStrategyPack,
+	}
+	cmd := application.NewPlacementPolicyCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "anti-affinity=ceph-osd,mysql strategy=pack\n")
+	s.api.CheckCall(c, 0, "PlacementPolicy", "wordpress")
+}
+
+func (s *PlacementPolicyCommandsSuite) TestGetYAML(c *gc.C) {
+	s.api.policy = coreapplication.PlacementPolicy{
+		AntiAffinity: []string{"mysql"},
+		Strategy:     coreapplication.PlacementStrategySpread,
+	}
+	cmd := application.NewPlacementPolicyCommandForTest(s.api, jujuclienttesting.MinimalStore())
+	ctx, err := cmdtesting.RunCommand(c, cmd, "wordpress", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"anti-affinity:\n"+
+		"- mysql\n"+
+		"strategy: spread\n")
+}
+
+type fakePlacementPolicyAPI struct {
+	jujutesting.Stub
+	policy coreapplication.PlacementPolicy
+}
+
+func (f *fakePlacementPolicyAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakePlacementPolicyAPI) PlacementPolicy(application string) (coreapplication.PlacementPolicy, error) {
+	f.MethodCall(f, "PlacementPolicy", application)
+	return f.policy, f.NextErr()
+}
+
+func (f *fakePlacementPolicyAPI) SetPlacementPolicy(application string, policy coreapplication.PlacementPolicy) error {
+	f.MethodCall(f, "SetPlacementPolicy", application, policy)
+	return f.NextErr()
+}
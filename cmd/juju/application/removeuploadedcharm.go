@@ -0,0 +1,102 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	apicharms "github.com/juju/juju/api/charms"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewRemoveUploadedCharmCommand returns a command which removes a charm
+// revision from the controller's charm repository.
+func NewRemoveUploadedCharmCommand() cmd.Command {
+	return modelcmd.Wrap(&removeUploadedCharmCommand{})
+}
+
+// RemoveUploadedCharmAPI defines the API methods required by the
+// remove-uploaded-charm command.
+type RemoveUploadedCharmAPI interface {
+	Close() error
+	RemoveLocalCharm(*charm.URL) error
+}
+
+// removeUploadedCharmCommand is responsible for removing revisions of
+// charms uploaded to the controller's charm repository.
+type removeUploadedCharmCommand struct {
+	modelcmd.ModelCommandBase
+
+	api RemoveUploadedCharmAPI
+
+	curl *charm.URL
+}
+
+const removeUploadedCharmDoc = `
+The revision of a local charm uploaded with "juju upload-charm" is
+removed from the controller's charm repository, together with the
+resources stored with it. Models that already deployed the revision
+keep their copy of the charm.
+
+Revisions released to a channel can't be removed; release another
+revision to the channel first. Removing charms requires superuser access
+to the controller.
+
+Examples:
+    juju remove-uploaded-charm local:focal/mycharm-3
+
+See also:
+    upload-charm
+`
+
+// Info implements cmd.Command.
+func (c *removeUploadedCharmCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-uploaded-charm",
+		Args:    "<charm URL>",
+		Purpose: "Remove a charm revision from the controller's charm repository.",
+		Doc:     removeUploadedCharmDoc,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *removeUploadedCharmCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no charm URL specified")
+	}
+	curl, err := charm.ParseURL(args[0])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if curl.Schema != "local" || curl.Series == "" || curl.Revision < 0 {
+		return errors.Errorf("expected a local charm URL with series and revision, got %q", args[0])
+	}
+	c.curl = curl
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *removeUploadedCharmCommand) Run(ctx *cmd.Context) error {
+	if c.api == nil {
+		apiRoot, err := c.NewAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.api = apicharms.NewClient(apiRoot)
+	}
+	defer func() { _ = c.api.Close() }()
+
+	err := c.api.RemoveLocalCharm(c.curl)
+	if errors.IsNotSupported(err) {
+		return errors.Errorf("the charm repository is not supported by this controller")
+	} else if err != nil {
+		return block.ProcessBlockedError(errors.Annotatef(err, "removing charm %q", c.curl), block.BlockRemove)
+	}
+	ctx.Infof("Removed charm %q from the charm repository.", c.curl.String())
+	return nil
+}
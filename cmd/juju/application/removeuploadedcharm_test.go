@@ -0,0 +1,80 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type removeUploadedCharmSuite struct {
+	testing.IsolationSuite
+	api *mockRemoveUploadedCharmAPI
+}
+
+var _ = gc.Suite(&removeUploadedCharmSuite{})
+
+func (s *removeUploadedCharmSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockRemoveUploadedCharmAPI{Stub: &testing.Stub{}}
+}
+
+func (s *removeUploadedCharmSuite) runRemoveUploadedCharm(c *gc.C, args ...string) (*cmd.Context, error) {
+	store := jujuclienttesting.MinimalStore()
+	return cmdtesting.RunCommand(c, application.NewRemoveUploadedCharmCommandForTest(s.api, store), args...)
+}
+
+func (s *removeUploadedCharmSuite) TestRemoveUploadedCharm(c *gc.C) {
+	ctx, err := s.runRemoveUploadedCharm(c, "local:bionic/dummy-3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `Removed charm "local:bionic/dummy-3" from the charm repository.`+"\n")
+	s.api.CheckCallNames(c, "RemoveLocalCharm", "Close")
+	s.api.CheckCall(c, 0, "RemoveLocalCharm", charm.MustParseURL("local:bionic/dummy-3"))
+}
+
+func (s *removeUploadedCharmSuite) TestRemoveUploadedCharmError(c *gc.C) {
+	s.api.SetErrors(errors.New("charm is released to latest/edge"))
+	_, err := s.runRemoveUploadedCharm(c, "local:bionic/dummy-3")
+	c.Assert(err, gc.ErrorMatches, `removing charm "local:bionic/dummy-3": charm is released to latest/edge`)
+}
+
+func (s *removeUploadedCharmSuite) TestRemoveUploadedCharmNotSupported(c *gc.C) {
+	s.api.SetErrors(errors.NotSupportedf("removing local charms"))
+	_, err := s.runRemoveUploadedCharm(c, "local:bionic/dummy-3")
+	c.Assert(err, gc.ErrorMatches, "the charm repository is not supported by this controller")
+}
+
+func (s *removeUploadedCharmSuite) TestInit(c *gc.C) {
+	_, err := s.runRemoveUploadedCharm(c)
+	c.Assert(err, gc.ErrorMatches, "no charm URL specified")
+	_, err = s.runRemoveUploadedCharm(c, "local:bionic/dummy-3", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	_, err = s.runRemoveUploadedCharm(c, "cs:bionic/dummy-3")
+	c.Assert(err, gc.ErrorMatches, `expected a local charm URL with series and revision, got "cs:bionic/dummy-3"`)
+	_, err = s.runRemoveUploadedCharm(c, "local:bionic/dummy")
+	c.Assert(err, gc.ErrorMatches, `expected a local charm URL with series and revision, got "local:bionic/dummy"`)
+	s.api.CheckNoCalls(c)
+}
+
+type mockRemoveUploadedCharmAPI struct {
+	*testing.Stub
+}
+
+func (m *mockRemoveUploadedCharmAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return nil
+}
+
+func (m *mockRemoveUploadedCharmAPI) RemoveLocalCharm(curl *charm.URL) error {
+	m.MethodCall(m, "RemoveLocalCharm", curl)
+	return m.NextErr()
+}
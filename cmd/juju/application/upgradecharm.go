@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/juju/charm/v7"
 	charmresource "github.com/juju/charm/v7/resource"
@@ -29,11 +31,13 @@ import (
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/api/spaces"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/charmstore"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/storage"
@@ -61,6 +65,8 @@ func newUpgradeCharmCommand() *upgradeCharmCommand {
 			return spaces.NewAPI(conn)
 		},
 		CharmStoreURLGetter: getCharmStoreAPIURL,
+		CharmHubURLGetter:   getCharmHubURL,
+		NewCharmHubClient:   newCharmHubClient,
 		NewCharmStore: func(
 			bakeryClient *httpbakery.Client,
 			csURL string,
@@ -94,6 +100,9 @@ type CharmUpgradeClient interface {
 // by the upgrade-charm command.
 type CharmClient interface {
 	CharmInfo(string) (*charms.CharmInfo, error)
+	ResolveLocalCharm(name, series, channel string) (*charm.URL, string, error)
+	AddCharmFromRepository(curl *charm.URL) error
+	AddRepositoryResources(curl *charm.URL, applicationName string, exclude []string) (map[string]string, error)
 }
 
 // ResourceLister defines a subset of the resources facade, as required
@@ -128,6 +137,8 @@ type upgradeCharmCommand struct {
 	NewResourceLister     func(base.APICallCloser) (ResourceLister, error)
 	NewSpacesClient       func(base.APICallCloser) SpacesAPI
 	CharmStoreURLGetter   func(base.APICallCloser) (string, error)
+	CharmHubURLGetter     func(base.APICallCloser) (string, error)
+	NewCharmHubClient     NewCharmHubClientFunc
 
 	ApplicationName string
 	// Force should be ubiquitous and we should eventually deprecate both
@@ -166,8 +177,12 @@ When no options are set, the application's charm will be upgraded to the latest
 revision available in the repository from which it was originally deployed. An
 explicit revision can be chosen with the --revision option.
 
-A path will need to be supplied to allow an updated copy of the charm
-to be located.
+Applications of local charms are upgraded to the latest revision released to
+the channel they track in the controller's charm repository, populated with
+"juju upload-charm". Resources stored with that revision replace the
+application's resources, unless they are given with --resource. If no revision
+has been released for the charm, a path will need to be supplied to allow an
+updated copy of the charm to be located.
 
 Deploying from a path is intended to suit the workflow of a charm author working
 on a single client machine; use of this deployment method from multiple clients
@@ -229,6 +244,24 @@ is determined by the contents of the charm at the specified path.
 number with --switch, give it in the charm URL, for instance "cs:wordpress-5"
 would specify revision number 5 of the wordpress charm.
 
+To switch to a charm published to charmhub, prefix its name with "ch:", for
+instance "ch:wordpress". The latest revision released to the --channel option
+(by default "stable") and built for the application's series and architecture
+is downloaded, verified and added to the model as a local charm:
+
+  juju upgrade-charm wordpress --switch ch:wordpress --channel candidate
+
+The charmhub origin of the charm and its channel are recorded against the
+application, so applications deployed from charmhub are upgraded without
+--switch. The latest revision released to the channel the application tracks,
+or to the --channel option if given, is fetched from charmhub:
+
+  juju upgrade-charm wordpress
+
+To switch to a charm uploaded to the controller's charm repository, give its
+local charm URL, for instance "local:wordpress". Unless a revision is given, the
+latest revision released to the --channel option (by default "stable") is used.
+
 Use of the --force-units option is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
@@ -251,7 +284,7 @@ func (c *upgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.Force, "force", false, "Allow a charm to be upgraded which bypasses LXD profile allow list")
 	f.BoolVar(&c.ForceUnits, "force-units", false, "Upgrade all units immediately, even if in error state")
-	f.StringVar((*string)(&c.Channel), "channel", "", "Channel to use when getting the charm or bundle from the charm store")
+	f.StringVar((*string)(&c.Channel), "channel", "", "Channel to use when getting the charm from the charm store, charmhub or the controller's charm repository")
 	f.BoolVar(&c.ForceSeries, "force-series", false, "Upgrade even if series of deployed applications are not supported by the new charm")
 	f.StringVar(&c.SwitchURL, "switch", "", "Crossgrade to a different charm")
 	f.StringVar(&c.CharmPath, "path", "", "Upgrade to a charm located at path")
@@ -327,20 +360,6 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
-	newRef := c.SwitchURL
-	if newRef == "" {
-		newRef = c.CharmPath
-	}
-	if c.SwitchURL == "" && c.CharmPath == "" {
-		// If the charm we are upgrading is local, then we must
-		// specify a path or switch url to upgrade with.
-		if oldURL.Schema == "local" {
-			return errors.New("upgrading a local charm requires either --path or --switch")
-		}
-		// No new URL specified, but revision might have been.
-		newRef = oldURL.WithRevision(c.Revision).String()
-	}
-
 	// First, ensure the charm is added to the model.
 	bakeryClient, err := c.BakeryClient()
 	if err != nil {
@@ -364,6 +383,20 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		c.Channel = csclientparams.Channel(applicationInfo.Channel)
 	}
 
+	newRef := c.SwitchURL
+	if newRef == "" {
+		newRef = c.CharmPath
+	}
+	if c.SwitchURL == "" && c.CharmPath == "" {
+		// No new URL specified, but revision might have been. Charmhub
+		// charms are refreshed from charmhub, and other local charms
+		// are upgraded from the controller's charm repository.
+		newRef = oldURL.WithRevision(c.Revision).String()
+		if origin := applicationInfo.CharmOrigin; origin != nil && origin.Source == charmHubOriginSource {
+			newRef = charmHubPrefix + oldURL.WithRevision(c.Revision).Path()
+		}
+	}
+
 	newURL, err := url.Parse(newRef)
 	if err != nil {
 		return errors.Trace(err)
@@ -373,15 +406,52 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		ctx.Infof("Looking up metadata for charm %v (channel: %s)", newRef, c.Channel)
 	}
 
-	chID, csMac, err := c.addCharm(addCharmParams{
-		charmAdder:     c.NewCharmAdder(apiRoot),
-		charmRepo:      c.NewCharmStore(bakeryClient, csURL, c.Channel),
-		authorizer:     newCharmStoreClient(bakeryClient, csURL),
-		oldURL:         oldURL,
-		newCharmRef:    newRef,
-		deployedSeries: applicationInfo.Series,
-		force:          c.Force,
-	})
+	var charmHubClient CharmHubClient
+	if isCharmHubRef(newRef) {
+		if err := c.checkApplicationFacadeSupport(apiRoot, "using charms from charmhub", 15); err != nil {
+			return err
+		}
+		charmHubURL, err := c.CharmHubURLGetter(apiRoot)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if charmHubClient, err = c.NewCharmHubClient(charmHubURL); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	var localCharmResolver CharmClient
+	if strings.HasPrefix(newRef, "local:") {
+		localCharmResolver = c.NewCharmClient(apiRoot)
+	}
+
+	addParams := addCharmParams{
+		charmAdder:         c.NewCharmAdder(apiRoot),
+		charmRepo:          c.NewCharmStore(bakeryClient, csURL, c.Channel),
+		charmHubClient:     charmHubClient,
+		localCharmResolver: localCharmResolver,
+		authorizer:         newCharmStoreClient(bakeryClient, csURL),
+		oldURL:             oldURL,
+		oldOrigin:          applicationInfo.CharmOrigin,
+		newCharmRef:        newRef,
+		deployedSeries:     applicationInfo.Series,
+		constraints:        applicationInfo.Constraints,
+		force:              c.Force,
+	}
+	var (
+		chID           charmstore.CharmID
+		csMac          *macaroon.Macaroon
+		origin         *params.CharmOrigin
+		fromRepository bool
+	)
+	switch {
+	case isCharmHubRef(newRef):
+		chID, origin, err = c.addCharmHubCharm(ctx, addParams)
+	case localCharmResolver != nil:
+		chID, fromRepository, err = c.addLocalRepositoryCharm(addParams)
+	default:
+		chID, csMac, err = c.addCharm(addParams)
+	}
 	if err != nil {
 		if termErr, ok := errors.Cause(err).(*common.TermsRequiredError); ok {
 			return errors.Trace(termErr.UserErr())
@@ -400,7 +470,13 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	ids, err := c.upgradeResources(apiRoot, resourceLister, chID, csMac, meta)
+	var repositoryIDs map[string]string
+	if fromRepository {
+		if repositoryIDs, err = c.addRepositoryResources(localCharmResolver, chID.URL); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	ids, err := c.upgradeResources(apiRoot, resourceLister, chID, csMac, meta, repositoryIDs)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -441,6 +517,7 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		ResourceIDs:        ids,
 		StorageConstraints: c.Storage,
 		EndpointBindings:   c.Bindings,
+		CharmOrigin:        origin,
 	}
 
 	if err := block.ProcessBlockedError(charmUpgradeClient.SetCharm(generation, cfg), block.BlockChange); err != nil {
@@ -524,6 +601,7 @@ func (c *upgradeCharmCommand) upgradeResources(
 	chID charmstore.CharmID,
 	csMac *macaroon.Macaroon,
 	meta map[string]charmresource.Meta,
+	repositoryIDs map[string]string,
 ) (map[string]string, error) {
 	filtered, err := getUpgradeResources(
 		resourceLister,
@@ -534,8 +612,15 @@ func (c *upgradeCharmCommand) upgradeResources(
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Resources added from the charm repository are already pending.
+	for name := range repositoryIDs {
+		delete(filtered, name)
+	}
 	if len(filtered) == 0 {
-		return nil, nil
+		if len(repositoryIDs) == 0 {
+			return nil, nil
+		}
+		return repositoryIDs, nil
 	}
 
 	// Note: the validity of user-supplied resources to be uploaded will be
@@ -548,7 +633,32 @@ func (c *upgradeCharmCommand) upgradeResources(
 		filtered,
 		apiRoot,
 	)
-	return ids, errors.Trace(err)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, pendingID := range repositoryIDs {
+		if ids == nil {
+			ids = make(map[string]string)
+		}
+		ids[name] = pendingID
+	}
+	return ids, nil
+}
+
+// addRepositoryResources adds the resources stored with the charm in the
+// controller's charm repository, other than those given with --resource,
+// as pending resources of the application.
+func (c *upgradeCharmCommand) addRepositoryResources(client CharmClient, curl *charm.URL) (map[string]string, error) {
+	exclude := make([]string, 0, len(c.Resources))
+	for name := range c.Resources {
+		exclude = append(exclude, name)
+	}
+	sort.Strings(exclude)
+	ids, err := client.AddRepositoryResources(curl, c.ApplicationName, exclude)
+	if err != nil {
+		return nil, errors.Annotate(err, "adding resources from the charm repository")
+	}
+	return ids, nil
 }
 
 func getUpgradeResources(
@@ -648,13 +758,17 @@ var getCharmStoreAPIURL = func(conAPIRoot base.APICallCloser) (string, error) {
 }
 
 type addCharmParams struct {
-	charmAdder     CharmAdder
-	authorizer     macaroonGetter
-	charmRepo      charmrepoForDeploy
-	oldURL         *charm.URL
-	newCharmRef    string
-	deployedSeries string
-	force          bool
+	charmAdder         CharmAdder
+	authorizer         macaroonGetter
+	charmRepo          charmrepoForDeploy
+	charmHubClient     CharmHubClient
+	localCharmResolver CharmClient
+	oldURL             *charm.URL
+	oldOrigin          *params.CharmOrigin
+	newCharmRef        string
+	deployedSeries     string
+	constraints        constraints.Value
+	force              bool
 }
 
 // addCharm interprets the new charmRef and adds the specified charm if
@@ -725,6 +839,142 @@ func (c *upgradeCharmCommand) addCharm(params addCharmParams) (charmstore.CharmI
 	return id, csMac, nil
 }
 
+// addLocalRepositoryCharm resolves the local charm referenced by the new
+// charm reference from the controller's charm repository, which holds the
+// charms uploaded with "juju upload-charm", and adds it to the model.
+// Unless a revision is given, the latest revision released to the channel
+// the application tracks is used. Whether the charm was added from the
+// repository, rather than being a charm uploaded only to the model, is
+// also returned.
+func (c *upgradeCharmCommand) addLocalRepositoryCharm(params addCharmParams) (charmstore.CharmID, bool, error) {
+	var id charmstore.CharmID
+	refURL, err := charm.ParseURL(params.newCharmRef)
+	if err != nil {
+		return id, false, errors.Trace(err)
+	}
+	if refURL.Name != params.oldURL.Name {
+		return id, false, errors.Errorf("cannot upgrade %q to %q", params.oldURL.Name, refURL.Name)
+	}
+	newURL := *refURL
+	if newURL.Series == "" {
+		newURL.Series = params.deployedSeries
+	}
+	if newURL.Series == "" {
+		newURL.Series = params.oldURL.Series
+	}
+	id.Channel = c.Channel
+	if newURL.Revision >= 0 {
+		if newURL == *params.oldURL {
+			return id, false, errors.Errorf("already running specified charm %q", newURL.String())
+		}
+		id.URL = &newURL
+		// The revision may have been uploaded to the model, or
+		// published to the charm repository from another model.
+		err := params.localCharmResolver.AddCharmFromRepository(id.URL)
+		switch {
+		case err == nil:
+			return id, true, nil
+		case errors.IsNotFound(err), errors.IsNotSupported(err), errors.IsAlreadyExists(err):
+			return id, false, nil
+		default:
+			return id, false, errors.Annotatef(err, "adding charm %q from the charm repository", id.URL)
+		}
+	}
+
+	channel := string(c.Channel)
+	if channel == "" {
+		channel = string(csclientparams.StableChannel)
+	}
+	resolvedURL, resolvedChannel, err := params.localCharmResolver.ResolveLocalCharm(newURL.Name, newURL.Series, channel)
+	if errors.IsNotSupported(err) || errors.IsNotFound(err) {
+		if c.SwitchURL == "" {
+			// Nothing has been released for the charm, so the new
+			// revision must be supplied.
+			return id, false, errors.New("upgrading a local charm requires either --path or --switch")
+		}
+		return id, false, errors.Errorf("no revision of local charm %q for series %q released to channel %q", newURL.Name, newURL.Series, channel)
+	} else if err != nil {
+		return id, false, errors.Trace(err)
+	}
+	if *resolvedURL == *params.oldURL {
+		return id, false, errors.Errorf("already running latest charm %q", resolvedURL)
+	}
+	if err := params.localCharmResolver.AddCharmFromRepository(resolvedURL); err != nil {
+		return id, false, errors.Annotatef(err, "adding charm %q from the charm repository", resolvedURL)
+	}
+	id.URL = resolvedURL
+	id.Channel = csclientparams.Channel(resolvedChannel)
+	return id, true, nil
+}
+
+// addCharmHubCharm downloads the charm referenced in charmhub for the
+// deployed series and adds it to the model as a local charm. Applications
+// already deployed from charmhub are refreshed through the refresh
+// endpoint, so the latest revision released to the tracked channel is
+// used. The origin of the added charm is returned to record against the
+// application.
+func (c *upgradeCharmCommand) addCharmHubCharm(ctx *cmd.Context, params addCharmParams) (id charmstore.CharmID, origin *params.CharmOrigin, err error) {
+	refURL, err := parseCharmHubRef(params.newCharmRef)
+	if err != nil {
+		return id, nil, errors.Trace(err)
+	}
+	series := params.deployedSeries
+	if series == "" {
+		series = params.oldURL.Series
+	}
+	if refURL.Series != "" && refURL.Series != series {
+		return id, nil, errors.Errorf("cannot upgrade %q charm to a charm for series %q", series, refURL.Series)
+	}
+	platform := charmhub.RefreshPlatform{
+		Architecture: charmHubArch(params.constraints),
+		Series:       series,
+	}
+
+	// Only a charm which is the same as the one deployed can be
+	// refreshed, otherwise the new charm is installed.
+	var refreshConfig charmhub.RefreshConfig
+	old := params.oldOrigin
+	refreshing := old != nil && old.Source == charmHubOriginSource && refURL.Name == params.oldURL.Name
+	if refreshing {
+		refreshConfig, err = charmHubRefreshConfig(refURL, old, string(c.Channel), platform)
+	} else {
+		refreshConfig, err = charmHubInstallConfig(refURL, string(c.Channel), platform)
+	}
+	if err != nil {
+		return id, nil, errors.Trace(err)
+	}
+
+	stdCtx, cancel := interruptibleContext(ctx)
+	defer cancel()
+	err = withCharmHubDownloadDir(func(dir string) error {
+		ch, err := downloadCharmHubCharm(stdCtx, params.charmHubClient, refURL.Name, refreshConfig, platform, dir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if *ch.URL == *params.oldURL {
+			if refreshing && refURL.Revision < 0 {
+				return errors.Errorf("already running latest charm %q", ch.URL)
+			}
+			return errors.Errorf("already running charm %q", ch.URL)
+		}
+		if _, err := charm.SeriesForCharm(series, ch.Archive.Meta().Series); err != nil && !c.ForceSeries {
+			return errors.Errorf(
+				"cannot upgrade from series %q to a charm supporting %q. Use --force-series to override.",
+				series, ch.Archive.Meta().Series,
+			)
+		}
+		if refURL.Revision < 0 && ch.Channel != "" {
+			id.Channel = csclientparams.Channel(ch.Channel)
+		} else {
+			id.Channel = c.Channel
+		}
+		origin = ch.Origin
+		id.URL, err = params.charmAdder.AddLocalCharm(ch.URL, ch.Archive, params.force)
+		return errors.Trace(err)
+	})
+	return id, origin, err
+}
+
 func allEndpoints(ci *charms.CharmInfo) set.Strings {
 	epSet := set.NewStrings()
 	for n := range ci.Meta.ExtraBindings {
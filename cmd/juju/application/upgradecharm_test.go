@@ -30,6 +30,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/charms"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub/charmhubtest"
+	"github.com/juju/juju/charmhub/transport"
 	jujucharmstore "github.com/juju/juju/charmstore"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -62,6 +64,7 @@ type BaseUpgradeCharmSuite struct {
 	modelConfigGetter mockModelConfigGetter
 	resourceLister    mockResourceLister
 	spacesClient      mockSpacesClient
+	charmHubURL       string
 }
 
 func (s *BaseUpgradeCharmSuite) runUpgradeCharm(c *gc.C, args ...string) (*cmd.Context, error) {
@@ -205,6 +208,10 @@ func (s *BaseUpgradeCharmSuite) upgradeCommand() cmd.Command {
 			s.AddCall("NewSpacesClient", conn)
 			return &s.spacesClient
 		},
+		func(conn base.APICallCloser) (string, error) {
+			s.AddCall("CharmHubURLGetter", conn)
+			return s.charmHubURL, s.NextErr()
+		},
 	)
 	return cmd
 }
@@ -608,6 +615,187 @@ func (s *UpgradeCharmSuite) TestSwitchDifferentRevision(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *UpgradeCharmSuite) setUpCharmHub(c *gc.C) *charmhubtest.Server {
+	archivePath := testcharms.RepoWithSeries("quantal").CharmArchivePath(c.MkDir(), "dummy")
+	archive, err := ioutil.ReadFile(archivePath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	srv := charmhubtest.NewServer()
+	s.AddCleanup(func(*gc.C) { srv.Close() })
+	srv.AddRevision("dummy", 7, "1.0", []transport.Platform{
+		{Architecture: "amd64", OS: "ubuntu", Series: "quantal"},
+	}, archive)
+	c.Assert(srv.Release("dummy", 7, "stable"), jc.ErrorIsNil)
+	s.charmHubURL = srv.URL
+	s.apiConnection.bestFacadeVersion = 15
+	return srv
+}
+
+func (s *UpgradeCharmSuite) TestSwitchCharmHub(c *gc.C) {
+	s.setUpCharmHub(c)
+	_, err := s.runUpgradeCharm(c, "foo", "--switch=ch:dummy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectedURL := charm.MustParseURL("local:quantal/dummy-7")
+	s.charmAdder.CheckCallNames(c, "AddLocalCharm")
+	calls := s.charmAdder.Calls()
+	c.Assert(calls[0].Args[0], gc.DeepEquals, expectedURL)
+	c.Assert(calls[0].Args[1].(charm.Charm).Meta().Name, gc.Equals, "dummy")
+	s.charmAPIClient.CheckCall(c, 2, "SetCharm", model.GenerationMaster, application.SetCharmConfig{
+		ApplicationName: "foo",
+		CharmID: jujucharmstore.CharmID{
+			URL:     expectedURL,
+			Channel: "latest/stable",
+		},
+		CharmOrigin: &params.CharmOrigin{
+			Source:   "charmhub",
+			ID:       "charm-id-dummy",
+			Revision: 7,
+		},
+	})
+	s.CheckCallNames(c, "OpenAPI", "NewCharmAPIClient", "OpenAPI", "CharmStoreURLGetter", "CharmHubURLGetter",
+		"NewCharmAdder", "NewCharmStore", "NewCharmClient", "NewResourceLister", "NewCharmClient")
+}
+
+func (s *UpgradeCharmSuite) TestSwitchCharmHubFacadeTooOld(c *gc.C) {
+	s.setUpCharmHub(c)
+	s.apiConnection.bestFacadeVersion = 14
+	_, err := s.runUpgradeCharm(c, "foo", "--switch=ch:dummy")
+	c.Assert(err, gc.ErrorMatches,
+		"using charms from charmhub at upgrade-charm time is not supported by server version 1.2.3")
+	s.charmAdder.CheckNoCalls(c)
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeCharmHubCharm(c *gc.C) {
+	srv := s.setUpCharmHub(c)
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/dummy-5")
+	s.charmAPIClient.channel = "latest/stable"
+	s.charmAPIClient.charmOrigin = &params.CharmOrigin{
+		Source:   "charmhub",
+		ID:       "charm-id-dummy",
+		Revision: 5,
+	}
+	_, err := s.runUpgradeCharm(c, "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(srv.RefreshActions(), jc.DeepEquals, []string{"refresh"})
+
+	expectedURL := charm.MustParseURL("local:quantal/dummy-7")
+	s.charmAdder.CheckCallNames(c, "AddLocalCharm")
+	c.Assert(s.charmAdder.Calls()[0].Args[0], gc.DeepEquals, expectedURL)
+	s.charmAPIClient.CheckCall(c, 2, "SetCharm", model.GenerationMaster, application.SetCharmConfig{
+		ApplicationName: "dummy",
+		CharmID: jujucharmstore.CharmID{
+			URL:     expectedURL,
+			Channel: "latest/stable",
+		},
+		CharmOrigin: &params.CharmOrigin{
+			Source:   "charmhub",
+			ID:       "charm-id-dummy",
+			Revision: 7,
+		},
+	})
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeCharmHubCharmAlreadyLatest(c *gc.C) {
+	s.setUpCharmHub(c)
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/dummy-7")
+	s.charmAPIClient.charmOrigin = &params.CharmOrigin{
+		Source:   "charmhub",
+		ID:       "charm-id-dummy",
+		Revision: 7,
+	}
+	_, err := s.runUpgradeCharm(c, "dummy")
+	c.Assert(err, gc.ErrorMatches, `already running latest charm "local:quantal/dummy-7"`)
+	s.charmAdder.CheckNoCalls(c)
+}
+
+func (s *UpgradeCharmSuite) TestSwitchCharmHubAlreadyRunning(c *gc.C) {
+	s.setUpCharmHub(c)
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/dummy-7")
+	_, err := s.runUpgradeCharm(c, "foo", "--switch=ch:dummy")
+	c.Assert(err, gc.ErrorMatches, `already running charm "local:quantal/dummy-7"`)
+}
+
+func (s *UpgradeCharmSuite) TestSwitchCharmHubDifferentSeries(c *gc.C) {
+	s.setUpCharmHub(c)
+	_, err := s.runUpgradeCharm(c, "foo", "--switch=ch:bionic/dummy")
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade "quantal" charm to a charm for series "bionic"`)
+}
+
+func (s *UpgradeCharmSuite) TestSwitchCharmHubNotFound(c *gc.C) {
+	s.setUpCharmHub(c)
+	_, err := s.runUpgradeCharm(c, "foo", "--switch=ch:missing")
+	c.Assert(err, gc.ErrorMatches, `resolving charm "missing": Name "missing" not found in the charm store.`)
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeLocalRepositoryCharm(c *gc.C) {
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/foo-1")
+	s.charmClient.localCharmURL = charm.MustParseURL("local:quantal/foo-3")
+	s.charmClient.localChannel = "latest/stable"
+	s.charmClient.repositoryIDs = map[string]string{"data": "pending-data"}
+	_, err := s.runUpgradeCharm(c, "foo", "--resource", "config=./config.tgz")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.charmAdder.CheckNoCalls(c)
+	s.charmClient.CheckCall(c, 0, "ResolveLocalCharm", "foo", "quantal", "stable")
+	s.charmClient.CheckCall(c, 1, "AddCharmFromRepository", s.charmClient.localCharmURL)
+	s.charmClient.CheckCall(c, 2, "AddRepositoryResources", s.charmClient.localCharmURL, "foo", []string{"config"})
+	s.charmAPIClient.CheckCall(c, 2, "SetCharm", model.GenerationMaster, application.SetCharmConfig{
+		ApplicationName: "foo",
+		CharmID: jujucharmstore.CharmID{
+			URL:     s.charmClient.localCharmURL,
+			Channel: "latest/stable",
+		},
+		ResourceIDs: map[string]string{"data": "pending-data"},
+	})
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeLocalRepositoryCharmRevision(c *gc.C) {
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/foo-1")
+	_, err := s.runUpgradeCharm(c, "foo", "--revision", "2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.charmClient.CheckCall(c, 0, "AddCharmFromRepository", charm.MustParseURL("local:quantal/foo-2"))
+	s.charmAPIClient.CheckCall(c, 2, "SetCharm", model.GenerationMaster, application.SetCharmConfig{
+		ApplicationName: "foo",
+		CharmID: jujucharmstore.CharmID{
+			URL: charm.MustParseURL("local:quantal/foo-2"),
+		},
+	})
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeLocalCharmRevisionNotInRepository(c *gc.C) {
+	// Revisions uploaded only to the model are used as they are.
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/foo-1")
+	s.charmClient.repositoryErr = errors.NotFoundf("charm")
+	_, err := s.runUpgradeCharm(c, "foo", "--revision", "2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.charmClient.CheckCallNames(c, "AddCharmFromRepository", "CharmInfo")
+	s.charmAPIClient.CheckCall(c, 2, "SetCharm", model.GenerationMaster, application.SetCharmConfig{
+		ApplicationName: "foo",
+		CharmID: jujucharmstore.CharmID{
+			URL: charm.MustParseURL("local:quantal/foo-2"),
+		},
+	})
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeLocalRepositoryCharmAlreadyRunning(c *gc.C) {
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/foo-3")
+	s.charmClient.localCharmURL = charm.MustParseURL("local:quantal/foo-3")
+	_, err := s.runUpgradeCharm(c, "foo")
+	c.Assert(err, gc.ErrorMatches, `already running latest charm "local:quantal/foo-3"`)
+}
+
+func (s *UpgradeCharmSuite) TestUpgradeLocalRepositoryCharmNotReleased(c *gc.C) {
+	s.charmAPIClient.charmURL = charm.MustParseURL("local:quantal/foo-1")
+	_, err := s.runUpgradeCharm(c, "foo")
+	c.Assert(err, gc.ErrorMatches, "upgrading a local charm requires either --path or --switch")
+
+	_, err = s.runUpgradeCharm(c, "foo", "--switch", "local:foo", "--channel", "edge")
+	c.Assert(err, gc.ErrorMatches, `no revision of local charm "foo" for series "quantal" released to channel "edge"`)
+}
+
 func (s *UpgradeCharmSuite) TestUpgradeWithTermsNotSigned(c *gc.C) {
 	termsRequiredError := &common.TermsRequiredError{Terms: []string{"term/1", "term/2"}}
 	s.charmAdder.SetErrors(termsRequiredError)
@@ -909,7 +1097,11 @@ func (m *mockCharmAdder) AddLocalCharm(curl *charm.URL, ch charm.Charm, force bo
 type mockCharmClient struct {
 	CharmClient
 	testing.Stub
-	charmInfo *charms.CharmInfo
+	charmInfo     *charms.CharmInfo
+	localCharmURL *charm.URL
+	localChannel  string
+	repositoryErr error
+	repositoryIDs map[string]string
 }
 
 func (m *mockCharmClient) CharmInfo(curl string) (*charms.CharmInfo, error) {
@@ -920,12 +1112,32 @@ func (m *mockCharmClient) CharmInfo(curl string) (*charms.CharmInfo, error) {
 	return m.charmInfo, nil
 }
 
+func (m *mockCharmClient) ResolveLocalCharm(name, series, channel string) (*charm.URL, string, error) {
+	m.MethodCall(m, "ResolveLocalCharm", name, series, channel)
+	if m.localCharmURL == nil {
+		return nil, "", errors.NotFoundf("local charm %q", name)
+	}
+	return m.localCharmURL, m.localChannel, nil
+}
+
+func (m *mockCharmClient) AddCharmFromRepository(curl *charm.URL) error {
+	m.MethodCall(m, "AddCharmFromRepository", curl)
+	return m.repositoryErr
+}
+
+func (m *mockCharmClient) AddRepositoryResources(curl *charm.URL, applicationName string, exclude []string) (map[string]string, error) {
+	m.MethodCall(m, "AddRepositoryResources", curl, applicationName, exclude)
+	return m.repositoryIDs, nil
+}
+
 type mockCharmAPIClient struct {
 	CharmAPIClient
 	testing.Stub
 	charmURL *charm.URL
 
-	bindings map[string]string
+	bindings    map[string]string
+	channel     string
+	charmOrigin *params.CharmOrigin
 }
 
 func (m *mockCharmAPIClient) GetCharmURL(branchName, appName string) (*charm.URL, error) {
@@ -942,6 +1154,8 @@ func (m *mockCharmAPIClient) Get(branchName, applicationName string) (*params.Ap
 	m.MethodCall(m, "Get", applicationName)
 	return &params.ApplicationGetResults{
 		EndpointBindings: m.bindings,
+		Channel:          m.channel,
+		CharmOrigin:      m.charmOrigin,
 	}, m.NextErr()
 }
 
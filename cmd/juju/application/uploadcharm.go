@@ -0,0 +1,224 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"archive/zip"
+	"os"
+
+	"github.com/juju/charm/v7"
+	charmresource "github.com/juju/charm/v7/resource"
+	"github.com/juju/charmrepo/v5"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/base"
+	apicharms "github.com/juju/juju/api/charms"
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmstore"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/resource/resourceadapters"
+)
+
+// NewUploadCharmCommand returns a command which uploads a charm to the
+// controller's charm repository.
+func NewUploadCharmCommand() cmd.Command {
+	return modelcmd.Wrap(&uploadCharmCommand{})
+}
+
+// UploadCharmAPI defines the API methods required by the upload-charm
+// command.
+type UploadCharmAPI interface {
+	AddLocalCharm(*charm.URL, charm.Charm, bool) (*charm.URL, error)
+	UploadResources(applicationID string, curl *charm.URL, files map[string]string, resources map[string]charmresource.Meta) (map[string]string, error)
+	PublishLocalCharm(*charm.URL, map[string]string) (*charm.URL, error)
+	ReleaseLocalCharm(*charm.URL, string) error
+}
+
+type uploadCharmAPIAdapter struct {
+	*apiClient
+	*charmsClient
+	conn base.APICallCloser
+}
+
+// UploadResources uploads the resource files as pending resources of the
+// application.
+func (a *uploadCharmAPIAdapter) UploadResources(
+	applicationID string, curl *charm.URL, files map[string]string, resources map[string]charmresource.Meta,
+) (map[string]string, error) {
+	return resourceadapters.DeployResources(applicationID, charmstore.CharmID{URL: curl}, nil, files, resources, a.conn)
+}
+
+// uploadCharmCommand is responsible for uploading charms to the
+// controller's charm repository, and releasing them to its channels.
+type uploadCharmCommand struct {
+	modelcmd.ModelCommandBase
+
+	api UploadCharmAPI
+
+	charmPath string
+	channel   string
+	series    string
+	force     bool
+	resources map[string]string
+}
+
+const uploadCharmDoc = `
+The charm at the given path, either a charm directory or archive, is
+uploaded to the controller's charm repository as a new revision of a
+local charm, which can then be deployed to any of the controller's models
+without access to the charm store or charmhub. Uploading a charm with the
+same content and resources as the latest revision in the repository
+doesn't add a new revision.
+
+Publishing charms to the repository requires superuser access to the
+controller. When a channel is given, the uploaded revision is also
+released to that channel of the controller's charm repository, replacing
+the revision previously released there. Model writers can only deploy
+revisions released to a channel. Deploying a local charm without a revision,
+and upgrading an application of a local charm without --path or --switch,
+uses the latest revision released to the requested channel, or to the
+channel the application tracks. Channels take the form
+[<track>/]<risk>[/<branch>], with the risk being one of stable,
+candidate, beta or edge. When nothing is released to a channel without a
+branch, revisions released to a more stable risk of the same track are
+used instead.
+
+Revisions are released for the series the charm is uploaded for, which
+defaults to the first series the charm supports; use --series to choose
+another one.
+
+File resources given with --resource are stored in the charm repository
+with the uploaded revision, and are used when deploying or upgrading to
+that revision unless other files are supplied with --resource then.
+Revisions and their resources are kept by the controller, even when no
+application uses them, until they are removed with
+"juju remove-uploaded-charm".
+
+Examples:
+    juju upload-charm ./mycharm
+    juju upload-charm ./mycharm --channel edge --resource config=./config.tgz
+    juju upload-charm ./mycharm.charm --series focal --channel 2.0/stable
+    juju deploy local:mycharm --channel edge
+
+See also:
+    deploy
+    remove-uploaded-charm
+    upgrade-charm
+`
+
+// Info implements cmd.Command.
+func (c *uploadCharmCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "upload-charm",
+		Args:    "<charm path>",
+		Purpose: "Upload a charm to the controller's charm repository.",
+		Doc:     uploadCharmDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *uploadCharmCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.channel, "channel", "", "Channel of the charm repository to release the charm to")
+	f.Var(stringMap{&c.resources}, "resource", "File resource to store with the charm")
+	f.StringVar(&c.series, "series", "", "The series to upload the charm for")
+	f.BoolVar(&c.force, "force", false, "Allow a charm to be uploaded for an unsupported series")
+}
+
+// Init implements cmd.Command.
+func (c *uploadCharmCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no charm path specified")
+	}
+	c.charmPath = args[0]
+	if c.channel != "" {
+		if _, err := charmhub.ParseChannel(c.channel); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *uploadCharmCommand) Run(ctx *cmd.Context) error {
+	ch, curl, err := charmrepo.NewCharmAtPathForceSeries(c.charmPath, c.series, c.force)
+	if charm.IsMissingSeriesError(err) {
+		return errors.Errorf("series not specified for charm at %q; use --series", c.charmPath)
+	} else if errors.Cause(err) == zip.ErrFormat {
+		return errors.Errorf("invalid charm provided at %q", c.charmPath)
+	} else if _, ok := err.(*charmrepo.NotFoundError); ok || err == os.ErrNotExist {
+		return errors.NotFoundf("charm at %q", c.charmPath)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	resources := make(map[string]charmresource.Meta)
+	for name := range c.resources {
+		meta, ok := ch.Meta().Resources[name]
+		if !ok {
+			return errors.NotFoundf("resource %q of charm %q", name, curl.Name)
+		}
+		if meta.Type != charmresource.TypeFile {
+			return errors.Errorf("only file resources can be stored with charms, %q is a %s resource", name, meta.Type)
+		}
+		resources[name] = meta
+	}
+
+	if c.api == nil {
+		apiRoot, err := c.NewAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer func() { _ = apiRoot.Close() }()
+		c.api = &uploadCharmAPIAdapter{
+			apiClient:    &apiClient{Client: apiRoot.Client()},
+			charmsClient: &charmsClient{Client: apicharms.NewClient(apiRoot)},
+			conn:         apiRoot,
+		}
+	}
+
+	curl, err = c.api.AddLocalCharm(curl, ch, c.force)
+	if err != nil {
+		return block.ProcessBlockedError(errors.Annotatef(err, "uploading charm"), block.BlockChange)
+	}
+	ctx.Infof("Uploaded charm %q.", curl.String())
+
+	var pendingIDs map[string]string
+	if len(resources) > 0 {
+		// Resources are uploaded as pending resources of an application
+		// named after the charm, and then moved into the repository.
+		pendingIDs, err = c.api.UploadResources(curl.Name, curl, c.resources, resources)
+		if err != nil {
+			return block.ProcessBlockedError(errors.Annotatef(err, "uploading resources"), block.BlockChange)
+		}
+	}
+	published, err := c.api.PublishLocalCharm(curl, pendingIDs)
+	if errors.IsNotSupported(err) {
+		if c.channel == "" && len(resources) == 0 {
+			// Older controllers have no charm repository, but the
+			// charm can still be deployed by revision.
+			return nil
+		}
+		return errors.Errorf("the charm repository is not supported by this controller")
+	} else if err != nil {
+		return block.ProcessBlockedError(errors.Annotatef(err, "publishing charm %q", curl), block.BlockChange)
+	}
+	curl = published
+	ctx.Infof("Published charm %q to the charm repository.", curl.String())
+
+	if c.channel == "" {
+		return nil
+	}
+	err = c.api.ReleaseLocalCharm(curl, c.channel)
+	if errors.IsNotSupported(err) {
+		return errors.Errorf("releasing charms to channels is not supported by this controller")
+	} else if err != nil {
+		return block.ProcessBlockedError(errors.Annotatef(err, "releasing charm %q", curl), block.BlockChange)
+	}
+	ctx.Infof("Released charm %q to channel %s.", curl.String(), c.channel)
+	return nil
+}
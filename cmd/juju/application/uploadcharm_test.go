@@ -0,0 +1,171 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/charm/v7"
+	charmresource "github.com/juju/charm/v7/resource"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testcharms"
+)
+
+type uploadCharmSuite struct {
+	testing.IsolationSuite
+	api       *mockUploadCharmAPI
+	charmPath string
+}
+
+var _ = gc.Suite(&uploadCharmSuite{})
+
+func (s *uploadCharmSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockUploadCharmAPI{Stub: &testing.Stub{}, revision: 3}
+	s.charmPath = testcharms.RepoWithSeries("bionic").CharmDirPath("dummy")
+}
+
+func (s *uploadCharmSuite) runUploadCharm(c *gc.C, args ...string) (*cmd.Context, error) {
+	store := jujuclienttesting.MinimalStore()
+	return cmdtesting.RunCommand(c, application.NewUploadCharmCommandForTest(s.api, store), args...)
+}
+
+func (s *uploadCharmSuite) TestUploadCharm(c *gc.C) {
+	ctx, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Uploaded charm "local:bionic/dummy-3".`+"\n"+
+		`Published charm "local:bionic/dummy-3" to the charm repository.`+"\n",
+	)
+	s.api.CheckCallNames(c, "AddLocalCharm", "PublishLocalCharm")
+	c.Check(s.api.Calls()[0].Args[0], gc.DeepEquals, charm.MustParseURL("local:bionic/dummy-1"))
+	s.api.CheckCall(c, 1, "PublishLocalCharm", charm.MustParseURL("local:bionic/dummy-3"), map[string]string(nil))
+}
+
+func (s *uploadCharmSuite) TestUploadCharmWithResources(c *gc.C) {
+	charmPath := testcharms.RepoWithSeries("bionic").CharmDirPath("dummy-resource")
+	s.api.published = 4
+	ctx, err := s.runUploadCharm(c, charmPath, "--series", "bionic", "--resource", "dummy=./dummy.zip", "--channel", "edge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Uploaded charm "local:bionic/dummy-resource-3".`+"\n"+
+		`Published charm "local:bionic/dummy-resource-4" to the charm repository.`+"\n"+
+		`Released charm "local:bionic/dummy-resource-4" to channel edge.`+"\n",
+	)
+	s.api.CheckCallNames(c, "AddLocalCharm", "UploadResources", "PublishLocalCharm", "ReleaseLocalCharm")
+	curl := charm.MustParseURL("local:bionic/dummy-resource-3")
+	s.api.CheckCall(c, 1, "UploadResources", "dummy-resource", curl,
+		map[string]string{"dummy": "./dummy.zip"},
+		map[string]charmresource.Meta{"dummy": {
+			Name:        "dummy",
+			Type:        charmresource.TypeFile,
+			Path:        "dummy.zip",
+			Description: "One line description that is useful when operators need to push it.",
+		}},
+	)
+	s.api.CheckCall(c, 2, "PublishLocalCharm", curl, map[string]string{"dummy": "pending-dummy"})
+	s.api.CheckCall(c, 3, "ReleaseLocalCharm", charm.MustParseURL("local:bionic/dummy-resource-4"), "edge")
+}
+
+func (s *uploadCharmSuite) TestUploadCharmUnknownResource(c *gc.C) {
+	_, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic", "--resource", "bogus=./bogus.zip")
+	c.Assert(err, gc.ErrorMatches, `resource "bogus" of charm "dummy" not found`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *uploadCharmSuite) TestUploadCharmToChannel(c *gc.C) {
+	ctx, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic", "--channel", "edge")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		`Uploaded charm "local:bionic/dummy-3".`+"\n"+
+		`Released charm "local:bionic/dummy-3" to channel edge.`+"\n",
+	)
+	s.api.CheckCallNames(c, "AddLocalCharm", "PublishLocalCharm", "ReleaseLocalCharm")
+	s.api.CheckCall(c, 2, "ReleaseLocalCharm", charm.MustParseURL("local:bionic/dummy-3"), "edge")
+}
+
+func (s *uploadCharmSuite) TestUploadCharmRepositoryNotSupported(c *gc.C) {
+	s.api.SetErrors(nil, errors.NotSupportedf("publishing local charms"))
+	_, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic", "--channel", "edge")
+	c.Assert(err, gc.ErrorMatches, "the charm repository is not supported by this controller")
+}
+
+func (s *uploadCharmSuite) TestUploadCharmRepositoryNotSupportedWithoutChannel(c *gc.C) {
+	s.api.SetErrors(nil, errors.NotSupportedf("publishing local charms"))
+	ctx, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `Uploaded charm "local:bionic/dummy-3".`+"\n")
+}
+
+func (s *uploadCharmSuite) TestUploadCharmError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := s.runUploadCharm(c, s.charmPath, "--series", "bionic", "--channel", "edge")
+	c.Assert(err, gc.ErrorMatches, "uploading charm: boom")
+	s.api.CheckCallNames(c, "AddLocalCharm")
+}
+
+func (s *uploadCharmSuite) TestNotFound(c *gc.C) {
+	_, err := s.runUploadCharm(c, c.MkDir()+"/missing")
+	c.Assert(err, gc.ErrorMatches, `charm at ".*/missing" not found`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *uploadCharmSuite) TestInit(c *gc.C) {
+	_, err := s.runUploadCharm(c)
+	c.Assert(err, gc.ErrorMatches, "no charm path specified")
+	_, err = s.runUploadCharm(c, s.charmPath, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	_, err = s.runUploadCharm(c, s.charmPath, "--channel", "latest/bogus")
+	c.Assert(err, gc.ErrorMatches, `risk in channel "latest/bogus" not valid`)
+}
+
+type mockUploadCharmAPI struct {
+	*testing.Stub
+	revision  int
+	published int
+}
+
+func (m *mockUploadCharmAPI) AddLocalCharm(curl *charm.URL, ch charm.Charm, force bool) (*charm.URL, error) {
+	m.MethodCall(m, "AddLocalCharm", curl, ch, force)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return curl.WithRevision(m.revision), nil
+}
+
+func (m *mockUploadCharmAPI) UploadResources(
+	applicationID string, curl *charm.URL, files map[string]string, resources map[string]charmresource.Meta,
+) (map[string]string, error) {
+	m.MethodCall(m, "UploadResources", applicationID, curl, files, resources)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	pendingIDs := make(map[string]string)
+	for name := range files {
+		pendingIDs[name] = "pending-" + name
+	}
+	return pendingIDs, nil
+}
+
+func (m *mockUploadCharmAPI) PublishLocalCharm(curl *charm.URL, pendingIDs map[string]string) (*charm.URL, error) {
+	m.MethodCall(m, "PublishLocalCharm", curl, pendingIDs)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	if m.published > 0 {
+		return curl.WithRevision(m.published), nil
+	}
+	return curl, nil
+}
+
+func (m *mockUploadCharmAPI) ReleaseLocalCharm(curl *charm.URL, channel string) error {
+	m.MethodCall(m, "ReleaseLocalCharm", curl, channel)
+	return m.NextErr()
+}
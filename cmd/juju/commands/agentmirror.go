@@ -0,0 +1,350 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/os/series"
+	"github.com/juju/utils"
+	"github.com/juju/version"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/agentmirror"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/sync"
+	coretools "github.com/juju/juju/tools"
+)
+
+var (
+	findSourceTools = sync.FindSourceTools
+	fetchTools      = sync.FetchTools
+)
+
+const createAgentMirrorDoc = `
+Creates an agent mirror bundle: a single signed archive holding agent
+binaries and, optionally, cloud image metadata. The bundle can be carried
+into a site without Internet access and imported into a controller there
+with import-agent-mirror.
+
+Agent binaries are fetched from the official agent binaries store, or from
+the given --source, which may be a URL or a local directory. By default
+every series and architecture available for the chosen versions is included.
+
+Image metadata is read from a local directory of simplestreams image
+metadata, such as one generated by "juju metadata generate-image".
+
+The bundle's manifest is signed with the armored private key in the
+--signing-key file. The controller only accepts bundles signed by a key in
+its agent-mirror-keyring controller config.
+
+Examples:
+    juju create-agent-mirror --agent-version 2.8.1 --signing-key key.asc mirror.tar.gz
+    juju create-agent-mirror --agent-version 2.8.1,2.8.2 --series focal --arch amd64 \
+        --image-metadata-dir ~/images --signing-key key.asc mirror.tar.gz
+
+See also:
+    import-agent-mirror
+    sync-agent-binaries
+`
+
+func newCreateAgentMirrorCommand() cmd.Command {
+	return &createAgentMirrorCommand{}
+}
+
+// createAgentMirrorCommand writes a signed agent mirror bundle.
+type createAgentMirrorCommand struct {
+	cmd.CommandBase
+
+	out              string
+	versions         []version.Number
+	series           []string
+	arches           []string
+	stream           string
+	source           string
+	imageMetadataDir string
+	keyFile          string
+	passphrase       string
+}
+
+func (c *createAgentMirrorCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "create-agent-mirror",
+		Args:    "<file>",
+		Purpose: "Create a signed bundle of agent binaries for offline controllers.",
+		Doc:     createAgentMirrorDoc,
+	})
+}
+
+func (c *createAgentMirrorCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.Var(newVersionsValue(&c.versions), "agent-version", "Comma separated agent versions to include")
+	f.Var(cmd.NewStringsValue(nil, &c.series), "series", "Comma separated series to include (default all)")
+	f.Var(cmd.NewStringsValue(nil, &c.arches), "arch", "Comma separated architectures to include (default all)")
+	f.StringVar(&c.stream, "stream", "", "Simplestreams stream to fetch agent binaries from")
+	f.StringVar(&c.source, "source", "", "URL or local directory to fetch agent binaries from")
+	f.StringVar(&c.imageMetadataDir, "image-metadata-dir", "", "Local directory of image metadata to include")
+	f.StringVar(&c.keyFile, "signing-key", "", "File containing the armored private signing key")
+	f.StringVar(&c.passphrase, "passphrase", "", "Passphrase used to decrypt the private signing key")
+}
+
+func (c *createAgentMirrorCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no agent mirror file specified")
+	}
+	c.out, args = args[0], args[1:]
+	if len(c.versions) == 0 {
+		return errors.New("--agent-version is required")
+	}
+	if c.keyFile == "" {
+		return errors.New("--signing-key is required")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *createAgentMirrorCommand) Run(ctx *cmd.Context) error {
+	keyData, err := ioutil.ReadFile(ctx.AbsPath(c.keyFile))
+	if err != nil {
+		return errors.Annotate(err, "reading signing key")
+	}
+	bundle := agentmirror.Bundle{Created: time.Now()}
+	for _, vers := range c.versions {
+		list, err := findSourceTools(c.source, c.stream, vers)
+		if err != nil {
+			return errors.Annotatef(err, "finding agent binaries %s", vers)
+		}
+		list = c.filterTools(list)
+		if len(list) == 0 {
+			return errors.NotFoundf("agent binaries %s matching series and architecture", vers)
+		}
+		for _, tools := range list {
+			ctx.Infof("fetching agent binaries %s", tools.Version)
+			data, err := fetchTools(tools)
+			if err != nil {
+				return errors.Annotatef(err, "fetching agent binaries %s", tools.Version)
+			}
+			bundle.Agents = append(bundle.Agents, agentmirror.AgentBinary{
+				Version: tools.Version,
+				Data:    data,
+			})
+		}
+	}
+	if c.imageMetadataDir != "" {
+		if bundle.Images, err = c.readImageMetadata(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	f, err := os.Create(ctx.AbsPath(c.out))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	if err := agentmirror.Write(f, bundle, string(keyData), c.passphrase); err != nil {
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("created agent mirror %s with %d agent binaries and %d images", c.out, len(bundle.Agents), len(bundle.Images))
+	return nil
+}
+
+// filterTools returns the tools in the list matching the requested
+// series and architectures.
+func (c *createAgentMirrorCommand) filterTools(list coretools.List) coretools.List {
+	wantSeries, wantArches := set.NewStrings(c.series...), set.NewStrings(c.arches...)
+	var result coretools.List
+	for _, tools := range list {
+		if !wantSeries.IsEmpty() && !wantSeries.Contains(tools.Version.Series) {
+			continue
+		}
+		if !wantArches.IsEmpty() && !wantArches.Contains(tools.Version.Arch) {
+			continue
+		}
+		result = append(result, tools)
+	}
+	return result
+}
+
+// readImageMetadata reads the image metadata matching the requested
+// series and architectures from the local image metadata directory.
+func (c *createAgentMirrorCommand) readImageMetadata(ctx *cmd.Context) ([]agentmirror.ImageMetadata, error) {
+	dir := ctx.AbsPath(c.imageMetadataDir)
+	if filepath.Base(dir) != storage.BaseImagesPath {
+		if _, err := os.Stat(filepath.Join(dir, storage.BaseImagesPath)); err == nil {
+			dir = filepath.Join(dir, storage.BaseImagesPath)
+		}
+	}
+	publicKey, err := simplestreams.UserPublicSigningKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dataSourceConfig := simplestreams.Config{
+		Description:          "agent mirror image metadata",
+		BaseURL:              "file://" + filepath.ToSlash(dir),
+		PublicSigningKey:     publicKey,
+		HostnameVerification: utils.NoVerifySSLHostnames,
+		Priority:             simplestreams.CUSTOM_CLOUD_DATA,
+	}
+	if err := dataSourceConfig.Validate(); err != nil {
+		return nil, errors.Annotate(err, "simplestreams config validation failed")
+	}
+	imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
+		Series: c.series,
+		Arches: c.arches,
+	})
+	metadata, _, err := imagemetadata.Fetch(
+		[]simplestreams.DataSource{simplestreams.NewDataSource(dataSourceConfig)}, imageConstraint,
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading image metadata")
+	}
+	images := make([]agentmirror.ImageMetadata, len(metadata))
+	for i, one := range metadata {
+		imageSeries, err := series.VersionSeries(one.Version)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot determine series for version %v", one.Version)
+		}
+		images[i] = agentmirror.ImageMetadata{
+			ImageId:         one.Id,
+			Region:          one.RegionName,
+			Version:         one.Version,
+			Series:          imageSeries,
+			Arch:            one.Arch,
+			VirtType:        one.VirtType,
+			RootStorageType: one.Storage,
+			Stream:          one.Stream,
+		}
+	}
+	return images, nil
+}
+
+// versionsValue implements gnuflag.Value for a comma separated
+// list of version numbers.
+type versionsValue struct {
+	target *[]version.Number
+}
+
+func newVersionsValue(target *[]version.Number) *versionsValue {
+	return &versionsValue{target: target}
+}
+
+func (v *versionsValue) Set(s string) error {
+	var versions []version.Number
+	for _, vs := range strings.Split(s, ",") {
+		vers, err := version.Parse(strings.TrimSpace(vs))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		versions = append(versions, vers)
+	}
+	*v.target = versions
+	return nil
+}
+
+func (v *versionsValue) String() string {
+	vs := make([]string, len(*v.target))
+	for i, vers := range *v.target {
+		vs[i] = vers.String()
+	}
+	return strings.Join(vs, ",")
+}
+
+const importAgentMirrorDoc = `
+Imports an agent mirror bundle created with create-agent-mirror into the
+controller. The controller verifies the bundle's signature against the keys
+in its agent-mirror-keyring controller config, and checks every agent binary
+against the signed manifest; a bundle failing any check is rejected without
+importing anything.
+
+The imported agent binaries and image metadata are available to every
+model on the controller. Importing requires controller admin access.
+
+Examples:
+    juju controller-config agent-mirror-keyring="$(cat mirror-key.pub)"
+    juju import-agent-mirror mirror.tar.gz
+
+See also:
+    create-agent-mirror
+    upgrade-model
+`
+
+func newImportAgentMirrorCommand() cmd.Command {
+	return modelcmd.Wrap(&importAgentMirrorCommand{})
+}
+
+// importAgentMirrorCommand imports an agent mirror bundle into the
+// controller.
+type importAgentMirrorCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	file string
+}
+
+func (c *importAgentMirrorCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "import-agent-mirror",
+		Args:    "<file>",
+		Purpose: "Import a signed bundle of agent binaries into the controller.",
+		Doc:     importAgentMirrorDoc,
+	})
+}
+
+func (c *importAgentMirrorCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no agent mirror file specified")
+	}
+	c.file, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// agentMirrorAPI provides an interface with a subset of the
+// api.Client API. This exists to enable mocking.
+type agentMirrorAPI interface {
+	ImportAgentMirror(r io.ReadSeeker) (coretools.List, int, error)
+	Close() error
+}
+
+var getAgentMirrorAPI = func(c *importAgentMirrorCommand) (agentMirrorAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *importAgentMirrorCommand) Run(ctx *cmd.Context) error {
+	f, err := os.Open(ctx.AbsPath(c.file))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	client, err := getAgentMirrorAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	list, images, err := client.ImportAgentMirror(f)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	for _, tools := range list {
+		ctx.Infof("imported agent binaries %s", tools.Version)
+	}
+	ctx.Infof("imported %d agent binaries and %d images", len(list), images)
+	return nil
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/agentmirror"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/simplestreams"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type createAgentMirrorSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	dir     string
+	keyFile string
+}
+
+var _ = gc.Suite(&createAgentMirrorSuite{})
+
+func (s *createAgentMirrorSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.keyFile = filepath.Join(s.dir, "key.asc")
+	err := ioutil.WriteFile(s.keyFile, []byte(sstesting.SignedMetadataPrivateKey), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchValue(&findSourceTools, func(source, stream string, vers version.Number) (coretools.List, error) {
+		if vers != version.MustParse("2.8.1") {
+			return nil, errors.NotFoundf("agent binaries")
+		}
+		return coretools.List{
+			{Version: version.MustParseBinary("2.8.1-focal-amd64")},
+			{Version: version.MustParseBinary("2.8.1-focal-arm64")},
+			{Version: version.MustParseBinary("2.8.1-bionic-amd64")},
+		}, nil
+	})
+	s.PatchValue(&fetchTools, func(tools *coretools.Tools) ([]byte, error) {
+		return []byte("agent " + tools.Version.String()), nil
+	})
+}
+
+func (s *createAgentMirrorSuite) run(c *gc.C, args ...string) (string, error) {
+	args = append([]string{"--passphrase", sstesting.PrivateKeyPassphrase}, args...)
+	ctx, err := cmdtesting.RunCommand(c, newCreateAgentMirrorCommand(), args...)
+	return cmdtesting.Stderr(ctx), err
+}
+
+func (s *createAgentMirrorSuite) readBundle(c *gc.C, path string) *agentmirror.Bundle {
+	f, err := os.Open(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	b, err := agentmirror.Read(f, sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	return b
+}
+
+func (s *createAgentMirrorSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--agent-version", "2.8.1", "--signing-key", s.keyFile},
+		err:  "no agent mirror file specified",
+	}, {
+		args: []string{"--signing-key", s.keyFile, "out.tar.gz"},
+		err:  "--agent-version is required",
+	}, {
+		args: []string{"--agent-version", "2.8.1", "out.tar.gz"},
+		err:  "--signing-key is required",
+	}, {
+		args: []string{"--agent-version", "2.8.1,foo", "--signing-key", s.keyFile, "out.tar.gz"},
+		err:  `invalid value "2.8.1,foo" for option --agent-version: invalid version "foo"`,
+	}, {
+		args: []string{"--agent-version", "2.8.1", "--signing-key", s.keyFile, "out.tar.gz", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *createAgentMirrorSuite) TestCreate(c *gc.C) {
+	out := filepath.Join(s.dir, "mirror.tar.gz")
+	stderr, err := s.run(c, "--agent-version", "2.8.1", "--signing-key", s.keyFile, out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stderr, jc.Contains, "created agent mirror "+out+" with 3 agent binaries and 0 images")
+
+	b := s.readBundle(c, out)
+	c.Assert(b.Agents, gc.HasLen, 3)
+	for _, agent := range b.Agents {
+		c.Check(string(agent.Data), gc.Equals, "agent "+agent.Version.String())
+	}
+	c.Check(b.Images, gc.HasLen, 0)
+}
+
+func (s *createAgentMirrorSuite) TestCreateFiltered(c *gc.C) {
+	out := filepath.Join(s.dir, "mirror.tar.gz")
+	_, err := s.run(c, "--agent-version", "2.8.1", "--series", "focal", "--arch", "amd64", "--signing-key", s.keyFile, out)
+	c.Assert(err, jc.ErrorIsNil)
+
+	b := s.readBundle(c, out)
+	c.Assert(b.Agents, gc.HasLen, 1)
+	c.Check(b.Agents[0].Version, gc.Equals, version.MustParseBinary("2.8.1-focal-amd64"))
+}
+
+func (s *createAgentMirrorSuite) TestCreateNoMatchingTools(c *gc.C) {
+	out := filepath.Join(s.dir, "mirror.tar.gz")
+	_, err := s.run(c, "--agent-version", "2.8.1", "--series", "xenial", "--signing-key", s.keyFile, out)
+	c.Assert(err, gc.ErrorMatches, "agent binaries 2.8.1 matching series and architecture not found")
+	_, err = os.Stat(out)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *createAgentMirrorSuite) TestCreateUnknownVersion(c *gc.C) {
+	out := filepath.Join(s.dir, "mirror.tar.gz")
+	_, err := s.run(c, "--agent-version", "2.8.1,2.9.0", "--signing-key", s.keyFile, out)
+	c.Assert(err, gc.ErrorMatches, "finding agent binaries 2.9.0: agent binaries not found")
+}
+
+func (s *createAgentMirrorSuite) TestCreateWithImageMetadata(c *gc.C) {
+	imageDir := c.MkDir()
+	stor, err := filestorage.NewFileStorageWriter(imageDir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = imagemetadata.MergeAndWriteMetadata("focal", []*imagemetadata.ImageMetadata{{
+		Id:      "ami-1234",
+		Arch:    "amd64",
+		Version: "20.04",
+	}}, &simplestreams.CloudSpec{
+		Region:   "us-east-1",
+		Endpoint: "https://ec2.us-east-1.amazonaws.com",
+	}, stor)
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := filepath.Join(s.dir, "mirror.tar.gz")
+	stderr, err := s.run(c, "--agent-version", "2.8.1", "--series", "focal", "--arch", "amd64",
+		"--image-metadata-dir", imageDir, "--signing-key", s.keyFile, out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stderr, jc.Contains, "with 1 agent binaries and 1 images")
+
+	b := s.readBundle(c, out)
+	c.Assert(b.Images, jc.DeepEquals, []agentmirror.ImageMetadata{{
+		ImageId: "ami-1234",
+		Region:  "us-east-1",
+		Version: "20.04",
+		Series:  "focal",
+		Arch:    "amd64",
+	}})
+}
+
+type importAgentMirrorSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api   *fakeAgentMirrorAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&importAgentMirrorSuite{})
+
+func (s *importAgentMirrorSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeAgentMirrorAPI{}
+	s.PatchValue(&getAgentMirrorAPI, func(*importAgentMirrorCommand) (agentMirrorAPI, error) {
+		return s.api, nil
+	})
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "ctrl"
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{}
+	s.store.Models["ctrl"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{"admin/controller": {ModelType: "iaas"}}}
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+}
+
+func (s *importAgentMirrorSuite) run(c *gc.C, args ...string) (string, error) {
+	command := &importAgentMirrorCommand{}
+	command.SetClientStore(s.store)
+	ctx, err := cmdtesting.RunCommand(c, modelcmd.Wrap(command), append([]string{"-m", "controller"}, args...)...)
+	return cmdtesting.Stderr(ctx), err
+}
+
+func (s *importAgentMirrorSuite) TestInitErrors(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no agent mirror file specified")
+	_, err = s.run(c, "mirror.tar.gz", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *importAgentMirrorSuite) TestImport(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mirror.tar.gz")
+	err := ioutil.WriteFile(path, []byte("bundle"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.importAgentMirror = func(r io.ReadSeeker) (coretools.List, int, error) {
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, "bundle")
+		return coretools.List{
+			{Version: version.MustParseBinary("2.8.1-focal-amd64")},
+		}, 2, nil
+	}
+
+	stderr, err := s.run(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stderr, gc.Equals, `
+imported agent binaries 2.8.1-focal-amd64
+imported 1 agent binaries and 2 images
+`[1:])
+}
+
+func (s *importAgentMirrorSuite) TestImportRejected(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mirror.tar.gz")
+	err := ioutil.WriteFile(path, []byte("bundle"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.importAgentMirror = func(r io.ReadSeeker) (coretools.List, int, error) {
+		return nil, 0, errors.New("verifying agent mirror manifest: openpgp: signature made by unknown entity")
+	}
+
+	_, err = s.run(c, path)
+	c.Assert(err, gc.ErrorMatches, "verifying agent mirror manifest: openpgp: signature made by unknown entity")
+}
+
+func (s *importAgentMirrorSuite) TestImportMissingFile(c *gc.C) {
+	_, err := s.run(c, filepath.Join(c.MkDir(), "mirror.tar.gz"))
+	c.Assert(err, gc.ErrorMatches, "open .*mirror.tar.gz: no such file or directory")
+}
+
+type fakeAgentMirrorAPI struct {
+	importAgentMirror func(r io.ReadSeeker) (coretools.List, int, error)
+}
+
+func (f *fakeAgentMirrorAPI) ImportAgentMirror(r io.ReadSeeker) (coretools.List, int, error) {
+	return f.importAgentMirror(r)
+}
+
+func (f *fakeAgentMirrorAPI) Close() error {
+	return nil
+}
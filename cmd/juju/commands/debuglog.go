@@ -52,6 +52,12 @@ The '--include-module' and '--exclude-module' options filter by (dotted)
 logging module name. The module name can be truncated such that all loggers
 with the prefix will match.
 
+The '--include-workload' option, available for k8s models only, adds the
+logs of the workload containers of the model's units to the output. Each of
+their lines is logged against the unit, at INFO level, with a logging module
+of "workload.<container-name>". Reading the workload logs requires read
+access to the model.
+
 The filtering options combine as follows:
 * All --include options are logically ORed together.
 * All --exclude options are logically ORed together.
@@ -96,6 +102,10 @@ machine-3 or machine-4, and then stop:
         --exclude machine-3 \
         --exclude machine-4
 
+Include the workload container logs of k8s application gitlab-k8s:
+
+    juju debug-log --include gitlab-k8s --include-workload
+
 To see all WARNING and ERROR messages and then continue showing any
 new WARNING and ERROR messages as they are logged:
 
@@ -158,6 +168,7 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "Exit once this many of the most recent (possibly filtered) lines are shown")
 	f.BoolVar(&c.params.Replay, "replay", false, "Show the entire (possibly filtered) log and continue to append")
+	f.BoolVar(&c.params.IncludeWorkload, "include-workload", false, "Include the logs of workload containers (k8s models only)")
 
 	f.BoolVar(&c.notail, "no-tail", false, "Stop after returning existing log messages")
 	f.BoolVar(&c.tail, "tail", false, "Wait for new logs")
@@ -197,6 +208,9 @@ func (c *debugLogCommand) Init(args []string) error {
 		return errors.Trace(err)
 	}
 	isCaas := modelType == model.CAAS
+	if c.params.IncludeWorkload && !isCaas {
+		return errors.NotSupportedf("--include-workload on a non k8s model")
+	}
 	c.params.IncludeEntity = c.processEntities(isCaas, c.params.IncludeEntity)
 	c.params.ExcludeEntity = c.processEntities(isCaas, c.params.ExcludeEntity)
 	return cmd.CheckEmpty(args)
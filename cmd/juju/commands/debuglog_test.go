@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
 	"github.com/juju/juju/testing"
 )
@@ -104,6 +105,9 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args:     []string{"--include-workload"},
+			errMatch: `--include-workload on a non k8s model not supported`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -142,6 +146,29 @@ func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	})
 }
 
+func (s *DebugLogSuite) TestIncludeWorkloadPassed(c *gc.C) {
+	fake := &fakeDebugLogAPI{}
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return fake, nil
+	})
+	store := jujuclienttesting.MinimalStore()
+	details := store.Models["arthur"].Models["king/sword"]
+	details.ModelType = model.CAAS
+	store.Models["arthur"].Models["king/sword"] = details
+	_, err := cmdtesting.RunCommand(c, newDebugLogCommand(store),
+		"-i", "gitlab-k8s",
+		"--include-workload",
+		"--no-tail",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fake.params, gc.DeepEquals, common.DebugLogParams{
+		IncludeEntity:   []string{"application-gitlab-k8s"},
+		Backlog:         10,
+		NoTail:          true,
+		IncludeWorkload: true,
+	})
+}
+
 func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 	// test timezone is 6 hours east of UTC
 	tz := time.FixedZone("test", 6*60*60)
@@ -320,9 +320,13 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewModelGetConstraintsCommand())
 	r.Register(model.NewModelSetConstraintsCommand())
 	r.Register(newSyncToolsCommand())
+	r.Register(newCreateAgentMirrorCommand())
+	r.Register(newImportAgentMirrorCommand())
 	r.Register(newUpgradeJujuCommand())
 	r.Register(newUpgradeControllerCommand())
 	r.Register(application.NewUpgradeCharmCommand())
+	r.Register(application.NewUploadCharmCommand())
+	r.Register(application.NewRemoveUploadedCharmCommand())
 	r.Register(application.NewSetSeriesCommand())
 	r.Register(application.NewBindCommand())
 
@@ -358,6 +362,22 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddGroupCommand())
+	r.Register(user.NewRemoveGroupCommand())
+	r.Register(user.NewAddToGroupCommand())
+	r.Register(user.NewRemoveFromGroupCommand())
+	r.Register(user.NewListGroupsCommand())
+	r.Register(user.NewAddRoleCommand())
+	r.Register(user.NewRemoveRoleCommand())
+	r.Register(user.NewAssignRoleCommand())
+	r.Register(user.NewUnassignRoleCommand())
+	r.Register(user.NewListRolesCommand())
+	r.Register(user.NewAddAPITokenCommand())
+	r.Register(user.NewListAPITokensCommand())
+	r.Register(user.NewRevokeAPITokenCommand())
+	r.Register(user.NewEncryptStoreCommand())
+	r.Register(user.NewUnlockStoreCommand())
+	r.Register(user.NewLockStoreCommand())
 
 	// Manage cached images
 	r.Register(cachedimages.NewRemoveCommand())
@@ -369,6 +389,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
 	r.Register(machine.NewUpgradeSeriesCommand())
+	r.Register(machine.NewAddMachinePoolCommand())
+	r.Register(machine.NewMachinePoolsCommand())
+	r.Register(machine.NewSetMachinePoolSizeCommand())
+	r.Register(machine.NewRemoveMachinePoolCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -391,7 +415,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	}
 
 	r.Register(newMigrateCommand())
+	r.Register(newMigrateModelsCommand())
+	r.Register(newMigrationProgressCommand())
 	r.Register(model.NewExportBundleCommand())
+	r.Register(model.NewExportModelCommand())
+	r.Register(model.NewImportModelCommand())
 
 	if featureflag.Enabled(feature.DeveloperMode) {
 		r.Register(model.NewDumpCommand())
@@ -424,6 +452,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewUnexposeCommand())
 	r.Register(application.NewApplicationGetConstraintsCommand())
 	r.Register(application.NewApplicationSetConstraintsCommand())
+	r.Register(application.NewPlacementPolicyCommand())
+	r.Register(application.NewSetPlacementPolicyCommand())
 	r.Register(application.NewBundleDiffCommand())
 	r.Register(application.NewShowApplicationCommand())
 	r.Register(application.NewShowUnitCommand())
@@ -465,20 +465,27 @@ func assertNoArgs(c *gc.C, argChan <-chan []string) {
 
 var commandNames = []string{
 	"actions",
+	"add-api-token",
 	"add-cloud",
 	"add-credential",
+	"add-group",
 	"add-k8s",
 	"add-machine",
+	"add-machine-pool",
 	"add-model",
 	"add-relation",
+	"add-role",
 	"add-space",
 	"add-ssh-key",
 	"add-storage",
 	"add-subnet",
+	"add-to-group",
 	"add-unit",
 	"add-user",
 	"agree",
 	"agreements",
+	"api-tokens",
+	"assign-role",
 	"attach",
 	"attach-resource",
 	"attach-storage",
@@ -498,6 +505,7 @@ var commandNames = []string{
 	"consume",
 	"controller-config",
 	"controllers",
+	"create-agent-mirror",
 	"create-backup",
 	"create-storage-pool",
 	"create-wallet",
@@ -522,8 +530,10 @@ var commandNames = []string{
 	"enable-destroy-controller",
 	"enable-ha",
 	"enable-user",
+	"encrypt-store",
 	"exec",
 	"export-bundle",
+	"export-model",
 	"expose",
 	"find-offers",
 	"firewall-rules",
@@ -531,16 +541,20 @@ var commandNames = []string{
 	"get-model-constraints",
 	"grant",
 	"grant-cloud",
+	"groups",
 	"gui",
 	"help",
 	"help-tool",
 	"hook-tool",
 	"hook-tools",
+	"import-agent-mirror",
 	"import-filesystem",
+	"import-model",
 	"import-ssh-key",
 	"kill-controller",
 	"list-actions",
 	"list-agreements",
+	"list-api-tokens",
 	"list-backups",
 	"list-cached-images",
 	"list-charm-resources",
@@ -549,6 +563,8 @@ var commandNames = []string{
 	"list-credentials",
 	"list-disabled-commands",
 	"list-firewall-rules",
+	"list-groups",
+	"list-machine-pools",
 	"list-machines",
 	"list-models",
 	"list-offers",
@@ -556,6 +572,7 @@ var commandNames = []string{
 	"list-plans",
 	"list-regions",
 	"list-resources",
+	"list-roles",
 	"list-spaces",
 	"list-ssh-keys",
 	"list-storage",
@@ -563,11 +580,15 @@ var commandNames = []string{
 	"list-subnets",
 	"list-users",
 	"list-wallets",
+	"lock-store",
 	"login",
 	"logout",
+	"machine-pools",
 	"machines",
 	"metrics",
 	"migrate",
+	"migrate-models",
+	"migration-progress",
 	"model-config",
 	"model-default",
 	"model-defaults",
@@ -576,6 +597,7 @@ var commandNames = []string{
 	"offer",
 	"offers",
 	"payloads",
+	"placement-policy",
 	"plans",
 	"regions",
 	"register",
@@ -587,16 +609,21 @@ var commandNames = []string{
 	"remove-cloud",
 	"remove-consumed-application",
 	"remove-credential",
+	"remove-from-group",
+	"remove-group",
 	"remove-k8s",
 	"remove-machine",
+	"remove-machine-pool",
 	"remove-offer",
 	"remove-relation",
+	"remove-role",
 	"remove-saas",
 	"remove-space",
 	"remove-ssh-key",
 	"remove-storage",
 	"remove-storage-pool",
 	"remove-unit",
+	"remove-uploaded-charm",
 	"remove-user",
 	"rename-space",
 	"resolved",
@@ -606,7 +633,9 @@ var commandNames = []string{
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
+	"revoke-api-token",
 	"revoke-cloud",
+	"roles",
 	"run",
 	"scale-application",
 	"scp",
@@ -615,8 +644,10 @@ var commandNames = []string{
 	"set-default-credential",
 	"set-default-region",
 	"set-firewall-rule",
+	"set-machine-pool-size",
 	"set-meter-status",
 	"set-model-constraints",
+	"set-placement-policy",
 	"set-plan",
 	"set-series",
 	"set-wallet",
@@ -650,7 +681,9 @@ var commandNames = []string{
 	"sync-agent-binaries",
 	"sync-tools",
 	"trust",
+	"unassign-role",
 	"unexpose",
+	"unlock-store",
 	"unregister",
 	"update-cloud",
 	"update-k8s",
@@ -666,6 +699,7 @@ var commandNames = []string{
 	"upgrade-model",
 	"upgrade-series",
 	"upload-backup",
+	"upload-charm",
 	"users",
 	"version",
 	"wallets",
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
 	"gopkg.in/macaroon.v2"
@@ -20,6 +21,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -32,7 +35,9 @@ func newMigrateCommand() modelcmd.ModelCommand {
 // migrateCommand initiates a model migration.
 type migrateCommand struct {
 	modelcmd.ModelCommandBase
+	out              cmd.Output
 	targetController string
+	dryRun           bool
 
 	// Overridden by tests
 	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error)
@@ -43,6 +48,7 @@ type migrateCommand struct {
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	PrecheckMigration(spec controller.MigrationSpec) (source, target []coremigration.PrecheckFailure, err error)
 	IdentityProviderURL() (string, error)
 	Close() error
 }
@@ -80,6 +86,20 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+With --dry-run, the checks made on the source and target controllers
+before a migration starts are run and reported without migrating the
+model. Every failed check is listed, along with the machine, unit or
+application it applies to, so that problems can be fixed ahead of a
+migration window. The report is written in YAML by default; use
+--format=json for JSON. The command exits with an error status if any
+check failed.
+
+Examples:
+
+    juju migrate mymodel othercontroller
+    juju migrate --dry-run mymodel othercontroller
+    juju migrate --dry-run --format=json mymodel othercontroller
+
 See also:
     login
     controllers
@@ -96,6 +116,13 @@ func (c *migrateCommand) Info() *cmd.Info {
 	})
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Run the migration prechecks and report all failures without migrating the model")
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -131,6 +158,9 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	spec.ModelUUID = uuids[0]
+	if c.dryRun {
+		return c.precheckMigration(ctx, modelName, spec)
+	}
 	if err := c.checkMigrationFeasibility(spec); err != nil {
 		return errors.Trace(err)
 	}
@@ -151,15 +181,81 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// migrationPrecheckReport is the report written by migrate --dry-run.
+type migrationPrecheckReport struct {
+	Model            string                    `yaml:"model" json:"model"`
+	TargetController string                    `yaml:"target-controller" json:"target-controller"`
+	Ready            bool                      `yaml:"ready" json:"ready"`
+	Source           []migrationPrecheckResult `yaml:"source-failures,omitempty" json:"source-failures,omitempty"`
+	Target           []migrationPrecheckResult `yaml:"target-failures,omitempty" json:"target-failures,omitempty"`
+}
+
+type migrationPrecheckResult struct {
+	Entity  string `yaml:"entity" json:"entity"`
+	Message string `yaml:"message" json:"message"`
+}
+
+func toMigrationPrecheckResults(failures []coremigration.PrecheckFailure) []migrationPrecheckResult {
+	var out []migrationPrecheckResult
+	for _, failure := range failures {
+		out = append(out, migrationPrecheckResult{
+			Entity:  failure.Entity,
+			Message: failure.Message,
+		})
+	}
+	return out
+}
+
+// precheckMigration runs the migration prechecks on the source and target
+// controllers and writes out the report, without starting the migration.
+func (c *migrateCommand) precheckMigration(ctx *cmd.Context, modelName string, spec *controller.MigrationSpec) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return err
+	}
+	api, err := c.getMigrationAPI(controllerName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = api.Close() }()
+	source, target, err := api.PrecheckMigration(*spec)
+	if err != nil {
+		return errors.Annotate(err, "running migration prechecks")
+	}
+	report := migrationPrecheckReport{
+		Model:            modelName,
+		TargetController: c.targetController,
+		Ready:            len(source) == 0 && len(target) == 0,
+		Source:           toMigrationPrecheckResults(source),
+		Target:           toMigrationPrecheckResults(target),
+	}
+	if err := c.out.Write(ctx, report); err != nil {
+		return errors.Trace(err)
+	}
+	if !report.Ready {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
 func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
-	store := c.ClientStore()
+	return targetMigrationSpec(&c.CommandBase, c.ClientStore(), c.newAPIRoot, c.targetController)
+}
 
-	controllerInfo, err := store.ControllerByName(c.targetController)
+// targetMigrationSpec returns a migration spec holding the details,
+// taken from the client store, of the target controller.
+func targetMigrationSpec(
+	base *modelcmd.CommandBase,
+	store jujuclient.ClientStore,
+	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error),
+	targetController string,
+) (*controller.MigrationSpec, error) {
+	controllerInfo, err := store.ControllerByName(targetController)
 	if err != nil {
 		return nil, err
 	}
 
-	accountInfo, err := store.AccountDetails(c.targetController)
+	accountInfo, err := store.AccountDetails(targetController)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +263,7 @@ func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
 	var macs []macaroon.Slice
 	if accountInfo.Password == "" {
 		var err error
-		macs, err = c.getTargetControllerMacaroons()
+		macs, err = targetControllerMacaroons(base, store, newAPIRoot, targetController)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -175,7 +271,7 @@ func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
 
 	return &controller.MigrationSpec{
 		TargetControllerUUID:  controllerInfo.ControllerUUID,
-		TargetControllerAlias: c.targetController,
+		TargetControllerAlias: targetController,
 		TargetAddrs:           controllerInfo.APIEndpoints,
 		TargetCACert:          controllerInfo.CACert,
 		TargetUser:            accountInfo.User,
@@ -226,8 +322,13 @@ func (c *migrateCommand) getTargetControllerUserAPI() (userListAPI, error) {
 	return usermanager.NewClient(apiRoot), nil
 }
 
-func (c *migrateCommand) getTargetControllerMacaroons() ([]macaroon.Slice, error) {
-	jar, err := c.CommandBase.CookieJar(c.ClientStore(), c.targetController)
+func targetControllerMacaroons(
+	base *modelcmd.CommandBase,
+	store jujuclient.ClientStore,
+	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error),
+	targetController string,
+) ([]macaroon.Slice, error) {
+	jar, err := base.CookieJar(store, targetController)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -237,7 +338,7 @@ func (c *migrateCommand) getTargetControllerMacaroons() ([]macaroon.Slice, error
 	//
 	// TODO(axw,mjs) add a controller API that returns a macaroon that
 	// may be used for the sole purpose of migration.
-	api, err := c.newAPIRoot(c.ClientStore(), c.targetController, "")
+	api, err := newAPIRoot(store, targetController, "")
 	if err != nil {
 		return nil, errors.Annotate(err, "connecting to target controller")
 	}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -23,6 +24,7 @@ import (
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
@@ -262,6 +264,49 @@ func (s *MigrateSuite) TestSuccess(c *gc.C) {
 	})
 }
 
+func (s *MigrateSuite) TestDryRunReady(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+model: model
+target-controller: target
+ready: true
+`[1:])
+	c.Check(s.api.specSeen, jc.DeepEquals, &controller.MigrationSpec{
+		ModelUUID:             modelUUID,
+		TargetControllerUUID:  targetControllerUUID,
+		TargetControllerAlias: "target",
+		TargetAddrs:           []string{"1.2.3.4:5"},
+		TargetCACert:          "cert",
+		TargetUser:            "targetuser",
+		TargetPassword:        "secret",
+	})
+}
+
+func (s *MigrateSuite) TestDryRunFailures(c *gc.C) {
+	s.api.sourceFailures = []coremigration.PrecheckFailure{
+		{Entity: "machine 0", Message: "machine 0 not running (stopped)"},
+		{Entity: "unit mysql/0", Message: "unit mysql/0 not idle or executing (failed)"},
+	}
+	s.api.targetFailures = []coremigration.PrecheckFailure{
+		{Entity: "controller", Message: "model with same UUID already exists"},
+	}
+	ctx, err := s.makeAndRun(c, "--dry-run", "--format=json", "model", "target")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `{"model":"model","target-controller":"target","ready":false,`+
+		`"source-failures":[{"entity":"machine 0","message":"machine 0 not running (stopped)"},`+
+		`{"entity":"unit mysql/0","message":"unit mysql/0 not idle or executing (failed)"}],`+
+		`"target-failures":[{"entity":"controller","message":"model with same UUID already exists"}]}`+"\n")
+}
+
+func (s *MigrateSuite) TestDryRunError(c *gc.C) {
+	s.api.precheckErr = errors.New("boom")
+	_, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Check(err, gc.ErrorMatches, "running migration prechecks: boom")
+}
+
 func (s *MigrateSuite) TestSuccessMacaroons(c *gc.C) {
 	err := s.store.UpdateAccount("target", jujuclient.AccountDetails{
 		User:     "targetuser",
@@ -440,8 +485,11 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 }
 
 type fakeMigrateAPI struct {
-	specSeen    *controller.MigrationSpec
-	identityURL string
+	specSeen       *controller.MigrationSpec
+	identityURL    string
+	sourceFailures []coremigration.PrecheckFailure
+	targetFailures []coremigration.PrecheckFailure
+	precheckErr    error
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -449,6 +497,11 @@ func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (strin
 	return "uuid:0", nil
 }
 
+func (a *fakeMigrateAPI) PrecheckMigration(spec controller.MigrationSpec) ([]coremigration.PrecheckFailure, []coremigration.PrecheckFailure, error) {
+	a.specSeen = &spec
+	return a.sourceFailures, a.targetFailures, a.precheckErr
+}
+
 func (a *fakeMigrateAPI) IdentityProviderURL() (string, error) {
 	return a.identityURL, nil
 }
@@ -0,0 +1,374 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/jujuclient"
+)
+
+// bulkMigrateAPI defines the controller API methods used by the
+// migrate-models and migration-progress commands.
+type bulkMigrateAPI interface {
+	InitiateBulkMigration(spec controller.BulkMigrationSpec) (string, []string, error)
+	BulkMigrationStatus(ids ...string) ([]params.BulkMigrationStatus, error)
+	Close() error
+}
+
+func newMigrateModelsCommand() modelcmd.ControllerCommand {
+	var cmd migrateModelsCommand
+	cmd.newAPIRoot = cmd.CommandBase.NewAPIRoot
+	return modelcmd.WrapController(&cmd)
+}
+
+// migrateModelsCommand initiates the migration of a number of models.
+type migrateModelsCommand struct {
+	modelcmd.ControllerCommandBase
+	targetController string
+	models           []string
+	owner            string
+	maxConcurrent    int
+
+	// Overridden by tests
+	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error)
+	api        bulkMigrateAPI
+}
+
+const migrateModelsDoc = `
+migrate-models begins the migration of a number of models from the
+current controller to the target controller. The models are either
+named on the command line, or are all the models owned by the user
+given with --owner. The controller model can not be migrated.
+
+The current controller starts the migrations itself, running no more
+than --max-concurrent of them at the same time, and starting the next
+queued migration as each one completes or aborts. A model whose
+migration fails is returned to its original state, and the remaining
+models continue to be migrated.
+
+This command only requests the migrations - it does not wait for them
+to complete. The progress of each model can be tracked using the
+"migration-progress" command with the ID printed by this command.
+
+As with the "migrate" command, the target controller must be in the
+juju client's local configuration cache. The checks made before a
+single migration can be run ahead of time using "migrate --dry-run".
+
+Examples:
+
+    juju migrate-models othercontroller mymodel bob/othermodel
+    juju migrate-models --owner bob --max-concurrent 4 othercontroller
+
+See also:
+    migrate
+    migration-progress
+`
+
+// Info implements cmd.Command.
+func (c *migrateModelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "migrate-models",
+		Args:    "<target-controller-name> [<model-name> ...]",
+		Purpose: "Migrate a number of hosted models to another controller.",
+		Doc:     migrateModelsDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *migrateModelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.owner, "owner", "", "Migrate all the models owned by this user")
+	f.IntVar(&c.maxConcurrent, "max-concurrent", 1, "The maximum number of migrations to run at the same time")
+}
+
+// Init implements cmd.Command.
+func (c *migrateModelsCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("target controller not specified")
+	}
+	c.targetController, c.models = args[0], args[1:]
+	if len(c.models) == 0 && c.owner == "" {
+		return errors.New("no models or --owner specified")
+	}
+	if len(c.models) > 0 && c.owner != "" {
+		return errors.New("specify either models or --owner, not both")
+	}
+	if c.owner != "" && !names.IsValidUser(c.owner) {
+		return errors.NotValidf("owner %q", c.owner)
+	}
+	if c.maxConcurrent < 1 {
+		return errors.Errorf("--max-concurrent must be at least 1, got %d", c.maxConcurrent)
+	}
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *migrateModelsCommand) Run(ctx *cmd.Context) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if controllerName == c.targetController {
+		return errors.New("models can't be migrated to the controller they're on")
+	}
+	target, err := targetMigrationSpec(&c.CommandBase, c.ClientStore(), c.newAPIRoot, c.targetController)
+	if err != nil {
+		return err
+	}
+	spec := controller.BulkMigrationSpec{
+		Owner:                 c.owner,
+		MaxConcurrent:         c.maxConcurrent,
+		TargetControllerUUID:  target.TargetControllerUUID,
+		TargetControllerAlias: target.TargetControllerAlias,
+		TargetAddrs:           target.TargetAddrs,
+		TargetCACert:          target.TargetCACert,
+		TargetUser:            target.TargetUser,
+		TargetPassword:        target.TargetPassword,
+		TargetMacaroons:       target.TargetMacaroons,
+	}
+	if len(c.models) > 0 {
+		if spec.ModelUUIDs, err = c.CommandBase.ModelUUIDs(c.ClientStore(), controllerName, c.models); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	client, err := c.getAPI(controllerName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	id, modelUUIDs, err := client.InitiateBulkMigration(spec)
+	if err != nil {
+		return err
+	}
+	ctx.Infof("Migration of %d models started with ID %q", len(modelUUIDs), id)
+	ctx.Infof("Use \"juju migration-progress %s\" to track its progress.", id)
+	return nil
+}
+
+func (c *migrateModelsCommand) getAPI(controllerName string) (bulkMigrateAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	apiRoot, err := c.newAPIRoot(c.ClientStore(), controllerName, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return controller.NewClient(apiRoot), nil
+}
+
+func newMigrationProgressCommand() modelcmd.ControllerCommand {
+	return modelcmd.WrapController(&migrationProgressCommand{})
+}
+
+// migrationProgressCommand shows the progress of bulk migrations.
+type migrationProgressCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+	ids []string
+
+	// Overridden by tests
+	api bulkMigrateAPI
+}
+
+const migrationProgressDoc = `
+migration-progress shows the progress of the model migrations started
+on the current controller by "migrate-models". For each model, the
+phase of its migration is shown, or "queued" if its migration has not
+been started yet. The number of models queued, being migrated, migrated
+and failed is summarised, and each failure is listed with its reason.
+
+With no ID, the progress of all such migrations is shown.
+
+Examples:
+
+    juju migration-progress
+    juju migration-progress 3
+    juju migration-progress 3 --format=yaml
+
+See also:
+    migrate-models
+`
+
+// Info implements cmd.Command.
+func (c *migrationProgressCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "migration-progress",
+		Args:    "[<migration-id> ...]",
+		Purpose: "Show the progress of migrations started by migrate-models.",
+		Doc:     migrationProgressDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *migrationProgressCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMigrationProgressTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *migrationProgressCommand) Init(args []string) error {
+	c.ids = args
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *migrationProgressCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	statuses, err := client.BulkMigrationStatus(c.ids...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(statuses) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No model migrations have been started with migrate-models.")
+		return nil
+	}
+	progress := make([]bulkMigrationProgress, len(statuses))
+	for i, status := range statuses {
+		progress[i] = toBulkMigrationProgress(status)
+	}
+	return c.out.Write(ctx, progress)
+}
+
+func (c *migrationProgressCommand) getAPI() (bulkMigrateAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	apiRoot, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return controller.NewClient(apiRoot), nil
+}
+
+// The states reported for a model that isn't being migrated.
+const (
+	modelMigrationQueued       = "queued"
+	modelMigrationStartFailure = "failed to start"
+)
+
+// bulkMigrationProgress is the progress of a bulk migration written by
+// the migration-progress command.
+type bulkMigrationProgress struct {
+	Id               string                   `yaml:"id" json:"id"`
+	InitiatedBy      string                   `yaml:"initiated-by" json:"initiated-by"`
+	Started          time.Time                `yaml:"started" json:"started"`
+	TargetController string                   `yaml:"target-controller" json:"target-controller"`
+	MaxConcurrent    int                      `yaml:"max-concurrent" json:"max-concurrent"`
+	Summary          bulkMigrationSummary     `yaml:"summary" json:"summary"`
+	Models           []modelMigrationProgress `yaml:"models" json:"models"`
+	Failures         map[string]string        `yaml:"failures,omitempty" json:"failures,omitempty"`
+}
+
+type bulkMigrationSummary struct {
+	Queued  int `yaml:"queued" json:"queued"`
+	Running int `yaml:"running" json:"running"`
+	Done    int `yaml:"done" json:"done"`
+	Failed  int `yaml:"failed" json:"failed"`
+}
+
+type modelMigrationProgress struct {
+	Model       string `yaml:"model" json:"model"`
+	Phase       string `yaml:"phase" json:"phase"`
+	MigrationId string `yaml:"migration-id,omitempty" json:"migration-id,omitempty"`
+	Message     string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+func toBulkMigrationProgress(status params.BulkMigrationStatus) bulkMigrationProgress {
+	progress := bulkMigrationProgress{
+		Id:               status.Id,
+		InitiatedBy:      status.InitiatedBy,
+		Started:          status.StartTime,
+		TargetController: status.TargetController,
+		MaxConcurrent:    status.MaxConcurrent,
+	}
+	for _, m := range status.Models {
+		name := m.ModelName
+		if owner, err := names.ParseUserTag(m.OwnerTag); err == nil {
+			name = jujuclient.JoinOwnerModelName(owner, m.ModelName)
+		}
+		model := modelMigrationProgress{
+			Model:       name,
+			Phase:       m.Phase,
+			MigrationId: m.MigrationId,
+			Message:     m.StatusMessage,
+		}
+		phase, _ := coremigration.ParsePhase(m.Phase)
+		failed := false
+		switch {
+		case m.StartError != "":
+			model.Phase = modelMigrationStartFailure
+			model.Message = m.StartError
+			failed = true
+		case m.MigrationId == "":
+			model.Phase = modelMigrationQueued
+			progress.Summary.Queued++
+		case phase == coremigration.DONE:
+			progress.Summary.Done++
+		case phase == coremigration.ABORT, phase == coremigration.ABORTDONE, phase == coremigration.REAPFAILED:
+			failed = true
+		default:
+			progress.Summary.Running++
+		}
+		if failed {
+			progress.Summary.Failed++
+			if progress.Failures == nil {
+				progress.Failures = make(map[string]string)
+			}
+			progress.Failures[name] = model.Message
+		}
+		progress.Models = append(progress.Models, model)
+	}
+	return progress
+}
+
+func formatMigrationProgressTabular(writer io.Writer, value interface{}) error {
+	progress, ok := value.([]bulkMigrationProgress)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", progress, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	for i, p := range progress {
+		if i > 0 {
+			w.Println()
+		}
+		w.Println("ID", "Target", "Started", "Max concurrent", "Queued", "Running", "Done", "Failed")
+		w.Println(p.Id, p.TargetController, p.Started.Format(time.RFC3339), p.MaxConcurrent,
+			p.Summary.Queued, p.Summary.Running, p.Summary.Done, p.Summary.Failed)
+		w.Println()
+		w.Println("Model", "Phase", "Message")
+		for _, m := range p.Models {
+			w.Println(m.Model, m.Phase, m.Message)
+		}
+		if len(p.Failures) > 0 {
+			w.Println()
+			w.Println(fmt.Sprintf("%d of %d models failed to migrate.", p.Summary.Failed, len(p.Models)))
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,305 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type MigrateModelsSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api      *fakeBulkMigrateAPI
+	modelAPI *fakeModelAPI
+	store    *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&MigrateModelsSuite{})
+
+func (s *MigrateModelsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+
+	s.store = jujuclient.NewMemStore()
+	err := s.store.AddController("source", jujuclient.ControllerDetails{
+		ControllerUUID: "eeeeeeee-0bad-400d-8000-4b1d0d06f00d",
+		CACert:         "somecert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.SetCurrentController("source")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("source", jujuclient.AccountDetails{
+		User: "sourceuser",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.AddController("target", jujuclient.ControllerDetails{
+		ControllerUUID: targetControllerUUID,
+		APIEndpoints:   []string{"1.2.3.4:5"},
+		CACert:         "cert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("target", jujuclient.AccountDetails{
+		User:     "targetuser",
+		Password: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.api = &fakeBulkMigrateAPI{}
+	s.modelAPI = &fakeModelAPI{
+		models: []base.UserModel{{
+			Name:  "model",
+			UUID:  modelUUID,
+			Type:  model.IAAS,
+			Owner: "sourceuser",
+		}, {
+			Name:  "production",
+			UUID:  "beefdeed-0bad-400d-8000-4b1d0d06f00d",
+			Type:  model.IAAS,
+			Owner: "alpha",
+		}},
+	}
+}
+
+func (s *MigrateModelsSuite) makeMigrateModelsCommand() cmd.Command {
+	cmd := newMigrateModelsCommand()
+	cmd.SetClientStore(s.store)
+	cmd.SetModelAPI(s.modelAPI)
+	inner := modelcmd.InnerCommand(cmd).(*migrateModelsCommand)
+	inner.api = s.api
+	inner.newAPIRoot = func(jujuclient.ClientStore, string, string) (api.Connection, error) {
+		return nil, errors.New("unexpected connection")
+	}
+	return cmd
+}
+
+func (s *MigrateModelsSuite) makeMigrationProgressCommand() cmd.Command {
+	cmd := newMigrationProgressCommand()
+	cmd.SetClientStore(s.store)
+	inner := modelcmd.InnerCommand(cmd).(*migrationProgressCommand)
+	inner.api = s.api
+	return cmd
+}
+
+func (s *MigrateModelsSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "target controller not specified",
+	}, {
+		args: []string{"target"},
+		err:  "no models or --owner specified",
+	}, {
+		args: []string{"--owner", "bob", "target", "model"},
+		err:  "specify either models or --owner, not both",
+	}, {
+		args: []string{"--owner", "bob!", "target"},
+		err:  `owner "bob!" not valid`,
+	}, {
+		args: []string{"--max-concurrent", "0", "target", "model"},
+		err:  "--max-concurrent must be at least 1, got 0",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := cmdtesting.RunCommand(c, s.makeMigrateModelsCommand(), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MigrateModelsSuite) TestMigrateModels(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.makeMigrateModelsCommand(),
+		"--max-concurrent", "2", "target", "model", "alpha/production")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+Migration of 2 models started with ID "0"
+Use "juju migration-progress 0" to track its progress.
+`[1:])
+	c.Check(s.api.specSeen, jc.DeepEquals, &controller.BulkMigrationSpec{
+		ModelUUIDs:            []string{modelUUID, "beefdeed-0bad-400d-8000-4b1d0d06f00d"},
+		MaxConcurrent:         2,
+		TargetControllerUUID:  targetControllerUUID,
+		TargetControllerAlias: "target",
+		TargetAddrs:           []string{"1.2.3.4:5"},
+		TargetCACert:          "cert",
+		TargetUser:            "targetuser",
+		TargetPassword:        "secret",
+	})
+}
+
+func (s *MigrateModelsSuite) TestMigrateModelsByOwner(c *gc.C) {
+	s.api.modelUUIDs = []string{modelUUID}
+	_, err := cmdtesting.RunCommand(c, s.makeMigrateModelsCommand(), "--owner", "sourceuser", "target")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.api.specSeen.Owner, gc.Equals, "sourceuser")
+	c.Check(s.api.specSeen.ModelUUIDs, gc.HasLen, 0)
+	c.Check(s.api.specSeen.MaxConcurrent, gc.Equals, 1)
+}
+
+func (s *MigrateModelsSuite) TestMigrateModelsToSameController(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.makeMigrateModelsCommand(), "source", "model")
+	c.Assert(err, gc.ErrorMatches, "models can't be migrated to the controller they're on")
+	c.Check(s.api.specSeen, gc.IsNil)
+}
+
+func (s *MigrateModelsSuite) TestMigrateModelsError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, s.makeMigrateModelsCommand(), "target", "model")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *MigrateModelsSuite) setStatus() {
+	s.api.statuses = []params.BulkMigrationStatus{{
+		Id:               "0",
+		InitiatedBy:      "admin",
+		StartTime:        time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+		MaxConcurrent:    1,
+		TargetController: "target",
+		Models: []params.BulkMigrationModelStatus{{
+			ModelTag:    "model-" + modelUUID,
+			ModelName:   "done",
+			OwnerTag:    "user-bob",
+			MigrationId: modelUUID + ":0",
+			Phase:       "DONE",
+		}, {
+			ModelTag:      "model-" + modelUUID,
+			ModelName:     "running",
+			OwnerTag:      "user-bob",
+			MigrationId:   modelUUID + ":1",
+			Phase:         "IMPORT",
+			StatusMessage: "importing",
+		}, {
+			ModelTag:      "model-" + modelUUID,
+			ModelName:     "aborted",
+			OwnerTag:      "user-bob",
+			MigrationId:   modelUUID + ":2",
+			Phase:         "ABORTDONE",
+			StatusMessage: "aborted, removing model from target controller: machine 0 not running",
+		}, {
+			ModelTag:   "model-" + modelUUID,
+			ModelName:  "broken",
+			OwnerTag:   "user-bob",
+			StartError: "model is being destroyed",
+		}, {
+			ModelTag:  "model-" + modelUUID,
+			ModelName: "waiting",
+			OwnerTag:  "user-bob",
+		}},
+	}}
+}
+
+func (s *MigrateModelsSuite) TestMigrationProgressTabular(c *gc.C) {
+	s.setStatus()
+	ctx, err := cmdtesting.RunCommand(c, s.makeMigrationProgressCommand(), "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.api.idsSeen, jc.DeepEquals, []string{"0"})
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+ID  Target  Started               Max concurrent  Queued  Running  Done  Failed
+0   target  2020-06-01T10:00:00Z  1               1       1        1     2
+
+Model        Phase            Message
+bob/done     DONE             
+bob/running  IMPORT           importing
+bob/aborted  ABORTDONE        aborted, removing model from target controller: machine 0 not running
+bob/broken   failed to start  model is being destroyed
+bob/waiting  queued           
+
+2 of 5 models failed to migrate.
+
+`[1:])
+}
+
+func (s *MigrateModelsSuite) TestMigrationProgressYAML(c *gc.C) {
+	s.setStatus()
+	ctx, err := cmdtesting.RunCommand(c, s.makeMigrationProgressCommand(), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.api.idsSeen, gc.HasLen, 0)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: "0"
+  initiated-by: admin
+  started: 2020-06-01T10:00:00Z
+  target-controller: target
+  max-concurrent: 1
+  summary:
+    queued: 1
+    running: 1
+    done: 1
+    failed: 2
+  models:
+  - model: bob/done
+    phase: DONE
+    migration-id: deadbeef-0bad-400d-8000-4b1d0d06f00d:0
+  - model: bob/running
+    phase: IMPORT
+    migration-id: deadbeef-0bad-400d-8000-4b1d0d06f00d:1
+    message: importing
+  - model: bob/aborted
+    phase: ABORTDONE
+    migration-id: deadbeef-0bad-400d-8000-4b1d0d06f00d:2
+    message: 'aborted, removing model from target controller: machine 0 not running'
+  - model: bob/broken
+    phase: failed to start
+    message: model is being destroyed
+  - model: bob/waiting
+    phase: queued
+  failures:
+    bob/aborted: 'aborted, removing model from target controller: machine 0 not running'
+    bob/broken: model is being destroyed
+`[1:])
+}
+
+func (s *MigrateModelsSuite) TestMigrationProgressNone(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.makeMigrationProgressCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "No model migrations have been started with migrate-models.\n")
+}
+
+func (s *MigrateModelsSuite) TestMigrationProgressError(c *gc.C) {
+	s.api.err = errors.NotFoundf(`bulk migration "42"`)
+	_, err := cmdtesting.RunCommand(c, s.makeMigrationProgressCommand(), "42")
+	c.Check(err, gc.ErrorMatches, `bulk migration "42" not found`)
+}
+
+type fakeBulkMigrateAPI struct {
+	specSeen   *controller.BulkMigrationSpec
+	modelUUIDs []string
+	idsSeen    []string
+	statuses   []params.BulkMigrationStatus
+	err        error
+}
+
+func (a *fakeBulkMigrateAPI) InitiateBulkMigration(spec controller.BulkMigrationSpec) (string, []string, error) {
+	a.specSeen = &spec
+	if a.err != nil {
+		return "", nil, a.err
+	}
+	modelUUIDs := a.modelUUIDs
+	if modelUUIDs == nil {
+		modelUUIDs = spec.ModelUUIDs
+	}
+	return "0", modelUUIDs, nil
+}
+
+func (a *fakeBulkMigrateAPI) BulkMigrationStatus(ids ...string) ([]params.BulkMigrationStatus, error) {
+	a.idsSeen = ids
+	return a.statuses, a.err
+}
+
+func (*fakeBulkMigrateAPI) Close() error {
+	return nil
+}
@@ -51,9 +51,14 @@ specified:
 The command will abort if an upgrade is in progress. It will also abort if
 a previous upgrade was not fully completed (e.g.: if one of the
 controllers in a high availability model failed to upgrade).
+With --dry-run, the checks made before an upgrade starts are run, and the
+upgrade steps that each controller agent would run are listed, without
+upgrading anything. The report is written in YAML by default; use
+--format=json for JSON.
 
 Examples:
     juju upgrade-controller --dry-run
+    juju upgrade-controller --dry-run --format=json
     juju upgrade-controller --agent-version 2.0.1
     
 See also: 
@@ -261,7 +266,7 @@ func (c *upgradeControllerCommand) upgradeCAASController(ctx *cmd.Context) error
 	if c.DryRun {
 		c.upgradeMessage = "upgrade to this version by running\n    juju upgrade-controller"
 		fmt.Fprintf(ctx.Stderr, "%s\n", c.upgradeMessage)
-		return nil
+		return c.previewUpgrade(ctx, client, context.chosen)
 	}
 	return c.notifyControllerUpgrade(ctx, client, context)
 }
@@ -326,7 +331,7 @@ func (c *upgradeControllerCommand) upgradeIAASController(ctx *cmd.Context, contr
 		args = append(args, "--build-agent")
 	}
 	if c.DryRun {
-		args = append(args, "--dry-run")
+		args = append(args, "--dry-run", "--format", c.out.Name())
 	}
 	if c.IgnoreAgentVersions {
 		args = append(args, "--ignore-agent-versions")
@@ -27,6 +27,7 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/environs"
@@ -61,17 +62,32 @@ the software to the controller's cache via the ` + "`juju sync-agent-binaries`"
 The command will abort if an upgrade is in progress. It will also abort if
 a previous upgrade was not fully completed (e.g.: if one of the
 controllers in a high availability model failed to upgrade).
+With --dry-run, the checks made before an upgrade starts are run, and the
+upgrade steps that each machine and unit agent would run are listed,
+without upgrading anything. The report is written in YAML by default; use
+--format=json for JSON. Only upgrade steps known to the controller's
+current version are listed; when upgrading to a later version the report
+says that its steps are unknown, rather than listing none.
 When looking for an agent to upgrade to Juju will check the currently
 configured agent stream for that model. It's possible to overwrite this for
 the lifetime of this upgrade using --agent-stream
 If a failed upgrade has been resolved, '--reset-previous-upgrade' can be
 used to allow the upgrade to proceed.
+Large models can be upgraded in stages. With --machines, only the agents of
+the given machines, and of the units they host, are upgraded; the rest of
+the model stays on its current version, and ` + "`juju status`" + ` reports the
+mixed versions as an upgrade in progress. Once the upgraded machines have
+been verified, run upgrade-model again, either with --machines to upgrade
+the next group, or without it to upgrade the rest of the model to the same
+version. Controller machines can't be upgraded in stages.
 Backups are recommended prior to upgrading.
 
 Examples:
     juju upgrade-model --dry-run
+    juju upgrade-model --dry-run --format=json
     juju upgrade-model --agent-version 2.0.1
     juju upgrade-model --agent-stream proposed
+    juju upgrade-model --agent-version 2.9.1 --machines 0,3
     
 See also: 
     sync-agent-binaries`
@@ -131,6 +147,8 @@ type baseUpgradeCommand struct {
 
 	modelConfigAPI modelConfigAPI
 	controllerAPI  controllerAPI
+
+	out cmd.Output
 }
 
 func (u *baseUpgradeCommand) SetFlags(f *gnuflag.FlagSet) {
@@ -143,6 +161,7 @@ func (u *baseUpgradeCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&u.AssumeYes, "yes", false, "")
 	f.BoolVar(&u.IgnoreAgentVersions, "ignore-agent-versions", false,
 		"Don't check if all agents have already reached the current version")
+	u.out.AddFlags(f, "yaml", output.DefaultFormatters)
 }
 
 func (c *baseUpgradeCommand) Init(args []string) error {
@@ -243,6 +262,9 @@ type upgradeJujuCommand struct {
 	baseUpgradeCommand
 
 	jujuClientAPI jujuClientAPI
+
+	machines     []names.MachineTag
+	machinesFlag string
 }
 
 func (c *upgradeJujuCommand) Info() *cmd.Info {
@@ -257,6 +279,23 @@ func (c *upgradeJujuCommand) Info() *cmd.Info {
 func (c *upgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	c.baseUpgradeCommand.SetFlags(f)
+	f.StringVar(&c.machinesFlag, "machines", "", "Only upgrade the agents of these machines (comma separated)")
+}
+
+func (c *upgradeJujuCommand) Init(args []string) error {
+	if c.machinesFlag != "" {
+		if c.ResetPrevious {
+			return errors.New("--machines cannot be used with --reset-previous-upgrade")
+		}
+		for _, id := range strings.Split(c.machinesFlag, ",") {
+			id = strings.TrimSpace(id)
+			if !names.IsValidMachine(id) {
+				return errors.NotValidf("machine %q", id)
+			}
+			c.machines = append(c.machines, names.NewMachineTag(id))
+		}
+	}
+	return c.baseUpgradeCommand.Init(args)
 }
 
 var (
@@ -310,6 +349,8 @@ type toolsAPI interface {
 type upgradeJujuAPI interface {
 	AbortCurrentUpgrade() error
 	SetModelAgentVersion(version version.Number, ignoreAgentVersion bool) error
+	UpgradePreview(version version.Number, ignoreAgentVersions bool) (params.UpgradePreview, error)
+	StageAgentVersion(version version.Number, machines ...names.MachineTag) ([]params.ErrorResult, error)
 	Close() error
 }
 
@@ -518,8 +559,38 @@ func (c *upgradeJujuCommand) upgradeModel(ctx *cmd.Context, implicitUploadAllowe
 		} else {
 			fmt.Fprintf(ctx.Stderr, "%s\n", c.upgradeMessage)
 		}
-	} else {
-		return c.notifyControllerUpgrade(ctx, client, upgradeCtx)
+		return c.previewUpgrade(ctx, client, upgradeCtx.chosen)
+	}
+	if len(c.machines) > 0 {
+		return c.stageUpgrade(ctx, client, upgradeCtx.chosen)
+	}
+	return c.notifyControllerUpgrade(ctx, client, upgradeCtx)
+}
+
+// stageUpgrade upgrades the agents of the selected machines to the given
+// version, leaving the rest of the model on its current version.
+func (c *upgradeJujuCommand) stageUpgrade(ctx *cmd.Context, client upgradeJujuAPI, vers version.Number) error {
+	results, err := client.StageAgentVersion(vers, c.machines...)
+	if errors.IsNotSupported(err) {
+		return errors.Errorf("%v by this controller, upgrade the controller first", err)
+	} else if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	var staged []string
+	failed := false
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Infof("cannot upgrade machine %s: %v", c.machines[i].Id(), result.Error)
+			failed = true
+			continue
+		}
+		staged = append(staged, c.machines[i].Id())
+	}
+	if len(staged) > 0 {
+		fmt.Fprintf(ctx.Stdout, "started upgrade to %s on machines %s\n", vers, strings.Join(staged, ","))
+	}
+	if failed {
+		return cmd.ErrSilent
 	}
 	return nil
 }
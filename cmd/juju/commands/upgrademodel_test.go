@@ -19,6 +19,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
+	"github.com/juju/names/v4"
 	"github.com/juju/os/series"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -113,6 +114,16 @@ var upgradeJujuTests = []upgradeTest{{
 	currentVersion: "1.0.0-quantal-amd64",
 	args:           []string{"--dev"},
 	expectInitErr:  "option provided but not defined: --dev",
+}, {
+	about:          "invalid --machines value",
+	currentVersion: "1.0.0-quantal-amd64",
+	args:           []string{"--machines", "0,foo"},
+	expectInitErr:  `machine "foo" not valid`,
+}, {
+	about:          "--machines with --reset-previous-upgrade",
+	currentVersion: "1.0.0-quantal-amd64",
+	args:           []string{"--machines", "0", "--reset-previous-upgrade"},
+	expectInitErr:  "--machines cannot be used with --reset-previous-upgrade",
 }, {
 	about:          "invalid --agent-version value",
 	currentVersion: "1.0.0-quantal-amd64",
@@ -990,6 +1001,38 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestStagedUpgrade(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+
+	command := s.upgradeJujuCommand(nil, fakeAPI, fakeAPI, fakeAPI)
+	ctx, err := cmdtesting.RunCommand(c, command, "--machines", "0,3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(fakeAPI.stageCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.stageMachines, jc.DeepEquals, []names.MachineTag{
+		names.NewMachineTag("0"), names.NewMachineTag("3"),
+	})
+	// The model agent version is left alone.
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		fmt.Sprintf("started upgrade to %s on machines 0,3\n", fakeAPI.nextVersion.Number))
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgradeMachineErrors(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.stageResults = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "machine 3 not found"}},
+	}
+
+	command := s.upgradeJujuCommand(nil, fakeAPI, fakeAPI, fakeAPI)
+	ctx, err := cmdtesting.RunCommand(c, command, "--machines", "0,3")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		fmt.Sprintf("started upgrade to %s on machines 0\n", fakeAPI.nextVersion.Number))
+	c.Assert(cmdtesting.Stderr(ctx), jc.Contains, "cannot upgrade machine 3: machine 3 not found\n")
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Binary{
 		Number: jujuversion.Current,
@@ -1020,6 +1063,9 @@ type fakeUpgradeJujuAPI struct {
 	setIgnoreCalledWith       bool
 	tools                     []string
 	findToolsCalled           bool
+	stageCalledWith           version.Number
+	stageMachines             []names.MachineTag
+	stageResults              []params.ErrorResult
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
@@ -1029,6 +1075,9 @@ func (a *fakeUpgradeJujuAPI) reset() {
 	a.setIgnoreCalledWith = false
 	a.tools = []string{}
 	a.findToolsCalled = false
+	a.stageCalledWith = version.Number{}
+	a.stageMachines = nil
+	a.stageResults = nil
 }
 
 func (a *fakeUpgradeJujuAPI) ControllerConfig() (controller.Config, error) {
@@ -1085,6 +1134,19 @@ func (a *fakeUpgradeJujuAPI) SetModelAgentVersion(v version.Number, ignoreAgentV
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) UpgradePreview(v version.Number, ignoreAgentVersions bool) (params.UpgradePreview, error) {
+	return params.UpgradePreview{}, errors.NotSupportedf("upgrade preview")
+}
+
+func (a *fakeUpgradeJujuAPI) StageAgentVersion(v version.Number, machines ...names.MachineTag) ([]params.ErrorResult, error) {
+	a.stageCalledWith = v
+	a.stageMachines = machines
+	if a.stageResults != nil {
+		return a.stageResults, nil
+	}
+	return make([]params.ErrorResult, len(machines)), nil
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
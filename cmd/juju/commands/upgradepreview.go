@@ -0,0 +1,104 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// upgradePreviewReport is the report written by upgrade-model and
+// upgrade-controller with --dry-run.
+type upgradePreviewReport struct {
+	CurrentVersion string                         `yaml:"current-version" json:"current-version"`
+	TargetVersion  string                         `yaml:"target-version" json:"target-version"`
+	KnownVersion   string                         `yaml:"known-steps-version" json:"known-steps-version"`
+	StepsUnknown   bool                           `yaml:"later-steps-unknown,omitempty" json:"later-steps-unknown,omitempty"`
+	Ready          bool                           `yaml:"ready" json:"ready"`
+	Prechecks      []upgradePrecheckResult        `yaml:"prechecks" json:"prechecks"`
+	Agents         map[string]agentUpgradePreview `yaml:"agents,omitempty" json:"agents,omitempty"`
+}
+
+type upgradePrecheckResult struct {
+	Name    string `yaml:"name" json:"name"`
+	Status  string `yaml:"status" json:"status"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+type agentUpgradePreview struct {
+	Version string               `yaml:"version" json:"version"`
+	Steps   []upgradeStepPreview `yaml:"steps,omitempty" json:"steps,omitempty"`
+}
+
+type upgradeStepPreview struct {
+	Version     string   `yaml:"version" json:"version"`
+	Description string   `yaml:"description" json:"description"`
+	Targets     []string `yaml:"targets" json:"targets"`
+	State       bool     `yaml:"state,omitempty" json:"state,omitempty"`
+}
+
+func toUpgradePreviewReport(preview params.UpgradePreview) upgradePreviewReport {
+	report := upgradePreviewReport{
+		CurrentVersion: preview.CurrentVersion.String(),
+		TargetVersion:  preview.TargetVersion.String(),
+		KnownVersion:   preview.KnownVersion.String(),
+		StepsUnknown:   preview.TargetVersion.Compare(preview.KnownVersion) > 0,
+		Ready:          true,
+	}
+	for _, check := range preview.Prechecks {
+		result := upgradePrecheckResult{
+			Name:   check.Name,
+			Status: "passed",
+		}
+		if check.Error != nil {
+			result.Status = "failed"
+			result.Message = check.Error.Error()
+			report.Ready = false
+		}
+		report.Prechecks = append(report.Prechecks, result)
+	}
+	for _, agent := range preview.Agents {
+		if report.Agents == nil {
+			report.Agents = make(map[string]agentUpgradePreview)
+		}
+		agentPreview := agentUpgradePreview{
+			Version: agent.Version.String(),
+		}
+		for _, step := range agent.Steps {
+			agentPreview.Steps = append(agentPreview.Steps, upgradeStepPreview{
+				Version:     step.Version.String(),
+				Description: step.Description,
+				Targets:     step.Targets,
+				State:       step.State,
+			})
+		}
+		report.Agents[agent.Tag] = agentPreview
+	}
+	return report
+}
+
+// previewUpgrade writes out the prechecks and upgrade steps that
+// upgrading the model to the given version would run. Only the steps
+// known to the controller's version are listed; KnownVersion in the
+// report says which version that is, and a warning is written when the
+// target version is later, as its steps can't be listed. Controllers
+// which can't preview an upgrade are reported with a warning only.
+func (c *baseUpgradeCommand) previewUpgrade(ctx *cmd.Context, client upgradeJujuAPI, vers version.Number) error {
+	preview, err := client.UpgradePreview(vers, c.IgnoreAgentVersions)
+	if errors.IsNotSupported(err) {
+		ctx.Warningf("upgrade steps not previewed: %v by this controller", err)
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "previewing upgrade")
+	}
+	report := toUpgradePreviewReport(preview)
+	if report.StepsUnknown {
+		ctx.Warningf("upgrade steps added after %s are not listed: this controller does not know the steps for %s",
+			preview.KnownVersion, preview.TargetVersion)
+	}
+	return errors.Trace(c.out.Write(ctx, report))
+}
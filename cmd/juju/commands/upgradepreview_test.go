@@ -0,0 +1,166 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type upgradePreviewSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&upgradePreviewSuite{})
+
+var samplePreview = params.UpgradePreview{
+	CurrentVersion: version.MustParse("2.7.6"),
+	TargetVersion:  version.MustParse("2.8.0"),
+	KnownVersion:   version.MustParse("2.8.0"),
+	Prechecks: []params.UpgradePrecheck{{
+		Name: "provider-api",
+	}, {
+		Name:  "agent-versions",
+		Error: &params.Error{Message: "some agents have not upgraded to the current model version 2.7.6: unit-mysql-0"},
+	}},
+	Agents: []params.AgentUpgradePreview{{
+		Tag:     "machine-0",
+		Version: version.MustParse("2.7.6"),
+		Steps: []params.UpgradeStepPreview{{
+			Version:     version.MustParse("2.8.0"),
+			Description: "add charm-hub-url to model config",
+			Targets:     []string{"database-master"},
+			State:       true,
+		}, {
+			Version:     version.MustParse("2.8.0"),
+			Description: "remove unused log file",
+			Targets:     []string{"all-machines"},
+		}},
+	}, {
+		Tag:     "unit-mysql-0",
+		Version: version.MustParse("2.7.5"),
+	}},
+}
+
+func (s *upgradePreviewSuite) runPreview(c *gc.C, client upgradeJujuAPI, args ...string) (*cmd.Context, error) {
+	var command baseUpgradeCommand
+	f := gnuflag.NewFlagSetWithFlagKnownAs("upgrade-model", gnuflag.ContinueOnError, "option")
+	command.SetFlags(f)
+	c.Assert(f.Parse(true, args), jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	err := command.previewUpgrade(ctx, client, version.MustParse("2.8.0"))
+	return ctx, err
+}
+
+func (s *upgradePreviewSuite) TestPreviewUpgrade(c *gc.C) {
+	client := &fakeUpgradePreviewAPI{preview: samplePreview}
+	ctx, err := s.runPreview(c, client, "--ignore-agent-versions")
+	c.Assert(err, jc.ErrorIsNil)
+	client.CheckCall(c, 0, "UpgradePreview", version.MustParse("2.8.0"), true)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+current-version: 2.7.6
+target-version: 2.8.0
+known-steps-version: 2.8.0
+ready: false
+prechecks:
+- name: provider-api
+  status: passed
+- name: agent-versions
+  status: failed
+  message: 'some agents have not upgraded to the current model version 2.7.6: unit-mysql-0'
+agents:
+  machine-0:
+    version: 2.7.6
+    steps:
+    - version: 2.8.0
+      description: add charm-hub-url to model config
+      targets:
+      - database-master
+      state: true
+    - version: 2.8.0
+      description: remove unused log file
+      targets:
+      - all-machines
+  unit-mysql-0:
+    version: 2.7.5
+`[1:])
+}
+
+func (s *upgradePreviewSuite) TestPreviewUpgradeJSON(c *gc.C) {
+	preview := params.UpgradePreview{
+		CurrentVersion: version.MustParse("2.7.6"),
+		TargetVersion:  version.MustParse("2.8.0"),
+		KnownVersion:   version.MustParse("2.7.6"),
+		Prechecks:      []params.UpgradePrecheck{{Name: "provider-api"}},
+	}
+	client := &fakeUpgradePreviewAPI{preview: preview}
+	ctx, err := s.runPreview(c, client, "--format=json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"current-version":"2.7.6","target-version":"2.8.0",`+
+		`"known-steps-version":"2.7.6","later-steps-unknown":true,"ready":true,`+
+		`"prechecks":[{"name":"provider-api","status":"passed"}]}`+"\n")
+}
+
+func (s *upgradePreviewSuite) TestPreviewUpgradeLaterStepsUnknown(c *gc.C) {
+	preview := params.UpgradePreview{
+		CurrentVersion: version.MustParse("2.7.6"),
+		TargetVersion:  version.MustParse("2.8.0"),
+		KnownVersion:   version.MustParse("2.7.6"),
+		Agents: []params.AgentUpgradePreview{{
+			Tag:     "machine-0",
+			Version: version.MustParse("2.7.6"),
+		}},
+	}
+	client := &fakeUpgradePreviewAPI{preview: preview}
+	ctx, err := s.runPreview(c, client)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+current-version: 2.7.6
+target-version: 2.8.0
+known-steps-version: 2.7.6
+later-steps-unknown: true
+ready: true
+prechecks: []
+agents:
+  machine-0:
+    version: 2.7.6
+`[1:])
+	c.Assert(c.GetTestLog(), jc.Contains,
+		"upgrade steps added after 2.7.6 are not listed: this controller does not know the steps for 2.8.0")
+}
+
+func (s *upgradePreviewSuite) TestPreviewUpgradeNotSupported(c *gc.C) {
+	client := &fakeUpgradePreviewAPI{}
+	client.SetErrors(errors.NotSupportedf("upgrade preview"))
+	ctx, err := s.runPreview(c, client)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(c.GetTestLog(), jc.Contains, "upgrade steps not previewed: upgrade preview not supported by this controller")
+}
+
+func (s *upgradePreviewSuite) TestPreviewUpgradeError(c *gc.C) {
+	client := &fakeUpgradePreviewAPI{}
+	client.SetErrors(errors.New("boom"))
+	_, err := s.runPreview(c, client)
+	c.Assert(err, gc.ErrorMatches, "previewing upgrade: boom")
+}
+
+type fakeUpgradePreviewAPI struct {
+	upgradeJujuAPI
+	testing.Stub
+	preview params.UpgradePreview
+}
+
+func (a *fakeUpgradePreviewAPI) UpgradePreview(v version.Number, ignoreAgentVersions bool) (params.UpgradePreview, error) {
+	a.AddCall("UpgradePreview", v, ignoreAgentVersions)
+	return a.preview, a.NextErr()
+}
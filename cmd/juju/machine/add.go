@@ -5,6 +5,7 @@ package machine
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 	"time"
@@ -87,6 +88,9 @@ as machines in the model.
 
 It is also possible to add containers to existing machines using the format
 <container-type>:<machine-id>. Constraints cannot be combined this mode.
+The format <container-type>:zone=<zone> adds a container on a new machine
+instance in the given availability zone; for the LXD provider, zones are
+the members of the LXD cluster.
 
 
 Examples:
@@ -107,6 +111,10 @@ Examples:
 	# Create a container on machine 4 and add it as a machine.
 	juju add-machine lxd:4
 
+	# Start a LXD container on a new machine instance on the LXD cluster
+	# member "node02", and add both as machines.
+	juju add-machine lxd:zone=node02
+
 	# Start a new machine and require that it has 8GB RAM
 	juju add-machine --constraints mem=8G
 
@@ -119,6 +127,11 @@ Examples:
 	# are storage pools specific to AWS. 
 	juju add-machine --constraints="cores=4 mem=16G" --disks="ebs,1T,2 ebs-ssd,100G,1"
 
+	# Start a new machine with cloud-init user data read from a YAML file,
+	# merged with that of the model and of the applications later deployed
+	# to the machine.
+	juju add-machine --cloudinit-userdata ./userdata.yaml
+
 	# Allocate a machine to the model via SSH
 	juju add-machine ssh:user@10.10.0.3
 
@@ -162,6 +175,9 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// CloudInitUserDataFile is the path of a YAML file holding cloud-init
+	// user data for the machine.
+	CloudInitUserDataFile string
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -179,6 +195,7 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Machine constraints that overwrite those available from 'juju get-model-constraints' and provider's defaults")
 	f.Var(disksFlag{&c.Disks}, "disks", "Storage constraints for disks to attach to the machine(s)")
+	f.StringVar(&c.CloudInitUserDataFile, "cloudinit-userdata", "", "Path to a YAML file of cloud-init user data for the machine(s)")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -200,6 +217,10 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return errors.New("cannot use -n when specifying a placement directive")
 	}
+	if c.CloudInitUserDataFile != "" && c.Placement != nil &&
+		(c.Placement.Scope == sshScope || c.Placement.Scope == winrmScope) {
+		return errors.New("cannot use --cloudinit-userdata when provisioning a machine manually")
+	}
 	return nil
 }
 
@@ -288,6 +309,21 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return errors.New("cannot add machines with disks: not supported by the API server")
 	}
 
+	var cloudInitUserData string
+	if c.CloudInitUserDataFile != "" {
+		if machineManager.BestAPIVersion() < 8 {
+			return errors.New("cannot add machines with cloud-init user data: not supported by the API server")
+		}
+		data, err := ioutil.ReadFile(ctx.AbsPath(c.CloudInitUserDataFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cloudInitUserData = string(data)
+		if _, err := config.ParseCloudInitUserData(cloudInitUserData); err != nil {
+			return errors.Annotatef(err, "invalid cloud-init user data in %q", c.CloudInitUserDataFile)
+		}
+	}
+
 	logger.Infof("load config")
 	modelConfigClient, err := c.getModelConfigAPI()
 	if err != nil {
@@ -330,11 +366,12 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 	jobs := []model.MachineJob{model.JobHostUnits}
 
 	machineParams := params.AddMachineParams{
-		Placement:   c.Placement,
-		Series:      c.Series,
-		Constraints: c.Constraints,
-		Jobs:        jobs,
-		Disks:       c.Disks,
+		Placement:         c.Placement,
+		Series:            c.Series,
+		Constraints:       c.Constraints,
+		Jobs:              jobs,
+		Disks:             c.Disks,
+		CloudInitUserData: cloudInitUserData,
 	}
 	machines := make([]params.AddMachineParams, c.NumMachines)
 	for i := 0; i < c.NumMachines; i++ {
@@ -4,6 +4,8 @@
 package machine_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -67,6 +69,10 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"lxd:4"},
 			count:     1,
 			placement: "lxd:4",
+		}, {
+			args:      []string{"lxd:zone=node02"},
+			count:     1,
+			placement: "lxd:zone=node02",
 		}, {
 			args:      []string{"ssh:user@10.10.0.3"},
 			count:     1,
@@ -90,6 +96,9 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"something:special"},
 			count:     1,
 			placement: "something:special",
+		}, {
+			args:        []string{"ssh:user@10.10.0.3", "--cloudinit-userdata", "userdata.yaml"},
+			errorString: "cannot use --cloudinit-userdata when provisioning a machine manually",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -211,6 +220,37 @@ func (s *AddMachineSuite) TestAddMachineWithDisksUnsupported(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot add machines with disks: not supported by the API server")
 }
 
+func (s *AddMachineSuite) writeUserData(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "userdata.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *AddMachineSuite) TestAddMachineWithCloudInitUserData(c *gc.C) {
+	s.fakeMachineManager.apiVersion = 8
+	path := s.writeUserData(c, "packages: [htop]\n")
+	_, err := s.run(c, "--cloudinit-userdata", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeMachineManager.args, gc.HasLen, 1)
+	c.Assert(s.fakeMachineManager.args[0].CloudInitUserData, gc.Equals, "packages: [htop]\n")
+}
+
+func (s *AddMachineSuite) TestAddMachineWithInvalidCloudInitUserData(c *gc.C) {
+	s.fakeMachineManager.apiVersion = 8
+	path := s.writeUserData(c, "runcmd: [ls]\n")
+	_, err := s.run(c, "--cloudinit-userdata", path)
+	c.Assert(err, gc.ErrorMatches, `invalid cloud-init user data in ".*userdata.yaml": runcmd not allowed, use preruncmd or postruncmd instead`)
+	c.Assert(s.fakeMachineManager.args, gc.HasLen, 0)
+}
+
+func (s *AddMachineSuite) TestAddMachineWithCloudInitUserDataUnsupported(c *gc.C) {
+	s.fakeMachineManager.apiVersion = 7
+	path := s.writeUserData(c, "packages: [htop]\n")
+	_, err := s.run(c, "--cloudinit-userdata", path)
+	c.Assert(err, gc.ErrorMatches, "cannot add machines with cloud-init user data: not supported by the API server")
+}
+
 type fakeAddMachineAPI struct {
 	successOrder     []bool
 	currentOp        int
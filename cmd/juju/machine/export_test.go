@@ -73,6 +73,42 @@ func NewUpgradeSeriesCommandForTest(upgradeAPI UpgradeMachineSeriesAPI) cmd.Comm
 	return modelcmd.Wrap(command)
 }
 
+// NewAddMachinePoolCommandForTest returns an add-machine-pool command
+// with the api provided as specified.
+func NewAddMachinePoolCommandForTest(api MachinePoolAPI) cmd.Command {
+	command := &addMachinePoolCommand{}
+	command.api = api
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+// NewMachinePoolsCommandForTest returns a machine-pools command with
+// the api provided as specified.
+func NewMachinePoolsCommandForTest(api MachinePoolAPI) cmd.Command {
+	command := &machinePoolsCommand{}
+	command.api = api
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+// NewSetMachinePoolSizeCommandForTest returns a set-machine-pool-size
+// command with the api provided as specified.
+func NewSetMachinePoolSizeCommandForTest(api MachinePoolAPI) cmd.Command {
+	command := &setMachinePoolSizeCommand{}
+	command.api = api
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+// NewRemoveMachinePoolCommandForTest returns a remove-machine-pool
+// command with the api provided as specified.
+func NewRemoveMachinePoolCommandForTest(api MachinePoolAPI) cmd.Command {
+	command := &removeMachinePoolCommand{}
+	command.api = api
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -0,0 +1,400 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/constraints"
+)
+
+const addMachinePoolDoc = `
+A machine pool is a named group of machines that Juju keeps at a target
+size. The machines of a pool are started with the pool's series and
+constraints, and are spread across the pool's availability zones.
+
+When the provider reports that the instance of a pool machine no longer
+exists, Juju starts a replacement machine. Units of applications with the
+"stateless" application setting enabled (juju config <application>
+stateless=true) are replaced by new units on the replacement machine, in
+a container of the same type if they were in one; units of other
+applications are left for the operator to recover.
+
+Examples:
+
+    juju add-machine-pool workers -n 3
+    juju add-machine-pool workers -n 6 --zones us-east-1a,us-east-1b --constraints mem=8G
+
+See also:
+    machine-pools
+    set-machine-pool-size
+    remove-machine-pool
+`
+
+// MachinePoolAPI defines the API methods used by the machine pool
+// commands.
+type MachinePoolAPI interface {
+	AddMachinePool(params.MachinePool) error
+	MachinePools() ([]params.MachinePool, error)
+	SetMachinePoolSize(string, int) error
+	RemoveMachinePool(string) error
+	Close() error
+}
+
+type baseMachinePoolCommand struct {
+	baseMachinesCommand
+	api MachinePoolAPI
+}
+
+func (c *baseMachinePoolCommand) getAPI() (MachinePoolAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func parseMachinePoolName(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, errors.New("no machine pool name specified")
+	}
+	return args[0], args[1:], nil
+}
+
+// NewAddMachinePoolCommand returns a command that adds a machine pool
+// to a model.
+func NewAddMachinePoolCommand() cmd.Command {
+	return modelcmd.Wrap(&addMachinePoolCommand{})
+}
+
+// addMachinePoolCommand adds a machine pool to a model.
+type addMachinePoolCommand struct {
+	baseMachinePoolCommand
+	Name           string
+	Series         string
+	ConstraintsStr string
+	ZonesStr       string
+	Size           int
+}
+
+// Info implements Command.Info.
+func (c *addMachinePoolCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-machine-pool",
+		Args:    "<name>",
+		Purpose: "Add a pool of machines kept at a target size.",
+		Doc:     addMachinePoolDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addMachinePoolCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baseMachinePoolCommand.SetFlags(f)
+	f.StringVar(&c.Series, "series", "", "The operating system series to install on the pool's machines")
+	f.IntVar(&c.Size, "n", 1, "The number of machines to keep in the pool")
+	f.StringVar(&c.ConstraintsStr, "constraints", "", "Constraints for the pool's machines")
+	f.StringVar(&c.ZonesStr, "zones", "", "Comma separated availability zones to spread the pool's machines across")
+}
+
+// Init implements Command.Init.
+func (c *addMachinePoolCommand) Init(args []string) error {
+	var err error
+	if c.Name, args, err = parseMachinePoolName(args); err != nil {
+		return err
+	}
+	if err := cmd.CheckEmpty(args); err != nil {
+		return err
+	}
+	if c.Size < 0 {
+		return errors.Errorf("machine pool size must not be negative")
+	}
+	if _, err := constraints.Parse(c.ConstraintsStr); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addMachinePoolCommand) Run(ctx *cmd.Context) error {
+	cons, err := constraints.Parse(c.ConstraintsStr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var zones []string
+	if c.ZonesStr != "" {
+		zones = strings.Split(c.ZonesStr, ",")
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.AddMachinePool(params.MachinePool{
+		Name:        c.Name,
+		Series:      c.Series,
+		Constraints: cons,
+		Zones:       zones,
+		TargetSize:  c.Size,
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("added machine pool %q", c.Name)
+	return nil
+}
+
+const machinePoolsDoc = `
+Lists the machine pools in the model, with their target sizes and the
+machines currently in them.
+
+Examples:
+
+    juju machine-pools
+    juju machine-pools --format yaml
+
+See also:
+    add-machine-pool
+`
+
+// NewMachinePoolsCommand returns a command that lists the machine
+// pools in a model.
+func NewMachinePoolsCommand() cmd.Command {
+	return modelcmd.Wrap(&machinePoolsCommand{})
+}
+
+// machinePoolsCommand lists the machine pools in a model.
+type machinePoolsCommand struct {
+	baseMachinePoolCommand
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *machinePoolsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "machine-pools",
+		Purpose: "Lists the machine pools in a model.",
+		Doc:     machinePoolsDoc,
+		Aliases: []string{"list-machine-pools"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *machinePoolsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baseMachinePoolCommand.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMachinePoolsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *machinePoolsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// MachinePoolInfo is the serialised form of a machine pool.
+type MachinePoolInfo struct {
+	Series      string   `yaml:"series" json:"series"`
+	Constraints string   `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Zones       []string `yaml:"zones,omitempty" json:"zones,omitempty"`
+	TargetSize  int      `yaml:"target-size" json:"target-size"`
+	Machines    []string `yaml:"machines,omitempty" json:"machines,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *machinePoolsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	pools, err := client.MachinePools()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make(map[string]MachinePoolInfo, len(pools))
+	for _, p := range pools {
+		result[p.Name] = MachinePoolInfo{
+			Series:      p.Series,
+			Constraints: p.Constraints.String(),
+			Zones:       p.Zones,
+			TargetSize:  p.TargetSize,
+			Machines:    p.Machines,
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatMachinePoolsTabular(writer io.Writer, value interface{}) error {
+	pools, ok := value.(map[string]MachinePoolInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", pools, value)
+	}
+	if len(pools) == 0 {
+		fmt.Fprintln(writer, "No machine pools to display.")
+		return nil
+	}
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Pool", "Series", "Size", "Machines", "Zones", "Constraints")
+	for _, name := range names {
+		p := pools[name]
+		w.Println(
+			name,
+			p.Series,
+			fmt.Sprintf("%d/%d", len(p.Machines), p.TargetSize),
+			strings.Join(p.Machines, ","),
+			strings.Join(p.Zones, ","),
+			p.Constraints,
+		)
+	}
+	return tw.Flush()
+}
+
+const setMachinePoolSizeDoc = `
+Sets the number of machines a machine pool is kept at. Machines are
+started to grow the pool; to shrink it, the most recently added machines
+that host no units or containers are removed.
+
+Examples:
+
+    juju set-machine-pool-size workers 5
+
+See also:
+    add-machine-pool
+    machine-pools
+`
+
+// NewSetMachinePoolSizeCommand returns a command that sets the target
+// size of a machine pool.
+func NewSetMachinePoolSizeCommand() cmd.Command {
+	return modelcmd.Wrap(&setMachinePoolSizeCommand{})
+}
+
+// setMachinePoolSizeCommand sets the target size of a machine pool.
+type setMachinePoolSizeCommand struct {
+	baseMachinePoolCommand
+	Name string
+	Size int
+}
+
+// Info implements Command.Info.
+func (c *setMachinePoolSizeCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-machine-pool-size",
+		Args:    "<name> <size>",
+		Purpose: "Sets the number of machines a machine pool is kept at.",
+		Doc:     setMachinePoolSizeDoc,
+	})
+}
+
+// Init implements Command.Init.
+func (c *setMachinePoolSizeCommand) Init(args []string) error {
+	var err error
+	if c.Name, args, err = parseMachinePoolName(args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("no machine pool size specified")
+	}
+	if c.Size, err = strconv.Atoi(args[0]); err != nil || c.Size < 0 {
+		return errors.NotValidf("machine pool size %q", args[0])
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *setMachinePoolSizeCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.SetMachinePoolSize(c.Name, c.Size)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+const removeMachinePoolDoc = `
+Removes a machine pool. The machines of the pool are left in the model,
+and are no longer replaced when lost; remove them with ` + "`juju remove-machine`" + `
+if they are no longer needed.
+
+Examples:
+
+    juju remove-machine-pool workers
+
+See also:
+    add-machine-pool
+    remove-machine
+`
+
+// NewRemoveMachinePoolCommand returns a command that removes a machine
+// pool from a model.
+func NewRemoveMachinePoolCommand() cmd.Command {
+	return modelcmd.Wrap(&removeMachinePoolCommand{})
+}
+
+// removeMachinePoolCommand removes a machine pool from a model.
+type removeMachinePoolCommand struct {
+	baseMachinePoolCommand
+	Name string
+}
+
+// Info implements Command.Info.
+func (c *removeMachinePoolCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-machine-pool",
+		Args:    "<name>",
+		Purpose: "Removes a machine pool, leaving its machines in place.",
+		Doc:     removeMachinePoolDoc,
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeMachinePoolCommand) Init(args []string) error {
+	var err error
+	if c.Name, args, err = parseMachinePoolName(args); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *removeMachinePoolCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.RemoveMachinePool(c.Name)
+	return block.ProcessBlockedError(err, block.BlockRemove)
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type MachinePoolSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeMachinePoolAPI
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeMachinePoolAPI{}
+}
+
+func (s *MachinePoolSuite) TestAddInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no machine pool name specified`,
+	}, {
+		args: []string{"workers", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"workers", "-n", "-1"},
+		err:  `machine pool size must not be negative`,
+	}, {
+		args: []string{"workers", "--constraints", "mem=lots"},
+		err:  `bad "mem" constraint: .*`,
+	}, {
+		args: []string{"workers", "-n", "3", "--zones", "a,b"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(machine.NewAddMachinePoolCommandForTest(s.api), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *MachinePoolSuite) TestAdd(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewAddMachinePoolCommandForTest(s.api),
+		"workers", "-n", "3", "--series", "focal", "--zones", "a,b", "--constraints", "mem=4G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "added machine pool \"workers\"\n")
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"AddMachinePool", []interface{}{params.MachinePool{
+			Name:        "workers",
+			Series:      "focal",
+			Constraints: constraints.MustParse("mem=4G"),
+			Zones:       []string{"a", "b"},
+			TargetSize:  3,
+		}}},
+		{"Close", nil},
+	})
+}
+
+func (s *MachinePoolSuite) TestAddError(c *gc.C) {
+	s.api.SetErrors(errors.AlreadyExistsf("machine pool %q", "workers"))
+	_, err := cmdtesting.RunCommand(c, machine.NewAddMachinePoolCommandForTest(s.api), "workers")
+	c.Assert(err, gc.ErrorMatches, `machine pool "workers" already exists`)
+}
+
+func (s *MachinePoolSuite) TestListTabular(c *gc.C) {
+	s.api.pools = []params.MachinePool{{
+		Name:        "workers",
+		Series:      "focal",
+		Constraints: constraints.MustParse("mem=4G"),
+		Zones:       []string{"a", "b"},
+		TargetSize:  3,
+		Machines:    []string{"1", "2"},
+	}, {
+		Name:       "batch",
+		Series:     "bionic",
+		TargetSize: 0,
+	}}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewMachinePoolsCommandForTest(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Pool     Series  Size  Machines  Zones  Constraints
+batch    bionic  0/0                    
+workers  focal   2/3   1,2       a,b    mem=4096M
+
+`[1:])
+}
+
+func (s *MachinePoolSuite) TestListYAML(c *gc.C) {
+	s.api.pools = []params.MachinePool{{
+		Name:       "workers",
+		Series:     "focal",
+		TargetSize: 1,
+		Machines:   []string{"4"},
+	}}
+	ctx, err := cmdtesting.RunCommand(c, machine.NewMachinePoolsCommandForTest(s.api), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+workers:
+  series: focal
+  target-size: 1
+  machines:
+  - "4"
+`[1:])
+}
+
+func (s *MachinePoolSuite) TestListEmpty(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewMachinePoolsCommandForTest(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No machine pools to display.\n\n")
+}
+
+func (s *MachinePoolSuite) TestSetSizeInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  `no machine pool name specified`,
+	}, {
+		args: []string{"workers"},
+		err:  `no machine pool size specified`,
+	}, {
+		args: []string{"workers", "many"},
+		err:  `machine pool size "many" not valid`,
+	}, {
+		args: []string{"workers", "2", "3"},
+		err:  `unrecognized args: \["3"\]`,
+	}, {
+		args: []string{"workers", "0"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(machine.NewSetMachinePoolSizeCommandForTest(s.api), test.args)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *MachinePoolSuite) TestSetSize(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewSetMachinePoolSizeCommandForTest(s.api), "workers", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"SetMachinePoolSize", []interface{}{"workers", 5}},
+		{"Close", nil},
+	})
+}
+
+func (s *MachinePoolSuite) TestRemove(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewRemoveMachinePoolCommandForTest(s.api), "workers")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"RemoveMachinePool", []interface{}{"workers"}},
+		{"Close", nil},
+	})
+}
+
+type fakeMachinePoolAPI struct {
+	jujutesting.Stub
+	pools []params.MachinePool
+}
+
+func (f *fakeMachinePoolAPI) AddMachinePool(pool params.MachinePool) error {
+	f.MethodCall(f, "AddMachinePool", pool)
+	return f.NextErr()
+}
+
+func (f *fakeMachinePoolAPI) MachinePools() ([]params.MachinePool, error) {
+	f.MethodCall(f, "MachinePools")
+	return f.pools, f.NextErr()
+}
+
+func (f *fakeMachinePoolAPI) SetMachinePoolSize(name string, size int) error {
+	f.MethodCall(f, "SetMachinePoolSize", name, size)
+	return f.NextErr()
+}
+
+func (f *fakeMachinePoolAPI) RemoveMachinePool(name string) error {
+	f.MethodCall(f, "RemoveMachinePool", name)
+	return f.NextErr()
+}
+
+func (f *fakeMachinePoolAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
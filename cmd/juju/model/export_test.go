@@ -116,6 +116,22 @@ func NewGrantCommandForTest(modelsApi GrantModelAPI, offersAPI GrantOfferAPI, st
 	return modelcmd.WrapController(cmd), &GrantCommand{cmd}
 }
 
+// NewGrantGroupCommandForTest returns a GrantCommand with the group api provided as specified.
+func NewGrantGroupCommandForTest(groupsApi GroupAccessAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCommand) {
+	cmd := &grantCommand{}
+	cmd.groupsApi = groupsApi
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd), &GrantCommand{cmd}
+}
+
+// NewRevokeGroupCommandForTest returns a RevokeCommand with the group api provided as specified.
+func NewRevokeGroupCommandForTest(groupsApi GroupAccessAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
+	cmd := &revokeCommand{}
+	cmd.groupsApi = groupsApi
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd), &RevokeCommand{cmd}
+}
+
 // NewRevokeCommandForTest returns an revokeCommand with the api provided as specified.
 func NewRevokeCommandForTest(modelsApi RevokeModelAPI, offersAPI RevokeOfferAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
 	cmd := &revokeCommand{
@@ -143,6 +159,14 @@ func NewGrantCloudCommandForTest(cloudsApi GrantCloudAPI, store jujuclient.Clien
 	return modelcmd.WrapController(cmd), &GrantCloudCommand{cmd}
 }
 
+// NewGrantCloudGroupCommandForTest returns a grantCloudCommand with the group api provided as specified.
+func NewGrantCloudGroupCommandForTest(groupsApi GroupAccessAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCloudCommand) {
+	cmd := &grantCloudCommand{}
+	cmd.groupsApi = groupsApi
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd), &GrantCloudCommand{cmd}
+}
+
 // NewRevokeCloudCommandForTest returns a revokeCloudCommand with the api provided as specified.
 func NewRevokeCloudCommandForTest(cloudsApi RevokeCloudAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCloudCommand) {
 	cmd := &revokeCloudCommand{
@@ -244,3 +268,19 @@ func NewShowCommitCommandForTest(api ShowCommitCommandAPI, store jujuclient.Clie
 	cmd.SetClientStore(store)
 	return modelcmd.Wrap(cmd)
 }
+
+// NewExportModelCommandForTest returns an exportModelCommand with the api
+// provided as specified.
+func NewExportModelCommandForTest(api ExportModelAPI, store jujuclient.ClientStore, clock jujuclock.Clock) cmd.Command {
+	cmd := &exportModelCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewImportModelCommandForTest returns an importModelCommand with the apis
+// provided as specified.
+func NewImportModelCommandForTest(api ImportModelAPI, sourceAPI SourceControllerAPI, store jujuclient.ClientStore, clock jujuclock.Clock) cmd.Command {
+	cmd := &importModelCommand{api: api, sourceAPI: sourceAPI, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
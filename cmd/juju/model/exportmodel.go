@@ -0,0 +1,381 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"github.com/juju/version"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/api/modelmanager"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/migration"
+)
+
+// NewExportModelCommand returns a fully constructed export-model command.
+func NewExportModelCommand() cmd.Command {
+	return modelcmd.Wrap(&exportModelCommand{
+		clock: clock.WallClock,
+	})
+}
+
+// ExportModelAPI specifies the API calls used by the export-model command.
+type ExportModelAPI interface {
+	Close() error
+	DumpModel(names.ModelTag, bool) (map[string]interface{}, error)
+	ControllerVersion() (version.Number, error)
+	OpenCharm(*charm.URL) (io.ReadCloser, error)
+	OpenURI(string, url.Values) (io.ReadCloser, error)
+	OpenResource(application, name string) (io.ReadCloser, error)
+	InitiateOfflineMigration(modelUUID string) (string, error)
+	MigrationPhase(id string) (coremigration.Phase, string, error)
+	AbortMigration(id string) error
+}
+
+type exportModelCommand struct {
+	modelcmd.ModelCommandBase
+	api   ExportModelAPI
+	clock clock.Clock
+
+	filename string
+	abort    bool
+}
+
+// migrationPollInterval is how often the phase of an offline migration
+// is checked while waiting for it to progress.
+const migrationPollInterval = 2 * time.Second
+
+const exportModelHelpDoc = `
+Exports the model, along with the charms, resources and agent binaries it
+uses, into a single archive file. The archive can then be imported into
+another controller with "juju import-model", without the two controllers
+needing to be able to reach each other.
+
+The model is described in the same format used by "juju dump-model" and
+by model migrations. Exporting starts a migration of the model: the
+controller checks the model can be migrated, and the model is locked and
+its agents are paused before it is exported, so that the archive holds
+its final state. The model stays locked on this controller until the
+archive is imported, when its agents are handed over to the importing
+controller and the model is removed from this one.
+
+If the archive is not going to be imported, the export can be aborted
+with the --abort option, unlocking the model again.
+
+Examples:
+
+    juju export-model mymodel.zip
+    juju export-model -m othermodel othermodel.zip
+    juju export-model --abort mymodel.zip
+
+See also:
+    import-model
+    dump-model
+    migrate
+`
+
+// Info implements Command.
+func (c *exportModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-model",
+		Args:    "<filename>",
+		Purpose: "Exports a model and its binaries into an archive file.",
+		Doc:     exportModelHelpDoc,
+	})
+}
+
+// SetFlags implements Command.
+func (c *exportModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.abort, "abort", false, "Abort the export held in the archive file, unlocking the model")
+}
+
+// Init implements Command.
+func (c *exportModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no filename specified")
+	}
+	c.filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type exportModelAPIAdapter struct {
+	*modelmanager.Client
+	controller *controller.Client
+	conn       api.Connection
+}
+
+// ControllerVersion is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) ControllerVersion() (version.Number, error) {
+	v, ok := a.conn.ServerVersion()
+	if !ok {
+		return version.Number{}, errors.New("controller version not known")
+	}
+	return v, nil
+}
+
+// OpenCharm is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return a.conn.Client().OpenCharm(curl)
+}
+
+// OpenURI is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	return a.conn.Client().OpenURI(uri, query)
+}
+
+// OpenResource is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) OpenResource(application, name string) (io.ReadCloser, error) {
+	httpClient, err := a.conn.HTTPClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to create HTTP client")
+	}
+	uri := fmt.Sprintf("/applications/%s/resources/%s", application, name)
+	var resp *http.Response
+	if err := httpClient.Get(a.conn.Context(), uri, &resp); err != nil {
+		return nil, errors.Annotate(err, "unable to retrieve resource")
+	}
+	return resp.Body, nil
+}
+
+// InitiateOfflineMigration is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) InitiateOfflineMigration(modelUUID string) (string, error) {
+	return a.controller.InitiateOfflineMigration(modelUUID)
+}
+
+// MigrationPhase is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) MigrationPhase(id string) (coremigration.Phase, string, error) {
+	return a.controller.MigrationPhase(id)
+}
+
+// AbortMigration is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) AbortMigration(id string) error {
+	return a.controller.AbortMigration(id)
+}
+
+// Close is part of the ExportModelAPI interface.
+func (a *exportModelAPIAdapter) Close() error {
+	_ = a.Client.Close()
+	_ = a.controller.Close()
+	return a.conn.Close()
+}
+
+func (c *exportModelCommand) getAPI() (ExportModelAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	client, err := c.NewModelManagerAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerRoot, err := c.NewControllerAPIRoot()
+	if err != nil {
+		_ = client.Close()
+		return nil, errors.Trace(err)
+	}
+	conn, err := c.NewAPIRoot()
+	if err != nil {
+		_ = client.Close()
+		_ = controllerRoot.Close()
+		return nil, errors.Trace(err)
+	}
+	return &exportModelAPIAdapter{
+		Client:     client,
+		controller: controller.NewClient(controllerRoot),
+		conn:       conn,
+	}, nil
+}
+
+// Run implements Command.
+func (c *exportModelCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.abort {
+		return c.abortExport(ctx, client)
+	}
+
+	modelName, modelDetails, err := c.ModelCommandBase.ModelDetails()
+	if err != nil {
+		return errors.Annotate(err, "getting model details")
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerDetails, err := c.ClientStore().ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerVersion, err := client.ControllerVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	path := ctx.AbsPath(c.filename)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Annotate(err, "creating model archive")
+	}
+	migrationId, err := client.InitiateOfflineMigration(modelDetails.ModelUUID)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return errors.Annotate(err, "locking model for export")
+	}
+	metadata := migration.ArchiveMetadata{
+		ControllerAgentVersion: controllerVersion,
+		SourceControllerUUID:   controllerDetails.ControllerUUID,
+		MigrationId:            migrationId,
+	}
+	if err := c.export(ctx, f, client, modelDetails.ModelUUID, metadata); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		if abortErr := client.AbortMigration(migrationId); abortErr != nil {
+			logger.Errorf("unlocking model: %v", abortErr)
+		}
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Annotate(err, "writing model archive")
+	}
+	ctx.Infof("Exported model %q to %s", modelName, c.filename)
+	ctx.Infof("The model is locked until the archive is imported with \"juju import-model\"")
+	return nil
+}
+
+// export waits for the model's agents to be paused by the offline
+// migration, then writes the model and its binaries into the archive.
+func (c *exportModelCommand) export(
+	ctx *cmd.Context, f io.Writer, client ExportModelAPI, modelUUID string, metadata migration.ArchiveMetadata,
+) error {
+	phase, message, err := waitForMigrationPhase(ctx, client, c.clock, metadata.MigrationId, func(phase coremigration.Phase) bool {
+		return phase != coremigration.QUIESCE
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if phase != coremigration.IMPORT {
+		return errors.Errorf("model export aborted: %s", message)
+	}
+
+	dumped, err := client.DumpModel(names.NewModelTag(modelUUID), false)
+	if err != nil {
+		return errors.Annotate(err, "exporting model")
+	}
+	// The dumped model is the map form of the model description, so
+	// round trip it through YAML to get the description back.
+	bytes, err := yaml.Marshal(dumped)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model, err := description.Deserialize(bytes)
+	if err != nil {
+		return errors.Annotate(err, "reading exported model")
+	}
+	serialized, err := migration.SerializeModel(model, func(v version.Binary) string {
+		return "/tools/" + v.String()
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.writeArchive(f, client, metadata, serialized))
+}
+
+// abortExport aborts the offline migration recorded in an archive that
+// won't be imported, unlocking the model on this controller.
+func (c *exportModelCommand) abortExport(ctx *cmd.Context, client ExportModelAPI) error {
+	archive, err := migration.OpenArchive(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	migrationId := archive.Metadata().MigrationId
+	if migrationId == "" {
+		return errors.New("model archive does not record an export to abort")
+	}
+	if err := client.AbortMigration(migrationId); err != nil {
+		return errors.Annotate(err, "aborting model export")
+	}
+	ctx.Infof("Aborted export %s; the model is being unlocked", migrationId)
+	return nil
+}
+
+func (c *exportModelCommand) writeArchive(
+	f io.Writer, client ExportModelAPI, metadata migration.ArchiveMetadata, serialized coremigration.SerializedModel,
+) error {
+	w := migration.NewArchiveWriter(f)
+	err := w.WriteModel(metadata, serialized.Bytes)
+	if err != nil {
+		return errors.Annotate(err, "writing model archive")
+	}
+	err = migration.UploadBinaries(migration.UploadBinariesConfig{
+		Charms:          serialized.Charms,
+		CharmDownloader: client,
+		CharmUploader:   w,
+
+		Tools:           serialized.Tools,
+		ToolsDownloader: client,
+		ToolsUploader:   w,
+
+		Resources:          serialized.Resources,
+		ResourceDownloader: client,
+		ResourceUploader:   w,
+	})
+	if err != nil {
+		return errors.Annotate(err, "exporting model binaries")
+	}
+	return errors.Annotate(w.Close(), "writing model archive")
+}
+
+// migrationPhaseAPI reports the progress of an offline migration on the
+// controller the model is exported from.
+type migrationPhaseAPI interface {
+	MigrationPhase(id string) (coremigration.Phase, string, error)
+}
+
+// waitForMigrationPhase polls the phase of the offline migration with
+// the given id until done returns true for it, reporting the migration's
+// status messages as they change. The phase reached and its status
+// message are returned.
+func waitForMigrationPhase(
+	ctx *cmd.Context, api migrationPhaseAPI, clk clock.Clock, id string, done func(coremigration.Phase) bool,
+) (coremigration.Phase, string, error) {
+	var reported string
+	for {
+		phase, message, err := api.MigrationPhase(id)
+		if err != nil {
+			return coremigration.UNKNOWN, "", errors.Annotate(err, "reading migration status")
+		}
+		if done(phase) {
+			return phase, message, nil
+		}
+		if message != reported && message != "" {
+			ctx.Verbosef("%s", message)
+			reported = message
+		}
+		<-clk.After(migrationPollInterval)
+	}
+}
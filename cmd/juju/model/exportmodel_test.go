@@ -0,0 +1,279 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cmd/juju/model"
+	coremigration "github.com/juju/juju/core/migration"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/testing"
+)
+
+type ExportModelCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeExportModelClient
+	store *jujuclient.MemStore
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&ExportModelCommandSuite{})
+
+// newExportedModel returns a minimal model description using a charm and
+// agent binaries.
+func newExportedModel() description.Model {
+	m := description.NewModel(description.ModelArgs{
+		Type:  "iaas",
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"uuid":          testing.ModelTag.Id(),
+			"name":          "mymodel",
+			"agent-version": "2.9.0",
+		},
+	})
+	m.SetStatus(description.StatusArgs{Value: "available"})
+	app := m.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("app"),
+		CharmURL: "cs:focal/app-1",
+	})
+	app.SetStatus(description.StatusArgs{Value: "active"})
+	machine := m.AddMachine(description.MachineArgs{Id: names.NewMachineTag("0")})
+	machine.SetStatus(description.StatusArgs{Value: "started"})
+	machine.SetTools(description.AgentToolsArgs{
+		Version: version.MustParseBinary("2.9.0-focal-amd64"),
+	})
+	return m
+}
+
+type fakeExportModelClient struct {
+	gitjujutesting.Stub
+	model  description.Model
+	phases []coremigration.Phase
+}
+
+func (f *fakeExportModelClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeExportModelClient) DumpModel(model names.ModelTag, simplified bool) (map[string]interface{}, error) {
+	f.MethodCall(f, "DumpModel", model, simplified)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	bytes, err := description.Serialize(f.model)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	return result, yaml.Unmarshal(bytes, &result)
+}
+
+func (f *fakeExportModelClient) ControllerVersion() (version.Number, error) {
+	f.MethodCall(f, "ControllerVersion")
+	return version.MustParse("2.9.1"), f.NextErr()
+}
+
+func (f *fakeExportModelClient) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenCharm", curl)
+	return ioutil.NopCloser(strings.NewReader("charm content")), f.NextErr()
+}
+
+func (f *fakeExportModelClient) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenURI", uri)
+	return ioutil.NopCloser(strings.NewReader("tools content")), f.NextErr()
+}
+
+func (f *fakeExportModelClient) OpenResource(application, name string) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenResource", application, name)
+	return ioutil.NopCloser(strings.NewReader("resource content")), f.NextErr()
+}
+
+func (f *fakeExportModelClient) InitiateOfflineMigration(modelUUID string) (string, error) {
+	f.MethodCall(f, "InitiateOfflineMigration", modelUUID)
+	return "mig-id", f.NextErr()
+}
+
+func (f *fakeExportModelClient) MigrationPhase(id string) (coremigration.Phase, string, error) {
+	f.MethodCall(f, "MigrationPhase", id)
+	phase := coremigration.IMPORT
+	if len(f.phases) > 0 {
+		phase, f.phases = f.phases[0], f.phases[1:]
+	}
+	return phase, "phase " + phase.String(), f.NextErr()
+}
+
+func (f *fakeExportModelClient) AbortMigration(id string) error {
+	f.MethodCall(f, "AbortMigration", id)
+	return f.NextErr()
+}
+
+func (s *ExportModelCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeExportModelClient{model: newExportedModel()}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+	}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *ExportModelCommandSuite) runExport(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewExportModelCommandForTest(&s.fake, s.store, s.clock), args...)
+}
+
+func (s *ExportModelCommandSuite) TestExport(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	ctx, err := s.runExport(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Exported model \"admin/mymodel\" to "+path+"\n"+
+		"The model is locked until the archive is imported with \"juju import-model\"\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ControllerVersion", nil},
+		{"InitiateOfflineMigration", []interface{}{testing.ModelTag.Id()}},
+		{"MigrationPhase", []interface{}{"mig-id"}},
+		{"DumpModel", []interface{}{testing.ModelTag, false}},
+		{"OpenCharm", []interface{}{charm.MustParseURL("cs:focal/app-1")}},
+		{"OpenURI", []interface{}{"/tools/2.9.0-focal-amd64"}},
+		{"Close", nil},
+	})
+
+	archive, err := migration.OpenArchive(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	c.Assert(archive.Metadata(), jc.DeepEquals, migration.ArchiveMetadata{
+		FormatVersion:          migration.ArchiveFormatVersion,
+		ControllerAgentVersion: version.MustParse("2.9.1"),
+		SourceControllerUUID:   testing.ControllerTag.Id(),
+		MigrationId:            "mig-id",
+	})
+	info, err := archive.ModelInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.UUID, gc.Equals, testing.ModelTag.Id())
+	c.Assert(info.Name, gc.Equals, "mymodel")
+	rc, err := archive.OpenCharm(charm.MustParseURL("cs:focal/app-1"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, "charm content")
+}
+
+func (s *ExportModelCommandSuite) TestExportRemovesArchiveOnError(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	s.fake.SetErrors(nil, nil, nil, nil, errors.New("boom"))
+	_, err := s.runExport(c, path)
+	c.Assert(err, gc.ErrorMatches, `exporting model binaries: .*boom`)
+	_, err = os.Stat(path)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	s.fake.CheckCallNames(c,
+		"ControllerVersion", "InitiateOfflineMigration", "MigrationPhase", "DumpModel", "OpenCharm",
+		"AbortMigration", "Close",
+	)
+	s.fake.CheckCall(c, 5, "AbortMigration", "mig-id")
+}
+
+func (s *ExportModelCommandSuite) TestExportWaitsForAgents(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	s.fake.phases = []coremigration.Phase{coremigration.QUIESCE, coremigration.IMPORT}
+	done := make(chan error)
+	go func() {
+		_, err := s.runExport(c, path)
+		done <- err
+	}()
+	err := s.clock.WaitAdvance(2*time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for export")
+	}
+	s.fake.CheckCallNames(c,
+		"ControllerVersion", "InitiateOfflineMigration", "MigrationPhase", "MigrationPhase",
+		"DumpModel", "OpenCharm", "OpenURI", "Close",
+	)
+}
+
+func (s *ExportModelCommandSuite) TestExportMigrationAborted(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	s.fake.phases = []coremigration.Phase{coremigration.ABORT}
+	_, err := s.runExport(c, path)
+	c.Assert(err, gc.ErrorMatches, `model export aborted: phase ABORT`)
+	_, err = os.Stat(path)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	s.fake.CheckCallNames(c,
+		"ControllerVersion", "InitiateOfflineMigration", "MigrationPhase", "AbortMigration", "Close",
+	)
+}
+
+func (s *ExportModelCommandSuite) TestExportInitiateFails(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	s.fake.SetErrors(nil, errors.New("model is being migrated"))
+	_, err := s.runExport(c, path)
+	c.Assert(err, gc.ErrorMatches, `locking model for export: model is being migrated`)
+	_, err = os.Stat(path)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	s.fake.CheckCallNames(c, "ControllerVersion", "InitiateOfflineMigration", "Close")
+}
+
+func (s *ExportModelCommandSuite) TestAbortExport(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	_, err := s.runExport(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.ResetCalls()
+
+	ctx, err := s.runExport(c, "--abort", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Aborted export mig-id; the model is being unlocked\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"AbortMigration", []interface{}{"mig-id"}},
+		{"Close", nil},
+	})
+}
+
+func (s *ExportModelCommandSuite) TestExportExistingFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	err := ioutil.WriteFile(path, nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.runExport(c, path)
+	c.Assert(err, gc.ErrorMatches, `creating model archive: .* file exists`)
+}
+
+func (s *ExportModelCommandSuite) TestExportNoFilename(c *gc.C) {
+	_, err := s.runExport(c)
+	c.Assert(err, gc.ErrorMatches, `no filename specified`)
+}
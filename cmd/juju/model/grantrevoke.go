@@ -6,6 +6,7 @@ package model
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api/applicationoffers"
@@ -65,9 +66,14 @@ Grant user 'sam' 'read' access to application offers 'fred/prod.hosted-mysql' an
 
     juju grant sam read fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Grant the members of group 'engineers' 'write' access to model 'mymodel':
+
+    juju grant --group engineers write mymodel
+
 See also: 
     revoke
-    add-user`[1:]
+    add-user
+    add-group`[1:]
 
 var usageRevokeSummary = `
 Revokes access from a Juju user for a model, controller, or application offer.`[1:]
@@ -96,21 +102,37 @@ Revoke 'consume' access from user 'sam' for models 'fred/prod.hosted-mysql' and
 
     juju revoke sam consume fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Revoke 'write' access from the members of group 'engineers' for model 'mymodel':
+
+    juju revoke --group engineers write mymodel
+
 See also: 
-    grant`[1:]
+    grant
+    remove-group`[1:]
 
 type accessCommand struct {
 	modelcmd.ControllerCommandBase
+	groupsApi GroupAccessAPI
 
 	User       string
 	ModelNames []string
 	OfferURLs  []*crossmodel.OfferURL
 	Access     string
+	Group      bool
+}
+
+// SetFlags implements cmd.Command.
+func (c *accessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.Group, "group", false, "Change the access of a group of users rather than a single user")
 }
 
 // Init implements cmd.Command.
 func (c *accessCommand) Init(args []string) error {
 	if len(args) < 1 {
+		if c.Group {
+			return errors.New("no group specified")
+		}
 		return errors.New("no user specified")
 	}
 
@@ -118,6 +140,9 @@ func (c *accessCommand) Init(args []string) error {
 		return errors.New("no permission level specified")
 	}
 
+	if c.Group && !names.IsValidUserName(args[0]) {
+		return errors.NotValidf("group name %q", args[0])
+	}
 	c.User = args[0]
 	c.Access = args[1]
 	// The remaining args are either model names or offer names.
@@ -181,7 +206,7 @@ type grantCommand struct {
 func (c *grantCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "grant",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name>|--group <group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageGrantSummary,
 		Doc:     usageGrantDetails,
 	})
@@ -229,6 +254,9 @@ type GrantOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *grantCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(true)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -296,7 +324,7 @@ type revokeCommand struct {
 func (c *revokeCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "revoke",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name>|--group <group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageRevokeSummary,
 		Doc:     usageRevokeDetails,
 	})
@@ -344,6 +372,9 @@ type RevokeOfferAPI interface {
 
 // Run implements cmd.Command.
 func (c *revokeCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(false)
+	}
 	if len(c.ModelNames) > 0 {
 		return c.runForModel()
 	}
@@ -380,6 +411,71 @@ func (c *revokeCommand) runForModel() error {
 	return block.ProcessBlockedError(client.RevokeModel(c.User, c.Access, models...), block.BlockChange)
 }
 
+// GroupAccessAPI defines the API functions used by the grant and
+// revoke commands to change the access of groups.
+type GroupAccessAPI interface {
+	Close() error
+	GrantGroup(group, access string, target names.Tag) error
+	RevokeGroup(group, access string, target names.Tag) error
+	GrantGroupOffer(group, access, offerURL string) error
+	RevokeGroupOffer(group, access, offerURL string) error
+}
+
+func (c *accessCommand) getGroupAPI() (GroupAccessAPI, error) {
+	if c.groupsApi != nil {
+		return c.groupsApi, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// runForGroup grants or revokes the access of the group to the
+// models, offers or controller.
+func (c *accessCommand) runForGroup(grant bool) error {
+	client, err := c.getGroupAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	change, changeOffer := client.RevokeGroup, client.RevokeGroupOffer
+	if grant {
+		change, changeOffer = client.GrantGroup, client.GrantGroupOffer
+	}
+	switch {
+	case len(c.ModelNames) > 0:
+		models, err := c.ModelUUIDs(c.ModelNames)
+		if err != nil {
+			return err
+		}
+		for _, uuid := range models {
+			if err := change(c.User, c.Access, names.NewModelTag(uuid)); err != nil {
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+		}
+	case len(c.OfferURLs) > 0:
+		if err := setUnsetUsers(c, c.OfferURLs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, url := range c.OfferURLs {
+			if err := changeOffer(c.User, c.Access, url.String()); err != nil {
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+		}
+	default:
+		controllerName, err := c.ControllerName()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		details, err := c.ClientStore().ControllerByName(controllerName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = change(c.User, c.Access, names.NewControllerTag(details.ControllerUUID))
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
 type accountDetailsGetter interface {
 	CurrentAccountDetails() (*jujuclient.AccountDetails, error)
 }
@@ -8,6 +8,8 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -254,3 +256,121 @@ func (f *fakeOffersGrantRevokeAPI) fake(user, access string, offerURLs ...string
 	f.offerURLs = append(f.offerURLs, offerURLs...)
 	return f.err
 }
+
+type groupGrantRevokeSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fakeGroupAPI *fakeGroupGrantRevokeAPI
+	store        *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&groupGrantRevokeSuite{})
+
+func (s *groupGrantRevokeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fakeGroupAPI = &fakeGroupGrantRevokeAPI{}
+
+	controllerName := "test-master"
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = controllerName
+	s.store.Controllers[controllerName] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+	}
+	s.store.Accounts[controllerName] = jujuclient.AccountDetails{
+		User: "bob",
+	}
+	s.store.Models = map[string]*jujuclient.ControllerModels{
+		controllerName: {
+			Models: map[string]jujuclient.ModelDetails{
+				"bob/foo": {ModelUUID: fooModelUUID, ModelType: coremodel.IAAS},
+				"bob/bar": {ModelUUID: barModelUUID, ModelType: coremodel.IAAS},
+			},
+		},
+	}
+}
+
+func (s *groupGrantRevokeSuite) TestInit(c *gc.C) {
+	wrappedCmd, grantCmd := model.NewGrantGroupCommandForTest(nil, s.store)
+	err := cmdtesting.InitCommand(wrappedCmd, []string{"--group"})
+	c.Assert(err, gc.ErrorMatches, "no group specified")
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"--group", "not/valid", "read", "foo"})
+	c.Assert(err, gc.ErrorMatches, `group name "not/valid" not valid`)
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"--group", "engineers", "read", "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grantCmd.Group, jc.IsTrue)
+	c.Assert(grantCmd.User, gc.Equals, "engineers")
+	c.Assert(grantCmd.ModelNames, jc.DeepEquals, []string{"foo"})
+}
+
+func (s *groupGrantRevokeSuite) TestGrantModels(c *gc.C) {
+	command, _ := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "engineers", "write", "foo", "bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeGroupAPI.calls, jc.DeepEquals, []string{
+		"GrantGroup engineers write model-" + fooModelUUID,
+		"GrantGroup engineers write model-" + barModelUUID,
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestGrantOffer(c *gc.C) {
+	command, _ := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "engineers", "consume", "fred/foo.mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeGroupAPI.calls, jc.DeepEquals, []string{
+		"GrantGroupOffer engineers consume fred/foo.mysql",
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestRevokeController(c *gc.C) {
+	command, _ := model.NewRevokeGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "engineers", "superuser")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeGroupAPI.calls, jc.DeepEquals, []string{
+		"RevokeGroup engineers superuser " + testing.ControllerTag.String(),
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestGrantCloud(c *gc.C) {
+	command, _ := model.NewGrantCloudGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "engineers", "add-model", "fluffy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeGroupAPI.calls, jc.DeepEquals, []string{
+		"GrantGroup engineers add-model cloud-fluffy",
+	})
+}
+
+func (s *groupGrantRevokeSuite) TestGrantError(c *gc.C) {
+	s.fakeGroupAPI.err = errors.New("boom")
+	command, _ := model.NewGrantGroupCommandForTest(s.fakeGroupAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "--group", "engineers", "read", "foo")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeGroupGrantRevokeAPI struct {
+	err   error
+	calls []string
+}
+
+func (f *fakeGroupGrantRevokeAPI) Close() error { return nil }
+
+func (f *fakeGroupGrantRevokeAPI) GrantGroup(group, access string, target names.Tag) error {
+	return f.fake("GrantGroup", group, access, target.String())
+}
+
+func (f *fakeGroupGrantRevokeAPI) RevokeGroup(group, access string, target names.Tag) error {
+	return f.fake("RevokeGroup", group, access, target.String())
+}
+
+func (f *fakeGroupGrantRevokeAPI) GrantGroupOffer(group, access, offerURL string) error {
+	return f.fake("GrantGroupOffer", group, access, offerURL)
+}
+
+func (f *fakeGroupGrantRevokeAPI) RevokeGroupOffer(group, access, offerURL string) error {
+	return f.fake("RevokeGroupOffer", group, access, offerURL)
+}
+
+func (f *fakeGroupGrantRevokeAPI) fake(call, group, access, target string) error {
+	f.calls = append(f.calls, strings.Join([]string{call, group, access, target}, " "))
+	return f.err
+}
@@ -6,6 +6,7 @@ package model
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/juju/api/cloud"
 	"github.com/juju/names/v4"
 
@@ -28,6 +29,10 @@ Grant user 'joe' 'add-model' access to cloud 'fluffy':
 
     juju grant-cloud joe add-model fluffy
 
+Grant the members of group 'engineers' 'add-model' access to cloud 'fluffy':
+
+    juju grant-cloud --group engineers add-model fluffy
+
 See also: 
     revoke-cloud
     add-user`[1:]
@@ -49,20 +54,35 @@ Revoke 'admin' access from user 'sam' for clouds 'fluffy' and 'rainy':
 
     juju revoke-cloud sam admin fluffy rainy
 
+Revoke 'add-model' access from the members of group 'engineers' for cloud 'fluffy':
+
+    juju revoke-cloud --group engineers add-model fluffy
+
 See also: 
     grant-cloud`[1:]
 
 type accessCloudCommand struct {
 	modelcmd.ControllerCommandBase
+	groupsApi GroupAccessAPI
 
 	User   string
 	Clouds []string
 	Access string
+	Group  bool
+}
+
+// SetFlags implements cmd.Command.
+func (c *accessCloudCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.Group, "group", false, "Change the access of a group of users rather than a single user")
 }
 
 // Init implements cmd.Command.
 func (c *accessCloudCommand) Init(args []string) error {
 	if len(args) < 1 {
+		if c.Group {
+			return errors.New("no group specified")
+		}
 		return errors.New("no user specified")
 	}
 
@@ -70,6 +90,10 @@ func (c *accessCloudCommand) Init(args []string) error {
 		return errors.New("no permission level specified")
 	}
 
+	if c.Group && !names.IsValidUserName(args[0]) {
+		return errors.NotValidf("group name %q", args[0])
+	}
+
 	c.User = args[0]
 	c.Access = args[1]
 	// The remaining args are cloud names.
@@ -106,7 +130,7 @@ type grantCloudCommand struct {
 func (c *grantCloudCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "grant-cloud",
-		Args:    "<user name> <permission> <cloud name> ...",
+		Args:    "<user name>|--group <group name> <permission> <cloud name> ...",
 		Purpose: usageGrantCloudSummary,
 		Doc:     usageGrantCloudDetails,
 	})
@@ -131,6 +155,9 @@ type GrantCloudAPI interface {
 
 // Run implements cmd.Command.
 func (c *grantCloudCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(true)
+	}
 	client, err := c.getCloudsAPI()
 	if err != nil {
 		return err
@@ -155,7 +182,7 @@ type revokeCloudCommand struct {
 func (c *revokeCloudCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "revoke-cloud",
-		Args:    "<user name> <permission> <cloud name> ...",
+		Args:    "<user name>|--group <group name> <permission> <cloud name> ...",
 		Purpose: usageRevokeCloudSummary,
 		Doc:     usageRevokeCloudDetails,
 	})
@@ -180,6 +207,9 @@ type RevokeCloudAPI interface {
 
 // Run implements cmd.Command.
 func (c *revokeCloudCommand) Run(ctx *cmd.Context) error {
+	if c.Group {
+		return c.runForGroup(false)
+	}
 	client, err := c.getCloudAPI()
 	if err != nil {
 		return err
@@ -188,3 +218,30 @@ func (c *revokeCloudCommand) Run(ctx *cmd.Context) error {
 
 	return block.ProcessBlockedError(client.RevokeCloud(c.User, c.Access, c.Clouds...), block.BlockChange)
 }
+
+func (c *accessCloudCommand) getGroupAPI() (GroupAccessAPI, error) {
+	if c.groupsApi != nil {
+		return c.groupsApi, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// runForGroup grants or revokes the access of the group to the clouds.
+func (c *accessCloudCommand) runForGroup(grant bool) error {
+	client, err := c.getGroupAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	change := client.RevokeGroup
+	if grant {
+		change = client.GrantGroup
+	}
+	for _, cloud := range c.Clouds {
+		if err := change(c.User, c.Access, names.NewCloudTag(cloud)); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/api/migrationtarget"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/tools"
+)
+
+// NewImportModelCommand returns a fully constructed import-model command.
+func NewImportModelCommand() cmd.Command {
+	return modelcmd.WrapController(&importModelCommand{
+		clock: clock.WallClock,
+	})
+}
+
+// ImportModelAPI specifies the API calls used by the import-model command.
+type ImportModelAPI interface {
+	Close() error
+	Prechecks(coremigration.ModelInfo) error
+	Import([]byte) error
+	Abort(string) error
+	Activate(string) error
+	UploadCharm(string, *charm.URL, io.ReadSeeker) (*charm.URL, error)
+	UploadTools(string, io.ReadSeeker, version.Binary, ...string) (tools.List, error)
+	UploadResource(string, resource.Resource, io.ReadSeeker) error
+	SetPlaceholderResource(string, resource.Resource) error
+	SetUnitResource(string, string, resource.Resource) error
+	CheckMachines(string) ([]error, error)
+	AdoptResources(string) error
+}
+
+// SourceControllerAPI specifies the API calls made by the import-model
+// command to the controller the model was exported from.
+type SourceControllerAPI interface {
+	Close() error
+	MigrationPhase(id string) (coremigration.Phase, string, error)
+	SetMigrationTarget(id string, target controller.MigrationTarget) error
+}
+
+type importModelCommand struct {
+	modelcmd.ControllerCommandBase
+	api       ImportModelAPI
+	sourceAPI SourceControllerAPI
+	clock     clock.Clock
+
+	filename string
+}
+
+const importModelHelpDoc = `
+Imports a model archive created by "juju export-model" into the controller.
+The controller is checked in the same way as the target controller of a
+model migration before the model is imported, and the charms, resources
+and agent binaries held in the archive are uploaded to it. Only controller
+superusers can import models.
+
+The machines of the model are checked to exist in the cloud before the
+model is handed over. If any check fails the imported model is removed
+again, leaving the controller unchanged, and the model stays locked on
+the controller it was exported from.
+
+The controller the model was exported from must be known to this client,
+as the model's agents are handed over from it once the model has been
+imported, in the same way as for "juju migrate". The model is activated
+on this controller, and takes ownership of the model's cloud resources,
+only after the exporting controller has handed the agents over and
+stopped managing the model.
+
+Examples:
+
+    juju import-model mymodel.zip
+    juju import-model -c othercontroller mymodel.zip
+
+See also:
+    export-model
+    migrate
+`
+
+// Info implements Command.
+func (c *importModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "import-model",
+		Args:    "<filename>",
+		Purpose: "Imports a model archive into the controller.",
+		Doc:     importModelHelpDoc,
+	})
+}
+
+// Init implements Command.
+func (c *importModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no filename specified")
+	}
+	c.filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type importModelAPIAdapter struct {
+	*migrationtarget.Client
+	io.Closer
+}
+
+func (c *importModelCommand) getAPI() (ImportModelAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	conn, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &importModelAPIAdapter{
+		Client: migrationtarget.NewClient(conn),
+		Closer: conn,
+	}, nil
+}
+
+func (c *importModelCommand) getSourceAPI(controllerName string) (SourceControllerAPI, error) {
+	if c.sourceAPI != nil {
+		return c.sourceAPI, nil
+	}
+	conn, err := c.CommandBase.NewAPIRoot(c.ClientStore(), controllerName, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return controller.NewClient(conn), nil
+}
+
+// sourceControllerName returns the name by which the client store knows
+// the controller with the given UUID.
+func (c *importModelCommand) sourceControllerName(controllerUUID string) (string, error) {
+	if controllerUUID == "" {
+		return "", errors.New("model archive does not record the controller it was exported from")
+	}
+	controllers, err := c.ClientStore().AllControllers()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for name, details := range controllers {
+		if details.ControllerUUID == controllerUUID {
+			return name, nil
+		}
+	}
+	return "", errors.NotFoundf("controller %s the model was exported from", controllerUUID)
+}
+
+// migrationTarget returns the details of this controller needed by the
+// source controller to hand the model's agents over to it.
+func (c *importModelCommand) migrationTarget() (controller.MigrationTarget, error) {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return controller.MigrationTarget{}, errors.Trace(err)
+	}
+	store := c.ClientStore()
+	controllerDetails, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return controller.MigrationTarget{}, errors.Trace(err)
+	}
+	accountDetails, err := store.AccountDetails(controllerName)
+	if err != nil {
+		return controller.MigrationTarget{}, errors.Trace(err)
+	}
+	return controller.MigrationTarget{
+		ControllerUUID:  controllerDetails.ControllerUUID,
+		ControllerAlias: controllerName,
+		Addrs:           controllerDetails.APIEndpoints,
+		CACert:          controllerDetails.CACert,
+		User:            accountDetails.User,
+	}, nil
+}
+
+// Run implements Command.
+func (c *importModelCommand) Run(ctx *cmd.Context) error {
+	archive, err := migration.OpenArchive(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	info, err := archive.ModelInfo()
+	if err != nil {
+		return errors.Annotate(err, "reading model archive")
+	}
+	serialized, err := archive.SerializedModel()
+	if err != nil {
+		return errors.Annotate(err, "reading model archive")
+	}
+	metadata := archive.Metadata()
+	if metadata.MigrationId == "" {
+		return errors.New("model archive does not record the export it was created by")
+	}
+
+	sourceName, err := c.sourceControllerName(metadata.SourceControllerUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	target, err := c.migrationTarget()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	source, err := c.getSourceAPI(sourceName)
+	if err != nil {
+		return errors.Annotatef(err, "connecting to controller %q", sourceName)
+	}
+	defer source.Close()
+
+	phase, _, err := source.MigrationPhase(metadata.MigrationId)
+	if err != nil {
+		return errors.Annotatef(err, "checking export on controller %q", sourceName)
+	}
+	if phase != coremigration.IMPORT {
+		return errors.Errorf("model export on controller %q is in the %s phase, not waiting to be imported", sourceName, phase)
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Prechecks(info); err != nil {
+		return errors.Annotate(err, "controller prechecks failed")
+	}
+	if err := client.Import(serialized.Bytes); err != nil {
+		return errors.Annotate(err, "importing model")
+	}
+	if err := c.handOver(ctx, client, source, archive, info.UUID, serialized, metadata.MigrationId, target); err != nil {
+		if abortErr := client.Abort(info.UUID); abortErr != nil {
+			logger.Errorf("removing partially imported model: %v", abortErr)
+		}
+		return errors.Trace(err)
+	}
+	if err := client.Activate(info.UUID); err != nil {
+		return errors.Annotate(err, "activating model")
+	}
+	if err := client.AdoptResources(info.UUID); err != nil {
+		return errors.Annotate(err, "transferring ownership of cloud resources")
+	}
+	ctx.Infof("Imported model %q owned by %s", info.Name, info.Owner.Id())
+	return nil
+}
+
+// handOver uploads the binaries for the imported model and checks its
+// machines, then has the source controller hand the model's agents over
+// to this controller, waiting until it has done so.
+func (c *importModelCommand) handOver(
+	ctx *cmd.Context, client ImportModelAPI, source SourceControllerAPI, archive *migration.ArchiveReader,
+	modelUUID string, serialized coremigration.SerializedModel, migrationId string, target controller.MigrationTarget,
+) error {
+	if err := c.transfer(ctx, client, archive, modelUUID, serialized); err != nil {
+		return errors.Trace(err)
+	}
+	if err := source.SetMigrationTarget(migrationId, target); err != nil {
+		return errors.Annotate(err, "handing model over")
+	}
+	phase, message, err := waitForMigrationPhase(ctx, source, c.clock, migrationId, func(phase coremigration.Phase) bool {
+		return phase >= coremigration.SUCCESS
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch phase {
+	case coremigration.ABORT, coremigration.ABORTDONE:
+		return errors.Errorf("handing model over aborted: %s", message)
+	}
+	return nil
+}
+
+// transfer uploads the binaries held in the archive and checks the
+// machines of the imported model.
+func (c *importModelCommand) transfer(
+	ctx *cmd.Context, client ImportModelAPI, archive *migration.ArchiveReader,
+	modelUUID string, serialized coremigration.SerializedModel,
+) error {
+	wrapper := &importUploadWrapper{client, modelUUID}
+	err := migration.UploadBinaries(migration.UploadBinariesConfig{
+		Charms:          serialized.Charms,
+		CharmDownloader: archive,
+		CharmUploader:   wrapper,
+
+		Tools:           serialized.Tools,
+		ToolsDownloader: archive,
+		ToolsUploader:   wrapper,
+
+		Resources:          serialized.Resources,
+		ResourceDownloader: archive,
+		ResourceUploader:   wrapper,
+	})
+	if err != nil {
+		return errors.Annotate(err, "uploading model binaries")
+	}
+
+	machineErrs, err := client.CheckMachines(modelUUID)
+	if err != nil {
+		return errors.Annotate(err, "checking machines")
+	}
+	if len(machineErrs) > 0 {
+		for _, machineErr := range machineErrs {
+			ctx.Warningf("%v", machineErr)
+		}
+		return errors.Errorf("machine sanity check failed, %d error(s) found", len(machineErrs))
+	}
+	return nil
+}
+
+// importUploadWrapper prepends the model UUID to the args passed to the
+// migration target client.
+type importUploadWrapper struct {
+	client    ImportModelAPI
+	modelUUID string
+}
+
+// UploadCharm is part of the migration.CharmUploader interface.
+func (w *importUploadWrapper) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	return w.client.UploadCharm(w.modelUUID, curl, content)
+}
+
+// UploadTools is part of the migration.ToolsUploader interface.
+func (w *importUploadWrapper) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	return w.client.UploadTools(w.modelUUID, r, vers, additionalSeries...)
+}
+
+// UploadResource is part of the migration.ResourceUploader interface.
+func (w *importUploadWrapper) UploadResource(res resource.Resource, content io.ReadSeeker) error {
+	return w.client.UploadResource(w.modelUUID, res, content)
+}
+
+// SetPlaceholderResource is part of the migration.ResourceUploader interface.
+func (w *importUploadWrapper) SetPlaceholderResource(res resource.Resource) error {
+	return w.client.SetPlaceholderResource(w.modelUUID, res)
+}
+
+// SetUnitResource is part of the migration.ResourceUploader interface.
+func (w *importUploadWrapper) SetUnitResource(unitName string, res resource.Resource) error {
+	return w.client.SetUnitResource(w.modelUUID, unitName, res)
+}
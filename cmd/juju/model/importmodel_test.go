@@ -0,0 +1,312 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/cmd/juju/model"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+)
+
+type ImportModelCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake   fakeImportModelClient
+	source fakeSourceControllerClient
+	store  *jujuclient.MemStore
+	clock  *testclock.Clock
+	path   string
+}
+
+var _ = gc.Suite(&ImportModelCommandSuite{})
+
+type fakeImportModelClient struct {
+	gitjujutesting.Stub
+	machineErrs []error
+}
+
+func (f *fakeImportModelClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) Prechecks(info coremigration.ModelInfo) error {
+	f.MethodCall(f, "Prechecks", info)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) Import(bytes []byte) error {
+	f.MethodCall(f, "Import")
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) Abort(modelUUID string) error {
+	f.MethodCall(f, "Abort", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) Activate(modelUUID string) error {
+	f.MethodCall(f, "Activate", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	f.MethodCall(f, "UploadCharm", modelUUID, curl, string(data))
+	return curl, f.NextErr()
+}
+
+func (f *fakeImportModelClient) UploadTools(modelUUID string, r io.ReadSeeker, v version.Binary, _ ...string) (tools.List, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f.MethodCall(f, "UploadTools", modelUUID, v, string(data))
+	return tools.List{{Version: v}}, f.NextErr()
+}
+
+func (f *fakeImportModelClient) UploadResource(modelUUID string, res resource.Resource, r io.ReadSeeker) error {
+	f.MethodCall(f, "UploadResource", modelUUID, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) SetPlaceholderResource(modelUUID string, res resource.Resource) error {
+	f.MethodCall(f, "SetPlaceholderResource", modelUUID, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) SetUnitResource(modelUUID, unit string, res resource.Resource) error {
+	f.MethodCall(f, "SetUnitResource", modelUUID, unit, res.Name)
+	return f.NextErr()
+}
+
+func (f *fakeImportModelClient) CheckMachines(modelUUID string) ([]error, error) {
+	f.MethodCall(f, "CheckMachines", modelUUID)
+	return f.machineErrs, f.NextErr()
+}
+
+func (f *fakeImportModelClient) AdoptResources(modelUUID string) error {
+	f.MethodCall(f, "AdoptResources", modelUUID)
+	return f.NextErr()
+}
+
+type fakeSourceControllerClient struct {
+	gitjujutesting.Stub
+	phases []coremigration.Phase
+}
+
+func (f *fakeSourceControllerClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeSourceControllerClient) MigrationPhase(id string) (coremigration.Phase, string, error) {
+	f.MethodCall(f, "MigrationPhase", id)
+	phase := coremigration.IMPORT
+	if len(f.phases) > 0 {
+		phase, f.phases = f.phases[0], f.phases[1:]
+	}
+	return phase, "phase " + phase.String(), f.NextErr()
+}
+
+func (f *fakeSourceControllerClient) SetMigrationTarget(id string, target controller.MigrationTarget) error {
+	f.MethodCall(f, "SetMigrationTarget", id, target)
+	return f.NextErr()
+}
+
+const sourceControllerUUID = "cafebabe-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *ImportModelCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeImportModelClient{}
+	s.source = fakeSourceControllerClient{
+		phases: []coremigration.Phase{coremigration.IMPORT, coremigration.SUCCESS},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{
+		ControllerUUID: testing.ControllerTag.Id(),
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         testing.CACert,
+	}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	s.store.Controllers["source"] = jujuclient.ControllerDetails{
+		ControllerUUID: sourceControllerUUID,
+	}
+	s.clock = testclock.NewClock(time.Now())
+	s.path = s.writeArchive(c, newExportedModel())
+}
+
+func (s *ImportModelCommandSuite) runImport(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := model.NewImportModelCommandForTest(&s.fake, &s.source, s.store, s.clock)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *ImportModelCommandSuite) expectedTarget() controller.MigrationTarget {
+	return controller.MigrationTarget{
+		ControllerUUID:  testing.ControllerTag.Id(),
+		ControllerAlias: "testing",
+		Addrs:           []string{"10.0.0.1:17070"},
+		CACert:          testing.CACert,
+		User:            "admin",
+	}
+}
+
+func (s *ImportModelCommandSuite) writeArchive(c *gc.C, m description.Model) string {
+	path := filepath.Join(c.MkDir(), "mymodel.zip")
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+
+	bytes, err := description.Serialize(m)
+	c.Assert(err, jc.ErrorIsNil)
+	w := migration.NewArchiveWriter(f)
+	err = w.WriteModel(migration.ArchiveMetadata{
+		ControllerAgentVersion: version.MustParse("2.9.1"),
+		SourceControllerUUID:   sourceControllerUUID,
+		MigrationId:            "mig-id",
+	}, bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.UploadCharm(charm.MustParseURL("cs:focal/app-1"), strings.NewReader("charm content"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.UploadTools(strings.NewReader("tools content"), version.MustParseBinary("2.9.0-focal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	return path
+}
+
+func (s *ImportModelCommandSuite) expectedModelInfo() coremigration.ModelInfo {
+	return coremigration.ModelInfo{
+		UUID:                   testing.ModelTag.Id(),
+		Owner:                  names.NewUserTag("admin"),
+		Name:                   "mymodel",
+		AgentVersion:           version.MustParse("2.9.0"),
+		ControllerAgentVersion: version.MustParse("2.9.1"),
+	}
+}
+
+func (s *ImportModelCommandSuite) TestImport(c *gc.C) {
+	ctx, err := s.runImport(c, s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Imported model \"mymodel\" owned by admin\n")
+	uuid := testing.ModelTag.Id()
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Prechecks", []interface{}{s.expectedModelInfo()}},
+		{"Import", nil},
+		{"UploadCharm", []interface{}{uuid, charm.MustParseURL("cs:focal/app-1"), "charm content"}},
+		{"UploadTools", []interface{}{uuid, version.MustParseBinary("2.9.0-focal-amd64"), "tools content"}},
+		{"CheckMachines", []interface{}{uuid}},
+		{"Activate", []interface{}{uuid}},
+		{"AdoptResources", []interface{}{uuid}},
+		{"Close", nil},
+	})
+	s.source.CheckCalls(c, []gitjujutesting.StubCall{
+		{"MigrationPhase", []interface{}{"mig-id"}},
+		{"SetMigrationTarget", []interface{}{"mig-id", s.expectedTarget()}},
+		{"MigrationPhase", []interface{}{"mig-id"}},
+		{"Close", nil},
+	})
+}
+
+func (s *ImportModelCommandSuite) TestImportWaitsForHandOver(c *gc.C) {
+	s.source.phases = []coremigration.Phase{
+		coremigration.IMPORT, coremigration.VALIDATION, coremigration.REAP,
+	}
+	done := make(chan error)
+	go func() {
+		_, err := s.runImport(c, s.path)
+		done <- err
+	}()
+	err := s.clock.WaitAdvance(2*time.Second, testing.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for import")
+	}
+	s.source.CheckCallNames(c, "MigrationPhase", "SetMigrationTarget", "MigrationPhase", "MigrationPhase", "Close")
+	s.fake.CheckCallNames(c,
+		"Prechecks", "Import", "UploadCharm", "UploadTools", "CheckMachines", "Activate", "AdoptResources", "Close",
+	)
+}
+
+func (s *ImportModelCommandSuite) TestImportHandOverAborted(c *gc.C) {
+	s.source.phases = []coremigration.Phase{coremigration.IMPORT, coremigration.ABORT}
+	_, err := s.runImport(c, s.path)
+	c.Assert(err, gc.ErrorMatches, "handing model over aborted: phase ABORT")
+	s.fake.CheckCallNames(c,
+		"Prechecks", "Import", "UploadCharm", "UploadTools", "CheckMachines", "Abort", "Close",
+	)
+}
+
+func (s *ImportModelCommandSuite) TestImportSourceNotWaiting(c *gc.C) {
+	s.source.phases = []coremigration.Phase{coremigration.ABORTDONE}
+	_, err := s.runImport(c, s.path)
+	c.Assert(err, gc.ErrorMatches, `model export on controller "source" is in the ABORTDONE phase, not waiting to be imported`)
+	s.source.CheckCallNames(c, "MigrationPhase", "Close")
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *ImportModelCommandSuite) TestImportSourceControllerUnknown(c *gc.C) {
+	delete(s.store.Controllers, "source")
+	_, err := s.runImport(c, s.path)
+	c.Assert(err, gc.ErrorMatches, "controller "+sourceControllerUUID+" the model was exported from not found")
+	s.source.CheckNoCalls(c)
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *ImportModelCommandSuite) TestImportPrechecksFail(c *gc.C) {
+	s.fake.SetErrors(errors.New("model already exists"))
+	_, err := s.runImport(c, s.path)
+	c.Assert(err, gc.ErrorMatches, "controller prechecks failed: model already exists")
+	s.fake.CheckCallNames(c, "Prechecks", "Close")
+	s.source.CheckCallNames(c, "MigrationPhase", "Close")
+}
+
+func (s *ImportModelCommandSuite) TestImportMachineCheckFailAborts(c *gc.C) {
+	s.fake.machineErrs = []error{errors.New("machine 0 not found")}
+	_, err := s.runImport(c, s.path)
+	c.Assert(err, gc.ErrorMatches, `machine sanity check failed, 1 error\(s\) found`)
+	c.Assert(c.GetTestLog(), jc.Contains, "WARNING cmd machine 0 not found")
+	s.fake.CheckCallNames(c, "Prechecks", "Import", "UploadCharm", "UploadTools", "CheckMachines", "Abort", "Close")
+	s.source.CheckCallNames(c, "MigrationPhase", "Close")
+}
+
+func (s *ImportModelCommandSuite) TestImportInvalidArchive(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bad.zip")
+	err := ioutil.WriteFile(path, []byte("not an archive"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.runImport(c, path)
+	c.Assert(err, gc.ErrorMatches, "opening model archive: .*")
+	s.fake.CheckNoCalls(c)
+}
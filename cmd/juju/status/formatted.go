@@ -42,6 +42,7 @@ type modelStatus struct {
 	CloudRegion      string             `json:"region,omitempty" yaml:"region,omitempty"`
 	Version          string             `json:"version" yaml:"version"`
 	AvailableVersion string             `json:"upgrade-available,omitempty" yaml:"upgrade-available,omitempty"`
+	AgentVersions    map[string]int     `json:"agent-versions,omitempty" yaml:"agent-versions,omitempty"`
 	Status           statusInfoContents `json:"model-status,omitempty" yaml:"model-status,omitempty"`
 	MeterStatus      *meterStatus       `json:"meter-status,omitempty" yaml:"meter-status,omitempty"`
 	SLA              string             `json:"sla,omitempty" yaml:"sla,omitempty"`
@@ -78,6 +79,7 @@ type machineStatus struct {
 	HAStatus           string                        `json:"controller-member-status,omitempty" yaml:"controller-member-status,omitempty"`
 	HAPrimary          bool                          `json:"ha-primary,omitempty" yaml:"ha-primary,omitempty"`
 	LXDProfiles        map[string]lxdProfileContents `json:"lxd-profiles,omitempty" yaml:"lxd-profiles,omitempty"`
+	UpgradingTo        string                        `json:"upgrading-to,omitempty" yaml:"upgrading-to,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
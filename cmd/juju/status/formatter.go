@@ -93,6 +93,7 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 			CloudRegion:      sf.status.Model.CloudRegion,
 			Version:          sf.status.Model.Version,
 			AvailableVersion: sf.status.Model.AvailableVersion,
+			AgentVersions:    mixedAgentVersions(sf.status),
 			Status:           sf.getStatusInfoContents(sf.status.Model.ModelStatus),
 			SLA:              sf.status.Model.SLA,
 		},
@@ -149,6 +150,41 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 	return out, nil
 }
 
+// mixedAgentVersions returns the number of machine and unit agents
+// running each agent version, or nil if all agents that have reported
+// a version are running the same one. Mixed versions are expected while
+// an upgrade is in progress, especially one staged across machines.
+func mixedAgentVersions(fs *params.FullStatus) map[string]int {
+	versions := make(map[string]int)
+	count := func(s params.DetailedStatus) {
+		if s.Version != "" {
+			versions[s.Version]++
+		}
+	}
+	var countMachines func(map[string]params.MachineStatus)
+	countMachines = func(machines map[string]params.MachineStatus) {
+		for _, m := range machines {
+			count(m.AgentStatus)
+			countMachines(m.Containers)
+		}
+	}
+	var countUnits func(map[string]params.UnitStatus)
+	countUnits = func(units map[string]params.UnitStatus) {
+		for _, u := range units {
+			count(u.AgentStatus)
+			countUnits(u.Subordinates)
+		}
+	}
+	countMachines(fs.Machines)
+	for _, app := range fs.Applications {
+		countUnits(app.Units)
+	}
+	if len(versions) < 2 {
+		return nil
+	}
+	return versions
+}
+
 // MachineFormat takes stored model information (params.FullStatus) and formats machine status info.
 func (sf *statusFormatter) MachineFormat(machineId []string) formattedMachineStatus {
 	if sf.status == nil {
@@ -190,6 +226,7 @@ func (sf *statusFormatter) formatMachine(machine params.MachineStatus) machineSt
 		Constraints:        machine.Constraints,
 		Hardware:           machine.Hardware,
 		LXDProfiles:        make(map[string]lxdProfileContents),
+		UpgradingTo:        machine.TargetAgentVersion,
 	}
 
 	for k, d := range machine.NetworkInterfaces {
@@ -383,6 +383,12 @@ func getModelMessage(model modelStatus) string {
 	switch {
 	case model.Status.Message != "":
 		return model.Status.Message
+	case len(model.AgentVersions) > 1:
+		var versions []string
+		for _, v := range naturalsort.Sort(stringKeysFromMap(model.AgentVersions)) {
+			versions = append(versions, fmt.Sprintf("%s (%d)", v, model.AgentVersions[v]))
+		}
+		return "upgrade in progress: " + strings.Join(versions, ", ")
 	case model.AvailableVersion != "":
 		return "upgrade available: " + model.AvailableVersion
 	default:
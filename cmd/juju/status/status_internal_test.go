@@ -5240,6 +5240,70 @@ foo/0  waiting   idle                            80/TCP
 `[1:])
 }
 
+func (s *StatusSuite) TestFormatTabularUpgradeInProgress(c *gc.C) {
+	fullStatus := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			Name:     "default",
+			CloudTag: "cloud-dummy",
+			Version:  "2.8.0",
+		},
+		Machines: map[string]params.MachineStatus{
+			"0": {
+				Id:                 "0",
+				AgentStatus:        params.DetailedStatus{Status: "started", Version: "2.9.0"},
+				TargetAgentVersion: "2.9.0",
+			},
+			"1": {
+				Id:          "1",
+				AgentStatus: params.DetailedStatus{Status: "started", Version: "2.8.0"},
+			},
+		},
+		Applications: map[string]params.ApplicationStatus{
+			"foo": {
+				Units: map[string]params.UnitStatus{
+					"foo/0": {
+						AgentStatus: params.DetailedStatus{Status: "idle", Version: "2.9.0"},
+					},
+				},
+			},
+		},
+	}
+	formatter := newStatusFormatter(newStatusFormatterParams{
+		status:         fullStatus,
+		controllerName: "kontroll",
+	})
+	fStatus, err := formatter.format()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fStatus.Model.AgentVersions, jc.DeepEquals, map[string]int{"2.8.0": 1, "2.9.0": 2})
+	c.Check(fStatus.Machines["0"].UpgradingTo, gc.Equals, "2.9.0")
+	c.Check(fStatus.Machines["1"].UpgradingTo, gc.Equals, "")
+
+	out := &bytes.Buffer{}
+	// Only the model section is of interest here.
+	err = FormatTabular(out, false, formattedStatus{Model: fStatus.Model})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.String(), gc.Equals, `
+Model    Controller  Cloud/Region  Version  Notes
+default  kontroll    dummy         2.8.0    upgrade in progress: 2.8.0 (1), 2.9.0 (2)
+`[1:])
+}
+
+func (s *StatusSuite) TestFormatSingleAgentVersion(c *gc.C) {
+	fullStatus := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			CloudTag: "cloud-dummy",
+			Version:  "2.8.0",
+		},
+		Machines: map[string]params.MachineStatus{
+			"0": {AgentStatus: params.DetailedStatus{Version: "2.8.0"}},
+			"1": {},
+		},
+	}
+	fStatus, err := newStatusFormatter(newStatusFormatterParams{status: fullStatus}).format()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fStatus.Model.AgentVersions, gc.IsNil)
+}
+
 func (s *StatusSuite) TestStatusWithNilStatusAPI(c *gc.C) {
 	ctx := s.newContext(c)
 	defer s.resetContext(c, ctx)
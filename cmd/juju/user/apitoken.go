@@ -0,0 +1,353 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/tokenmanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/permission"
+)
+
+var addAPITokenUsageSummary = `
+Issues an API token to a service account.`[1:]
+
+var addAPITokenUsageDetails = `
+A service account is an account for automation, such as a CI system.
+It has no password; instead it logs in with an API token issued to it.
+The token grants the given access (read, write or admin) on the given
+models until it expires, and nothing else. The service account comes
+into being with its first token, and can be referred to elsewhere as
+<name>@serviceaccount.
+
+The token is printed once and cannot be retrieved again. To use it,
+set the JUJU_API_TOKEN environment variable; juju commands then log
+in to the controller with the token instead of the account in the
+local client store, and leave that account untouched.
+
+Only controller superusers can manage API tokens.
+
+Examples:
+    juju add-api-token ci write mymodel
+    juju add-api-token ci read mymodel othermodel --expires-in 2h
+
+    JUJU_API_TOKEN=$(juju add-api-token ci write mymodel) juju status -m mymodel
+
+See also:
+    api-tokens
+    revoke-api-token`[1:]
+
+var listAPITokensUsageSummary = `
+Lists the API tokens issued to service accounts.`[1:]
+
+var listAPITokensUsageDetails = `
+Lists the API tokens issued to the given service account, or to all
+service accounts if none is given. Token values are never shown.
+
+Examples:
+    juju api-tokens
+    juju api-tokens ci --format yaml
+
+See also:
+    add-api-token
+    revoke-api-token`[1:]
+
+var revokeAPITokenUsageSummary = `
+Revokes API tokens issued to service accounts.`[1:]
+
+var revokeAPITokenUsageDetails = `
+A revoked token can no longer be used to log in. Connections already
+made with the token are not closed.
+
+Examples:
+    juju revoke-api-token 0f3c8e6a2b1d4c5e
+
+See also:
+    add-api-token
+    api-tokens`[1:]
+
+// TokenAPI defines the tokenmanager API methods that the API token
+// commands use.
+type TokenAPI interface {
+	AddAPIToken(account, access string, expires time.Time, models ...names.ModelTag) (string, *params.APITokenInfo, error)
+	APITokens(account string) ([]params.APITokenInfo, error)
+	RevokeAPIToken(ids ...string) error
+	Close() error
+}
+
+// tokenCommandBase holds the fields shared by the API token commands.
+type tokenCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api TokenAPI
+}
+
+func (c *tokenCommandBase) getAPI() (TokenAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tokenmanager.NewClient(root), nil
+}
+
+// serviceAccountArg validates and returns the service account name
+// from the command line arguments.
+func serviceAccountArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no service account specified")
+	}
+	if !names.IsValidUserName(args[0]) {
+		return "", errors.NotValidf("service account name %q", args[0])
+	}
+	return args[0], nil
+}
+
+// NewAddAPITokenCommand returns a command to issue an API token.
+func NewAddAPITokenCommand() cmd.Command {
+	return modelcmd.WrapController(&addAPITokenCommand{clock: clock.WallClock})
+}
+
+// addAPITokenCommand issues an API token to a service account.
+type addAPITokenCommand struct {
+	tokenCommandBase
+	clock clock.Clock
+
+	Account    string
+	Access     string
+	ModelNames []string
+	ExpiresIn  time.Duration
+}
+
+// Info implements Command.Info.
+func (c *addAPITokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-api-token",
+		Args:    "<service account> <access> <model name> ...",
+		Purpose: addAPITokenUsageSummary,
+		Doc:     addAPITokenUsageDetails,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addAPITokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.tokenCommandBase.SetFlags(f)
+	f.DurationVar(&c.ExpiresIn, "expires-in", 24*time.Hour, "How long the token is valid for")
+}
+
+// Init implements Command.Init.
+func (c *addAPITokenCommand) Init(args []string) (err error) {
+	if c.Account, err = serviceAccountArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) < 2 {
+		return errors.New("no access level specified")
+	}
+	if err := permission.ValidateModelAccess(permission.Access(args[1])); err != nil {
+		return errors.Trace(err)
+	}
+	c.Access = args[1]
+	if len(args) < 3 {
+		return errors.New("no models specified")
+	}
+	c.ModelNames = args[2:]
+	if c.ExpiresIn <= 0 {
+		return errors.NotValidf("expiry %v", c.ExpiresIn)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addAPITokenCommand) Run(ctx *cmd.Context) error {
+	modelUUIDs, err := c.ModelUUIDs(c.ModelNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	models := make([]names.ModelTag, len(modelUUIDs))
+	for i, uuid := range modelUUIDs {
+		models[i] = names.NewModelTag(uuid)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	expires := c.clock.Now().Add(c.ExpiresIn)
+	token, info, err := api.AddAPIToken(c.Account, c.Access, expires, models...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintln(ctx.Stdout, token)
+	ctx.Infof("API token %s issued to %s, expiring %s.", info.ID, c.Account, info.Expires.Format(time.RFC3339))
+	ctx.Infof("The token will not be shown again.")
+	return nil
+}
+
+// NewListAPITokensCommand returns a command to list API tokens.
+func NewListAPITokensCommand() cmd.Command {
+	return modelcmd.WrapController(&listAPITokensCommand{})
+}
+
+// listAPITokensCommand lists the API tokens issued to service
+// accounts.
+type listAPITokensCommand struct {
+	tokenCommandBase
+	out cmd.Output
+
+	Account string
+}
+
+// Info implements Command.Info.
+func (c *listAPITokensCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "api-tokens",
+		Args:    "[<service account>]",
+		Purpose: listAPITokensUsageSummary,
+		Doc:     listAPITokensUsageDetails,
+		Aliases: []string{"list-api-tokens"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAPITokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.tokenCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAPITokensTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listAPITokensCommand) Init(args []string) (err error) {
+	if len(args) > 0 {
+		if c.Account, err = serviceAccountArg(args); err != nil {
+			return errors.Trace(err)
+		}
+		return cmd.CheckEmpty(args[1:])
+	}
+	return nil
+}
+
+// APITokenInfo holds the details of an API token for output.
+type APITokenInfo struct {
+	ID          string   `yaml:"id" json:"id"`
+	Account     string   `yaml:"account" json:"account"`
+	Access      string   `yaml:"access" json:"access"`
+	Models      []string `yaml:"models" json:"models"`
+	CreatedBy   string   `yaml:"created-by" json:"created-by"`
+	DateCreated string   `yaml:"date-created" json:"date-created"`
+	Expires     string   `yaml:"expires" json:"expires"`
+}
+
+// Run implements Command.Run.
+func (c *listAPITokensCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tokens, err := api.APITokens(c.Account)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]APITokenInfo, len(tokens))
+	for i, t := range tokens {
+		result[i] = APITokenInfo{
+			ID:          t.ID,
+			Account:     t.Account,
+			Access:      t.Access,
+			CreatedBy:   t.CreatedBy,
+			DateCreated: t.DateCreated.Format(time.RFC3339),
+			Expires:     t.Expires.Format(time.RFC3339),
+		}
+		for _, modelTag := range t.ModelTags {
+			tag, err := names.ParseModelTag(modelTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			result[i].Models = append(result[i].Models, tag.Id())
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatAPITokensTabular(writer io.Writer, value interface{}) error {
+	tokens, ok := value.([]APITokenInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	if len(tokens) == 0 {
+		fmt.Fprintln(writer, "No API tokens to display.")
+		return nil
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Account", "Access", "Models", "Expires")
+	for _, t := range tokens {
+		w.Println(t.ID, t.Account, t.Access, strings.Join(t.Models, ", "), t.Expires)
+	}
+	return tw.Flush()
+}
+
+// NewRevokeAPITokenCommand returns a command to revoke API tokens.
+func NewRevokeAPITokenCommand() cmd.Command {
+	return modelcmd.WrapController(&revokeAPITokenCommand{})
+}
+
+// revokeAPITokenCommand revokes API tokens.
+type revokeAPITokenCommand struct {
+	tokenCommandBase
+	IDs []string
+}
+
+// Info implements Command.Info.
+func (c *revokeAPITokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "revoke-api-token",
+		Args:    "<token id> ...",
+		Purpose: revokeAPITokenUsageSummary,
+		Doc:     revokeAPITokenUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *revokeAPITokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no API token IDs specified")
+	}
+	c.IDs = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *revokeAPITokenCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RevokeAPIToken(c.IDs...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type APITokenCommandSuite struct {
+	BaseSuite
+	mockAPI *mockTokenAPI
+	clock   *testclock.Clock
+}
+
+var _ = gc.Suite(&APITokenCommandSuite{})
+
+func (s *APITokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockTokenAPI{}
+	s.clock = testclock.NewClock(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/mymodel": {ModelUUID: testing.ModelTag.Id()},
+		},
+	}
+}
+
+func (s *APITokenCommandSuite) TestAddAPIToken(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c,
+		user.NewAddAPITokenCommandForTest(s.mockAPI, s.clock, s.store),
+		"ci", "write", "mymodel", "--expires-in", "2h",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{
+		"AddAPIToken ci write 2020-06-01T14:00:00Z " + testing.ModelTag.String(),
+	})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "jujutoken.abc.secret\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"API token abc issued to ci, expiring 2020-06-01T14:00:00Z.\n"+
+		"The token will not be shown again.\n")
+}
+
+func (s *APITokenCommandSuite) TestAddAPITokenInit(c *gc.C) {
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service account specified",
+	}, {
+		args: []string{"c i"},
+		err:  `service account name "c i" not valid`,
+	}, {
+		args: []string{"ci"},
+		err:  "no access level specified",
+	}, {
+		args: []string{"ci", "superuser", "mymodel"},
+		err:  `"superuser" model access not valid`,
+	}, {
+		args: []string{"ci", "read"},
+		err:  "no models specified",
+	}, {
+		args: []string{"ci", "read", "mymodel", "--expires-in", "-1h"},
+		err:  "expiry -1h0m0s not valid",
+	}} {
+		_, err := cmdtesting.RunCommand(c, user.NewAddAPITokenCommandForTest(s.mockAPI, s.clock, s.store), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *APITokenCommandSuite) TestAddAPITokenError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, user.NewAddAPITokenCommandForTest(s.mockAPI, s.clock, s.store), "ci", "read", "mymodel")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *APITokenCommandSuite) TestListAPITokens(c *gc.C) {
+	s.mockAPI.tokens = []params.APITokenInfo{{
+		ID:          "abc",
+		Account:     "ci",
+		ModelTags:   []string{testing.ModelTag.String()},
+		Access:      "write",
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+		Expires:     time.Date(2020, 6, 2, 12, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListAPITokensCommandForTest(s.mockAPI, s.store), "ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"APITokens ci"})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"ID   Account  Access  Models                                Expires\n"+
+		"abc  ci       write   "+testing.ModelTag.Id()+"  2020-06-02T12:00:00Z\n"+
+		"\n")
+}
+
+func (s *APITokenCommandSuite) TestListAPITokensEmpty(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewListAPITokensCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"APITokens "})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No API tokens to display.\n\n")
+}
+
+func (s *APITokenCommandSuite) TestRevokeAPIToken(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRevokeAPITokenCommandForTest(s.mockAPI, s.store), "abc", "def")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"RevokeAPIToken abc def"})
+}
+
+func (s *APITokenCommandSuite) TestRevokeAPITokenInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRevokeAPITokenCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, "no API token IDs specified")
+}
+
+type mockTokenAPI struct {
+	err    error
+	calls  []string
+	tokens []params.APITokenInfo
+}
+
+func (*mockTokenAPI) Close() error { return nil }
+
+func (m *mockTokenAPI) record(call string, args ...string) error {
+	m.calls = append(m.calls, strings.Join(append([]string{call}, args...), " "))
+	return m.err
+}
+
+func (m *mockTokenAPI) AddAPIToken(account, access string, expires time.Time, models ...names.ModelTag) (string, *params.APITokenInfo, error) {
+	args := []string{account, access, expires.Format(time.RFC3339)}
+	for _, model := range models {
+		args = append(args, model.String())
+	}
+	if err := m.record("AddAPIToken", args...); err != nil {
+		return "", nil, err
+	}
+	return "jujutoken.abc.secret", &params.APITokenInfo{ID: "abc", Account: account, Expires: expires}, nil
+}
+
+func (m *mockTokenAPI) APITokens(account string) ([]params.APITokenInfo, error) {
+	return m.tokens, m.record("APITokens", account)
+}
+
+func (m *mockTokenAPI) RevokeAPIToken(ids ...string) error {
+	return m.record("RevokeAPIToken", ids...)
+}
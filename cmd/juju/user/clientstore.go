@@ -0,0 +1,308 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+)
+
+var encryptStoreUsageSummary = `
+Encrypts the credentials and passwords in the local client store.`[1:]
+
+var encryptStoreUsageDetails = `
+By default, the cloud credentials (credentials.yaml) and controller
+account passwords (accounts.yaml) in the Juju data directory are
+stored in plaintext, protected only by file permissions. This command
+converts them to an encrypted store, using a key derived from a
+passphrase. The passphrase itself is never stored.
+
+Once the store is encrypted, it must be unlocked before juju can use
+the credentials and passwords in it; see unlock-store.
+
+Examples:
+    juju encrypt-store
+
+See also:
+    unlock-store
+    lock-store`[1:]
+
+var unlockStoreUsageSummary = `
+Unlocks an encrypted local client store for the session.`[1:]
+
+var unlockStoreUsageDetails = `
+The passphrase of the encrypted client store is checked and the key
+derived from it is held by a store agent process, in the manner of
+ssh-agent, so that juju commands run in the session can use the store
+without asking for the passphrase again. The agent stops, locking the
+store, when it times out or when lock-store is run.
+
+The command prints the shell commands needed to point juju at the
+agent, so it is usually evaluated by the shell. With --foreground,
+the agent runs in the foreground instead of in the background.
+
+Examples:
+    eval $(juju unlock-store)
+    eval $(juju unlock-store --timeout 1h)
+
+See also:
+    encrypt-store
+    lock-store`[1:]
+
+var lockStoreUsageSummary = `
+Locks an encrypted local client store.`[1:]
+
+var lockStoreUsageDetails = `
+Stops the store agent started by unlock-store, so that the encrypted
+client store can no longer be used until it is unlocked again.
+
+Examples:
+    eval $(juju lock-store)
+
+See also:
+    unlock-store`[1:]
+
+// NewEncryptStoreCommand returns a command to encrypt the local
+// client store.
+func NewEncryptStoreCommand() cmd.Command {
+	return modelcmd.WrapBase(&encryptStoreCommand{})
+}
+
+// encryptStoreCommand converts a plaintext client store into an
+// encrypted one.
+type encryptStoreCommand struct {
+	modelcmd.CommandBase
+}
+
+// Info implements Command.Info.
+func (c *encryptStoreCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "encrypt-store",
+		Purpose: encryptStoreUsageSummary,
+		Doc:     encryptStoreUsageDetails,
+	})
+}
+
+// Run implements Command.Run.
+func (c *encryptStoreCommand) Run(ctx *cmd.Context) error {
+	encrypted, err := jujuclient.IsStoreEncrypted()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encrypted {
+		return errors.New("client store is already encrypted")
+	}
+	passphrase, err := readAndConfirmPassphrase(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := jujuclient.EncryptStore(passphrase); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Client store encrypted.")
+	ctx.Infof(`Run "eval $(juju unlock-store)" to use it.`)
+	return nil
+}
+
+// NewUnlockStoreCommand returns a command to unlock the local client
+// store.
+func NewUnlockStoreCommand() cmd.Command {
+	return modelcmd.WrapBase(&unlockStoreCommand{
+		clock:         clock.WallClock,
+		startAgent:    startStoreAgent,
+		storeAgentDir: defaultStoreAgentDir,
+	})
+}
+
+// unlockStoreCommand unlocks an encrypted client store, leaving a
+// store agent holding its key.
+type unlockStoreCommand struct {
+	modelcmd.CommandBase
+	clock clock.Clock
+
+	// startAgent starts a store agent in the background, returning
+	// the shell commands that point juju at it.
+	startAgent func(passphrase string, timeout time.Duration) (string, error)
+
+	// storeAgentDir returns a new directory, accessible only to
+	// the user, for the store agent's socket.
+	storeAgentDir func() (string, error)
+
+	Foreground bool
+	Timeout    time.Duration
+}
+
+// Info implements Command.Info.
+func (c *unlockStoreCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "unlock-store",
+		Purpose: unlockStoreUsageSummary,
+		Doc:     unlockStoreUsageDetails,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *unlockStoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.Foreground, "foreground", false, "Run the store agent in the foreground")
+	f.DurationVar(&c.Timeout, "timeout", 12*time.Hour, "Lock the store after this long; 0 means never")
+}
+
+// Init implements Command.Init.
+func (c *unlockStoreCommand) Init(args []string) error {
+	if c.Timeout < 0 {
+		return errors.NotValidf("timeout %v", c.Timeout)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *unlockStoreCommand) Run(ctx *cmd.Context) error {
+	fmt.Fprint(ctx.Stderr, "passphrase: ")
+	passphrase, err := readPassword(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key, err := jujuclient.UnlockStore(passphrase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !c.Foreground {
+		env, err := c.startAgent(passphrase, c.Timeout)
+		if err != nil {
+			return errors.Annotate(err, "cannot start store agent")
+		}
+		fmt.Fprint(ctx.Stdout, env)
+		return nil
+	}
+
+	dir, err := c.storeAgentDir()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "agent.sock")
+	agent, err := jujuclient.NewStoreAgent(socketPath, key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer agent.Close()
+	if c.Timeout > 0 {
+		timer := c.clock.AfterFunc(c.Timeout, func() { _ = agent.Close() })
+		defer timer.Stop()
+	}
+	fmt.Fprint(ctx.Stdout, storeAgentEnv(socketPath))
+	return errors.Trace(agent.Serve())
+}
+
+// storeAgentEnv returns the shell commands that point juju at the
+// store agent listening on the socket path.
+func storeAgentEnv(socketPath string) string {
+	return fmt.Sprintf("%s=%s; export %s;\n", osenv.JujuStoreAgentEnvKey, socketPath, osenv.JujuStoreAgentEnvKey)
+}
+
+func defaultStoreAgentDir() (string, error) {
+	dir, err := ioutil.TempDir("", "juju-store-")
+	return dir, errors.Trace(err)
+}
+
+// startStoreAgent runs "juju unlock-store --foreground" in the
+// background, handing it the passphrase, and returns the shell
+// commands it prints once its agent is listening.
+func startStoreAgent(passphrase string, timeout time.Duration) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	agentCmd := exec.Command(executable, "unlock-store", "--foreground", "--timeout", timeout.String())
+	agentCmd.Stdin = strings.NewReader(passphrase + "\n")
+	stdout, err := agentCmd.StdoutPipe()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := agentCmd.Start(); err != nil {
+		return "", errors.Trace(err)
+	}
+	env, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		_ = agentCmd.Process.Kill()
+		return "", errors.New("store agent exited before it was ready")
+	}
+	return env, nil
+}
+
+// NewLockStoreCommand returns a command to lock the local client
+// store.
+func NewLockStoreCommand() cmd.Command {
+	return modelcmd.WrapBase(&lockStoreCommand{})
+}
+
+// lockStoreCommand stops the store agent holding the key of an
+// encrypted client store.
+type lockStoreCommand struct {
+	modelcmd.CommandBase
+}
+
+// Info implements Command.Info.
+func (c *lockStoreCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "lock-store",
+		Purpose: lockStoreUsageSummary,
+		Doc:     lockStoreUsageDetails,
+	})
+}
+
+// Run implements Command.Run.
+func (c *lockStoreCommand) Run(ctx *cmd.Context) error {
+	socketPath := os.Getenv(osenv.JujuStoreAgentEnvKey)
+	if socketPath == "" {
+		return errors.Errorf("no store agent: %s is not set", osenv.JujuStoreAgentEnvKey)
+	}
+	if err := jujuclient.LockStoreAgent(socketPath); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "unset %s;\n", osenv.JujuStoreAgentEnvKey)
+	ctx.Infof("Client store locked.")
+	return nil
+}
+
+func readAndConfirmPassphrase(ctx *cmd.Context) (string, error) {
+	fmt.Fprint(ctx.Stderr, "passphrase: ")
+	passphrase, err := readPassword(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if passphrase == "" {
+		return "", errors.Errorf("you must enter a passphrase")
+	}
+
+	fmt.Fprint(ctx.Stderr, "type passphrase again: ")
+	verify, err := readPassword(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if passphrase != verify {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type ClientStoreCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&ClientStoreCommandSuite{})
+
+func (s *ClientStoreCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	jujuclient.SetStoreKey(nil)
+	s.AddCleanup(func(*gc.C) { jujuclient.SetStoreKey(nil) })
+	err := jujuclient.WriteAccountsFile(map[string]jujuclient.AccountDetails{
+		"ctrl": {User: "admin", Password: "hunter2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ClientStoreCommandSuite) run(c *gc.C, command cmd.Command, stdin string, args ...string) (*cmd.Context, error) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	if err := cmdtesting.InitCommand(command, args); err != nil {
+		return ctx, err
+	}
+	return ctx, command.Run(ctx)
+}
+
+func (s *ClientStoreCommandSuite) encryptStore(c *gc.C) {
+	err := jujuclient.EncryptStore("sekrit")
+	c.Assert(err, jc.ErrorIsNil)
+	jujuclient.SetStoreKey(nil)
+}
+
+func (s *ClientStoreCommandSuite) TestEncryptStore(c *gc.C) {
+	ctx, err := s.run(c, user.NewEncryptStoreCommandForTest(), "sekrit\nsekrit\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"passphrase: \n"+
+		"type passphrase again: \n"+
+		"Client store encrypted.\n"+
+		"Run \"eval $(juju unlock-store)\" to use it.\n")
+	encrypted, err := jujuclient.IsStoreEncrypted()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(encrypted, jc.IsTrue)
+}
+
+func (s *ClientStoreCommandSuite) TestEncryptStoreMismatch(c *gc.C) {
+	_, err := s.run(c, user.NewEncryptStoreCommandForTest(), "sekrit\nsecret\n")
+	c.Assert(err, gc.ErrorMatches, "passphrases do not match")
+	encrypted, err := jujuclient.IsStoreEncrypted()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(encrypted, jc.IsFalse)
+}
+
+func (s *ClientStoreCommandSuite) TestEncryptStoreAlreadyEncrypted(c *gc.C) {
+	s.encryptStore(c)
+	_, err := s.run(c, user.NewEncryptStoreCommandForTest(), "sekrit\nsekrit\n")
+	c.Assert(err, gc.ErrorMatches, "client store is already encrypted")
+}
+
+func (s *ClientStoreCommandSuite) TestUnlockStore(c *gc.C) {
+	s.encryptStore(c)
+	var gotPassphrase string
+	var gotTimeout time.Duration
+	startAgent := func(passphrase string, timeout time.Duration) (string, error) {
+		gotPassphrase, gotTimeout = passphrase, timeout
+		return "JUJU_STORE_AGENT=/tmp/agent.sock; export JUJU_STORE_AGENT;\n", nil
+	}
+	command := user.NewUnlockStoreCommandForTest(testclock.NewClock(time.Time{}), startAgent, nil)
+	ctx, err := s.run(c, command, "sekrit\n", "--timeout", "1h")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotPassphrase, gc.Equals, "sekrit")
+	c.Assert(gotTimeout, gc.Equals, time.Hour)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "JUJU_STORE_AGENT=/tmp/agent.sock; export JUJU_STORE_AGENT;\n")
+}
+
+func (s *ClientStoreCommandSuite) TestUnlockStoreIncorrectPassphrase(c *gc.C) {
+	s.encryptStore(c)
+	command := user.NewUnlockStoreCommandForTest(testclock.NewClock(time.Time{}), nil, nil)
+	_, err := s.run(c, command, "wrong\n")
+	c.Assert(err, gc.ErrorMatches, "incorrect passphrase")
+}
+
+func (s *ClientStoreCommandSuite) TestUnlockStoreForeground(c *gc.C) {
+	s.encryptStore(c)
+	dir := c.MkDir()
+	socketPath := filepath.Join(dir, "agent.sock")
+	storeAgentDir := func() (string, error) { return dir, nil }
+	command := user.NewUnlockStoreCommandForTest(testclock.NewClock(time.Time{}), nil, storeAgentDir)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("sekrit\n")
+	err := cmdtesting.InitCommand(command, []string{"--foreground"})
+	c.Assert(err, jc.ErrorIsNil)
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Run(ctx)
+	}()
+
+	// Wait for the agent to hand out the key.
+	var key []byte
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		if key, err = jujuclient.StoreAgentKey(socketPath); err == nil {
+			break
+		}
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, gc.HasLen, 32)
+
+	s.PatchEnvironment(osenv.JujuStoreAgentEnvKey, socketPath)
+	lockCtx, err := s.run(c, user.NewLockStoreCommandForTest(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(lockCtx), gc.Equals, "unset JUJU_STORE_AGENT;\n")
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("store agent did not stop")
+	}
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "JUJU_STORE_AGENT="+socketPath+"; export JUJU_STORE_AGENT;\n")
+}
+
+func (s *ClientStoreCommandSuite) TestLockStoreNoAgent(c *gc.C) {
+	_, err := s.run(c, user.NewLockStoreCommandForTest(), "")
+	c.Assert(err, gc.ErrorMatches, "no store agent: JUJU_STORE_AGENT is not set")
+}
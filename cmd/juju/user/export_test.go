@@ -4,6 +4,8 @@
 package user
 
 import (
+	"time"
+
 	"github.com/juju/clock"
 	"github.com/juju/cmd"
 
@@ -59,6 +61,97 @@ func NewRemoveCommandForTest(api RemoveUserAPI, store jujuclient.ClientStore) (c
 	return modelcmd.WrapController(c), &RemoveCommand{c}
 }
 
+func NewAddGroupCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addGroupCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRemoveGroupCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeGroupCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewAddToGroupCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &groupMembersCommand{add: true}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRemoveFromGroupCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &groupMembersCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewListGroupsCommandForTest(api GroupAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listGroupsCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewAddRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addRoleCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRemoveRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeRoleCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewAssignRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &assignRoleCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewUnassignRoleCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &unassignRoleCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewListRolesCommandForTest(api RoleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listRolesCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewAddAPITokenCommandForTest(api TokenAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	c := &addAPITokenCommand{clock: clock}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewListAPITokensCommandForTest(api TokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listAPITokensCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRevokeAPITokenCommandForTest(api TokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &revokeAPITokenCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 func NewShowUserCommandForTest(api UserInfoAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &infoCommand{infoCommandBase: infoCommandBase{
 		clock: clock.WallClock,
@@ -124,3 +217,23 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+func NewEncryptStoreCommandForTest() cmd.Command {
+	return modelcmd.WrapBase(&encryptStoreCommand{})
+}
+
+func NewUnlockStoreCommandForTest(
+	clock clock.Clock,
+	startAgent func(passphrase string, timeout time.Duration) (string, error),
+	storeAgentDir func() (string, error),
+) cmd.Command {
+	return modelcmd.WrapBase(&unlockStoreCommand{
+		clock:         clock,
+		startAgent:    startAgent,
+		storeAgentDir: storeAgentDir,
+	})
+}
+
+func NewLockStoreCommandForTest() cmd.Command {
+	return modelcmd.WrapBase(&lockStoreCommand{})
+}
@@ -0,0 +1,385 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var addGroupUsageSummary = `
+Adds a group of users to a controller.`[1:]
+
+var addGroupUsageDetails = `
+Groups collect users so that access to controllers, clouds, models
+and offers can be granted to all of them at once, with
+` + "`juju grant --group` and `juju grant-cloud --group`" + `.
+
+Only controller superusers can manage groups.
+
+Examples:
+    juju add-group engineers
+    juju add-to-group engineers alice bob
+    juju grant --group engineers write mymodel
+
+See also:
+    add-to-group
+    remove-from-group
+    remove-group
+    groups
+    grant`[1:]
+
+var removeGroupUsageSummary = `
+Removes a group of users from a controller.`[1:]
+
+var removeGroupUsageDetails = `
+Removing a group also removes all the access granted to the group.
+The members of the group keep any access granted to them directly.
+
+Examples:
+    juju remove-group engineers
+
+See also:
+    add-group
+    groups`[1:]
+
+var addToGroupUsageSummary = `
+Adds users to a group.`[1:]
+
+var addToGroupUsageDetails = `
+The users get all the access granted to the group, in addition
+to any access granted to them directly.
+
+Examples:
+    juju add-to-group engineers alice bob
+    juju add-to-group engineers carol@external
+
+See also:
+    add-group
+    remove-from-group`[1:]
+
+var removeFromGroupUsageSummary = `
+Removes users from a group.`[1:]
+
+var removeFromGroupUsageDetails = `
+The users lose the access granted to the group, but keep any access
+granted to them directly.
+
+Examples:
+    juju remove-from-group engineers bob
+
+See also:
+    add-to-group
+    groups`[1:]
+
+var listGroupsUsageSummary = `
+Lists the groups of users in a controller.`[1:]
+
+var listGroupsUsageDetails = `
+Controller superusers see all the groups; other users see only the
+groups they are a member of.
+
+Examples:
+    juju groups
+    juju groups --format yaml
+
+See also:
+    add-group
+    add-to-group`[1:]
+
+// GroupAPI defines the usermanager API methods that the group
+// commands use.
+type GroupAPI interface {
+	AddGroup(name string) error
+	RemoveGroup(name string) error
+	GroupInfo(groups ...string) ([]params.GroupInfo, error)
+	AddToGroup(group string, users ...string) error
+	RemoveFromGroup(group string, users ...string) error
+	Close() error
+}
+
+// groupCommandBase holds the fields shared by the group commands.
+type groupCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api GroupAPI
+}
+
+func (c *groupCommandBase) getAPI() (GroupAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// groupNameArg validates and returns the group name from the
+// command line arguments.
+func groupNameArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no group name specified")
+	}
+	if !names.IsValidUserName(args[0]) {
+		return "", errors.NotValidf("group name %q", args[0])
+	}
+	return args[0], nil
+}
+
+// NewAddGroupCommand returns a command to add a group.
+func NewAddGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&addGroupCommand{})
+}
+
+// addGroupCommand adds a group to a controller.
+type addGroupCommand struct {
+	groupCommandBase
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *addGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-group",
+		Args:    "<group name>",
+		Purpose: addGroupUsageSummary,
+		Doc:     addGroupUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *addGroupCommand) Init(args []string) (err error) {
+	if c.Group, err = groupNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.AddGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Group %q added", c.Group)
+	return nil
+}
+
+// NewRemoveGroupCommand returns a command to remove a group.
+func NewRemoveGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&removeGroupCommand{})
+}
+
+// removeGroupCommand removes a group from a controller.
+type removeGroupCommand struct {
+	groupCommandBase
+	Group string
+}
+
+// Info implements Command.Info.
+func (c *removeGroupCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-group",
+		Args:    "<group name>",
+		Purpose: removeGroupUsageSummary,
+		Doc:     removeGroupUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeGroupCommand) Init(args []string) (err error) {
+	if c.Group, err = groupNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeGroupCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveGroup(c.Group); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Group %q removed", c.Group)
+	return nil
+}
+
+// NewAddToGroupCommand returns a command to add users to a group.
+func NewAddToGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&groupMembersCommand{add: true})
+}
+
+// NewRemoveFromGroupCommand returns a command to remove users
+// from a group.
+func NewRemoveFromGroupCommand() cmd.Command {
+	return modelcmd.WrapController(&groupMembersCommand{})
+}
+
+// groupMembersCommand adds users to, or removes users from, a group.
+type groupMembersCommand struct {
+	groupCommandBase
+	add   bool
+	Group string
+	Users []string
+}
+
+// Info implements Command.Info.
+func (c *groupMembersCommand) Info() *cmd.Info {
+	info := &cmd.Info{
+		Name:    "remove-from-group",
+		Args:    "<group name> <user name> ...",
+		Purpose: removeFromGroupUsageSummary,
+		Doc:     removeFromGroupUsageDetails,
+	}
+	if c.add {
+		info.Name = "add-to-group"
+		info.Purpose = addToGroupUsageSummary
+		info.Doc = addToGroupUsageDetails
+	}
+	return jujucmd.Info(info)
+}
+
+// Init implements Command.Init.
+func (c *groupMembersCommand) Init(args []string) (err error) {
+	if c.Group, err = groupNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) < 2 {
+		return errors.New("no user names specified")
+	}
+	for _, user := range args[1:] {
+		if !names.IsValidUser(user) {
+			return errors.NotValidf("user name %q", user)
+		}
+	}
+	c.Users = args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *groupMembersCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if c.add {
+		err = api.AddToGroup(c.Group, c.Users...)
+	} else {
+		err = api.RemoveFromGroup(c.Group, c.Users...)
+	}
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// NewListGroupsCommand returns a command to list groups.
+func NewListGroupsCommand() cmd.Command {
+	return modelcmd.WrapController(&listGroupsCommand{})
+}
+
+// listGroupsCommand lists the groups in a controller.
+type listGroupsCommand struct {
+	groupCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *listGroupsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "groups",
+		Purpose: listGroupsUsageSummary,
+		Doc:     listGroupsUsageDetails,
+		Aliases: []string{"list-groups"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listGroupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.groupCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatGroupsTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listGroupsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// GroupInfo holds the details of a group for output.
+type GroupInfo struct {
+	Name        string   `yaml:"name" json:"name"`
+	CreatedBy   string   `yaml:"created-by" json:"created-by"`
+	DateCreated string   `yaml:"date-created" json:"date-created"`
+	Members     []string `yaml:"members,omitempty" json:"members,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *listGroupsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	groups, err := api.GroupInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]GroupInfo, len(groups))
+	for i, g := range groups {
+		result[i] = GroupInfo{
+			Name:        g.Name,
+			CreatedBy:   g.CreatedBy,
+			DateCreated: g.DateCreated.Format("2006-01-02"),
+		}
+		for _, member := range g.Members {
+			tag, err := names.ParseUserTag(member)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			result[i].Members = append(result[i].Members, tag.Id())
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatGroupsTabular(writer io.Writer, value interface{}) error {
+	groups, ok := value.([]GroupInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", groups, value)
+	}
+	if len(groups) == 0 {
+		fmt.Fprintln(writer, "No groups to display.")
+		return nil
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Name", "Created by", "Members")
+	for _, g := range groups {
+		w.Println(g.Name, g.CreatedBy, strings.Join(g.Members, ", "))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type GroupCommandSuite struct {
+	BaseSuite
+	mockAPI *mockGroupAPI
+}
+
+var _ = gc.Suite(&GroupCommandSuite{})
+
+func (s *GroupCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockGroupAPI{}
+}
+
+func (s *GroupCommandSuite) TestAddGroup(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"AddGroup engineers"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Group \"engineers\" added\n")
+}
+
+func (s *GroupCommandSuite) TestAddGroupInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, "no group name specified")
+	_, err = cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "not/valid")
+	c.Assert(err, gc.ErrorMatches, `group name "not/valid" not valid`)
+	_, err = cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "engineers", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *GroupCommandSuite) TestAddGroupError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, user.NewAddGroupCommandForTest(s.mockAPI, s.store), "engineers")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *GroupCommandSuite) TestRemoveGroup(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRemoveGroupCommandForTest(s.mockAPI, s.store), "engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"RemoveGroup engineers"})
+}
+
+func (s *GroupCommandSuite) TestAddToGroup(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddToGroupCommandForTest(s.mockAPI, s.store), "engineers", "alice", "bob@external")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"AddToGroup engineers alice bob@external"})
+}
+
+func (s *GroupCommandSuite) TestAddToGroupNoUsers(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddToGroupCommandForTest(s.mockAPI, s.store), "engineers")
+	c.Assert(err, gc.ErrorMatches, "no user names specified")
+}
+
+func (s *GroupCommandSuite) TestRemoveFromGroup(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRemoveFromGroupCommandForTest(s.mockAPI, s.store), "engineers", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"RemoveFromGroup engineers bob"})
+}
+
+func (s *GroupCommandSuite) TestListGroups(c *gc.C) {
+	s.mockAPI.groups = []params.GroupInfo{{
+		Name:        "engineers",
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		Members:     []string{"user-alice", "user-bob@external"},
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListGroupsCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Name       Created by  Members\n"+
+		"engineers  admin       alice, bob@external\n"+
+		"\n")
+}
+
+func (s *GroupCommandSuite) TestListGroupsYAML(c *gc.C) {
+	s.mockAPI.groups = []params.GroupInfo{{
+		Name:        "engineers",
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		Members:     []string{"user-alice"},
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListGroupsCommandForTest(s.mockAPI, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- name: engineers
+  created-by: admin
+  date-created: "2020-03-01"
+  members:
+  - alice
+`[1:])
+}
+
+func (s *GroupCommandSuite) TestListGroupsEmpty(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewListGroupsCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No groups to display.\n\n")
+}
+
+type mockGroupAPI struct {
+	err    error
+	calls  []string
+	groups []params.GroupInfo
+}
+
+func (*mockGroupAPI) Close() error { return nil }
+
+func (m *mockGroupAPI) record(call string, args ...string) error {
+	m.calls = append(m.calls, strings.Join(append([]string{call}, args...), " "))
+	return m.err
+}
+
+func (m *mockGroupAPI) AddGroup(name string) error {
+	return m.record("AddGroup", name)
+}
+
+func (m *mockGroupAPI) RemoveGroup(name string) error {
+	return m.record("RemoveGroup", name)
+}
+
+func (m *mockGroupAPI) GroupInfo(groups ...string) ([]params.GroupInfo, error) {
+	return m.groups, m.record("GroupInfo", groups...)
+}
+
+func (m *mockGroupAPI) AddToGroup(group string, users ...string) error {
+	return m.record("AddToGroup", append([]string{group}, users...)...)
+}
+
+func (m *mockGroupAPI) RemoveFromGroup(group string, users ...string) error {
+	return m.record("RemoveFromGroup", append([]string{group}, users...)...)
+}
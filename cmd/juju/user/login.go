@@ -5,6 +5,7 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/juju/clock"
 	"github.com/juju/cmd"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -52,6 +54,12 @@ except verify that fact.
 If the -u option is provided, the juju login command will attempt to log
 into the controller as that user.
 
+If the controller authenticates users with an OpenID Connect issuer,
+the juju login command prints a URL and a code; visit the URL and
+enter the code to log in. The resulting ID token is stored with the
+account and used until it expires, after which juju login must be
+run again.
+
 After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
@@ -362,9 +370,13 @@ func (c *loginCommand) publicControllerLogin(
 			dialOpts.BakeryClient.AddInteractor(i)
 		}
 
+		password := d.Password
+		if d.IDToken != "" {
+			password = d.IDToken
+		}
 		return apiOpen(&c.CommandBase, &api.Info{
 			Tag:      tag,
-			Password: d.Password,
+			Password: password,
 			Addrs:    []string{host},
 		}, dialOpts)
 	}
@@ -407,14 +419,14 @@ Run "juju logout" first before attempting to log in as a different user.`,
 			accountDetails.User)
 	}
 
-	if accountDetails != nil && accountDetails.Password != "" {
+	if accountDetails != nil && (accountDetails.Password != "" || accountDetails.IDToken != "") {
 		// We've been provided some account details that
-		// contain a password, so try that first.
+		// contain a password or ID token, so try that first.
 		conn, err := dial(accountDetails)
 		if err == nil {
 			return conn, accountDetails, nil
 		}
-		if !errors.IsUnauthorized(err) {
+		if !errors.IsUnauthorized(err) && !params.IsCodeLoginExpired(err) {
 			return nil, nil, errors.Trace(err)
 		}
 	}
@@ -431,6 +443,9 @@ Run "juju logout" first before attempting to log in as a different user.`,
 				User: user.Id(),
 			}, nil
 		}
+		if oidcErr, ok := errors.Cause(err).(*api.OIDCLoginRequiredError); ok {
+			return c.oidcLogin(ctx, oidcErr, dial)
+		}
 		if !params.IsCodeNoCreds(err) {
 			return nil, nil, errors.Trace(err)
 		}
@@ -461,6 +476,43 @@ Run "juju logout" first before attempting to log in as a different user.`,
 	return conn, accountDetails, errors.Trace(err)
 }
 
+// oidcLogin obtains an ID token from the OpenID Connect issuer named by
+// the controller, using the device authorization grant, and logs in
+// with it.
+func (c *loginCommand) oidcLogin(
+	ctx *cmd.Context,
+	oidcErr *api.OIDCLoginRequiredError,
+	dial func(*jujuclient.AccountDetails) (api.Connection, error),
+) (api.Connection, *jujuclient.AccountDetails, error) {
+	deviceLogin := &authentication.OIDCDeviceLogin{
+		IssuerURL: oidcErr.IssuerURL,
+		ClientID:  oidcErr.ClientID,
+		Clock:     clock.WallClock,
+		Prompt: func(auth authentication.DeviceAuthorization) error {
+			_, err := fmt.Fprintf(ctx.Stderr, "To log in, visit %s and enter the code %s\n",
+				auth.VerificationURI, auth.UserCode)
+			return err
+		},
+	}
+	token, err := deviceLogin.IDToken(context.Background())
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot log in with OpenID Connect")
+	}
+	conn, err := dial(&jujuclient.AccountDetails{IDToken: token})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	user, ok := conn.AuthTag().(names.UserTag)
+	if !ok {
+		conn.Close()
+		return nil, nil, errors.Errorf("logged in as %v, not a user", conn.AuthTag())
+	}
+	return conn, &jujuclient.AccountDetails{
+		User:    user.Id(),
+		IDToken: token,
+	}, nil
+}
+
 const badCred = "invalid entity name or password"
 
 const noModelsMessage = `
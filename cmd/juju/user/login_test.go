@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/pki"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/oidctest"
 )
 
 type LoginCommandSuite struct {
@@ -262,6 +263,44 @@ There are no models available(.|\n)*`[1:])
 	c.Assert(code, gc.Equals, 0)
 }
 
+func (s *LoginCommandSuite) TestLoginWithOIDC(c *gc.C) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	issuer.ApproveDevice(0, issuer.Claims("alice", "staff"))
+
+	err := s.store.RemoveAccount("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	s.apiConnection.authTag = names.NewUserTag("alice@oidc")
+	var token string
+	*user.NewAPIConnection = func(p juju.NewAPIConnectionParams) (api.Connection, error) {
+		if !c.Check(p.AccountDetails, gc.NotNil) {
+			return nil, errors.New("no account details")
+		}
+		if p.AccountDetails.IDToken == "" {
+			return nil, &api.OIDCLoginRequiredError{
+				IssuerURL: issuer.URL,
+				ClientID:  oidctest.ClientID,
+			}
+		}
+		token = p.AccountDetails.IDToken
+		return s.apiConnection, nil
+	}
+	stdout, stderr, code := runLogin(c, "")
+	c.Check(stdout, gc.Equals, ``)
+	c.Check(stderr, gc.Matches, `
+To log in, visit .*/activate and enter the code ABCD-EFGH
+Welcome, alice@oidc. You are now logged into "testing".
+
+There are no models available(.|\n)*`[1:])
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(token, gc.Not(gc.Equals), "")
+
+	account, err := s.store.AccountDetails("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(account.User, gc.Equals, "alice@oidc")
+	c.Assert(account.IDToken, gc.Equals, token)
+}
+
 func (s *LoginCommandSuite) TestLoginWithCAVerification(c *gc.C) {
 	caCert := testing.CACertX509
 	fingerprint, _, err := pki.Fingerprint([]byte(testing.CACert))
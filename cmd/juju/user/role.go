@@ -0,0 +1,494 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/rolemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/permission"
+)
+
+var addRoleUsageSummary = `
+Adds a custom role to a controller.`[1:]
+
+var addRoleUsageDetails = `
+A custom role restricts the API calls that the users it is assigned
+to can make. It never grants more than the access the users already
+have to a model or controller; instead it limits which operations
+that access can be used for.
+
+A role is made up of one or more permissions. Each permission is
+either a capability or a facade method pattern of the form
+<facade>.<method>, where a "*" at the start or end of either part
+matches anything, e.g. "Application.Destroy*" or "Cloud.*Credential*".
+Requests to the controller's HTTP endpoints, such as debug-log and
+charm or resource uploads, are matched as methods of the "HTTP"
+facade named by the request method and endpoint, e.g. "HTTP.GetDebugLog"
+or "HTTP.PostCharms".
+
+The available capabilities are:
+` + "    " + strings.Join(permission.Capabilities(), "\n    ") + `
+
+Only controller superusers can manage roles.
+
+Examples:
+    juju add-role operator view run-actions ssh
+    juju add-role deployer view deploy configure remove
+    juju assign-role alice operator mymodel
+
+See also:
+    assign-role
+    remove-role
+    roles`[1:]
+
+var removeRoleUsageSummary = `
+Removes a custom role from a controller.`[1:]
+
+var removeRoleUsageDetails = `
+Removing a role also removes all its assignments to users, who are
+then no longer restricted by it.
+
+Examples:
+    juju remove-role operator
+
+See also:
+    add-role
+    roles`[1:]
+
+var assignRoleUsageSummary = `
+Assigns a custom role to a user on a model or controller.`[1:]
+
+var assignRoleUsageDetails = `
+The role restricts the API calls that the user can make when
+connected to the model, or to the controller if no model is given.
+A role assigned on a model takes precedence over a role assigned on
+the controller. Any role previously assigned to the user in the same
+place is replaced. The new role takes effect the next time the user
+connects.
+
+Examples:
+    juju assign-role alice operator mymodel
+    juju assign-role bob deployer
+
+See also:
+    add-role
+    unassign-role`[1:]
+
+var unassignRoleUsageSummary = `
+Removes the custom role assigned to a user on a model or controller.`[1:]
+
+var unassignRoleUsageDetails = `
+Examples:
+    juju unassign-role alice mymodel
+    juju unassign-role bob
+
+See also:
+    assign-role
+    roles`[1:]
+
+var listRolesUsageSummary = `
+Lists the custom roles in a controller.`[1:]
+
+var listRolesUsageDetails = `
+Controller superusers also see the users that each role is assigned to.
+
+Examples:
+    juju roles
+    juju roles --format yaml
+
+See also:
+    add-role
+    assign-role`[1:]
+
+// RoleAPI defines the rolemanager API methods that the role commands
+// use.
+type RoleAPI interface {
+	AddRole(name string, permissions ...string) error
+	RemoveRole(names ...string) error
+	Roles(roles ...string) ([]params.RoleInfo, error)
+	AssignRole(user, role string, target names.Tag) error
+	UnassignRole(user string, target names.Tag) error
+	Close() error
+}
+
+// roleCommandBase holds the fields shared by the role commands.
+type roleCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api RoleAPI
+}
+
+func (c *roleCommandBase) getAPI() (RoleAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rolemanager.NewClient(root), nil
+}
+
+// roleTarget returns the tag of the named model, or of the current
+// controller if no model is named.
+func (c *roleCommandBase) roleTarget(modelName string) (names.Tag, error) {
+	if modelName != "" {
+		models, err := c.ModelUUIDs([]string{modelName})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return names.NewModelTag(models[0]), nil
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details, err := c.ClientStore().ControllerByName(controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return names.NewControllerTag(details.ControllerUUID), nil
+}
+
+// roleNameArg validates and returns the role name from the command
+// line arguments.
+func roleNameArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no role name specified")
+	}
+	if !permission.IsValidRoleName(args[0]) {
+		return "", errors.NotValidf("role name %q", args[0])
+	}
+	return args[0], nil
+}
+
+// userNameArg validates and returns the user name from the command
+// line arguments.
+func userNameArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no user name specified")
+	}
+	if !names.IsValidUser(args[0]) {
+		return "", errors.NotValidf("user name %q", args[0])
+	}
+	return args[0], nil
+}
+
+// NewAddRoleCommand returns a command to add a custom role.
+func NewAddRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&addRoleCommand{})
+}
+
+// addRoleCommand adds a custom role to a controller.
+type addRoleCommand struct {
+	roleCommandBase
+	Role        string
+	Permissions []string
+}
+
+// Info implements Command.Info.
+func (c *addRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-role",
+		Args:    "<role name> <permission> ...",
+		Purpose: addRoleUsageSummary,
+		Doc:     addRoleUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *addRoleCommand) Init(args []string) (err error) {
+	if c.Role, err = roleNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) < 2 {
+		return errors.New("no permissions specified")
+	}
+	for _, p := range args[1:] {
+		if err := permission.ValidateRolePermission(p); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	c.Permissions = args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *addRoleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.AddRole(c.Role, c.Permissions...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Role %q added", c.Role)
+	return nil
+}
+
+// NewRemoveRoleCommand returns a command to remove a custom role.
+func NewRemoveRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&removeRoleCommand{})
+}
+
+// removeRoleCommand removes a custom role from a controller.
+type removeRoleCommand struct {
+	roleCommandBase
+	Role string
+}
+
+// Info implements Command.Info.
+func (c *removeRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-role",
+		Args:    "<role name>",
+		Purpose: removeRoleUsageSummary,
+		Doc:     removeRoleUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeRoleCommand) Init(args []string) (err error) {
+	if c.Role, err = roleNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeRoleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RemoveRole(c.Role); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Role %q removed", c.Role)
+	return nil
+}
+
+// NewAssignRoleCommand returns a command to assign a custom role to
+// a user.
+func NewAssignRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&assignRoleCommand{})
+}
+
+// assignRoleCommand assigns a custom role to a user on a model or
+// controller.
+type assignRoleCommand struct {
+	roleCommandBase
+	User      string
+	Role      string
+	ModelName string
+}
+
+// Info implements Command.Info.
+func (c *assignRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "assign-role",
+		Args:    "<user name> <role name> [<model name>]",
+		Purpose: assignRoleUsageSummary,
+		Doc:     assignRoleUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *assignRoleCommand) Init(args []string) (err error) {
+	if c.User, err = userNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	if c.Role, err = roleNameArg(args[1:]); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) > 2 {
+		c.ModelName = args[2]
+		return cmd.CheckEmpty(args[3:])
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *assignRoleCommand) Run(ctx *cmd.Context) error {
+	target, err := c.roleTarget(c.ModelName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	err = api.AssignRole(c.User, c.Role, target)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// NewUnassignRoleCommand returns a command to remove the custom role
+// assigned to a user.
+func NewUnassignRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&unassignRoleCommand{})
+}
+
+// unassignRoleCommand removes the custom role assigned to a user on a
+// model or controller.
+type unassignRoleCommand struct {
+	roleCommandBase
+	User      string
+	ModelName string
+}
+
+// Info implements Command.Info.
+func (c *unassignRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "unassign-role",
+		Args:    "<user name> [<model name>]",
+		Purpose: unassignRoleUsageSummary,
+		Doc:     unassignRoleUsageDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *unassignRoleCommand) Init(args []string) (err error) {
+	if c.User, err = userNameArg(args); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) > 1 {
+		c.ModelName = args[1]
+		return cmd.CheckEmpty(args[2:])
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *unassignRoleCommand) Run(ctx *cmd.Context) error {
+	target, err := c.roleTarget(c.ModelName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	err = api.UnassignRole(c.User, target)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// NewListRolesCommand returns a command to list custom roles.
+func NewListRolesCommand() cmd.Command {
+	return modelcmd.WrapController(&listRolesCommand{})
+}
+
+// listRolesCommand lists the custom roles in a controller.
+type listRolesCommand struct {
+	roleCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *listRolesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "roles",
+		Purpose: listRolesUsageSummary,
+		Doc:     listRolesUsageDetails,
+		Aliases: []string{"list-roles"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listRolesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.roleCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRolesTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listRolesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// RoleInfo holds the details of a custom role for output.
+type RoleInfo struct {
+	Name        string   `yaml:"name" json:"name"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+	CreatedBy   string   `yaml:"created-by" json:"created-by"`
+	DateCreated string   `yaml:"date-created" json:"date-created"`
+	// Assignments holds "<user> on <model or controller>" entries.
+	Assignments []string `yaml:"assignments,omitempty" json:"assignments,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *listRolesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	roles, err := api.Roles()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]RoleInfo, len(roles))
+	for i, r := range roles {
+		result[i] = RoleInfo{
+			Name:        r.Name,
+			Permissions: r.Permissions,
+			CreatedBy:   r.CreatedBy,
+			DateCreated: r.DateCreated.Format("2006-01-02"),
+		}
+		for _, a := range r.Assignments {
+			user, err := names.ParseUserTag(a.UserTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			target, err := names.ParseTag(a.TargetTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			result[i].Assignments = append(result[i].Assignments,
+				fmt.Sprintf("%s on %s", user.Id(), names.ReadableString(target)))
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatRolesTabular(writer io.Writer, value interface{}) error {
+	roles, ok := value.([]RoleInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", roles, value)
+	}
+	if len(roles) == 0 {
+		fmt.Fprintln(writer, "No roles to display.")
+		return nil
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Name", "Permissions", "Created by", "Assignments")
+	for _, r := range roles {
+		w.Println(r.Name, strings.Join(r.Permissions, ", "), r.CreatedBy, strings.Join(r.Assignments, ", "))
+	}
+	return tw.Flush()
+}
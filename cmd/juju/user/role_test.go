@@ -0,0 +1,170 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type RoleCommandSuite struct {
+	BaseSuite
+	mockAPI *mockRoleAPI
+}
+
+var _ = gc.Suite(&RoleCommandSuite{})
+
+func (s *RoleCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockRoleAPI{}
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/mymodel": {ModelUUID: testing.ModelTag.Id()},
+		},
+	}
+}
+
+func (s *RoleCommandSuite) TestAddRole(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store), "operator", "ssh", "run-actions", "Client.FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"AddRole operator ssh run-actions Client.FullStatus"})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Role \"operator\" added\n")
+}
+
+func (s *RoleCommandSuite) TestAddRoleInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, "no role name specified")
+	_, err = cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store), "Operator", "ssh")
+	c.Assert(err, gc.ErrorMatches, `role name "Operator" not valid`)
+	_, err = cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store), "operator")
+	c.Assert(err, gc.ErrorMatches, "no permissions specified")
+	_, err = cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store), "operator", "fly")
+	c.Assert(err, gc.ErrorMatches, `role permission "fly" not valid`)
+}
+
+func (s *RoleCommandSuite) TestAddRoleError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, user.NewAddRoleCommandForTest(s.mockAPI, s.store), "operator", "ssh")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *RoleCommandSuite) TestRemoveRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRemoveRoleCommandForTest(s.mockAPI, s.store), "operator")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"RemoveRole operator"})
+}
+
+func (s *RoleCommandSuite) TestAssignRoleOnModel(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAssignRoleCommandForTest(s.mockAPI, s.store), "alice", "operator", "mymodel")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"AssignRole alice operator " + testing.ModelTag.String()})
+}
+
+func (s *RoleCommandSuite) TestAssignRoleOnController(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAssignRoleCommandForTest(s.mockAPI, s.store), "bob@external", "deployer")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"AssignRole bob@external deployer " + testing.ControllerTag.String()})
+}
+
+func (s *RoleCommandSuite) TestAssignRoleInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAssignRoleCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, gc.ErrorMatches, "no user name specified")
+	_, err = cmdtesting.RunCommand(c, user.NewAssignRoleCommandForTest(s.mockAPI, s.store), "alice")
+	c.Assert(err, gc.ErrorMatches, "no role name specified")
+	_, err = cmdtesting.RunCommand(c, user.NewAssignRoleCommandForTest(s.mockAPI, s.store), "alice", "operator", "mymodel", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *RoleCommandSuite) TestUnassignRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewUnassignRoleCommandForTest(s.mockAPI, s.store), "alice", "mymodel")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.calls, jc.DeepEquals, []string{"UnassignRole alice " + testing.ModelTag.String()})
+}
+
+func (s *RoleCommandSuite) TestListRoles(c *gc.C) {
+	s.mockAPI.roles = []params.RoleInfo{{
+		Name:        "operator",
+		Permissions: []string{"ssh", "run-actions"},
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		Assignments: []params.RoleAssignment{{
+			UserTag:   "user-alice",
+			TargetTag: names.NewControllerTag(testing.ControllerTag.Id()).String(),
+		}},
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListRolesCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Name      Permissions       Created by  Assignments\n"+
+		"operator  ssh, run-actions  admin       alice on controller "+testing.ControllerTag.Id()+"\n"+
+		"\n")
+}
+
+func (s *RoleCommandSuite) TestListRolesYAML(c *gc.C) {
+	s.mockAPI.roles = []params.RoleInfo{{
+		Name:        "deployer",
+		Permissions: []string{"deploy"},
+		CreatedBy:   "admin",
+		DateCreated: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, user.NewListRolesCommandForTest(s.mockAPI, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- name: deployer
+  permissions:
+  - deploy
+  created-by: admin
+  date-created: "2020-03-01"
+`[1:])
+}
+
+func (s *RoleCommandSuite) TestListRolesEmpty(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewListRolesCommandForTest(s.mockAPI, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No roles to display.\n\n")
+}
+
+type mockRoleAPI struct {
+	err   error
+	calls []string
+	roles []params.RoleInfo
+}
+
+func (*mockRoleAPI) Close() error { return nil }
+
+func (m *mockRoleAPI) record(call string, args ...string) error {
+	m.calls = append(m.calls, strings.Join(append([]string{call}, args...), " "))
+	return m.err
+}
+
+func (m *mockRoleAPI) AddRole(name string, permissions ...string) error {
+	return m.record("AddRole", append([]string{name}, permissions...)...)
+}
+
+func (m *mockRoleAPI) RemoveRole(names ...string) error {
+	return m.record("RemoveRole", names...)
+}
+
+func (m *mockRoleAPI) Roles(roles ...string) ([]params.RoleInfo, error) {
+	return m.roles, m.record("Roles", roles...)
+}
+
+func (m *mockRoleAPI) AssignRole(user, role string, target names.Tag) error {
+	return m.record("AssignRole", user, role, target.String())
+}
+
+func (m *mockRoleAPI) UnassignRole(user string, target names.Tag) error {
+	return m.record("UnassignRole", user, target.String())
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/bulkmigration"
 	"github.com/juju/juju/worker/caasupgrader"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
@@ -654,6 +655,16 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
+		bulkMigrationName: ifNotMigrating(ifPrimaryController(bulkmigration.Manifold(
+			bulkmigration.ManifoldConfig{
+				ClockName:  clockName,
+				StateName:  stateName,
+				Interval:   30 * time.Second,
+				NewBackend: bulkmigration.NewBackend,
+				NewWorker:  bulkmigration.New,
+			},
+		))),
+
 		httpServerArgsName: httpserverargs.Manifold(httpserverargs.ManifoldConfig{
 			ClockName:             clockName,
 			ControllerPortName:    controllerPortName,
@@ -1130,6 +1141,7 @@ const (
 	isControllerFlagName          = "is-controller-flag"
 	instanceMutaterName           = "instance-mutater"
 	txnPrunerName                 = "transaction-pruner"
+	bulkMigrationName             = "bulk-migration-scheduler"
 	certificateWatcherName        = "certificate-watcher"
 	modelCacheName                = "model-cache"
 	modelCacheInitializedFlagName = "model-cache-initialized-flag"
@@ -66,6 +66,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"api-server",
 			"audit-config-updater",
 			"broker-tracker",
+			"bulk-migration-scheduler",
 			"central-hub",
 			"certificate-updater",
 			"certificate-watcher",
@@ -144,6 +145,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"api-config-watcher",
 			"api-server",
 			"audit-config-updater",
+			"bulk-migration-scheduler",
 			"central-hub",
 			"certificate-watcher",
 			"clock",
@@ -304,6 +306,7 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 		"upgrade-database-runner",
 	)
 	primaryControllerWorkers := set.NewStrings(
+		"bulk-migration-scheduler",
 		"external-controller-updater",
 		"transaction-pruner",
 	)
@@ -467,6 +470,23 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state-config-watcher",
 	},
 
+	"bulk-migration-scheduler": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"central-hub": {"agent", "state-config-watcher"},
 
 	"certificate-updater": {
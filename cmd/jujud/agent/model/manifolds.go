@@ -375,6 +375,7 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 			EnvironName:   environTrackerName,
 			Logger:        config.LoggingContext.GetLogger("juju.worker.provisioner"),
+			Clock:         config.Clock,
 
 			NewProvisionerFunc:           provisioner.NewEnvironProvisioner,
 			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
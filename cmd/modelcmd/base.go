@@ -30,6 +30,7 @@ import (
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/pki"
 )
@@ -517,6 +518,7 @@ func newAPIConnectionParams(
 		ModelUUID:      modelUUID,
 		DialOpts:       dialOpts,
 		OpenAPI:        apiOpen,
+		APIToken:       os.Getenv(osenv.JujuAPITokenEnvKey),
 	}, nil
 }
 
@@ -11,11 +11,14 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
 )
 
 var kvmLogger = loggo.GetLogger("juju.container.broker.kvm")
@@ -111,8 +114,14 @@ func (broker *kvmBroker) StartInstance(ctx context.ProviderCallContext, args env
 		return nil, errors.Trace(err)
 	}
 
-	cloudInitUserData, err := combinedCloudInitData(
-		config.CloudInitUserData,
+	// The provisioner supplies user data merged for the machine,
+	// which already includes the model's.
+	cloudInitUserData := args.InstanceConfig.CloudInitUserData
+	if cloudInitUserData == nil {
+		cloudInitUserData = config.CloudInitUserData
+	}
+	cloudInitUserData, err = combinedCloudInitData(
+		cloudInitUserData,
 		config.ContainerInheritProperties,
 		series, kvmLogger)
 	if err != nil {
@@ -137,6 +146,22 @@ func (broker *kvmBroker) StartInstance(ctx context.ProviderCallContext, args env
 	storageConfig := &container.StorageConfig{
 		AllowMount: true,
 	}
+	var kvmVolumes []storage.VolumeParams
+	for _, v := range args.Volumes {
+		if v.Provider != provider.KVMProviderType {
+			continue
+		}
+		if v.Attachment == nil {
+			return nil, errors.NotValidf("volume %q without attachment", v.Tag.Id())
+		}
+		pool, _ := v.Attributes[provider.KVMPoolAttr].(string)
+		storageConfig.Disks = append(storageConfig.Disks, container.DiskConfig{
+			Pool: pool,
+			// Disks are sized in whole GB, so round up.
+			Size: (v.Size + 1023) / 1024,
+		})
+		kvmVolumes = append(kvmVolumes, v)
+	}
 	inst, hardware, err := broker.manager.CreateContainer(
 		args.InstanceConfig, args.Constraints,
 		series, network, storageConfig, args.StatusCallback,
@@ -146,12 +171,44 @@ func (broker *kvmBroker) StartInstance(ctx context.ProviderCallContext, args env
 		return nil, err
 	}
 	kvmLogger.Infof("started kvm container for containerMachineID: %s, %s, %s", containerMachineID, inst.Id(), hardware.String())
+	volumes, attachments := kvmVolumeResults(string(inst.Id()), kvmVolumes, storageConfig.Disks)
 	return &environs.StartInstanceResult{
-		Instance: inst,
-		Hardware: hardware,
+		Instance:          inst,
+		Hardware:          hardware,
+		Volumes:           volumes,
+		VolumeAttachments: attachments,
 	}, nil
 }
 
+// kvmVolumeResults reports the disks created for a KVM container as the
+// volumes they were requested for, attached to the container.
+func kvmVolumeResults(
+	hostname string, params []storage.VolumeParams, disks []container.DiskConfig,
+) ([]storage.Volume, []storage.VolumeAttachment) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	volumes := make([]storage.Volume, len(params))
+	attachments := make([]storage.VolumeAttachment, len(params))
+	for i, v := range params {
+		volumes[i] = storage.Volume{
+			Tag: v.Tag,
+			VolumeInfo: storage.VolumeInfo{
+				VolumeId: kvm.DiskVolumeName(hostname, i),
+				Size:     disks[i].Size * 1024,
+			},
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:  v.Tag,
+			Machine: v.Attachment.Machine,
+			VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+				DeviceName: kvm.DiskDeviceName(i),
+			},
+		}
+	}
+	return volumes, attachments
+}
+
 // MaintainInstance is a no-op.
 func (broker *kvmBroker) MaintainInstance(_ context.ProviderCallContext, _ environs.StartInstanceParams) error {
 	return nil
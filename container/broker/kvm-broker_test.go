@@ -25,6 +25,8 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
 	coretesting "github.com/juju/juju/testing"
 	jujuversion "github.com/juju/juju/version"
 )
@@ -275,6 +277,66 @@ func (s *kvmBrokerSuite) TestStartInstanceWithContainerInheritProperties(c *gc.C
 	}, c)
 }
 
+func (s *kvmBrokerSuite) TestStartInstanceWithKVMVolumes(c *gc.C) {
+	broker, brokerErr := s.newKVMBrokerFakeManager(c)
+	c.Assert(brokerErr, jc.ErrorIsNil)
+
+	machineTag := names.NewMachineTag("1/kvm/0")
+	attachment := &storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{Machine: machineTag},
+	}
+	result, err := broker.StartInstance(context.NewCloudCallContext(), environs.StartInstanceParams{
+		Tools:          makePossibleTools(),
+		InstanceConfig: makeInstanceConfig(c, s, "1/kvm/0"),
+		StatusCallback: makeNoOpStatusCallback(),
+		Volumes: []storage.VolumeParams{{
+			Tag:        names.NewVolumeTag("0"),
+			Size:       1500,
+			Provider:   provider.KVMProviderType,
+			Attachment: attachment,
+		}, {
+			Tag:        names.NewVolumeTag("1"),
+			Size:       1024,
+			Provider:   "loop",
+			Attachment: attachment,
+		}, {
+			Tag:        names.NewVolumeTag("2"),
+			Size:       2048,
+			Provider:   provider.KVMProviderType,
+			Attributes: map[string]interface{}{provider.KVMPoolAttr: "fast"},
+			Attachment: attachment,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager.CheckCallNames(c, "CreateContainer")
+	call := s.manager.Calls()[0]
+	c.Assert(call.Args[4], jc.DeepEquals, &container.StorageConfig{
+		AllowMount: true,
+		Disks: []container.DiskConfig{
+			{Size: 2},
+			{Pool: "fast", Size: 2},
+		},
+	})
+
+	c.Assert(result.Volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:        names.NewVolumeTag("0"),
+		VolumeInfo: storage.VolumeInfo{VolumeId: "testinst-disk0", Size: 2048},
+	}, {
+		Tag:        names.NewVolumeTag("2"),
+		VolumeInfo: storage.VolumeInfo{VolumeId: "testinst-disk1", Size: 2048},
+	}})
+	c.Assert(result.VolumeAttachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:               names.NewVolumeTag("0"),
+		Machine:              machineTag,
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{DeviceName: "vdc"},
+	}, {
+		Volume:               names.NewVolumeTag("2"),
+		Machine:              machineTag,
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{DeviceName: "vdd"},
+	}})
+}
+
 type blankMachineInitReader struct {
 	cloudconfig.InitReader
 }
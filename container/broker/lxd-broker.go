@@ -113,8 +113,14 @@ func (broker *lxdBroker) StartInstance(ctx context.ProviderCallContext, args env
 		return nil, errors.Trace(err)
 	}
 
-	cloudInitUserData, err := combinedCloudInitData(
-		config.CloudInitUserData,
+	// The provisioner supplies user data merged for the machine,
+	// which already includes the model's.
+	cloudInitUserData := args.InstanceConfig.CloudInitUserData
+	if cloudInitUserData == nil {
+		cloudInitUserData = config.CloudInitUserData
+	}
+	cloudInitUserData, err = combinedCloudInitData(
+		cloudInitUserData,
 		config.ContainerInheritProperties,
 		series, lxdLogger)
 	if err != nil {
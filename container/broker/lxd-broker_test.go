@@ -155,6 +155,32 @@ func (s *lxdBrokerSuite) TestStartInstanceWithCloudInitUserData(c *gc.C) {
 	}, c)
 }
 
+func (s *lxdBrokerSuite) TestStartInstanceWithMachineCloudInitUserData(c *gc.C) {
+	broker, brokerErr := s.newLXDBroker(c)
+	c.Assert(brokerErr, jc.ErrorIsNil)
+
+	// User data supplied by the provisioner for the machine is used
+	// in place of the model's.
+	instanceConfig := makeInstanceConfig(c, s, "1/lxd/0")
+	instanceConfig.CloudInitUserData = map[string]interface{}{
+		"packages": []interface{}{"htop"},
+	}
+	_, err := broker.StartInstance(context.NewCloudCallContext(), environs.StartInstanceParams{
+		Tools:          makePossibleTools(),
+		InstanceConfig: instanceConfig,
+		StatusCallback: makeNoOpStatusCallback(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager.CheckCallNames(c, "CreateContainer")
+	call := s.manager.Calls()[0]
+	c.Assert(call.Args[0], gc.FitsTypeOf, &instancecfg.InstanceConfig{})
+	instanceConfig = call.Args[0].(*instancecfg.InstanceConfig)
+	assertCloudInitUserData(instanceConfig.CloudInitUserData, map[string]interface{}{
+		"packages": []interface{}{"htop"},
+	}, c)
+}
+
 func (s *lxdBrokerSuite) TestStartInstanceWithContainerInheritProperties(c *gc.C) {
 	broker.PatchNewMachineInitReader(s, newFakeMachineInitReader)
 	s.api.fakeContainerConfig.ContainerInheritProperties = "ca-certs,apt-security"
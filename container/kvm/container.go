@@ -112,6 +112,8 @@ func (c *kvmContainer) Start(params StartParams) error {
 		Memory:            params.Memory,
 		CpuCores:          params.CpuCores,
 		RootDisk:          params.RootDisk,
+		Disks:             params.Disks,
+		PinnedCPUs:        params.PinnedCPUs,
 		Interfaces:        interfaces,
 	}
 	if err := CreateMachine(mparams); err != nil {
@@ -12,7 +12,10 @@ import (
 // This file exports internal package implementations so that tests
 // can utilize them to mock behavior.
 
-var KVMPath = &kvmPath
+var (
+	KVMPath      = &kvmPath
+	HostCPUCount = &hostCPUCount
+)
 
 // MakeCreateMachineParamsTestable adds test values to non exported values on
 // CreateMachineParams.
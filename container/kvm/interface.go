@@ -19,6 +19,8 @@ type StartParams struct {
 	Memory            uint64 // MB
 	CpuCores          uint64
 	RootDisk          uint64 // GB
+	Disks             []DiskParams
+	PinnedCPUs        []uint
 	ImageDownloadURL  string
 	StatusCallback    func(status status.Status, info string, data map[string]interface{}) error
 }
@@ -5,8 +5,11 @@ package kvm
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 
@@ -110,6 +113,17 @@ func NewContainerManager(conf container.ManagerConfig) (container.Manager, error
 	imageMetaDataURL := conf.PopValue(config.ContainerImageMetadataURLKey)
 	imageStream := conf.PopValue(config.ContainerImageStreamKey)
 
+	pinnedCPUs, err := config.ParseKVMPinnedCPUs(conf.PopValue(config.KVMPinnedCPUs))
+	if err != nil {
+		return nil, errors.Annotate(err, config.KVMPinnedCPUs)
+	}
+	hostCPUs := hostCPUCount()
+	for _, cpu := range pinnedCPUs {
+		if cpu >= uint(hostCPUs) {
+			return nil, errors.Errorf("%s: host CPU %d not found, the host has %d CPUs", config.KVMPinnedCPUs, cpu, hostCPUs)
+		}
+	}
+
 	conf.WarnAboutUnused()
 	return &containerManager{
 		namespace:        namespace,
@@ -117,6 +131,7 @@ func NewContainerManager(conf container.ManagerConfig) (container.Manager, error
 		availabilityZone: availabilityZone,
 		imageMetadataURL: imageMetaDataURL,
 		imageStream:      imageStream,
+		pinnedCPUs:       pinnedCPUs,
 	}, nil
 }
 
@@ -130,7 +145,9 @@ type containerManager struct {
 	availabilityZone string
 	imageMetadataURL string
 	imageStream      string
+	pinnedCPUs       []uint
 	imageMutex       sync.Mutex
+	pinMutex         sync.Mutex
 }
 
 var _ container.Manager = (*containerManager)(nil)
@@ -192,6 +209,21 @@ func (manager *containerManager) CreateContainer(
 	startParams.NetworkConfigData = cloudinit.CloudInitNetworkConfigDisabled
 	startParams.StatusCallback = callback
 	startParams.Stream = manager.imageStream
+	if storageConfig != nil {
+		for _, disk := range storageConfig.Disks {
+			startParams.Disks = append(startParams.Disks, DiskParams{Pool: disk.Pool, Size: disk.Size})
+		}
+	}
+	if len(manager.pinnedCPUs) > 0 {
+		if startParams.PinnedCPUs, err = manager.allocatePinnedCPUs(name, startParams.CpuCores); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		defer func() {
+			if err != nil {
+				_ = os.Remove(filepath.Join(directory, pinnedCPUsFile))
+			}
+		}()
+	}
 
 	// Check whether a container image metadata URL was configured.
 	// Default to Ubuntu cloud images if configured stream is not "released".
@@ -235,6 +267,81 @@ func (manager *containerManager) CreateContainer(
 	return &kvmInstance{kvmContainer, name}, &hardware, nil
 }
 
+// pinnedCPUsFile is the file in a container's directory recording the
+// host CPUs the container is pinned to.
+const pinnedCPUsFile = "pinned-cpus"
+
+// hostCPUCount returns the number of CPUs on the host.
+var hostCPUCount = runtime.NumCPU
+
+// allocatePinnedCPUs returns as many of the pinned host CPUs as the
+// container has virtual CPUs, choosing those no other container on the
+// host is pinned to, so that the containers don't share host CPUs. The
+// CPUs are recorded in the container's directory, and freed when it is
+// removed with the container.
+func (manager *containerManager) allocatePinnedCPUs(name string, count uint64) ([]uint, error) {
+	manager.pinMutex.Lock()
+	defer manager.pinMutex.Unlock()
+
+	used, err := usedPinnedCPUs(name)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading pinned CPUs of other containers")
+	}
+	var cpus []uint
+	for _, cpu := range manager.pinnedCPUs {
+		if uint64(len(cpus)) == count {
+			break
+		}
+		if !used[cpu] {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if uint64(len(cpus)) < count {
+		return nil, errors.Errorf(
+			"container needs %d CPUs but only %d of the %d pinned host CPUs are free",
+			count, len(cpus), len(manager.pinnedCPUs))
+	}
+
+	raw := make([]string, len(cpus))
+	for i, cpu := range cpus {
+		raw[i] = fmt.Sprint(cpu)
+	}
+	path := filepath.Join(container.ContainerDir, name, pinnedCPUsFile)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(raw, ",")), 0644); err != nil {
+		return nil, errors.Annotate(err, "recording pinned CPUs")
+	}
+	return cpus, nil
+}
+
+// usedPinnedCPUs returns the host CPUs that containers other than the
+// named one are pinned to.
+func usedPinnedCPUs(name string) (map[uint]bool, error) {
+	dirs, err := ioutil.ReadDir(container.ContainerDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	used := make(map[uint]bool)
+	for _, dir := range dirs {
+		if !dir.IsDir() || dir.Name() == name {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(container.ContainerDir, dir.Name(), pinnedCPUsFile))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cpus, err := config.ParseKVMPinnedCPUs(string(data))
+		if err != nil {
+			return nil, errors.Annotatef(err, "container %q", dir.Name())
+		}
+		for _, cpu := range cpus {
+			used[cpu] = true
+		}
+	}
+	return used, nil
+}
+
 func (manager *containerManager) IsInitialized() bool {
 	requiredBinaries := []string{
 		"virsh",
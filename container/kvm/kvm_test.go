@@ -22,8 +22,10 @@ import (
 	containertesting "github.com/juju/juju/container/testing"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/instances"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -36,6 +38,7 @@ var _ = gc.Suite(&KVMSuite{})
 
 func (s *KVMSuite) SetUpTest(c *gc.C) {
 	s.TestSuite.SetUpTest(c)
+	s.PatchValue(kvm.HostCPUCount, func() int { return 8 })
 	var err error
 	s.manager, err = kvm.NewContainerManager(container.ManagerConfig{
 		container.ConfigModelUUID:      coretesting.ModelTag.Id(),
@@ -181,6 +184,116 @@ func (s *KVMSuite) TestCreateContainerUsesSetImageMetadataURL(c *gc.C) {
 	c.Assert(startParams.ImageDownloadURL, gc.Equals, "https://images.linuxcontainers.org")
 }
 
+func (s *KVMSuite) TestCreateContainerPassesDisks(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	networkConfig := container.BridgeNetworkConfig("nic42", 0, nil)
+	storageConfig := &container.StorageConfig{
+		AllowMount: true,
+		Disks: []container.DiskConfig{
+			{Size: 10},
+			{Pool: "fast", Size: 20},
+		},
+	}
+	inst := containertesting.CreateContainerWithMachineAndNetworkAndStorageConfig(
+		c, s.manager, instanceConfig, networkConfig, storageConfig)
+	startParams := kvm.ContainerFromInstance(inst).(*mock.MockContainer).StartParams
+	c.Assert(startParams.Disks, jc.DeepEquals, []kvm.DiskParams{
+		{Size: 10},
+		{Pool: "fast", Size: 20},
+	})
+	c.Assert(startParams.PinnedCPUs, gc.HasLen, 0)
+}
+
+func (s *KVMSuite) TestCreateContainerPinsCPUs(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager, err = kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		config.KVMPinnedCPUs:      "4,5,6",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	inst := containertesting.CreateContainerWithMachineConfig(c, s.manager, instanceConfig)
+	startParams := kvm.ContainerFromInstance(inst).(*mock.MockContainer).StartParams
+	// The container has the default single CPU.
+	c.Assert(startParams.PinnedCPUs, jc.DeepEquals, []uint{4})
+}
+
+func (s *KVMSuite) TestCreateContainerTooFewPinnedCPUs(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager, err = kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		config.KVMPinnedCPUs:      "4",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	callback := func(status.Status, string, map[string]interface{}) error { return nil }
+	_, _, err = s.manager.CreateContainer(
+		instanceConfig, constraints.MustParse("cores=2"), "bionic",
+		container.BridgeNetworkConfig("nic42", 0, nil), &container.StorageConfig{}, callback)
+	c.Assert(err, gc.ErrorMatches, "container needs 2 CPUs but only 1 of the 1 pinned host CPUs are free")
+}
+
+func (s *KVMSuite) TestCreateContainerPinsDisjointCPUs(c *gc.C) {
+	var err error
+	s.manager, err = kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		config.KVMPinnedCPUs:      "4,5,6",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pinnedCPUs := func(inst instances.Instance) []uint {
+		return kvm.ContainerFromInstance(inst).(*mock.MockContainer).StartParams.PinnedCPUs
+	}
+	callback := func(status.Status, string, map[string]interface{}) error { return nil }
+	createContainer := func(machineId string, cons constraints.Value) (instances.Instance, error) {
+		instanceConfig, err := containertesting.MockMachineConfig(machineId)
+		c.Assert(err, jc.ErrorIsNil)
+		inst, _, err := s.manager.CreateContainer(
+			instanceConfig, cons, "bionic",
+			container.BridgeNetworkConfig("nic42", 0, nil), &container.StorageConfig{}, callback)
+		return inst, err
+	}
+
+	first, err := createContainer("1/kvm/0", constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pinnedCPUs(first), jc.DeepEquals, []uint{4})
+	second, err := createContainer("1/kvm/1", constraints.MustParse("cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pinnedCPUs(second), jc.DeepEquals, []uint{5, 6})
+
+	_, err = createContainer("1/kvm/2", constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, "container needs 1 CPUs but only 0 of the 3 pinned host CPUs are free")
+
+	// The CPUs of a removed container are free again.
+	err = s.manager.DestroyContainer(first.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	third, err := createContainer("1/kvm/3", constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pinnedCPUs(third), jc.DeepEquals, []uint{4})
+}
+
+func (*KVMSuite) TestManagerPinnedCPUsNotOnHost(c *gc.C) {
+	_, err := kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		config.KVMPinnedCPUs:      "4,8",
+	})
+	c.Assert(err, gc.ErrorMatches, `kvm-pinned-cpus: host CPU 8 not found, the host has 8 CPUs`)
+}
+
+func (*KVMSuite) TestManagerInvalidPinnedCPUs(c *gc.C) {
+	_, err := kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigModelUUID: coretesting.ModelTag.Id(),
+		config.KVMPinnedCPUs:      "four",
+	})
+	c.Assert(err, gc.ErrorMatches, `kvm-pinned-cpus: host CPU "four" not valid`)
+}
+
 func (s *KVMSuite) TestImageAcquisitionUsesSimpleStream(c *gc.C) {
 
 	startParams := kvm.StartParams{
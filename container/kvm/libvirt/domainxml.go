@@ -24,6 +24,14 @@ type DiskInfo interface {
 	Driver() string
 }
 
+// PoolDiskInfo represents a disk backed by a volume in a libvirt storage
+// pool. For such disks, Source returns the volume name.
+type PoolDiskInfo interface {
+	DiskInfo
+	// Pool is the name of the storage pool holding the volume.
+	Pool() string
+}
+
 // InterfaceInfo represents network interface parameters for a kvm domain.
 type InterfaceInfo interface {
 	// MAC returns the interfaces MAC address.
@@ -43,6 +51,9 @@ type domainParams interface {
 	Arch() string
 	// CPUs returns the number of CPUs to use.
 	CPUs() uint64
+	// CPUPinning returns the host CPUs to pin each virtual CPU to, in
+	// order. It is empty if the virtual CPUs are not pinned.
+	CPUPinning() []uint
 	// DiskInfo returns the disk information for the domain.
 	DiskInfo() []DiskInfo
 	// Host returns the host name.
@@ -65,12 +76,18 @@ func NewDomain(p domainParams) (Domain, error) {
 		return Domain{}, errors.Trace(err)
 	}
 
+	cpuTune, err := generateCPUTune(p)
+	if err != nil {
+		return Domain{}, errors.Trace(err)
+	}
+
 	d := Domain{
 		Type:          "kvm",
 		Name:          p.Host(),
 		VCPU:          p.CPUs(),
 		CurrentMemory: Memory{Unit: "MiB", Text: p.RAM()},
 		Memory:        Memory{Unit: "MiB", Text: p.RAM()},
+		CPUTune:       cpuTune,
 		OS:            generateOSElement(p),
 		Features:      generateFeaturesElement(p),
 		CPU:           generateCPU(p),
@@ -104,26 +121,23 @@ func NewDomain(p domainParams) (Domain, error) {
 			return Domain{}, errors.Trace(err)
 		}
 		switch diskInfo.Driver() {
-		case "raw":
-			d.Disk = append(d.Disk, Disk{
-				Device: "disk",
-				Type:   "file",
-				Driver: DiskDriver{Type: diskInfo.Driver(), Name: "qemu"},
-				Source: DiskSource{File: diskInfo.Source()},
-				Target: DiskTarget{Dev: devID},
-			})
-		case "qcow2":
-			d.Disk = append(d.Disk, Disk{
-				Device: "disk",
-				Type:   "file",
-				Driver: DiskDriver{Type: diskInfo.Driver(), Name: "qemu"},
-				Source: DiskSource{File: diskInfo.Source()},
-				Target: DiskTarget{Dev: devID},
-			})
+		case "raw", "qcow2":
 		default:
 			return Domain{}, errors.Errorf(
 				"unsupported disk type %q", diskInfo.Driver())
 		}
+		disk := Disk{
+			Device: "disk",
+			Type:   "file",
+			Driver: DiskDriver{Type: diskInfo.Driver(), Name: "qemu"},
+			Source: DiskSource{File: diskInfo.Source()},
+			Target: DiskTarget{Dev: devID},
+		}
+		if poolDisk, ok := diskInfo.(PoolDiskInfo); ok && poolDisk.Pool() != "" {
+			disk.Type = "volume"
+			disk.Source = DiskSource{Pool: poolDisk.Pool(), Volume: poolDisk.Source()}
+		}
+		d.Disk = append(d.Disk, disk)
 	}
 	for _, iface := range p.NetworkInfo() {
 		virtIf := Interface{
@@ -190,6 +204,27 @@ func generateCPU(p domainParams) *CPU {
 	return nil
 }
 
+// generateCPUTune pins each virtual CPU to the corresponding host CPU, if
+// pinning was requested.
+// See: https://libvirt.org/formatdomain.html#elementsCPUTuning
+func generateCPUTune(p domainParams) (*CPUTune, error) {
+	pinning := p.CPUPinning()
+	if len(pinning) == 0 {
+		return nil, nil
+	}
+	if uint64(len(pinning)) != p.CPUs() {
+		return nil, errors.Errorf("got %d CPU pins for %d CPUs", len(pinning), p.CPUs())
+	}
+	tune := &CPUTune{}
+	for vcpu, cpu := range pinning {
+		tune.VCPUPin = append(tune.VCPUPin, VCPUPin{
+			VCPU:   uint64(vcpu),
+			CPUSet: fmt.Sprint(cpu),
+		})
+	}
+	return tune, nil
+}
+
 // deviceID generates a device id from and int. The limit of 26 is arbitrary,
 // but it seems unlikely we'll need more than a couple for our use case.
 func deviceID(i int) (string, error) {
@@ -210,6 +245,7 @@ type Domain struct {
 	VCPU          uint64      `xml:"vcpu"`
 	CurrentMemory Memory      `xml:"currentMemory"`
 	Memory        Memory      `xml:"memory"`
+	CPUTune       *CPUTune    `xml:"cputune,omitempty"`
 	OS            OS          `xml:"os"`
 	Features      *Features   `xml:"features,omitempty"`
 	CPU           *CPU        `xml:"cpu,omitempty"`
@@ -283,6 +319,19 @@ type CPU struct {
 	Model Model  `xml:"model,omitempty"`
 }
 
+// CPUTune holds the virtual CPU to host CPU pinning.
+// See: https://libvirt.org/formatdomain.html#elementsCPUTuning
+type CPUTune struct {
+	VCPUPin []VCPUPin `xml:"vcpupin"`
+}
+
+// VCPUPin pins a virtual CPU to a set of host CPUs.
+// See: CPUTune
+type VCPUPin struct {
+	VCPU   uint64 `xml:"vcpu,attr"`
+	CPUSet string `xml:"cpuset,attr"`
+}
+
 // Address is static. We generate a default value for it.
 // See: Controller, Video
 type Address struct {
@@ -392,11 +441,13 @@ type DiskDriver struct {
 	Name string `xml:"name,attr"`
 }
 
-// DiskSource is the location of the disk image. In our case the path to the
-// necessary images.
+// DiskSource is the location of the disk image. In our case either the path
+// to the necessary images, or a volume in a storage pool.
 // See: Disk
 type DiskSource struct {
-	File string `xml:"file,attr"`
+	File   string `xml:"file,attr,omitempty"`
+	Pool   string `xml:"pool,attr,omitempty"`
+	Volume string `xml:"volume,attr,omitempty"`
 }
 
 // DiskTarget is the target device on the guest. We generate these.
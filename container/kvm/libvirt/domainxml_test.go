@@ -5,6 +5,8 @@ package libvirt_test
 
 import (
 	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -201,6 +203,91 @@ func (domainXMLSuite) TestNewDomainWithOvsBridge(c *gc.C) {
 	c.Assert(string(ml), jc.DeepEquals, amd64WithOvsBridgeDomainStr)
 }
 
+func (domainXMLSuite) TestNewDomainGoldenFiles(c *gc.C) {
+	systemDisks := []DiskInfo{
+		dummyDisk{driver: "qcow2", source: "/var/lib/juju/kvm/guests/juju-someid.qcow"},
+		dummyDisk{driver: "raw", source: "/var/lib/juju/kvm/guests/juju-someid-ds.iso"},
+	}
+	spaceIfaces := []InterfaceInfo{
+		dummyInterface{mac: "52:54:00:00:00:01", parent: "br-eth0", name: "eth0"},
+		dummyInterface{mac: "52:54:00:00:00:02", parent: "br-eth1", name: "eth1"},
+		dummyInterface{mac: "52:54:00:00:00:03", parent: "br-ovs", name: "eth2", parentVirtualPortType: "openvswitch"},
+	}
+	poolDisks := append(systemDisks[:2:2],
+		dummyPoolDisk{dummyDisk: dummyDisk{driver: "qcow2", source: "juju-someid-disk0"}, pool: "juju-pool"},
+		dummyPoolDisk{dummyDisk: dummyDisk{driver: "raw", source: "juju-someid-disk1"}, pool: "fast-pool"},
+	)
+
+	for i, test := range []struct {
+		golden string
+		params dummyParams
+	}{{
+		golden: "amd64-multiple-nics.xml",
+		params: dummyParams{
+			arch: "amd64", hostname: "juju-someid", cpuCores: 2, memory: 2048,
+			diskInfo: systemDisks, ifaceInfo: spaceIfaces,
+		},
+	}, {
+		golden: "amd64-pool-disks.xml",
+		params: dummyParams{
+			arch: "amd64", hostname: "juju-someid", cpuCores: 2, memory: 2048,
+			diskInfo: poolDisks, ifaceInfo: spaceIfaces[:1],
+		},
+	}, {
+		golden: "amd64-cpu-pinning.xml",
+		params: dummyParams{
+			arch: "amd64", hostname: "juju-someid", cpuCores: 4, memory: 4096,
+			cpuPinning: []uint{2, 3, 6, 7},
+			diskInfo:   systemDisks, ifaceInfo: spaceIfaces[:1],
+		},
+	}, {
+		golden: "arm64-full.xml",
+		params: dummyParams{
+			arch: "arm64", hostname: "juju-someid", cpuCores: 2, memory: 2048,
+			loader:     "/usr/share/AAVMF/AAVMF_CODE.fd",
+			cpuPinning: []uint{0, 1},
+			diskInfo:   poolDisks, ifaceInfo: spaceIfaces,
+		},
+	}} {
+		c.Logf("test %d: %s", i, test.golden)
+		want, err := ioutil.ReadFile(filepath.Join("testdata", test.golden))
+		c.Assert(err, jc.ErrorIsNil)
+
+		d, err := NewDomain(test.params)
+		c.Assert(err, jc.ErrorIsNil)
+		ml, err := xml.MarshalIndent(&d, "", "    ")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(ml)+"\n", gc.Equals, string(want))
+
+		// The golden files must also read back into an equivalent domain.
+		var read Domain
+		err = xml.Unmarshal(want, &read)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(read.Disk, jc.DeepEquals, d.Disk)
+		c.Check(read.CPUTune, jc.DeepEquals, d.CPUTune)
+	}
+}
+
+func (domainXMLSuite) TestNewDomainCPUPinningMismatch(c *gc.C) {
+	params := dummyParams{
+		arch: "amd64", hostname: "juju-someid", cpuCores: 2, memory: 1024,
+		cpuPinning: []uint{1, 2, 3},
+	}
+	_, err := NewDomain(params)
+	c.Assert(err, gc.ErrorMatches, "got 3 CPU pins for 2 CPUs")
+}
+
+func (domainXMLSuite) TestNewDomainUnsupportedPoolDisk(c *gc.C) {
+	params := dummyParams{
+		arch: "amd64", hostname: "juju-someid", cpuCores: 2, memory: 1024,
+		diskInfo: []DiskInfo{
+			dummyPoolDisk{dummyDisk: dummyDisk{driver: "vmdk", source: "vol"}, pool: "juju-pool"},
+		},
+	}
+	_, err := NewDomain(params)
+	c.Assert(err, gc.ErrorMatches, `unsupported disk type "vmdk"`)
+}
+
 func (domainXMLSuite) TestNewDomainError(c *gc.C) {
 	d, err := NewDomain(dummyParams{err: errors.Errorf("boom")})
 	c.Check(d, jc.DeepEquals, Domain{})
@@ -208,19 +295,21 @@ func (domainXMLSuite) TestNewDomainError(c *gc.C) {
 }
 
 type dummyParams struct {
-	err       error
-	arch      string
-	cpuCores  uint64
-	diskInfo  []DiskInfo
-	hostname  string
-	ifaceInfo []InterfaceInfo
-	loader    string
-	memory    uint64
-	nvram     string
+	err        error
+	arch       string
+	cpuCores   uint64
+	cpuPinning []uint
+	diskInfo   []DiskInfo
+	hostname   string
+	ifaceInfo  []InterfaceInfo
+	loader     string
+	memory     uint64
+	nvram      string
 }
 
 func (p dummyParams) Arch() string                 { return p.arch }
 func (p dummyParams) CPUs() uint64                 { return p.cpuCores }
+func (p dummyParams) CPUPinning() []uint           { return p.cpuPinning }
 func (p dummyParams) DiskInfo() []DiskInfo         { return p.diskInfo }
 func (p dummyParams) Host() string                 { return p.hostname }
 func (p dummyParams) Loader() string               { return p.loader }
@@ -237,6 +326,13 @@ type dummyDisk struct {
 func (d dummyDisk) Driver() string { return d.driver }
 func (d dummyDisk) Source() string { return d.source }
 
+type dummyPoolDisk struct {
+	dummyDisk
+	pool string
+}
+
+func (d dummyPoolDisk) Pool() string { return d.pool }
+
 type dummyInterface struct {
 	mac, parent, parentVirtualPortType, name string
 }
@@ -63,6 +63,16 @@ var (
 	}
 )
 
+// DiskParams describes an additional disk to be created as a volume in a
+// libvirt storage pool and attached to the guest.
+type DiskParams struct {
+	// Pool is the libvirt storage pool to create the volume in. If empty
+	// the juju-pool is used.
+	Pool string
+	// Size is the size of the volume in GB.
+	Size uint64
+}
+
 // CreateMachineParams Implements libvirt.domainParams.
 type CreateMachineParams struct {
 	Hostname          string
@@ -74,6 +84,14 @@ type CreateMachineParams struct {
 	RootDisk          uint64
 	Interfaces        []libvirt.InterfaceInfo
 
+	// Disks are extra disks attached to the guest after the system and
+	// data source disks.
+	Disks []DiskParams
+
+	// PinnedCPUs optionally pins each of the guest's virtual CPUs to the
+	// host CPU at the same index.
+	PinnedCPUs []uint
+
 	disks    []libvirt.DiskInfo
 	findPath func(string) (string, error)
 
@@ -109,6 +127,11 @@ func (p CreateMachineParams) CPUs() uint64 {
 	return p.CpuCores
 }
 
+// CPUPinning implements libvirt.domainParams.
+func (p CreateMachineParams) CPUPinning() []uint {
+	return p.PinnedCPUs
+}
+
 // DiskInfo implements libvirt.domainParams.
 func (p CreateMachineParams) DiskInfo() []libvirt.DiskInfo {
 	return p.disks
@@ -169,8 +192,19 @@ func (d diskInfo) Source() string {
 	return d.source
 }
 
+// poolDiskInfo is type for implementing libvirt.PoolDiskInfo.
+type poolDiskInfo struct {
+	diskInfo
+	pool string
+}
+
+// Pool implements libvirt.PoolDiskInfo.
+func (d poolDiskInfo) Pool() string {
+	return d.pool
+}
+
 // CreateMachine creates a virtual machine and starts it.
-func CreateMachine(params CreateMachineParams) error {
+func CreateMachine(params CreateMachineParams) (err error) {
 	if params.Hostname == "" {
 		return fmt.Errorf("hostname is required")
 	}
@@ -179,7 +213,7 @@ func CreateMachine(params CreateMachineParams) error {
 
 	templateDir := filepath.Dir(params.UserDataFile)
 
-	err := writeMetadata(templateDir)
+	err = writeMetadata(templateDir)
 	if err != nil {
 		return errors.Annotate(err, "failed to write instance metadata")
 	}
@@ -197,6 +231,29 @@ func CreateMachine(params CreateMachineParams) error {
 	params.disks = append(params.disks, diskInfo{source: imgPath, driver: "qcow2"})
 	params.disks = append(params.disks, diskInfo{source: dsPath, driver: "raw"})
 
+	// Until the domain is defined, the pool volumes created for it are
+	// not known to DestroyMachine, so they are removed here on failure.
+	var volumes []poolDiskInfo
+	defined := false
+	defer func() {
+		if err == nil || defined {
+			return
+		}
+		for _, v := range volumes {
+			if _, err := params.runCmdAsRoot("", virsh, "vol-delete", "--pool", v.pool, v.source); err != nil {
+				logger.Errorf("failed to remove volume %q in pool %q for %q: %s", v.source, v.pool, params.Host(), err)
+			}
+		}
+	}()
+	for i, disk := range params.Disks {
+		volume, err := createPoolVolume(params, i, disk)
+		if err != nil {
+			return errors.Annotatef(err, "failed to create disk %d for %q", i, params.Host())
+		}
+		volumes = append(volumes, volume)
+		params.disks = append(params.disks, volume)
+	}
+
 	domainPath, err := writeDomainXML(templateDir, params)
 	if err != nil {
 		return errors.Annotatef(err, "failed to write domain xml for %q", params.Host())
@@ -206,6 +263,7 @@ func CreateMachine(params CreateMachineParams) error {
 	if err != nil {
 		return errors.Annotatef(err, "failed to defined the domain for %q from %s", params.Host(), domainPath)
 	}
+	defined = true
 	logger.Debugf("created domain: %s", out)
 
 	out, err = params.runCmdAsRoot("", virsh, "start", params.Host())
@@ -246,6 +304,10 @@ func DestroyMachine(c *kvmContainer) error {
 	// all the parts. The exception here is getting the guestBase, if that
 	// fails we return the error because we cannot continue without it.
 
+	// Any extra disks live as volumes in storage pools, which are only
+	// known from the domain definition, so read it before undefining.
+	volumes := poolVolumes(c)
+
 	_, err := c.runCmd("", virsh, "destroy", c.Name())
 	if err != nil {
 		logger.Infof("`%s destroy %s` failed: %q", virsh, c.Name(), err)
@@ -271,10 +333,38 @@ func DestroyMachine(c *kvmContainer) error {
 	if err != nil {
 		logger.Errorf("failed to remove cloud-init data disk for %q: %s", c.Name(), err)
 	}
+	for _, v := range volumes {
+		_, err = c.runCmd("", virsh, "vol-delete", "--pool", v.Pool, v.Volume)
+		if err != nil {
+			logger.Errorf("failed to remove volume %q in pool %q for %q: %s", v.Volume, v.Pool, c.Name(), err)
+		}
+	}
 
 	return nil
 }
 
+// poolVolumes returns the storage pool volumes attached to the domain for
+// the container. Errors are logged, and result in no volumes.
+func poolVolumes(c *kvmContainer) []libvirt.DiskSource {
+	out, err := c.runCmd("", virsh, "dumpxml", c.Name())
+	if err != nil {
+		logger.Infof("`%s dumpxml %s` failed: %q", virsh, c.Name(), err)
+		return nil
+	}
+	var dom libvirt.Domain
+	if err := xml.Unmarshal([]byte(out), &dom); err != nil {
+		logger.Infof("failed to parse domain xml for %q: %s", c.Name(), err)
+		return nil
+	}
+	var volumes []libvirt.DiskSource
+	for _, d := range dom.Disk {
+		if d.Source.Pool != "" && d.Source.Volume != "" {
+			volumes = append(volumes, d.Source)
+		}
+	}
+	return volumes
+}
+
 // AutostartMachine indicates that the virtual machines should automatically
 // restart when the host restarts.
 func AutostartMachine(c *kvmContainer) error {
@@ -495,6 +585,38 @@ func writeRootDisk(params CreateMachineParams) (string, error) {
 	return imgPath, nil
 }
 
+// DiskVolumeName returns the name of the libvirt volume created for the
+// extra disk at the given index of the named machine.
+func DiskVolumeName(hostname string, index int) string {
+	return fmt.Sprintf("%s-disk%d", hostname, index)
+}
+
+// DiskDeviceName returns the guest device name of the extra disk at the
+// given index. Extra disks follow the system and data source disks.
+func DiskDeviceName(index int) string {
+	return fmt.Sprintf("vd%c", 'c'+index)
+}
+
+// createPoolVolume creates a qcow2 volume in a libvirt storage pool for the
+// extra disk at the given index.
+func createPoolVolume(params CreateMachineParams, index int, disk DiskParams) (poolDiskInfo, error) {
+	pool := disk.Pool
+	if pool == "" {
+		pool = poolName
+	}
+	if disk.Size == 0 {
+		return poolDiskInfo{}, errors.NotValidf("disk size 0")
+	}
+	volume := DiskVolumeName(params.Host(), index)
+	out, err := params.runCmdAsRoot(
+		"", virsh, "vol-create-as", pool, volume, fmt.Sprintf("%dG", disk.Size), "--format", "qcow2")
+	logger.Debugf("create volume: %s", out)
+	if err != nil {
+		return poolDiskInfo{}, errors.Trace(err)
+	}
+	return poolDiskInfo{diskInfo: diskInfo{source: volume, driver: "qcow2"}, pool: pool}, nil
+}
+
 // mustSpecifyBackingImageFormat returns true if the qemu-img command on the
 // host requests the backing image format to be explicitly provided as an
 // argument.
@@ -4,9 +4,11 @@
 package kvm_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -113,7 +115,7 @@ func (commandWrapperSuite) TestCreateMachineSuccess(c *gc.C) {
 		" virsh start host00",
 	}
 
-	assertCreateMachineSuccess(c, tmpDir, want)
+	assertCreateMachineSuccess(c, tmpDir, nil, want)
 }
 
 func (s *commandWrapperSuite) TestCreateMachineSuccessOnFocal(c *gc.C) {
@@ -133,9 +135,131 @@ func (s *commandWrapperSuite) TestCreateMachineSuccessOnFocal(c *gc.C) {
 		" virsh start host00",
 	}
 
-	assertCreateMachineSuccess(c, tmpDir, want)
+	assertCreateMachineSuccess(c, tmpDir, nil, want)
 }
-func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
+func (commandWrapperSuite) TestCreateMachineWithDisksSuccess(c *gc.C) {
+	tmpDir, err := ioutil.TempDir("", "juju-libvirtSuite-")
+	c.Check(err, jc.ErrorIsNil)
+
+	disks := []DiskParams{{Size: 10}, {Pool: "fast-pool", Size: 20}}
+	want := []string{
+		tmpDir + ` genisoimage -output \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00-ds\.iso -volid cidata -joliet -rock user-data meta-data network-config`,
+		` qemu-img create -b \/tmp/juju-libvirtSuite-\d+\/kvm\/guests\/precise-arm64-backing-file.qcow -f qcow2 \/tmp\/juju-libvirtSuite-\d+\/kvm\/guests\/host00.qcow 8G`,
+		" virsh vol-create-as juju-pool host00-disk0 10G --format qcow2",
+		" virsh vol-create-as fast-pool host00-disk1 20G --format qcow2",
+		` virsh define \/tmp\/juju-libvirtSuite-\d+\/host00.xml`,
+		" virsh start host00",
+	}
+
+	assertCreateMachineSuccess(c, tmpDir, disks, want)
+}
+
+func (commandWrapperSuite) TestCreateMachineDiskFails(c *gc.C) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = ioutil.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := CreateMachineParams{
+		Hostname:     "host00",
+		Series:       "precise",
+		UserDataFile: cloudInitPath,
+		RootDisk:     8,
+		Disks:        []DiskParams{{}},
+	}
+	stub := NewRunStub("success", nil)
+	MakeCreateMachineParamsTestable(&params, func(string) (string, error) { return tmpDir, nil }, stub.Run, "arm64")
+	err = CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `failed to create disk 0 for "host00": disk size 0 not valid`)
+}
+
+func (commandWrapperSuite) TestCreateMachineLaterDiskFailsRemovesVolumes(c *gc.C) {
+	params, stub := newFailingCreateMachineParams(c, "", []DiskParams{{Size: 10}, {}})
+	err := CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `failed to create disk 1 for "host00": disk size 0 not valid`)
+	assertVolumeCommands(c, stub.Calls(), []string{
+		" virsh vol-create-as juju-pool host00-disk0 10G --format qcow2",
+		" virsh vol-delete --pool juju-pool host00-disk0",
+	})
+}
+
+func (commandWrapperSuite) TestCreateMachineDefineFailsRemovesVolumes(c *gc.C) {
+	params, stub := newFailingCreateMachineParams(c, "define", []DiskParams{{Size: 10}, {Pool: "fast-pool", Size: 20}})
+	err := CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `failed to defined the domain for "host00" from .*: boom`)
+	assertVolumeCommands(c, stub.Calls(), []string{
+		" virsh vol-create-as juju-pool host00-disk0 10G --format qcow2",
+		" virsh vol-create-as fast-pool host00-disk1 20G --format qcow2",
+		" virsh vol-delete --pool juju-pool host00-disk0",
+		" virsh vol-delete --pool fast-pool host00-disk1",
+	})
+}
+
+func (commandWrapperSuite) TestCreateMachineStartFailsKeepsVolumes(c *gc.C) {
+	// Once the domain is defined, DestroyMachine finds and removes
+	// its volumes.
+	params, stub := newFailingCreateMachineParams(c, "start", []DiskParams{{Size: 10}})
+	err := CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `failed to start domain "host00": boom`)
+	assertVolumeCommands(c, stub.Calls(), []string{
+		" virsh vol-create-as juju-pool host00-disk0 10G --format qcow2",
+	})
+}
+
+// failingRunStub records the commands run, failing the virsh
+// subcommand it is given.
+type failingRunStub struct {
+	fail  string
+	calls []string
+}
+
+func (s *failingRunStub) Run(dir, cmd string, args ...string) (string, error) {
+	s.calls = append(s.calls, strings.Join(append([]string{dir, cmd}, args...), " "))
+	if cmd == "virsh" && len(args) > 0 && args[0] == s.fail {
+		return "", errors.New("boom")
+	}
+	return "success", nil
+}
+
+func (s *failingRunStub) Calls() []string {
+	return s.calls
+}
+
+func newFailingCreateMachineParams(c *gc.C, fail string, disks []DiskParams) (CreateMachineParams, *failingRunStub) {
+	tmpDir := c.MkDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = ioutil.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := CreateMachineParams{
+		Hostname:     "host00",
+		Series:       "precise",
+		UserDataFile: cloudInitPath,
+		RootDisk:     8,
+		Disks:        disks,
+	}
+	stub := &failingRunStub{fail: fail}
+	MakeCreateMachineParamsTestable(&params, func(string) (string, error) { return tmpDir, nil }, stub.Run, "arm64")
+	return params, stub
+}
+
+// assertVolumeCommands checks the storage pool volume commands among
+// the commands run.
+func assertVolumeCommands(c *gc.C, calls []string, expected []string) {
+	var volumeCalls []string
+	for _, call := range calls {
+		if strings.Contains(call, " vol-") {
+			volumeCalls = append(volumeCalls, call)
+		}
+	}
+	c.Assert(volumeCalls, jc.DeepEquals, expected)
+}
+
+func assertCreateMachineSuccess(c *gc.C, tmpDir string, disks []DiskParams, expCommands []string) {
 	stub := NewRunStub("success", nil)
 
 	err := os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
@@ -164,6 +288,7 @@ func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
 		NetworkConfigData: "this-is-network-config",
 		CpuCores:          1,
 		RootDisk:          8,
+		Disks:             disks,
 	}
 
 	MakeCreateMachineParamsTestable(&params, pathfinder, stub.Run, "arm64")
@@ -181,6 +306,16 @@ func assertCreateMachineSuccess(c *gc.C, tmpDir string, expCommands []string) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(b), gc.Equals, "this-is-network-config")
 
+	b, err = ioutil.ReadFile(filepath.Join(tmpDir, hostname+".xml"))
+	c.Assert(err, jc.ErrorIsNil)
+	for i, disk := range disks {
+		pool := disk.Pool
+		if pool == "" {
+			pool = "juju-pool"
+		}
+		c.Check(string(b), jc.Contains, fmt.Sprintf(`<source pool="%s" volume="%s-disk%d"></source>`, pool, hostname, i))
+	}
+
 	c.Check(len(stub.Calls()), gc.Equals, len(expCommands))
 	for i, cmd := range stub.Calls() {
 		c.Check(cmd, gc.Matches, expCommands[i])
@@ -208,8 +343,49 @@ func (commandWrapperSuite) TestDestroyMachineSuccess(c *gc.C) {
 	err = DestroyMachine(container)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		" virsh dumpxml aname",
+		" virsh destroy aname",
+		" virsh undefine --nvram aname",
+	})
+}
+
+func (commandWrapperSuite) TestDestroyMachineRemovesVolumes(c *gc.C) {
+	tmpDir := c.MkDir()
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+
+	domain := `
+<domain type="kvm">
+    <name>aname</name>
+    <devices>
+        <disk device="disk" type="file">
+            <driver type="qcow2" name="qemu"></driver>
+            <source file="/var/lib/juju/kvm/guests/aname.qcow"></source>
+            <target dev="vda"></target>
+        </disk>
+        <disk device="disk" type="volume">
+            <driver type="qcow2" name="qemu"></driver>
+            <source pool="juju-pool" volume="aname-disk0"></source>
+            <target dev="vdc"></target>
+        </disk>
+        <disk device="disk" type="volume">
+            <driver type="qcow2" name="qemu"></driver>
+            <source pool="fast-pool" volume="aname-disk1"></source>
+            <target dev="vdd"></target>
+        </disk>
+    </devices>
+</domain>`[1:]
+	stub := NewRunStub(domain, nil)
+	container := NewTestContainer("aname", stub.Run, pathfinder)
+	err := DestroyMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		" virsh dumpxml aname",
 		" virsh destroy aname",
 		" virsh undefine --nvram aname",
+		" virsh vol-delete --pool juju-pool aname-disk0",
+		" virsh vol-delete --pool fast-pool aname-disk1",
 	})
 }
 
@@ -218,6 +394,7 @@ func (commandWrapperSuite) TestDestroyMachineFails(c *gc.C) {
 	container := NewTestContainer("aname", stub.Run, nil)
 	err := DestroyMachine(container)
 	c.Check(stub.Calls(), jc.DeepEquals, []string{
+		" virsh dumpxml aname",
 		" virsh destroy aname",
 		" virsh undefine --nvram aname",
 	})
@@ -3,6 +3,12 @@
 
 package lxd
 
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
 func (s *Server) ClusterSupported() bool {
 	return s.clusterAPISupport
 }
@@ -14,3 +20,97 @@ func (s Server) UseTargetServer(name string) (*Server, error) {
 	logger.Debugf("creating LXD server for cluster node %q", name)
 	return NewServer(s.UseTarget(name))
 }
+
+// ClusterMemberCapacity describes the compute resources of a single
+// cluster member, along with how much of them is allocated to the
+// containers on it.
+type ClusterMemberCapacity struct {
+	// Name is the server name of the cluster member.
+	Name string
+
+	// CPUCores is the total number of CPU threads on the member.
+	CPUCores uint64
+
+	// MemoryMiB is the total memory on the member in MiB.
+	MemoryMiB uint64
+
+	// AllocatedCPUCores is the sum of the CPU limits of the
+	// containers on the member.
+	AllocatedCPUCores uint64
+
+	// AllocatedMemoryMiB is the sum of the memory limits of the
+	// containers on the member in MiB.
+	AllocatedMemoryMiB uint64
+}
+
+// FreeCPUCores returns the number of CPU cores not yet allocated
+// to containers on the member.
+func (c ClusterMemberCapacity) FreeCPUCores() uint64 {
+	if c.AllocatedCPUCores >= c.CPUCores {
+		return 0
+	}
+	return c.CPUCores - c.AllocatedCPUCores
+}
+
+// FreeMemoryMiB returns the memory not yet allocated to containers
+// on the member in MiB.
+func (c ClusterMemberCapacity) FreeMemoryMiB() uint64 {
+	if c.AllocatedMemoryMiB >= c.MemoryMiB {
+		return 0
+	}
+	return c.MemoryMiB - c.AllocatedMemoryMiB
+}
+
+// Containers without a CPU or memory limit can use as much of the
+// member as they need; they are counted as allocated this much.
+const (
+	unlimitedContainerCPUCores  = 1
+	unlimitedContainerMemoryMiB = 1024
+)
+
+// ClusterMemberCapacities returns the compute capacity of each online
+// member of the cluster.
+func (s *Server) ClusterMemberCapacities() ([]ClusterMemberCapacity, error) {
+	members, err := s.GetClusterMembers()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing cluster members")
+	}
+	containers, err := s.AliveContainers("")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	allocatedCPUCores := make(map[string]uint64)
+	allocatedMemoryMiB := make(map[string]uint64)
+	for _, c := range containers {
+		cores := uint64(c.CPUs())
+		if cores == 0 {
+			cores = unlimitedContainerCPUCores
+		}
+		mem := uint64(c.Mem())
+		if mem == 0 {
+			mem = unlimitedContainerMemoryMiB
+		}
+		allocatedCPUCores[c.Location] += cores
+		allocatedMemoryMiB[c.Location] += mem
+	}
+
+	const oneMiB = 1024 * 1024
+	var result []ClusterMemberCapacity
+	for _, m := range members {
+		if strings.ToLower(m.Status) != "online" {
+			continue
+		}
+		resources, err := s.UseTarget(m.ServerName).GetServerResources()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting resources for cluster member %q", m.ServerName)
+		}
+		result = append(result, ClusterMemberCapacity{
+			Name:               m.ServerName,
+			CPUCores:           resources.CPU.Total,
+			MemoryMiB:          resources.Memory.Total / oneMiB,
+			AllocatedCPUCores:  allocatedCPUCores[m.ServerName],
+			AllocatedMemoryMiB: allocatedMemoryMiB[m.ServerName],
+		})
+	}
+	return result, nil
+}
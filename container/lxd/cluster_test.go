@@ -8,9 +8,11 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/lxc/lxd/shared/api"
+	"github.com/pkg/errors"
+
 	"github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
-	"github.com/pkg/errors"
 )
 
 type clusterSuite struct {
@@ -51,3 +53,95 @@ func (s *imageSuite) TestUseTargetBadNode(c *gc.C) {
 	_, err = jujuSvr.UseTargetServer("cluster-2")
 	c.Assert(err, gc.ErrorMatches, "not a cluster member")
 }
+
+func (s *clusterSuite) TestClusterMemberCapacities(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	c1Svr := s.NewMockServerClustered(ctrl, "cluster-1")
+	c2Svr := lxdtesting.NewMockContainerServer(ctrl)
+
+	members := []api.ClusterMember{
+		{ServerName: "cluster-1", Status: "ONLINE"},
+		{ServerName: "cluster-2", Status: "ONLINE"},
+		{ServerName: "cluster-3", Status: "OFFLINE"},
+	}
+	containers := []api.Container{{
+		Name:         "juju-0",
+		Location:     "cluster-1",
+		StatusCode:   api.Running,
+		ContainerPut: api.ContainerPut{Config: map[string]string{"limits.cpu": "2"}},
+	}, {
+		Name:         "juju-1",
+		Location:     "cluster-1",
+		StatusCode:   api.Running,
+		ContainerPut: api.ContainerPut{Config: map[string]string{"limits.cpu": "1"}},
+	}, {
+		Name:         "juju-2",
+		Location:     "cluster-2",
+		StatusCode:   api.Stopped,
+		ContainerPut: api.ContainerPut{Config: map[string]string{"limits.cpu": "4", "limits.memory": "2048MiB"}},
+	}, {
+		// Containers without limits are counted as allocated
+		// one CPU core and 1GiB of memory.
+		Name:       "juju-3",
+		Location:   "cluster-2",
+		StatusCode: api.Running,
+	}}
+
+	resources := func(cpus, total, used uint64) *api.Resources {
+		return &api.Resources{
+			CPU:    api.ResourcesCPU{Total: cpus},
+			Memory: api.ResourcesMemory{Total: total << 20, Used: used << 20},
+		}
+	}
+
+	c1Exp := c1Svr.EXPECT()
+	c1Exp.GetClusterMembers().Return(members, nil)
+	c1Exp.GetContainers().Return(containers, nil)
+	c1Exp.UseTarget("cluster-1").Return(c1Svr)
+	c1Exp.GetServerResources().Return(resources(8, 16384, 4096), nil)
+	c1Exp.UseTarget("cluster-2").Return(c2Svr)
+	c2Svr.EXPECT().GetServerResources().Return(resources(4, 8192, 1024), nil)
+
+	jujuSvr, err := lxd.NewServer(c1Svr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	capacities, err := jujuSvr.ClusterMemberCapacities()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(capacities, jc.DeepEquals, []lxd.ClusterMemberCapacity{{
+		Name:               "cluster-1",
+		CPUCores:           8,
+		MemoryMiB:          16384,
+		AllocatedCPUCores:  3,
+		AllocatedMemoryMiB: 2048,
+	}, {
+		Name:               "cluster-2",
+		CPUCores:           4,
+		MemoryMiB:          8192,
+		AllocatedCPUCores:  5,
+		AllocatedMemoryMiB: 3072,
+	}})
+	c.Check(capacities[0].FreeCPUCores(), gc.Equals, uint64(5))
+	c.Check(capacities[0].FreeMemoryMiB(), gc.Equals, uint64(14336))
+	c.Check(capacities[1].FreeCPUCores(), gc.Equals, uint64(0))
+}
+
+func (s *clusterSuite) TestClusterMemberCapacitiesResourcesError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	c1Svr := s.NewMockServerClustered(ctrl, "cluster-1")
+
+	c1Exp := c1Svr.EXPECT()
+	c1Exp.GetClusterMembers().Return([]api.ClusterMember{{ServerName: "cluster-1", Status: "ONLINE"}}, nil)
+	c1Exp.GetContainers().Return(nil, nil)
+	c1Exp.UseTarget("cluster-1").Return(c1Svr)
+	c1Exp.GetServerResources().Return(nil, errors.New("boom"))
+
+	jujuSvr, err := lxd.NewServer(c1Svr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.ClusterMemberCapacities()
+	c.Assert(err, gc.ErrorMatches, `getting resources for cluster member "cluster-1": boom`)
+}
@@ -120,6 +120,32 @@ func (s *Server) FindImage(
 	return sourced, nil
 }
 
+// LocalImage returns the image in the server's local image store that is
+// identified by the input reference. As with the lxc client, the reference
+// may be either an alias or a fingerprint.
+func (s *Server) LocalImage(ref string) (SourcedImage, error) {
+	target := ref
+	entry, _, err := s.GetImageAlias(ref)
+	if err != nil && !IsLXDNotFound(err) {
+		return SourcedImage{}, errors.Trace(err)
+	}
+	if entry != nil {
+		target = entry.Target
+	}
+	image, _, err := s.GetImage(target)
+	if err != nil {
+		if IsLXDNotFound(err) {
+			return SourcedImage{}, errors.NotFoundf("image %q", ref)
+		}
+		return SourcedImage{}, errors.Trace(err)
+	}
+	logger.Debugf("Found image locally - %q %q", image.Filename, image.Fingerprint)
+	return SourcedImage{
+		Image:     image,
+		LXDServer: s.ContainerServer,
+	}, nil
+}
+
 // CopyRemoteImage accepts an image sourced from a remote server and copies it
 // to the local cache
 func (s *Server) CopyRemoteImage(
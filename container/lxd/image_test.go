@@ -86,6 +86,63 @@ func (s *imageSuite) TestFindImageLocalServerUnknownSeries(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `.*series: "pldlinux".*`)
 }
 
+func (s *imageSuite) TestLocalImageByAlias(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	iSvr := s.NewMockServer(ctrl)
+
+	alias := &lxdapi.ImageAliasesEntry{ImageAliasesEntryPut: lxdapi.ImageAliasesEntryPut{Target: "foo-target"}}
+	image := lxdapi.Image{Filename: "this-is-our-image"}
+	gomock.InOrder(
+		iSvr.EXPECT().GetImageAlias("custom").Return(alias, lxdtesting.ETag, nil),
+		iSvr.EXPECT().GetImage("foo-target").Return(&image, lxdtesting.ETag, nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(iSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := jujuSvr.LocalImage("custom")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(found.LXDServer, gc.Equals, iSvr)
+	c.Check(*found.Image, gc.DeepEquals, image)
+}
+
+func (s *imageSuite) TestLocalImageByFingerprint(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	iSvr := s.NewMockServer(ctrl)
+
+	image := lxdapi.Image{Filename: "this-is-our-image"}
+	gomock.InOrder(
+		iSvr.EXPECT().GetImageAlias("abc123").Return(nil, lxdtesting.ETag, errors.New("not found")),
+		iSvr.EXPECT().GetImage("abc123").Return(&image, lxdtesting.ETag, nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(iSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := jujuSvr.LocalImage("abc123")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*found.Image, gc.DeepEquals, image)
+}
+
+func (s *imageSuite) TestLocalImageNotFound(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	iSvr := s.NewMockServer(ctrl)
+
+	gomock.InOrder(
+		iSvr.EXPECT().GetImageAlias("custom").Return(nil, lxdtesting.ETag, errors.New("not found")),
+		iSvr.EXPECT().GetImage("custom").Return(nil, lxdtesting.ETag, errors.New("not found")),
+	)
+
+	jujuSvr, err := lxd.NewServer(iSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.LocalImage("custom")
+	c.Check(err, gc.ErrorMatches, `image "custom" not found`)
+}
+
 func (s *imageSuite) TestFindImageRemoteServers(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -10,4 +10,18 @@ type StorageConfig struct {
 	// AllowMount is true is the container is required to allow
 	// mounting block devices.
 	AllowMount bool
+
+	// Disks are extra disks to create and attach to the container,
+	// for container types that support them.
+	Disks []DiskConfig
+}
+
+// DiskConfig describes an extra disk to attach to a container.
+type DiskConfig struct {
+	// Pool is the host storage pool to create the disk in. If empty
+	// the container type's default pool is used.
+	Pool string
+
+	// Size is the size of the disk in GB.
+	Size uint64
 }
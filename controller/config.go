@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/charmrepo/v5/csclient"
@@ -16,9 +17,11 @@ import (
 	"github.com/juju/romulus"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
+	"golang.org/x/crypto/openpgp"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/core/resources"
 	"github.com/juju/juju/pki"
 )
@@ -108,6 +111,23 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// OIDCIssuerURL sets the URL of an OpenID Connect issuer trusted
+	// to authenticate controller users.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID sets the client ID that ID tokens issued by the
+	// OpenID Connect issuer must be addressed to.
+	OIDCClientID = "oidc-client-id"
+
+	// OIDCGroupsClaim sets the name of the ID token claim holding the
+	// groups the user is a member of.
+	OIDCGroupsClaim = "oidc-groups-claim"
+
+	// OIDCGroupAccess holds the mappings from OpenID Connect groups to
+	// controller and model access, each of the form <group>=<access>
+	// or <group>=<access>@<model-uuid>.
+	OIDCGroupAccess = "oidc-group-access"
+
 	// SetNUMAControlPolicyKey stores the value for this setting
 	SetNUMAControlPolicyKey = "set-numa-control-policy"
 
@@ -199,6 +219,10 @@ const (
 	// when writing to the raft log by setting this value to true.
 	NonSyncedWritesToRaftLog = "non-synced-writes-to-raft-log"
 
+	// AgentMirrorKeyring holds the armored PGP public keys trusted to
+	// sign agent mirror bundles imported into the controller.
+	AgentMirrorKeyring = "agent-mirror-keyring"
+
 	// Attribute Defaults
 
 	// DefaultAgentRateLimitMax allows the first 10 agents to connect without any
@@ -229,6 +253,10 @@ const (
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
 
+	// DefaultOIDCGroupsClaim is the default ID token claim holding
+	// the groups of an OpenID Connect user.
+	DefaultOIDCGroupsClaim = "groups"
+
 	// DefaultStatePort is the default port the controller is listening on.
 	DefaultStatePort int = 37017
 
@@ -334,6 +362,10 @@ var (
 		ControllerUUIDKey,
 		IdentityPublicKey,
 		IdentityURL,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCGroupsClaim,
+		OIDCGroupAccess,
 		SetNUMAControlPolicyKey,
 		StatePort,
 		MongoMemoryProfile,
@@ -361,6 +393,7 @@ var (
 		MaxCharmStateSize,
 		MaxAgentStateSize,
 		NonSyncedWritesToRaftLog,
+		AgentMirrorKeyring,
 	}
 
 	// For backwards compatibility, we must include "anything", "juju-apiserver"
@@ -406,6 +439,11 @@ var (
 		MaxCharmStateSize,
 		MaxAgentStateSize,
 		NonSyncedWritesToRaftLog,
+		AgentMirrorKeyring,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCGroupsClaim,
+		OIDCGroupAccess,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -669,6 +707,48 @@ func (c Config) IdentityURL() string {
 	return c.asString(IdentityURL)
 }
 
+// OIDCIssuerURL returns the URL of the trusted OpenID Connect issuer,
+// or the empty string if OpenID Connect logins are not enabled.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID that ID tokens must be issued to.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
+// OIDCGroupsClaim returns the name of the ID token claim holding the
+// user's groups.
+func (c Config) OIDCGroupsClaim() string {
+	if v := c.asString(OIDCGroupsClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCGroupsClaim
+}
+
+// OIDCGroupAccess returns the mappings from OpenID Connect groups
+// to controller and model access.
+func (c Config) OIDCGroupAccess() []permission.GroupClaimAccess {
+	mappings, err := permission.ParseGroupClaimAccessList(c.oidcGroupAccess())
+	if err != nil {
+		// We check the mappings can be parsed in the Validate
+		// function, so we really do not expect this to fail.
+		panic(err)
+	}
+	return mappings
+}
+
+func (c Config) oidcGroupAccess() []string {
+	var values []string
+	if value, ok := c[OIDCGroupAccess].([]interface{}); ok {
+		for _, item := range value {
+			values = append(values, item.(string))
+		}
+	}
+	return values
+}
+
 // AutocertURL returns the URL used to obtain official TLS certificates
 // when a client connects to the API. See AutocertURLKey
 // for more details.
@@ -834,6 +914,12 @@ func (c Config) MaxAgentStateSize() int {
 	return c.intOrDefault(MaxAgentStateSize, DefaultMaxAgentStateSize)
 }
 
+// AgentMirrorKeyring returns the armored PGP public keys trusted to sign
+// agent mirror bundles. An empty keyring means no bundles can be imported.
+func (c Config) AgentMirrorKeyring() string {
+	return c.asString(AgentMirrorKeyring)
+}
+
 // NonSyncedWritesToRaftLog returns true if fsync calls should be skipped
 // after each write to the raft log.
 func (c Config) NonSyncedWritesToRaftLog() bool {
@@ -866,6 +952,29 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[OIDCIssuerURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Annotate(err, "invalid OIDC issuer URL")
+		}
+		if u.Scheme != "https" {
+			return errors.Errorf("%s needs to be https", OIDCIssuerURL)
+		}
+		if c.OIDCClientID() == "" {
+			return errors.Errorf("%s required when %s provided", OIDCClientID, OIDCIssuerURL)
+		}
+	}
+
+	if _, err := permission.ParseGroupClaimAccessList(c.oidcGroupAccess()); err != nil {
+		return errors.Annotatef(err, "invalid %s", OIDCGroupAccess)
+	}
+
+	if v, ok := c[AgentMirrorKeyring].(string); ok && v != "" {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(v)); err != nil {
+			return errors.Annotatef(err, "invalid %s", AgentMirrorKeyring)
+		}
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -1113,6 +1222,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:                schema.ForceInt(),
 	IdentityURL:              schema.String(),
 	IdentityPublicKey:        schema.String(),
+	OIDCIssuerURL:            schema.String(),
+	OIDCClientID:             schema.String(),
+	OIDCGroupsClaim:          schema.String(),
+	OIDCGroupAccess:          schema.List(schema.String()),
 	SetNUMAControlPolicyKey:  schema.Bool(),
 	AutocertURLKey:           schema.String(),
 	AutocertDNSNameKey:       schema.String(),
@@ -1138,6 +1251,7 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxCharmStateSize:        schema.ForceInt(),
 	MaxAgentStateSize:        schema.ForceInt(),
 	NonSyncedWritesToRaftLog: schema.Bool(),
+	AgentMirrorKeyring:       schema.String(),
 }, schema.Defaults{
 	AgentRateLimitMax:        schema.Omit,
 	AgentRateLimitRate:       schema.Omit,
@@ -1153,6 +1267,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	StatePort:                DefaultStatePort,
 	IdentityURL:              schema.Omit,
 	IdentityPublicKey:        schema.Omit,
+	OIDCIssuerURL:            schema.Omit,
+	OIDCClientID:             schema.Omit,
+	OIDCGroupsClaim:          schema.Omit,
+	OIDCGroupAccess:          schema.Omit,
 	SetNUMAControlPolicyKey:  DefaultNUMAControlPolicy,
 	AutocertURLKey:           schema.Omit,
 	AutocertDNSNameKey:       schema.Omit,
@@ -1178,6 +1296,7 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxCharmStateSize:        DefaultMaxCharmStateSize,
 	MaxAgentStateSize:        DefaultMaxAgentStateSize,
 	NonSyncedWritesToRaftLog: DefaultNonSyncedWritesToRaftLog,
+	AgentMirrorKeyring:       schema.Omit,
 })
 
 // ConfigSchema holds information on all the fields defined by
@@ -1243,6 +1362,23 @@ set, the api-port isn't opened until the controllers have started properly.`,
 		Type:        environschema.Tstring,
 		Description: `The public key of the identity manager`,
 	},
+	OIDCIssuerURL: {
+		Type:        environschema.Tstring,
+		Description: `The URL of an OpenID Connect issuer trusted to authenticate users`,
+	},
+	OIDCClientID: {
+		Type:        environschema.Tstring,
+		Description: `The client ID registered for the controller with the OpenID Connect issuer`,
+	},
+	OIDCGroupsClaim: {
+		Type:        environschema.Tstring,
+		Description: `The ID token claim holding the groups of an OpenID Connect user`,
+	},
+	OIDCGroupAccess: {
+		Type: environschema.FieldType("list of strings"),
+		Description: `Mappings from OpenID Connect groups to controller access
+(<group>=<access>) or model access (<group>=<access>@<model-uuid>)`,
+	},
 	SetNUMAControlPolicyKey: {
 		Type:        environschema.Tbool,
 		Description: `Determines if the NUMA control policy is set`,
@@ -1346,4 +1482,8 @@ Use "caas-image-repo" instead.`,
 		Type:        environschema.Tbool,
 		Description: `Do not perform fsync calls after appending entries to the raft log. Disabling sync improves performance at the cost of reliability`,
 	},
+	AgentMirrorKeyring: {
+		Type:        environschema.Tstring,
+		Description: `The armored PGP public keys trusted to sign imported agent mirror bundles`,
+	},
 }
@@ -15,6 +15,8 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/juju/keys"
 	"github.com/juju/juju/testing"
 )
 
@@ -100,6 +102,43 @@ var newConfigTests = []struct {
 		controller.IdentityPublicKey: `xxxx`,
 	},
 	expectError: `invalid identity public key: wrong length for key, got 3 want 32`,
+}, {
+	about: "HTTPS OIDC issuer URL OK",
+	config: controller.Config{
+		controller.OIDCIssuerURL:   "https://sso.example.com/realms/juju",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"admins=superuser", "devs=write@deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+	},
+}, {
+	about: "HTTP OIDC issuer URL",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "http://sso.example.com",
+		controller.OIDCClientID:  "juju",
+	},
+	expectError: `oidc-issuer-url needs to be https`,
+}, {
+	about: "OIDC issuer URL requires client ID",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://sso.example.com",
+	},
+	expectError: `oidc-client-id required when oidc-issuer-url provided`,
+}, {
+	about: "invalid OIDC group access",
+	config: controller.Config{
+		controller.OIDCGroupAccess: []interface{}{"admins=admin"},
+	},
+	expectError: `invalid oidc-group-access: group claim access "admins=admin": "admin" controller access not valid`,
+}, {
+	about: "agent mirror keyring OK",
+	config: controller.Config{
+		controller.AgentMirrorKeyring: keys.JujuPublicKey,
+	},
+}, {
+	about: "invalid agent mirror keyring",
+	config: controller.Config{
+		controller.AgentMirrorKeyring: "not a key",
+	},
+	expectError: `invalid agent-mirror-keyring: .*`,
 }, {
 	about: "invalid management space name - whitespace",
 	config: controller.Config{
@@ -649,6 +688,39 @@ func (s *ConfigSuite) TestMeteringURLSettingValue(c *gc.C) {
 	c.Assert(cfg.MeteringURL(), gc.Equals, mURL)
 }
 
+func (s *ConfigSuite) TestOIDCDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.OIDCIssuerURL(), gc.Equals, "")
+	c.Check(cfg.OIDCGroupsClaim(), gc.Equals, controller.DefaultOIDCGroupsClaim)
+	c.Check(cfg.OIDCGroupAccess(), gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestOIDCSettingValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			controller.OIDCIssuerURL:   "https://sso.example.com",
+			controller.OIDCClientID:    "juju",
+			controller.OIDCGroupsClaim: "roles",
+			controller.OIDCGroupAccess: []string{"admins=superuser"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.OIDCIssuerURL(), gc.Equals, "https://sso.example.com")
+	c.Check(cfg.OIDCClientID(), gc.Equals, "juju")
+	c.Check(cfg.OIDCGroupsClaim(), gc.Equals, "roles")
+	c.Check(cfg.OIDCGroupAccess(), jc.DeepEquals, []permission.GroupClaimAccess{{
+		Group:  "admins",
+		Access: permission.SuperuserAccess,
+	}})
+}
+
 func (s *ConfigSuite) TestMaxDebugLogDuration(c *gc.C) {
 	cfg, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
@@ -0,0 +1,8 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+// CloudInitUserDataConfigOptionName is the option name used to set
+// cloud-init user data for the machines hosting an application's units.
+const CloudInitUserDataConfigOptionName = "cloudinit-userdata"
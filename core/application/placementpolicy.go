@@ -0,0 +1,143 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+)
+
+// PlacementStrategy describes how the units of an application are
+// placed relative to each other.
+type PlacementStrategy string
+
+const (
+	// PlacementStrategyNone leaves the placement of units to the
+	// default assignment policy.
+	PlacementStrategyNone PlacementStrategy = ""
+
+	// PlacementStrategySpread never places two units of the
+	// application on the same host, whether directly or in
+	// containers.
+	PlacementStrategySpread PlacementStrategy = "spread"
+
+	// PlacementStrategyPack places new containers for the units of
+	// the application on hosts that already run its units, and does
+	// not distribute its machines across availability zones.
+	PlacementStrategyPack PlacementStrategy = "pack"
+)
+
+const (
+	// PlacementAntiAffinityKey is the key of the applications
+	// whose units must not share a host with those of the
+	// application.
+	PlacementAntiAffinityKey = "anti-affinity"
+
+	// PlacementStrategyKey is the key of the placement strategy.
+	PlacementStrategyKey = "strategy"
+)
+
+// PlacementPolicy holds the rules for placing the units of an
+// application on machines.
+type PlacementPolicy struct {
+	// AntiAffinity lists the applications whose units must never
+	// share a host with the units of the application. The rule
+	// applies in both directions.
+	AntiAffinity []string
+
+	// Strategy is how the units of the application are placed
+	// relative to each other.
+	Strategy PlacementStrategy
+}
+
+// IsEmpty reports whether the policy has no rules.
+func (p PlacementPolicy) IsEmpty() bool {
+	return len(p.AntiAffinity) == 0 && p.Strategy == PlacementStrategyNone
+}
+
+// Validate returns an error if the policy is not valid for the named
+// application.
+func (p PlacementPolicy) Validate(appName string) error {
+	switch p.Strategy {
+	case PlacementStrategyNone, PlacementStrategySpread, PlacementStrategyPack:
+	default:
+		return errors.NotValidf("placement strategy %q", p.Strategy)
+	}
+	for _, name := range p.AntiAffinity {
+		if !names.IsValidApplication(name) {
+			return errors.NotValidf("anti-affinity application name %q", name)
+		}
+		if name == appName {
+			return errors.NotValidf("anti-affinity of %q with itself", name)
+		}
+	}
+	return nil
+}
+
+// HasAntiAffinity reports whether the units of the named application
+// must not share a host with those of the application.
+func (p PlacementPolicy) HasAntiAffinity(appName string) bool {
+	for _, name := range p.AntiAffinity {
+		if name == appName {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the policy in the form accepted by
+// ParsePlacementPolicy.
+func (p PlacementPolicy) String() string {
+	var parts []string
+	if len(p.AntiAffinity) > 0 {
+		parts = append(parts, fmt.Sprintf("%s=%s", PlacementAntiAffinityKey, strings.Join(p.AntiAffinity, ",")))
+	}
+	if p.Strategy != PlacementStrategyNone {
+		parts = append(parts, fmt.Sprintf("%s=%s", PlacementStrategyKey, p.Strategy))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParsePlacementPolicy parses a placement policy from key=value
+// arguments, each of which may hold several space separated pairs,
+// such as "anti-affinity=mysql,ceph-osd strategy=spread". An empty
+// value clears the rule.
+func ParsePlacementPolicy(args ...string) (PlacementPolicy, error) {
+	var policy PlacementPolicy
+	seen := set.NewStrings()
+	for _, arg := range args {
+		for _, field := range strings.Fields(arg) {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return PlacementPolicy{}, errors.NotValidf("placement policy %q without a value", field)
+			}
+			key, value := parts[0], parts[1]
+			if seen.Contains(key) {
+				return PlacementPolicy{}, errors.Errorf("placement policy %q specified more than once", key)
+			}
+			seen.Add(key)
+			switch key {
+			case PlacementAntiAffinityKey:
+				apps := set.NewStrings()
+				for _, name := range strings.Split(value, ",") {
+					if name != "" {
+						apps.Add(name)
+					}
+				}
+				if !apps.IsEmpty() {
+					policy.AntiAffinity = apps.SortedValues()
+				}
+			case PlacementStrategyKey:
+				policy.Strategy = PlacementStrategy(value)
+			default:
+				return PlacementPolicy{}, errors.NotValidf("placement policy %q", key)
+			}
+		}
+	}
+	return policy, nil
+}
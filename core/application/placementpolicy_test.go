@@ -0,0 +1,75 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/application"
+)
+
+type placementPolicySuite struct{}
+
+var _ = gc.Suite(&placementPolicySuite{})
+
+func (s *placementPolicySuite) TestParse(c *gc.C) {
+	policy, err := application.ParsePlacementPolicy("strategy=spread anti-affinity=mysql,ceph-osd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, application.PlacementPolicy{
+		AntiAffinity: []string{"ceph-osd", "mysql"},
+		Strategy:     application.PlacementStrategySpread,
+	})
+	c.Assert(policy.String(), gc.Equals, "anti-affinity=ceph-osd,mysql strategy=spread")
+	c.Assert(policy.HasAntiAffinity("mysql"), jc.IsTrue)
+	c.Assert(policy.HasAntiAffinity("wordpress"), jc.IsFalse)
+}
+
+func (s *placementPolicySuite) TestParseSeparateArgs(c *gc.C) {
+	policy, err := application.ParsePlacementPolicy("anti-affinity=mysql", "strategy=pack")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, jc.DeepEquals, application.PlacementPolicy{
+		AntiAffinity: []string{"mysql"},
+		Strategy:     application.PlacementStrategyPack,
+	})
+}
+
+func (s *placementPolicySuite) TestParseEmpty(c *gc.C) {
+	policy, err := application.ParsePlacementPolicy("anti-affinity= strategy=")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy.IsEmpty(), jc.IsTrue)
+	c.Assert(policy.String(), gc.Equals, "")
+}
+
+func (s *placementPolicySuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		arg string
+		err string
+	}{{
+		arg: "strategy",
+		err: `placement policy "strategy" without a value not valid`,
+	}, {
+		arg: "affinity=mysql",
+		err: `placement policy "affinity" not valid`,
+	}, {
+		arg: "strategy=pack strategy=spread",
+		err: `placement policy "strategy" specified more than once`,
+	}} {
+		c.Logf("test %d: %s", i, test.arg)
+		_, err := application.ParsePlacementPolicy(test.arg)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *placementPolicySuite) TestValidate(c *gc.C) {
+	policy := application.PlacementPolicy{AntiAffinity: []string{"mysql"}, Strategy: application.PlacementStrategySpread}
+	c.Assert(policy.Validate("wordpress"), jc.ErrorIsNil)
+	c.Assert(policy.Validate("mysql"), gc.ErrorMatches, `anti-affinity of "mysql" with itself not valid`)
+
+	policy = application.PlacementPolicy{AntiAffinity: []string{"Bad_Name"}}
+	c.Assert(policy.Validate("wordpress"), gc.ErrorMatches, `anti-affinity application name "Bad_Name" not valid`)
+
+	policy = application.PlacementPolicy{Strategy: "scatter"}
+	c.Assert(policy.Validate("wordpress"), gc.ErrorMatches, `placement strategy "scatter" not valid`)
+}
@@ -0,0 +1,10 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+// StatelessConfigOptionName is the option name used to declare that an
+// application's units keep no state on their machines. When a machine
+// pool loses a machine, units of stateless applications on it are
+// replaced by new units on the replacement machine.
+const StatelessConfigOptionName = "stateless"
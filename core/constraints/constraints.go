@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -31,7 +32,11 @@ const (
 	RootDiskSource = "root-disk-source"
 	Tags           = "tags"
 	InstanceType   = "instance-type"
+	ImageID        = "image-id"
+	ImageName      = "image-name"
 	Spaces         = "spaces"
+	Spot           = "spot"
+	MaxPrice       = "max-price"
 	VirtType       = "virt-type"
 	Zones          = "zones"
 )
@@ -84,12 +89,35 @@ type Value struct {
 	// be used. Only valid for clouds which support instance types.
 	InstanceType *string `json:"instance-type,omitempty" yaml:"instance-type,omitempty"`
 
+	// ImageID, if not nil or empty, holds the cloud specific id of the
+	// image a machine must be started from, such as an EC2 AMI id or an
+	// LXD image fingerprint. Image metadata is not consulted when it is
+	// set.
+	ImageID *string `json:"image-id,omitempty" yaml:"image-id,omitempty"`
+
+	// ImageName, if not nil or empty, holds the cloud specific name of
+	// the image a machine must be started from, such as an OpenStack
+	// image name, an LXD image alias or a GCE image. Image metadata is
+	// not consulted when it is set.
+	ImageName *string `json:"image-name,omitempty" yaml:"image-name,omitempty"`
+
 	// Spaces, if not nil, holds a list of juju network spaces that
 	// should be available (or not) on the machine. Positive and
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Spaces *[]string `json:"spaces,omitempty" yaml:"spaces,omitempty"`
 
+	// Spot, if true, indicates that the machine should be a spot (or
+	// preemptible) instance, which is cheaper but may be interrupted
+	// by the cloud at any time. Only valid for clouds which support
+	// such instances.
+	Spot *bool `json:"spot,omitempty" yaml:"spot,omitempty"`
+
+	// MaxPrice, if not nil or empty, holds the maximum hourly price, in
+	// the cloud's currency, to pay for a spot instance. It is only
+	// meaningful together with Spot.
+	MaxPrice *string `json:"max-price,omitempty" yaml:"max-price,omitempty"`
+
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
@@ -156,6 +184,23 @@ func (v *Value) HasInstanceType() bool {
 	return v.InstanceType != nil && *v.InstanceType != ""
 }
 
+// HasImageID returns true if the constraints.Value specifies an image id.
+func (v *Value) HasImageID() bool {
+	return v.ImageID != nil && *v.ImageID != ""
+}
+
+// HasImageName returns true if the constraints.Value specifies an image
+// name.
+func (v *Value) HasImageName() bool {
+	return v.ImageName != nil && *v.ImageName != ""
+}
+
+// HasImage returns true if the constraints.Value specifies the image to
+// start a machine from, by id or by name.
+func (v *Value) HasImage() bool {
+	return v.HasImageID() || v.HasImageName()
+}
+
 // extractItems returns the list of entries in the given field which
 // are either positive (included) or negative (!included; with prefix
 // "^").
@@ -198,6 +243,18 @@ func (v *Value) HasSpaces() bool {
 	return v.Spaces != nil && len(*v.Spaces) > 0
 }
 
+// HasSpot returns true if the constraints.Value specifies a spot
+// instance.
+func (v *Value) HasSpot() bool {
+	return v.Spot != nil && *v.Spot
+}
+
+// HasMaxPrice returns true if the constraints.Value specifies a maximum
+// spot price.
+func (v *Value) HasMaxPrice() bool {
+	return v.MaxPrice != nil && *v.MaxPrice != ""
+}
+
 // HasVirtType returns true if the constraints.Value specifies an virtual type.
 func (v *Value) HasVirtType() bool {
 	return v.VirtType != nil && *v.VirtType != ""
@@ -223,6 +280,12 @@ func (v Value) String() string {
 	if v.CpuPower != nil {
 		strs = append(strs, "cpu-power="+uintStr(*v.CpuPower))
 	}
+	if v.ImageID != nil {
+		strs = append(strs, "image-id="+(*v.ImageID))
+	}
+	if v.ImageName != nil {
+		strs = append(strs, "image-name="+(*v.ImageName))
+	}
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+(*v.InstanceType))
 	}
@@ -252,6 +315,12 @@ func (v Value) String() string {
 		s := strings.Join(*v.Spaces, ",")
 		strs = append(strs, "spaces="+s)
 	}
+	if v.Spot != nil {
+		strs = append(strs, "spot="+strconv.FormatBool(*v.Spot))
+	}
+	if v.MaxPrice != nil {
+		strs = append(strs, "max-price="+(*v.MaxPrice))
+	}
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+(*v.VirtType))
 	}
@@ -290,6 +359,12 @@ func (v Value) GoString() string {
 	if v.InstanceType != nil {
 		values = append(values, fmt.Sprintf("InstanceType: %q", *v.InstanceType))
 	}
+	if v.ImageID != nil {
+		values = append(values, fmt.Sprintf("ImageID: %q", *v.ImageID))
+	}
+	if v.ImageName != nil {
+		values = append(values, fmt.Sprintf("ImageName: %q", *v.ImageName))
+	}
 	if v.Container != nil {
 		values = append(values, fmt.Sprintf("Container: %q", *v.Container))
 	}
@@ -303,6 +378,12 @@ func (v Value) GoString() string {
 	} else if v.Spaces != nil {
 		values = append(values, "Spaces: (*[]string)(nil)")
 	}
+	if v.Spot != nil {
+		values = append(values, fmt.Sprintf("Spot: %v", *v.Spot))
+	}
+	if v.MaxPrice != nil {
+		values = append(values, fmt.Sprintf("MaxPrice: %q", *v.MaxPrice))
+	}
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
@@ -470,8 +551,16 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setTags(str)
 	case InstanceType:
 		err = v.setInstanceType(str)
+	case ImageID:
+		err = v.setImageID(str)
+	case ImageName:
+		err = v.setImageName(str)
 	case Spaces:
 		err = v.setSpaces(str)
+	case Spot:
+		err = v.setSpot(str)
+	case MaxPrice:
+		err = v.setMaxPrice(str)
 	case VirtType:
 		err = v.setVirtType(str)
 	case Zones:
@@ -517,6 +606,10 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.Container = &ctype
 		case InstanceType:
 			v.InstanceType = &vstr
+		case ImageID:
+			v.ImageID = &vstr
+		case ImageName:
+			v.ImageName = &vstr
 		case Cores:
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
@@ -539,6 +632,13 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			if err == nil {
 				v.Spaces = spaces
 			}
+		case Spot:
+			v.Spot, err = parseBool(vstr)
+		case MaxPrice:
+			err = validatePrice(vstr)
+			if err == nil {
+				v.MaxPrice = &vstr
+			}
 		case VirtType:
 			v.VirtType = &vstr
 		case Zones:
@@ -610,6 +710,22 @@ func (v *Value) setInstanceType(str string) error {
 	return nil
 }
 
+func (v *Value) setImageID(str string) error {
+	if v.ImageID != nil {
+		return errors.Errorf("already set")
+	}
+	v.ImageID = &str
+	return nil
+}
+
+func (v *Value) setImageName(str string) error {
+	if v.ImageName != nil {
+		return errors.Errorf("already set")
+	}
+	v.ImageName = &str
+	return nil
+}
+
 func (v *Value) setMem(str string) (err error) {
 	if v.Mem != nil {
 		return errors.Errorf("already set")
@@ -667,6 +783,25 @@ func (v *Value) validateSpaces(spaces *[]string) error {
 	return nil
 }
 
+func (v *Value) setSpot(str string) (err error) {
+	if v.Spot != nil {
+		return errors.Errorf("already set")
+	}
+	v.Spot, err = parseBool(str)
+	return
+}
+
+func (v *Value) setMaxPrice(str string) error {
+	if v.MaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validatePrice(str); err != nil {
+		return err
+	}
+	v.MaxPrice = &str
+	return nil
+}
+
 func (v *Value) setVirtType(str string) error {
 	if v.VirtType != nil {
 		return errors.Errorf("already set")
@@ -695,6 +830,33 @@ func parseUint64(str string) (*uint64, error) {
 	return &value, nil
 }
 
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be true or false")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+// validatePrice checks that str is empty or a non-negative decimal
+// price. The price is kept as a string so that it reaches the cloud
+// exactly as it was specified.
+func validatePrice(str string) error {
+	if str == "" {
+		return nil
+	}
+	if !validPrice.MatchString(str) {
+		return errors.Errorf("must be a non-negative decimal number")
+	}
+	return nil
+}
+
+var validPrice = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
 func parseSize(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
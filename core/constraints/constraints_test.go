@@ -352,6 +352,70 @@ var parseConstraintsTests = []struct {
 		args:    []string{"zones="},
 	},
 
+	// Spot
+	{
+		summary: "set spot",
+		args:    []string{"spot=true"},
+	}, {
+		summary: "set spot false",
+		args:    []string{"spot=false"},
+	}, {
+		summary: "set spot empty",
+		args:    []string{"spot="},
+	}, {
+		summary: "set nonsense spot",
+		args:    []string{"spot=maybe"},
+		err:     `bad "spot" constraint: must be true or false`,
+	}, {
+		summary: "double set spot together",
+		args:    []string{"spot=true spot=false"},
+		err:     `bad "spot" constraint: already set`,
+	},
+
+	// MaxPrice
+	{
+		summary: "set max-price",
+		args:    []string{"spot=true max-price=0.05"},
+	}, {
+		summary: "set integer max-price",
+		args:    []string{"max-price=2"},
+	}, {
+		summary: "set empty max-price",
+		args:    []string{"max-price="},
+	}, {
+		summary: "set negative max-price",
+		args:    []string{"max-price=-0.05"},
+		err:     `bad "max-price" constraint: must be a non-negative decimal number`,
+	}, {
+		summary: "set nonsense max-price",
+		args:    []string{"max-price=cheap"},
+		err:     `bad "max-price" constraint: must be a non-negative decimal number`,
+	}, {
+		summary: "double set max-price separately",
+		args:    []string{"max-price=1", "max-price=2"},
+		err:     `bad "max-price" constraint: already set`,
+	},
+
+	// ImageID and ImageName
+	{
+		summary: "set image-id",
+		args:    []string{"image-id=ami-0123456789abcdef0"},
+	}, {
+		summary: "set empty image-id",
+		args:    []string{"image-id="},
+	}, {
+		summary: "double set image-id together",
+		args:    []string{"image-id=ami-1 image-id=ami-2"},
+		err:     `bad "image-id" constraint: already set`,
+	}, {
+		summary: "set image-name",
+		args:    []string{"image-name=ubuntu-focal-custom"},
+	}, {
+		summary: "double set image-name separately",
+		args:    []string{"image-name=a", "image-name=b"},
+		err:     `bad "image-name" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -559,6 +623,31 @@ func (s *ConstraintsSuite) TestHasRootDiskSource(c *gc.C) {
 	c.Check(con.HasRootDiskSource(), jc.IsFalse)
 }
 
+func (s *ConstraintsSuite) TestHasSpot(c *gc.C) {
+	con := constraints.MustParse("spot=true")
+	c.Check(con.HasSpot(), jc.IsTrue)
+	con = constraints.MustParse("spot=false")
+	c.Check(con.HasSpot(), jc.IsFalse)
+	con = constraints.MustParse("max-price=0.1")
+	c.Check(con.HasSpot(), jc.IsFalse)
+	c.Check(con.HasMaxPrice(), jc.IsTrue)
+	con = constraints.MustParse("max-price=")
+	c.Check(con.HasMaxPrice(), jc.IsFalse)
+}
+
+func (s *ConstraintsSuite) TestHasImage(c *gc.C) {
+	con := constraints.MustParse("image-id=ami-1")
+	c.Check(con.HasImageID(), jc.IsTrue)
+	c.Check(con.HasImageName(), jc.IsFalse)
+	c.Check(con.HasImage(), jc.IsTrue)
+	con = constraints.MustParse("image-name=custom")
+	c.Check(con.HasImageID(), jc.IsFalse)
+	c.Check(con.HasImageName(), jc.IsTrue)
+	c.Check(con.HasImage(), jc.IsTrue)
+	con = constraints.MustParse("image-id= image-name=")
+	c.Check(con.HasImage(), jc.IsFalse)
+}
+
 func (s *ConstraintsSuite) TestHasRootDisk(c *gc.C) {
 	con := constraints.MustParse("root-disk=32G")
 	c.Check(con.HasRootDisk(), jc.IsTrue)
@@ -595,6 +684,8 @@ func (s *ConstraintsSuite) TestIsEmpty(c *gc.C) {
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 	con = constraints.MustParse("zones=")
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+	con = constraints.MustParse("spot=")
+	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 }
 
 func uint64p(i uint64) *uint64 {
@@ -605,6 +696,10 @@ func strp(s string) *string {
 	return &s
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
 func ctypep(ctype string) *instance.ContainerType {
 	res := instance.ContainerType(ctype)
 	return &res
@@ -647,6 +742,15 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Zones1", constraints.Value{Zones: nil}},
 	{"Zones2", constraints.Value{Zones: &[]string{}}},
 	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Spot1", constraints.Value{Spot: nil}},
+	{"Spot2", constraints.Value{Spot: boolp(false)}},
+	{"Spot3", constraints.Value{Spot: boolp(true)}},
+	{"MaxPrice1", constraints.Value{MaxPrice: strp("")}},
+	{"MaxPrice2", constraints.Value{MaxPrice: strp("0.125")}},
+	{"ImageID1", constraints.Value{ImageID: strp("")}},
+	{"ImageID2", constraints.Value{ImageID: strp("ami-0123456789abcdef0")}},
+	{"ImageName1", constraints.Value{ImageName: strp("")}},
+	{"ImageName2", constraints.Value{ImageName: strp("ubuntu-focal-custom")}},
 	{"All", constraints.Value{
 		Arch:           strp("i386"),
 		Container:      ctypep("lxd"),
@@ -659,6 +763,10 @@ var constraintsRoundtripTests = []roundTrip{
 		Spaces:         &[]string{"space1", "^space2"},
 		InstanceType:   strp("foo"),
 		Zones:          &[]string{"az1", "az2"},
+		Spot:           boolp(true),
+		MaxPrice:       strp("0.5"),
+		ImageID:        strp("ami-1"),
+		ImageName:      strp("custom"),
 	}},
 }
 
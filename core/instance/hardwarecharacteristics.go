@@ -38,6 +38,10 @@ type HardwareCharacteristics struct {
 
 	// AvailabilityZone defines the zone in which the machine resides.
 	AvailabilityZone *string `json:"availability-zone,omitempty" yaml:"availabilityzone,omitempty"`
+
+	// Image identifies the image the instance was started from, when
+	// it was chosen with the image-id or image-name constraint.
+	Image *string `json:"image,omitempty" yaml:"image,omitempty"`
 }
 
 func (hc HardwareCharacteristics) String() string {
@@ -66,6 +70,9 @@ func (hc HardwareCharacteristics) String() string {
 	if hc.AvailabilityZone != nil && *hc.AvailabilityZone != "" {
 		strs = append(strs, fmt.Sprintf("availability-zone=%s", *hc.AvailabilityZone))
 	}
+	if hc.Image != nil && *hc.Image != "" {
+		strs = append(strs, fmt.Sprintf("image=%s", *hc.Image))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -132,6 +139,8 @@ func (hc *HardwareCharacteristics) setRaw(raw string) error {
 		err = hc.setTags(str)
 	case "availability-zone":
 		err = hc.setAvailabilityZone(str)
+	case "image":
+		err = hc.setImage(str)
 	default:
 		return fmt.Errorf("unknown characteristic %q", name)
 	}
@@ -212,6 +221,16 @@ func (hc *HardwareCharacteristics) setAvailabilityZone(str string) error {
 	return nil
 }
 
+func (hc *HardwareCharacteristics) setImage(str string) error {
+	if hc.Image != nil {
+		return fmt.Errorf("already set")
+	}
+	if str != "" {
+		hc.Image = &str
+	}
+	return nil
+}
+
 // parseTags returns the tags in the value s
 func parseTags(s string) *[]string {
 	if s == "" {
@@ -278,6 +278,19 @@ var parseHardwareTests = []parseHardwareTestSpec{
 		err:     `bad "availability-zone" characteristic: already set`,
 	},
 
+	// "image" in detail.
+	{
+		summary: "set image empty",
+		args:    []string{"image="},
+	}, {
+		summary: "set image non-empty",
+		args:    []string{"image=ami-0123456789abcdef0"},
+	}, {
+		summary: "double set image separately",
+		args:    []string{"image=ami-1", "image=ami-2"},
+		err:     `bad "image" characteristic: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -298,7 +311,7 @@ func (s *HardwareSuite) TestParseHardware(c *gc.C) {
 func (s HardwareSuite) TestClone(c *gc.C) {
 	var hcNil *instance.HardwareCharacteristics
 	c.Assert(hcNil.Clone(), gc.IsNil)
-	hc := instance.MustParseHardware("root-disk=4G", "mem=2T", "cores=4096", "cpu-power=9001", "arch=armhf", "availability-zone=a_zone")
+	hc := instance.MustParseHardware("root-disk=4G", "mem=2T", "cores=4096", "cpu-power=9001", "arch=armhf", "availability-zone=a_zone", "image=ami-1")
 	hc2 := hc.Clone()
 	c.Assert(hc, jc.DeepEquals, *hc2)
 }
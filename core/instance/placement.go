@@ -31,7 +31,8 @@ type Placement struct {
 	// Directive is a scope-specific placement directive.
 	//
 	// For MachineScope or a container scope, this may be empty or
-	// the ID of an existing machine.
+	// the ID of an existing machine. For a container scope, it may
+	// also be a zone directive (zone=<name>) for a new host machine.
 	Directive string `json:"directive"`
 }
 
@@ -44,6 +45,14 @@ func isContainerType(s string) bool {
 	return err == nil
 }
 
+// IsZoneDirective returns true if the directive names an
+// availability zone (zone=<name>) rather than a machine.
+func IsZoneDirective(directive string) bool {
+	return strings.HasPrefix(directive, zonePrefix) && len(directive) > len(zonePrefix)
+}
+
+const zonePrefix = "zone="
+
 // ParsePlacement attempts to parse the specified string and create a
 // corresponding Placement structure.
 //
@@ -60,9 +69,13 @@ func ParsePlacement(directive string) (*Placement, error) {
 			return nil, ErrPlacementScopeMissing
 		}
 		// Sanity check: machine/container scopes require a machine ID as the value.
-		if (scope == MachineScope || isContainerType(scope)) && !names.IsValidMachine(directive) {
+		// A container scope may instead name the zone for a new host machine.
+		if scope == MachineScope && !names.IsValidMachine(directive) {
 			return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
 		}
+		if isContainerType(scope) && !names.IsValidMachine(directive) && !IsZoneDirective(directive) {
+			return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id or zone", directive, scope)
+		}
 		return &Placement{Scope: scope, Directive: directive}, nil
 	}
 	if names.IsValidMachine(directive) {
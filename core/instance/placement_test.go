@@ -34,10 +34,20 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		err: `invalid value "x" for "#" scope: expected machine-id`,
 	}, {
 		arg: "lxd:x",
-		err: `invalid value "x" for "lxd" scope: expected machine-id`,
+		err: `invalid value "x" for "lxd" scope: expected machine-id or zone`,
 	}, {
 		arg: "kvm:x",
-		err: `invalid value "x" for "kvm" scope: expected machine-id`,
+		err: `invalid value "x" for "kvm" scope: expected machine-id or zone`,
+	}, {
+		arg:             "lxd:zone=node01",
+		expectScope:     string(instance.LXD),
+		expectDirective: "zone=node01",
+	}, {
+		arg: "lxd:zone=",
+		err: `invalid value "zone=" for "lxd" scope: expected machine-id or zone`,
+	}, {
+		arg: "#:zone=node01",
+		err: `invalid value "zone=node01" for "#" scope: expected machine-id`,
 	}, {
 		arg:             "kvm:123",
 		expectScope:     string(instance.KVM),
@@ -32,6 +32,11 @@ type MigrationStatus struct {
 	// TargetInfo contains the details of how to connect to the target
 	// controller.
 	TargetInfo TargetInfo
+
+	// Offline is true when the model is transferred to the target
+	// controller in a model archive. TargetInfo is then empty until
+	// the archive has been imported, and holds no credentials.
+	Offline bool
 }
 
 // SerializedModel wraps a buffer contain a serialised Juju model as
@@ -85,3 +90,13 @@ func (i *ModelInfo) Validate() error {
 	}
 	return nil
 }
+
+// PrecheckFailure describes a single migration precheck which failed.
+type PrecheckFailure struct {
+	// Entity describes what failed the check, such as "model",
+	// "machine 0", "unit mysql/0" or "controller machine 0".
+	Entity string
+
+	// Message describes why the check failed.
+	Message string
+}
@@ -48,6 +48,25 @@ type TargetInfo struct {
 // Validate returns an error if the TargetInfo contains bad data. Nil
 // is returned otherwise.
 func (info *TargetInfo) Validate() error {
+	if err := info.ValidateAddress(); err != nil {
+		return err
+	}
+
+	if info.AuthTag.Id() == "" {
+		return errors.NotValidf("empty AuthTag")
+	}
+
+	if info.Password == "" && len(info.Macaroons) == 0 {
+		return errors.NotValidf("missing Password & Macaroons")
+	}
+
+	return nil
+}
+
+// ValidateAddress returns an error if the TargetInfo doesn't identify
+// the target controller and how to reach it. Unlike Validate, it
+// doesn't require credentials for the target controller.
+func (info *TargetInfo) ValidateAddress() error {
 	if !names.IsValidModel(info.ControllerTag.Id()) {
 		return errors.NotValidf("ControllerTag")
 	}
@@ -61,14 +80,5 @@ func (info *TargetInfo) Validate() error {
 			return errors.NotValidf("%q in Addrs", addr)
 		}
 	}
-
-	if info.AuthTag.Id() == "" {
-		return errors.NotValidf("empty AuthTag")
-	}
-
-	if info.Password == "" && len(info.Macaroons) == 0 {
-		return errors.NotValidf("missing Password & Macaroons")
-	}
-
 	return nil
 }
@@ -0,0 +1,129 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// GroupClaimAccess maps a group asserted by an external identity
+// provider onto an access level for either the controller or a
+// single model.
+type GroupClaimAccess struct {
+	// Group is the name of the group as it appears in the
+	// identity provider's group claim.
+	Group string
+
+	// Access is the access level granted to members of the group.
+	Access Access
+
+	// ModelUUID holds the UUID of the model the access applies to.
+	// If it is empty, the access applies to the controller.
+	ModelUUID string
+}
+
+// ParseGroupClaimAccess parses a group claim mapping of the form
+//
+//     <group>=<access>
+//
+// granting controller access, or
+//
+//     <group>=<access>@<model-uuid>
+//
+// granting access to a single model.
+func ParseGroupClaimAccess(s string) (GroupClaimAccess, error) {
+	i := strings.LastIndex(s, "=")
+	if i <= 0 {
+		return GroupClaimAccess{}, errors.NotValidf("group claim access %q", s)
+	}
+	result := GroupClaimAccess{Group: s[:i]}
+	access := s[i+1:]
+	if j := strings.Index(access, "@"); j >= 0 {
+		result.ModelUUID = access[j+1:]
+		access = access[:j]
+		if !utils.IsValidUUIDString(result.ModelUUID) {
+			return GroupClaimAccess{}, errors.NotValidf("model UUID %q in group claim access %q", result.ModelUUID, s)
+		}
+	}
+	result.Access = Access(access)
+	if err := result.Validate(); err != nil {
+		return GroupClaimAccess{}, errors.Annotatef(err, "group claim access %q", s)
+	}
+	return result, nil
+}
+
+// ParseGroupClaimAccessList parses each of the given group claim mappings.
+func ParseGroupClaimAccessList(values []string) ([]GroupClaimAccess, error) {
+	result := make([]GroupClaimAccess, len(values))
+	for i, v := range values {
+		g, err := ParseGroupClaimAccess(v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = g
+	}
+	return result, nil
+}
+
+// Validate returns an error if the access level is not valid for the
+// target of the mapping.
+func (g GroupClaimAccess) Validate() error {
+	if g.Group == "" {
+		return errors.NotValidf("empty group")
+	}
+	if g.ModelUUID == "" {
+		return ValidateControllerAccess(g.Access)
+	}
+	return ValidateModelAccess(g.Access)
+}
+
+// String returns the mapping in the form accepted by ParseGroupClaimAccess.
+func (g GroupClaimAccess) String() string {
+	if g.ModelUUID == "" {
+		return fmt.Sprintf("%s=%s", g.Group, g.Access)
+	}
+	return fmt.Sprintf("%s=%s@%s", g.Group, g.Access, g.ModelUUID)
+}
+
+// ResolveGroupClaimAccess returns the most capable access level granted
+// to any of the given groups on the controller (if modelUUID is empty)
+// or on the model with the given UUID. NoAccess is returned if none of
+// the groups are mapped for the target.
+func ResolveGroupClaimAccess(mappings []GroupClaimAccess, groups []string, modelUUID string) Access {
+	member := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		member[g] = true
+	}
+	result := NoAccess
+	for _, m := range mappings {
+		if m.ModelUUID != modelUUID || !member[m.Group] {
+			continue
+		}
+		if modelUUID == "" && m.Access.GreaterControllerAccessThan(result) {
+			result = m.Access
+		} else if modelUUID != "" && m.Access.GreaterModelAccessThan(result) {
+			result = m.Access
+		}
+	}
+	return result
+}
+
+// GroupClaimModelUUIDs returns the UUIDs of all the models referred to
+// by the given mappings, without duplicates.
+func GroupClaimModelUUIDs(mappings []GroupClaimAccess) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, m := range mappings {
+		if m.ModelUUID == "" || seen[m.ModelUUID] {
+			continue
+		}
+		seen[m.ModelUUID] = true
+		result = append(result, m.ModelUUID)
+	}
+	return result
+}
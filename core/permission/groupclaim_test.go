@@ -0,0 +1,86 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+)
+
+type groupClaimSuite struct{}
+
+var _ = gc.Suite(&groupClaimSuite{})
+
+const testModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (*groupClaimSuite) TestParseGroupClaimAccess(c *gc.C) {
+	for i, test := range []struct {
+		in     string
+		expect permission.GroupClaimAccess
+		err    string
+	}{{
+		in:     "admins=superuser",
+		expect: permission.GroupClaimAccess{Group: "admins", Access: permission.SuperuserAccess},
+	}, {
+		in:     "org=team=login",
+		expect: permission.GroupClaimAccess{Group: "org=team", Access: permission.LoginAccess},
+	}, {
+		in: "devs=write@" + testModelUUID,
+		expect: permission.GroupClaimAccess{
+			Group:     "devs",
+			Access:    permission.WriteAccess,
+			ModelUUID: testModelUUID,
+		},
+	}, {
+		in:  "devs",
+		err: `group claim access "devs" not valid`,
+	}, {
+		in:  "=login",
+		err: `group claim access "=login" not valid`,
+	}, {
+		in:  "devs=write",
+		err: `group claim access "devs=write": "write" controller access not valid`,
+	}, {
+		in:  "devs=superuser@" + testModelUUID,
+		err: `group claim access .*: "superuser" model access not valid`,
+	}, {
+		in:  "devs=read@not-a-uuid",
+		err: `model UUID "not-a-uuid" in group claim access .* not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.in)
+		g, err := permission.ParseGroupClaimAccess(test.in)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(g, jc.DeepEquals, test.expect)
+		c.Check(g.String(), gc.Equals, test.in)
+	}
+}
+
+func (*groupClaimSuite) TestResolveGroupClaimAccess(c *gc.C) {
+	mappings, err := permission.ParseGroupClaimAccessList([]string{
+		"everyone=login",
+		"admins=superuser",
+		"devs=write@" + testModelUUID,
+		"everyone=read@" + testModelUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	resolve := func(modelUUID string, groups ...string) permission.Access {
+		return permission.ResolveGroupClaimAccess(mappings, groups, modelUUID)
+	}
+	c.Check(resolve(""), gc.Equals, permission.NoAccess)
+	c.Check(resolve("", "everyone"), gc.Equals, permission.LoginAccess)
+	c.Check(resolve("", "everyone", "admins"), gc.Equals, permission.SuperuserAccess)
+	c.Check(resolve("", "devs"), gc.Equals, permission.NoAccess)
+	c.Check(resolve(testModelUUID, "everyone"), gc.Equals, permission.ReadAccess)
+	c.Check(resolve(testModelUUID, "everyone", "devs"), gc.Equals, permission.WriteAccess)
+	c.Check(resolve(testModelUUID, "admins"), gc.Equals, permission.NoAccess)
+
+	c.Check(permission.GroupClaimModelUUIDs(mappings), jc.DeepEquals, []string{testModelUUID})
+}
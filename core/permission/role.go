@@ -0,0 +1,262 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Role is a named set of permissions which restricts the API calls
+// a user can make. A role never grants more than the user's access
+// level; it limits which facade methods the user can call on top of
+// the usual access checks.
+//
+// Each permission is either the name of a capability, such as "ssh"
+// or "run-actions", or a facade method pattern of the form
+//
+//     <facade>.<method>
+//
+// where a "*" at the start or end of either part matches any
+// sequence of characters, e.g. "Application.Destroy*", "SSHClient.*"
+// or "Cloud.*Credential*". Requests to the HTTP endpoints of the API
+// server are matched as methods of the HTTPFacade pseudo facade.
+type Role struct {
+	Name        string
+	Permissions []string
+}
+
+// HTTPFacade is the facade name under which requests to the HTTP
+// endpoints of the API server are checked against roles. The method
+// name is the request's HTTP method followed by the endpoint name,
+// e.g. "HTTP.GetDebugLog" or "HTTP.PostCharms".
+const HTTPFacade = "HTTP"
+
+// Capabilities that can be used as role permissions.
+const (
+	// ViewCapability allows looking at the status and configuration
+	// of models and their contents.
+	ViewCapability = "view"
+
+	// RunActionsCapability allows running actions and commands on
+	// units and machines.
+	RunActionsCapability = "run-actions"
+
+	// SSHCapability allows connecting to machines and units with
+	// juju ssh, scp and debug-hooks.
+	SSHCapability = "ssh"
+
+	// DeployCapability allows deploying, relating, exposing and
+	// upgrading applications, and adding units and machines.
+	DeployCapability = "deploy"
+
+	// RemoveCapability allows removing applications, units,
+	// relations and machines.
+	RemoveCapability = "remove"
+
+	// ConfigureCapability allows changing model and application
+	// configuration and constraints.
+	ConfigureCapability = "configure"
+
+	// CredentialsCapability allows adding, updating and removing
+	// cloud credentials, and changing the credential used by models.
+	CredentialsCapability = "credentials"
+)
+
+// capabilityMethods maps each capability to the facade method
+// patterns it allows.
+var capabilityMethods = map[string][]string{
+	ViewCapability: {
+		"*.Watch*",
+		"Action.Actions",
+		"Action.ApplicationsCharmsActions",
+		"Action.ListAll",
+		"Action.ListCompleted",
+		"Action.ListOperations",
+		"Action.ListPending",
+		"Action.ListRunning",
+		"Action.Operations",
+		"Annotations.Get",
+		"Application.ApplicationsInfo",
+		"Application.CharmConfig",
+		"Application.CharmRelations",
+		"Application.GetCharmURL",
+		"Application.GetConstraints",
+		"Application.UnitsInfo",
+		"ApplicationOffers.ListApplicationOffers",
+		"Block.List",
+		"Charms.CharmInfo",
+		"Charms.IsMetered",
+		"Charms.List",
+		"Client.AgentVersion",
+		"Client.FullStatus",
+		"Client.GetModelConstraints",
+		"Client.ModelInfo",
+		"Client.ModelUserInfo",
+		"Client.StatusHistory",
+		"FirewallRules.ListFirewallRules",
+		"ModelConfig.ModelGet",
+		"ModelConfig.Sla",
+		"ModelGeneration.ListCommits",
+		"ModelGeneration.ShowCommit",
+		"ModelManager.ListModelSummaries",
+		"ModelManager.ListModels",
+		"ModelManager.ModelInfo",
+		"Resources.ListResources",
+		"Spaces.ListSpaces",
+		"Spaces.ShowSpace",
+		"Storage.ListFilesystems",
+		"Storage.ListPools",
+		"Storage.ListStorageDetails",
+		"Storage.ListVolumes",
+		"Storage.StorageDetails",
+		"Subnets.ListSubnets",
+		HTTPFacade + ".GetCharms",
+		HTTPFacade + ".GetDebugLog",
+		HTTPFacade + ".GetModelRest",
+	},
+	RunActionsCapability: {
+		"Action.*",
+	},
+	SSHCapability: {
+		"SSHClient.*",
+	},
+	DeployCapability: {
+		"Application.AddRelation",
+		"Application.AddUnits",
+		"Application.Consume",
+		"Application.Deploy",
+		"Application.Expose",
+		"Application.ResolveUnitErrors",
+		"Application.ScaleApplications",
+		"Application.SetCharm",
+		"Application.Unexpose",
+		"Application.UpdateApplicationSeries",
+		"Bundle.*",
+		"Charms.*",
+		"Client.AddCharm*",
+		"Client.AddMachines*",
+		"Client.Resolve*",
+		"MachineManager.AddMachines",
+		"Resources.*",
+		HTTPFacade + ".GetResources",
+		HTTPFacade + ".PostCharms",
+		HTTPFacade + ".PutResources",
+	},
+	RemoveCapability: {
+		"Application.Destroy*",
+		"Client.DestroyMachines",
+		"MachineManager.DestroyMachine*",
+		"MachineManager.ForceDestroyMachine",
+	},
+	ConfigureCapability: {
+		"Application.Set*",
+		"Application.Unset*",
+		"Application.Update*",
+		"Client.SetModelConstraints",
+		"ModelConfig.*",
+	},
+	CredentialsCapability: {
+		"Cloud.*Credential*",
+		"CredentialManager.*",
+		"ModelManager.ChangeModelCredential",
+	},
+}
+
+// Capabilities returns the names of the capabilities that can be
+// used as role permissions, in alphabetical order.
+func Capabilities() []string {
+	result := make([]string, 0, len(capabilityMethods))
+	for name := range capabilityMethods {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+var (
+	validRoleName      = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	validMethodPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*\*?|\*)\.([A-Za-z][A-Za-z0-9]*\*?|\*[A-Za-z0-9]*\*?)$`)
+)
+
+// IsValidRoleName reports whether name is a valid role name.
+func IsValidRoleName(name string) bool {
+	return validRoleName.MatchString(name)
+}
+
+// ValidateRolePermission returns an error if the permission is
+// neither a known capability nor a facade method pattern.
+func ValidateRolePermission(p string) error {
+	if _, ok := capabilityMethods[p]; ok {
+		return nil
+	}
+	if !validMethodPattern.MatchString(p) {
+		return errors.NotValidf("role permission %q", p)
+	}
+	return nil
+}
+
+// Validate returns an error if the role's name or any of its
+// permissions are not valid.
+func (r Role) Validate() error {
+	if !IsValidRoleName(r.Name) {
+		return errors.NotValidf("role name %q", r.Name)
+	}
+	if len(r.Permissions) == 0 {
+		return errors.NotValidf("role %q without permissions", r.Name)
+	}
+	for _, p := range r.Permissions {
+		if err := ValidateRolePermission(p); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the role permits calls to the given
+// facade method.
+func (r Role) Allows(facadeName, methodName string) bool {
+	for _, p := range r.Permissions {
+		patterns, ok := capabilityMethods[p]
+		if !ok {
+			patterns = []string{p}
+		}
+		for _, pattern := range patterns {
+			if matchMethodPattern(pattern, facadeName, methodName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchMethodPattern reports whether the facade method matches the
+// <facade>.<method> pattern.
+func matchMethodPattern(pattern, facadeName, methodName string) bool {
+	parts := strings.SplitN(pattern, ".", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	return matchName(parts[0], facadeName) && matchName(parts[1], methodName)
+}
+
+// matchName matches a name against a pattern in which a leading or
+// trailing "*" matches any sequence of characters.
+func matchName(pattern, name string) bool {
+	prefix := strings.HasPrefix(pattern, "*")
+	suffix := strings.HasSuffix(pattern, "*")
+	literal := strings.Trim(pattern, "*")
+	switch {
+	case prefix && suffix:
+		return strings.Contains(name, literal)
+	case prefix:
+		return strings.HasSuffix(name, literal)
+	case suffix:
+		return strings.HasPrefix(name, literal)
+	}
+	return name == literal
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+)
+
+type roleSuite struct{}
+
+var _ = gc.Suite(&roleSuite{})
+
+func (*roleSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		role permission.Role
+		err  string
+	}{{
+		role: permission.Role{Name: "operator", Permissions: []string{"ssh", "run-actions"}},
+	}, {
+		role: permission.Role{Name: "deployer", Permissions: []string{"Application.Deploy", "Charms.*", "Cloud.*Credential*", "*.List*"}},
+	}, {
+		role: permission.Role{Name: "Operator", Permissions: []string{"ssh"}},
+		err:  `role name "Operator" not valid`,
+	}, {
+		role: permission.Role{Name: "operator"},
+		err:  `role "operator" without permissions not valid`,
+	}, {
+		role: permission.Role{Name: "operator", Permissions: []string{"fly"}},
+		err:  `role permission "fly" not valid`,
+	}, {
+		role: permission.Role{Name: "operator", Permissions: []string{"Application.De*ploy"}},
+		err:  `role permission "Application.De\*ploy" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.role)
+		err := test.role.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (*roleSuite) TestAllows(c *gc.C) {
+	operator := permission.Role{
+		Name:        "operator",
+		Permissions: []string{permission.ViewCapability, permission.RunActionsCapability, permission.SSHCapability},
+	}
+	c.Check(operator.Allows("Client", "FullStatus"), jc.IsTrue)
+	c.Check(operator.Allows("Action", "Enqueue"), jc.IsTrue)
+	c.Check(operator.Allows("SSHClient", "PublicKeys"), jc.IsTrue)
+	c.Check(operator.Allows("Application", "Deploy"), jc.IsFalse)
+	c.Check(operator.Allows("Application", "DestroyApplication"), jc.IsFalse)
+	c.Check(operator.Allows("Storage", "ListStorageDetails"), jc.IsTrue)
+	c.Check(operator.Allows(permission.HTTPFacade, "GetDebugLog"), jc.IsTrue)
+	c.Check(operator.Allows(permission.HTTPFacade, "PostCharms"), jc.IsFalse)
+	// Viewing doesn't grant every method that looks like a read.
+	c.Check(operator.Allows("Cloud", "Credential"), jc.IsFalse)
+	c.Check(operator.Allows("Backups", "List"), jc.IsFalse)
+	c.Check(operator.Allows("ModelManager", "GetNewMethod"), jc.IsFalse)
+
+	deployer := permission.Role{
+		Name:        "deployer",
+		Permissions: []string{permission.DeployCapability, permission.RemoveCapability, "Client.FullStatus"},
+	}
+	c.Check(deployer.Allows("Application", "Deploy"), jc.IsTrue)
+	c.Check(deployer.Allows("Application", "DestroyUnit"), jc.IsTrue)
+	c.Check(deployer.Allows("Client", "FullStatus"), jc.IsTrue)
+	c.Check(deployer.Allows("Cloud", "UpdateCredentialsCheckModels"), jc.IsFalse)
+	c.Check(deployer.Allows("ModelManager", "ChangeModelCredential"), jc.IsFalse)
+	c.Check(deployer.Allows(permission.HTTPFacade, "PutResources"), jc.IsTrue)
+	c.Check(deployer.Allows(permission.HTTPFacade, "GetBackups"), jc.IsFalse)
+
+	credentials := permission.Role{Name: "creds", Permissions: []string{"Cloud.*Credential*"}}
+	c.Check(credentials.Allows("Cloud", "UpdateCredentialsCheckModels"), jc.IsTrue)
+	c.Check(credentials.Allows("Cloud", "RevokeCredentialsCheckModels"), jc.IsTrue)
+	c.Check(credentials.Allows("Cloud", "AddCloud"), jc.IsFalse)
+}
+
+func (*roleSuite) TestCapabilities(c *gc.C) {
+	c.Assert(permission.Capabilities(), jc.DeepEquals, []string{
+		"configure", "credentials", "deploy", "remove", "run-actions", "ssh", "view",
+	})
+}
@@ -116,6 +116,8 @@ const (
 	// Lost is set when:
 	// The juju agent has has not communicated with the juju server for an unexpectedly long time;
 	// the unit agent ought to be signalling activity, but none has been detected.
+	// As an instance status, it is set when the provider no longer knows of
+	// an instance that was running.
 	Lost Status = "lost"
 )
 
@@ -230,6 +232,14 @@ const (
 	Provisioning      Status = "allocating"
 	Running           Status = "running"
 	ProvisioningError Status = "provisioning error"
+
+	// Interrupting indicates that the cloud has given notice that it
+	// will reclaim a spot or preemptible instance.
+	Interrupting Status = "interrupting"
+
+	// Interrupted indicates that the cloud has reclaimed a spot or
+	// preemptible instance.
+	Interrupted Status = "interrupted"
 )
 
 // ModificationStatus
@@ -266,6 +276,8 @@ func (status Status) KnownInstanceStatus() bool {
 		ProvisioningError,
 		Allocating,
 		Running,
+		Interrupted,
+		Lost,
 		Error,
 		Unknown:
 		return true
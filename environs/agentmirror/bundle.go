@@ -0,0 +1,361 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agentmirror reads and writes agent mirror bundles. A bundle is
+// a gzipped tar archive of agent binaries and image metadata, used to
+// stock controllers that have no access to the official agent binaries
+// store. The bundle's manifest is signed, and lists the size and SHA-256
+// hash of every agent binary in the bundle, so that a controller can
+// check that each part of the bundle came from a trusted source before
+// using it.
+package agentmirror
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/environs/simplestreams"
+)
+
+const (
+	// manifestName is the name of the signed manifest in a bundle.
+	manifestName = "manifest.sjson"
+
+	// agentsDir is the directory in a bundle holding agent binaries.
+	agentsDir = "agents"
+)
+
+// Bundle holds the contents of an agent mirror bundle.
+type Bundle struct {
+	// Created is when the bundle was created.
+	Created time.Time
+
+	// Agents holds the agent binary tarballs in the bundle.
+	Agents []AgentBinary
+
+	// Images holds the image metadata in the bundle.
+	Images []ImageMetadata
+}
+
+// AgentBinary is an agent binary tarball held in a bundle. Size is
+// set when reading a bundle; Write uses the length of Data.
+type AgentBinary struct {
+	Version version.Binary
+	Size    int64
+	SHA256  string
+	Data    []byte
+}
+
+// ImageMetadata describes a cloud image that machines may be
+// started from.
+type ImageMetadata struct {
+	ImageId         string `json:"image-id"`
+	Region          string `json:"region"`
+	Version         string `json:"version"`
+	Series          string `json:"series"`
+	Arch            string `json:"arch"`
+	VirtType        string `json:"virt-type,omitempty"`
+	RootStorageType string `json:"root-storage-type,omitempty"`
+	Stream          string `json:"stream,omitempty"`
+}
+
+// manifest is the signed description of a bundle's contents.
+type manifest struct {
+	Created time.Time       `json:"created"`
+	Agents  []manifestAgent `json:"agents"`
+	Images  []ImageMetadata `json:"images,omitempty"`
+}
+
+type manifestAgent struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// Write writes the bundle to w, signing its manifest with the given
+// armored private key.
+func Write(w io.Writer, b Bundle, armoredPrivateKey, passphrase string) error {
+	m := manifest{
+		Created: b.Created.UTC(),
+		Images:  b.Images,
+	}
+	for _, agent := range b.Agents {
+		m.Agents = append(m.Agents, manifestAgent{
+			Version: agent.Version.String(),
+			Path:    agentPath(agent.Version),
+			Size:    int64(len(agent.Data)),
+			SHA256:  hashData(agent.Data),
+		})
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	signed, err := simplestreams.Encode(bytes.NewReader(data), armoredPrivateKey, passphrase)
+	if err != nil {
+		return errors.Annotate(err, "signing agent mirror manifest")
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	if err := writeFile(tw, manifestName, signed, m.Created); err != nil {
+		return errors.Trace(err)
+	}
+	for i, agent := range b.Agents {
+		if err := writeFile(tw, m.Agents[i].Path, agent.Data, m.Created); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(gzw.Close())
+}
+
+// Read reads a bundle written by Write into memory. It is a
+// convenience for small bundles; see NewReader for reading the agent
+// binaries as a stream.
+func Read(r io.Reader, armoredPublicKeys string) (*Bundle, error) {
+	br, err := NewReader(r, armoredPublicKeys)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer br.Close()
+	b := &Bundle{
+		Created: br.Created,
+		Images:  br.Images,
+	}
+	for {
+		agent, err := br.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		agent.Data, err = ioutil.ReadAll(br)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		b.Agents = append(b.Agents, *agent)
+	}
+	return b, nil
+}
+
+// Reader reads a bundle written by Write. The manifest, which Write
+// puts first, is read and its signature checked before anything else
+// in the bundle is looked at. The agent binaries are then read one at
+// a time, and each is checked against the manifest as it is read: a
+// file not listed in the manifest, or of a different size, is rejected
+// without reading it, and a hash mismatch is reported by the Read call
+// that returns the last of its data. Bundles whose agent binaries are
+// all read without error have been verified as a whole.
+type Reader struct {
+	// Created is when the bundle was created.
+	Created time.Time
+
+	// Images holds the image metadata in the bundle.
+	Images []ImageMetadata
+
+	gzr     *gzip.Reader
+	tr      *tar.Reader
+	pending map[string]manifestAgent
+
+	// current is the agent binary being read, and remaining the
+	// number of bytes of it left to read.
+	current   *AgentBinary
+	remaining int64
+	hash      hash.Hash
+	err       error
+}
+
+// maxManifestSize holds the largest manifest that is read. Manifests
+// hold a short entry for each agent binary, so are far smaller.
+const maxManifestSize = 1 << 20
+
+// NewReader returns a reader for the bundle read from r, after
+// checking the manifest's signature against the armored public keys.
+func NewReader(r io.Reader, armoredPublicKeys string) (_ *Reader, err error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading agent mirror bundle")
+	}
+	defer func() {
+		if err != nil {
+			gzr.Close()
+		}
+	}()
+	tr := tar.NewReader(gzr)
+	hdr, err := nextFile(tr)
+	if err == io.EOF || (err == nil && hdr.Name != manifestName) {
+		return nil, errors.NotValidf("agent mirror bundle without manifest")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "reading agent mirror bundle")
+	}
+	if hdr.Size > maxManifestSize {
+		return nil, errors.NotValidf("agent mirror manifest of %d bytes", hdr.Size)
+	}
+	signed, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %s", manifestName)
+	}
+	data, err := simplestreams.DecodeCheckSignature(bytes.NewReader(signed), armoredPublicKeys)
+	if err != nil {
+		return nil, errors.Annotate(err, "verifying agent mirror manifest")
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Annotate(err, "parsing agent mirror manifest")
+	}
+	pending := make(map[string]manifestAgent)
+	for _, agent := range m.Agents {
+		if _, err := version.ParseBinary(agent.Version); err != nil {
+			return nil, errors.Annotate(err, "parsing agent mirror manifest")
+		}
+		pending[agent.Path] = agent
+	}
+	return &Reader{
+		Created: m.Created,
+		Images:  m.Images,
+		gzr:     gzr,
+		tr:      tr,
+		pending: pending,
+	}, nil
+}
+
+// Next advances to the next agent binary in the bundle, returning its
+// version and hash; its data is read by calling Read. Any data of the
+// previous agent binary that was not read is read and checked first.
+// At the end of the bundle, Next returns io.EOF if every agent binary
+// in the manifest was found.
+func (r *Reader) Next() (*AgentBinary, error) {
+	if r.current != nil {
+		if _, err := io.Copy(ioutil.Discard, r); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	r.current, r.err = nil, nil
+	hdr, err := nextFile(r.tr)
+	if err == io.EOF {
+		for _, agent := range r.pending {
+			return nil, errors.NotFoundf("agent binary %s in agent mirror bundle", agent.Version)
+		}
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, errors.Annotate(err, "reading agent mirror bundle")
+	}
+	agent, ok := r.pending[hdr.Name]
+	if !ok {
+		// Every file in the bundle must be listed in the manifest,
+		// once.
+		return nil, errors.NotValidf("agent mirror bundle with unsigned file %q", hdr.Name)
+	}
+	delete(r.pending, hdr.Name)
+	vers := version.MustParseBinary(agent.Version)
+	if hdr.Size != agent.Size {
+		return nil, errors.Errorf("agent binary %s size mismatch (%d/%d)", vers, hdr.Size, agent.Size)
+	}
+	r.current = &AgentBinary{
+		Version: vers,
+		Size:    agent.Size,
+		SHA256:  agent.SHA256,
+	}
+	r.remaining = agent.Size
+	r.hash = sha256.New()
+	result := *r.current
+	return &result, nil
+}
+
+// Read reads the data of the current agent binary, as chosen by
+// Next. The data is never more than the size in the manifest, and
+// the read returning the last of it fails if the data does not have
+// the hash in the manifest.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.current == nil {
+		return 0, errors.New("no current agent binary")
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.tr.Read(p)
+	r.hash.Write(p[:n])
+	r.remaining -= int64(n)
+	if r.remaining > 0 {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			r.err = errors.Annotatef(err, "reading agent binary %s", r.current.Version)
+		}
+		return n, r.err
+	}
+	if sha256 := fmt.Sprintf("%x", r.hash.Sum(nil)); sha256 != r.current.SHA256 {
+		r.err = errors.Errorf("agent binary %s SHA-256 hash mismatch (%v/%v)", r.current.Version, sha256, r.current.SHA256)
+	}
+	return n, r.err
+}
+
+// Close releases the resources used by the reader. It does not close
+// the underlying reader.
+func (r *Reader) Close() error {
+	return errors.Trace(r.gzr.Close())
+}
+
+func agentPath(vers version.Binary) string {
+	return path.Join(agentsDir, fmt.Sprintf("juju-%s.tgz", vers))
+}
+
+func hashData(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Annotatef(err, "writing %s", name)
+	}
+	_, err := tw.Write(data)
+	return errors.Annotatef(err, "writing %s", name)
+}
+
+// nextFile returns the header of the next regular file in the tar
+// archive.
+func nextFile(tr *tar.Reader) (*tar.Header, error) {
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, errors.NotValidf("non-regular file %q", hdr.Name)
+		}
+		return hdr, nil
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentmirror_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"testing/iotest"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/agentmirror"
+	sstesting "github.com/juju/juju/environs/simplestreams/testing"
+	"github.com/juju/juju/juju/keys"
+)
+
+type bundleSuite struct{}
+
+var _ = gc.Suite(&bundleSuite{})
+
+var testBundle = agentmirror.Bundle{
+	Created: time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC),
+	Agents: []agentmirror.AgentBinary{{
+		Version: version.MustParseBinary("2.8.1-focal-amd64"),
+		Data:    []byte("focal agent"),
+	}, {
+		Version: version.MustParseBinary("2.8.1-bionic-arm64"),
+		Data:    []byte("bionic agent"),
+	}},
+	Images: []agentmirror.ImageMetadata{{
+		ImageId: "ami-1234",
+		Region:  "us-east-1",
+		Version: "20.04",
+		Series:  "focal",
+		Arch:    "amd64",
+		Stream:  "released",
+	}},
+}
+
+func (s *bundleSuite) writeBundle(c *gc.C) []byte {
+	var buf bytes.Buffer
+	err := agentmirror.Write(&buf, testBundle, sstesting.SignedMetadataPrivateKey, sstesting.PrivateKeyPassphrase)
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *bundleSuite) TestRoundTrip(c *gc.C) {
+	b, err := agentmirror.Read(bytes.NewReader(s.writeBundle(c)), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(b.Created, gc.Equals, testBundle.Created)
+	c.Check(b.Images, jc.DeepEquals, testBundle.Images)
+	c.Assert(b.Agents, gc.HasLen, 2)
+	for i, agent := range b.Agents {
+		c.Check(agent.Version, gc.Equals, testBundle.Agents[i].Version)
+		c.Check(agent.Data, jc.DeepEquals, testBundle.Agents[i].Data)
+		c.Check(agent.SHA256, gc.Equals, fmt.Sprintf("%x", sha256.Sum256(agent.Data)))
+	}
+}
+
+func (s *bundleSuite) TestLayout(c *gc.C) {
+	files := readArchive(c, s.writeBundle(c))
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	c.Assert(names, jc.DeepEquals, []string{
+		"agents/juju-2.8.1-bionic-arm64.tgz",
+		"agents/juju-2.8.1-focal-amd64.tgz",
+		"manifest.sjson",
+	})
+	c.Assert(string(files["manifest.sjson"]), jc.HasPrefix, "-----BEGIN PGP SIGNED MESSAGE-----")
+}
+
+func (s *bundleSuite) TestWriteBadKey(c *gc.C) {
+	var buf bytes.Buffer
+	err := agentmirror.Write(&buf, testBundle, sstesting.SignedMetadataPrivateKey, "wrong")
+	c.Assert(err, gc.ErrorMatches, "signing agent mirror manifest: .*")
+}
+
+func (s *bundleSuite) TestReadUntrustedKey(c *gc.C) {
+	_, err := agentmirror.Read(bytes.NewReader(s.writeBundle(c)), keys.JujuPublicKey)
+	c.Assert(err, gc.ErrorMatches, "verifying agent mirror manifest: .*")
+}
+
+func (s *bundleSuite) TestReadTamperedManifest(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		files["manifest.sjson"] = bytes.Replace(files["manifest.sjson"], []byte("ami-1234"), []byte("ami-6666"), 1)
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "verifying agent mirror manifest: .*")
+}
+
+func (s *bundleSuite) TestReadTamperedAgent(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		files["agents/juju-2.8.1-focal-amd64.tgz"] = []byte("focal AGENT")
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, `agent binary 2.8.1-focal-amd64 SHA-256 hash mismatch \(.*\)`)
+}
+
+func (s *bundleSuite) TestReadTruncatedAgent(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		files["agents/juju-2.8.1-focal-amd64.tgz"] = []byte("focal")
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, `agent binary 2.8.1-focal-amd64 size mismatch \(5/11\)`)
+}
+
+func (s *bundleSuite) TestReadMissingAgent(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		delete(files, "agents/juju-2.8.1-bionic-arm64.tgz")
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "agent binary 2.8.1-bionic-arm64 in agent mirror bundle not found")
+}
+
+func (s *bundleSuite) TestReadUnsignedFile(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		files["agents/juju-2.8.2-focal-amd64.tgz"] = []byte("sneaky agent")
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, `agent mirror bundle with unsigned file "agents/juju-2.8.2-focal-amd64.tgz" not valid`)
+}
+
+func (s *bundleSuite) TestReadMissingManifest(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		delete(files, "manifest.sjson")
+	})
+	_, err := agentmirror.Read(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "agent mirror bundle without manifest not valid")
+}
+
+func (s *bundleSuite) TestReadManifestNotFirst(c *gc.C) {
+	files := readArchive(c, s.writeBundle(c))
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, name := range []string{"agents/juju-2.8.1-focal-amd64.tgz", "manifest.sjson"} {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write(files[name])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+
+	_, err := agentmirror.Read(bytes.NewReader(buf.Bytes()), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "agent mirror bundle without manifest not valid")
+}
+
+func (s *bundleSuite) TestReaderStreamsAgents(c *gc.C) {
+	data := rewriteArchive(c, s.writeBundle(c), func(files map[string][]byte) {
+		files["agents/juju-2.8.1-focal-amd64.tgz"] = []byte("focal AGENT")
+	})
+	r, err := agentmirror.NewReader(bytes.NewReader(data), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	c.Check(r.Created, gc.Equals, testBundle.Created)
+	c.Check(r.Images, jc.DeepEquals, testBundle.Images)
+
+	// The untampered agent is read a byte at a time, and verified.
+	agent, err := r.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agent.Version, gc.Equals, version.MustParseBinary("2.8.1-bionic-arm64"))
+	read, err := ioutil.ReadAll(iotest.OneByteReader(r))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(read), gc.Equals, "bionic agent")
+
+	// The read returning the last of the tampered agent fails.
+	agent, err = r.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agent.Version, gc.Equals, version.MustParseBinary("2.8.1-focal-amd64"))
+	_, err = ioutil.ReadAll(r)
+	c.Assert(err, gc.ErrorMatches, `agent binary 2.8.1-focal-amd64 SHA-256 hash mismatch \(.*\)`)
+	_, err = r.Next()
+	c.Assert(err, gc.ErrorMatches, `agent binary 2.8.1-focal-amd64 SHA-256 hash mismatch \(.*\)`)
+}
+
+func (s *bundleSuite) TestReadNotArchive(c *gc.C) {
+	_, err := agentmirror.Read(bytes.NewReader([]byte("not a bundle")), sstesting.SignedMetadataPublicKey)
+	c.Assert(err, gc.ErrorMatches, "reading agent mirror bundle: .*")
+}
+
+func readArchive(c *gc.C, data []byte) map[string][]byte {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		files[hdr.Name], err = ioutil.ReadAll(tr)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return files
+}
+
+// rewriteArchive returns a copy of the bundle with its files changed
+// by the given function. The manifest is written first, as by
+// agentmirror.Write, and the other files in name order.
+func rewriteArchive(c *gc.C, data []byte, change func(map[string][]byte)) []byte {
+	files := readArchive(c, data)
+	change(files)
+	var names []string
+	for name := range files {
+		if name != "manifest.sjson" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := files["manifest.sjson"]; ok {
+		names = append([]string{"manifest.sjson"}, names...)
+	}
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, name := range names {
+		data := files[name]
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write(data)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
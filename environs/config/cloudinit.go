@@ -0,0 +1,91 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+// cloudInitListKeys are the cloud-init user data keys whose lists are
+// concatenated, rather than replaced, when user data is merged.
+var cloudInitListKeys = []string{"packages", "preruncmd", "postruncmd"}
+
+// ParseCloudInitUserData parses and validates cloud-init user data
+// YAML, as accepted by the cloudinit-userdata model config key, and
+// returns the user data attributes.
+func ParseCloudInitUserData(raw string) (map[string]interface{}, error) {
+	userDataMap, err := ensureStringMaps(raw)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// if there packages, ensure they are strings
+	if packages, ok := userDataMap["packages"].([]interface{}); ok {
+		for _, v := range packages {
+			checker := schema.String()
+			if _, err := checker.Coerce(v, nil); err != nil {
+				return nil, errors.Annotate(err, "packages must be a list of strings")
+			}
+		}
+	}
+
+	// error if users is specified
+	if _, ok := userDataMap["users"]; ok {
+		return nil, errors.New("users not allowed")
+	}
+
+	// error if runcmd is specified
+	if _, ok := userDataMap["runcmd"]; ok {
+		return nil, errors.New("runcmd not allowed, use preruncmd or postruncmd instead")
+	}
+
+	// error if bootcmd is specified
+	if _, ok := userDataMap["bootcmd"]; ok {
+		return nil, errors.New("bootcmd not allowed")
+	}
+	return userDataMap, nil
+}
+
+// MergeCloudInitUserData merges layers of cloud-init user data, such as
+// those of a model, an application and a machine, into one. Later
+// layers take precedence: their values replace those of earlier layers,
+// except that the packages, preruncmd and postruncmd lists of all layers
+// are concatenated in order, with duplicate packages dropped.
+//
+// Nil is returned if all of the layers are empty.
+func MergeCloudInitUserData(layers ...map[string]interface{}) map[string]interface{} {
+	var merged map[string]interface{}
+	for _, layer := range layers {
+		for k, v := range layer {
+			if merged == nil {
+				merged = make(map[string]interface{})
+			}
+			merged[k] = v
+		}
+	}
+	for _, key := range cloudInitListKeys {
+		var values []interface{}
+		seen := make(map[string]bool)
+		for _, layer := range layers {
+			list, ok := layer[key].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, v := range list {
+				if name, ok := v.(string); ok && key == "packages" {
+					if seen[name] {
+						continue
+					}
+					seen[name] = true
+				}
+				values = append(values, v)
+			}
+		}
+		if values != nil {
+			merged[key] = values
+		}
+	}
+	return merged
+}
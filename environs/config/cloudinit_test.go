@@ -0,0 +1,97 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+)
+
+type CloudInitSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&CloudInitSuite{})
+
+func (s *CloudInitSuite) TestParseCloudInitUserData(c *gc.C) {
+	userData, err := config.ParseCloudInitUserData(`
+packages: [htop]
+preruncmd:
+  - echo hello
+`[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(userData, jc.DeepEquals, map[string]interface{}{
+		"packages":  []interface{}{"htop"},
+		"preruncmd": []interface{}{"echo hello"},
+	})
+}
+
+func (s *CloudInitSuite) TestParseCloudInitUserDataInvalid(c *gc.C) {
+	for i, test := range []struct {
+		raw string
+		err string
+	}{{
+		raw: "packages: [76]",
+		err: `packages must be a list of strings: expected string, got int\(76\)`,
+	}, {
+		raw: "users: [bob]",
+		err: `users not allowed`,
+	}, {
+		raw: "runcmd: [ls]",
+		err: `runcmd not allowed, use preruncmd or postruncmd instead`,
+	}, {
+		raw: "bootcmd: [ls]",
+		err: `bootcmd not allowed`,
+	}, {
+		raw: "packages: [",
+		err: `must be valid YAML: .*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := config.ParseCloudInitUserData(test.raw)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *CloudInitSuite) TestMergeCloudInitUserData(c *gc.C) {
+	model := map[string]interface{}{
+		"packages":        []interface{}{"htop", "curl"},
+		"postruncmd":      []interface{}{"echo model"},
+		"package_upgrade": false,
+		"apt": map[string]interface{}{
+			"primary": "model-mirror",
+		},
+	}
+	application := map[string]interface{}{
+		"packages":  []interface{}{"curl", "nvidia-driver"},
+		"preruncmd": []interface{}{"echo application"},
+		"apt": map[string]interface{}{
+			"primary": "application-mirror",
+		},
+	}
+	machine := map[string]interface{}{
+		"postruncmd":      []interface{}{"echo machine"},
+		"package_upgrade": true,
+	}
+	merged := config.MergeCloudInitUserData(model, nil, application, machine)
+	c.Assert(merged, jc.DeepEquals, map[string]interface{}{
+		"packages":        []interface{}{"htop", "curl", "nvidia-driver"},
+		"preruncmd":       []interface{}{"echo application"},
+		"postruncmd":      []interface{}{"echo model", "echo machine"},
+		"package_upgrade": true,
+		"apt": map[string]interface{}{
+			"primary": "application-mirror",
+		},
+	})
+
+	// The layers are not changed.
+	c.Assert(model["packages"], jc.DeepEquals, []interface{}{"htop", "curl"})
+}
+
+func (s *CloudInitSuite) TestMergeCloudInitUserDataEmpty(c *gc.C) {
+	c.Assert(config.MergeCloudInitUserData(), gc.IsNil)
+	c.Assert(config.MergeCloudInitUserData(nil, map[string]interface{}{}), gc.IsNil)
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -253,6 +254,11 @@ const (
 	// LXDSnapChannel selects the channel to use when installing LXD from a snap.
 	LXDSnapChannel = "lxd-snap-channel"
 
+	// KVMPinnedCPUs lists the host CPUs, comma separated, that the
+	// virtual CPUs of KVM containers are pinned to. Each container is
+	// pinned to CPUs no other container on the host is pinned to.
+	KVMPinnedCPUs = "kvm-pinned-cpus"
+
 	// CharmhubURLKey is the key for the url to use for charmhub API calls
 	CharmhubURLKey = "charmhub-url"
 
@@ -695,35 +701,9 @@ func Validate(cfg, old *Config) error {
 	}
 
 	if raw, ok := cfg.defined[CloudInitUserDataKey].(string); ok && raw != "" {
-		userDataMap, err := ensureStringMaps(raw)
-		if err != nil {
+		if _, err := ParseCloudInitUserData(raw); err != nil {
 			return errors.Annotate(err, "cloudinit-userdata")
 		}
-
-		// if there packages, ensure they are strings
-		if packages, ok := userDataMap["packages"].([]interface{}); ok {
-			for _, v := range packages {
-				checker := schema.String()
-				if _, err := checker.Coerce(v, nil); err != nil {
-					return errors.Annotate(err, "cloudinit-userdata: packages must be a list of strings")
-				}
-			}
-		}
-
-		// error if users is specified
-		if _, ok := userDataMap["users"]; ok {
-			return errors.New("cloudinit-userdata: users not allowed")
-		}
-
-		// error if runcmd is specified
-		if _, ok := userDataMap["runcmd"]; ok {
-			return errors.New("cloudinit-userdata: runcmd not allowed, use preruncmd or postruncmd instead")
-		}
-
-		// error if bootcmd is specified
-		if _, ok := userDataMap["bootcmd"]; ok {
-			return errors.New("cloudinit-userdata: bootcmd not allowed")
-		}
 	}
 
 	if raw, ok := cfg.defined[ContainerInheritPropertiesKey].(string); ok && raw != "" {
@@ -740,6 +720,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if raw, ok := cfg.defined[KVMPinnedCPUs].(string); ok && raw != "" {
+		if _, err := ParseKVMPinnedCPUs(raw); err != nil {
+			return errors.Annotate(err, KVMPinnedCPUs)
+		}
+	}
+
 	if err := cfg.validateCharmhubURL(); err != nil {
 		return err
 	}
@@ -1459,6 +1445,29 @@ func (c *Config) LXDSnapChannel() string {
 	return c.asString(LXDSnapChannel)
 }
 
+// KVMPinnedCPUs returns the comma separated list of host CPUs that the
+// virtual CPUs of KVM containers are pinned to.
+func (c *Config) KVMPinnedCPUs() string {
+	return c.asString(KVMPinnedCPUs)
+}
+
+// ParseKVMPinnedCPUs parses a comma separated list of host CPU numbers,
+// as held by the kvm-pinned-cpus model config.
+func ParseKVMPinnedCPUs(raw string) ([]uint, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var cpus []uint
+	for _, field := range strings.Split(raw, ",") {
+		cpu, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil {
+			return nil, errors.NotValidf("host CPU %q", strings.TrimSpace(field))
+		}
+		cpus = append(cpus, uint(cpu))
+	}
+	return cpus, nil
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1590,6 +1599,7 @@ var alwaysOptional = schema.Defaults{
 	BackupDirKey:                  schema.Omit,
 	DefaultSpace:                  schema.Omit,
 	LXDSnapChannel:                schema.Omit,
+	KVMPinnedCPUs:                 schema.Omit,
 	CharmhubURLKey:                schema.Omit,
 }
 
@@ -2101,6 +2111,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	KVMPinnedCPUs: {
+		Description: "Host CPUs that the virtual CPUs of KVM containers are pinned to, each container to its own CPUs (comma-separated)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	CharmhubURLKey: {
 		Description: `The url for charmhub API calls`,
 		Type:        environschema.Tstring,
@@ -186,25 +186,52 @@ func copyTools(toolsDir, stream string, tools []*coretools.Tools, u ToolsUploade
 func copyOneToolsPackage(toolsDir, stream string, tools *coretools.Tools, u ToolsUploader) error {
 	toolsName := envtools.StorageName(tools.Version, toolsDir)
 	logger.Infof("downloading %q %v (%v)", stream, toolsName, tools.URL)
-	resp, err := utils.GetValidatingHTTPClient().Get(tools.URL)
+	data, err := FetchTools(tools)
 	if err != nil {
 		return err
 	}
+	sizeInKB := (len(data) + 512) / 1024
+	logger.Infof("uploading %v (%dkB) to model", toolsName, sizeInKB)
+	return u.UploadTools(toolsDir, stream, tools, data)
+}
+
+// FetchTools downloads the tarball for the given tools, verifying
+// its SHA-256 hash.
+func FetchTools(tools *coretools.Tools) ([]byte, error) {
+	resp, err := utils.GetValidatingHTTPClient().Get(tools.URL)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 	// Verify SHA-256 hash.
 	var buf bytes.Buffer
-	sha256, size, err := utils.ReadSHA256(io.TeeReader(resp.Body, &buf))
+	sha256, _, err := utils.ReadSHA256(io.TeeReader(resp.Body, &buf))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if tools.SHA256 == "" {
 		logger.Errorf("no SHA-256 hash for %v", tools.SHA256) // TODO(dfc) can you spot the bug ?
 	} else if sha256 != tools.SHA256 {
-		return errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, tools.SHA256)
+		return nil, errors.Errorf("SHA-256 hash mismatch (%v/%v)", sha256, tools.SHA256)
 	}
-	sizeInKB := (size + 512) / 1024
-	logger.Infof("uploading %v (%dkB) to model", toolsName, sizeInKB)
-	return u.UploadTools(toolsDir, stream, tools, buf.Bytes())
+	return buf.Bytes(), nil
+}
+
+// FindSourceTools returns the agent binaries with the given version
+// in the given stream of the source, which may be a URL or a local
+// directory. An empty source means the official agent binaries store,
+// and an empty stream means the preferred stream for the version.
+func FindSourceTools(source, stream string, vers version.Number) (coretools.List, error) {
+	sourceDataSource, err := selectSourceDatasource(&SyncContext{Source: source})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if stream == "" {
+		stream = envtools.PreferredStreams(&vers, false, "")[0]
+	}
+	return envtools.FindToolsForCloud(
+		[]simplestreams.DataSource{sourceDataSource}, simplestreams.CloudSpec{},
+		[]string{stream}, vers.Major, vers.Minor, coretools.Filter{Number: vers})
 }
 
 // UploadFunc is the type of Upload, which may be
@@ -6,6 +6,7 @@ package sync_test
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -192,6 +193,38 @@ func (s *syncSuite) TestSyncing(c *gc.C) {
 	}
 }
 
+func (s *syncSuite) TestFindSourceTools(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	list, err := sync.FindSourceTools(s.localStorage, "released", version.MustParse("1.8.0"))
+	c.Assert(err, jc.ErrorIsNil)
+	var found []version.Binary
+	for _, tools := range list {
+		found = append(found, tools.Version)
+	}
+	c.Assert(found, jc.SameContents, v180all)
+}
+
+func (s *syncSuite) TestFetchTools(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	list, err := sync.FindSourceTools(s.localStorage, "released", version.MustParse("1.8.0"))
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := sync.FetchTools(list[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Not(gc.HasLen), 0)
+
+	list[0].SHA256 = fmt.Sprintf("%x", sha256.Sum256(data))
+	_, err = sync.FetchTools(list[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	list[0].SHA256 = "deadbeef"
+	_, err = sync.FetchTools(list[0])
+	c.Assert(err, gc.ErrorMatches, `SHA-256 hash mismatch \(.*/deadbeef\)`)
+}
+
 type fakeToolsUploader struct {
 	uploaded map[version.Binary]bool
 }
@@ -246,3 +246,7 @@ func (*mockExecutor) Exec(params exec.ExecParams, cancel <-chan struct{}) error
 func (*mockExecutor) Copy(params exec.CopyParams, cancel <-chan struct{}) error {
 	return errors.NotImplementedf("exec copy")
 }
+
+func (*mockExecutor) Logs(params exec.LogsParams, cancel <-chan struct{}) error {
+	return errors.NotImplementedf("exec logs")
+}
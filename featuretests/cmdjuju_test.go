@@ -191,6 +191,11 @@ application-config:
     source: default
     type: bool
     value: false
+  kubernetes-ingress-tls-secret:
+    description: the name of the secret holding the TLS certificate for the external
+      hostname
+    source: unset
+    type: string
   kubernetes-service-annotations:
     description: a space separated set of annotations to add to the service
     source: unset
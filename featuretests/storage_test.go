@@ -226,6 +226,8 @@ block:
   provider: loop
   attrs:
     it: works
+kvm:
+  provider: kvm
 loop:
   provider: loop
 machinescoped:
@@ -252,6 +254,7 @@ func (s *cmdStorageSuite) TestListPoolsTabular(c *gc.C) {
 	expected := `
 Name                      Provider                  Attrs
 block                     loop                      it=works
+kvm                       kvm                       
 loop                      loop                      
 machinescoped             machinescoped             
 modelscoped               modelscoped               
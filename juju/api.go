@@ -44,6 +44,12 @@ type NewAPIConnectionParams struct {
 	// will be scoped to the model with that UUID; otherwise it will be
 	// scoped to the controller.
 	ModelUUID string
+
+	// APIToken is an optional API token issued to a service account.
+	// If specified, and AccountDetails is not nil, the login uses the
+	// token in place of the account details, and the account details
+	// in the store are left untouched.
+	APIToken string
 }
 
 // NewAPIConnection returns an api.Connection to the specified Juju controller,
@@ -126,7 +132,7 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 	// Process the account details obtained from login.
 	var accountDetails *jujuclient.AccountDetails
 	user, ok := st.AuthTag().(names.UserTag)
-	if !apiInfo.SkipLogin {
+	if !apiInfo.SkipLogin && args.APIToken == "" {
 		if ok {
 			if accountDetails, err = args.Store.AccountDetails(args.ControllerName); err != nil {
 				if !errors.IsNotFound(err) {
@@ -180,6 +186,11 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 		apiInfo.SkipLogin = true
 		return apiInfo, controller, nil
 	}
+	if args.APIToken != "" {
+		// The service account is taken from the token.
+		apiInfo.Password = args.APIToken
+		return apiInfo, controller, nil
+	}
 	account := args.AccountDetails
 	if account.User != "" {
 		userTag := names.NewUserTag(account.User)
@@ -187,6 +198,17 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 			apiInfo.Tag = userTag
 		}
 	}
+	if account.IDToken != "" {
+		// OpenID Connect users are not local, but they log in
+		// with their ID token as the credentials. The user may
+		// not be known yet, in which case the controller takes
+		// it from the token.
+		if account.User != "" {
+			apiInfo.Tag = names.NewUserTag(account.User)
+		}
+		apiInfo.Password = account.IDToken
+		return apiInfo, controller, nil
+	}
 	if args.AccountDetails.Password != "" {
 		// If a password is available, we always use that.
 		// If no password is recorded, we'll attempt to
@@ -138,6 +138,34 @@ func (s *NewAPIClientSuite) TestUpdatesLastKnownAccess(c *gc.C) {
 	)
 }
 
+func (s *NewAPIClientSuite) TestWithAPIToken(c *gc.C) {
+	store := newClientStore(c, "noconfig")
+
+	expectState := mockedAPIState(mockedHostPort | mockedModelTag)
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(apiInfo.Tag, gc.IsNil)
+		c.Check(apiInfo.Password, gc.Equals, "jujutoken.abc.secret")
+		return expectState, nil
+	}
+	accountDetails, err := store.AccountDetails("noconfig")
+	c.Assert(err, jc.ErrorIsNil)
+
+	stubStore := jujuclienttesting.WrapClientStore(store)
+	st, err := juju.NewAPIConnection(juju.NewAPIConnectionParams{
+		Store:          stubStore,
+		ControllerName: "noconfig",
+		AccountDetails: accountDetails,
+		DialOpts:       api.DefaultDialOpts(),
+		OpenAPI:        apiOpen,
+		APIToken:       "jujutoken.abc.secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.Equals, expectState)
+	// The account details are left untouched.
+	stubStore.CheckCallNames(c, "ControllerByName", "UpdateController")
+	c.Assert(store.Accounts["noconfig"], jc.DeepEquals, *accountDetails)
+}
+
 func (s *NewAPIClientSuite) TestUpdatesPublicDNSName(c *gc.C) {
 	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
 		conn := mockedAPIState(noFlags)
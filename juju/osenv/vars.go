@@ -22,6 +22,15 @@ const (
 	JujuXDGDataHomeEnvKey   = "JUJU_DATA"
 	JujuLoggingConfigEnvKey = "JUJU_LOGGING_CONFIG"
 
+	// JujuAPITokenEnvKey holds an API token issued to a service
+	// account. If set, clients log in to the controller with the
+	// token instead of the account recorded in the client store.
+	JujuAPITokenEnvKey = "JUJU_API_TOKEN"
+
+	// JujuStoreAgentEnvKey holds the socket path of the agent holding
+	// the key of an unlocked, encrypted client store.
+	JujuStoreAgentEnvKey = "JUJU_STORE_AGENT"
+
 	// JujuFeatureFlagEnvKey is used to enable prototype/developer only
 	// features that we don't want to expose by default to the general user.
 	// It is propagated to as an environment variable to all agents.
@@ -4,13 +4,11 @@
 package jujuclient
 
 import (
-	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
-	"github.com/juju/utils"
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/juju/osenv"
@@ -22,10 +20,11 @@ func JujuAccountsPath() string {
 	return osenv.JujuXDGDataHomePath("accounts.yaml")
 }
 
-// ReadAccountsFile loads all accounts defined in a given file.
-// If the file is not found, it is not an error.
+// ReadAccountsFile loads all accounts defined in a given file,
+// decrypting it if it is encrypted. If the file is not found, it is
+// not an error.
 func ReadAccountsFile(file string) (map[string]AccountDetails, error) {
-	data, err := ioutil.ReadFile(file)
+	data, err := readStoreFile(file)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -61,13 +60,14 @@ func migrateLocalAccountUsers(accounts map[string]AccountDetails) error {
 }
 
 // WriteAccountsFile marshals to YAML details of the given accounts
-// and writes it to the accounts file.
+// and writes it to the accounts file, encrypting it if the client
+// store is encrypted.
 func WriteAccountsFile(controllerAccounts map[string]AccountDetails) error {
 	data, err := yaml.Marshal(accountsCollection{controllerAccounts})
 	if err != nil {
 		return errors.Annotate(err, "cannot marshal accounts")
 	}
-	return utils.AtomicWriteFile(JujuAccountsPath(), data, os.FileMode(0600))
+	return writeStoreFile(JujuAccountsPath(), data)
 }
 
 // ParseAccounts parses the given YAML bytes into accounts metadata.
@@ -4,11 +4,9 @@
 package jujuclient
 
 import (
-	"io/ioutil"
 	"os"
 
 	"github.com/juju/errors"
-	"github.com/juju/utils"
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/cloud"
@@ -21,10 +19,11 @@ func JujuCredentialsPath() string {
 	return osenv.JujuXDGDataHomePath("credentials.yaml")
 }
 
-// ReadCredentialsFile loads all credentials defined in a given file.
-// If the file is not found, it is not an error.
+// ReadCredentialsFile loads all credentials defined in a given file,
+// decrypting it if it is encrypted. If the file is not found, it is
+// not an error.
 func ReadCredentialsFile(file string) (*cloud.CredentialCollection, error) {
-	data, err := ioutil.ReadFile(file)
+	data, err := readStoreFile(file)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &cloud.CredentialCollection{}, nil
@@ -39,11 +38,12 @@ func ReadCredentialsFile(file string) (*cloud.CredentialCollection, error) {
 }
 
 // WriteCredentialsFile marshals to YAML details of the given credentials
-// and writes it to the credentials file.
+// and writes it to the credentials file, encrypting it if the client
+// store is encrypted.
 func WriteCredentialsFile(credentials *cloud.CredentialCollection) error {
 	data, err := yaml.Marshal(credentials)
 	if err != nil {
 		return errors.Annotate(err, "cannot marshal yaml credentials")
 	}
-	return utils.AtomicWriteFile(JujuCredentialsPath(), data, os.FileMode(0600))
+	return writeStoreFile(JujuCredentialsPath(), data)
 }
@@ -0,0 +1,295 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// encryptedFileHeader starts every encrypted client store file, so
+// that encrypted files can be told apart from plaintext ones.
+const encryptedFileHeader = "# encrypted by juju; run \"juju unlock-store\" to access\n"
+
+// storeKeyCheck is sealed with the store key and kept in the key
+// file, so that a passphrase can be checked before it is used.
+const storeKeyCheck = "juju client store"
+
+// The scrypt parameters used to derive the store key from a
+// passphrase.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keySize = 32
+)
+
+// ErrStoreLocked is returned when the client store is encrypted and
+// no store agent holding the unlocked key can be reached.
+var ErrStoreLocked = errors.New(`client store is encrypted and locked; run "eval $(juju unlock-store)"`)
+
+var (
+	storeKeyMu sync.Mutex
+	storeKey   []byte
+)
+
+// JujuStoreKeyPath is the location of the file recording how the key
+// of an encrypted client store is derived from its passphrase. The
+// client store is encrypted if and only if the file exists.
+func JujuStoreKeyPath() string {
+	return osenv.JujuXDGDataHomePath("store-key.yaml")
+}
+
+// storeKeyFile holds the parameters used to derive the key of an
+// encrypted client store from its passphrase. The key itself is never
+// written to disk.
+type storeKeyFile struct {
+	Salt  string `yaml:"salt"`
+	N     int    `yaml:"n"`
+	R     int    `yaml:"r"`
+	P     int    `yaml:"p"`
+	Check string `yaml:"check"`
+}
+
+// IsStoreEncrypted reports whether the client store is encrypted.
+func IsStoreEncrypted() (bool, error) {
+	_, err := os.Stat(JujuStoreKeyPath())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, errors.Trace(err)
+}
+
+// UnlockStore derives the key of the encrypted client store from the
+// passphrase, returning an error if the passphrase is incorrect.
+func UnlockStore(passphrase string) ([]byte, error) {
+	data, err := ioutil.ReadFile(JujuStoreKeyPath())
+	if os.IsNotExist(err) {
+		return nil, errors.New("client store is not encrypted")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read store key file")
+	}
+	var keyFile storeKeyFile
+	if err := yaml.Unmarshal(data, &keyFile); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal store key file")
+	}
+	salt, err := base64.StdEncoding.DecodeString(keyFile.Salt)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode store key salt")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, keyFile.N, keyFile.R, keyFile.P, keySize)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot derive store key")
+	}
+	check, err := base64.StdEncoding.DecodeString(keyFile.Check)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode store key check")
+	}
+	if plaintext, err := decrypt(key, check); err != nil || string(plaintext) != storeKeyCheck {
+		return nil, errors.New("incorrect passphrase")
+	}
+	return key, nil
+}
+
+// EncryptStore converts a plaintext client store into an encrypted
+// one. A key is derived from the passphrase, and the accounts and
+// credentials files are rewritten encrypted with it. From then on,
+// the store must be unlocked with "juju unlock-store" before it can
+// be used.
+func EncryptStore(passphrase string) error {
+	if passphrase == "" {
+		return errors.NotValidf("empty passphrase")
+	}
+	s := &store{lockName: generateStoreLockName()}
+	releaser, err := s.acquireLock()
+	if err != nil {
+		return errors.Annotate(err, "cannot acquire lock file for encrypting the client store")
+	}
+	defer releaser.Release()
+
+	encrypted, err := IsStoreEncrypted()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encrypted {
+		return errors.AlreadyExistsf("encrypted client store")
+	}
+
+	// Read the plaintext files before the key file exists, and
+	// rewrite them once it does.
+	paths := []string{JujuAccountsPath(), JujuCredentialsPath()}
+	contents := make([][]byte, len(paths))
+	for i, path := range paths {
+		if contents[i], err = ioutil.ReadFile(path); err != nil && !os.IsNotExist(err) {
+			return errors.Annotatef(err, "cannot read %s", path)
+		}
+	}
+
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return errors.Annotate(err, "cannot generate store key salt")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return errors.Annotate(err, "cannot derive store key")
+	}
+	check, err := encrypt(key, []byte(storeKeyCheck))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(storeKeyFile{
+		Salt:  base64.StdEncoding.EncodeToString(salt),
+		N:     scryptN,
+		R:     scryptR,
+		P:     scryptP,
+		Check: base64.StdEncoding.EncodeToString(check),
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal store key file")
+	}
+	if err := utils.AtomicWriteFile(JujuStoreKeyPath(), data, os.FileMode(0600)); err != nil {
+		return errors.Trace(err)
+	}
+	SetStoreKey(key)
+
+	for i, path := range paths {
+		if contents[i] == nil {
+			continue
+		}
+		if err := writeStoreFile(path, contents[i]); err != nil {
+			return errors.Annotatef(err, "cannot encrypt %s", path)
+		}
+	}
+	return nil
+}
+
+// SetStoreKey sets the key used to access the encrypted client store
+// for the remainder of the process. Without it, the key is obtained
+// from the store agent named by the JUJU_STORE_AGENT environment
+// variable.
+func SetStoreKey(key []byte) {
+	storeKeyMu.Lock()
+	defer storeKeyMu.Unlock()
+	storeKey = key
+}
+
+// currentStoreKey returns the key used to access the encrypted client
+// store, asking the store agent for it if it is not yet known.
+func currentStoreKey() ([]byte, error) {
+	storeKeyMu.Lock()
+	defer storeKeyMu.Unlock()
+	if storeKey != nil {
+		return storeKey, nil
+	}
+	socketPath := os.Getenv(osenv.JujuStoreAgentEnvKey)
+	if socketPath == "" {
+		return nil, ErrStoreLocked
+	}
+	key, err := StoreAgentKey(socketPath)
+	if err != nil {
+		logger.Debugf("cannot get key from store agent: %v", err)
+		return nil, ErrStoreLocked
+	}
+	storeKey = key
+	return key, nil
+}
+
+// readStoreFile reads a client store file, decrypting it if it is
+// encrypted. If the file is not found, the error satisfies
+// os.IsNotExist.
+func readStoreFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(encryptedFileHeader)) {
+		return data, nil
+	}
+	key, err := currentStoreKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data[len(encryptedFileHeader):])))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot decode %s", path)
+	}
+	plaintext, err := decrypt(key, sealed)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot decrypt %s", path)
+	}
+	return plaintext, nil
+}
+
+// writeStoreFile writes a client store file, encrypting it if the
+// client store is encrypted.
+func writeStoreFile(path string, data []byte) error {
+	encrypted, err := IsStoreEncrypted()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if encrypted {
+		key, err := currentStoreKey()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sealed, err := encrypt(key, data)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		data = []byte(encryptedFileHeader + base64.StdEncoding.EncodeToString(sealed) + "\n")
+	}
+	return utils.AtomicWriteFile(path, data, os.FileMode(0600))
+}
+
+// encrypt seals the plaintext with AES-GCM, prefixing the result with
+// the random nonce used.
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Annotate(err, "cannot generate nonce")
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decrypt opens data sealed by encrypt.
+func decrypt(key, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted data too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	return plaintext, errors.Trace(err)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	return aead, errors.Trace(err)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type EncryptedStoreSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&EncryptedStoreSuite{})
+
+func (s *EncryptedStoreSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	jujuclient.SetStoreKey(nil)
+	s.AddCleanup(func(*gc.C) { jujuclient.SetStoreKey(nil) })
+}
+
+func (s *EncryptedStoreSuite) encryptStore(c *gc.C) {
+	writeTestAccountsFile(c)
+	credentials := &cloud.CredentialCollection{}
+	credentials.UpdateCloudCredential("aws", cloud.CloudCredential{
+		AuthCredentials: map[string]cloud.Credential{
+			"bob": cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+				"access-key": "key",
+				"secret-key": "sekrit",
+			}),
+		},
+	})
+	err := jujuclient.WriteCredentialsFile(credentials)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuclient.EncryptStore("passphrase")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EncryptedStoreSuite) TestEncryptStore(c *gc.C) {
+	encrypted, err := jujuclient.IsStoreEncrypted()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(encrypted, jc.IsFalse)
+
+	s.encryptStore(c)
+	encrypted, err = jujuclient.IsStoreEncrypted()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(encrypted, jc.IsTrue)
+
+	// The secrets are no longer in plaintext on disk.
+	for _, path := range []string{jujuclient.JujuAccountsPath(), jujuclient.JujuCredentialsPath()} {
+		data, err := ioutil.ReadFile(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Not(jc.Contains), "hunter2")
+		c.Check(string(data), gc.Not(jc.Contains), "sekrit")
+	}
+
+	// This process still holds the key.
+	accounts, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(accounts, jc.DeepEquals, testControllerAccounts)
+	credentials, err := jujuclient.ReadCredentialsFile(jujuclient.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	credential, err := credentials.CloudCredential("aws")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credential.AuthCredentials["bob"].Attributes()["secret-key"], gc.Equals, "sekrit")
+}
+
+func (s *EncryptedStoreSuite) TestEncryptStoreTwice(c *gc.C) {
+	s.encryptStore(c)
+	err := jujuclient.EncryptStore("passphrase")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *EncryptedStoreSuite) TestLocked(c *gc.C) {
+	s.encryptStore(c)
+	jujuclient.SetStoreKey(nil)
+	_, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(errors.Cause(err), gc.Equals, jujuclient.ErrStoreLocked)
+	err = jujuclient.WriteAccountsFile(testControllerAccounts)
+	c.Assert(errors.Cause(err), gc.Equals, jujuclient.ErrStoreLocked)
+}
+
+func (s *EncryptedStoreSuite) TestUnlockStore(c *gc.C) {
+	s.encryptStore(c)
+	_, err := jujuclient.UnlockStore("wrong")
+	c.Assert(err, gc.ErrorMatches, "incorrect passphrase")
+
+	key, err := jujuclient.UnlockStore("passphrase")
+	c.Assert(err, jc.ErrorIsNil)
+	jujuclient.SetStoreKey(key)
+	accounts, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(accounts, jc.DeepEquals, testControllerAccounts)
+}
+
+func (s *EncryptedStoreSuite) TestUnlockStoreNotEncrypted(c *gc.C) {
+	_, err := jujuclient.UnlockStore("passphrase")
+	c.Assert(err, gc.ErrorMatches, "client store is not encrypted")
+}
+
+func (s *EncryptedStoreSuite) TestStoreAgent(c *gc.C) {
+	s.encryptStore(c)
+	key, err := jujuclient.UnlockStore("passphrase")
+	c.Assert(err, jc.ErrorIsNil)
+	jujuclient.SetStoreKey(nil)
+
+	socketPath := filepath.Join(c.MkDir(), "agent.sock")
+	agent, err := jujuclient.NewStoreAgent(socketPath, key)
+	c.Assert(err, jc.ErrorIsNil)
+	done := make(chan error, 1)
+	go func() {
+		done <- agent.Serve()
+	}()
+	s.PatchEnvironment(osenv.JujuStoreAgentEnvKey, socketPath)
+
+	accounts, err := jujuclient.ReadAccountsFile(jujuclient.JujuAccountsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(accounts, jc.DeepEquals, testControllerAccounts)
+
+	err = jujuclient.LockStoreAgent(socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("store agent did not stop")
+	}
+	_, err = jujuclient.StoreAgentKey(socketPath)
+	c.Assert(err, gc.ErrorMatches, "cannot connect to store agent: .*")
+}
+
+func (s *EncryptedStoreSuite) TestPlaintextFilesStillRead(c *gc.C) {
+	s.encryptStore(c)
+	jujuclient.SetStoreKey(nil)
+	path := filepath.Join(c.MkDir(), "accounts.yaml")
+	err := ioutil.WriteFile(path, []byte(testAccountsYAML), os.FileMode(0600))
+	c.Assert(err, jc.ErrorIsNil)
+	accounts, err := jujuclient.ReadAccountsFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(accounts, jc.DeepEquals, testControllerAccounts)
+}
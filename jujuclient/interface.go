@@ -88,6 +88,11 @@ type AccountDetails struct {
 	// Password is the password for the account.
 	Password string `yaml:"password,omitempty"`
 
+	// IDToken is the OpenID Connect ID token for the account, used
+	// to log in to controllers that authenticate users with an
+	// external OpenID Connect issuer.
+	IDToken string `yaml:"id-token,omitempty"`
+
 	// LastKnownAccess is the last known access level for the account.
 	LastKnownAccess string `yaml:"last-known-access,omitempty"`
 }
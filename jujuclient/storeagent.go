@@ -0,0 +1,140 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// The requests understood by a store agent. Each request is a single
+// line; the agent answers "key" with the hex encoded store key.
+const (
+	storeAgentKeyRequest  = "key"
+	storeAgentLockRequest = "lock"
+)
+
+// storeAgentTimeout bounds how long a client waits on a store agent.
+const storeAgentTimeout = 5 * time.Second
+
+// StoreAgent holds the key of an unlocked client store for the
+// duration of a session, handing it out to juju clients over a unix
+// socket, in the manner of ssh-agent. The socket should be created in
+// a directory only the user can access.
+type StoreAgent struct {
+	key      []byte
+	listener net.Listener
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewStoreAgent returns a store agent listening on the socket path
+// and holding the key.
+func NewStoreAgent(socketPath string, key []byte) (*StoreAgent, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot listen for store agent requests")
+	}
+	return &StoreAgent{key: append([]byte(nil), key...), listener: listener}, nil
+}
+
+// Serve answers requests until the agent is locked or closed.
+func (a *StoreAgent) Serve() error {
+	for {
+		conn, err := a.listener.Accept()
+		if err != nil {
+			a.mu.Lock()
+			closed := a.closed
+			a.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return errors.Trace(err)
+		}
+		if a.handle(conn) {
+			return errors.Trace(a.Close())
+		}
+	}
+}
+
+// handle answers a single request, reporting whether the agent
+// should be locked.
+func (a *StoreAgent) handle(conn net.Conn) bool {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(storeAgentTimeout))
+	request, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		logger.Debugf("cannot read store agent request: %v", err)
+		return false
+	}
+	switch strings.TrimSpace(request) {
+	case storeAgentKeyRequest:
+		fmt.Fprintln(conn, hex.EncodeToString(a.key))
+	case storeAgentLockRequest:
+		return true
+	}
+	return false
+}
+
+// Close stops the agent, forgetting the key.
+func (a *StoreAgent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	for i := range a.key {
+		a.key[i] = 0
+	}
+	return errors.Trace(a.listener.Close())
+}
+
+// StoreAgentKey asks the store agent listening on the socket path for
+// the key of the client store.
+func StoreAgentKey(socketPath string) ([]byte, error) {
+	response, err := storeAgentRequest(socketPath, storeAgentKeyRequest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(response))
+	if err != nil || len(key) != keySize {
+		return nil, errors.New("invalid key from store agent")
+	}
+	return key, nil
+}
+
+// LockStoreAgent stops the store agent listening on the socket path.
+func LockStoreAgent(socketPath string) error {
+	_, err := storeAgentRequest(socketPath, storeAgentLockRequest)
+	return errors.Trace(err)
+}
+
+func storeAgentRequest(socketPath, request string) (string, error) {
+	conn, err := net.DialTimeout("unix", socketPath, storeAgentTimeout)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot connect to store agent")
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(storeAgentTimeout))
+	if _, err := fmt.Fprintln(conn, request); err != nil {
+		return "", errors.Annotate(err, "cannot send store agent request")
+	}
+	if request == storeAgentLockRequest {
+		return "", nil
+	}
+	response, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", errors.Annotate(err, "cannot read store agent response")
+	}
+	return response, nil
+}
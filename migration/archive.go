@@ -0,0 +1,281 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/yaml.v2"
+
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/tools"
+)
+
+// ArchiveFormatVersion is the version of the model archive format
+// written by ArchiveWriter.
+const ArchiveFormatVersion = 1
+
+// Names of the archive entries holding the metadata and the model
+// description.
+const (
+	archiveMetadataName = "metadata.yaml"
+	archiveModelName    = "model.yaml"
+)
+
+// ArchiveMetadata describes the contents of a model archive.
+type ArchiveMetadata struct {
+	// FormatVersion is the version of the archive format.
+	FormatVersion int `yaml:"format-version"`
+
+	// ControllerAgentVersion is the agent version of the controller the
+	// model was exported from.
+	ControllerAgentVersion version.Number `yaml:"controller-agent-version"`
+
+	// SourceControllerUUID is the UUID of the controller the model was
+	// exported from.
+	SourceControllerUUID string `yaml:"source-controller-uuid"`
+
+	// MigrationId is the id of the offline migration which holds the
+	// model on the source controller until the archive is imported.
+	MigrationId string `yaml:"migration-id"`
+}
+
+func charmArchiveName(curl *charm.URL) string {
+	return "charms/" + url.PathEscape(curl.String())
+}
+
+func toolsArchiveName(v version.Binary) string {
+	return "tools/" + v.String() + ".tgz"
+}
+
+func resourceArchiveName(application, name string) string {
+	return "resources/" + url.PathEscape(application) + "/" + url.PathEscape(name)
+}
+
+// ArchiveWriter writes a model, along with the charms, agent binaries and
+// resources it uses, into a single archive which can be imported into
+// another controller without the two controllers being connected.
+//
+// ArchiveWriter implements the uploader interfaces of UploadBinariesConfig,
+// so the binaries can be transferred into the archive with UploadBinaries.
+type ArchiveWriter struct {
+	zw *zip.Writer
+}
+
+// NewArchiveWriter returns an ArchiveWriter writing the archive to w.
+// The archive is complete once Close has been called.
+func NewArchiveWriter(w io.Writer) *ArchiveWriter {
+	return &ArchiveWriter{zw: zip.NewWriter(w)}
+}
+
+// WriteModel writes the archive metadata and the serialized model
+// description.
+func (w *ArchiveWriter) WriteModel(metadata ArchiveMetadata, model []byte) error {
+	metadata.FormatVersion = ArchiveFormatVersion
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.writeEntry(archiveMetadataName, zip.Deflate, strings.NewReader(string(data))); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(w.writeEntry(archiveModelName, zip.Deflate, strings.NewReader(string(model))))
+}
+
+// UploadCharm is part of the CharmUploader interface.
+func (w *ArchiveWriter) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	// Charms are already compressed.
+	if err := w.writeEntry(charmArchiveName(curl), zip.Store, content); err != nil {
+		return nil, errors.Annotatef(err, "writing charm %q", curl)
+	}
+	return curl, nil
+}
+
+// UploadTools is part of the ToolsUploader interface.
+func (w *ArchiveWriter) UploadTools(r io.ReadSeeker, v version.Binary, _ ...string) (tools.List, error) {
+	hash := sha256.New()
+	counter := &countingWriter{}
+	if err := w.writeEntry(toolsArchiveName(v), zip.Store, io.TeeReader(r, io.MultiWriter(hash, counter))); err != nil {
+		return nil, errors.Annotatef(err, "writing agent binaries %q", v)
+	}
+	return tools.List{{
+		Version: v,
+		SHA256:  fmt.Sprintf("%x", hash.Sum(nil)),
+		Size:    counter.n,
+	}}, nil
+}
+
+// UploadResource is part of the ResourceUploader interface.
+func (w *ArchiveWriter) UploadResource(res resource.Resource, content io.ReadSeeker) error {
+	err := w.writeEntry(resourceArchiveName(res.ApplicationID, res.Name), zip.Deflate, content)
+	return errors.Annotatef(err, "writing resource %q of application %q", res.Name, res.ApplicationID)
+}
+
+// SetPlaceholderResource is part of the ResourceUploader interface.
+// Placeholder resources are recorded in the model description.
+func (w *ArchiveWriter) SetPlaceholderResource(resource.Resource) error {
+	return nil
+}
+
+// SetUnitResource is part of the ResourceUploader interface. Unit
+// resources are recorded in the model description.
+func (w *ArchiveWriter) SetUnitResource(string, resource.Resource) error {
+	return nil
+}
+
+// Close completes the archive.
+func (w *ArchiveWriter) Close() error {
+	return errors.Trace(w.zw.Close())
+}
+
+func (w *ArchiveWriter) writeEntry(name string, method uint16, r io.Reader) error {
+	entry, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: method,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = io.Copy(entry, r)
+	return errors.Trace(err)
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// ArchiveReader reads a model archive written by ArchiveWriter.
+//
+// ArchiveReader implements the downloader interfaces of
+// UploadBinariesConfig, so the binaries can be transferred from the
+// archive to a controller with UploadBinaries.
+type ArchiveReader struct {
+	zr       *zip.ReadCloser
+	entries  map[string]*zip.File
+	metadata ArchiveMetadata
+	model    []byte
+}
+
+// OpenArchive opens the model archive at the given path.
+func OpenArchive(path string) (_ *ArchiveReader, err error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "opening model archive")
+	}
+	defer func() {
+		if err != nil {
+			_ = zr.Close()
+		}
+	}()
+	r := &ArchiveReader{
+		zr:      zr,
+		entries: make(map[string]*zip.File),
+	}
+	for _, f := range zr.File {
+		r.entries[f.Name] = f
+	}
+
+	data, err := r.readEntry(archiveMetadataName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := yaml.Unmarshal(data, &r.metadata); err != nil {
+		return nil, errors.Annotate(err, "reading model archive metadata")
+	}
+	if r.metadata.FormatVersion != ArchiveFormatVersion {
+		return nil, errors.NotSupportedf("model archive format version %d", r.metadata.FormatVersion)
+	}
+	if r.model, err = r.readEntry(archiveModelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return r, nil
+}
+
+// Metadata returns the archive metadata.
+func (r *ArchiveReader) Metadata() ArchiveMetadata {
+	return r.metadata
+}
+
+// SerializedModel returns the serialized model held in the archive, along
+// with the charms, agent binaries and resources it uses. The agent binary
+// URIs can be opened with OpenURI.
+func (r *ArchiveReader) SerializedModel() (coremigration.SerializedModel, error) {
+	model, err := description.Deserialize(r.model)
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	serialized, err := SerializeModel(model, toolsArchiveName)
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	// Keep the model exactly as it was exported.
+	serialized.Bytes = r.model
+	return serialized, nil
+}
+
+// ModelInfo returns the details of the archived model required to check
+// whether a controller can import it.
+func (r *ArchiveReader) ModelInfo() (coremigration.ModelInfo, error) {
+	model, err := description.Deserialize(r.model)
+	if err != nil {
+		return coremigration.ModelInfo{}, errors.Trace(err)
+	}
+	return ModelInfo(model, r.metadata.ControllerAgentVersion)
+}
+
+// OpenCharm is part of the CharmDownloader interface.
+func (r *ArchiveReader) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return r.openEntry(charmArchiveName(curl))
+}
+
+// OpenURI is part of the ToolsDownloader interface. The URI is one of the
+// agent binary URIs returned by SerializedModel.
+func (r *ArchiveReader) OpenURI(uri string, _ url.Values) (io.ReadCloser, error) {
+	return r.openEntry(uri)
+}
+
+// OpenResource is part of the ResourceDownloader interface.
+func (r *ArchiveReader) OpenResource(application, name string) (io.ReadCloser, error) {
+	return r.openEntry(resourceArchiveName(application, name))
+}
+
+// Close closes the archive.
+func (r *ArchiveReader) Close() error {
+	return errors.Trace(r.zr.Close())
+}
+
+func (r *ArchiveReader) openEntry(name string) (io.ReadCloser, error) {
+	f, ok := r.entries[name]
+	if !ok {
+		return nil, errors.NotFoundf("%q in model archive", name)
+	}
+	rc, err := f.Open()
+	return rc, errors.Trace(err)
+}
+
+func (r *ArchiveReader) readEntry(name string) ([]byte, error) {
+	rc, err := r.openEntry(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := ioutil.ReadAll(rc)
+	return data, errors.Trace(err)
+}
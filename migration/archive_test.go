@@ -0,0 +1,138 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/resource/resourcetesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ArchiveSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ArchiveSuite{})
+
+func (s *ArchiveSuite) newModel() description.Model {
+	model := description.NewModel(description.ModelArgs{
+		Type:  "iaas",
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"uuid":          coretesting.ModelTag.Id(),
+			"name":          "foo",
+			"agent-version": "2.9.0",
+		},
+	})
+	model.SetStatus(description.StatusArgs{Value: "available"})
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("app"),
+		CharmURL: "cs:trusty/app-1",
+	})
+	app.SetStatus(description.StatusArgs{Value: "active"})
+	machine := model.AddMachine(description.MachineArgs{Id: names.NewMachineTag("0")})
+	machine.SetStatus(description.StatusArgs{Value: "started"})
+	machine.SetTools(description.AgentToolsArgs{
+		Version: version.MustParseBinary("2.9.0-focal-amd64"),
+	})
+	return model
+}
+
+func (s *ArchiveSuite) TestRoundTrip(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "model.zip")
+	f, err := os.Create(path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	model := s.newModel()
+	serialized, err := migration.SerializeModel(model, func(v version.Binary) string {
+		return "/tools/" + v.String()
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serialized.Charms, jc.DeepEquals, []string{"cs:trusty/app-1"})
+	c.Assert(serialized.Tools, jc.DeepEquals, map[version.Binary]string{
+		version.MustParseBinary("2.9.0-focal-amd64"): "/tools/2.9.0-focal-amd64",
+	})
+
+	w := migration.NewArchiveWriter(f)
+	err = w.WriteModel(migration.ArchiveMetadata{
+		ControllerAgentVersion: version.MustParse("2.9.1"),
+		SourceControllerUUID:   coretesting.ControllerTag.Id(),
+		MigrationId:            coretesting.ModelTag.Id() + ":0",
+	}, serialized.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.UploadCharm(charm.MustParseURL("cs:trusty/app-1"), strings.NewReader("charm content"))
+	c.Assert(err, jc.ErrorIsNil)
+	tools, err := w.UploadTools(strings.NewReader("tools content"), version.MustParseBinary("2.9.0-focal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tools, gc.HasLen, 1)
+	c.Assert(tools[0].Size, gc.Equals, int64(len("tools content")))
+	res := resourcetesting.NewResource(c, nil, "blob", "app", "resource content").Resource
+	err = w.UploadResource(res, strings.NewReader("resource content"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	c.Assert(f.Close(), jc.ErrorIsNil)
+
+	r, err := migration.OpenArchive(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	c.Assert(r.Metadata(), jc.DeepEquals, migration.ArchiveMetadata{
+		FormatVersion:          migration.ArchiveFormatVersion,
+		ControllerAgentVersion: version.MustParse("2.9.1"),
+		SourceControllerUUID:   coretesting.ControllerTag.Id(),
+		MigrationId:            coretesting.ModelTag.Id() + ":0",
+	})
+
+	info, err := r.ModelInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.UUID, gc.Equals, coretesting.ModelTag.Id())
+	c.Assert(info.Name, gc.Equals, "foo")
+	c.Assert(info.AgentVersion, gc.Equals, version.MustParse("2.9.0"))
+	c.Assert(info.ControllerAgentVersion, gc.Equals, version.MustParse("2.9.1"))
+
+	fromArchive, err := r.SerializedModel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fromArchive.Bytes, jc.DeepEquals, serialized.Bytes)
+	c.Assert(fromArchive.Charms, jc.DeepEquals, serialized.Charms)
+
+	c.Assert(readAll(c)(r.OpenCharm(charm.MustParseURL("cs:trusty/app-1"))), gc.Equals, "charm content")
+	for _, uri := range fromArchive.Tools {
+		c.Assert(readAll(c)(r.OpenURI(uri, nil)), gc.Equals, "tools content")
+	}
+	c.Assert(readAll(c)(r.OpenResource("app", "blob")), gc.Equals, "resource content")
+
+	_, err = r.OpenResource("app", "missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func readAll(c *gc.C) func(io.ReadCloser, error) string {
+	return func(rc io.ReadCloser, err error) string {
+		c.Assert(err, jc.ErrorIsNil)
+		defer func() { _ = rc.Close() }()
+		data, err := ioutil.ReadAll(rc)
+		c.Assert(err, jc.ErrorIsNil)
+		return string(data)
+	}
+}
+
+func (s *ArchiveSuite) TestOpenArchiveInvalid(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "model.zip")
+	err := ioutil.WriteFile(path, []byte("not an archive"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = migration.OpenArchive(path)
+	c.Assert(err, gc.ErrorMatches, "opening model archive: .*")
+}
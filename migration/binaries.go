@@ -0,0 +1,175 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	charmresource "github.com/juju/charm/v7/resource"
+	"github.com/juju/collections/set"
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	coremigration "github.com/juju/juju/core/migration"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/resource"
+)
+
+// SerializeModel serializes the model description, listing the charms,
+// agent binaries and resources used by the model which need to be
+// transferred along with it. The URI of each agent binary is built with
+// toolsURI.
+func SerializeModel(model description.Model, toolsURI func(version.Binary) string) (coremigration.SerializedModel, error) {
+	bytes, err := description.Serialize(model)
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	resources, err := modelResources(model)
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	serialized := coremigration.SerializedModel{
+		Bytes:     bytes,
+		Charms:    modelCharms(model),
+		Tools:     make(map[version.Binary]string),
+		Resources: resources,
+	}
+	// CAAS models don't use agent binaries from the controller.
+	if model.Type() == string(coremodel.IAAS) {
+		for _, v := range modelTools(model) {
+			serialized.Tools[v] = toolsURI(v)
+		}
+	}
+	return serialized, nil
+}
+
+// ModelInfo returns the details of the model required to check whether
+// a controller can import it.
+func ModelInfo(model description.Model, controllerVersion version.Number) (coremigration.ModelInfo, error) {
+	cfg := model.Config()
+	name, _ := cfg["name"].(string)
+	agentVersion, _ := cfg["agent-version"].(string)
+	v, err := version.Parse(agentVersion)
+	if err != nil {
+		return coremigration.ModelInfo{}, errors.Annotate(err, "model agent version")
+	}
+	info := coremigration.ModelInfo{
+		UUID:                   model.Tag().Id(),
+		Owner:                  model.Owner(),
+		Name:                   name,
+		AgentVersion:           v,
+		ControllerAgentVersion: controllerVersion,
+	}
+	if err := info.Validate(); err != nil {
+		return coremigration.ModelInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+func modelCharms(model description.Model) []string {
+	result := set.NewStrings()
+	for _, application := range model.Applications() {
+		result.Add(application.CharmURL())
+	}
+	return result.SortedValues()
+}
+
+func modelTools(model description.Model) []version.Binary {
+	used := make(map[version.Binary]bool)
+	var addMachine func(description.Machine)
+	addMachine = func(machine description.Machine) {
+		if tools := machine.Tools(); tools != nil {
+			used[tools.Version()] = true
+		}
+		for _, container := range machine.Containers() {
+			addMachine(container)
+		}
+	}
+	for _, machine := range model.Machines() {
+		addMachine(machine)
+	}
+	for _, application := range model.Applications() {
+		for _, unit := range application.Units() {
+			if tools := unit.Tools(); tools != nil {
+				used[tools.Version()] = true
+			}
+		}
+	}
+	result := make([]version.Binary, 0, len(used))
+	for v := range used {
+		result = append(result, v)
+	}
+	return result
+}
+
+func modelResources(model description.Model) ([]coremigration.SerializedModelResource, error) {
+	var result []coremigration.SerializedModelResource
+	for _, app := range model.Applications() {
+		for _, res := range app.Resources() {
+			appRev, err := resourceRevision(app.Name(), res.Name(), res.ApplicationRevision())
+			if err != nil {
+				return nil, errors.Annotatef(err, "resource %q of application %q", res.Name(), app.Name())
+			}
+			csRev, err := resourceRevision(app.Name(), res.Name(), res.CharmStoreRevision())
+			if err != nil {
+				return nil, errors.Annotatef(err, "resource %q of application %q", res.Name(), app.Name())
+			}
+			unitRevs := make(map[string]resource.Resource)
+			for _, unit := range app.Units() {
+				for _, unitRes := range unit.Resources() {
+					if unitRes.Name() != res.Name() {
+						continue
+					}
+					unitRev, err := resourceRevision(app.Name(), res.Name(), unitRes.Revision())
+					if err != nil {
+						return nil, errors.Annotatef(err, "resource %q of unit %q", res.Name(), unit.Name())
+					}
+					unitRevs[unit.Name()] = unitRev
+				}
+			}
+			result = append(result, coremigration.SerializedModelResource{
+				ApplicationRevision: appRev,
+				CharmStoreRevision:  csRev,
+				UnitRevisions:       unitRevs,
+			})
+		}
+	}
+	return result, nil
+}
+
+func resourceRevision(app, name string, rev description.ResourceRevision) (resource.Resource, error) {
+	if rev == nil {
+		return resource.Resource{}, nil
+	}
+	resType, err := charmresource.ParseType(rev.Type())
+	if err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	origin, err := charmresource.ParseOrigin(rev.Origin())
+	if err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	var fp charmresource.Fingerprint
+	if rev.FingerprintHex() != "" {
+		if fp, err = charmresource.ParseFingerprint(rev.FingerprintHex()); err != nil {
+			return resource.Resource{}, errors.Annotate(err, "invalid fingerprint")
+		}
+	}
+	return resource.Resource{
+		Resource: charmresource.Resource{
+			Meta: charmresource.Meta{
+				Name:        name,
+				Type:        resType,
+				Path:        rev.Path(),
+				Description: rev.Description(),
+			},
+			Origin:      origin,
+			Revision:    rev.Revision(),
+			Size:        rev.Size(),
+			Fingerprint: fp,
+		},
+		ApplicationID: app,
+		Username:      rev.Username(),
+		Timestamp:     rev.Timestamp(),
+	}, nil
+}
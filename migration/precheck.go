@@ -35,6 +35,9 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackend, error)
 	CloudCredential(tag names.CloudCredentialTag) (state.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	ListResources(string) (resource.ApplicationResources, error)
+	Charm(*charm.URL) (PrecheckCharm, error)
+	ExportBlockers() ([]state.ExportBlocker, error)
 }
 
 // Pool defines the interface to a StatePool used by the migration
@@ -88,6 +91,13 @@ type PrecheckUnit interface {
 	ShouldBeAssigned() bool
 }
 
+// PrecheckCharm describes the state interface for a charm needed by
+// migration prechecks.
+type PrecheckCharm interface {
+	IsUploaded() bool
+	IsPlaceholder() bool
+}
+
 // PrecheckRelation describes the state interface for relations needed
 // for prechecks.
 type PrecheckRelation interface {
@@ -118,45 +128,99 @@ func SourcePrecheck(
 	modelPresence ModelPresence,
 	controllerPresence ModelPresence,
 ) error {
-	ctx := precheckContext{backend, modelPresence}
+	_, err := sourcePrecheck(backend, modelPresence, controllerPresence, nil)
+	return errors.Trace(err)
+}
+
+// SourcePrecheckReport makes the same checks as SourcePrecheck, but
+// carries on after a check fails, returning all of the failed checks.
+// An error is only returned if the checks could not be made.
+func SourcePrecheckReport(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+) ([]coremigration.PrecheckFailure, error) {
+	return sourcePrecheck(backend, modelPresence, controllerPresence, []coremigration.PrecheckFailure{})
+}
+
+func sourcePrecheck(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+	failures []coremigration.PrecheckFailure,
+) ([]coremigration.PrecheckFailure, error) {
+	ctx := &precheckContext{backend: backend, presence: modelPresence, failures: failures}
 	if err := ctx.checkModel(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
+	}
+
+	if err := ctx.checkExportBlockers(); err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	if err := ctx.checkMachines(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	appUnits, err := ctx.checkApplications()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	if err := ctx.checkRelations(appUnits); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
-		return errors.Annotate(err, "checking cleanups")
+		return nil, errors.Annotate(err, "checking cleanups")
 	} else if cleanupNeeded {
-		return errors.New("cleanup needed")
+		if err := ctx.fail("model", errors.New("cleanup needed")); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Check the source controller.
 	controllerBackend, err := backend.ControllerBackend()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
+	}
+	controllerCtx := &precheckContext{
+		backend:      controllerBackend,
+		presence:     controllerPresence,
+		entityPrefix: "controller ",
+		failures:     ctx.failures,
 	}
-	controllerCtx := precheckContext{controllerBackend, controllerPresence}
 	if err := controllerCtx.checkController(); err != nil {
-		return errors.Annotate(err, "controller")
+		return nil, errors.Annotate(err, "controller")
 	}
-	return nil
+	return controllerCtx.failures, nil
 }
 
 type precheckContext struct {
 	backend  PrecheckBackend
 	presence ModelPresence
+
+	// entityPrefix is prepended to the entities of failed checks, to
+	// tell the controller's entities apart from the model's.
+	entityPrefix string
+
+	// failures holds the failed checks when all of them are being
+	// collected. When it is nil the checks stop at the first failure.
+	failures []coremigration.PrecheckFailure
+}
+
+// fail records that the entity failed a check. When all the failures
+// are being collected it returns nil so the checks carry on, otherwise
+// it returns the failure as an error.
+func (ctx *precheckContext) fail(entity string, err error) error {
+	if ctx.failures == nil {
+		return err
+	}
+	ctx.failures = append(ctx.failures, coremigration.PrecheckFailure{
+		Entity:  ctx.entityPrefix + entity,
+		Message: err.Error(),
+	})
+	return nil
 }
 
 func (ctx *precheckContext) checkModel() error {
@@ -165,10 +229,10 @@ func (ctx *precheckContext) checkModel() error {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		return ctx.fail("model", errors.Errorf("model is %s", model.Life()))
 	}
 	if model.MigrationMode() == state.MigrationModeImporting {
-		return errors.New("model is being imported as part of another migration")
+		return ctx.fail("model", errors.New("model is being imported as part of another migration"))
 	}
 	if credTag, found := model.CloudCredentialTag(); found {
 		creds, err := ctx.backend.CloudCredential(credTag)
@@ -176,7 +240,23 @@ func (ctx *precheckContext) checkModel() error {
 			return errors.Trace(err)
 		}
 		if creds.Revoked {
-			return errors.New("model has revoked credentials")
+			return ctx.fail("model", errors.New("model has revoked credentials"))
+		}
+	}
+	return nil
+}
+
+// checkExportBlockers checks that the model doesn't use any features
+// that can't be exported.
+func (ctx *precheckContext) checkExportBlockers() error {
+	blockers, err := ctx.backend.ExportBlockers()
+	if err != nil {
+		return errors.Annotate(err, "retrieving export blockers")
+	}
+	for _, blocker := range blockers {
+		err := ctx.fail(blocker.Entity, errors.Errorf("%s of %s can't be migrated", blocker.Feature, blocker.Entity))
+		if err != nil {
+			return errors.Trace(err)
 		}
 	}
 	return nil
@@ -186,9 +266,27 @@ func (ctx *precheckContext) checkModel() error {
 // sure that the preconditions for model migration are met. The
 // backend provided must be for the target controller.
 func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) error {
+	_, err := targetPrecheck(backend, pool, modelInfo, presence, nil)
+	return errors.Trace(err)
+}
+
+// TargetPrecheckReport makes the same checks as TargetPrecheck, but
+// carries on after a check fails, returning all of the failed checks.
+// An error is only returned if the checks could not be made.
+func TargetPrecheckReport(
+	backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence,
+) ([]coremigration.PrecheckFailure, error) {
+	return targetPrecheck(backend, pool, modelInfo, presence, []coremigration.PrecheckFailure{})
+}
+
+func targetPrecheck(
+	backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence,
+	failures []coremigration.PrecheckFailure,
+) ([]coremigration.PrecheckFailure, error) {
 	if err := modelInfo.Validate(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
+	ctx := &precheckContext{backend: backend, presence: presence, failures: failures}
 
 	// This check is necessary because there is a window between the
 	// REAP phase and then end of the DONE phase where a model's
@@ -198,40 +296,54 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	//
 	// See also https://lpad.tv/1611391
 	if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
-		return errors.Annotate(err, "checking for active migration")
+		return nil, errors.Annotate(err, "checking for active migration")
 	} else if migrating {
-		return errors.New("model is being migrated out of target controller")
+		if err := ctx.fail("model", errors.New("model is being migrated out of target controller")); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	controllerVersion, err := backend.AgentVersion()
 	if err != nil {
-		return errors.Annotate(err, "retrieving model version")
+		return nil, errors.Annotate(err, "retrieving model version")
 	}
 
 	if controllerVersion.Compare(modelInfo.AgentVersion) < 0 {
-		return errors.Errorf("model has higher version than target controller (%s > %s)",
-			modelInfo.AgentVersion, controllerVersion)
+		err := ctx.fail("model", errors.Errorf("model has higher version than target controller (%s > %s)",
+			modelInfo.AgentVersion, controllerVersion))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	if !controllerVersionCompatible(modelInfo.ControllerAgentVersion, controllerVersion) {
-		return errors.Errorf("source controller has higher version than target controller (%s > %s)",
-			modelInfo.ControllerAgentVersion, controllerVersion)
+		err := ctx.fail("model", errors.Errorf("source controller has higher version than target controller (%s > %s)",
+			modelInfo.ControllerAgentVersion, controllerVersion))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
-	controllerCtx := precheckContext{backend, presence}
+	controllerCtx := &precheckContext{
+		backend:      backend,
+		presence:     presence,
+		entityPrefix: "controller ",
+		failures:     ctx.failures,
+	}
 	if err := controllerCtx.checkController(); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
+	ctx.failures = controllerCtx.failures
 
 	// Check for conflicts with existing models
 	modelUUIDs, err := backend.AllModelUUIDs()
 	if err != nil {
-		return errors.Annotate(err, "retrieving models")
+		return nil, errors.Annotate(err, "retrieving models")
 	}
 	for _, modelUUID := range modelUUIDs {
 		model, release, err := pool.GetModel(modelUUID)
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		defer release()
 
@@ -239,14 +351,19 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 		// from a previous migration attempt. It will be removed
 		// before the next import.
 		if model.UUID() == modelInfo.UUID && model.MigrationMode() != state.MigrationModeImporting {
-			return errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)
+			err := ctx.fail("model", errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 		if model.Name() == modelInfo.Name && model.Owner() == modelInfo.Owner {
-			return errors.Errorf("model named %q already exists", model.Name())
+			if err := ctx.fail("model", errors.Errorf("model named %q already exists", model.Name())); err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 	}
 
-	return nil
+	return ctx.failures, nil
 }
 
 func controllerVersionCompatible(sourceVersion, targetVersion version.Number) bool {
@@ -272,13 +389,17 @@ func (ctx *precheckContext) checkController() error {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		if err := ctx.fail("model", errors.Errorf("model is %s", model.Life())); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	if upgrading, err := ctx.backend.IsUpgrading(); err != nil {
 		return errors.Annotate(err, "checking for upgrades")
 	} else if upgrading {
-		return errors.New("upgrade in progress")
+		if err := ctx.fail("model", errors.New("upgrade in progress")); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	return errors.Trace(ctx.checkMachines())
@@ -294,36 +415,42 @@ func (ctx *precheckContext) checkMachines() error {
 	if err != nil {
 		return errors.Annotate(err, "retrieving machines")
 	}
-	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
 	for _, machine := range machines {
-		if machine.Life() != state.Alive {
-			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+		if err := ctx.checkMachine(machine, modelVersion); err != nil {
+			if err := ctx.fail("machine "+machine.Id(), err); err != nil {
+				return errors.Trace(err)
+			}
 		}
+	}
+	return nil
+}
 
-		if statusInfo, err := machine.InstanceStatus(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
-		} else if statusInfo.Status != status.Running {
-			return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
-		}
+func (ctx *precheckContext) checkMachine(machine PrecheckMachine, modelVersion version.Number) error {
+	if machine.Life() != state.Alive {
+		return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+	}
 
-		if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
-		} else if statusInfo.Status != status.Started {
-			return newStatusError("machine %s agent not functioning at this time",
-				machine.Id(), statusInfo.Status)
-		}
+	if statusInfo, err := machine.InstanceStatus(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
+	} else if statusInfo.Status != status.Running {
+		return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
+	}
 
-		if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
-		} else if rebootAction != state.ShouldDoNothing {
-			return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
-		}
+	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
+	if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
+	} else if statusInfo.Status != status.Started {
+		return newStatusError("machine %s agent not functioning at this time",
+			machine.Id(), statusInfo.Status)
+	}
 
-		if err := checkAgentTools(modelVersion, machine, "machine "+machine.Id()); err != nil {
-			return errors.Trace(err)
-		}
+	if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
+	} else if rebootAction != state.ShouldDoNothing {
+		return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
 	}
-	return nil
+
+	return errors.Trace(checkAgentTools(modelVersion, machine, "machine "+machine.Id()))
 }
 
 func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, error) {
@@ -342,8 +469,17 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 	}
 	appUnits := make(map[string][]PrecheckUnit, len(apps))
 	for _, app := range apps {
+		entity := "application " + app.Name()
 		if app.Life() != state.Alive {
-			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
+			if err := ctx.fail(entity, errors.Errorf("application %s is %s", app.Name(), app.Life())); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		if err := ctx.checkBinaries(app); err != nil {
+			if err := ctx.fail(entity, err); err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
 		units, err := app.AllUnits()
 		if err != nil {
@@ -358,33 +494,69 @@ func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, erro
 	return appUnits, nil
 }
 
+// checkBinaries checks that the charm and resources used by the
+// application are available to be transferred to the target controller.
+func (ctx *precheckContext) checkBinaries(app PrecheckApplication) error {
+	curl, _ := app.CharmURL()
+	ch, err := ctx.backend.Charm(curl)
+	if errors.IsNotFound(err) {
+		return errors.Errorf("charm %s of application %s not found", curl, app.Name())
+	} else if err != nil {
+		return errors.Annotatef(err, "retrieving charm %s", curl)
+	}
+	if ch.IsPlaceholder() || !ch.IsUploaded() {
+		return errors.Errorf("charm %s of application %s has not been uploaded", curl, app.Name())
+	}
+
+	resources, err := ctx.backend.ListResources(app.Name())
+	if err != nil {
+		return errors.Annotatef(err, "retrieving resources for %s", app.Name())
+	}
+	if len(resources.Resources) != len(resources.CharmStoreResources) {
+		return errors.Errorf("resources of application %s don't match charm store resources", app.Name())
+	}
+	return nil
+}
+
 func (ctx *precheckContext) checkUnits(app PrecheckApplication, units []PrecheckUnit, modelVersion version.Number, modelType state.ModelType) error {
 	if len(units) < app.MinUnits() {
-		return errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		err := errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		if err := ctx.fail("application "+app.Name(), err); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	appCharmURL, _ := app.CharmURL()
 
 	for _, unit := range units {
-		if unit.Life() != state.Alive {
-			return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
-		}
-
-		if err := ctx.checkUnitAgentStatus(unit); err != nil {
-			return errors.Trace(err)
-		}
-
-		if modelType == state.ModelTypeIAAS {
-			if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
+		if err := ctx.checkUnit(unit, appCharmURL, modelVersion, modelType); err != nil {
+			if err := ctx.fail("unit "+unit.Name(), err); err != nil {
 				return errors.Trace(err)
 			}
 		}
+	}
+	return nil
+}
+
+func (ctx *precheckContext) checkUnit(unit PrecheckUnit, appCharmURL *charm.URL, modelVersion version.Number, modelType state.ModelType) error {
+	if unit.Life() != state.Alive {
+		return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
+	}
+
+	if err := ctx.checkUnitAgentStatus(unit); err != nil {
+		return errors.Trace(err)
+	}
 
-		unitCharmURL, _ := unit.CharmURL()
-		if appCharmURL.String() != unitCharmURL.String() {
-			return errors.Errorf("unit %s is upgrading", unit.Name())
+	if modelType == state.ModelTypeIAAS {
+		if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
+			return errors.Trace(err)
 		}
 	}
+
+	unitCharmURL, _ := unit.CharmURL()
+	if appCharmURL.String() != unitCharmURL.String() {
+		return errors.Errorf("unit %s is upgrading", unit.Name())
+	}
 	return nil
 }
 
@@ -463,7 +635,10 @@ func (ctx *precheckContext) checkRelations(appUnits map[string][]PrecheckUnit) e
 					return errors.Trace(err)
 				}
 				if !inScope {
-					return errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
+					err := errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
+					if err := ctx.fail("unit "+unit.Name(), err); err != nil {
+						return errors.Trace(err)
+					}
 				}
 			}
 		}
@@ -4,6 +4,7 @@
 package migration
 
 import (
+	"github.com/juju/charm/v7"
 	"github.com/juju/errors"
 	"github.com/juju/version"
 
@@ -105,6 +106,21 @@ func (s *precheckShim) ListPendingResources(app string) ([]resource.Resource, er
 	return resources, errors.Trace(err)
 }
 
+// ListResources implements PrecheckBackend.
+func (s *precheckShim) ListResources(app string) (resource.ApplicationResources, error) {
+	resources, err := s.resourcesSt.ListResources(app)
+	return resources, errors.Trace(err)
+}
+
+// Charm implements PrecheckBackend.
+func (s *precheckShim) Charm(curl *charm.URL) (PrecheckCharm, error) {
+	ch, err := s.State.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ch, nil
+}
+
 // ControllerBackend implements PrecheckBackend.
 func (s *precheckShim) ControllerBackend() (PrecheckBackend, error) {
 	return PrecheckShim(s.controllerState, s.controllerState)
@@ -107,6 +107,23 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestExportBlockersError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.exportBlockersErr = errors.New("boom")
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "retrieving export blockers: boom")
+}
+
+func (*SourcePrecheckSuite) TestExportBlockers(c *gc.C) {
+	backend := newFakeBackend()
+	backend.exportBlockers = []state.ExportBlocker{{
+		Entity:  "application foo",
+		Feature: "per-endpoint expose settings",
+	}}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "per-endpoint expose settings of application foo can't be migrated")
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -396,6 +413,79 @@ func (s *SourcePrecheckSuite) TestCrossModelUnitsNotYetInScope(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *SourcePrecheckSuite) TestCharmNotUploaded(c *gc.C) {
+	backend := newHappyBackend()
+	backend.charmNotUploaded = true
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "charm cs:foo-1 of application foo has not been uploaded")
+}
+
+func (s *SourcePrecheckSuite) TestResourcesMismatch(c *gc.C) {
+	backend := newHappyBackend()
+	backend.resources = resource.ApplicationResources{
+		Resources: []resource.Resource{
+			resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource,
+		},
+	}
+	err := sourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "resources of application foo don't match charm store resources")
+}
+
+func (s *SourcePrecheckSuite) TestReportSuccess(c *gc.C) {
+	backend := newHappyBackend()
+	backend.controllerBackend = newHappyBackend()
+	failures, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, gc.HasLen, 0)
+}
+
+func (s *SourcePrecheckSuite) TestReportCollectsAllFailures(c *gc.C) {
+	backend := newHappyBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", life: state.Dying},
+		&fakeMachine{id: "1", rebootAction: state.ShouldReboot},
+	}
+	backend.apps = []migration.PrecheckApplication{
+		&fakeApp{
+			name: "foo",
+			units: []migration.PrecheckUnit{
+				&fakeUnit{name: "foo/0", agentStatus: status.Failed},
+				&fakeUnit{name: "foo/1", life: state.Dead},
+			},
+		},
+	}
+	backend.cleanupNeeded = true
+	backend.exportBlockers = []state.ExportBlocker{{
+		Entity:  "application foo",
+		Feature: "per-endpoint expose settings",
+	}}
+	backend.controllerBackend = newHappyBackend()
+	backend.controllerBackend.isUpgrading = true
+	backend.controllerBackend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", instanceStatus: status.Provisioning},
+	}
+
+	failures, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, jc.DeepEquals, []coremigration.PrecheckFailure{
+		{Entity: "application foo", Message: "per-endpoint expose settings of application foo can't be migrated"},
+		{Entity: "machine 0", Message: "machine 0 is dying"},
+		{Entity: "machine 1", Message: "machine 1 is scheduled to reboot"},
+		{Entity: "unit foo/0", Message: "unit foo/0 not idle or executing (failed)"},
+		{Entity: "unit foo/1", Message: "unit foo/1 is dead"},
+		{Entity: "model", Message: "cleanup needed"},
+		{Entity: "controller model", Message: "upgrade in progress"},
+		{Entity: "controller machine 0", Message: "machine 0 not running (allocating)"},
+	})
+}
+
+func (s *SourcePrecheckSuite) TestReportError(c *gc.C) {
+	backend := newHappyBackend()
+	backend.allMachinesErr = errors.New("boom")
+	_, err := migration.SourcePrecheckReport(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(err, gc.ErrorMatches, "retrieving machines: boom")
+}
+
 type TargetPrecheckSuite struct {
 	precheckBaseSuite
 	modelInfo coremigration.ModelInfo
@@ -623,6 +713,34 @@ func (s *TargetPrecheckSuite) TestUUIDAlreadyExistsButImporting(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *TargetPrecheckSuite) TestReportCollectsAllFailures(c *gc.C) {
+	pool := &fakePool{
+		models: []migration.PrecheckModel{
+			&fakeModel{uuid: modelUUID, modelType: state.ModelTypeIAAS},
+			&fakeModel{
+				uuid:      "uuid",
+				name:      modelName,
+				modelType: state.ModelTypeIAAS,
+				owner:     modelOwner,
+			},
+		},
+	}
+	backend := newBackendWithDyingMachine()
+	backend.migrationActive = true
+	backend.models = pool.uuids()
+	s.modelInfo.AgentVersion = version.MustParse("1.2.4")
+
+	failures, err := migration.TargetPrecheckReport(backend, pool, s.modelInfo, allAlivePresence())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failures, jc.DeepEquals, []coremigration.PrecheckFailure{
+		{Entity: "model", Message: "model is being migrated out of target controller"},
+		{Entity: "model", Message: "model has higher version than target controller (1.2.4 > 1.2.3)"},
+		{Entity: "controller machine 0", Message: "machine 0 is dying"},
+		{Entity: "model", Message: "model with same UUID already exists (model-uuid)"},
+		{Entity: "model", Message: `model named "model-name" already exists`},
+	})
+}
+
 type precheckRunner func(migration.PrecheckBackend) error
 
 type precheckBaseSuite struct {
@@ -728,6 +846,9 @@ type fakeBackend struct {
 	cleanupNeeded bool
 	cleanupErr    error
 
+	exportBlockers    []state.ExportBlocker
+	exportBlockersErr error
+
 	isUpgrading    bool
 	isUpgradingErr error
 
@@ -749,6 +870,10 @@ type fakeBackend struct {
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	resources resource.ApplicationResources
+
+	charmNotUploaded bool
+
 	controllerBackend *fakeBackend
 }
 
@@ -764,6 +889,10 @@ func (b *fakeBackend) NeedsCleanup() (bool, error) {
 	return b.cleanupNeeded, b.cleanupErr
 }
 
+func (b *fakeBackend) ExportBlockers() ([]state.ExportBlocker, error) {
+	return b.exportBlockers, b.exportBlockersErr
+}
+
 func (b *fakeBackend) AgentVersion() (version.Number, error) {
 	return backendVersion, b.agentVersionErr
 }
@@ -796,6 +925,14 @@ func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, err
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) ListResources(app string) (resource.ApplicationResources, error) {
+	return b.resources, nil
+}
+
+func (b *fakeBackend) Charm(*charm.URL) (migration.PrecheckCharm, error) {
+	return &fakeCharm{uploaded: !b.charmNotUploaded}, nil
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackend, error) {
 	if b.controllerBackend == nil {
 		return b, nil
@@ -919,6 +1056,18 @@ func (m *fakeMachine) ShouldRebootOrShutdown() (state.RebootAction, error) {
 	return m.rebootAction, nil
 }
 
+type fakeCharm struct {
+	uploaded bool
+}
+
+func (c *fakeCharm) IsUploaded() bool {
+	return c.uploaded
+}
+
+func (c *fakeCharm) IsPlaceholder() bool {
+	return false
+}
+
 type fakeApp struct {
 	name     string
 	life     state.Life
@@ -402,6 +402,10 @@ func (env *azureEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Spot,
+		constraints.MaxPrice,
+		constraints.ImageID,
+		constraints.ImageName,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -13,6 +13,10 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.ImageID,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator returns a Validator instance which
@@ -170,6 +170,7 @@ var unsupportedConstraints = []string{
 	// TODO(anastasiamac 2016-03-16) LP#1557874
 	// use virt-type in StartInstances
 	constraints.VirtType,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -339,6 +340,9 @@ func (e *environ) PrecheckInstance(ctx context.ProviderCallContext, args environ
 	); err != nil {
 		return errors.Trace(err)
 	}
+	if args.Constraints.HasMaxPrice() && !args.Constraints.HasSpot() {
+		return errors.NotValidf("max-price constraint without spot=true")
+	}
 	if !args.Constraints.HasInstanceType() {
 		return nil
 	}
@@ -475,9 +479,19 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		return nil, wrapError(err)
 	}
 
+	// An image chosen with the image-id constraint is used in place of
+	// those found in the region's image metadata.
+	imageMetadata := args.ImageMetadata
+	if args.Constraints.HasImageID() {
+		imageMetadata, err = customImageMetadata(e.ec2, e.cloud.Region, *args.Constraints.ImageID)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+	}
+
 	spec, err := findInstanceSpec(
 		args.InstanceConfig.Controller != nil,
-		args.ImageMetadata,
+		imageMetadata,
 		instanceTypes,
 		&instances.InstanceConstraint{
 			Region:      e.cloud.Region,
@@ -574,20 +588,39 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
-	if err != nil {
-		if !isZoneOrSubnetConstrainedError(err) {
-			err = annotateWrapError(err, "cannot run instances")
+	if args.Constraints.HasSpot() {
+		var maxPrice string
+		if args.Constraints.HasMaxPrice() {
+			maxPrice = *args.Constraints.MaxPrice
+		}
+		spotInst, err := runSpotInstance(ec2Session, ctx, runArgs, maxPrice)
+		if err != nil {
+			if isSpotZoneConstrainedError(err) {
+				return nil, errors.Annotate(err, "cannot run spot instance")
+			}
+			return nil, annotateWrapError(err, "cannot run spot instance")
+		}
+		inst = &ec2Instance{
+			e:        e,
+			Instance: spotInst,
+		}
+		args.InstanceConfig.Tags[tagSpotInstance] = "true"
+	} else {
+		instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+		if err != nil {
+			if !isZoneOrSubnetConstrainedError(err) {
+				err = annotateWrapError(err, "cannot run instances")
+			}
+			return nil, err
+		}
+		if len(instResp.Instances) != 1 {
+			return nil, errors.Errorf("expected 1 started instance, got %d", len(instResp.Instances))
 		}
-		return nil, err
-	}
-	if len(instResp.Instances) != 1 {
-		return nil, errors.Errorf("expected 1 started instance, got %d", len(instResp.Instances))
-	}
 
-	inst = &ec2Instance{
-		e:        e,
-		Instance: &instResp.Instances[0],
+		inst = &ec2Instance{
+			e:        e,
+			Instance: &instResp.Instances[0],
+		}
 	}
 	instAZ := inst.Instance.AvailZone
 	if hasVPCID {
@@ -630,6 +663,9 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		// Tags currently not supported by EC2
 		AvailabilityZone: &inst.Instance.AvailZone,
 	}
+	if args.Constraints.HasImageID() {
+		hc.Image = &spec.Image.Id
+	}
 	return &environs.StartInstanceResult{
 		Instance: inst,
 		Hardware: &hc,
@@ -1017,6 +1053,16 @@ func (e *environ) Instances(ctx context.ProviderCallContext, ids []instance.Id)
 			break
 		}
 	}
+	if err == nil || err == environs.ErrPartialInstances {
+		e.addSpotInterruptions(ctx, ids, insts)
+		err = nil
+		for _, inst := range insts {
+			if inst == nil {
+				err = environs.ErrPartialInstances
+				break
+			}
+		}
+	}
 	if err == environs.ErrPartialInstances {
 		for _, inst := range insts {
 			if inst != nil {
@@ -51,6 +51,7 @@ func AllModelGroups(e environs.Environ, ctx context.ProviderCallContext) ([]stri
 
 var (
 	EC2AvailabilityZones           = &ec2AvailabilityZones
+	EC2Images                      = &ec2Images
 	RunInstances                   = &runInstances
 	BlockDeviceNamer               = blockDeviceNamer
 	GetBlockDeviceMappings         = getBlockDeviceMappings
@@ -4,6 +4,10 @@
 package ec2
 
 import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+	"gopkg.in/amz.v3/ec2"
+
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
@@ -35,6 +39,40 @@ func filterImages(images []*imagemetadata.ImageMetadata, ic *instances.InstanceC
 	return imagesByStorage[""]
 }
 
+var ec2Images = (*ec2.EC2).Images
+
+// ec2ImageArches maps the architectures EC2 reports for images to
+// those known to Juju.
+var ec2ImageArches = map[string]string{
+	"x86_64": arch.AMD64,
+	"i386":   arch.I386,
+	"arm64":  arch.ARM64,
+}
+
+// customImageMetadata returns image metadata for the image with the
+// given id, as described by EC2, so that an instance type can be
+// chosen for it without consulting simplestreams.
+func customImageMetadata(e *ec2.EC2, region, id string) ([]*imagemetadata.ImageMetadata, error) {
+	resp, err := ec2Images(e, []string{id}, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot describe image %q", id)
+	}
+	if len(resp.Images) != 1 {
+		return nil, errors.NotFoundf("image %q", id)
+	}
+	image := resp.Images[0]
+	imageArch, ok := ec2ImageArches[image.Architecture]
+	if !ok {
+		return nil, errors.NotSupportedf("image %q with architecture %q", id, image.Architecture)
+	}
+	return []*imagemetadata.ImageMetadata{{
+		Id:         image.Id,
+		Arch:       imageArch,
+		VirtType:   image.VirtualizationType,
+		RegionName: region,
+	}}, nil
+}
+
 // findInstanceSpec returns an InstanceSpec satisfying the supplied instanceConstraint.
 func findInstanceSpec(
 	controller bool,
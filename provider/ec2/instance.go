@@ -21,6 +21,14 @@ type ec2Instance struct {
 	e *environ
 
 	*ec2.Instance
+
+	// interruption holds the reason the cloud interrupted the
+	// instance, if it is a spot instance that has been interrupted.
+	interruption string
+
+	// interruptionNotice is true if the cloud has only given notice
+	// that it will interrupt the instance, which is still running.
+	interruptionNotice bool
 }
 
 func (inst *ec2Instance) String() string {
@@ -34,6 +42,16 @@ func (inst *ec2Instance) Id() instance.Id {
 }
 
 func (inst *ec2Instance) Status(ctx context.ProviderCallContext) instance.Status {
+	if inst.interruption != "" {
+		interruptionStatus := status.Interrupted
+		if inst.interruptionNotice {
+			interruptionStatus = status.Interrupting
+		}
+		return instance.Status{
+			Status:  interruptionStatus,
+			Message: inst.interruption,
+		}
+	}
 	// pending | running | shutting-down | terminated | stopping | stopped
 	var jujuStatus status.Status
 	switch inst.State.Name {
@@ -753,6 +753,37 @@ func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	c.Check(*hc.CpuCores, gc.Equals, uint64(2))
 }
 
+func (t *localServerSuite) TestStartInstanceImageID(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	var describedIds []string
+	t.PatchValue(ec2.EC2Images, func(_ *amzec2.EC2, ids []string, _ *amzec2.Filter) (*amzec2.ImagesResp, error) {
+		describedIds = append(describedIds, ids...)
+		return &amzec2.ImagesResp{Images: []amzec2.Image{{
+			Id:                 "ami-00000133",
+			Architecture:       "x86_64",
+			VirtualizationType: "hvm",
+		}}}, nil
+	})
+	cons := constraints.MustParse("image-id=ami-00000133")
+	inst, hc := testing.AssertStartInstanceWithConstraints(c, env, t.callCtx, t.ControllerUUID, "1", cons)
+	c.Check(describedIds, jc.DeepEquals, []string{"ami-00000133"})
+	c.Check(ec2.InstanceEC2(inst).ImageId, gc.Equals, "ami-00000133")
+	c.Check(*hc.Arch, gc.Equals, "amd64")
+	c.Assert(hc.Image, gc.NotNil)
+	c.Check(*hc.Image, gc.Equals, "ami-00000133")
+}
+
+func (t *localServerSuite) TestStartInstanceImageIDNotFound(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	t.PatchValue(ec2.EC2Images, func(*amzec2.EC2, []string, *amzec2.Filter) (*amzec2.ImagesResp, error) {
+		return &amzec2.ImagesResp{}, nil
+	})
+	cons := constraints.MustParse("image-id=ami-missing")
+	_, _, _, err := testing.StartInstanceWithConstraints(env, t.callCtx, t.ControllerUUID, "1", cons)
+	c.Assert(err, gc.ErrorMatches, `image "ami-missing" not found`)
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZone(c *gc.C) {
 	inst, err := t.testStartInstanceAvailZone(c, "test-available")
 	c.Assert(err, jc.ErrorIsNil)
@@ -1469,6 +1500,16 @@ func (t *localServerSuite) TestPrecheckInstanceInvalidInstanceType(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid AWS instance type "m1.invalid" specified`)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceMaxPriceWithoutSpot(c *gc.C) {
+	env := t.Prepare(c)
+	cons := constraints.MustParse("max-price=0.05")
+	err := env.PrecheckInstance(t.callCtx, environs.PrecheckInstanceParams{
+		Series:      series.DefaultSupportedLTS(),
+		Constraints: cons,
+	})
+	c.Assert(err, gc.ErrorMatches, `max-price constraint without spot=true not valid`)
+}
+
 func (t *localServerSuite) TestPrecheckInstanceUnsupportedArch(c *gc.C) {
 	env := t.Prepare(c)
 	cons := constraints.MustParse("instance-type=cc1.4xlarge arch=i386")
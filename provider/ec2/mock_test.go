@@ -86,3 +86,7 @@ func (mockEC2Session) DescribeSpotPriceHistory(*ec2.DescribeSpotPriceHistoryInpu
 		SpotPriceHistory: nil,
 	}, nil
 }
+
+func (mockEC2Session) DescribeSpotInstanceRequests(*ec2.DescribeSpotInstanceRequestsInput) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	return &ec2.DescribeSpotInstanceRequestsOutput{}, nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/juju/errors"
+	amzec2 "gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+// tagSpotInstance marks instances started as spot instances, so that
+// their spot requests can be checked for interruptions.
+const tagSpotInstance = "juju-spot-instance"
+
+// runSpotInstance starts a one-time spot instance, which is terminated
+// when interrupted, with the arguments used to start an on-demand
+// instance. The spot price is capped at maxPrice if it is not empty,
+// and at the on-demand price otherwise.
+//
+// The amz library used for other requests has no support for spot
+// instances, so the request is made with the AWS SDK and the instance
+// started is converted for use with the rest of the provider.
+func runSpotInstance(client ec2iface.EC2API, ctx context.ProviderCallContext, ri *amzec2.RunInstances, maxPrice string) (*amzec2.Instance, error) {
+	spotOptions := &ec2.SpotMarketOptions{
+		SpotInstanceType:             aws.String(ec2.SpotInstanceTypeOneTime),
+		InstanceInterruptionBehavior: aws.String(ec2.InstanceInterruptionBehaviorTerminate),
+	}
+	if maxPrice != "" {
+		spotOptions.MaxPrice = aws.String(maxPrice)
+	}
+	input := &ec2.RunInstancesInput{
+		MinCount:     aws.Int64(1),
+		MaxCount:     aws.Int64(1),
+		ImageId:      aws.String(ri.ImageId),
+		InstanceType: aws.String(ri.InstanceType),
+		InstanceMarketOptions: &ec2.InstanceMarketOptionsRequest{
+			MarketType:  aws.String(ec2.MarketTypeSpot),
+			SpotOptions: spotOptions,
+		},
+	}
+	if ri.UserData != nil {
+		input.UserData = aws.String(base64.StdEncoding.EncodeToString(ri.UserData))
+	}
+	if ri.AvailZone != "" {
+		input.Placement = &ec2.Placement{AvailabilityZone: aws.String(ri.AvailZone)}
+	}
+	if ri.SubnetId != "" {
+		input.SubnetId = aws.String(ri.SubnetId)
+	}
+	for _, group := range ri.SecurityGroups {
+		if group.Id != "" {
+			input.SecurityGroupIds = append(input.SecurityGroupIds, aws.String(group.Id))
+		} else {
+			input.SecurityGroups = append(input.SecurityGroups, aws.String(group.Name))
+		}
+	}
+	for _, mapping := range ri.BlockDeviceMappings {
+		input.BlockDeviceMappings = append(input.BlockDeviceMappings, sdkBlockDeviceMapping(mapping))
+	}
+
+	resp, err := client.RunInstances(input)
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	if len(resp.Instances) != 1 {
+		return nil, errors.Errorf("expected 1 started instance, got %d", len(resp.Instances))
+	}
+	return amzInstance(resp.Instances[0]), nil
+}
+
+// isSpotZoneConstrainedError reports whether the error from running a
+// spot instance indicates that the availability zone can't provide the
+// instance requested, so that another zone may be tried.
+func isSpotZoneConstrainedError(err error) bool {
+	if err, ok := errors.Cause(err).(awserr.Error); ok {
+		switch err.Code() {
+		case "InsufficientInstanceCapacity", "SpotMaxPriceTooLow", "Unsupported":
+			return true
+		}
+	}
+	return false
+}
+
+func sdkBlockDeviceMapping(mapping amzec2.BlockDeviceMapping) *ec2.BlockDeviceMapping {
+	result := &ec2.BlockDeviceMapping{
+		DeviceName: aws.String(mapping.DeviceName),
+	}
+	if mapping.VirtualName != "" {
+		result.VirtualName = aws.String(mapping.VirtualName)
+		return result
+	}
+	ebs := &ec2.EbsBlockDevice{
+		DeleteOnTermination: aws.Bool(mapping.DeleteOnTermination),
+	}
+	if mapping.SnapshotId != "" {
+		ebs.SnapshotId = aws.String(mapping.SnapshotId)
+	}
+	if mapping.VolumeType != "" {
+		ebs.VolumeType = aws.String(mapping.VolumeType)
+	}
+	if mapping.VolumeSize > 0 {
+		ebs.VolumeSize = aws.Int64(mapping.VolumeSize)
+	}
+	if mapping.IOPS > 0 {
+		ebs.Iops = aws.Int64(mapping.IOPS)
+	}
+	result.Ebs = ebs
+	return result
+}
+
+// amzInstance converts an instance described by the AWS SDK into the
+// representation used by the amz library.
+func amzInstance(inst *ec2.Instance) *amzec2.Instance {
+	result := &amzec2.Instance{
+		InstanceId:       aws.StringValue(inst.InstanceId),
+		InstanceType:     aws.StringValue(inst.InstanceType),
+		ImageId:          aws.StringValue(inst.ImageId),
+		PrivateDNSName:   aws.StringValue(inst.PrivateDnsName),
+		DNSName:          aws.StringValue(inst.PublicDnsName),
+		IPAddress:        aws.StringValue(inst.PublicIpAddress),
+		PrivateIPAddress: aws.StringValue(inst.PrivateIpAddress),
+		SubnetId:         aws.StringValue(inst.SubnetId),
+		VPCId:            aws.StringValue(inst.VpcId),
+		RootDeviceType:   aws.StringValue(inst.RootDeviceType),
+		RootDeviceName:   aws.StringValue(inst.RootDeviceName),
+	}
+	if inst.Placement != nil {
+		result.AvailZone = aws.StringValue(inst.Placement.AvailabilityZone)
+	}
+	if inst.State != nil {
+		result.State = amzec2.InstanceState{
+			Code: int(aws.Int64Value(inst.State.Code)),
+			Name: aws.StringValue(inst.State.Name),
+		}
+	}
+	for _, group := range inst.SecurityGroups {
+		result.SecurityGroups = append(result.SecurityGroups, amzec2.SecurityGroup{
+			Id:   aws.StringValue(group.GroupId),
+			Name: aws.StringValue(group.GroupName),
+		})
+	}
+	for _, mapping := range inst.BlockDeviceMappings {
+		m := amzec2.InstanceBlockDeviceMapping{
+			DeviceName: aws.StringValue(mapping.DeviceName),
+		}
+		if mapping.Ebs != nil {
+			m.VolumeId = aws.StringValue(mapping.Ebs.VolumeId)
+			m.Status = aws.StringValue(mapping.Ebs.Status)
+			m.DeleteOnTermination = aws.BoolValue(mapping.Ebs.DeleteOnTermination)
+		}
+		result.BlockDeviceMappings = append(result.BlockDeviceMappings, m)
+	}
+	return result
+}
+
+// isSpotInstance reports whether the instance was started as a spot
+// instance.
+func isSpotInstance(inst *amzec2.Instance) bool {
+	for _, tag := range inst.Tags {
+		if tag.Key == tagSpotInstance {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpotInterruption reports whether the status code of a spot
+// request shows that the cloud has interrupted, or is about to
+// interrupt, its instance.
+func isSpotInterruption(code string) bool {
+	switch {
+	case isSpotInterruptionNotice(code):
+		return true
+	case strings.HasPrefix(code, "instance-terminated-"), strings.HasPrefix(code, "instance-stopped-"):
+		return !strings.HasSuffix(code, "-by-user")
+	}
+	return false
+}
+
+// isSpotInterruptionNotice reports whether the status code of a spot
+// request shows that the cloud has given notice that it will interrupt
+// its instance, which is still running.
+func isSpotInterruptionNotice(code string) bool {
+	return strings.HasPrefix(code, "marked-for-")
+}
+
+// addSpotInterruptions checks the spot requests of the spot instances
+// among insts, and of any instances not found, and records those
+// interrupted by the cloud. Interrupted instances that are no longer
+// alive are added to insts, so that the interruption can be reported.
+//
+// Failure to check is logged rather than returned; it only means that
+// interruptions are not yet reported.
+func (e *environ) addSpotInterruptions(ctx context.ProviderCallContext, ids []instance.Id, insts []instances.Instance) {
+	var check []string
+	for i, inst := range insts {
+		if inst == nil || isSpotInstance(inst.(*ec2Instance).Instance) {
+			check = append(check, string(ids[i]))
+		}
+	}
+	if len(check) == 0 {
+		return
+	}
+	ec2Session := EC2Session(e.cloud.Region, e.ec2.AccessKey, e.ec2.SecretKey)
+	resp, err := ec2Session.DescribeSpotInstanceRequests(&ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("instance-id"),
+			Values: aws.StringSlice(check),
+		}},
+	})
+	if err != nil {
+		logger.Warningf("cannot check spot instances for interruptions: %v", maybeConvertCredentialError(err, ctx))
+		return
+	}
+	interruptions := make(map[string]*ec2.SpotInstanceStatus)
+	for _, request := range resp.SpotInstanceRequests {
+		if request.Status == nil || !isSpotInterruption(aws.StringValue(request.Status.Code)) {
+			continue
+		}
+		interruptions[aws.StringValue(request.InstanceId)] = request.Status
+	}
+	for i, id := range ids {
+		interruption, ok := interruptions[string(id)]
+		if !ok {
+			continue
+		}
+		message := aws.StringValue(interruption.Message)
+		if message == "" {
+			message = "spot instance interrupted"
+		}
+		if insts[i] == nil {
+			insts[i] = &ec2Instance{
+				e: e,
+				Instance: &amzec2.Instance{
+					InstanceId: string(id),
+					State:      amzec2.InstanceState{Name: "terminated"},
+				},
+			}
+		}
+		inst := insts[i].(*ec2Instance)
+		inst.interruption = message
+		inst.interruptionNotice = isSpotInterruptionNotice(aws.StringValue(interruption.Code))
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	jc "github.com/juju/testing/checkers"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/provider/ec2"
+)
+
+// spotEC2Server is a stub EC2 endpoint for the requests made with the
+// AWS SDK to start spot instances and check them for interruptions.
+// The instances it is asked to run are started in the ec2test server,
+// so that they are known to the rest of the provider.
+type spotEC2Server struct {
+	*httptest.Server
+	client *amzec2.EC2
+
+	mu            sync.Mutex
+	runForm       url.Values
+	runError      [2]string
+	interruptions map[string][2]string
+}
+
+func newSpotEC2Server(client *amzec2.EC2) *spotEC2Server {
+	srv := &spotEC2Server{
+		client:        client,
+		interruptions: make(map[string][2]string),
+	}
+	srv.Server = httptest.NewServer(srv)
+	return srv
+}
+
+// interrupt records that the spot request of the instance has the
+// status code and message.
+func (srv *spotEC2Server) interrupt(id instance.Id, code, message string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.interruptions[string(id)] = [2]string{code, message}
+}
+
+// failRunInstances makes RunInstances requests fail with the error
+// code and message.
+func (srv *spotEC2Server) failRunInstances(code, message string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.runError = [2]string{code, message}
+}
+
+// lastRunForm returns the form of the last RunInstances request.
+func (srv *spotEC2Server) lastRunForm() url.Values {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.runForm
+}
+
+func (srv *spotEC2Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		srv.error(w, "InvalidRequest", err.Error())
+		return
+	}
+	switch action := req.Form.Get("Action"); action {
+	case "RunInstances":
+		srv.runInstances(w, req.Form)
+	case "DescribeSpotInstanceRequests":
+		srv.describeSpotInstanceRequests(w, req.Form)
+	default:
+		srv.error(w, "InvalidAction", fmt.Sprintf("unexpected action %q", action))
+	}
+}
+
+func (srv *spotEC2Server) runInstances(w http.ResponseWriter, form url.Values) {
+	srv.mu.Lock()
+	srv.runForm = form
+	runError := srv.runError
+	srv.mu.Unlock()
+	if runError[0] != "" {
+		srv.error(w, runError[0], runError[1])
+		return
+	}
+
+	resp, err := srv.client.RunInstances(&amzec2.RunInstances{
+		ImageId:      form.Get("ImageId"),
+		InstanceType: form.Get("InstanceType"),
+		AvailZone:    form.Get("Placement.AvailabilityZone"),
+		SubnetId:     form.Get("SubnetId"),
+	})
+	if err != nil {
+		srv.error(w, "InternalError", err.Error())
+		return
+	}
+	inst := resp.Instances[0]
+	fmt.Fprintf(w, `<RunInstancesResponse>
+  <requestId>req-1</requestId>
+  <reservationId>%s</reservationId>
+  <instancesSet>
+    <item>
+      <instanceId>%s</instanceId>
+      <instanceType>%s</instanceType>
+      <imageId>%s</imageId>
+      <instanceState><code>%d</code><name>%s</name></instanceState>
+      <placement><availabilityZone>%s</availabilityZone></placement>
+      <instanceLifecycle>spot</instanceLifecycle>
+    </item>
+  </instancesSet>
+</RunInstancesResponse>`,
+		resp.ReservationId, inst.InstanceId, inst.InstanceType, inst.ImageId,
+		inst.State.Code, inst.State.Name, inst.AvailZone,
+	)
+}
+
+func (srv *spotEC2Server) describeSpotInstanceRequests(w http.ResponseWriter, form url.Values) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	var items []string
+	for key, values := range form {
+		if !strings.HasPrefix(key, "Filter.1.Value.") {
+			continue
+		}
+		interruption, ok := srv.interruptions[values[0]]
+		if !ok {
+			continue
+		}
+		items = append(items, fmt.Sprintf(`
+    <item>
+      <spotInstanceRequestId>sir-%s</spotInstanceRequestId>
+      <instanceId>%s</instanceId>
+      <status><code>%s</code><message>%s</message></status>
+    </item>`, values[0], values[0], interruption[0], interruption[1]))
+	}
+	fmt.Fprintf(w, `<DescribeSpotInstanceRequestsResponse>
+  <requestId>req-2</requestId>
+  <spotInstanceRequestSet>%s
+  </spotInstanceRequestSet>
+</DescribeSpotInstanceRequestsResponse>`, strings.Join(items, ""))
+}
+
+func (srv *spotEC2Server) error(w http.ResponseWriter, code, message string) {
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, `<Response><Errors><Error><Code>%s</Code><Message>%s</Message></Error></Errors><RequestID>req-3</RequestID></Response>`, code, message)
+}
+
+// spotEC2Session sends the spot requests made with the AWS SDK to a
+// stub EC2 endpoint, and answers the others as mockEC2Session does.
+type spotEC2Session struct {
+	mockEC2Session
+	spot ec2iface.EC2API
+}
+
+func (s spotEC2Session) RunInstances(input *awsec2.RunInstancesInput) (*awsec2.Reservation, error) {
+	return s.spot.RunInstances(input)
+}
+
+func (s spotEC2Session) DescribeSpotInstanceRequests(input *awsec2.DescribeSpotInstanceRequestsInput) (*awsec2.DescribeSpotInstanceRequestsOutput, error) {
+	return s.spot.DescribeSpotInstanceRequests(input)
+}
+
+func (t *localServerSuite) patchSpotEC2Server(c *gc.C) *spotEC2Server {
+	srv := newSpotEC2Server(t.client)
+	t.AddCleanup(func(*gc.C) { srv.Close() })
+	sess := session.Must(session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("test"),
+		Credentials: credentials.NewStaticCredentials("x", "x", ""),
+	}))
+	spot := awsec2.New(sess)
+	t.PatchValue(&ec2.EC2Session, func(region, accessKey, secretKey string) ec2iface.EC2API {
+		return spotEC2Session{spot: spot}
+	})
+	return srv
+}
+
+func (t *localServerSuite) TestStartSpotInstance(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	result, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		Constraints:    constraints.MustParse("spot=true max-price=0.05"),
+		StatusCallback: fakeCallback,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	form := srv.lastRunForm()
+	c.Assert(form, gc.NotNil)
+	c.Check(form.Get("InstanceMarketOptions.MarketType"), gc.Equals, "spot")
+	c.Check(form.Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "0.05")
+	c.Check(form.Get("InstanceMarketOptions.SpotOptions.SpotInstanceType"), gc.Equals, "one-time")
+	c.Check(form.Get("InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior"), gc.Equals, "terminate")
+	c.Check(form.Get("UserData"), gc.Not(gc.Equals), "")
+
+	id := result.Instance.Id()
+	insts, err := env.Instances(t.callCtx, []instance.Id{id})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(insts[0].Status(t.callCtx).Status, gc.Not(gc.Equals), status.Interrupted)
+	var spotTag string
+	for _, tag := range ec2.InstanceEC2(insts[0]).Tags {
+		if tag.Key == "juju-spot-instance" {
+			spotTag = tag.Value
+		}
+	}
+	c.Check(spotTag, gc.Equals, "true")
+}
+
+func (t *localServerSuite) TestStartSpotInstanceZoneConstrained(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	for _, code := range []string{"InsufficientInstanceCapacity", "SpotMaxPriceTooLow", "Unsupported"} {
+		c.Logf("error code %q", code)
+		srv.failRunInstances(code, "no spot capacity")
+		_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+			ControllerUUID:   t.ControllerUUID,
+			Constraints:      constraints.MustParse("spot=true"),
+			StatusCallback:   fakeCallback,
+			AvailabilityZone: "test-available",
+		})
+		c.Assert(err, gc.ErrorMatches, "(?s)cannot run spot instance: "+code+": no spot capacity.*")
+		// Another availability zone may have capacity, so the
+		// error should not be zone independent.
+		c.Assert(err, gc.Not(jc.Satisfies), environs.IsAvailabilityZoneIndependent)
+	}
+}
+
+func (t *localServerSuite) TestStartSpotInstanceZoneIndependent(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	srv.failRunInstances("InvalidParameterValue", "bad request")
+	_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID:   t.ControllerUUID,
+		Constraints:      constraints.MustParse("spot=true"),
+		StatusCallback:   fakeCallback,
+		AvailabilityZone: "test-available",
+	})
+	c.Assert(err, gc.ErrorMatches, "(?s)cannot run spot instance: InvalidParameterValue: bad request.*")
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+}
+
+func (t *localServerSuite) TestStartInstanceWithoutSpot(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(srv.lastRunForm(), gc.IsNil)
+}
+
+func (t *localServerSuite) TestSpotInstanceInterrupted(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	result, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		Constraints:    constraints.MustParse("spot=true"),
+		StatusCallback: fakeCallback,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	id := result.Instance.Id()
+	c.Check(srv.lastRunForm().Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "")
+
+	// The instance is reported as interrupting as soon as the cloud
+	// marks it for termination.
+	srv.interrupt(id, "marked-for-termination", "Spot instance termination notice")
+	insts, err := env.Instances(t.callCtx, []instance.Id{id})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(insts[0].Status(t.callCtx), jc.DeepEquals, instance.Status{
+		Status:  status.Interrupting,
+		Message: "Spot instance termination notice",
+	})
+
+	// And as interrupted once it has been terminated.
+	srv.interrupt(id, "instance-terminated-no-capacity", "Spot instance terminated due to no capacity")
+	_, err = t.client.TerminateInstances([]string{string(id)})
+	c.Assert(err, jc.ErrorIsNil)
+	insts, err = env.Instances(t.callCtx, []instance.Id{id})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(insts[0].Status(t.callCtx), jc.DeepEquals, instance.Status{
+		Status:  status.Interrupted,
+		Message: "Spot instance terminated due to no capacity",
+	})
+}
+
+func (t *localServerSuite) TestSpotInstanceTerminatedByUser(c *gc.C) {
+	srv := t.patchSpotEC2Server(c)
+	env := t.prepareAndBootstrap(c)
+
+	result, err := testing.StartInstanceWithParams(env, t.callCtx, "1", environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		Constraints:    constraints.MustParse("spot=true"),
+		StatusCallback: fakeCallback,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	id := result.Instance.Id()
+
+	srv.interrupt(id, "instance-terminated-by-user", "Spot instance terminated by user")
+	_, err = t.client.TerminateInstances([]string{string(id)})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = env.Instances(t.callCtx, []instance.Id{id})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
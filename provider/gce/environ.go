@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
 
@@ -29,6 +30,9 @@ type gceConnection interface {
 	// and returns it.
 	Instance(id, zone string) (google.Instance, error)
 	Instances(prefix string, statuses ...string) ([]google.Instance, error)
+	// PreemptedInstances returns the IDs of the instances, with IDs
+	// starting with the given prefix, which GCE has preempted.
+	PreemptedInstances(prefix string) (set.Strings, error)
 	AddInstance(spec google.InstanceSpec) (*google.Instance, error)
 	RemoveInstances(prefix string, ids ...string) error
 	UpdateMetadata(key, value string, ids ...string) error
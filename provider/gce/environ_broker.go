@@ -60,6 +60,9 @@ func (env *environ) StartInstance(ctx context.ProviderCallContext, args environs
 
 	// Build the result.
 	hwc := getHardwareCharacteristics(env, spec, inst)
+	if args.Constraints.HasImageName() {
+		hwc.Image = args.Constraints.ImageName
+	}
 	result := environs.StartInstanceResult{
 		Instance: inst,
 		Hardware: hwc,
@@ -99,6 +102,20 @@ func (env *environ) finishInstanceConfig(args environs.StartInstanceParams, spec
 func (env *environ) buildInstanceSpec(args environs.StartInstanceParams) (*instances.InstanceSpec, error) {
 	arches := args.Tools.Arches()
 	series := args.Tools.OneSeries()
+	imageMetadata := args.ImageMetadata
+	if args.Constraints.HasImageName() {
+		// The image was chosen by the operator, so simplestreams is not
+		// consulted. GCE does not record an image's architecture, so
+		// the image is assumed to support all of the tools' arches.
+		imageMetadata = make([]*imagemetadata.ImageMetadata, len(arches))
+		for i, arch := range arches {
+			imageMetadata[i] = &imagemetadata.ImageMetadata{
+				Id:         *args.Constraints.ImageName,
+				Arch:       arch,
+				RegionName: env.cloud.Region,
+			}
+		}
+	}
 	spec, err := findInstanceSpec(
 		env, &instances.InstanceConstraint{
 			Region:      env.cloud.Region,
@@ -106,7 +123,7 @@ func (env *environ) buildInstanceSpec(args environs.StartInstanceParams) (*insta
 			Arches:      arches,
 			Constraints: args.Constraints,
 		},
-		args.ImageMetadata,
+		imageMetadata,
 	)
 	return spec, errors.Trace(err)
 }
@@ -177,9 +194,12 @@ func (env *environ) newRawInstance(ctx context.ProviderCallContext, args environ
 		hostname,
 	}
 
-	imageURLBase, err := env.imageURLBase(os)
-	if err != nil {
-		return nil, common.ZoneIndependentError(err)
+	imageURLBase := customImageBasePath
+	if !args.Constraints.HasImageName() {
+		imageURLBase, err = env.imageURLBase(os)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
 	}
 
 	disks, err := getDisks(
@@ -204,6 +224,7 @@ func (env *environ) newRawInstance(ctx context.ProviderCallContext, args environ
 		Metadata:          metadata,
 		Tags:              tags,
 		AvailabilityZone:  args.AvailabilityZone,
+		Preemptible:       args.Constraints.HasSpot(),
 		// Network is omitted (left empty).
 	})
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
@@ -95,6 +96,19 @@ func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
 	c.Check(result.Hardware, jc.DeepEquals, s.hardware)
 }
 
+func (s *environBrokerSuite) TestStartInstanceImageName(c *gc.C) {
+	s.FakeEnviron.Spec = s.spec
+	s.FakeEnviron.Inst = s.BaseInstance
+	s.FakeEnviron.Hwc = s.hardware
+	s.StartInstArgs.Constraints = constraints.MustParse("image-name=custom")
+
+	result, err := s.Env.StartInstance(s.CallCtx, s.StartInstArgs)
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.Image, gc.NotNil)
+	c.Check(*result.Hardware.Image, gc.Equals, "custom")
+}
+
 func (s *environBrokerSuite) TestStartInstanceAvailabilityZoneIndependentError(c *gc.C) {
 	s.FakeEnviron.Err = errors.New("blargh")
 
@@ -138,6 +152,33 @@ func (s *environBrokerSuite) TestBuildInstanceSpec(c *gc.C) {
 	c.Check(spec.InstanceType, jc.DeepEquals, s.InstanceType)
 }
 
+func (s *environBrokerSuite) TestBuildInstanceSpecImageName(c *gc.C) {
+	s.FakeEnviron.Spec = s.spec
+	s.StartInstArgs.Constraints = constraints.MustParse("image-name=custom")
+
+	_, err := gce.BuildInstanceSpec(s.Env, s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The image is used as given, instead of the simplestreams metadata.
+	s.FakeEnviron.CheckCalls(c, []gce.FakeCall{{
+		FuncName: "FindInstanceSpec",
+		Args: gce.FakeCallArgs{
+			"switch": s.Env,
+			"ic": &instances.InstanceConstraint{
+				Region:      "us-east1",
+				Series:      s.StartInstArgs.Tools.OneSeries(),
+				Arches:      s.StartInstArgs.Tools.Arches(),
+				Constraints: s.StartInstArgs.Constraints,
+			},
+			"imageMetadata": []*imagemetadata.ImageMetadata{{
+				Id:         "custom",
+				Arch:       arch.AMD64,
+				RegionName: "us-east1",
+			}},
+		},
+	}})
+}
+
 func (s *environBrokerSuite) TestFindInstanceSpec(c *gc.C) {
 	spec, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
 
@@ -6,6 +6,7 @@ package gce
 import (
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/version"
 
@@ -36,7 +37,11 @@ func (env *environ) Instances(ctx context.ProviderCallContext, ids []instance.Id
 		return nil, environs.ErrNoInstances
 	}
 
-	all, err := getInstances(env, ctx)
+	// Preempted instances are stopped rather than deleted, and are
+	// included so that their interruption can be reported.
+	statuses := append([]string{google.StatusStopping, google.StatusTerminated}, instStatuses...)
+	all, err := getInstances(env, ctx, statuses...)
+	all = env.withoutStoppedInstances(ctx, all)
 	if err != nil {
 		// We don't return the error since we need to pack one instance
 		// for each ID into the result. If there is a problem then we
@@ -67,6 +72,41 @@ func (env *environ) Instances(ctx context.ProviderCallContext, ids []instance.Id
 	return results, err
 }
 
+// withoutStoppedInstances returns the instances that are either alive
+// or have been preempted, marking those that have been preempted.
+func (env *environ) withoutStoppedInstances(ctx context.ProviderCallContext, all []instances.Instance) []instances.Instance {
+	var (
+		result    []instances.Instance
+		preempted set.Strings
+	)
+	for _, inst := range all {
+		inst := inst.(*environInstance)
+		switch inst.base.Status() {
+		case google.StatusStopping, google.StatusTerminated:
+			if !inst.base.Preemptible {
+				continue
+			}
+			// A preemptible instance may also have been stopped by
+			// hand, so GCE's record of preemptions is checked.
+			if preempted == nil {
+				var err error
+				preempted, err = env.gce.PreemptedInstances(env.namespace.Prefix())
+				if err != nil {
+					err = google.HandleCredentialError(errors.Trace(err), ctx)
+					logger.Warningf("cannot check instances for preemption: %v", err)
+					preempted = set.NewStrings()
+				}
+			}
+			if !preempted.Contains(inst.base.ID) {
+				continue
+			}
+			inst.preempted = true
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
 var getInstances = func(env *environ, ctx context.ProviderCallContext, statusFilters ...string) ([]instances.Instance, error) {
 	return env.instances(ctx, statusFilters...)
 }
@@ -4,6 +4,7 @@
 package gce_test
 
 import (
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/tags"
@@ -37,6 +39,34 @@ func (s *environInstSuite) TestInstances(c *gc.C) {
 	c.Check(insts, jc.DeepEquals, []instances.Instance{spam, eggs, ham})
 }
 
+func (s *environInstSuite) TestInstancesStopped(c *gc.C) {
+	spam := s.NewBaseInstance(c, "spam")
+	spam.InstanceSummary.Status = google.StatusTerminated
+	ham := s.NewBaseInstance(c, "ham")
+	ham.InstanceSummary.Status = google.StatusTerminated
+	ham.InstanceSummary.Preemptible = true
+	eggs := s.NewBaseInstance(c, "eggs")
+	eggs.InstanceSummary.Status = google.StatusTerminated
+	eggs.InstanceSummary.Preemptible = true
+	s.FakeEnviron.Insts = []instances.Instance{
+		s.NewInstanceFromBase(spam),
+		s.NewInstanceFromBase(ham),
+		s.NewInstanceFromBase(eggs),
+	}
+	s.FakeConn.Preempted = set.NewStrings("ham")
+
+	// Only preempted instances are reported once stopped; eggs is
+	// preemptible but was stopped by hand.
+	ids := []instance.Id{"spam", "ham", "eggs"}
+	insts, err := s.Env.Instances(s.CallCtx, ids)
+	c.Check(errors.Cause(err), gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts, gc.HasLen, 3)
+	c.Check(insts[0], gc.IsNil)
+	c.Check(insts[1].Id(), gc.Equals, instance.Id("ham"))
+	c.Check(insts[1].Status(s.CallCtx).Status, gc.Equals, status.Interrupted)
+	c.Check(insts[2], gc.IsNil)
+}
+
 func (s *environInstSuite) TestInstancesEmptyArg(c *gc.C) {
 	_, err := s.Env.Instances(s.CallCtx, nil)
 
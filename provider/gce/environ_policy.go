@@ -34,6 +34,10 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	// Preemptible instances have a fixed price.
+	constraints.MaxPrice,
+	// Images are named, not identified, in GCE.
+	constraints.ImageID,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
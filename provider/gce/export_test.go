@@ -27,6 +27,10 @@ func ExposeInstBase(inst instances.Instance) *google.Instance {
 	return inst.(*environInstance).base
 }
 
+func SetInstPreempted(inst instances.Instance) {
+	inst.(*environInstance).preempted = true
+}
+
 func ExposeInstEnv(inst *environInstance) *environ {
 	return inst.env
 }
@@ -26,6 +26,10 @@ const (
 	ubuntuImageBasePath      = "projects/ubuntu-os-cloud/global/images/"
 	ubuntuDailyImageBasePath = "projects/ubuntu-os-cloud-devel/global/images/"
 	windowsImageBasePath     = "projects/windows-cloud/global/images/"
+
+	// customImageBasePath is the base path of images named with the
+	// image-name constraint, which live in the model's project.
+	customImageBasePath = "global/images/"
 )
 
 var (
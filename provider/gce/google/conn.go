@@ -37,6 +37,11 @@ type service interface {
 	// the specified statuses (if any).
 	ListInstances(projectID, prefix string, status ...string) ([]*compute.Instance, error)
 
+	// ListPreemptions sends a request to the GCE API for a list of the
+	// operations recording that GCE preempted an instance in the
+	// project.
+	ListPreemptions(projectID string) ([]*compute.Operation, error)
+
 	// AddInstance sends a request to GCE to add a new instance to the
 	// given project, with the provided instance data. The call blocks
 	// until the instance is created or the request fails.
@@ -5,7 +5,9 @@ package google
 
 import (
 	"path"
+	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
 )
@@ -84,6 +86,26 @@ func (gce *Connection) Instances(prefix string, statuses ...string) ([]Instance,
 	return insts, nil
 }
 
+// PreemptedInstances returns the IDs of the instances in the
+// connection's project, with IDs starting with the provided prefix,
+// which GCE has preempted.
+func (gce *Connection) PreemptedInstances(prefix string) (set.Strings, error) {
+	ops, err := gce.service.ListPreemptions(gce.projectID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ids := set.NewStrings()
+	for _, op := range ops {
+		// The target link ends in "zones/<zone>/instances/<name>".
+		id := path.Base(op.TargetLink)
+		if strings.HasPrefix(id, prefix) {
+			ids.Add(id)
+		}
+	}
+	return ids, nil
+}
+
 // removeInstance sends a request to the GCE API to remove the instance
 // with the provided ID (in the specified zone). The call blocks until
 // the instance is removed (or the request fails).
@@ -195,6 +195,24 @@ func (s *connSuite) TestConnectionInstancesFailure(c *gc.C) {
 	c.Check(errors.Cause(err), gc.Equals, failure)
 }
 
+func (s *connSuite) TestConnectionPreemptedInstances(c *gc.C) {
+	s.FakeConn.Preemptions = []*compute.Operation{{
+		OperationType: "compute.instances.preempted",
+		TargetLink:    "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/instances/sp-1",
+	}, {
+		OperationType: "compute.instances.preempted",
+		TargetLink:    "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/instances/eggs-1",
+	}}
+
+	ids, err := s.Conn.PreemptedInstances("sp")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids.SortedValues(), jc.DeepEquals, []string{"sp-1"})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListPreemptions")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+}
+
 func (s *connSuite) TestConnectionRemoveInstance(c *gc.C) {
 	err := google.ConnRemoveInstance(s.Conn, "spam", "a-zone")
 
@@ -52,10 +52,14 @@ type InstanceSpec struct {
 	// AvailabilityZone holds the name of the availability zone in which
 	// to create the instance.
 	AvailabilityZone string
+
+	// Preemptible indicates that the instance should be preemptible,
+	// which makes it cheaper but allows GCE to stop it at any time.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
-	return &compute.Instance{
+	raw := &compute.Instance{
 		Name:              is.ID,
 		Disks:             is.disks(),
 		NetworkInterfaces: is.networkInterfaces(),
@@ -63,6 +67,17 @@ func (is InstanceSpec) raw() *compute.Instance {
 		Tags:              &compute.Tags{Items: is.Tags},
 		// MachineType is set in the addInstance call.
 	}
+	if is.Preemptible {
+		// Preemptible instances can be neither restarted nor
+		// migrated by GCE.
+		automaticRestart := false
+		raw.Scheduling = &compute.Scheduling{
+			Preemptible:       true,
+			AutomaticRestart:  &automaticRestart,
+			OnHostMaintenance: "TERMINATE",
+		}
+	}
+	return raw
 }
 
 // Summary builds an InstanceSummary based on the spec and returns it.
@@ -111,6 +126,9 @@ type InstanceSummary struct {
 	// NetworkInterfaces are the network connections associated with
 	// the instance.
 	NetworkInterfaces []*compute.NetworkInterface
+	// Preemptible indicates whether GCE may stop the instance at any
+	// time.
+	Preemptible bool
 }
 
 func newInstanceSummary(raw *compute.Instance) InstanceSummary {
@@ -121,6 +139,7 @@ func newInstanceSummary(raw *compute.Instance) InstanceSummary {
 		Metadata:          unpackMetadata(raw.Metadata),
 		Addresses:         extractAddresses(raw.NetworkInterfaces...),
 		NetworkInterfaces: raw.NetworkInterfaces,
+		Preemptible:       raw.Scheduling != nil && raw.Scheduling.Preemptible,
 	}
 }
 
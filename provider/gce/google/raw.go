@@ -81,6 +81,28 @@ func (rc *rawConn) ListInstances(projectID, prefix string, statuses ...string) (
 	return results, nil
 }
 
+func (rc *rawConn) ListPreemptions(projectID string) ([]*compute.Operation, error) {
+	call := rc.GlobalOperations.AggregatedList(projectID)
+	call = call.Filter("operationType eq compute.instances.preempted")
+
+	var results []*compute.Operation
+	for {
+		rawResult, err := call.Do()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		for _, opList := range rawResult.Items {
+			results = append(results, opList.Operations...)
+		}
+		if rawResult.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(rawResult.NextPageToken)
+	}
+	return results, nil
+}
+
 func checkInstStatus(inst *compute.Instance, statuses []string) bool {
 	if len(statuses) == 0 {
 		return true
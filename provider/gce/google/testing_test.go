@@ -176,6 +176,7 @@ type fakeConn struct {
 	Project       *compute.Project
 	Instance      *compute.Instance
 	Instances     []*compute.Instance
+	Preemptions   []*compute.Operation
 	Firewalls     []*compute.Firewall
 	Zones         []*compute.Zone
 	Err           error
@@ -233,6 +234,20 @@ func (rc *fakeConn) ListInstances(projectID, prefix string, statuses ...string)
 	return rc.Instances, err
 }
 
+func (rc *fakeConn) ListPreemptions(projectID string) ([]*compute.Operation, error) {
+	call := fakeCall{
+		FuncName:  "ListPreemptions",
+		ProjectID: projectID,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Preemptions, err
+}
+
 func (rc *fakeConn) AddInstance(projectID, zoneName string, spec *compute.Instance) error {
 	call := fakeCall{
 		FuncName:  "AddInstance",
@@ -18,6 +18,9 @@ import (
 type environInstance struct {
 	base *google.Instance
 	env  *environ
+
+	// preempted is true if GCE has preempted the instance.
+	preempted bool
 }
 
 var _ instances.Instance = (*environInstance)(nil)
@@ -44,7 +47,11 @@ func (inst *environInstance) Status(ctx context.ProviderCallContext) instance.St
 	case "RUNNING":
 		jujuStatus = status.Running
 	case "STOPPING", "TERMINATED":
-		jujuStatus = status.Empty
+		if inst.preempted {
+			jujuStatus = status.Interrupted
+		} else {
+			jujuStatus = status.Empty
+		}
 	default:
 		jujuStatus = status.Empty
 	}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -505,6 +506,7 @@ type fakeConn struct {
 
 	Inst      *google.Instance
 	Insts     []google.Instance
+	Preempted set.Strings
 	Rules     []network.IngressRule
 	Zones     []google.AvailabilityZone
 	Subnets   []*compute.Subnetwork
@@ -551,6 +553,14 @@ func (fc *fakeConn) Instances(prefix string, statuses ...string) ([]google.Insta
 	return fc.Insts, fc.err()
 }
 
+func (fc *fakeConn) PreemptedInstances(prefix string) (set.Strings, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "PreemptedInstances",
+		Prefix:   prefix,
+	})
+	return fc.Preempted, fc.err()
+}
+
 func (fc *fakeConn) AddInstance(spec google.InstanceSpec) (*google.Instance, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:     "AddInstance",
@@ -50,6 +50,10 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.ImageID,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -5,8 +5,10 @@ package lxd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cloudconfig/providerinit"
 	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
@@ -120,7 +123,14 @@ func (env *environ) newContainer(
 		return nil, errors.Trace(err)
 	}
 
-	image, err := target.FindImage(args.InstanceConfig.Series, arch, imageSources, true, statusCallback)
+	var image lxd.SourcedImage
+	if ref := imageConstraint(args.Constraints); ref != "" {
+		// The image was chosen by the operator; it must already be
+		// in the target server's image store.
+		image, err = target.LocalImage(ref)
+	} else {
+		image, err = target.FindImage(args.InstanceConfig.Series, arch, imageSources, true, statusCallback)
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -245,6 +255,11 @@ func (env *environ) getContainerSpec(
 // getTargetServer checks to see if a valid zone was passed as a placement
 // directive in the start-up start-up arguments. If so, a server for the
 // specific node is returned.
+// Otherwise, if the server is clustered, a server for the cluster member
+// chosen by the provisioner as the availability zone is returned, as long
+// as it has capacity for the constraints. Without an availability zone,
+// the member with the most free capacity satisfying the constraints is
+// used.
 func (env *environ) getTargetServer(
 	ctx context.ProviderCallContext, args environs.StartInstanceParams,
 ) (Server, error) {
@@ -253,10 +268,73 @@ func (env *environ) getTargetServer(
 		return nil, errors.Trace(err)
 	}
 
+	server := env.server()
 	if p.nodeName == "" {
-		return env.server(), nil
+		if !server.IsClustered() {
+			return server, nil
+		}
+		capacities, err := server.ClusterMemberCapacities()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		p.nodeName, err = selectClusterMember(capacities, args.Constraints, args.AvailabilityZone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		logger.Debugf("selected cluster member %q for machine %q", p.nodeName, args.InstanceConfig.MachineId)
+	}
+	return server.UseTargetServer(p.nodeName)
+}
+
+// selectClusterMember returns the name of the cluster member that can
+// accommodate the input constraints. If a zone is given, that member is
+// returned if it has the capacity; otherwise the member with the most
+// free memory, then the most free CPU cores, is returned.
+func selectClusterMember(capacities []lxd.ClusterMemberCapacity, cons constraints.Value, zone string) (string, error) {
+	zones := set.NewStrings()
+	if cons.HasZones() {
+		zones = set.NewStrings(*cons.Zones...)
+	}
+	var candidates []lxd.ClusterMemberCapacity
+	for _, m := range capacities {
+		if !zones.IsEmpty() && !zones.Contains(m.Name) {
+			continue
+		}
+		if cons.HasCpuCores() && m.FreeCPUCores() < *cons.CpuCores {
+			continue
+		}
+		if cons.Mem != nil && m.FreeMemoryMiB() < *cons.Mem {
+			continue
+		}
+		candidates = append(candidates, m)
 	}
-	return env.server().UseTargetServer(p.nodeName)
+	if len(candidates) == 0 {
+		return "", common.ZoneIndependentError(
+			errors.Errorf("no cluster member has capacity for constraints %q", cons.String()))
+	}
+
+	if zone != "" {
+		for _, m := range candidates {
+			if m.Name == zone {
+				return m.Name, nil
+			}
+		}
+		// Other members have the capacity, so the provisioner
+		// can try them next.
+		return "", errors.Errorf("cluster member %q has no capacity for constraints %q", zone, cons.String())
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.FreeMemoryMiB() != b.FreeMemoryMiB() {
+			return a.FreeMemoryMiB() > b.FreeMemoryMiB()
+		}
+		if a.FreeCPUCores() != b.FreeCPUCores() {
+			return a.FreeCPUCores() > b.FreeCPUCores()
+		}
+		return a.Name < b.Name
+	})
+	return candidates[0].Name, nil
 }
 
 type lxdPlacement struct {
@@ -308,11 +386,31 @@ func (env *environ) getHardwareCharacteristics(
 	}
 	cores := uint64(container.CPUs())
 	mem := uint64(container.Mem())
-	return &instance.HardwareCharacteristics{
+	hwc := &instance.HardwareCharacteristics{
 		Arch:     &archStr,
 		CpuCores: &cores,
 		Mem:      &mem,
 	}
+	if ref := imageConstraint(args.Constraints); ref != "" {
+		hwc.Image = &ref
+	}
+	// Standalone servers report their containers' location as "none".
+	if zone := container.Location; zone != "" && zone != "none" {
+		hwc.AvailabilityZone = &zone
+	}
+	return hwc
+}
+
+// imageConstraint returns the alias (image-name) or fingerprint
+// (image-id) of the image requested by the constraints, if any.
+func imageConstraint(cons constraints.Value) string {
+	switch {
+	case cons.HasImageID():
+		return *cons.ImageID
+	case cons.HasImageName():
+		return *cons.ImageName
+	}
+	return ""
 }
 
 // AllInstances implements environs.InstanceBroker.
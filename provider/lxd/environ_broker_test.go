@@ -17,6 +17,7 @@ import (
 	containerlxd "github.com/juju/juju/container/lxd"
 	lxdtesting "github.com/juju/juju/container/lxd/testing"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/provider/lxd"
 )
@@ -90,6 +91,7 @@ func (s *environBrokerSuite) TestStartInstanceDefaultNIC(c *gc.C) {
 	exp := svr.EXPECT()
 	gomock.InOrder(
 		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
 		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
 		exp.ServerVersion().Return("3.10.0"),
 		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
@@ -129,6 +131,7 @@ func (s *environBrokerSuite) TestStartInstanceNonDefaultNIC(c *gc.C) {
 	exp := svr.EXPECT()
 	gomock.InOrder(
 		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
 		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
 		exp.ServerVersion().Return("3.10.0"),
 		exp.GetNICsFromProfile("default").Return(nics, nil),
@@ -271,6 +274,161 @@ func (s *environBrokerSuite) TestStartInstanceWithPlacementBadArgument(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "unknown placement directive.*")
 }
 
+func (s *environBrokerSuite) TestStartInstanceClusteredSelectsMember(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	target := lxdtesting.NewMockContainerServer(ctrl)
+	tExp := target.EXPECT()
+	image := &api.Image{Filename: "container-image"}
+
+	tExp.GetServer().Return(&api.Server{}, lxdtesting.ETag, nil)
+	tExp.GetImageAlias("juju/bionic/amd64").Return(&api.ImageAliasesEntry{}, lxdtesting.ETag, nil)
+	tExp.GetImage("").Return(image, lxdtesting.ETag, nil)
+
+	jujuTarget, err := containerlxd.NewServer(target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	capacities := []containerlxd.ClusterMemberCapacity{{
+		Name:               "node01",
+		CPUCores:           4,
+		MemoryMiB:          4096,
+		AllocatedMemoryMiB: 2048,
+	}, {
+		Name:               "node02",
+		CPUCores:           4,
+		MemoryMiB:          16384,
+		AllocatedMemoryMiB: 2048,
+	}}
+
+	createOp := lxdtesting.NewMockRemoteOperation(ctrl)
+	createOp.EXPECT().Wait().Return(nil)
+	createOp.EXPECT().GetTarget().Return(&api.Operation{StatusCode: api.Success}, nil)
+
+	startOp := lxdtesting.NewMockOperation(ctrl)
+	startOp.EXPECT().Wait().Return(nil)
+
+	sExp := svr.EXPECT()
+	gomock.InOrder(
+		sExp.HostArch().Return(arch.AMD64),
+		sExp.IsClustered().Return(true),
+		sExp.ClusterMemberCapacities().Return(capacities, nil),
+		sExp.UseTargetServer("node02").Return(jujuTarget, nil),
+		sExp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
+		sExp.HostArch().Return(arch.AMD64),
+	)
+
+	tExp.CreateContainerFromImage(gomock.Any(), gomock.Any(), gomock.Any()).Return(createOp, nil)
+	tExp.UpdateContainerState(gomock.Any(), gomock.Any(), "").Return(startOp, nil)
+	tExp.GetContainer(gomock.Any()).Return(&api.Container{Location: "node02"}, lxdtesting.ETag, nil)
+
+	env := s.NewEnviron(c, svr, nil)
+	result, err := env.StartInstance(s.callCtx, s.GetStartInstanceArgs(c, "bionic"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.AvailabilityZone, gc.NotNil)
+	c.Check(*result.Hardware.AvailabilityZone, gc.Equals, "node02")
+}
+
+func (s *environBrokerSuite) TestStartInstanceClusteredNoCapacity(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	capacities := []containerlxd.ClusterMemberCapacity{{
+		Name:      "node01",
+		CPUCores:  4,
+		MemoryMiB: 4096,
+	}}
+
+	sExp := svr.EXPECT()
+	gomock.InOrder(
+		sExp.HostArch().Return(arch.AMD64),
+		sExp.IsClustered().Return(true),
+		sExp.ClusterMemberCapacities().Return(capacities, nil),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("mem=8G")
+
+	env := s.NewEnviron(c, svr, nil)
+	_, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `no cluster member has capacity for constraints "mem=8192M"`)
+	c.Check(environs.IsAvailabilityZoneIndependent(err), jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestSelectClusterMember(c *gc.C) {
+	capacities := []containerlxd.ClusterMemberCapacity{{
+		Name:              "node01",
+		CPUCores:          8,
+		MemoryMiB:         8192,
+		AllocatedCPUCores: 6,
+	}, {
+		Name:               "node02",
+		CPUCores:           4,
+		MemoryMiB:          4096,
+		AllocatedMemoryMiB: 1024,
+	}, {
+		Name:      "node03",
+		CPUCores:  4,
+		MemoryMiB: 4096,
+	}}
+
+	for i, test := range []struct {
+		cons     string
+		zone     string
+		expected string
+		err      string
+	}{{
+		expected: "node01",
+	}, {
+		cons:     "cores=3",
+		expected: "node03",
+	}, {
+		cons:     "mem=4G",
+		expected: "node01",
+	}, {
+		cons:     "zones=node02,node03",
+		expected: "node03",
+	}, {
+		cons:     "zones=node02",
+		expected: "node02",
+	}, {
+		cons: "cores=4 mem=8G",
+		err:  `no cluster member has capacity for constraints "cores=4 mem=8192M"`,
+	}, {
+		cons: "zones=node04",
+		err:  `no cluster member has capacity for constraints "zones=node04"`,
+	}, {
+		zone:     "node02",
+		expected: "node02",
+	}, {
+		cons:     "mem=2G",
+		zone:     "node03",
+		expected: "node03",
+	}, {
+		cons: "mem=4G",
+		zone: "node02",
+		err:  `cluster member "node02" has no capacity for constraints "mem=4096M"`,
+	}, {
+		cons: "zones=node01",
+		zone: "node02",
+		err:  `cluster member "node02" has no capacity for constraints "zones=node01"`,
+	}} {
+		c.Logf("test %d: %q in zone %q", i, test.cons, test.zone)
+		name, err := lxd.SelectClusterMember(capacities, constraints.MustParse(test.cons), test.zone)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			// Only a lack of capacity in the chosen zone
+			// leaves other zones to be tried.
+			c.Check(environs.IsAvailabilityZoneIndependent(err), gc.Equals, test.zone == "")
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(name, gc.Equals, test.expected)
+	}
+}
+
 func (s *environBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -291,6 +449,7 @@ func (s *environBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
 	exp := svr.EXPECT()
 	gomock.InOrder(
 		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
 		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
 		exp.ServerVersion().Return("3.10.0"),
 		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
@@ -313,6 +472,37 @@ func (s *environBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *environBrokerSuite) TestStartInstanceWithImageName(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	image := containerlxd.SourcedImage{Image: &api.Image{Fingerprint: "abc123"}}
+	check := func(spec containerlxd.ContainerSpec) bool {
+		return spec.Image.Image.Fingerprint == "abc123"
+	}
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
+		exp.LocalImage("custom").Return(image, nil),
+		exp.ServerVersion().Return("3.10.0"),
+		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
+		exp.CreateContainerFromSpec(matchesContainerSpec(check)).Return(&containerlxd.Container{}, nil),
+		exp.HostArch().Return(arch.AMD64),
+	)
+
+	args := s.GetStartInstanceArgs(c, "bionic")
+	args.Constraints = constraints.MustParse("image-name=custom")
+
+	env := s.NewEnviron(c, svr, nil)
+	result, err := env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.Image, gc.NotNil)
+	c.Check(*result.Hardware.Image, gc.Equals, "custom")
+}
+
 func (s *environBrokerSuite) TestStartInstanceWithCharmLXDProfile(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -336,6 +526,7 @@ func (s *environBrokerSuite) TestStartInstanceWithCharmLXDProfile(c *gc.C) {
 	exp := svr.EXPECT()
 	gomock.InOrder(
 		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
 		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
 		exp.ServerVersion().Return("3.10.0"),
 		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
@@ -373,6 +564,7 @@ func (s *environBrokerSuite) TestStartInstanceInvalidCredentials(c *gc.C) {
 	exp := svr.EXPECT()
 	gomock.InOrder(
 		exp.HostArch().Return(arch.AMD64),
+		exp.IsClustered().Return(false),
 		exp.FindImage("bionic", arch.AMD64, gomock.Any(), true, gomock.Any()).Return(containerlxd.SourcedImage{}, nil),
 		exp.ServerVersion().Return("3.10.0"),
 		exp.GetNICsFromProfile("default").Return(s.defaultProfile.Devices, nil),
@@ -23,6 +23,8 @@ var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
 	constraints.Container,
+	constraints.Spot,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -31,6 +33,7 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 	validator := constraints.NewValidator()
 
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterConflicts([]string{constraints.ImageID}, []string{constraints.ImageName})
 	validator.RegisterVocabulary(constraints.Arch, []string{env.server().HostArch()})
 
 	return validator, nil
@@ -16,6 +16,7 @@ var (
 	NewInstance           = newInstance
 	GetCertificates       = getCertificates
 	IsSupportedAPIVersion = isSupportedAPIVersion
+	SelectClusterMember   = selectClusterMember
 )
 
 func NewProviderWithMocks(
@@ -53,6 +53,21 @@ func (mr *MockServerMockRecorder) AliveContainers(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AliveContainers", reflect.TypeOf((*MockServer)(nil).AliveContainers), arg0)
 }
 
+// ClusterMemberCapacities mocks base method
+func (m *MockServer) ClusterMemberCapacities() ([]lxd.ClusterMemberCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterMemberCapacities")
+	ret0, _ := ret[0].([]lxd.ClusterMemberCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClusterMemberCapacities indicates an expected call of ClusterMemberCapacities
+func (mr *MockServerMockRecorder) ClusterMemberCapacities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterMemberCapacities", reflect.TypeOf((*MockServer)(nil).ClusterMemberCapacities))
+}
+
 // ContainerAddresses mocks base method
 func (m *MockServer) ContainerAddresses(arg0 string) ([]network.ProviderAddress, error) {
 	m.ctrl.T.Helper()
@@ -561,6 +576,21 @@ func (mr *MockServerMockRecorder) LocalBridgeName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalBridgeName", reflect.TypeOf((*MockServer)(nil).LocalBridgeName))
 }
 
+// LocalImage mocks base method
+func (m *MockServer) LocalImage(arg0 string) (lxd.SourcedImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LocalImage", arg0)
+	ret0, _ := ret[0].(lxd.SourcedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LocalImage indicates an expected call of LocalImage
+func (mr *MockServerMockRecorder) LocalImage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalImage", reflect.TypeOf((*MockServer)(nil).LocalImage), arg0)
+}
+
 // Name mocks base method
 func (m *MockServer) Name() string {
 	m.ctrl.T.Helper()
@@ -463,6 +463,15 @@ func (conn *StubClient) FindImage(
 	return lxd.SourcedImage{}, nil
 }
 
+func (conn *StubClient) LocalImage(ref string) (lxd.SourcedImage, error) {
+	conn.AddCall("LocalImage", ref)
+	if err := conn.NextErr(); err != nil {
+		return lxd.SourcedImage{}, errors.Trace(err)
+	}
+
+	return lxd.SourcedImage{}, nil
+}
+
 func (conn *StubClient) CreateCertificate(cert api.CertificatesPost) error {
 	conn.AddCall("CreateCertificate", cert)
 	return conn.NextErr()
@@ -737,6 +746,11 @@ func (conn *StubClient) GetClusterMembers() (members []api.ClusterMember, err er
 	return nil, conn.NextErr()
 }
 
+func (conn *StubClient) ClusterMemberCapacities() ([]lxd.ClusterMemberCapacity, error) {
+	conn.AddCall("ClusterMemberCapacities")
+	return nil, conn.NextErr()
+}
+
 type MockClock struct {
 	clock.Clock
 	now time.Time
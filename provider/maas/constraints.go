@@ -21,6 +21,10 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.ImageID,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -332,6 +332,10 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.ImageID,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -332,6 +332,10 @@ func (e *Environ) ConstraintsValidator(ctx envcontext.ProviderCallContext) (cons
 		constraints.Container,
 		constraints.VirtType,
 		constraints.Tags,
+		constraints.Spot,
+		constraints.MaxPrice,
+		constraints.ImageID,
+		constraints.ImageName,
 	}
 
 	validator := constraints.NewValidator()
@@ -37,6 +37,7 @@ func InstanceFloatingIP(inst instances.Instance) *string {
 var (
 	NovaListAvailabilityZones = &novaListAvailabilityZones
 	NewOpenstackStorage       = &newOpenstackStorage
+	GlanceListImagesDetail    = &glanceListImagesDetail
 )
 
 func NewCinderVolumeSource(s OpenstackStorage, env common.ZonedEnviron) storage.VolumeSource {
@@ -4,8 +4,11 @@
 package openstack
 
 import (
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/glance"
 	"gopkg.in/goose.v2/nova"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 )
@@ -93,3 +96,54 @@ func findInstanceSpec(
 	}
 	return spec, nil
 }
+
+var glanceListImagesDetail = (*glance.Client).ListImagesDetail
+
+// customImageMetadata returns image metadata for the image named by the
+// image-id or image-name constraint, so that the image is used without
+// consulting simplestreams. Images that do not record their architecture
+// are assumed to support all of the given arches.
+func customImageMetadata(
+	e *Environ,
+	cons constraints.Value,
+	region string,
+	arches []string,
+) ([]*imagemetadata.ImageMetadata, error) {
+	images, err := glanceListImagesDetail(glance.New(e.client()))
+	if err != nil {
+		return nil, errors.Annotate(err, "listing images")
+	}
+	var matches []glance.ImageDetail
+	for _, image := range images {
+		if cons.HasImageID() && image.Id == *cons.ImageID ||
+			cons.HasImageName() && image.Name == *cons.ImageName {
+			matches = append(matches, image)
+		}
+	}
+	var what string
+	if cons.HasImageID() {
+		what = *cons.ImageID
+	} else {
+		what = *cons.ImageName
+	}
+	switch len(matches) {
+	case 0:
+		return nil, errors.NotFoundf("image %q", what)
+	case 1:
+	default:
+		return nil, errors.Errorf("image name %q is ambiguous, use the image-id constraint", what)
+	}
+	image := matches[0]
+	if image.Metadata.Architecture != "" {
+		arches = []string{image.Metadata.Architecture}
+	}
+	metadata := make([]*imagemetadata.ImageMetadata, len(arches))
+	for i, arch := range arches {
+		metadata[i] = &imagemetadata.ImageMetadata{
+			Id:         image.Id,
+			Arch:       arch,
+			RegionName: region,
+		}
+	}
+	return metadata, nil
+}
@@ -30,6 +30,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/goose.v2/cinder"
 	"gopkg.in/goose.v2/client"
+	"gopkg.in/goose.v2/glance"
 	"gopkg.in/goose.v2/identity"
 	"gopkg.in/goose.v2/neutron"
 	"gopkg.in/goose.v2/nova"
@@ -504,6 +505,28 @@ func (s *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	c.Assert(hc.CpuPower, gc.IsNil)
 }
 
+func (s *localServerSuite) TestStartInstanceImageName(c *gc.C) {
+	s.PatchValue(openstack.GlanceListImagesDetail, func(*glance.Client) ([]glance.ImageDetail, error) {
+		return []glance.ImageDetail{
+			{Id: "1", Name: "other"},
+			{Id: "2", Name: "custom", Metadata: glance.ImageMetadata{Architecture: "amd64"}},
+		}, nil
+	})
+	inst, hc := testing.AssertStartInstanceWithConstraints(c, s.env, s.callCtx, s.ControllerUUID, "100", constraints.MustParse("image-name=custom"))
+	c.Check(openstack.InstanceServerDetail(inst).Image.Id, gc.Equals, "2")
+	c.Check(*hc.Arch, gc.Equals, "amd64")
+	c.Assert(hc.Image, gc.NotNil)
+	c.Check(*hc.Image, gc.Equals, "custom")
+}
+
+func (s *localServerSuite) TestStartInstanceImageNameNotFound(c *gc.C) {
+	s.PatchValue(openstack.GlanceListImagesDetail, func(*glance.Client) ([]glance.ImageDetail, error) {
+		return []glance.ImageDetail{{Id: "1", Name: "other"}}, nil
+	})
+	_, _, _, err := testing.StartInstanceWithConstraints(s.env, s.callCtx, s.ControllerUUID, "100", constraints.MustParse("image-name=custom"))
+	c.Assert(err, gc.ErrorMatches, `image "custom" not found`)
+}
+
 func (s *localServerSuite) TestInstanceName(c *gc.C) {
 	inst, _ := testing.AssertStartInstance(c, s.env, s.callCtx, s.ControllerUUID, "100")
 	serverDetail := openstack.InstanceServerDetail(inst)
@@ -572,6 +572,8 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Spot,
+	constraints.MaxPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -580,6 +582,9 @@ func (e *Environ) ConstraintsValidator(ctx context.ProviderCallContext) (constra
 	validator.RegisterConflicts(
 		[]string{constraints.InstanceType},
 		[]string{constraints.Mem, constraints.Cores})
+	validator.RegisterConflicts(
+		[]string{constraints.ImageID},
+		[]string{constraints.ImageName})
 	// NOTE: RootDiskSource and RootDisk constraints are validated in PrecheckInstance.
 	validator.RegisterUnsupported(unsupportedConstraints)
 	novaClient := e.nova()
@@ -1082,12 +1087,19 @@ func (e *Environ) startInstance(
 
 	series := args.Tools.OneSeries()
 	arches := args.Tools.Arches()
+	imageMetadata := args.ImageMetadata
+	if args.Constraints.HasImage() {
+		imageMetadata, err = customImageMetadata(e, args.Constraints, e.cloud().Region, arches)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+	}
 	spec, err := findInstanceSpec(e, instances.InstanceConstraint{
 		Region:      e.cloud().Region,
 		Series:      series,
 		Arches:      arches,
 		Constraints: args.Constraints,
-	}, args.ImageMetadata)
+	}, imageMetadata)
 	if err != nil {
 		return nil, common.ZoneIndependentError(err)
 	}
@@ -1332,9 +1344,15 @@ func (e *Environ) startInstance(
 		inst.floatingIP = publicIP
 	}
 
+	hc := inst.hardwareCharacteristics()
+	if args.Constraints.HasImageID() {
+		hc.Image = args.Constraints.ImageID
+	} else if args.Constraints.HasImageName() {
+		hc.Image = args.Constraints.ImageName
+	}
 	return &environs.StartInstanceResult{
 		Instance: inst,
-		Hardware: inst.hardwareCharacteristics(),
+		Hardware: hc,
 	}, nil
 }
 
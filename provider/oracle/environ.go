@@ -662,6 +662,10 @@ func (o *OracleEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (c
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.Spot,
+		constraints.MaxPrice,
+		constraints.ImageID,
+		constraints.ImageName,
 	}
 
 	// we choose to use the default validator implementation
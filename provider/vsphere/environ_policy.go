@@ -76,6 +76,10 @@ func (env *sessionEnviron) checkDatastore(ctx context.ProviderCallContext, datas
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.MaxPrice,
+	constraints.ImageID,
+	constraints.ImageName,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage"
 )
 
@@ -84,6 +85,12 @@ type MachineTemplate struct {
 	// with the machine.
 	Placement string
 
+	// CloudInitUserData holds cloud-init user data YAML that is merged
+	// into the user data of the machine's instance when it is
+	// provisioned. It must be valid according to
+	// config.ParseCloudInitUserData.
+	CloudInitUserData string
+
 	// principals holds the principal units that will
 	// associated with the machine.
 	principals []string
@@ -225,6 +232,11 @@ func (st *State) effectiveMachineTemplate(p MachineTemplate, allowController boo
 	} else if p.Nonce != "" {
 		return tmpl, errors.New("cannot specify a nonce without an instance id")
 	}
+	if p.CloudInitUserData != "" {
+		if _, err := config.ParseCloudInitUserData(p.CloudInitUserData); err != nil {
+			return tmpl, errors.Annotate(err, "cloudinit-userdata")
+		}
+	}
 
 	// We ignore all constraints if there's a placement directive.
 	if p.Placement == "" {
@@ -303,6 +315,7 @@ func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op,
 				CpuPower:       template.HardwareCharacteristics.CpuPower,
 				Tags:           template.HardwareCharacteristics.Tags,
 				AvailZone:      template.HardwareCharacteristics.AvailabilityZone,
+				Image:          template.HardwareCharacteristics.Image,
 			},
 		})
 	}
@@ -513,6 +526,7 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		PreferredPrivateAddress: fromNetworkAddress(privateAddr, network.OriginMachine),
 		PreferredPublicAddress:  fromNetworkAddress(publicAddr, network.OriginMachine),
 		Placement:               template.Placement,
+		CloudInitUserData:       template.CloudInitUserData,
 	}
 }
 
@@ -111,6 +111,19 @@ func allCollections() CollectionSchema {
 		// This collection holds Juju GUI current version and other settings.
 		guisettingsC: {global: true},
 
+		// This collection holds the revisions of local charms published
+		// to the controller's charm repository, with their resources.
+		repositoryCharmsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"name", "series", "revision"},
+			}},
+		},
+
+		// This collection records the revisions of local charms released
+		// to the channels of the controller's charm repository.
+		localCharmReleasesC: {global: true},
+
 		// This collection holds model information; in particular its
 		// Life and its UUID.
 		modelsC: {global: true},
@@ -140,12 +153,50 @@ func allCollections() CollectionSchema {
 		// migration minions.
 		migrationsMinionSyncC: {global: true},
 
+		// This collection holds requests to migrate a number of models
+		// to another controller, a few at a time.
+		bulkMigrationsC: {global: true},
+
 		// This collection holds user information that's not specific to any
 		// one model.
 		usersC: {
 			global: true,
 		},
 
+		// This collection holds groups of users, which may be granted
+		// access to controllers, clouds, models and offers.
+		groupsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"members"},
+			}},
+		},
+
+		// This collection holds custom roles, which restrict the API
+		// calls that users can make.
+		rolesC: {global: true},
+
+		// This collection holds the roles assigned to users on
+		// models and controllers.
+		roleAssignmentsC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"role"},
+			}},
+		},
+
+		// This collection holds the API tokens issued to service
+		// accounts, which grant access to the models they are
+		// scoped to until they expire.
+		apiTokensC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"account"},
+			}, {
+				Key: []string{"models"},
+			}},
+		},
+
 		// This collection holds users that are relative to controllers.
 		controllerUsersC: {
 			global: true,
@@ -332,6 +383,10 @@ func allCollections() CollectionSchema {
 		// that needs to be cleaned up in the provider.
 		machineRemovalsC: {},
 
+		// This collection holds the named groups of machines that the
+		// provisioner keeps at a target size.
+		machinePoolsC: {},
+
 		// this collection contains machine update locks whose existence indicates
 		// that a particular machine in the process of performing a series upgrade.
 		machineUpgradeSeriesLocksC: {
@@ -550,11 +605,13 @@ const (
 	actionresultsC             = "actionresults"
 	actionsC                   = "actions"
 	annotationsC               = "annotations"
+	apiTokensC                 = "apitokens"
 	autocertCacheC             = "autocertCache"
 	assignUnitC                = "assignUnits"
 	bakeryStorageItemsC        = "bakeryStorageItems"
 	blockDevicesC              = "blockdevices"
 	blocksC                    = "blocks"
+	bulkMigrationsC            = "bulkmigrations"
 	charmsC                    = "charms"
 	cleanupsC                  = "cleanups"
 	cloudimagemetadataC        = "cloudimagemetadata"
@@ -574,10 +631,13 @@ const (
 	globalRefcountsC           = "globalRefcounts"
 	globalSettingsC            = "globalSettings"
 	guimetadataC               = "guimetadata"
+	groupsC                    = "groups"
 	guisettingsC               = "guisettings"
 	instanceDataC              = "instanceData"
 	leaseHoldersC              = "leaseholders"
+	localCharmReleasesC        = "localCharmReleases"
 	machinesC                  = "machines"
+	machinePoolsC              = "machinepools"
 	machineRemovalsC           = "machineremovals"
 	machineUpgradeSeriesLocksC = "machineUpgradeSeriesLocks"
 	meterStatusC               = "meterStatus"
@@ -601,7 +661,10 @@ const (
 	rebootC                    = "reboot"
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
+	repositoryCharmsC          = "repositoryCharms"
 	restoreInfoC               = "restoreInfo"
+	roleAssignmentsC           = "roleassignments"
+	rolesC                     = "roles"
 	sequenceC                  = "sequence"
 	applicationsC              = "applications"
 	endpointBindingsC          = "endpointbindings"
@@ -0,0 +1,355 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/permission"
+)
+
+// ServiceAccountDomain is the user domain of service accounts. A
+// service account has no password; it logs in with one of the API
+// tokens issued to it, and has access only to the models its live
+// tokens are scoped to.
+const ServiceAccountDomain = "serviceaccount"
+
+// apiTokenPrefix starts every API token, so that tokens can be told
+// apart from other credentials presented at login.
+const apiTokenPrefix = "jujutoken"
+
+// ServiceAccountUserTag returns the user tag of the named service
+// account.
+func ServiceAccountUserTag(name string) names.UserTag {
+	return names.NewUserTag(name).WithDomain(ServiceAccountDomain)
+}
+
+// IsServiceAccount reports whether the user is a service account.
+func IsServiceAccount(user names.UserTag) bool {
+	return user.Domain() == ServiceAccountDomain
+}
+
+// IsAPIToken reports whether value has the form of an API token.
+func IsAPIToken(value string) bool {
+	return strings.HasPrefix(value, apiTokenPrefix+".")
+}
+
+// ParseAPIToken splits an API token into its ID and secret.
+func ParseAPIToken(value string) (id, secret string, err error) {
+	parts := strings.SplitN(value, ".", 3)
+	if len(parts) != 3 || parts[0] != apiTokenPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", errors.NotValidf("API token")
+	}
+	return parts[1], parts[2], nil
+}
+
+// apiTokenDoc represents an API token issued to a service account.
+// Only a hash of the token's secret is stored.
+type apiTokenDoc struct {
+	DocID       string    `bson:"_id"`
+	Account     string    `bson:"account"`
+	SecretHash  string    `bson:"secrethash"`
+	Models      []string  `bson:"models"`
+	Access      string    `bson:"access"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+	Expires     time.Time `bson:"expires"`
+}
+
+// APIToken represents an API token issued to a service account.
+type APIToken struct {
+	st  *State
+	doc apiTokenDoc
+}
+
+// Id returns the ID of the token.
+func (t *APIToken) Id() string {
+	return t.doc.DocID
+}
+
+// Account returns the name of the service account the token was
+// issued to.
+func (t *APIToken) Account() string {
+	return t.doc.Account
+}
+
+// UserTag returns the tag of the service account the token was
+// issued to.
+func (t *APIToken) UserTag() names.UserTag {
+	return ServiceAccountUserTag(t.doc.Account)
+}
+
+// ModelUUIDs returns the UUIDs of the models the token is scoped to.
+func (t *APIToken) ModelUUIDs() []string {
+	return append([]string(nil), t.doc.Models...)
+}
+
+// Access returns the access the token grants on its models.
+func (t *APIToken) Access() permission.Access {
+	return permission.Access(t.doc.Access)
+}
+
+// CreatedBy returns the name of the user that issued the token.
+func (t *APIToken) CreatedBy() string {
+	return t.doc.CreatedBy
+}
+
+// DateCreated returns when the token was issued in UTC.
+func (t *APIToken) DateCreated() time.Time {
+	return t.doc.DateCreated.UTC()
+}
+
+// Expires returns when the token expires in UTC.
+func (t *APIToken) Expires() time.Time {
+	return t.doc.Expires.UTC()
+}
+
+// Expired reports whether the token has expired at the given time.
+func (t *APIToken) Expired(now time.Time) bool {
+	return !now.Before(t.doc.Expires)
+}
+
+// SecretValid reports whether the secret matches the token's secret.
+func (t *APIToken) SecretValid(secret string) bool {
+	hash := utils.AgentPasswordHash(secret)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(t.doc.SecretHash)) == 1
+}
+
+// Allows reports whether a connection authenticated with the token
+// may perform the operation on the target. The token only allows
+// logging in to the controller, and access up to its own level on
+// the models it is scoped to.
+func (t *APIToken) Allows(operation permission.Access, target names.Tag) bool {
+	switch target.Kind() {
+	case names.ControllerTagKind:
+		return operation == permission.LoginAccess
+	case names.ModelTagKind:
+		if !t.hasModel(target.Id()) {
+			return false
+		}
+		return !operation.GreaterModelAccessThan(t.Access())
+	}
+	return false
+}
+
+func (t *APIToken) hasModel(modelUUID string) bool {
+	for _, uuid := range t.doc.Models {
+		if uuid == modelUUID {
+			return true
+		}
+	}
+	return false
+}
+
+// APITokenArgs holds the parameters for issuing an API token.
+type APITokenArgs struct {
+	// Account is the name of the service account the token is
+	// issued to. The account comes into being with its first token.
+	Account string
+
+	// Models holds the models the token is scoped to.
+	Models []names.ModelTag
+
+	// Access is the access the token grants on its models.
+	Access permission.Access
+
+	// Expires is when the token expires.
+	Expires time.Time
+
+	// CreatedBy is the user issuing the token.
+	CreatedBy names.UserTag
+}
+
+// Validate returns an error if the arguments are not valid.
+func (args APITokenArgs) Validate() error {
+	if !names.IsValidUserName(args.Account) {
+		return errors.NotValidf("service account name %q", args.Account)
+	}
+	if len(args.Models) == 0 {
+		return errors.NotValidf("API token without models")
+	}
+	if err := permission.ValidateModelAccess(args.Access); err != nil {
+		return errors.Trace(err)
+	}
+	if args.Expires.IsZero() {
+		return errors.NotValidf("API token without expiry")
+	}
+	return nil
+}
+
+// AddAPIToken issues a new API token to a service account. It
+// returns the token along with its value, which is the only time the
+// value is available; the controller keeps only a hash of the
+// token's secret.
+func (st *State) AddAPIToken(args APITokenArgs) (*APIToken, string, error) {
+	if err := args.Validate(); err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	now := st.nowToTheSecond()
+	if !args.Expires.After(now) {
+		return nil, "", errors.NotValidf("API token expiry %s in the past", args.Expires.Format(time.RFC3339))
+	}
+	idBytes, err := utils.RandomBytes(8)
+	if err != nil {
+		return nil, "", errors.Annotate(err, "generating API token ID")
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Annotate(err, "generating API token secret")
+	}
+	token := &APIToken{
+		st: st,
+		doc: apiTokenDoc{
+			DocID:       hex.EncodeToString(idBytes),
+			Account:     args.Account,
+			SecretHash:  utils.AgentPasswordHash(secret),
+			Access:      string(args.Access),
+			CreatedBy:   args.CreatedBy.Id(),
+			DateCreated: now,
+			Expires:     args.Expires.UTC(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     token.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	for _, model := range args.Models {
+		token.doc.Models = append(token.doc.Models, model.Id())
+		ops = append(ops, txn.Op{
+			C:      modelsC,
+			Id:     model.Id(),
+			Assert: isAliveDoc,
+		})
+	}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, "", errors.Errorf("cannot add API token: not all models are alive")
+	} else if err != nil {
+		return nil, "", errors.Annotate(err, "cannot add API token")
+	}
+	value := strings.Join([]string{apiTokenPrefix, token.doc.DocID, secret}, ".")
+	return token, value, nil
+}
+
+// APIToken returns the API token with the given ID.
+func (st *State) APIToken(id string) (*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var doc apiTokenDoc
+	err := tokens.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API token %q", id)
+	}
+	return &APIToken{st: st, doc: doc}, nil
+}
+
+// APITokens returns the API tokens issued to the named service
+// account, or all API tokens if no account is given, ordered by
+// account and expiry.
+func (st *State) APITokens(account string) ([]*APIToken, error) {
+	var query bson.D
+	if account != "" {
+		query = bson.D{{"account", account}}
+	}
+	return st.apiTokens(query)
+}
+
+func (st *State) apiTokens(query bson.D) ([]*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	if err := tokens.Find(query).Sort("account", "expires").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get API tokens")
+	}
+	result := make([]*APIToken, len(docs))
+	for i, doc := range docs {
+		result[i] = &APIToken{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RevokeAPIToken removes the API token with the given ID. Connections
+// already authenticated with the token are not affected.
+func (st *State) RevokeAPIToken(id string) error {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("API token %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// liveAPITokens returns the unexpired API tokens issued to the
+// service account.
+func (st *State) liveAPITokens(user names.UserTag) ([]*APIToken, error) {
+	if !IsServiceAccount(user) {
+		return nil, nil
+	}
+	return st.apiTokens(bson.D{
+		{"account", user.Name()},
+		{"expires", bson.D{{"$gt", st.clock().Now()}}},
+	})
+}
+
+// ServiceAccountPermission returns the highest access on the target
+// granted to the service account by any of its unexpired API tokens.
+// A service account with a live token can log in to the controller.
+// For users that are not service accounts, NoAccess is returned.
+func (st *State) ServiceAccountPermission(user names.UserTag, target names.Tag) (permission.Access, error) {
+	tokens, err := st.liveAPITokens(user)
+	if err != nil || len(tokens) == 0 {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	result := permission.NoAccess
+	for _, token := range tokens {
+		access := permission.NoAccess
+		switch target.Kind() {
+		case names.ControllerTagKind:
+			access = permission.LoginAccess
+		case names.ModelTagKind:
+			if token.hasModel(target.Id()) {
+				access = token.Access()
+			}
+		}
+		if greaterAccessForTarget(target, access, result) {
+			result = access
+		}
+	}
+	return result, nil
+}
+
+// serviceAccountModelUUIDs returns the UUIDs of the models the
+// service account has access to through its unexpired API tokens.
+func (st *State) serviceAccountModelUUIDs(user names.UserTag) ([]string, error) {
+	tokens, err := st.liveAPITokens(user)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []string
+	for _, token := range tokens {
+		result = append(result, token.doc.Models...)
+	}
+	return result, nil
+}
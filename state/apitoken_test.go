@@ -0,0 +1,125 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+type APITokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APITokenSuite{})
+
+func (s *APITokenSuite) addToken(c *gc.C, access permission.Access, ttl time.Duration) (*state.APIToken, string) {
+	token, value, err := s.State.AddAPIToken(state.APITokenArgs{
+		Account:   "ci",
+		Models:    []names.ModelTag{s.Model.ModelTag()},
+		Access:    access,
+		Expires:   s.Clock.Now().Add(ttl),
+		CreatedBy: s.Owner,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token, value
+}
+
+func (s *APITokenSuite) TestAddAPIToken(c *gc.C) {
+	token, value := s.addToken(c, permission.WriteAccess, time.Hour)
+	c.Assert(state.IsAPIToken(value), jc.IsTrue)
+	id, secret, err := state.ParseAPIToken(value)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, token.Id())
+	c.Assert(token.SecretValid(secret), jc.IsTrue)
+	c.Assert(token.SecretValid("wrong"), jc.IsFalse)
+	c.Assert(token.UserTag(), gc.Equals, names.NewUserTag("ci@serviceaccount"))
+	c.Assert(token.ModelUUIDs(), jc.DeepEquals, []string{s.Model.UUID()})
+	c.Assert(token.Access(), gc.Equals, permission.WriteAccess)
+	c.Assert(token.CreatedBy(), gc.Equals, s.Owner.Id())
+
+	stored, err := s.State.APIToken(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored.SecretValid(secret), jc.IsTrue)
+}
+
+func (s *APITokenSuite) TestAddAPITokenInvalid(c *gc.C) {
+	args := state.APITokenArgs{
+		Account:   "ci",
+		Models:    []names.ModelTag{s.Model.ModelTag()},
+		Access:    permission.SuperuserAccess,
+		Expires:   s.Clock.Now().Add(time.Hour),
+		CreatedBy: s.Owner,
+	}
+	_, _, err := s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `"superuser" model access not valid`)
+
+	args.Access = permission.ReadAccess
+	args.Expires = s.Clock.Now().Add(-time.Hour)
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `API token expiry .* in the past not valid`)
+
+	args.Expires = s.Clock.Now().Add(time.Hour)
+	args.Models = []names.ModelTag{names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")}
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, "cannot add API token: not all models are alive")
+}
+
+func (s *APITokenSuite) TestServiceAccountPermission(c *gc.C) {
+	account := state.ServiceAccountUserTag("ci")
+	modelTag := s.Model.ModelTag()
+	access, err := s.State.UserPermission(account, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.addToken(c, permission.ReadAccess, 2*time.Hour)
+	s.addToken(c, permission.WriteAccess, time.Hour)
+	access, err = s.State.UserPermission(account, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+	access, err = s.State.ServiceAccountPermission(account, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+
+	// Expired tokens give no access.
+	s.Clock.Advance(90 * time.Minute)
+	access, err = s.State.UserPermission(account, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ReadAccess)
+
+	models, err := s.State.ModelUUIDsForUser(account)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, []string{s.Model.UUID()})
+}
+
+func (s *APITokenSuite) TestAllows(c *gc.C) {
+	token, _ := s.addToken(c, permission.WriteAccess, time.Hour)
+	modelTag := s.Model.ModelTag()
+	c.Assert(token.Allows(permission.WriteAccess, modelTag), jc.IsTrue)
+	c.Assert(token.Allows(permission.AdminAccess, modelTag), jc.IsFalse)
+	c.Assert(token.Allows(permission.ReadAccess, names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")), jc.IsFalse)
+	c.Assert(token.Allows(permission.LoginAccess, s.State.ControllerTag()), jc.IsTrue)
+	c.Assert(token.Allows(permission.SuperuserAccess, s.State.ControllerTag()), jc.IsFalse)
+}
+
+func (s *APITokenSuite) TestRevokeAPIToken(c *gc.C) {
+	token, _ := s.addToken(c, permission.ReadAccess, time.Hour)
+	tokens, err := s.State.APITokens("ci")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+
+	err = s.State.RevokeAPIToken(token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RevokeAPIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	tokens, err = s.State.APITokens("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
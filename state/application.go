@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -63,6 +64,10 @@ type applicationDoc struct {
 	TxnRevno             int64        `bson:"txn-revno"`
 	MetricCredentials    []byte       `bson:"metric-credentials"`
 
+	// ExposedEndpoints holds the expose settings of the application's
+	// endpoints when it is exposed. See ExposedEndpoints.
+	ExposedEndpoints map[string]ExposedEndpoint `bson:"exposed-endpoints,omitempty"`
+
 	// CAAS related attributes.
 	DesiredScale int    `bson:"scale"`
 	PasswordHash string `bson:"passwordhash"`
@@ -72,6 +77,27 @@ type applicationDoc struct {
 	// and any k8s cluster resources have been fully cleaned up.
 	// Until then, the application must not be removed from the Juju model.
 	HasResources bool `bson:"has-resources,omitempty"`
+
+	// PlacementPolicy holds the rules for placing the application's
+	// units on machines. See PlacementPolicy.
+	PlacementPolicy *placementPolicyDoc `bson:"placement-policy,omitempty"`
+
+	// CharmOrigin records where the charm was obtained from when the
+	// charm URL doesn't tell. See CharmOrigin.
+	CharmOrigin *CharmOrigin `bson:"charm-origin,omitempty"`
+}
+
+// ExposedEndpoint holds the expose settings of an application endpoint,
+// listing the sources allowed to access the ports opened by the
+// application's units. An endpoint without any sources is accessible
+// from everywhere.
+type ExposedEndpoint struct {
+	// ExposeToSpaceIDs lists the IDs of the spaces whose subnets
+	// are allowed access.
+	ExposeToSpaceIDs []string `bson:"to-space-ids,omitempty"`
+
+	// ExposeToCIDRs lists the CIDRs allowed access.
+	ExposeToCIDRs []string `bson:"to-cidrs,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -641,23 +667,114 @@ func (a *Application) SetExposed() error {
 	return a.setExposed(true)
 }
 
-// ClearExposed removes the exposed flag from the application.
+// ClearExposed removes the exposed flag and any per-endpoint expose
+// settings from the application.
 // See SetExposed and IsExposed.
 func (a *Application) ClearExposed() error {
 	return a.setExposed(false)
 }
 
 func (a *Application) setExposed(exposed bool) (err error) {
+	update := bson.D{{"$set", bson.D{{"exposed", exposed}}}}
+	if !exposed {
+		update = append(update, bson.DocElem{"$unset", bson.D{{"exposed-endpoints", nil}}})
+	}
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := a.st.db().RunTransaction(ops); err != nil {
 		return errors.Errorf("cannot set exposed flag for application %q to %v: %v", a, exposed, onAbort(err, applicationNotAliveErr))
 	}
 	a.doc.Exposed = exposed
+	if !exposed {
+		a.doc.ExposedEndpoints = nil
+	}
+	return nil
+}
+
+// ExposedEndpoints returns the expose settings for the application's
+// endpoints, keyed by endpoint name. An empty endpoint name holds the
+// settings applying to all of the application's endpoints.
+// An exposed application without any settings allows access from
+// everywhere.
+func (a *Application) ExposedEndpoints() map[string]ExposedEndpoint {
+	if len(a.doc.ExposedEndpoints) == 0 {
+		return nil
+	}
+	result := make(map[string]ExposedEndpoint, len(a.doc.ExposedEndpoints))
+	for endpoint, exposed := range a.doc.ExposedEndpoints {
+		result[endpoint] = exposed
+	}
+	return result
+}
+
+// MergeExposeSettings marks the application as exposed and merges the
+// provided expose settings into the existing settings for the application's
+// endpoints. An empty endpoint name may be used to specify the settings
+// applying to all of the application's endpoints.
+func (a *Application) MergeExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, applicationNotAliveErr
+		}
+		if err := a.validateExposeSettings(exposedEndpoints); err != nil {
+			return nil, errors.Trace(err)
+		}
+		merged := a.ExposedEndpoints()
+		if merged == nil {
+			merged = make(map[string]ExposedEndpoint)
+		}
+		for endpoint, exposed := range exposedEndpoints {
+			merged[endpoint] = exposed
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"txn-revno", a.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{
+				{"exposed", true},
+				{"exposed-endpoints", merged},
+			}}},
+		}}, nil
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot merge expose settings for application %q", a)
+	}
+	return a.Refresh()
+}
+
+func (a *Application) validateExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	endpoints, err := a.Endpoints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	known := set.NewStrings("")
+	for _, ep := range endpoints {
+		known.Add(ep.Name)
+	}
+	for endpoint, exposed := range exposedEndpoints {
+		if !known.Contains(endpoint) {
+			return errors.NotValidf("endpoint %q for application %q", endpoint, a.Name())
+		}
+		for _, spaceID := range exposed.ExposeToSpaceIDs {
+			if _, err := a.st.Space(spaceID); err != nil {
+				return errors.Annotatef(err, "validating expose settings for endpoint %q", endpoint)
+			}
+		}
+		for _, cidr := range exposed.ExposeToCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return errors.NotValidf("CIDR %q for endpoint %q", cidr, endpoint)
+			}
+		}
+	}
 	return nil
 }
 
@@ -1246,6 +1363,10 @@ type SetCharmConfig struct {
 	// Channel is the charm store channel from which charm was pulled.
 	Channel csparams.Channel
 
+	// CharmOrigin records where the charm was obtained from, if that
+	// can't be told from its URL. It replaces the application's origin.
+	CharmOrigin *CharmOrigin
+
 	// ConfigSettings is the charm config settings to apply when upgrading
 	// the charm.
 	ConfigSettings charm.Settings
@@ -1350,6 +1471,12 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 		}
 	}
 
+	if cfg.CharmOrigin != nil {
+		if err := cfg.CharmOrigin.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	updatedSettings, err := cfg.Charm.Config().ValidateSettings(cfg.ConfigSettings)
 	if err != nil {
 		return errors.Annotate(err, "validating config settings")
@@ -1437,6 +1564,11 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 			ops = append(ops, chng...)
 			newCharmModifiedVersion++
 		}
+		ops = append(ops, txn.Op{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Update: charmOriginUpdate(cfg.CharmOrigin),
+		})
 
 		// Always update bindings regardless of whether we upgrade to a
 		// new version or stay at the previous version.
@@ -1466,6 +1598,7 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 	}
 	a.doc.CharmURL = cfg.Charm.URL()
 	a.doc.Channel = channel
+	a.doc.CharmOrigin = cfg.CharmOrigin
 	a.doc.ForceCharm = cfg.ForceUnits
 	a.doc.CharmModifiedVersion = newCharmModifiedVersion
 	return nil
@@ -3025,6 +3158,7 @@ func (a *Application) PasswordValid(password string) bool {
 type UnitUpdateProperties struct {
 	ProviderId           *string
 	Address              *string
+	SpaceAddresses       *network.SpaceAddresses
 	Ports                *[]string
 	AgentStatus          *status.StatusInfo
 	UnitStatus           *status.StatusInfo
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/charm/v7"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -108,6 +109,46 @@ func (s *ApplicationSuite) TestSetCharm(c *gc.C) {
 	c.Assert(force, jc.IsTrue)
 }
 
+func (s *ApplicationSuite) TestSetCharmOrigin(c *gc.C) {
+	c.Assert(s.mysql.CharmOrigin(), gc.IsNil)
+
+	origin := &state.CharmOrigin{
+		Source:   state.CharmOriginCharmHub,
+		ID:       "mysql-id",
+		Revision: 7,
+	}
+	err := s.mysql.SetCharm(state.SetCharmConfig{
+		Charm:       s.AddMetaCharm(c, "mysql", metaBase, 2),
+		Channel:     "latest/stable",
+		CharmOrigin: origin,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.CharmOrigin(), jc.DeepEquals, origin)
+	c.Assert(s.mysql.Channel(), gc.Equals, csparams.Channel("latest/stable"))
+
+	app, err := s.State.Application("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.CharmOrigin(), jc.DeepEquals, origin)
+
+	// Switching to a charm without an origin clears it.
+	err = s.mysql.SetCharm(state.SetCharmConfig{
+		Charm: s.AddMetaCharm(c, "mysql", metaBase, 3),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.CharmOrigin(), gc.IsNil)
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.CharmOrigin(), gc.IsNil)
+}
+
+func (s *ApplicationSuite) TestSetCharmOriginInvalid(c *gc.C) {
+	err := s.mysql.SetCharm(state.SetCharmConfig{
+		Charm:       s.AddMetaCharm(c, "mysql", metaBase, 2),
+		CharmOrigin: &state.CharmOrigin{Source: state.CharmOriginCharmHub},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade application "mysql" to charm .*: charm origin without ID not valid`)
+}
+
 func (s *ApplicationSuite) TestLXDProfileSetCharm(c *gc.C) {
 	charm := s.AddTestingCharm(c, "lxd-profile")
 	app := s.AddTestingApplication(c, "lxd-profile", charm)
@@ -2340,6 +2381,57 @@ func (s *ApplicationSuite) TestApplicationExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ApplicationSuite) TestMergeExposeSettings(c *gc.C) {
+	dbSpace, err := s.State.AddSpace("db", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{dbSpace.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := s.State.Application(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsTrue)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"":       {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"server": {ExposeToSpaceIDs: []string{dbSpace.Id()}},
+	})
+
+	// Clearing the exposed flag removes the expose settings.
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), gc.HasLen, 0)
+	err = app.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.IsExposed(), jc.IsFalse)
+	c.Assert(app.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationSuite) TestMergeExposeSettingsInvalid(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"bogus": {},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot merge expose settings for application "mysql": endpoint "bogus" for application "mysql" not valid`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0.0"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `.*CIDR "10.0.0.0" for endpoint "server" not valid`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaceIDs: []string{"42"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `.*space id "42" not found`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	c.Assert(s.mysql.UnitCount(), gc.Equals, 0)
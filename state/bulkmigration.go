@@ -0,0 +1,372 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/migration"
+)
+
+// BulkMigrationSpec holds the information required to create a
+// BulkMigration.
+type BulkMigrationSpec struct {
+	InitiatedBy   names.UserTag
+	ModelUUIDs    []string
+	TargetInfo    migration.TargetInfo
+	MaxConcurrent int
+
+	// PrecheckErrors holds the reasons models failed the migration
+	// prechecks, keyed by model UUID. These models are recorded as
+	// not started, so they aren't migrated.
+	PrecheckErrors map[string]string
+}
+
+// Validate returns an error if the BulkMigrationSpec contains bad
+// data. Nil is returned otherwise.
+func (spec *BulkMigrationSpec) Validate() error {
+	if !names.IsValidUser(spec.InitiatedBy.Id()) {
+		return errors.NotValidf("InitiatedBy")
+	}
+	if len(spec.ModelUUIDs) == 0 {
+		return errors.NotValidf("empty ModelUUIDs")
+	}
+	seen := make(map[string]bool)
+	for _, modelUUID := range spec.ModelUUIDs {
+		if !names.IsValidModel(modelUUID) {
+			return errors.NotValidf("model UUID %q", modelUUID)
+		}
+		if seen[modelUUID] {
+			return errors.NotValidf("duplicate model UUID %q", modelUUID)
+		}
+		seen[modelUUID] = true
+	}
+	for modelUUID := range spec.PrecheckErrors {
+		if !seen[modelUUID] {
+			return errors.NotValidf("precheck error for unknown model UUID %q", modelUUID)
+		}
+	}
+	if spec.MaxConcurrent < 1 {
+		return errors.NotValidf("MaxConcurrent %d", spec.MaxConcurrent)
+	}
+	return spec.TargetInfo.Validate()
+}
+
+// BulkMigration tracks the migration of a number of models to the same
+// target controller, of which at most MaxConcurrent are migrated at the
+// same time.
+type BulkMigration struct {
+	st  *State
+	doc bulkMigrationDoc
+}
+
+// bulkMigrationDoc holds the parameters of a bulk migration. These are
+// written into bulkMigrationsC.
+type bulkMigrationDoc struct {
+	// Id holds the sequence number of the bulk migration.
+	Id string `bson:"_id"`
+
+	// InitiatedBy holds the username of the user that requested the
+	// bulk migration.
+	InitiatedBy string `bson:"initiated-by"`
+
+	// StartTime holds the time the bulk migration was requested
+	// (stored as per UnixNano).
+	StartTime int64 `bson:"start-time"`
+
+	// MaxConcurrent holds the maximum number of the models which may
+	// be migrating at the same time.
+	MaxConcurrent int `bson:"max-concurrent"`
+
+	// The target controller details are the same as for modelMigDoc.
+	TargetController      string   `bson:"target-controller"`
+	TargetControllerAlias string   `bson:"target-controller-alias"`
+	TargetAddrs           []string `bson:"target-addrs"`
+	TargetCACert          string   `bson:"target-cacert"`
+	TargetAuthTag         string   `bson:"target-entity"`
+	TargetPassword        string   `bson:"target-password,omitempty"`
+	TargetMacaroons       string   `bson:"target-macaroons,omitempty"`
+
+	// Models holds the models to migrate, in the order in which their
+	// migrations are started.
+	Models []bulkMigrationModelDoc `bson:"models"`
+}
+
+type bulkMigrationModelDoc struct {
+	ModelUUID   string `bson:"model-uuid"`
+	ModelName   string `bson:"model-name"`
+	ModelOwner  string `bson:"model-owner"`
+	MigrationId string `bson:"migration-id,omitempty"`
+	StartError  string `bson:"start-error,omitempty"`
+}
+
+// BulkMigrationModel describes a model which is part of a bulk
+// migration.
+type BulkMigrationModel struct {
+	// ModelUUID is the UUID of the model.
+	ModelUUID string
+
+	// ModelName and ModelOwner identify the model. They are recorded
+	// when the bulk migration is created, as migrated models are
+	// removed from the controller.
+	ModelName  string
+	ModelOwner string
+
+	// MigrationId is the id of the migration of the model. It is
+	// empty until the model's migration has been started.
+	MigrationId string
+
+	// StartError holds the reason the model's migration couldn't be
+	// started, if it couldn't.
+	StartError string
+}
+
+// Queued returns true if the model's migration has not been started
+// yet.
+func (m BulkMigrationModel) Queued() bool {
+	return m.MigrationId == "" && m.StartError == ""
+}
+
+// Id returns the id of the bulk migration.
+func (b *BulkMigration) Id() string {
+	return b.doc.Id
+}
+
+// InitiatedBy returns the username of the user that requested the bulk
+// migration.
+func (b *BulkMigration) InitiatedBy() string {
+	return b.doc.InitiatedBy
+}
+
+// StartTime returns the time the bulk migration was requested.
+func (b *BulkMigration) StartTime() time.Time {
+	return unixNanoToTime0(b.doc.StartTime)
+}
+
+// MaxConcurrent returns the maximum number of models which may be
+// migrating at the same time.
+func (b *BulkMigration) MaxConcurrent() int {
+	return b.doc.MaxConcurrent
+}
+
+// TargetInfo returns the details required to connect to the bulk
+// migration's target controller.
+func (b *BulkMigration) TargetInfo() (*migration.TargetInfo, error) {
+	authTag, err := names.ParseUserTag(b.doc.TargetAuthTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	macs, err := jsonToMacaroons(b.doc.TargetMacaroons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &migration.TargetInfo{
+		ControllerTag:   names.NewControllerTag(b.doc.TargetController),
+		ControllerAlias: b.doc.TargetControllerAlias,
+		Addrs:           b.doc.TargetAddrs,
+		CACert:          b.doc.TargetCACert,
+		AuthTag:         authTag,
+		Password:        b.doc.TargetPassword,
+		Macaroons:       macs,
+	}, nil
+}
+
+// Models returns the models which are part of the bulk migration.
+func (b *BulkMigration) Models() []BulkMigrationModel {
+	models := make([]BulkMigrationModel, len(b.doc.Models))
+	for i, m := range b.doc.Models {
+		models[i] = BulkMigrationModel{
+			ModelUUID:   m.ModelUUID,
+			ModelName:   m.ModelName,
+			ModelOwner:  m.ModelOwner,
+			MigrationId: m.MigrationId,
+			StartError:  m.StartError,
+		}
+	}
+	return models
+}
+
+// SetModelMigration records that the migration of the model with the
+// given UUID was started with the given migration id.
+func (b *BulkMigration) SetModelMigration(modelUUID, migrationId string) error {
+	if migrationId == "" {
+		return errors.NotValidf("empty migration id")
+	}
+	return errors.Trace(b.setModelStarted(modelUUID, migrationId, ""))
+}
+
+// SetModelStartError records that the migration of the model with the
+// given UUID could not be started.
+func (b *BulkMigration) SetModelStartError(modelUUID string, startErr error) error {
+	if startErr == nil {
+		return errors.NotValidf("nil start error")
+	}
+	return errors.Trace(b.setModelStarted(modelUUID, "", startErr.Error()))
+}
+
+func (b *BulkMigration) setModelStarted(modelUUID, migrationId, startError string) error {
+	index := -1
+	for i, m := range b.doc.Models {
+		if m.ModelUUID == modelUUID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return errors.NotFoundf("model %q in bulk migration %s", modelUUID, b.doc.Id)
+	}
+	prefix := "models." + strconv.Itoa(index) + "."
+	update := bson.D{{prefix + "migration-id", migrationId}}
+	if startError != "" {
+		update = bson.D{{prefix + "start-error", startError}}
+	}
+	ops := []txn.Op{{
+		C:  bulkMigrationsC,
+		Id: b.doc.Id,
+		Assert: bson.D{
+			{prefix + "model-uuid", modelUUID},
+			{prefix + "migration-id", bson.D{{"$exists", false}}},
+			{prefix + "start-error", bson.D{{"$exists", false}}},
+		},
+		Update: bson.D{{"$set", update}},
+	}}
+	if err := b.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("migration of model %q already started", modelUUID)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	b.doc.Models[index].MigrationId = migrationId
+	b.doc.Models[index].StartError = startError
+	return nil
+}
+
+// Refresh updates the contents of the BulkMigration from the
+// underlying state.
+func (b *BulkMigration) Refresh() error {
+	doc, err := b.st.bulkMigrationDoc(b.doc.Id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.doc = *doc
+	return nil
+}
+
+// CreateBulkMigration records a request to migrate a number of models
+// to the same target controller. The migrations of the models are
+// started by the bulk migration worker on the controller, so that no
+// more than MaxConcurrent of them are running at the same time.
+func (st *State) CreateBulkMigration(spec BulkMigrationSpec) (*BulkMigration, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := checkTargetController(st, spec.TargetInfo.ControllerTag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	macsJSON, err := macaroonsToJSON(spec.TargetInfo.Macaroons)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+	var modelDocs []bulkMigrationModelDoc
+	var ops []txn.Op
+	for _, modelUUID := range spec.ModelUUIDs {
+		if modelUUID == st.ControllerModelUUID() {
+			return nil, errors.New("controllers can't be migrated")
+		}
+		var model modelDoc
+		if err := models.FindId(modelUUID).One(&model); err == mgo.ErrNotFound {
+			return nil, errors.NotFoundf("model %q", modelUUID)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelDocs = append(modelDocs, bulkMigrationModelDoc{
+			ModelUUID:  modelUUID,
+			ModelName:  model.Name,
+			ModelOwner: model.Owner,
+			StartError: spec.PrecheckErrors[modelUUID],
+		})
+		ops = append(ops, txn.Op{
+			C:      modelsC,
+			Id:     modelUUID,
+			Assert: isAliveDoc,
+		})
+	}
+
+	seq, err := sequence(st, "bulkmigration")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := bulkMigrationDoc{
+		Id:                    strconv.Itoa(seq),
+		InitiatedBy:           spec.InitiatedBy.Id(),
+		StartTime:             st.clock().Now().UnixNano(),
+		MaxConcurrent:         spec.MaxConcurrent,
+		TargetController:      spec.TargetInfo.ControllerTag.Id(),
+		TargetControllerAlias: spec.TargetInfo.ControllerAlias,
+		TargetAddrs:           spec.TargetInfo.Addrs,
+		TargetCACert:          spec.TargetInfo.CACert,
+		TargetAuthTag:         spec.TargetInfo.AuthTag.String(),
+		TargetPassword:        spec.TargetInfo.Password,
+		TargetMacaroons:       macsJSON,
+		Models:                modelDocs,
+	}
+	ops = append(ops, txn.Op{
+		C:      bulkMigrationsC,
+		Id:     doc.Id,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	})
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("not all models exist and are alive")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "failed to create bulk migration")
+	}
+	return &BulkMigration{st: st, doc: doc}, nil
+}
+
+// BulkMigration returns the bulk migration with the given id.
+func (st *State) BulkMigration(id string) (*BulkMigration, error) {
+	doc, err := st.bulkMigrationDoc(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &BulkMigration{st: st, doc: *doc}, nil
+}
+
+// AllBulkMigrations returns all of the bulk migrations, oldest first.
+func (st *State) AllBulkMigrations() ([]*BulkMigration, error) {
+	coll, closer := st.db().GetCollection(bulkMigrationsC)
+	defer closer()
+
+	var docs []bulkMigrationDoc
+	if err := coll.Find(nil).Sort("start-time").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "reading bulk migrations")
+	}
+	result := make([]*BulkMigration, len(docs))
+	for i, doc := range docs {
+		result[i] = &BulkMigration{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+func (st *State) bulkMigrationDoc(id string) (*bulkMigrationDoc, error) {
+	coll, closer := st.db().GetCollection(bulkMigrationsC)
+	defer closer()
+
+	var doc bulkMigrationDoc
+	if err := coll.FindId(id).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("bulk migration %q", id)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "bulk migration lookup failed")
+	}
+	return &doc, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/state"
+)
+
+type BulkMigrationSuite struct {
+	ConnSuite
+	State2  *state.State
+	State3  *state.State
+	stdSpec state.BulkMigrationSpec
+}
+
+var _ = gc.Suite(new(BulkMigrationSuite))
+
+func (s *BulkMigrationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	s.State2 = s.Factory.MakeModel(c, nil)
+	s.AddCleanup(func(*gc.C) { s.State2.Close() })
+	s.State3 = s.Factory.MakeModel(c, nil)
+	s.AddCleanup(func(*gc.C) { s.State3.Close() })
+
+	s.stdSpec = state.BulkMigrationSpec{
+		InitiatedBy:   names.NewUserTag("admin"),
+		ModelUUIDs:    []string{s.State2.ModelUUID(), s.State3.ModelUUID()},
+		MaxConcurrent: 1,
+		TargetInfo: migration.TargetInfo{
+			ControllerTag:   names.NewControllerTag(utils.MustNewUUID().String()),
+			ControllerAlias: "target-controller",
+			Addrs:           []string{"1.2.3.4:5555"},
+			CACert:          "cert",
+			AuthTag:         names.NewUserTag("user"),
+			Password:        "password",
+		},
+	}
+}
+
+func (s *BulkMigrationSuite) TestCreate(c *gc.C) {
+	bulk, err := s.State.CreateBulkMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(bulk.Id(), gc.Equals, "0")
+	c.Check(bulk.InitiatedBy(), gc.Equals, "admin")
+	c.Check(bulk.StartTime(), gc.Equals, s.Clock.Now())
+	c.Check(bulk.MaxConcurrent(), gc.Equals, 1)
+	info, err := bulk.TargetInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*info, jc.DeepEquals, s.stdSpec.TargetInfo)
+	model2, err := s.State2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	model3, err := s.State3.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bulk.Models(), jc.DeepEquals, []state.BulkMigrationModel{
+		{ModelUUID: s.State2.ModelUUID(), ModelName: model2.Name(), ModelOwner: model2.Owner().Id()},
+		{ModelUUID: s.State3.ModelUUID(), ModelName: model3.Name(), ModelOwner: model3.Owner().Id()},
+	})
+
+	all, err := s.State.AllBulkMigrations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Check(all[0].Id(), gc.Equals, bulk.Id())
+}
+
+func (s *BulkMigrationSuite) TestCreateInvalid(c *gc.C) {
+	spec := s.stdSpec
+	spec.MaxConcurrent = 0
+	_, err := s.State.CreateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, "MaxConcurrent 0 not valid")
+
+	spec = s.stdSpec
+	spec.ModelUUIDs = []string{s.State2.ModelUUID(), s.State2.ModelUUID()}
+	_, err = s.State.CreateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, `duplicate model UUID ".+" not valid`)
+}
+
+func (s *BulkMigrationSuite) TestCreatePrecheckErrors(c *gc.C) {
+	spec := s.stdSpec
+	spec.PrecheckErrors = map[string]string{s.State3.ModelUUID(): "boom"}
+	bulk, err := s.State.CreateBulkMigration(spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	models := bulk.Models()
+	c.Assert(models, gc.HasLen, 2)
+	c.Check(models[0].Queued(), jc.IsTrue)
+	c.Check(models[1].StartError, gc.Equals, "boom")
+	c.Check(models[1].Queued(), jc.IsFalse)
+
+	spec.PrecheckErrors = map[string]string{s.State.ModelUUID(): "boom"}
+	_, err = s.State.CreateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, `precheck error for unknown model UUID ".+" not valid`)
+}
+
+func (s *BulkMigrationSuite) TestCreateMissingModel(c *gc.C) {
+	spec := s.stdSpec
+	spec.ModelUUIDs = []string{utils.MustNewUUID().String()}
+	_, err := s.State.CreateBulkMigration(spec)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BulkMigrationSuite) TestCreateControllerModel(c *gc.C) {
+	spec := s.stdSpec
+	spec.ModelUUIDs = []string{s.State.ModelUUID()}
+	_, err := s.State.CreateBulkMigration(spec)
+	c.Check(err, gc.ErrorMatches, "controllers can't be migrated")
+}
+
+func (s *BulkMigrationSuite) TestSetModelMigration(c *gc.C) {
+	bulk, err := s.State.CreateBulkMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = bulk.SetModelMigration(s.State2.ModelUUID(), s.State2.ModelUUID()+":0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = bulk.SetModelStartError(s.State3.ModelUUID(), errors.New("boom"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	bulk, err = s.State.BulkMigration(bulk.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	models := bulk.Models()
+	c.Assert(models, gc.HasLen, 2)
+	c.Check(models[0].MigrationId, gc.Equals, s.State2.ModelUUID()+":0")
+	c.Check(models[0].StartError, gc.Equals, "")
+	c.Check(models[1].MigrationId, gc.Equals, "")
+	c.Check(models[1].StartError, gc.Equals, "boom")
+
+	err = bulk.SetModelMigration(s.State2.ModelUUID(), s.State2.ModelUUID()+":1")
+	c.Check(err, gc.ErrorMatches, `migration of model ".+" already started`)
+}
+
+func (s *BulkMigrationSuite) TestBulkMigrationNotFound(c *gc.C) {
+	_, err := s.State.BulkMigration("42")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
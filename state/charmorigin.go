@@ -0,0 +1,63 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CharmOriginCharmHub is the source of charms downloaded from charmhub.
+const CharmOriginCharmHub = "charmhub"
+
+// CharmOrigin records where an application's charm was obtained from,
+// when that can't be told from the charm URL. Charms downloaded from
+// charmhub are added to the model as local charms; their origin
+// identifies the charmhub charm to refresh the application from.
+// The channel the application tracks is its Channel.
+type CharmOrigin struct {
+	// Source is where the charm was obtained from, e.g. "charmhub".
+	Source string `bson:"source" yaml:"source"`
+
+	// ID is the charm's ID in the source.
+	ID string `bson:"id" yaml:"id"`
+
+	// Revision is the charm's revision in the source, which may
+	// differ from the revision of the local charm.
+	Revision int `bson:"revision" yaml:"revision"`
+}
+
+// migrationCharmOriginAnnotation is the application annotation used to
+// carry the application's charm origin through a migration.
+const migrationCharmOriginAnnotation = "juju-migration-charm-origin"
+
+// Validate returns an error if the origin is not valid.
+func (o CharmOrigin) Validate() error {
+	if o.Source != CharmOriginCharmHub {
+		return errors.NotValidf("charm origin source %q", o.Source)
+	}
+	if o.ID == "" {
+		return errors.NotValidf("charm origin without ID")
+	}
+	return nil
+}
+
+// CharmOrigin returns where the application's charm was obtained
+// from, or nil if that is told by the charm URL.
+func (a *Application) CharmOrigin() *CharmOrigin {
+	if a.doc.CharmOrigin == nil {
+		return nil
+	}
+	origin := *a.doc.CharmOrigin
+	return &origin
+}
+
+// charmOriginUpdate returns the update setting the application's charm
+// origin, clearing it when origin is nil.
+func charmOriginUpdate(origin *CharmOrigin) bson.D {
+	if origin == nil {
+		return bson.D{{"$unset", bson.D{{"charm-origin", nil}}}}
+	}
+	return bson.D{{"$set", bson.D{{"charm-origin", origin}}}}
+}
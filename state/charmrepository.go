@@ -0,0 +1,592 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	charmresource "github.com/juju/charm/v7/resource"
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/storage"
+)
+
+// The controller's charm repository holds revisions of local charms, and
+// the resources stored with them, published from any model hosted by the
+// controller. Revisions are released to channels, and resolved from them
+// when deploying and upgrading applications of local charms.
+//
+// Charm archives and resource blobs are kept in the controller model's
+// storage, apart from the charms added to models. Removing unused charms
+// from a model doesn't affect the revisions in the repository; the charm
+// is added to the model again from the repository when it is next
+// deployed. Revisions are only removed from the repository explicitly,
+// and not while they are released to a channel.
+
+// repositoryCharmDoc records a revision of a local charm published to the
+// controller's charm repository.
+type repositoryCharmDoc struct {
+	DocID       string                  `bson:"_id"`
+	Name        string                  `bson:"name"`
+	Series      string                  `bson:"series"`
+	Revision    int                     `bson:"revision"`
+	StoragePath string                  `bson:"storage-path"`
+	SHA256      string                  `bson:"sha256"`
+	Size        int64                   `bson:"size"`
+	Resources   []repositoryResourceDoc `bson:"resources,omitempty"`
+}
+
+// repositoryResourceDoc records a resource stored with a revision of a
+// charm in the controller's charm repository.
+type repositoryResourceDoc struct {
+	Name        string `bson:"name"`
+	Type        string `bson:"type"`
+	Path        string `bson:"path"`
+	Description string `bson:"description"`
+	Fingerprint string `bson:"fingerprint"`
+	Size        int64  `bson:"size"`
+	StoragePath string `bson:"storage-path"`
+}
+
+func (doc repositoryResourceDoc) resource() (charmresource.Resource, error) {
+	resType, err := charmresource.ParseType(doc.Type)
+	if err != nil {
+		return charmresource.Resource{}, errors.Trace(err)
+	}
+	fp, err := charmresource.ParseFingerprint(doc.Fingerprint)
+	if err != nil {
+		return charmresource.Resource{}, errors.Trace(err)
+	}
+	return charmresource.Resource{
+		Meta: charmresource.Meta{
+			Name:        doc.Name,
+			Type:        resType,
+			Path:        doc.Path,
+			Description: doc.Description,
+		},
+		Origin:      charmresource.OriginUpload,
+		Fingerprint: fp,
+		Size:        doc.Size,
+	}, nil
+}
+
+// localCharmReleaseDoc records the revision of a local charm released to a
+// channel of the controller's charm repository.
+type localCharmReleaseDoc struct {
+	DocID   string `bson:"_id"`
+	Name    string `bson:"name"`
+	Series  string `bson:"series"`
+	Channel string `bson:"channel"`
+	URL     string `bson:"url"`
+}
+
+func localCharmReleaseKey(name, series, channel string) string {
+	return fmt.Sprintf("%s#%s#%s", series, name, channel)
+}
+
+// repositoryStorage returns the storage holding the charm archives and
+// resources of the controller's charm repository.
+func (st *State) repositoryStorage() storage.Storage {
+	return storage.NewStorage(st.ControllerModelUUID(), st.MongoSession())
+}
+
+func repositoryStoragePath() (string, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("charmrepository/%s", uuid), nil
+}
+
+func validateLocalCharmURL(curl *charm.URL) error {
+	if curl.Schema != "local" {
+		return errors.NotValidf("non-local charm %q", curl)
+	}
+	if curl.Series == "" || curl.Revision < 0 {
+		return errors.NotValidf("charm URL %q without series and revision", curl)
+	}
+	return nil
+}
+
+// PublishLocalCharm publishes the local charm uploaded to the model as a
+// new revision of the charm in the controller's charm repository, and
+// returns the URL of that revision. The pending resources, which were
+// uploaded for an application named after the charm, are keyed on the
+// resource names; they are stored with the published revision and then
+// removed from the model.
+//
+// If the latest revision published for the charm has the same archive
+// and resources, its URL is returned rather than publishing the charm
+// again.
+func (st *State) PublishLocalCharm(curl *charm.URL, pendingIDs map[string]string) (_ *charm.URL, err error) {
+	if err := validateLocalCharmURL(curl); err != nil {
+		return nil, errors.Annotate(err, "publishing charm")
+	}
+	ch, err := st.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resources, err := st.Resources()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var pending []charmresource.Resource
+	for name, pendingID := range pendingIDs {
+		res, err := resources.GetPendingResource(curl.Name, name, pendingID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if res.Type != charmresource.TypeFile {
+			return nil, errors.NotSupportedf("%s resource %q in the charm repository", res.Type, name)
+		}
+		pending = append(pending, res.Resource)
+	}
+
+	if latest, err := st.latestRepositoryCharm(curl.Name, curl.Series); err == nil {
+		if latest.SHA256 == ch.BundleSha256() && sameRepositoryResources(latest.Resources, pending) {
+			removePublishedResources(resources, curl, pendingIDs)
+			return charm.ParseURL(latest.DocID)
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	// Copy the archive and resources into the repository's storage.
+	// They are removed again if the revision isn't recorded.
+	modelStorage := storage.NewStorage(st.ModelUUID(), st.MongoSession())
+	repoStorage := st.repositoryStorage()
+	var stored []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, path := range stored {
+			if err := repoStorage.Remove(path); err != nil {
+				logger.Errorf("cannot remove charm repository blob %q: %v", path, err)
+			}
+		}
+	}()
+	copyBlob := func(fromPath, hash string) (string, int64, error) {
+		r, size, err := modelStorage.Get(fromPath)
+		if err != nil {
+			return "", 0, errors.Trace(err)
+		}
+		defer func() { _ = r.Close() }()
+		toPath, err := repositoryStoragePath()
+		if err != nil {
+			return "", 0, errors.Trace(err)
+		}
+		if hash == "" {
+			err = repoStorage.Put(toPath, r, size)
+		} else {
+			err = repoStorage.PutAndCheckHash(toPath, r, size, hash)
+		}
+		if err != nil {
+			return "", 0, errors.Trace(err)
+		}
+		stored = append(stored, toPath)
+		return toPath, size, nil
+	}
+
+	archivePath, archiveSize, err := copyBlob(ch.StoragePath(), "")
+	if err != nil {
+		return nil, errors.Annotate(err, "storing charm archive")
+	}
+	doc := repositoryCharmDoc{
+		Name:        curl.Name,
+		Series:      curl.Series,
+		StoragePath: archivePath,
+		SHA256:      ch.BundleSha256(),
+		Size:        archiveSize,
+	}
+	for name, pendingID := range pendingIDs {
+		res, err := resources.GetPendingResource(curl.Name, name, pendingID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		path, _, err := copyBlob(storagePath(name, curl.Name, pendingID), res.Fingerprint.String())
+		if err != nil {
+			return nil, errors.Annotatef(err, "storing resource %q", name)
+		}
+		doc.Resources = append(doc.Resources, repositoryResourceDoc{
+			Name:        res.Name,
+			Type:        res.Type.String(),
+			Path:        res.Path,
+			Description: res.Description,
+			Fingerprint: res.Fingerprint.String(),
+			Size:        res.Size,
+			StoragePath: path,
+		})
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		// Revisions follow on from the latest one published, but are
+		// never lower than the revision of the uploaded charm.
+		doc.Revision = curl.Revision
+		latest, err := st.latestRepositoryCharm(curl.Name, curl.Series)
+		if err == nil && latest.Revision >= doc.Revision {
+			doc.Revision = latest.Revision + 1
+		} else if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		doc.DocID = curl.WithRevision(doc.Revision).String()
+		return []txn.Op{{
+			C:      repositoryCharmsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "publishing charm %q", curl)
+	}
+
+	removePublishedResources(resources, curl, pendingIDs)
+	return curl.WithRevision(doc.Revision), nil
+}
+
+// removePublishedResources removes the pending resources, which have been
+// stored in the charm repository, from the model.
+func removePublishedResources(resources Resources, curl *charm.URL, pendingIDs map[string]string) {
+	if len(pendingIDs) == 0 {
+		return
+	}
+	if err := resources.RemovePendingAppResources(curl.Name, pendingIDs); err != nil {
+		logger.Warningf("cannot remove published resources of charm %q: %v", curl, err)
+	}
+}
+
+func sameRepositoryResources(docs []repositoryResourceDoc, resources []charmresource.Resource) bool {
+	if len(docs) != len(resources) {
+		return false
+	}
+	fingerprints := make(map[string]string)
+	for _, doc := range docs {
+		fingerprints[doc.Name] = doc.Fingerprint
+	}
+	for _, res := range resources {
+		if fp, ok := fingerprints[res.Name]; !ok || fp != res.Fingerprint.String() {
+			return false
+		}
+	}
+	return true
+}
+
+func (st *State) repositoryCharm(curl *charm.URL) (*repositoryCharmDoc, error) {
+	coll, closer := st.db().GetCollection(repositoryCharmsC)
+	defer closer()
+
+	var doc repositoryCharmDoc
+	if err := coll.FindId(curl.String()).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("charm %q in the charm repository", curl)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+func (st *State) latestRepositoryCharm(name, series string) (*repositoryCharmDoc, error) {
+	coll, closer := st.db().GetCollection(repositoryCharmsC)
+	defer closer()
+
+	var doc repositoryCharmDoc
+	err := coll.Find(bson.D{{"name", name}, {"series", series}}).Sort("-revision").One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("charm %q for series %q in the charm repository", name, series)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// ReleaseLocalCharm releases the revision of a local charm published to
+// the controller's charm repository to the channel, replacing the
+// revision previously released to that channel for the charm's name and
+// series.
+func (st *State) ReleaseLocalCharm(curl *charm.URL, channel string) error {
+	if err := validateLocalCharmURL(curl); err != nil {
+		return errors.Annotate(err, "releasing charm")
+	}
+	if channel == "" {
+		return errors.NotValidf("empty channel")
+	}
+	key := localCharmReleaseKey(curl.Name, curl.Series, channel)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		// Only published charms can be released. The assertion
+		// below keeps the release from being left dangling by the
+		// revision's removal.
+		if _, err := st.repositoryCharm(curl); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      repositoryCharmsC,
+			Id:     curl.String(),
+			Assert: txn.DocExists,
+		}}
+
+		existing, err := st.localCharmRelease(key)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      localCharmReleasesC,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: &localCharmReleaseDoc{
+					DocID:   key,
+					Name:    curl.Name,
+					Series:  curl.Series,
+					Channel: channel,
+					URL:     curl.String(),
+				},
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if existing.URL == curl.String() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, txn.Op{
+			C:      localCharmReleasesC,
+			Id:     key,
+			Assert: bson.D{{"url", existing.URL}},
+			Update: bson.D{{"$set", bson.D{{"url", curl.String()}}}},
+		}), nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "releasing charm %q to channel %q", curl, channel)
+}
+
+// LocalCharmReleased reports whether the revision of a local charm in the
+// controller's charm repository is released to any of its channels.
+func (st *State) LocalCharmReleased(curl *charm.URL) (bool, error) {
+	channels, err := st.localCharmReleaseChannels(curl)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return len(channels) > 0, nil
+}
+
+// localCharmReleaseChannels returns the channels the revision of a local
+// charm is released to.
+func (st *State) localCharmReleaseChannels(curl *charm.URL) ([]string, error) {
+	coll, closer := st.db().GetCollection(localCharmReleasesC)
+	defer closer()
+
+	var docs []localCharmReleaseDoc
+	if err := coll.Find(bson.D{{"url", curl.String()}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	channels := make([]string, len(docs))
+	for i, doc := range docs {
+		channels[i] = doc.Channel
+	}
+	sort.Strings(channels)
+	return channels, nil
+}
+
+// RemoveRepositoryCharm removes the revision of a local charm, and the
+// resources stored with it, from the controller's charm repository.
+// Models that already added the charm keep their copy of it. It is an
+// error to remove a revision released to a channel.
+func (st *State) RemoveRepositoryCharm(curl *charm.URL) error {
+	if err := validateLocalCharmURL(curl); err != nil {
+		return errors.Annotate(err, "removing charm")
+	}
+	var doc *repositoryCharmDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var err error
+		if doc, err = st.repositoryCharm(curl); err != nil {
+			return nil, errors.Trace(err)
+		}
+		channels, err := st.localCharmReleaseChannels(curl)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(channels) > 0 {
+			return nil, errors.Errorf("charm is released to %s", strings.Join(channels, ", "))
+		}
+		// Releasing the revision asserts that it exists, so it
+		// can't be released once it's removed.
+		return []txn.Op{{
+			C:      repositoryCharmsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "removing charm %q", curl)
+	}
+
+	repoStorage := st.repositoryStorage()
+	paths := []string{doc.StoragePath}
+	for _, res := range doc.Resources {
+		paths = append(paths, res.StoragePath)
+	}
+	for _, path := range paths {
+		if err := repoStorage.Remove(path); err != nil && !errors.IsNotFound(err) {
+			logger.Errorf("cannot remove charm repository blob %q: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// LocalCharmRelease returns the local charm with the given name and series
+// released to the channel of the controller's charm repository.
+func (st *State) LocalCharmRelease(name, series, channel string) (*charm.URL, error) {
+	doc, err := st.localCharmRelease(localCharmReleaseKey(name, series, channel))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	curl, err := charm.ParseURL(doc.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return curl, nil
+}
+
+func (st *State) localCharmRelease(key string) (*localCharmReleaseDoc, error) {
+	coll, closer := st.db().GetCollection(localCharmReleasesC)
+	defer closer()
+
+	var doc localCharmReleaseDoc
+	if err := coll.FindId(key).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("local charm release %q", key)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// AddCharmFromRepository adds the revision of a local charm published to
+// the controller's charm repository to the model, unless the model
+// already has it. It is an error for the model to have a different charm
+// with the same URL.
+func (st *State) AddCharmFromRepository(curl *charm.URL) (*Charm, error) {
+	doc, err := st.repositoryCharm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ch, err := st.Charm(curl); err == nil {
+		if ch.BundleSha256() != doc.SHA256 {
+			return nil, errors.AlreadyExistsf("different charm %q in the model", curl)
+		}
+		return ch, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	r, _, err := st.repositoryStorage().Get(doc.StoragePath)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading charm archive")
+	}
+	data, err := ioutil.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		return nil, errors.Annotate(err, "reading charm archive")
+	}
+	archive, err := charm.ReadCharmArchiveBytes(data)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading charm archive")
+	}
+
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	storagePath := fmt.Sprintf("charms/%s-%s", curl.String(), uuid)
+	modelStorage := storage.NewStorage(st.ModelUUID(), st.MongoSession())
+	if err := modelStorage.Put(storagePath, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, errors.Annotate(err, "storing charm archive")
+	}
+	// Local charms uploaded to the model later on must not be given
+	// the revision of the charm from the repository.
+	if _, err := sequenceWithMin(st, charmRevSeqName(curl.WithRevision(-1).String()), curl.Revision); err != nil {
+		_ = modelStorage.Remove(storagePath)
+		return nil, errors.Annotate(err, "unable to allocate charm revision")
+	}
+	ch, err := st.AddCharm(CharmInfo{
+		Charm:       archive,
+		ID:          curl,
+		StoragePath: storagePath,
+		SHA256:      doc.SHA256,
+		Version:     archive.Version(),
+	})
+	if err != nil {
+		_ = modelStorage.Remove(storagePath)
+		return nil, errors.Trace(err)
+	}
+	return ch, nil
+}
+
+// AddPendingResourcesFromRepository adds the resources stored with the
+// revision of a local charm in the controller's charm repository as
+// pending resources of the application, except for those named in
+// exclude. It returns the pending IDs keyed on the resource names.
+func (st *State) AddPendingResourcesFromRepository(curl *charm.URL, applicationID, userID string, exclude []string) (map[string]string, error) {
+	doc, err := st.repositoryCharm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resources, err := st.Resources()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	excluded := make(map[string]bool)
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+	repoStorage := st.repositoryStorage()
+	pendingIDs := make(map[string]string)
+	for _, resDoc := range doc.Resources {
+		if excluded[resDoc.Name] {
+			continue
+		}
+		res, err := resDoc.resource()
+		if err != nil {
+			return nil, errors.Annotatef(err, "resource %q", resDoc.Name)
+		}
+		if err := st.addPendingResourceFromRepository(
+			resources, repoStorage, resDoc.StoragePath, applicationID, userID, res, pendingIDs,
+		); err != nil {
+			if len(pendingIDs) > 0 {
+				if err := resources.RemovePendingAppResources(applicationID, pendingIDs); err != nil {
+					logger.Errorf("cannot remove pending resources of application %q: %v", applicationID, err)
+				}
+			}
+			return nil, errors.Annotatef(err, "adding resource %q", res.Name)
+		}
+	}
+	return pendingIDs, nil
+}
+
+func (st *State) addPendingResourceFromRepository(
+	resources Resources,
+	repoStorage storage.Storage,
+	path, applicationID, userID string,
+	res charmresource.Resource,
+	pendingIDs map[string]string,
+) error {
+	pendingID, err := resources.AddPendingResource(applicationID, userID, res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	pendingIDs[res.Name] = pendingID
+	var r io.ReadCloser
+	if r, _, err = repoStorage.Get(path); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = r.Close() }()
+	_, err = resources.UpdatePendingResource(applicationID, pendingID, userID, res, r)
+	return errors.Trace(err)
+}
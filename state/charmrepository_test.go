@@ -0,0 +1,269 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/resource/resourcetesting"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing/factory"
+)
+
+type CharmRepositorySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CharmRepositorySuite{})
+
+// uploadCharm adds the dummy charm to the model with its archive in the
+// model's storage, as uploading a local charm does.
+func (s *CharmRepositorySuite) uploadCharm(c *gc.C, st *state.State, url string) *state.Charm {
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	data, err := ioutil.ReadFile(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL(url)
+	storagePath := "charms/" + curl.String()
+	stor := storage.NewStorage(st.ModelUUID(), st.MongoSession())
+	err = stor.Put(storagePath, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := st.AddCharm(state.CharmInfo{
+		Charm:       archive,
+		ID:          curl,
+		StoragePath: storagePath,
+		SHA256:      fmt.Sprintf("%x", sha256.Sum256(data)),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return ch
+}
+
+func (s *CharmRepositorySuite) addPendingResource(c *gc.C, st *state.State, applicationID, name, data string) string {
+	resources, err := st.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	res := resourcetesting.NewResource(c, nil, name, applicationID, data).Resource.Resource
+	pendingID, err := resources.AddPendingResource(applicationID, "admin", res)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = resources.UpdatePendingResource(applicationID, pendingID, "admin", res, strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	return pendingID
+}
+
+func (s *CharmRepositorySuite) TestPublishLocalCharm(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, ch.URL())
+
+	// Publishing the same charm again gives the same revision.
+	curl, err = s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, ch.URL())
+
+	// A charm with different resources is given a new revision.
+	pendingID := s.addPendingResource(c, s.State, "dummy", "spam", "spamspamspam")
+	curl, err = s.State.PublishLocalCharm(ch.URL(), map[string]string{"spam": pendingID})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, charm.MustParseURL("local:quantal/dummy-2"))
+
+	// The pending resource is stored in the repository, not the model.
+	resources, err := s.State.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = resources.GetPendingResource("dummy", "spam", pendingID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmRepositorySuite) TestPublishLocalCharmNotUploaded(c *gc.C) {
+	_, err := s.State.PublishLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmRepositorySuite) TestReleaseLocalCharm(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ReleaseLocalCharm(curl, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	released, err := s.State.LocalCharmRelease("dummy", "quantal", "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, gc.DeepEquals, curl)
+
+	_, err = s.State.LocalCharmRelease("dummy", "quantal", "latest/edge")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.LocalCharmRelease("dummy", "bionic", "latest/stable")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmRepositorySuite) TestReleaseLocalCharmReplacesRevision(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl1, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	pendingID := s.addPendingResource(c, s.State, "dummy", "spam", "spamspamspam")
+	curl2, err := s.State.PublishLocalCharm(ch.URL(), map[string]string{"spam": pendingID})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ReleaseLocalCharm(curl1, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(curl2, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	// Releasing the same revision again is a no-op.
+	err = s.State.ReleaseLocalCharm(curl2, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	released, err := s.State.LocalCharmRelease("dummy", "quantal", "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, gc.DeepEquals, curl2)
+}
+
+func (s *CharmRepositorySuite) TestReleaseLocalCharmNotPublished(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy", URL: "local:quantal/dummy-1"})
+	err := s.State.ReleaseLocalCharm(ch.URL(), "latest/stable")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmRepositorySuite) TestReleaseLocalCharmInvalid(c *gc.C) {
+	err := s.State.ReleaseLocalCharm(charm.MustParseURL("cs:quantal/dummy-1"), "latest/stable")
+	c.Assert(err, gc.ErrorMatches, `releasing charm: non-local charm "cs:quantal/dummy-1" not valid`)
+
+	err = s.State.ReleaseLocalCharm(charm.MustParseURL("local:quantal/dummy"), "latest/stable")
+	c.Assert(err, gc.ErrorMatches, `releasing charm: charm URL "local:quantal/dummy" without series and revision not valid`)
+
+	err = s.State.ReleaseLocalCharm(charm.MustParseURL("local:quantal/dummy-1"), "")
+	c.Assert(err, gc.ErrorMatches, `empty channel not valid`)
+}
+
+func (s *CharmRepositorySuite) TestReleasesSharedByModels(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(curl, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	released, err := otherSt.LocalCharmRelease("dummy", "quantal", "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, gc.DeepEquals, curl)
+}
+
+func (s *CharmRepositorySuite) TestAddCharmFromRepository(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	pendingID := s.addPendingResource(c, s.State, "dummy", "spam", "spamspamspam")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), map[string]string{"spam": pendingID})
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	added, err := otherSt.AddCharmFromRepository(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added.URL(), gc.DeepEquals, curl)
+	c.Assert(added.BundleSha256(), gc.Equals, ch.BundleSha256())
+	c.Assert(added.IsUploaded(), jc.IsTrue)
+
+	// Adding it again gives the charm already in the model.
+	again, err := otherSt.AddCharmFromRepository(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.URL(), gc.DeepEquals, curl)
+
+	pendingIDs, err := otherSt.AddPendingResourcesFromRepository(curl, "dummy", "admin", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pendingIDs, gc.HasLen, 1)
+	resources, err := otherSt.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	res, err := resources.GetPendingResource("dummy", "spam", pendingIDs["spam"])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Size, gc.Equals, int64(len("spamspamspam")))
+
+	pendingIDs, err = otherSt.AddPendingResourcesFromRepository(curl, "dummy", "admin", []string{"spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pendingIDs, gc.HasLen, 0)
+}
+
+func (s *CharmRepositorySuite) TestAddCharmFromRepositoryDifferentCharm(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	f := factory.NewFactory(otherSt, s.StatePool)
+	f.MakeCharm(c, &factory.CharmParams{Name: "dummy", URL: curl.String()})
+	_, err = otherSt.AddCharmFromRepository(curl)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *CharmRepositorySuite) TestAddCharmFromRepositoryNotPublished(c *gc.C) {
+	_, err := s.State.AddCharmFromRepository(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmRepositorySuite) TestLocalCharmReleased(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	released, err := s.State.LocalCharmReleased(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsFalse)
+
+	err = s.State.ReleaseLocalCharm(curl, "latest/edge")
+	c.Assert(err, jc.ErrorIsNil)
+	released, err = s.State.LocalCharmReleased(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(released, jc.IsTrue)
+}
+
+func (s *CharmRepositorySuite) TestRemoveRepositoryCharm(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	pendingID := s.addPendingResource(c, s.State, "dummy", "spam", "spamspamspam")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), map[string]string{"spam": pendingID})
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherSt := s.Factory.MakeModel(c, nil)
+	defer otherSt.Close()
+	_, err = otherSt.AddCharmFromRepository(curl)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveRepositoryCharm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherSt.AddPendingResourcesFromRepository(curl, "dummy", "admin", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.ReleaseLocalCharm(curl, "latest/stable")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The model that added the charm keeps it.
+	_, err = otherSt.Charm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmRepositorySuite) TestRemoveRepositoryCharmReleased(c *gc.C) {
+	ch := s.uploadCharm(c, s.State, "local:quantal/dummy-1")
+	curl, err := s.State.PublishLocalCharm(ch.URL(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(curl, "latest/stable")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseLocalCharm(curl, "latest/edge")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveRepositoryCharm(curl)
+	c.Assert(err, gc.ErrorMatches, `removing charm "local:quantal/dummy-1": charm is released to latest/edge, latest/stable`)
+}
+
+func (s *CharmRepositorySuite) TestRemoveRepositoryCharmNotPublished(c *gc.C) {
+	err := s.State.RemoveRepositoryCharm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
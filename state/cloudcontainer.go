@@ -24,6 +24,10 @@ type CloudContainer interface {
 	// Address returns the container address.
 	Address() *network.SpaceAddress
 
+	// SpaceAddresses returns the container addresses on
+	// secondary networks, each associated with a space.
+	SpaceAddresses() network.SpaceAddresses
+
 	// Ports returns the open container ports.
 	Ports() []string
 }
@@ -40,9 +44,10 @@ type cloudContainerDoc struct {
 	// by this container.
 	Id string `bson:"_id"`
 
-	ProviderId string   `bson:"provider-id"`
-	Address    *address `bson:"address"`
-	Ports      []string `bson:"ports"`
+	ProviderId     string    `bson:"provider-id"`
+	Address        *address  `bson:"address"`
+	SpaceAddresses []address `bson:"space-addresses,omitempty"`
+	Ports          []string  `bson:"ports"`
 }
 
 // Id implements CloudContainer.
@@ -69,6 +74,14 @@ func (c *cloudContainer) Address() *network.SpaceAddress {
 	return &addr
 }
 
+// SpaceAddresses implements CloudContainer.
+func (c *cloudContainer) SpaceAddresses() network.SpaceAddresses {
+	if len(c.doc.SpaceAddresses) == 0 {
+		return nil
+	}
+	return networkAddresses(c.doc.SpaceAddresses)
+}
+
 // Ports implements CloudContainer.
 func (c *cloudContainer) Ports() []string {
 	return c.doc.Ports
@@ -124,7 +137,8 @@ func (u *Unit) saveContainerOps(doc cloudContainerDoc) ([]txn.Op, error) {
 			{"$set",
 				bson.D{{"provider-id", doc.ProviderId},
 					{"ports", doc.Ports},
-					{"address", doc.Address}},
+					{"address", doc.Address},
+					{"space-addresses", doc.SpaceAddresses}},
 			},
 		},
 	}}, nil
@@ -26,9 +26,13 @@ type constraintsDoc struct {
 	RootDisk       *uint64
 	RootDiskSource *string
 	InstanceType   *string
+	ImageID        *string
+	ImageName      *string
 	Container      *instance.ContainerType
 	Tags           *[]string
 	Spaces         *[]string
+	Spot           *bool
+	MaxPrice       *string
 	VirtType       *string
 	Zones          *[]string
 }
@@ -43,9 +47,13 @@ func newConstraintsDoc(cons constraints.Value, id string) constraintsDoc {
 		RootDisk:       cons.RootDisk,
 		RootDiskSource: cons.RootDiskSource,
 		InstanceType:   cons.InstanceType,
+		ImageID:        cons.ImageID,
+		ImageName:      cons.ImageName,
 		Container:      cons.Container,
 		Tags:           cons.Tags,
 		Spaces:         cons.Spaces,
+		Spot:           cons.Spot,
+		MaxPrice:       cons.MaxPrice,
 		VirtType:       cons.VirtType,
 		Zones:          cons.Zones,
 	}
@@ -61,9 +69,13 @@ func (doc constraintsDoc) value() constraints.Value {
 		RootDisk:       doc.RootDisk,
 		RootDiskSource: doc.RootDiskSource,
 		InstanceType:   doc.InstanceType,
+		ImageID:        doc.ImageID,
+		ImageName:      doc.ImageName,
 		Container:      doc.Container,
 		Tags:           doc.Tags,
 		Spaces:         doc.Spaces,
+		Spot:           doc.Spot,
+		MaxPrice:       doc.MaxPrice,
 		VirtType:       doc.VirtType,
 		Zones:          doc.Zones,
 	}
@@ -54,11 +54,16 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 		controller.ModelLogfileMaxBackups,
 		controller.ModelLogfileMaxSize,
 		controller.MongoMemoryProfile,
+		controller.OIDCIssuerURL,
+		controller.OIDCClientID,
+		controller.OIDCGroupsClaim,
+		controller.OIDCGroupAccess,
 		controller.PruneTxnQueryCount,
 		controller.PruneTxnSleepTime,
 		controller.MaxCharmStateSize,
 		controller.MaxAgentStateSize,
 		controller.NonSyncedWritesToRaftLog,
+		controller.AgentMirrorKeyring,
 	)
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
 		v, ok := controllerSettings.Get(controllerAttr)
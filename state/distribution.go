@@ -35,7 +35,7 @@ func distributeUnit(u *Unit, candidates []instance.Id, limitZones []string) ([]i
 		return nil, fmt.Errorf("policy returned nil instance distributor without an error")
 	}
 
-	distributionGroup, err := ApplicationInstances(u.st, u.doc.Application)
+	distributionGroup, err := ApplicationDistributionGroup(u.st, u.doc.Application)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,8 @@
 package errors
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 )
 
@@ -18,3 +20,26 @@ var (
 	// has storage attachments.
 	ErrUnitHasStorageAttachments = errors.New("unit has storage attachments")
 )
+
+// placementPolicyError indicates that a unit cannot be placed on a
+// machine without breaking the placement policy of an application.
+type placementPolicyError struct {
+	message string
+}
+
+// NewPlacementPolicyError returns an error indicating that a unit
+// cannot be placed without breaking a placement policy.
+func NewPlacementPolicyError(format string, args ...interface{}) error {
+	return &placementPolicyError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *placementPolicyError) Error() string {
+	return e.message
+}
+
+// IsPlacementPolicyError returns true if the error or its cause is
+// a placement policy error.
+func IsPlacementPolicyError(err error) bool {
+	_, ok := errors.Cause(err).(*placementPolicyError)
+	return ok
+}
@@ -0,0 +1,587 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/core/permission"
+)
+
+// groupGlobalKeyPrefix is the prefix of the subject global key used
+// for permissions granted to a group, as opposed to a single user.
+const groupGlobalKeyPrefix = "gr"
+
+func groupGlobalKey(name string) string {
+	return fmt.Sprintf("%s#%s", groupGlobalKeyPrefix, strings.ToLower(name))
+}
+
+// groupDoc represents a named set of users in the controller.
+// Access granted to the group applies to all of its members.
+type groupDoc struct {
+	DocID       string    `bson:"_id"`
+	Name        string    `bson:"name"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+	// Members holds the ids of the member users, in lower case.
+	Members []string `bson:"members"`
+	// ImportingModelUUID is set on groups created by the import of
+	// a migrated model until the import completes, so that they are
+	// removed again if the import is aborted.
+	ImportingModelUUID string `bson:"importing-model-uuid,omitempty"`
+}
+
+// migrationGroupsAnnotation is the model annotation used to carry the
+// groups with access to a model through a migration.
+const migrationGroupsAnnotation = "juju-migration-groups"
+
+// migrationGroup describes a group with access to a migrated model or
+// to its application offers.
+type migrationGroup struct {
+	Name        string    `yaml:"name"`
+	CreatedBy   string    `yaml:"created-by"`
+	DateCreated time.Time `yaml:"date-created"`
+	Members     []string  `yaml:"members"`
+	ModelAccess string    `yaml:"model-access,omitempty"`
+	// OfferAccess holds the access granted to the group on the
+	// model's application offers, keyed by offer UUID.
+	OfferAccess map[string]string `yaml:"offer-access,omitempty"`
+}
+
+// Group represents a group of users in the controller.
+type Group struct {
+	st  *State
+	doc groupDoc
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string {
+	return g.doc.Name
+}
+
+// CreatedBy returns the name of the user that created the group.
+func (g *Group) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// DateCreated returns when the group was created in UTC.
+func (g *Group) DateCreated() time.Time {
+	return g.doc.DateCreated.UTC()
+}
+
+// Members returns the users that are members of the group,
+// sorted by id.
+func (g *Group) Members() []names.UserTag {
+	ids := append([]string(nil), g.doc.Members...)
+	sort.Strings(ids)
+	members := make([]names.UserTag, len(ids))
+	for i, id := range ids {
+		members[i] = names.NewUserTag(id)
+	}
+	return members
+}
+
+// Refresh refreshes information about the group from the state.
+func (g *Group) Refresh() error {
+	group, err := g.st.Group(g.doc.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	g.doc = group.doc
+	return nil
+}
+
+// AddGroup adds a group with the given name to the controller.
+func (st *State) AddGroup(name string, createdBy names.UserTag) (*Group, error) {
+	if !names.IsValidUserName(name) {
+		return nil, errors.NotValidf("group name %q", name)
+	}
+	group := &Group{
+		st: st,
+		doc: groupDoc{
+			DocID:       strings.ToLower(name),
+			Name:        name,
+			CreatedBy:   createdBy.Id(),
+			DateCreated: st.nowToTheSecond(),
+			Members:     []string{},
+		},
+	}
+	ops := []txn.Op{{
+		C:      groupsC,
+		Id:     group.doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &group.doc,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return group, nil
+}
+
+// Group returns the group with the given name.
+func (st *State) Group(name string) (*Group, error) {
+	groups, closer := st.db().GetCollection(groupsC)
+	defer closer()
+
+	var doc groupDoc
+	err := groups.FindId(strings.ToLower(name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("group %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get group %q", name)
+	}
+	return &Group{st: st, doc: doc}, nil
+}
+
+// AllGroups returns all the groups in the controller, sorted by name.
+func (st *State) AllGroups() ([]*Group, error) {
+	groups, closer := st.db().GetCollection(groupsC)
+	defer closer()
+
+	var docs []groupDoc
+	if err := groups.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all groups")
+	}
+	result := make([]*Group, len(docs))
+	for i, doc := range docs {
+		result[i] = &Group{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// GroupsForUser returns the names of the groups the user is a member
+// of, sorted by name.
+func (st *State) GroupsForUser(user names.UserTag) ([]string, error) {
+	groups, closer := st.db().GetCollection(groupsC)
+	defer closer()
+
+	var docs []groupDoc
+	err := groups.Find(bson.D{{"members", userAccessID(user)}}).Sort("_id").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get groups for user %q", user.Id())
+	}
+	result := make([]string, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.Name
+	}
+	return result, nil
+}
+
+// RemoveGroup removes the group, and all access granted to it.
+func (st *State) RemoveGroup(name string) error {
+	group, err := st.Group(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	permPattern := bson.M{
+		"subject-global-key": groupGlobalKey(group.doc.Name),
+	}
+	ops, err := st.removeInCollectionOps(permissionsC, permPattern)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      groupsC,
+		Id:     group.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	})
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("group %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// AddGroupMembers adds the given users to the group. Local users
+// must exist; users that are already members are ignored.
+func (st *State) AddGroupMembers(name string, users ...names.UserTag) error {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		if user.IsLocal() {
+			if _, err := st.User(user); err != nil {
+				return errors.Annotatef(err, "user %q does not exist locally", user.Name())
+			}
+		}
+		ids[i] = userAccessID(user)
+	}
+	ops := []txn.Op{{
+		C:      groupsC,
+		Id:     strings.ToLower(name),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$addToSet", bson.D{{"members", bson.D{{"$each", ids}}}}}},
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("group %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// RemoveGroupMembers removes the given users from the group. Users
+// that are not members are ignored.
+func (st *State) RemoveGroupMembers(name string, users ...names.UserTag) error {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = userAccessID(user)
+	}
+	ops := []txn.Op{{
+		C:      groupsC,
+		Id:     strings.ToLower(name),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pullAll", bson.D{{"members", ids}}}},
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("group %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// GroupAccess returns the access granted to the group on the target,
+// which may be a controller, model, cloud or application offer tag.
+// Application offers are looked up in the state's model.
+func (st *State) GroupAccess(name string, target names.Tag) (permission.Access, error) {
+	objectGlobalKey, err := st.permissionObjectGlobalKey(target)
+	if err != nil {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	perm, err := st.userPermission(objectGlobalKey, groupGlobalKey(name))
+	if err != nil {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// SetGroupAccess grants access on the target to all members of the
+// group, replacing any access previously granted to the group. The
+// target may be a controller, model, cloud or application offer tag.
+func (st *State) SetGroupAccess(name string, target names.Tag, access permission.Access) error {
+	if err := validateAccessForTarget(target, access); err != nil {
+		return errors.Trace(err)
+	}
+	objectGlobalKey, err := st.permissionObjectGlobalKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subjectGlobalKey := groupGlobalKey(name)
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Group(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      groupsC,
+			Id:     strings.ToLower(name),
+			Assert: txn.DocExists,
+		}}
+		_, err := st.userPermission(objectGlobalKey, subjectGlobalKey)
+		if errors.IsNotFound(err) {
+			return append(ops, createPermissionOp(objectGlobalKey, subjectGlobalKey, access)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, updatePermissionOp(objectGlobalKey, subjectGlobalKey, access)), nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// RemoveGroupAccess removes the access granted to the group on the
+// target.
+func (st *State) RemoveGroupAccess(name string, target names.Tag) error {
+	objectGlobalKey, err := st.permissionObjectGlobalKey(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subjectGlobalKey := groupGlobalKey(name)
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.userPermission(objectGlobalKey, subjectGlobalKey); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{removePermissionOp(objectGlobalKey, subjectGlobalKey)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// GroupPermission returns the highest access on the target granted
+// to any of the groups the user is a member of. If the user has no
+// access through any group, NoAccess is returned.
+func (st *State) GroupPermission(user names.UserTag, target names.Tag) (permission.Access, error) {
+	groups, err := st.GroupsForUser(user)
+	if err != nil || len(groups) == 0 {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	objectGlobalKey, err := st.permissionObjectGlobalKey(target)
+	if err != nil {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = permissionID(objectGlobalKey, groupGlobalKey(group))
+	}
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	if err := permissions.Find(bson.M{"_id": bson.M{"$in": ids}}).All(&docs); err != nil {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	result := permission.NoAccess
+	for _, doc := range docs {
+		access := stringToAccess(doc.Access)
+		if greaterAccessForTarget(target, access, result) {
+			result = access
+		}
+	}
+	return result, nil
+}
+
+// groupModelUUIDs returns the UUIDs of the models the user has been
+// granted access to through group membership.
+func (st *State) groupModelUUIDs(user names.UserTag) ([]string, error) {
+	groups, err := st.GroupsForUser(user)
+	if err != nil || len(groups) == 0 {
+		return nil, errors.Trace(err)
+	}
+	subjects := make([]string, len(groups))
+	for i, group := range groups {
+		subjects[i] = groupGlobalKey(group)
+	}
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	err = permissions.Find(bson.M{
+		"subject-global-key": bson.M{"$in": subjects},
+		"object-global-key":  bson.M{"$regex": "^" + modelGlobalKey + "#"},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []string
+	for _, doc := range docs {
+		result = append(result, strings.TrimPrefix(doc.ObjectGlobalKey, modelGlobalKey+"#"))
+	}
+	return result, nil
+}
+
+// groupPermissions returns the access granted to groups on the object
+// with the given global key, keyed by group id.
+func (st *State) groupPermissions(objectGlobalKey string) (map[string]permission.Access, error) {
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	err := permissions.Find(bson.M{
+		"object-global-key":  objectGlobalKey,
+		"subject-global-key": bson.M{"$regex": "^" + groupGlobalKeyPrefix + "#"},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]permission.Access, len(docs))
+	for _, doc := range docs {
+		id := strings.TrimPrefix(doc.SubjectGlobalKey, groupGlobalKeyPrefix+"#")
+		result[id] = stringToAccess(doc.Access)
+	}
+	return result, nil
+}
+
+// modelMigrationGroups returns the groups granted access to the model,
+// and to its application offers if includeOffers is true, as they are
+// carried through a migration of the model.
+func (st *State) modelMigrationGroups(includeOffers bool) ([]migrationGroup, error) {
+	modelAccess, err := st.groupPermissions(modelKey(st.ModelUUID()))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var offers []*crossmodel.ApplicationOffer
+	if includeOffers {
+		offers, err = NewApplicationOffers(st).AllApplicationOffers()
+		if err != nil {
+			return nil, errors.Annotate(err, "listing offers")
+		}
+	}
+	offerAccess := make(map[string]map[string]permission.Access)
+	for _, offer := range offers {
+		access, err := st.groupPermissions(applicationOfferKey(offer.OfferUUID))
+		if err != nil {
+			return nil, errors.Annotatef(err, "group access for offer %s", offer.OfferName)
+		}
+		for id, a := range access {
+			if offerAccess[id] == nil {
+				offerAccess[id] = make(map[string]permission.Access)
+			}
+			offerAccess[id][offer.OfferUUID] = a
+		}
+	}
+
+	ids := set.NewStrings()
+	for id := range modelAccess {
+		ids.Add(id)
+	}
+	for id := range offerAccess {
+		ids.Add(id)
+	}
+	var groups []migrationGroup
+	for _, id := range ids.SortedValues() {
+		group, err := st.Group(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		g := migrationGroup{
+			Name:        group.Name(),
+			CreatedBy:   group.CreatedBy(),
+			DateCreated: group.DateCreated(),
+			ModelAccess: string(modelAccess[id]),
+		}
+		for _, member := range group.Members() {
+			g.Members = append(g.Members, member.Id())
+		}
+		for offerUUID, access := range offerAccess[id] {
+			if g.OfferAccess == nil {
+				g.OfferAccess = make(map[string]string)
+			}
+			g.OfferAccess[offerUUID] = string(access)
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// ModelGroupMembers returns the names of the members of the groups
+// granted access to the model or to its application offers, sorted by
+// name. The groups are migrated with the model, so their members are
+// checked against the migration target's users like the model's users.
+func (st *State) ModelGroupMembers() ([]string, error) {
+	groups, err := st.modelMigrationGroups(true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	members := set.NewStrings()
+	for _, g := range groups {
+		members = members.Union(set.NewStrings(g.Members...))
+	}
+	return members.SortedValues(), nil
+}
+
+// removeImportedGroupsOps returns the operations removing the groups created
+// by the import of the model with the given UUID, and all access
+// granted to them, when the import is aborted.
+func (st *State) removeImportedGroupsOps(modelUUID string) ([]txn.Op, error) {
+	groups, closer := st.db().GetCollection(groupsC)
+	defer closer()
+
+	var docs []groupDoc
+	if err := groups.Find(bson.D{{"importing-model-uuid", modelUUID}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get imported groups")
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		permOps, err := st.removeInCollectionOps(permissionsC, bson.M{
+			"subject-global-key": groupGlobalKey(doc.Name),
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, permOps...)
+		ops = append(ops, txn.Op{
+			C:      groupsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"importing-model-uuid", modelUUID}},
+			Remove: true,
+		})
+	}
+	return ops, nil
+}
+
+// completeImportedGroupsOps returns the operations keeping the groups
+// created by the import of the model with the given UUID once the
+// import has completed.
+func (st *State) completeImportedGroupsOps(modelUUID string) ([]txn.Op, error) {
+	groups, closer := st.db().GetCollection(groupsC)
+	defer closer()
+
+	var docs []groupDoc
+	if err := groups.Find(bson.D{{"importing-model-uuid", modelUUID}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get imported groups")
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      groupsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$unset", bson.D{{"importing-model-uuid", 1}}}},
+		}
+	}
+	return ops, nil
+}
+
+// permissionObjectGlobalKey returns the object global key used for
+// permissions on the given target.
+func (st *State) permissionObjectGlobalKey(target names.Tag) (string, error) {
+	switch target.Kind() {
+	case names.ControllerTagKind:
+		return controllerKey(st.ControllerUUID()), nil
+	case names.ModelTagKind:
+		return modelKey(target.Id()), nil
+	case names.CloudTagKind:
+		return cloudGlobalKey(target.Id()), nil
+	case names.ApplicationOfferTagKind:
+		offerUUID, err := applicationOfferUUID(st, target.Id())
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return applicationOfferKey(offerUUID), nil
+	}
+	return "", errors.NotValidf("%q as a target", target.Kind())
+}
+
+func validateAccessForTarget(target names.Tag, access permission.Access) error {
+	switch target.Kind() {
+	case names.ControllerTagKind:
+		return permission.ValidateControllerAccess(access)
+	case names.ModelTagKind:
+		return permission.ValidateModelAccess(access)
+	case names.CloudTagKind:
+		return permission.ValidateCloudAccess(access)
+	case names.ApplicationOfferTagKind:
+		return permission.ValidateOfferAccess(access)
+	}
+	return errors.NotValidf("%q as a target", target.Kind())
+}
+
+// greaterAccessForTarget reports whether a is greater access than b
+// on the kind of target given.
+func greaterAccessForTarget(target names.Tag, a, b permission.Access) bool {
+	switch target.Kind() {
+	case names.ControllerTagKind:
+		return a.GreaterControllerAccessThan(b)
+	case names.ModelTagKind:
+		return a.GreaterModelAccessThan(b)
+	case names.CloudTagKind:
+		return a.EqualOrGreaterCloudAccessThan(b) && a != b
+	case names.ApplicationOfferTagKind:
+		return a.GreaterOfferAccessThan(b)
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/testing/factory"
+)
+
+type GroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&GroupSuite{})
+
+func (s *GroupSuite) TestAddGroup(c *gc.C) {
+	group, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Name(), gc.Equals, "engineers")
+	c.Assert(group.CreatedBy(), gc.Equals, s.Owner.Id())
+	c.Assert(group.Members(), gc.HasLen, 0)
+
+	_, err = s.State.AddGroup("Engineers", s.Owner)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	_, err = s.State.AddGroup("not/valid", s.Owner)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *GroupSuite) TestGroupMembers(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	bob := names.NewUserTag("bob@external")
+	_, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AddGroupMembers("engineers", alice, bob)
+	c.Assert(err, jc.ErrorIsNil)
+	group, err := s.State.Group("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{alice, bob})
+
+	groups, err := s.State.GroupsForUser(alice)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"engineers"})
+
+	err = s.State.RemoveGroupMembers("engineers", alice)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Refresh(), jc.ErrorIsNil)
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{bob})
+
+	err = s.State.AddGroupMembers("engineers", names.NewUserTag("nobody"))
+	c.Assert(err, gc.ErrorMatches, `user "nobody" does not exist locally: .*`)
+	err = s.State.AddGroupMembers("missing", alice)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GroupSuite) TestGroupModelAccess(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", NoModelUser: true}).UserTag()
+	_, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.AddGroupMembers("engineers", alice), jc.ErrorIsNil)
+
+	modelTag := s.Model.ModelTag()
+	_, err = s.State.UserPermission(alice, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetGroupAccess("engineers", modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.GroupAccess("engineers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+	access, err = s.State.UserPermission(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	uuids, err := s.State.ModelUUIDsForUser(alice)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{s.Model.UUID()})
+
+	err = s.State.SetGroupAccess("engineers", modelTag, permission.SuperuserAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.RemoveGroupAccess("engineers", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserPermission(alice, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GroupSuite) TestGroupAccessTakesHighest(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", Access: permission.ReadAccess}).UserTag()
+	_, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.AddGroupMembers("engineers", alice), jc.ErrorIsNil)
+
+	modelTag := s.Model.ModelTag()
+	err = s.State.SetGroupAccess("engineers", modelTag, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserPermission(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (s *GroupSuite) TestGroupAccessDisabledUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", NoModelUser: true})
+	_, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.AddGroupMembers("engineers", user.UserTag()), jc.ErrorIsNil)
+	err = s.State.SetGroupAccess("engineers", s.State.ControllerTag(), permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(user.Disable(), jc.ErrorIsNil)
+	_, err = s.State.UserPermission(user.UserTag(), s.State.ControllerTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GroupSuite) TestRemoveGroup(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", NoModelUser: true}).UserTag()
+	_, err := s.State.AddGroup("engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.AddGroupMembers("engineers", alice), jc.ErrorIsNil)
+	err = s.State.SetGroupAccess("engineers", s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveGroup("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Group("engineers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.UserPermission(alice, s.Model.ModelTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveGroup("engineers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GroupSuite) TestModelGroupMembers(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	bob := names.NewUserTag("bob@external")
+	for _, name := range []string{"engineers", "managers", "outsiders"} {
+		_, err := s.State.AddGroup(name, s.Owner)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.State.AddGroupMembers("engineers", alice, bob)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddGroupMembers("managers", alice)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddGroupMembers("outsiders", names.NewUserTag("mallory@external"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetGroupAccess("engineers", s.Model.ModelTag(), permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetGroupAccess("managers", s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	members, err := s.State.ModelGroupMembers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(members, jc.DeepEquals, []string{"alice", "bob@external"})
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/network"
 	stateerrors "github.com/juju/juju/state/errors"
 	"github.com/juju/juju/tools"
+	jujuversion "github.com/juju/juju/version"
 )
 
 // Machine represents the state of a machine.
@@ -144,6 +145,14 @@ type machineDoc struct {
 
 	// AgentStartedAt records the time when the machine agent started.
 	AgentStartedAt time.Time `bson:"agent-started-at,omitempty"`
+
+	// CloudInitUserData holds cloud-init user data YAML that is merged
+	// into the user data of the machine's instance when it is provisioned.
+	CloudInitUserData string `bson:"cloudinit-userdata,omitempty"`
+
+	// TargetAgentVersion holds the agent version that the machine's
+	// agents are upgraded to ahead of the rest of the model.
+	TargetAgentVersion string `bson:"target-agent-version,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -245,6 +254,7 @@ type instanceData struct {
 	CpuPower       *uint64     `bson:"cpupower,omitempty"`
 	Tags           *[]string   `bson:"tags,omitempty"`
 	AvailZone      *string     `bson:"availzone,omitempty"`
+	Image          *string     `bson:"image,omitempty"`
 
 	// KeepInstance is set to true if, on machine removal from Juju,
 	// the cloud instance should be retained.
@@ -265,6 +275,7 @@ func hardwareCharacteristics(instData instanceData) *instance.HardwareCharacteri
 		CpuPower:         instData.CpuPower,
 		Tags:             instData.Tags,
 		AvailabilityZone: instData.AvailZone,
+		Image:            instData.Image,
 	}
 }
 
@@ -557,6 +568,58 @@ func (m *Machine) SetAgentVersion(v version.Binary) (err error) {
 	return nil
 }
 
+// TargetAgentVersion returns the agent version staged for the machine
+// by SetTargetAgentVersion, and whether one has been staged. A staged
+// version only applies while it is later than the model agent version.
+func (m *Machine) TargetAgentVersion() (version.Number, bool) {
+	if m.doc.TargetAgentVersion == "" {
+		return version.Zero, false
+	}
+	v, err := version.Parse(m.doc.TargetAgentVersion)
+	if err != nil {
+		logger.Warningf("invalid target agent version %q for machine %v", m.doc.TargetAgentVersion, m)
+		return version.Zero, false
+	}
+	return v, true
+}
+
+// SetTargetAgentVersion stages an upgrade of the machine's agents, and
+// the agents of the units it hosts, to the given version ahead of the
+// rest of the model. The version must be later than the model agent
+// version. Controller machines can't be staged; they are upgraded
+// together by changing the agent version of the controller model.
+func (m *Machine) SetTargetAgentVersion(v version.Number) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot stage agent version %s for machine %v", v, m)
+	if m.IsManager() {
+		return errors.NotSupportedf("staging the upgrade of a controller machine")
+	}
+	if v.Compare(jujuversion.Current) > 0 && !m.st.IsController() {
+		return errors.Errorf("version is later than the controller version %s", jujuversion.Current)
+	}
+	model, err := m.st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := model.AgentVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if v.Compare(current) <= 0 {
+		return errors.Errorf("version is not later than the model agent version %s", current)
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"target-agent-version", v.String()}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, machineNotAliveErr)
+	}
+	m.doc.TargetAgentVersion = v.String()
+	return nil
+}
+
 func (m *Machine) setAgentVersionOps(v version.Binary) ([]txn.Op, *tools.Tools, error) {
 	if err := checkVersionValidity(v); err != nil {
 		return nil, nil, err
@@ -1307,6 +1370,7 @@ func (m *Machine) SetProvisioned(
 		CpuPower:       characteristics.CpuPower,
 		Tags:           characteristics.Tags,
 		AvailZone:      characteristics.AvailabilityZone,
+		Image:          characteristics.Image,
 	}
 
 	ops := []txn.Op{
@@ -1771,6 +1835,12 @@ func (m *Machine) Placement() string {
 	return m.doc.Placement
 }
 
+// CloudInitUserData returns the cloud-init user data YAML that was
+// specified for the machine when it was added.
+func (m *Machine) CloudInitUserData() string {
+	return m.doc.CloudInitUserData
+}
+
 // Constraints returns the exact constraints that should apply when provisioning
 // an instance for the machine.
 func (m *Machine) Constraints() (constraints.Value, error) {
@@ -60,6 +60,13 @@ type MachineSubnetPorts interface {
 	// not be ignored.
 	OpenClosePortsOperation(unitName string, openPortRanges, closePortRanges []network.PortRange) (ModelOperation, error)
 
+	// OpenClosePortsOnEndpointsOperation creates a ModelOperation that
+	// opens the port ranges keyed by the name of the unit endpoint they
+	// are opened for, and closes the requested set of port ranges. Port
+	// ranges keyed by an empty endpoint name are opened for all of the
+	// unit's endpoints.
+	OpenClosePortsOnEndpointsOperation(unitName string, openPortRanges map[string][]network.PortRange, closePortRanges []network.PortRange) (ModelOperation, error)
+
 	// PortRangesByUnit returns the set of port ranges opened by each unit
 	// in a particular machine subnet grouped by unit name.
 	PortRangesByUnit() map[string][]network.PortRange
@@ -67,6 +74,12 @@ type MachineSubnetPorts interface {
 	// PortRangesForUnit returns the set of port ranges opened by the
 	// specified unit in a particular machine subnet.
 	PortRangesForUnit(unitName string) []network.PortRange
+
+	// PortRangeEndpointsForUnit returns the name of the endpoint each
+	// port range opened by the specified unit in a particular machine
+	// subnet was opened for. The name is empty for port ranges opened
+	// for all of the unit's endpoints.
+	PortRangeEndpointsForUnit(unitName string) map[network.PortRange]string
 }
 
 // portsDoc represents the state of ports opened on machines for networks
@@ -86,6 +99,9 @@ type portRangeDoc struct {
 	FromPort int
 	ToPort   int
 	Protocol string
+	// Endpoint is the name of the unit endpoint the port range is
+	// opened for, or empty if it is opened for all of them.
+	Endpoint string `bson:"endpoint,omitempty"`
 }
 
 func (doc portRangeDoc) asPortRange() network.PortRange {
@@ -129,10 +145,21 @@ func (p *machineSubnetPorts) Persisted() bool {
 // requested set of PortRange arguments. Each of the PortRange arguments can be
 // left empty to indicate that they should not be ignored.
 func (p *machineSubnetPorts) OpenClosePortsOperation(unitName string, openPortRanges, closePortRanges []network.PortRange) (ModelOperation, error) {
+	return p.OpenClosePortsOnEndpointsOperation(unitName, map[string][]network.PortRange{"": openPortRanges}, closePortRanges)
+}
+
+// OpenClosePortsOnEndpointsOperation creates a ModelOperation that opens
+// the port ranges keyed by the name of the unit endpoint they are opened
+// for, and closes the requested set of port ranges. Opening a port range
+// that the unit already opened for another endpoint moves it to the new
+// endpoint.
+func (p *machineSubnetPorts) OpenClosePortsOnEndpointsOperation(unitName string, openPortRanges map[string][]network.PortRange, closePortRanges []network.PortRange) (ModelOperation, error) {
 	// pre-flight checks
-	for _, r := range openPortRanges {
-		if err := r.Validate(); err != nil {
-			return nil, errors.Annotatef(errors.Trace(err), "cannot open ports %v", r)
+	for _, ranges := range openPortRanges {
+		for _, r := range ranges {
+			if err := r.Validate(); err != nil {
+				return nil, errors.Annotatef(errors.Trace(err), "cannot open ports %v", r)
+			}
 		}
 	}
 	for _, r := range closePortRanges {
@@ -176,6 +203,19 @@ func (p *machineSubnetPorts) PortRangesForUnit(unitName string) []network.PortRa
 	return res
 }
 
+// PortRangeEndpointsForUnit returns the name of the endpoint each port
+// range opened by the specified unit on the subnet represented by this
+// MachineSubnetPorts instance was opened for.
+func (p *machineSubnetPorts) PortRangeEndpointsForUnit(unitName string) map[network.PortRange]string {
+	res := make(map[network.PortRange]string)
+	for _, pr := range p.doc.Ports {
+		if pr.UnitName == unitName {
+			res[pr.asPortRange()] = pr.Endpoint
+		}
+	}
+	return res
+}
+
 // Refresh refreshes the port document from state.
 func (p *machineSubnetPorts) Refresh() error {
 	openedPorts, closer := p.st.db().GetCollection(openedPortsC)
@@ -4,6 +4,8 @@
 package state
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/juju/core/network"
 	jujutxn "github.com/juju/txn"
@@ -16,9 +18,11 @@ var (
 )
 
 type openClosePortsOperation struct {
-	p               *machineSubnetPorts
-	unitName        string
-	openPortRanges  []network.PortRange
+	p        *machineSubnetPorts
+	unitName string
+	// openPortRanges holds the port ranges to open keyed by the name
+	// of the endpoint they are opened for.
+	openPortRanges  map[string][]network.PortRange
 	closePortRanges []network.PortRange
 
 	updatedPortList []portRangeDoc
@@ -85,30 +89,44 @@ func (op *openClosePortsOperation) Build(attempt int) ([]txn.Op, error) {
 
 // addPortDocsForOpenedPortRanges compares the set of new port ranges to open
 // to the set of currently opened port range documents and appends a document
-// for each port range that is not present in the current list. The method
-// returns a boolean value to indicate whether new documents were generated.
+// for each port range that is not present in the current list. The endpoint
+// of a port range that is already present is updated if it differs. The
+// method returns a boolean value to indicate whether the documents were
+// modified.
 func (op *openClosePortsOperation) addPortDocsForOpenedPortRanges() (bool, error) {
+	endpoints := make([]string, 0, len(op.openPortRanges))
+	for endpoint := range op.openPortRanges {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
 	var portListModified bool
-	for _, openPortRange := range op.openPortRanges {
-		var alreadyExists bool
-		for _, existing := range op.updatedPortList {
-			identical, err := checkForPortRangeConflict(existing.asPortRange(), existing.UnitName, openPortRange, op.unitName)
-			if err != nil {
-				return false, errors.Annotatef(err, "cannot open ports %v", openPortRange)
-			} else if identical {
-				alreadyExists = true
-				break // nothing to do
+	for _, endpoint := range endpoints {
+		for _, openPortRange := range op.openPortRanges[endpoint] {
+			existingIndex := -1
+			for i, existing := range op.updatedPortList {
+				identical, err := checkForPortRangeConflict(existing.asPortRange(), existing.UnitName, openPortRange, op.unitName)
+				if err != nil {
+					return false, errors.Annotatef(err, "cannot open ports %v", openPortRange)
+				} else if identical {
+					existingIndex = i
+					break
+				}
 			}
-		}
 
-		if !alreadyExists {
-			op.updatedPortList = append(op.updatedPortList, portRangeDoc{
-				FromPort: openPortRange.FromPort,
-				ToPort:   openPortRange.ToPort,
-				Protocol: openPortRange.Protocol,
-				UnitName: op.unitName,
-			})
-			portListModified = true
+			if existingIndex == -1 {
+				op.updatedPortList = append(op.updatedPortList, portRangeDoc{
+					FromPort: openPortRange.FromPort,
+					ToPort:   openPortRange.ToPort,
+					Protocol: openPortRange.Protocol,
+					UnitName: op.unitName,
+					Endpoint: endpoint,
+				})
+				portListModified = true
+			} else if op.updatedPortList[existingIndex].Endpoint != endpoint {
+				op.updatedPortList[existingIndex].Endpoint = endpoint
+				portListModified = true
+			}
 		}
 	}
 
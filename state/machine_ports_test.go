@@ -383,6 +383,47 @@ func (s *MachinePortsDocSuite) TestComposedOpenCloseOperationNoEffectiveOps(c *g
 	c.Assert(err, gc.Equals, jujutxn.ErrNoOperations)
 }
 
+func (s *MachinePortsDocSuite) TestOpenPortsOnEndpoints(c *gc.C) {
+	op, err := s.portsWithoutSubnet.OpenClosePortsOnEndpointsOperation(s.unit1.Name(),
+		map[string][]network.PortRange{
+			"":    {network.MustParsePortRange("22/tcp")},
+			"url": {network.MustParsePortRange("80/tcp")},
+		},
+		nil,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.ApplyOperation(op), jc.ErrorIsNil)
+
+	assertRefreshMachinePortsDoc(c, s.portsWithoutSubnet, nil)
+	c.Assert(s.portsWithoutSubnet.PortRangeEndpointsForUnit(s.unit1.Name()), jc.DeepEquals, map[network.PortRange]string{
+		network.MustParsePortRange("22/tcp"): "",
+		network.MustParsePortRange("80/tcp"): "url",
+	})
+
+	// Opening a range again for another endpoint moves it there.
+	op, err = s.portsWithoutSubnet.OpenClosePortsOnEndpointsOperation(s.unit1.Name(),
+		map[string][]network.PortRange{
+			"db": {network.MustParsePortRange("80/tcp")},
+		},
+		nil,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.State.ApplyOperation(op), jc.ErrorIsNil)
+
+	assertRefreshMachinePortsDoc(c, s.portsWithoutSubnet, nil)
+	c.Assert(s.portsWithoutSubnet.PortRangeEndpointsForUnit(s.unit1.Name()), jc.DeepEquals, map[network.PortRange]string{
+		network.MustParsePortRange("22/tcp"): "",
+		network.MustParsePortRange("80/tcp"): "db",
+	})
+}
+
+func (s *MachinePortsDocSuite) TestOpenPortsOnUnknownEndpoint(c *gc.C) {
+	_, err := s.unit1.OpenClosePortsOnEndpointsOperation(map[string][]network.PortRange{
+		"bogus": {network.MustParsePortRange("80/tcp")},
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot open ports for unit "wordpress/0": application "wordpress" has no "bogus" relation`)
+}
+
 func (s *MachinePortsDocSuite) TestICMP(c *gc.C) {
 	// Open initial port range
 	toOpen := []network.PortRange{network.MustParsePortRange("icmp")}
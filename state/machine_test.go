@@ -245,6 +245,51 @@ func (s *MachineSuite) TestParentId(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetTargetAgentVersion(c *gc.C) {
+	_, ok := s.machine.TargetAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+
+	current, err := s.Model.AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	target := current
+	target.Patch++
+	err = s.machine.SetTargetAgentVersion(target)
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok := s.machine.TargetAgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, target)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	v, ok = s.machine.TargetAgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, target)
+}
+
+func (s *MachineSuite) TestSetTargetAgentVersionNotLater(c *gc.C) {
+	current, err := s.Model.AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetTargetAgentVersion(current)
+	c.Assert(err, gc.ErrorMatches, `cannot stage agent version .* for machine 1: version is not later than the model agent version .*`)
+	_, ok := s.machine.TargetAgentVersion()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetTargetAgentVersionController(c *gc.C) {
+	err := s.machine0.SetTargetAgentVersion(version.MustParse("9.9.9"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MachineSuite) TestSetTargetAgentVersionDeadMachine(c *gc.C) {
+	current, err := s.Model.AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	current.Patch++
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetTargetAgentVersion(current)
+	c.Assert(err, gc.ErrorMatches, `cannot stage agent version .* for machine 1: machine is not found or not alive`)
+}
+
 func (s *MachineSuite) TestMachineIsManager(c *gc.C) {
 	c.Assert(s.machine0.IsManager(), jc.IsTrue)
 	c.Assert(s.machine.IsManager(), jc.IsFalse)
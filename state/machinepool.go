@@ -0,0 +1,615 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	stateerrors "github.com/juju/juju/state/errors"
+)
+
+var validMachinePoolName = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
+
+// IsValidMachinePoolName reports whether name is a valid machine pool
+// name.
+func IsValidMachinePoolName(name string) bool {
+	return validMachinePoolName.MatchString(name)
+}
+
+// machinePoolDoc represents a named group of machines, started with
+// the same constraints, that the provisioner keeps at a target size.
+type machinePoolDoc struct {
+	DocID      string   `bson:"_id"`
+	Name       string   `bson:"name"`
+	ModelUUID  string   `bson:"model-uuid"`
+	Series     string   `bson:"series"`
+	Zones      []string `bson:"zones,omitempty"`
+	TargetSize int      `bson:"target-size"`
+	Machines   []string `bson:"machines"`
+	TxnRevno   int64    `bson:"txn-revno"`
+}
+
+// MachinePool represents a named group of machines, started with the
+// same constraints and spread across availability zones, that the
+// provisioner keeps at a target size.
+type MachinePool struct {
+	st  *State
+	doc machinePoolDoc
+}
+
+// MachinePoolArgs holds the parameters for adding a machine pool.
+type MachinePoolArgs struct {
+	// Name is the name of the pool.
+	Name string
+
+	// Series is the series of the pool's machines.
+	Series string
+
+	// Constraints are the constraints used to start the pool's
+	// machines.
+	Constraints constraints.Value
+
+	// Zones holds the availability zones across which the pool's
+	// machines are spread. If empty, the provisioner distributes
+	// the machines as it does any other.
+	Zones []string
+
+	// TargetSize is the number of machines the pool is kept at.
+	TargetSize int
+}
+
+func machinePoolGlobalKey(name string) string {
+	return "mp#" + name
+}
+
+// Name returns the name of the pool.
+func (p *MachinePool) Name() string {
+	return p.doc.Name
+}
+
+// Series returns the series of the pool's machines.
+func (p *MachinePool) Series() string {
+	return p.doc.Series
+}
+
+// Zones returns the availability zones across which the pool's
+// machines are spread.
+func (p *MachinePool) Zones() []string {
+	return append([]string(nil), p.doc.Zones...)
+}
+
+// TargetSize returns the number of machines the pool is kept at.
+func (p *MachinePool) TargetSize() int {
+	return p.doc.TargetSize
+}
+
+// MachineIds returns the ids of the machines in the pool.
+func (p *MachinePool) MachineIds() []string {
+	return append([]string(nil), p.doc.Machines...)
+}
+
+// Constraints returns the constraints used to start the pool's
+// machines.
+func (p *MachinePool) Constraints() (constraints.Value, error) {
+	return readConstraints(p.st, machinePoolGlobalKey(p.doc.Name))
+}
+
+// Refresh refreshes the contents of the pool from the underlying
+// state.
+func (p *MachinePool) Refresh() error {
+	pools, closer := p.st.db().GetCollection(machinePoolsC)
+	defer closer()
+
+	err := pools.FindId(p.doc.Name).One(&p.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("machine pool %q", p.doc.Name)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh machine pool %q", p.doc.Name)
+	}
+	return nil
+}
+
+// SetTargetSize sets the number of machines the pool is kept at.
+func (p *MachinePool) SetTargetSize(size int) error {
+	if size < 0 {
+		return errors.NotValidf("machine pool size %d", size)
+	}
+	ops := []txn.Op{{
+		C:      machinePoolsC,
+		Id:     p.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"target-size", size}}}},
+	}}
+	err := p.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("machine pool %q", p.doc.Name)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set size of machine pool %q", p.doc.Name)
+	}
+	p.doc.TargetSize = size
+	return nil
+}
+
+// Remove removes the pool. Its machines are left in place, and are
+// no longer replaced when lost.
+func (p *MachinePool) Remove() error {
+	ops := []txn.Op{{
+		C:      machinePoolsC,
+		Id:     p.doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}, removeConstraintsOp(machinePoolGlobalKey(p.doc.Name))}
+	err := p.st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("machine pool %q", p.doc.Name)
+	}
+	return errors.Annotatef(err, "cannot remove machine pool %q", p.doc.Name)
+}
+
+// AddMachinePool adds a machine pool to the model. The pool's machines
+// are started by the provisioner when it next reconciles the pools.
+func (st *State) AddMachinePool(args MachinePoolArgs) (_ *MachinePool, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add machine pool %q", args.Name)
+	if !IsValidMachinePoolName(args.Name) {
+		return nil, errors.NotValidf("machine pool name")
+	}
+	if args.Series == "" {
+		return nil, errors.New("no series specified")
+	}
+	if args.TargetSize < 0 {
+		return nil, errors.NotValidf("machine pool size %d", args.TargetSize)
+	}
+	if set.NewStrings(args.Zones...).Size() != len(args.Zones) {
+		return nil, errors.NotValidf("duplicate zones")
+	}
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Type() != ModelTypeIAAS {
+		return nil, errors.NotSupportedf("machine pools on %s models", model.Type())
+	}
+	unsupported, err := st.validateConstraints(args.Constraints)
+	if len(unsupported) > 0 {
+		logger.Warningf(
+			"adding machine pool %q: unsupported constraints: %v", args.Name, unsupported)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := machinePoolDoc{
+		DocID:      st.docID(args.Name),
+		Name:       args.Name,
+		ModelUUID:  st.ModelUUID(),
+		Series:     args.Series,
+		Zones:      args.Zones,
+		TargetSize: args.TargetSize,
+		Machines:   []string{},
+	}
+	ops := []txn.Op{
+		assertModelActiveOp(st.ModelUUID()),
+		{
+			C:      machinePoolsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		},
+		createConstraintsOp(machinePoolGlobalKey(args.Name), args.Constraints),
+	}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		if err := checkModelActive(st); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, errors.AlreadyExistsf("machine pool %q", args.Name)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachinePool{st: st, doc: doc}, nil
+}
+
+// MachinePool returns the machine pool with the given name.
+func (st *State) MachinePool(name string) (*MachinePool, error) {
+	pools, closer := st.db().GetCollection(machinePoolsC)
+	defer closer()
+
+	var doc machinePoolDoc
+	err := pools.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("machine pool %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get machine pool %q", name)
+	}
+	return &MachinePool{st: st, doc: doc}, nil
+}
+
+// AllMachinePools returns all the machine pools in the model, sorted
+// by name.
+func (st *State) AllMachinePools() ([]*MachinePool, error) {
+	pools, closer := st.db().GetCollection(machinePoolsC)
+	defer closer()
+
+	var docs []machinePoolDoc
+	if err := pools.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all machine pools")
+	}
+	result := make([]*MachinePool, len(docs))
+	for i, doc := range docs {
+		result[i] = &MachinePool{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// ReconcileMachinePools reconciles each machine pool in the model, as
+// described by MachinePool.Reconcile.
+func (st *State) ReconcileMachinePools(maxWait time.Duration) error {
+	pools, err := st.AllMachinePools()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, pool := range pools {
+		if err := pool.Reconcile(maxWait); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Reconcile brings the pool to its target size. Machines whose
+// instance the instance poller reports as lost are replaced, and the
+// principal units on them of applications configured as stateless
+// are placed again on the replacement. The lost machine is force
+// destroyed if all of its units were placed again; otherwise it is
+// left for the operator, outside the pool. Machines that are not
+// alive are dropped from the pool, and when the pool is larger than
+// its target size, machines without units or containers are
+// destroyed.
+func (p *MachinePool) Reconcile(maxWait time.Duration) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot reconcile machine pool %q", p.doc.Name)
+	if err := p.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	var kept, members, lost []*Machine
+	for _, id := range p.doc.Machines {
+		m, err := p.st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if m.Life() != Alive {
+			continue
+		}
+		instStatus, err := m.InstanceStatus()
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		kept = append(kept, m)
+		if instStatus.Status == status.Lost {
+			lost = append(lost, m)
+			continue
+		}
+		members = append(members, m)
+	}
+	if len(kept) != len(p.doc.Machines) {
+		if err := p.setMachines(kept, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	// Lost machines stay in the pool until their replacement is added,
+	// in the same transaction, so that a failure part way through leaves
+	// them to be replaced by the next reconciliation.
+	for _, m := range lost {
+		logger.Infof("replacing lost machine %s in machine pool %q", m.Id(), p.doc.Name)
+		replacement, err := p.addMachine(members, m.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		members = append(members, replacement)
+		if err := p.replaceUnits(m, replacement, maxWait); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	for len(members) < p.doc.TargetSize {
+		m, err := p.addMachine(members, "")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("added machine %s to machine pool %q", m.Id(), p.doc.Name)
+		members = append(members, m)
+	}
+
+	if len(members) > p.doc.TargetSize {
+		// Remove the newest machines first, as they are the most
+		// likely not to be provisioned yet.
+		candidates := append([]*Machine(nil), members...)
+		sort.Sort(sort.Reverse(machinesById(candidates)))
+		var removed []*Machine
+		for _, m := range candidates {
+			if len(members)-len(removed) <= p.doc.TargetSize {
+				break
+			}
+			err := m.Destroy()
+			if stateerrors.IsHasAssignedUnitsError(err) || stateerrors.IsHasContainersError(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			logger.Infof("removed machine %s from machine pool %q", m.Id(), p.doc.Name)
+			removed = append(removed, m)
+		}
+		if len(removed) > 0 {
+			if err := p.setMachines(members, removed); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// setMachinesOp returns the operation that sets the ids of the pool's
+// machines, asserting that they have not changed since the pool was
+// read.
+func (p *MachinePool) setMachinesOp(ids []string) txn.Op {
+	return txn.Op{
+		C:      machinePoolsC,
+		Id:     p.doc.DocID,
+		Assert: bson.D{{"txn-revno", p.doc.TxnRevno}},
+		Update: bson.D{{"$set", bson.D{{"machines", ids}}}},
+	}
+}
+
+// setMachines sets the pool's machines to the given machines, less
+// the excluded ones.
+func (p *MachinePool) setMachines(machines, exclude []*Machine) error {
+	excluded := set.NewStrings()
+	for _, m := range exclude {
+		excluded.Add(m.Id())
+	}
+	ids := []string{}
+	for _, m := range machines {
+		if !excluded.Contains(m.Id()) {
+			ids = append(ids, m.Id())
+		}
+	}
+	if err := p.st.db().RunTransaction([]txn.Op{p.setMachinesOp(ids)}); err != nil {
+		return errors.Trace(onAbort(err, errors.New("machine pool changed during reconciliation")))
+	}
+	return errors.Trace(p.Refresh())
+}
+
+// addMachine adds a machine to the pool, in the zone that is least
+// used by its current members, replacing the machine with the given
+// id if it is not empty.
+func (p *MachinePool) addMachine(members []*Machine, replacing string) (*Machine, error) {
+	cons, err := p.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zone, err := p.nextZone(members)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone != "" {
+		zones := []string{zone}
+		cons.Zones = &zones
+	}
+	template := MachineTemplate{
+		Series:      p.doc.Series,
+		Constraints: cons,
+		Jobs:        []MachineJob{JobHostUnits},
+	}
+	var mdoc *machineDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := p.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		var ids []string
+		for _, id := range p.doc.Machines {
+			if id != replacing {
+				ids = append(ids, id)
+			}
+		}
+		var ops []txn.Op
+		mdoc, ops, err = p.st.addMachineOps(template)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, p.setMachinesOp(append(ids, mdoc.Id))), nil
+	}
+	if err := p.st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotate(err, "cannot add machine")
+	}
+	if err := p.Refresh(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newMachine(p.st, mdoc), nil
+}
+
+// nextZone returns the zone, of those the pool spreads its machines
+// across, that is used by the fewest of the given machines.
+func (p *MachinePool) nextZone(members []*Machine) (string, error) {
+	if len(p.doc.Zones) == 0 {
+		return "", nil
+	}
+	counts := make(map[string]int)
+	for _, m := range members {
+		cons, err := m.Constraints()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if cons.Zones != nil && len(*cons.Zones) == 1 {
+			counts[(*cons.Zones)[0]]++
+		}
+	}
+	zone := p.doc.Zones[0]
+	for _, z := range p.doc.Zones[1:] {
+		if counts[z] < counts[zone] {
+			zone = z
+		}
+	}
+	return zone, nil
+}
+
+// replaceUnits places the principal units on the lost machine, or in
+// containers on it, of applications configured as stateless, again
+// on the replacement machine, and destroys the lost ones. The lost
+// machine is force destroyed if all of its units were placed again.
+func (p *MachinePool) replaceUnits(lost, replacement *Machine, maxWait time.Duration) error {
+	units, err := hostPrincipalUnits(p.st, lost.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	replaced := true
+	stateless := make(map[string]bool)
+	for _, unit := range units {
+		appName := unit.ApplicationName()
+		isStateless, ok := stateless[appName]
+		if !ok {
+			if isStateless, err = applicationIsStateless(p.st, appName); err != nil {
+				return errors.Trace(err)
+			}
+			stateless[appName] = isStateless
+		}
+		if !isStateless {
+			logger.Warningf("unit %q on lost machine %s is not stateless and was not placed again", unit.Name(), lost.Id())
+			replaced = false
+			continue
+		}
+		if err := p.replaceUnit(unit, replacement, maxWait); err != nil {
+			logger.Warningf("cannot place unit %q from lost machine %s again: %v", unit.Name(), lost.Id(), err)
+			replaced = false
+		}
+	}
+	if !replaced {
+		logger.Warningf("lost machine %s is no longer in machine pool %q and must be removed by the operator", lost.Id(), p.doc.Name)
+		return nil
+	}
+	return errors.Trace(lost.ForceDestroy(maxWait))
+}
+
+// replaceUnit adds a unit of the unit's application to the
+// replacement machine, in a new container of the same type if the unit
+// was in a container, and destroys the unit.
+func (p *MachinePool) replaceUnit(unit *Unit, replacement *Machine, maxWait time.Duration) error {
+	app, err := unit.Application()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if app.Life() == Alive {
+		placement, err := p.replacementPlacement(unit, replacement)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		newUnit, err := app.AddUnit(AddUnitParams{})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := p.st.AssignUnitWithPlacement(newUnit, placement); err != nil {
+			if _, destroyErr := newUnit.DestroyWithForce(true, maxWait); destroyErr != nil {
+				logger.Warningf("cannot destroy unit %q: %v", newUnit.Name(), destroyErr)
+			}
+			return errors.Trace(err)
+		}
+		logger.Infof("unit %q replaces unit %q from a lost machine", newUnit.Name(), unit.Name())
+	}
+	_, err = unit.DestroyWithForce(true, maxWait)
+	return errors.Trace(err)
+}
+
+// replacementPlacement returns the placement on the replacement machine
+// of a unit replacing the given one: in a container of the type that
+// hosted the unit, or directly on the machine.
+func (p *MachinePool) replacementPlacement(unit *Unit, replacement *Machine) (*instance.Placement, error) {
+	placement := &instance.Placement{
+		Scope:     instance.MachineScope,
+		Directive: replacement.Id(),
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m, err := p.st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return placement, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ct := m.ContainerType(); ct != "" && ct != instance.NONE {
+		placement.Scope = string(ct)
+	}
+	return placement, nil
+}
+
+// hostPrincipalUnits returns the principal units on the machine, or in
+// containers on it.
+func hostPrincipalUnits(st *State, machineId string) ([]*Unit, error) {
+	unitsCollection, closer := st.db().GetCollection(unitsC)
+	defer closer()
+
+	query := bson.D{
+		{"principal", ""},
+		{"$or", []bson.D{
+			{{"machineid", machineId}},
+			{{"machineid", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(machineId+"/")}}},
+		}},
+	}
+	var docs []unitDoc
+	if err := unitsCollection.Find(query).Sort("name").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	units := make([]*Unit, len(docs))
+	for i := range docs {
+		units[i] = newUnit(st, ModelTypeIAAS, &docs[i])
+	}
+	return units, nil
+}
+
+// applicationIsStateless reports whether the named application is
+// configured as stateless, so that its units can be placed again on
+// another machine when their machine is lost.
+func applicationIsStateless(st *State, appName string) (bool, error) {
+	app, err := st.Application(appName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	cfg, err := app.ApplicationConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return cfg.GetBool(application.StatelessConfigOptionName, false), nil
+}
+
+// machinesById sorts machines by their numeric id.
+type machinesById []*Machine
+
+func (m machinesById) Len() int      { return len(m) }
+func (m machinesById) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m machinesById) Less(i, j int) bool {
+	a, _ := strconv.Atoi(m[i].Id())
+	b, _ := strconv.Atoi(m[j].Id())
+	return a < b
+}
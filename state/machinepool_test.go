@@ -0,0 +1,294 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/txn"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/environschema.v1"
+
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type MachinePoolSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MachinePoolSuite{})
+
+func (s *MachinePoolSuite) addPool(c *gc.C, name string, size int, zones ...string) *state.MachinePool {
+	pool, err := s.State.AddMachinePool(state.MachinePoolArgs{
+		Name:        name,
+		Series:      "quantal",
+		Constraints: constraints.MustParse("mem=4G"),
+		Zones:       zones,
+		TargetSize:  size,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return pool
+}
+
+func (s *MachinePoolSuite) reconcile(c *gc.C, pool *state.MachinePool) {
+	err := s.State.ReconcileMachinePools(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = pool.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MachinePoolSuite) TestAddMachinePool(c *gc.C) {
+	s.addPool(c, "workers", 3, "az1", "az2")
+
+	pool, err := s.State.MachinePool("workers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool.Name(), gc.Equals, "workers")
+	c.Assert(pool.Series(), gc.Equals, "quantal")
+	c.Assert(pool.Zones(), jc.DeepEquals, []string{"az1", "az2"})
+	c.Assert(pool.TargetSize(), gc.Equals, 3)
+	c.Assert(pool.MachineIds(), gc.HasLen, 0)
+	cons, err := pool.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+}
+
+func (s *MachinePoolSuite) TestAddMachinePoolInvalid(c *gc.C) {
+	for i, test := range []struct {
+		args state.MachinePoolArgs
+		err  string
+	}{{
+		args: state.MachinePoolArgs{Name: "Workers", Series: "quantal"},
+		err:  `cannot add machine pool "Workers": machine pool name not valid`,
+	}, {
+		args: state.MachinePoolArgs{Name: "workers"},
+		err:  `cannot add machine pool "workers": no series specified`,
+	}, {
+		args: state.MachinePoolArgs{Name: "workers", Series: "quantal", TargetSize: -1},
+		err:  `cannot add machine pool "workers": machine pool size -1 not valid`,
+	}, {
+		args: state.MachinePoolArgs{Name: "workers", Series: "quantal", Zones: []string{"az1", "az1"}},
+		err:  `cannot add machine pool "workers": duplicate zones not valid`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddMachinePool(test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MachinePoolSuite) TestAddMachinePoolAlreadyExists(c *gc.C) {
+	s.addPool(c, "workers", 1)
+	_, err := s.State.AddMachinePool(state.MachinePoolArgs{Name: "workers", Series: "quantal"})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MachinePoolSuite) TestAllMachinePools(c *gc.C) {
+	s.addPool(c, "workers", 1)
+	s.addPool(c, "batch", 2)
+
+	pools, err := s.State.AllMachinePools()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pools, gc.HasLen, 2)
+	c.Assert(pools[0].Name(), gc.Equals, "batch")
+	c.Assert(pools[1].Name(), gc.Equals, "workers")
+}
+
+func (s *MachinePoolSuite) TestReconcileSpreadsAcrossZones(c *gc.C) {
+	pool := s.addPool(c, "workers", 3, "az1", "az2")
+	s.reconcile(c, pool)
+
+	c.Assert(pool.MachineIds(), gc.HasLen, 3)
+	var zones []string
+	for _, id := range pool.MachineIds() {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		cons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(*cons.Mem, gc.Equals, uint64(4096))
+		c.Assert(cons.Zones, gc.NotNil)
+		zones = append(zones, *cons.Zones...)
+	}
+	c.Assert(zones, jc.DeepEquals, []string{"az1", "az2", "az1"})
+
+	// Reconciling again does not change a pool at its target size.
+	ids := pool.MachineIds()
+	s.reconcile(c, pool)
+	c.Assert(pool.MachineIds(), jc.DeepEquals, ids)
+}
+
+func (s *MachinePoolSuite) TestReconcileScalesDown(c *gc.C) {
+	pool := s.addPool(c, "workers", 3)
+	s.reconcile(c, pool)
+	ids := pool.MachineIds()
+	c.Assert(ids, gc.HasLen, 3)
+
+	// The newest machine hosts a unit, so is kept.
+	newest, err := s.State.Machine(ids[2])
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress")).AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(newest)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = pool.SetTargetSize(2)
+	c.Assert(err, jc.ErrorIsNil)
+	s.reconcile(c, pool)
+	c.Assert(pool.MachineIds(), jc.DeepEquals, []string{ids[0], ids[2]})
+
+	removed, err := s.State.Machine(ids[1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Life(), gc.Equals, state.Dying)
+}
+
+func (s *MachinePoolSuite) TestSetTargetSizeInvalid(c *gc.C) {
+	pool := s.addPool(c, "workers", 1)
+	err := pool.SetTargetSize(-1)
+	c.Assert(err, gc.ErrorMatches, `machine pool size -1 not valid`)
+}
+
+func (s *MachinePoolSuite) TestReconcileReplacesLostMachine(c *gc.C) {
+	pool := s.addPool(c, "workers", 1)
+	s.reconcile(c, pool)
+	c.Assert(pool.MachineIds(), gc.HasLen, 1)
+	lost, err := s.State.Machine(pool.MachineIds()[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit's application is not stateless, so it is not placed again,
+	// and the lost machine is left for the operator.
+	unit, err := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress")).AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(lost)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := testing.NonZeroTime()
+	err = lost.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Lost,
+		Message: "instance not found by the provider",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.reconcile(c, pool)
+
+	c.Assert(pool.MachineIds(), gc.HasLen, 1)
+	c.Assert(pool.MachineIds()[0], gc.Not(gc.Equals), lost.Id())
+	err = lost.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lost.Life(), gc.Equals, state.Alive)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, lost.Id())
+}
+
+func (s *MachinePoolSuite) TestReconcileReplacesStatelessUnitInContainer(c *gc.C) {
+	pool := s.addPool(c, "workers", 1)
+	s.reconcile(c, pool)
+	lost, err := s.State.Machine(pool.MachineIds()[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err = app.UpdateApplicationConfig(
+		application.ConfigAttributes{application.StatelessConfigOptionName: true},
+		nil,
+		environschema.Fields{application.StatelessConfigOptionName: {Type: environschema.Tbool}},
+		nil,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{Scope: string(instance.LXD), Directive: lost.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := testing.NonZeroTime()
+	err = lost.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Lost,
+		Message: "instance not found by the provider",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.reconcile(c, pool)
+
+	// The unit is replaced by a new unit in a container of the same
+	// type on the replacement machine, and the lost machine destroyed.
+	c.Assert(pool.MachineIds(), gc.HasLen, 1)
+	replacementId := pool.MachineIds()[0]
+	c.Assert(replacementId, gc.Not(gc.Equals), lost.Id())
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	var newUnit *state.Unit
+	for _, u := range units {
+		if u.Name() != unit.Name() {
+			newUnit = u
+		} else {
+			c.Assert(u.Life(), gc.Not(gc.Equals), state.Alive)
+		}
+	}
+	c.Assert(newUnit, gc.NotNil)
+	machineId, err := newUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.ContainerType(), gc.Equals, instance.LXD)
+	parentId, ok := container.ParentId()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(parentId, gc.Equals, replacementId)
+
+	err = lost.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lost.Life(), gc.Not(gc.Equals), state.Alive)
+}
+
+func (s *MachinePoolSuite) TestReconcileKeepsLostMachineUntilReplaced(c *gc.C) {
+	pool := s.addPool(c, "workers", 1)
+	s.reconcile(c, pool)
+	lostId := pool.MachineIds()[0]
+	lost, err := s.State.Machine(lostId)
+	c.Assert(err, jc.ErrorIsNil)
+	now := testing.NonZeroTime()
+	err = lost.SetInstanceStatus(status.StatusInfo{
+		Status:  status.Lost,
+		Message: "instance not found by the provider",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Change the pool 3 times so that adding the replacement fails
+	// with ErrExcessiveContention.
+	setSize := func() {
+		other, err := s.State.MachinePool("workers")
+		c.Assert(err, jc.ErrorIsNil)
+		err = other.SetTargetSize(1)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	defer state.SetBeforeHooks(c, s.State, setSize, setSize, setSize).Check()
+	err = s.State.ReconcileMachinePools(time.Minute)
+	c.Assert(errors.Cause(err), gc.Equals, txn.ErrExcessiveContention)
+
+	// The lost machine is still in the pool, to be replaced next time.
+	err = pool.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool.MachineIds(), jc.DeepEquals, []string{lostId})
+}
+
+func (s *MachinePoolSuite) TestRemoveLeavesMachines(c *gc.C) {
+	pool := s.addPool(c, "workers", 1)
+	s.reconcile(c, pool)
+	id := pool.MachineIds()[0]
+
+	err := pool.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.MachinePool("workers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	m, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Life(), gc.Equals, state.Alive)
+
+	err = pool.Remove()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
 	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/network"
@@ -78,11 +79,174 @@ func (st *State) ExportPartial(cfg ExportConfig) (description.Model, error) {
 	return st.exportImpl(cfg)
 }
 
-// Export the current model for the State.
+// Export the current model for the State. Models using features the
+// model description can't represent are not exported; see ExportBlockers.
 func (st *State) Export() (description.Model, error) {
+	blockers, err := st.ExportBlockers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(blockers) > 0 {
+		return nil, errors.NotSupportedf("exporting %s of %s", blockers[0].Feature, blockers[0].Entity)
+	}
 	return st.exportImpl(ExportConfig{})
 }
 
+// ExportBlocker records an entity using a feature that the model
+// description can't represent. Exporting a model with export blockers
+// would silently drop the feature on the target, so such models can't
+// be exported or migrated.
+type ExportBlocker struct {
+	// Entity identifies the entity using the feature,
+	// e.g. "application mysql".
+	Entity string
+
+	// Feature describes the feature.
+	Feature string
+}
+
+// ExportBlockers returns the uses of features in the model that
+// prevent it from being exported.
+func (st *State) ExportBlockers() ([]ExportBlocker, error) {
+	var blockers []ExportBlocker
+	apps, err := st.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, app := range apps {
+		entity := "application " + app.Name()
+		if !exposeSettingsExportable(app.doc.ExposedEndpoints) {
+			blockers = append(blockers, ExportBlocker{Entity: entity, Feature: "per-endpoint expose settings"})
+		}
+		if app.doc.PlacementPolicy != nil {
+			blockers = append(blockers, ExportBlocker{Entity: entity, Feature: "placement policy"})
+		}
+	}
+
+	pools, err := st.AllMachinePools()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, pool := range pools {
+		blockers = append(blockers, ExportBlocker{Entity: "model", Feature: fmt.Sprintf("machine pool %q", pool.Name())})
+	}
+
+	portsBlockers, err := st.portEndpointsExportBlockers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	blockers = append(blockers, portsBlockers...)
+
+	constraintsBlockers, err := st.constraintsExportBlockers(apps)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(blockers, constraintsBlockers...), nil
+}
+
+// portEndpointsExportBlockers returns a blocker for each unit with
+// ports opened for one of its endpoints rather than for all of them.
+func (st *State) portEndpointsExportBlockers() ([]ExportBlocker, error) {
+	coll, closer := st.db().GetCollection(openedPortsC)
+	defer closer()
+
+	var docs []portsDoc
+	if err := coll.Find(bson.D{{"ports.endpoint", bson.D{{"$exists", true}}}}).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	units := set.NewStrings()
+	for _, doc := range docs {
+		for _, pr := range doc.Ports {
+			if pr.Endpoint != "" {
+				units.Add(pr.UnitName)
+			}
+		}
+	}
+	var blockers []ExportBlocker
+	for _, unitName := range units.SortedValues() {
+		blockers = append(blockers, ExportBlocker{Entity: "unit " + unitName, Feature: "ports opened for an endpoint"})
+	}
+	return blockers, nil
+}
+
+// constraintsExportBlockers returns a blocker for each of the model,
+// applications and machines with constraints asking for spot instances
+// or for a specific image, and for each machine provisioned from an
+// image chosen by such a constraint.
+func (st *State) constraintsExportBlockers(apps []*Application) ([]ExportBlocker, error) {
+	coll, closer := st.db().GetCollection(constraintsC)
+	defer closer()
+
+	var docs []constraintsDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "reading constraints")
+	}
+	features := make(map[string][]string)
+	for _, doc := range docs {
+		cons := doc.value()
+		key := st.localID(doc.DocID)
+		if cons.HasSpot() || cons.HasMaxPrice() {
+			features[key] = append(features[key], "spot instance constraints")
+		}
+		if cons.HasImage() {
+			features[key] = append(features[key], "image constraints")
+		}
+	}
+
+	instances, closer := st.db().GetCollection(instanceDataC)
+	defer closer()
+
+	var instanceDocs []instanceData
+	if err := instances.Find(bson.D{{"image", bson.D{{"$exists", true}}}}).All(&instanceDocs); err != nil {
+		return nil, errors.Annotate(err, "reading instance data")
+	}
+	for _, doc := range instanceDocs {
+		key := machineGlobalKey(doc.MachineId)
+		features[key] = append(features[key], "instance image")
+	}
+	if len(features) == 0 {
+		return nil, nil
+	}
+
+	var blockers []ExportBlocker
+	addBlockers := func(entity, key string) {
+		for _, feature := range features[key] {
+			blockers = append(blockers, ExportBlocker{Entity: entity, Feature: feature})
+		}
+	}
+	addBlockers("model", modelGlobalKey)
+	for _, app := range apps {
+		addBlockers("application "+app.Name(), applicationGlobalKey(app.Name()))
+	}
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, machine := range machines {
+		addBlockers("machine "+machine.Id(), machineGlobalKey(machine.Id()))
+	}
+	return blockers, nil
+}
+
+// exposeSettingsExportable returns whether the expose settings are
+// represented by the exposed flag alone, that is whether they allow
+// access to all endpoints from everywhere.
+func exposeSettingsExportable(exposedEndpoints map[string]ExposedEndpoint) bool {
+	if len(exposedEndpoints) == 0 {
+		return true
+	}
+	allEndpoints, ok := exposedEndpoints[""]
+	if !ok || len(exposedEndpoints) > 1 || len(allEndpoints.ExposeToSpaceIDs) > 0 {
+		return false
+	}
+	for _, cidr := range allEndpoints.ExposeToCIDRs {
+		if cidr != "0.0.0.0/0" && cidr != "::/0" {
+			return false
+		}
+	}
+	return true
+}
+
 func (st *State) exportImpl(cfg ExportConfig) (description.Model, error) {
 	dbModel, err := st.Model()
 	if err != nil {
@@ -165,6 +329,9 @@ func (st *State) exportImpl(cfg ExportConfig) (description.Model, error) {
 	if err := export.modelUsers(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := export.groups(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if err := export.machines(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -353,6 +520,32 @@ func (e *exporter) modelUsers() error {
 	return nil
 }
 
+// groups exports the groups with access to the model or to any of its
+// application offers, along with their members and that access. The
+// model description has no place for groups, so they are recorded in a
+// reserved model annotation that is removed again on import.
+func (e *exporter) groups() error {
+	groups, err := e.st.modelMigrationGroups(!e.cfg.SkipApplicationOffers)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	data, err := yaml.Marshal(groups)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	annotations := make(map[string]string)
+	for key, value := range e.model.Annotations() {
+		annotations[key] = value
+	}
+	annotations[migrationGroupsAnnotation] = string(data)
+	e.model.SetAnnotations(annotations)
+	return nil
+}
+
 func (e *exporter) machines() error {
 	machines, err := e.st.AllMachines()
 	if err != nil {
@@ -888,7 +1081,11 @@ func (e *exporter) addApplication(ctx addApplicationContext) error {
 	}
 	exApplication.SetStatus(statusArgs)
 	exApplication.SetStatusHistory(e.statusHistoryArgs(globalKey))
-	exApplication.SetAnnotations(e.getAnnotations(globalKey))
+	annotations, err := e.applicationAnnotations(application)
+	if err != nil {
+		return errors.Annotatef(err, "annotations for application %s", appName)
+	}
+	exApplication.SetAnnotations(annotations)
 
 	globalAppWorkloadKey := applicationGlobalOperatorKey(appName)
 	operatorStatusArgs, err := e.statusArgs(globalAppWorkloadKey)
@@ -1868,6 +2065,27 @@ func (e *exporter) readAllConstraints() error {
 	return nil
 }
 
+// applicationAnnotations returns the annotations to export for the
+// application. The model description has no place for the charm origin,
+// so it is recorded in a reserved annotation that is removed again on
+// import.
+func (e *exporter) applicationAnnotations(application *Application) (map[string]string, error) {
+	annotations := e.getAnnotations(application.globalKey())
+	if application.doc.CharmOrigin == nil {
+		return annotations, nil
+	}
+	data, err := yaml.Marshal(application.doc.CharmOrigin)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]string)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[migrationCharmOriginAnnotation] = string(data)
+	return result, nil
+}
+
 // getAnnotations doesn't really care if there are any there or not
 // for the key, but if they were there, they are removed so we can
 // check at the end of the export for anything we have forgotten.
@@ -23,8 +23,10 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/macaroon.v2"
+	"gopkg.in/yaml.v2"
 
 	apitesting "github.com/juju/juju/api/testing"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/firewall"
@@ -266,6 +268,36 @@ func (s *MigrationExportSuite) TestModelUsers(c *gc.C) {
 	c.Assert(exportedBob.Access(), gc.Equals, "read")
 }
 
+func (s *MigrationExportSuite) TestGroups(c *gc.C) {
+	bobTag := names.NewUserTag("bob@external")
+	_, err := s.State.AddGroup("Engineers", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddGroupMembers("engineers", bobTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetGroupAccess("engineers", s.Model.ModelTag(), permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	// Groups without access to the model aren't exported.
+	_, err = s.State.AddGroup("others", s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Group members aren't exported as model users.
+	users := model.Users()
+	c.Assert(users, gc.HasLen, 1)
+	c.Assert(users[0].Name(), gc.Equals, s.Owner)
+
+	var groups []map[string]interface{}
+	err = yaml.Unmarshal([]byte(model.Annotations()["juju-migration-groups"]), &groups)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	c.Assert(groups[0]["name"], gc.Equals, "Engineers")
+	c.Assert(groups[0]["created-by"], gc.Equals, s.Owner.Id())
+	c.Assert(groups[0]["members"], jc.DeepEquals, []interface{}{"bob@external"})
+	c.Assert(groups[0]["model-access"], gc.Equals, "write")
+}
+
 func (s *MigrationExportSuite) TestSLAs(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -565,6 +597,195 @@ func (s *MigrationExportSuite) TestMultipleApplications(c *gc.C) {
 	c.Assert(applications, gc.HasLen, 3)
 }
 
+func (s *MigrationExportSuite) TestApplicationExposedToEverywhere(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"0.0.0.0/0", "::/0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, gc.HasLen, 0)
+
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Applications(), gc.HasLen, 1)
+	c.Assert(model.Applications()[0].Exposed(), jc.IsTrue)
+}
+
+func (s *MigrationExportSuite) TestApplicationExposeSettingsBlockExport(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "application wordpress",
+		Feature: "per-endpoint expose settings",
+	}})
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, "exporting per-endpoint expose settings of application wordpress not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	// Partial exports, such as those used for bundles, are lossy
+	// anyway and aren't blocked.
+	_, err = s.State.ExportPartial(state.ExportConfig{IgnoreIncompleteModel: true})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MigrationExportSuite) TestPortEndpointsBlockExport(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+	op, err := unit.OpenClosePortsOnEndpointsOperation(map[string][]network.PortRange{
+		"url": {network.MustParsePortRange("80/tcp")},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "unit " + unit.Name(),
+		Feature: "ports opened for an endpoint",
+	}})
+}
+
+func (s *MigrationExportSuite) TestPlacementPolicyBlocksExport(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := app.SetPlacementPolicy(application.PlacementPolicy{
+		Strategy: application.PlacementStrategySpread,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "application wordpress",
+		Feature: "placement policy",
+	}})
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, "exporting placement policy of application wordpress not supported")
+}
+
+func (s *MigrationExportSuite) TestApplicationCharmOrigin(c *gc.C) {
+	app, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+		CharmOrigin: &state.CharmOrigin{
+			Source:   state.CharmOriginCharmHub,
+			ID:       "wordpress-id",
+			Revision: 3,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.SetAnnotations(app, testAnnotations)
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, gc.HasLen, 0)
+
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	applications := model.Applications()
+	c.Assert(applications, gc.HasLen, 1)
+	annotations := applications[0].Annotations()
+	c.Assert(annotations, gc.HasLen, len(testAnnotations)+1)
+	for key, value := range testAnnotations {
+		c.Check(annotations[key], gc.Equals, value)
+	}
+
+	var origin state.CharmOrigin
+	err = yaml.Unmarshal([]byte(annotations["juju-migration-charm-origin"]), &origin)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origin, jc.DeepEquals, state.CharmOrigin{
+		Source:   state.CharmOriginCharmHub,
+		ID:       "wordpress-id",
+		Revision: 3,
+	})
+}
+
+func (s *MigrationExportSuite) TestMachinePoolBlocksExport(c *gc.C) {
+	_, err := s.State.AddMachinePool(state.MachinePoolArgs{
+		Name:       "workers",
+		Series:     "quantal",
+		TargetSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "model",
+		Feature: `machine pool "workers"`,
+	}})
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `exporting machine pool "workers" of model not supported`)
+}
+
+func (s *MigrationExportSuite) TestSpotConstraintsBlockExport(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := app.SetConstraints(constraints.MustParse("spot=true"))
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("max-price=0.05"),
+	})
+	err = s.State.SetModelConstraints(constraints.MustParse("spot=false"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "application wordpress",
+		Feature: "spot instance constraints",
+	}, {
+		Entity:  "machine " + machine.Id(),
+		Feature: "spot instance constraints",
+	}})
+
+	_, err = s.State.Export()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MigrationExportSuite) TestImageConstraintsBlockExport(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := app.SetConstraints(constraints.MustParse("image-name=custom"))
+	c.Assert(err, jc.ErrorIsNil)
+	image := "ami-0123"
+	arch := "amd64"
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("image-id=ami-0123"),
+		Characteristics: &instance.HardwareCharacteristics{
+			Arch:  &arch,
+			Image: &image,
+		},
+	})
+
+	blockers, err := s.State.ExportBlockers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockers, jc.DeepEquals, []state.ExportBlocker{{
+		Entity:  "application wordpress",
+		Feature: "image constraints",
+	}, {
+		Entity:  "machine " + machine.Id(),
+		Feature: "image constraints",
+	}, {
+		Entity:  "machine " + machine.Id(),
+		Feature: "instance image",
+	}})
+
+	_, err = s.State.Export()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *MigrationExportSuite) TestApplicationExposingOffers(c *gc.C) {
 	_ = s.Factory.MakeUser(c, &factory.UserParams{Name: "admin"})
 	fooUser := s.Factory.MakeUser(c, &factory.UserParams{Name: "foo"})
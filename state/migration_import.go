@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/charm/v7"
+	"github.com/juju/collections/set"
 	"github.com/juju/description/v2"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -18,6 +20,7 @@ import (
 	"github.com/juju/version"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/controller"
@@ -209,6 +212,9 @@ func (ctrl *Controller) Import(model description.Model) (_ *Model, _ *State, err
 	if err := restore.storage(); err != nil {
 		return nil, nil, errors.Annotate(err, "storage")
 	}
+	if err := restore.groups(); err != nil {
+		return nil, nil, errors.Annotate(err, "groups")
+	}
 
 	// NOTE: at the end of the import make sure that the mode of the model
 	// is set to "imported" not "active" (or whatever we call it). This way
@@ -280,7 +286,15 @@ func (i *importer) modelExtras() error {
 		}
 	}
 
-	if annotations := i.model.Annotations(); len(annotations) > 0 {
+	annotations := make(map[string]string)
+	for key, value := range i.model.Annotations() {
+		// The groups annotation is only used to carry groups through
+		// the migration, see groups.
+		if key != migrationGroupsAnnotation {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) > 0 {
 		if err := i.dbModel.SetAnnotations(i.dbModel, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -379,6 +393,83 @@ func (i *importer) modelUsers() error {
 	return nil
 }
 
+// groups restores the groups with access to the model or its offers,
+// and the access granted to them. Groups are controller wide, so a group
+// that already exists in this controller is used as long as it has the
+// same members; otherwise access would be granted to a different set of
+// users than in the source controller.
+func (i *importer) groups() error {
+	data, ok := i.model.Annotations()[migrationGroupsAnnotation]
+	if !ok {
+		return nil
+	}
+	i.logger.Debugf("importing groups")
+	var groups []migrationGroup
+	if err := yaml.Unmarshal([]byte(data), &groups); err != nil {
+		return errors.Annotate(err, "reading groups")
+	}
+	var ops []txn.Op
+	for _, g := range groups {
+		members := set.NewStrings()
+		for _, member := range g.Members {
+			// Local members must exist, as when they're added to
+			// a group; the migration prechecks ensure they do.
+			if user := names.NewUserTag(member); user.IsLocal() {
+				if _, err := i.st.User(user); err != nil {
+					return errors.Annotatef(err, "member %q of group %q", member, g.Name)
+				}
+			}
+			members.Add(strings.ToLower(member))
+		}
+		existing, err := i.st.Group(g.Name)
+		if errors.IsNotFound(err) {
+			ops = append(ops, txn.Op{
+				C:      groupsC,
+				Id:     strings.ToLower(g.Name),
+				Assert: txn.DocMissing,
+				Insert: &groupDoc{
+					DocID:       strings.ToLower(g.Name),
+					Name:        g.Name,
+					CreatedBy:   g.CreatedBy,
+					DateCreated: g.DateCreated,
+					Members:     members.SortedValues(),
+					// Groups created by the import are removed if
+					// the import is aborted.
+					ImportingModelUUID: i.dbModel.UUID(),
+				},
+			})
+		} else if err != nil {
+			return errors.Trace(err)
+		} else {
+			existingMembers := set.NewStrings()
+			for _, member := range existing.Members() {
+				existingMembers.Add(member.Id())
+			}
+			if existingMembers.Size() != members.Size() || !existingMembers.Difference(members).IsEmpty() {
+				return errors.Errorf("group %q exists in this controller with different members", g.Name)
+			}
+			ops = append(ops, txn.Op{
+				C:      groupsC,
+				Id:     existing.doc.DocID,
+				Assert: txn.DocExists,
+			})
+		}
+		subjectGlobalKey := groupGlobalKey(g.Name)
+		if g.ModelAccess != "" {
+			ops = append(ops, createPermissionOp(
+				modelKey(i.dbModel.UUID()), subjectGlobalKey, permission.Access(g.ModelAccess)))
+		}
+		for offerUUID, access := range g.OfferAccess {
+			ops = append(ops, createPermissionOp(
+				applicationOfferKey(offerUUID), subjectGlobalKey, permission.Access(access)))
+		}
+	}
+	if err := i.st.db().RunTransaction(ops); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 func (i *importer) machines() error {
 	i.logger.Debugf("importing machines")
 	for _, m := range i.model.Machines() {
@@ -905,7 +996,15 @@ func (i *importer) application(a description.Application, ctrlCfg controller.Con
 		}
 	}
 
-	if annotations := a.Annotations(); len(annotations) > 0 {
+	annotations := make(map[string]string)
+	for key, value := range a.Annotations() {
+		// The charm origin annotation is only used to carry the
+		// origin through the migration, see makeApplicationDoc.
+		if key != migrationCharmOriginAnnotation {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) > 0 {
 		if err := i.dbModel.SetAnnotations(app, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -1249,11 +1348,23 @@ func (i *importer) makeApplicationDoc(a description.Application) (*applicationDo
 		return nil, errors.Trace(err)
 	}
 
+	var origin *CharmOrigin
+	if data, ok := a.Annotations()[migrationCharmOriginAnnotation]; ok {
+		origin = &CharmOrigin{}
+		if err := yaml.Unmarshal([]byte(data), origin); err != nil {
+			return nil, errors.Annotate(err, "reading charm origin")
+		}
+		if err := origin.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	return &applicationDoc{
 		Name:                 a.Name(),
 		Series:               a.Series(),
 		Subordinate:          a.Subordinate(),
 		CharmURL:             charmURL,
+		CharmOrigin:          origin,
 		Channel:              a.Channel(),
 		CharmModifiedVersion: a.CharmModifiedVersion(),
 		ForceCharm:           a.ForceCharm(),
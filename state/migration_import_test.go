@@ -271,6 +271,130 @@ func (s *MigrationImportSuite) TestModelUsers(c *gc.C) {
 	c.Assert(allUsers, gc.HasLen, 3)
 }
 
+func (s *MigrationImportSuite) addGroupWithModelAccess(c *gc.C, name string, members ...names.UserTag) {
+	_, err := s.State.AddGroup(name, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddGroupMembers(name, members...)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetGroupAccess(name, s.modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MigrationImportSuite) TestGroups(c *gc.C) {
+	bob := names.NewUserTag("bob@external")
+	s.addGroupWithModelAccess(c, "engineers", bob)
+
+	// The group already exists in the controller with the same members,
+	// so it is used by the imported model.
+	newModel, newSt := s.importModel(c, s.State)
+
+	access, err := newSt.GroupAccess("engineers", newModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+	access, err = newSt.UserPermission(bob, newModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	// Group members aren't imported as model users.
+	users, err := newModel.Users()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 1)
+
+	// The annotation carrying the groups isn't kept.
+	annotations, err := newModel.Annotations(newModel)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 0)
+}
+
+func (s *MigrationImportSuite) TestGroupsCreated(c *gc.C) {
+	bob := names.NewUserTag("bob@external")
+	s.addGroupWithModelAccess(c, "engineers", bob)
+
+	newModel, newSt := s.importModel(c, s.State, func(map[string]interface{}) {
+		// Remove the group after the export, so the import
+		// has to create it.
+		err := s.State.RemoveGroup("engineers")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+
+	group, err := newSt.Group("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.CreatedBy(), gc.Equals, s.Owner.Id())
+	c.Assert(group.Members(), jc.DeepEquals, []names.UserTag{bob})
+	access, err := newSt.GroupAccess("engineers", newModel.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+}
+
+func (s *MigrationImportSuite) TestGroupsDifferentMembers(c *gc.C) {
+	bob := names.NewUserTag("bob@external")
+	s.addGroupWithModelAccess(c, "engineers", bob)
+
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddGroupMembers("engineers", names.NewUserTag("mallory@external"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	in := newModel(out, utils.MustNewUUID().String(), "new")
+	_, _, err = s.Controller.Import(in)
+	c.Assert(err, gc.ErrorMatches, `.*group "engineers" exists in this controller with different members`)
+}
+
+func (s *MigrationImportSuite) TestGroupsMissingMember(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	s.addGroupWithModelAccess(c, "engineers", alice)
+
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveGroup("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveUser(alice)
+	c.Assert(err, jc.ErrorIsNil)
+
+	in := newModel(out, utils.MustNewUUID().String(), "new")
+	_, _, err = s.Controller.Import(in)
+	c.Assert(err, gc.ErrorMatches, `.*member "alice" of group "engineers": .*`)
+}
+
+func (s *MigrationImportSuite) TestGroupsRemovedWhenImportAborted(c *gc.C) {
+	s.addGroupWithModelAccess(c, "engineers", names.NewUserTag("bob@external"))
+	s.addGroupWithModelAccess(c, "managers", names.NewUserTag("carol@external"))
+
+	_, newSt := s.importModel(c, s.State, func(map[string]interface{}) {
+		err := s.State.RemoveGroup("engineers")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+
+	err := newSt.RemoveImportingModelDocs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the group created by the import is removed.
+	_, err = s.State.Group("engineers")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Group("managers")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MigrationImportSuite) TestGroupsKeptWhenImportCompleted(c *gc.C) {
+	s.addGroupWithModelAccess(c, "engineers", names.NewUserTag("bob@external"))
+
+	newModel, newSt := s.importModel(c, s.State, func(map[string]interface{}) {
+		err := s.State.RemoveGroup("engineers")
+		c.Assert(err, jc.ErrorIsNil)
+	})
+	err := newModel.SetMigrationMode(state.MigrationModeNone)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A later import of the model being aborted doesn't remove
+	// the group created by the completed one.
+	err = newModel.SetMigrationMode(state.MigrationModeImporting)
+	c.Assert(err, jc.ErrorIsNil)
+	err = newSt.RemoveImportingModelDocs()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Group("engineers")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *MigrationImportSuite) TestSLA(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -630,6 +754,33 @@ func (s *MigrationImportSuite) TestApplications(c *gc.C) {
 	s.assertImportedApplication(c, application, pwd, cons, exported, newModel, newSt, true)
 }
 
+func (s *MigrationImportSuite) TestApplicationCharmOrigin(c *gc.C) {
+	origin := &state.CharmOrigin{
+		Source:   state.CharmOriginCharmHub,
+		ID:       "wordpress-id",
+		Revision: 3,
+	}
+	app, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:        "wordpress",
+		Charm:       s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+		CharmOrigin: origin,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.SetAnnotations(app, testAnnotations)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c, s.State)
+
+	imported, err := newSt.Application("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(imported.CharmOrigin(), jc.DeepEquals, origin)
+
+	// The annotation carrying the charm origin isn't kept.
+	annotations, err := newModel.Annotations(imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, jc.DeepEquals, testAnnotations)
+}
+
 func (s *MigrationImportSuite) TestApplicationStatus(c *gc.C) {
 	cons := constraints.MustParse("arch=amd64 mem=8G")
 	testCharm, application, pwd := s.setupSourceApplications(c, s.State, cons, false)
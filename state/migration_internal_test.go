@@ -25,6 +25,9 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		modelUsersC,
 		modelUserLastConnectionC,
 		permissionsC,
+		// Groups with access to the model or its offers are
+		// migrated along with that access.
+		groupsC,
 		settingsC,
 		generationsC,
 		sequenceC,
@@ -96,6 +99,8 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// machine removals.
 		cleanupsC,
 		machineRemovalsC,
+		// See ExportBlockers.
+		machinePoolsC,
 		// The autocert cache is non-critical. After migration
 		// you'll just need to acquire new certificates.
 		autocertCacheC,
@@ -113,8 +118,17 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		guimetadataC,
 		// This is controller global, not migrated.
 		guisettingsC,
+		// The charm repository is hosted by the controller and
+		// shared by its models, so it isn't migrated.
+		repositoryCharmsC,
+		localCharmReleasesC,
 		// Users aren't migrated.
 		usersC,
+		// Custom roles are controller wide and aren't migrated.
+		rolesC,
+		roleAssignmentsC,
+		// API tokens are controller wide and aren't migrated.
+		apiTokensC,
 		userLastLoginC,
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
@@ -148,6 +162,7 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		migrationsStatusC,
 		migrationsActiveC,
 		migrationsMinionSyncC,
+		bulkMigrationsC,
 
 		// The container ref document is primarily there to keep track
 		// of a particular machine's containers. The migration format
@@ -328,6 +343,12 @@ func (s *MigrationSuite) TestMachineDocFields(c *gc.C) {
 		"StopMongoUntilVersion",
 		// Ignored; it gets populated on demand when the agent restarts
 		"AgentStartedAt",
+		// Only used when the machine is provisioned, and the export
+		// precheck ensures that all machines are provisioned.
+		"CloudInitUserData",
+		// Staged upgrades block migration, as the export precheck
+		// requires all agents to run the model agent version.
+		"TargetAgentVersion",
 	)
 	migrated := set.NewStrings(
 		"Addresses",
@@ -356,6 +377,9 @@ func (s *MigrationSuite) TestInstanceDataFields(c *gc.C) {
 		// KeepInstance is only set when a machine is
 		// dying/dead (to be removed).
 		"KeepInstance",
+		// Image is not supported by the description
+		// package; see ExportBlockers.
+		"Image",
 	)
 	migrated := set.NewStrings(
 		// DocID is the model + machine id
@@ -396,12 +420,17 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// See ExportBlockers.
+		"ExposedEndpoints",
+		"PlacementPolicy",
 	)
 	migrated := set.NewStrings(
 		"Name",
 		"Series",
 		"Subordinate",
 		"CharmURL",
+		// CharmOrigin is carried in an application annotation.
+		"CharmOrigin",
 		"Channel",
 		"CharmModifiedVersion",
 		"ForceCharm",
@@ -426,6 +455,9 @@ func (s *MigrationSuite) TestUnitDocFields(c *gc.C) {
 		"Application",
 		// Resolved is not migrated as we check that all is good before we start.
 		"Resolved",
+		// Interrupting is not migrated as the instance status of the
+		// unit's machine fails the prechecks.
+		"Interrupting",
 		// Series and CharmURL also come from the application.
 		"Series",
 		"CharmURL",
@@ -460,6 +492,18 @@ func (s *MigrationSuite) TestPortsDocFields(c *gc.C) {
 	s.AssertExportedFields(c, portsDoc{}, fields)
 }
 
+func (s *MigrationSuite) TestPortRangeDocFields(c *gc.C) {
+	fields := set.NewStrings(
+		"UnitName",
+		"FromPort",
+		"ToPort",
+		"Protocol",
+		// See ExportBlockers.
+		"Endpoint",
+	)
+	s.AssertExportedFields(c, portRangeDoc{}, fields)
+}
+
 func (s *MigrationSuite) TestMeterStatusDocFields(c *gc.C) {
 	fields := set.NewStrings(
 		// DocID itself isn't migrated
@@ -582,6 +626,11 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"Spaces",
 		"VirtType",
 		"Zones",
+		// See ExportBlockers.
+		"Spot",
+		"MaxPrice",
+		"ImageID",
+		"ImageName",
 	)
 	s.AssertExportedFields(c, constraintsDoc{}, fields)
 }
@@ -681,6 +681,14 @@ func (m *Model) SetMigrationMode(mode MigrationMode) error {
 		Assert: txn.DocExists,
 		Update: bson.D{{"$set", bson.D{{"migration-mode", mode}}}},
 	}}
+	if m.doc.MigrationMode == MigrationModeImporting && mode != MigrationModeImporting {
+		// The groups created by the import are kept from now on.
+		groupOps, err := m.st.completeImportedGroupsOps(m.doc.UUID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ops = append(ops, groupOps...)
+	}
 	if err := m.st.db().RunTransaction(ops); err != nil {
 		return errors.Trace(err)
 	}
@@ -64,9 +64,19 @@ type ModelMigration interface {
 	InitiatedBy() string
 
 	// TargetInfo returns the details required to connect to the
-	// migration's target controller.
+	// migration's target controller. The details are empty for an
+	// offline migration until its target has been set.
 	TargetInfo() (*migration.TargetInfo, error)
 
+	// Offline returns true if the model is transferred to the target
+	// controller in a model archive rather than by the migrationmaster.
+	Offline() bool
+
+	// SetTarget records the target controller of an offline
+	// migration, once the model has been imported into it. The
+	// target can only be set once, in the IMPORT phase.
+	SetTarget(migration.TargetInfo) error
+
 	// SetPhase sets the phase of the migration. An error will be
 	// returned if the new phase does not follow the current phase or
 	// if the migration is no longer active.
@@ -162,6 +172,11 @@ type modelMigDoc struct {
 
 	// The list of users and their access-level to the model being migrated.
 	ModelUsers []modelMigUserDoc `bson:"model-users,omitempty"`
+
+	// Offline is true when the model is transferred to the target
+	// controller in a model archive. The target details are then only
+	// recorded once the archive has been imported.
+	Offline bool `bson:"offline,omitempty"`
 }
 
 type modelMigUserDoc struct {
@@ -272,6 +287,10 @@ func (mig *modelMigration) InitiatedBy() string {
 
 // TargetInfo implements ModelMigration.
 func (mig *modelMigration) TargetInfo() (*migration.TargetInfo, error) {
+	if mig.doc.TargetController == "" {
+		// The target of an offline migration isn't known yet.
+		return &migration.TargetInfo{}, nil
+	}
 	authTag, err := names.ParseUserTag(mig.doc.TargetAuthTag)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -291,6 +310,76 @@ func (mig *modelMigration) TargetInfo() (*migration.TargetInfo, error) {
 	}, nil
 }
 
+// Offline implements ModelMigration.
+func (mig *modelMigration) Offline() bool {
+	return mig.doc.Offline
+}
+
+// SetTarget implements ModelMigration.
+func (mig *modelMigration) SetTarget(targetInfo migration.TargetInfo) error {
+	if !mig.doc.Offline {
+		return errors.Errorf("migration %q is not an offline migration", mig.doc.Id)
+	}
+	if mig.doc.TargetController != "" {
+		return errors.Errorf("target of migration %q is already set", mig.doc.Id)
+	}
+	if err := targetInfo.ValidateAddress(); err != nil {
+		return errors.Trace(err)
+	}
+	if targetInfo.AuthTag.Id() == "" {
+		return errors.NotValidf("empty AuthTag")
+	}
+	if err := checkTargetController(mig.st, targetInfo.ControllerTag); err != nil {
+		return errors.Trace(err)
+	}
+	phase, err := mig.Phase()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if phase != migration.IMPORT {
+		return errors.Errorf("migration %q is in the %s phase, not IMPORT", mig.doc.Id, phase)
+	}
+
+	msg := "model imported into target controller"
+	ops, err := migStatusHistoryAndOps(mig.st, phase, mig.st.clock().Now().UnixNano(), msg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	nextDoc := mig.doc
+	nextDoc.TargetController = targetInfo.ControllerTag.Id()
+	nextDoc.TargetControllerAlias = targetInfo.ControllerAlias
+	nextDoc.TargetAddrs = targetInfo.Addrs
+	nextDoc.TargetCACert = targetInfo.CACert
+	nextDoc.TargetAuthTag = targetInfo.AuthTag.String()
+	ops = append(ops, txn.Op{
+		C:      migrationsC,
+		Id:     mig.doc.Id,
+		Assert: bson.M{"target-controller": ""},
+		Update: bson.M{"$set": bson.M{
+			"target-controller":       nextDoc.TargetController,
+			"target-controller-alias": nextDoc.TargetControllerAlias,
+			"target-addrs":            nextDoc.TargetAddrs,
+			"target-cacert":           nextDoc.TargetCACert,
+			"target-entity":           nextDoc.TargetAuthTag,
+		}},
+	}, txn.Op{
+		// Updating the status document wakes up the migrationmaster,
+		// which is waiting for the target to be set.
+		C:      migrationsStatusC,
+		Id:     mig.statusDoc.Id,
+		Assert: bson.M{"phase": mig.statusDoc.Phase},
+		Update: bson.M{"$set": bson.M{"status-message": msg}},
+	})
+	if err := mig.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("target already set or phase changed")
+	} else if err != nil {
+		return errors.Annotate(err, "failed to set migration target")
+	}
+	mig.doc = nextDoc
+	mig.statusDoc.StatusMessage = msg
+	return nil
+}
+
 // SetPhase implements ModelMigration.
 func (mig *modelMigration) SetPhase(nextPhase migration.Phase) error {
 	now := mig.st.clock().Now().UnixNano()
@@ -638,6 +727,11 @@ func (mig *modelMigration) ModelUserAccess(tag names.Tag) permission.Access {
 type MigrationSpec struct {
 	InitiatedBy names.UserTag
 	TargetInfo  migration.TargetInfo
+
+	// Offline is set when the model is to be exported to a model
+	// archive. TargetInfo must then be empty; it is set with
+	// ModelMigration.SetTarget once the archive has been imported.
+	Offline bool
 }
 
 // Validate returns an error if the MigrationSpec contains bad
@@ -646,6 +740,12 @@ func (spec *MigrationSpec) Validate() error {
 	if !names.IsValidUser(spec.InitiatedBy.Id()) {
 		return errors.NotValidf("InitiatedBy")
 	}
+	if spec.Offline {
+		if spec.TargetInfo.ControllerTag.Id() != "" {
+			return errors.NotValidf("TargetInfo for offline migration")
+		}
+		return nil
+	}
 	return spec.TargetInfo.Validate()
 }
 
@@ -659,8 +759,10 @@ func (st *State) CreateMigration(spec MigrationSpec) (ModelMigration, error) {
 	if err := spec.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	if err := checkTargetController(st, spec.TargetInfo.ControllerTag); err != nil {
-		return nil, errors.Trace(err)
+	if !spec.Offline {
+		if err := checkTargetController(st, spec.TargetInfo.ControllerTag); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	now := st.clock().Now().UnixNano()
@@ -704,6 +806,11 @@ func (st *State) CreateMigration(spec MigrationSpec) (ModelMigration, error) {
 			return nil, errors.Trace(err)
 		}
 
+		var targetAuthTag string
+		if !spec.Offline {
+			targetAuthTag = spec.TargetInfo.AuthTag.String()
+		}
+
 		id := fmt.Sprintf("%s:%d", modelUUID, attempt)
 		doc = modelMigDoc{
 			Id:                    id,
@@ -714,10 +821,11 @@ func (st *State) CreateMigration(spec MigrationSpec) (ModelMigration, error) {
 			TargetControllerAlias: spec.TargetInfo.ControllerAlias,
 			TargetAddrs:           spec.TargetInfo.Addrs,
 			TargetCACert:          spec.TargetInfo.CACert,
-			TargetAuthTag:         spec.TargetInfo.AuthTag.String(),
+			TargetAuthTag:         targetAuthTag,
 			TargetPassword:        spec.TargetInfo.Password,
 			TargetMacaroons:       macsJSON,
 			ModelUsers:            userDocs,
+			Offline:               spec.Offline,
 		}
 
 		statusDoc = modelMigStatusDoc{
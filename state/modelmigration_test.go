@@ -96,6 +96,91 @@ func (s *MigrationSuite) TestCreate(c *gc.C) {
 	c.Check(model.MigrationMode(), gc.Equals, state.MigrationModeExporting)
 }
 
+func (s *MigrationSuite) TestCreateOffline(c *gc.C) {
+	mig, err := s.State2.CreateMigration(state.MigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		Offline:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mig.Offline(), jc.IsTrue)
+
+	info, err := mig.TargetInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*info, jc.DeepEquals, migration.TargetInfo{})
+
+	assertPhase(c, mig, migration.QUIESCE)
+	assertMigrationActive(c, s.State2)
+	model, err := s.State2.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.MigrationMode(), gc.Equals, state.MigrationModeExporting)
+}
+
+func (s *MigrationSuite) TestCreateOfflineWithTarget(c *gc.C) {
+	spec := s.stdSpec
+	spec.Offline = true
+	_, err := s.State2.CreateMigration(spec)
+	c.Assert(err, gc.ErrorMatches, "TargetInfo for offline migration not valid")
+}
+
+func (s *MigrationSuite) TestSetTarget(c *gc.C) {
+	mig, err := s.State2.CreateMigration(state.MigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		Offline:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	target := migration.TargetInfo{
+		ControllerTag:   s.stdSpec.TargetInfo.ControllerTag,
+		ControllerAlias: "target-controller",
+		Addrs:           []string{"1.2.3.4:5555"},
+		CACert:          "cert",
+		AuthTag:         names.NewUserTag("user"),
+	}
+
+	err = mig.SetTarget(target)
+	c.Assert(err, gc.ErrorMatches, `migration ".*" is in the QUIESCE phase, not IMPORT`)
+
+	c.Assert(mig.SetPhase(migration.IMPORT), jc.ErrorIsNil)
+	c.Assert(mig.SetTarget(target), jc.ErrorIsNil)
+	c.Check(mig.StatusMessage(), gc.Equals, "model imported into target controller")
+
+	mig2, err := s.State2.LatestMigration()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := mig2.TargetInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*info, jc.DeepEquals, target)
+
+	err = mig2.SetTarget(target)
+	c.Assert(err, gc.ErrorMatches, `target of migration ".*" is already set`)
+}
+
+func (s *MigrationSuite) TestSetTargetNotOffline(c *gc.C) {
+	mig, err := s.State2.CreateMigration(s.stdSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mig.SetTarget(s.stdSpec.TargetInfo)
+	c.Assert(err, gc.ErrorMatches, `migration ".*" is not an offline migration`)
+}
+
+func (s *MigrationSuite) TestSetTargetInvalid(c *gc.C) {
+	mig, err := s.State2.CreateMigration(state.MigrationSpec{
+		InitiatedBy: names.NewUserTag("admin"),
+		Offline:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mig.SetPhase(migration.IMPORT), jc.ErrorIsNil)
+
+	err = mig.SetTarget(migration.TargetInfo{
+		ControllerTag: s.stdSpec.TargetInfo.ControllerTag,
+	})
+	c.Assert(err, gc.ErrorMatches, "empty Addrs not valid")
+
+	err = mig.SetTarget(migration.TargetInfo{
+		ControllerTag: names.NewControllerTag(s.State.ControllerUUID()),
+		Addrs:         []string{"1.2.3.4:5555"},
+		AuthTag:       names.NewUserTag("user"),
+	})
+	c.Assert(err, gc.ErrorMatches, "model already attached to target controller")
+}
+
 func (s *MigrationSuite) TestIsMigrationActive(c *gc.C) {
 	check := func(expected bool) {
 		isActive, err := s.State2.IsMigrationActive()
@@ -224,8 +224,10 @@ func (p *modelSummaryProcessor) fillInPermissions(permissionIds []string) error
 			continue
 		}
 		details := &p.summaries[modelIdx]
+		// The user may have access both directly and through
+		// groups; the highest access wins.
 		access := permission.Access(doc.Access)
-		if err := access.Validate(); err == nil {
+		if err := access.Validate(); err == nil && access.GreaterModelAccessThan(details.Access) {
 			details.Access = access
 		}
 	}
@@ -408,10 +410,20 @@ func (p *modelSummaryProcessor) fillInJustUser() error {
 	// TODO(jam): 2017-11-27 ensure that we have appropriate indexes so that users that aren't "admin" and only see a couple
 	// models don't do a COLLSCAN on the table.
 	username := strings.ToLower(p.user.Name())
+	subjects := []string{userGlobalKey(username)}
+	groups, err := p.st.GroupsForUser(p.user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, group := range groups {
+		subjects = append(subjects, groupGlobalKey(group))
+	}
 	var permissionIds []string
 	for _, modelUUID := range p.modelUUIDs {
-		permId := permissionID(modelKey(modelUUID), userGlobalKey(username))
-		permissionIds = append(permissionIds, permId)
+		for _, subject := range subjects {
+			permId := permissionID(modelKey(modelUUID), subject)
+			permissionIds = append(permissionIds, permId)
+		}
 	}
 	if err := p.fillInPermissions(permissionIds); err != nil {
 		return errors.Trace(err)
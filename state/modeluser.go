@@ -157,14 +157,14 @@ func (st *State) removeModelUser(user names.UserTag) error {
 
 // isUserSuperuser if this user has the Superuser access on the controller.
 func (st *State) isUserSuperuser(user names.UserTag) (bool, error) {
-	access, err := st.UserAccess(user, st.controllerTag)
+	access, err := st.UserPermission(user, st.controllerTag)
 	if err != nil {
 		// TODO(jam): 2017-11-27 We weren't suppressing NotFound here so that we would know when someone asked for
 		// the list of models of a user that doesn't exist.
 		// However, now we will not even check if its a known user if they aren't asking for all=true.
 		return false, errors.Trace(err)
 	}
-	isControllerSuperuser := (access.Access == permission.SuperuserAccess)
+	isControllerSuperuser := (access == permission.SuperuserAccess)
 	return isControllerSuperuser, nil
 }
 
@@ -244,6 +244,18 @@ func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.
 			closer()
 			return nil, nil, errors.Trace(err)
 		}
+		groupModelUUIDs, err := st.groupModelUUIDs(user)
+		if err != nil {
+			closer()
+			return nil, nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
+		tokenModelUUIDs, err := st.serviceAccountModelUUIDs(user)
+		if err != nil {
+			closer()
+			return nil, nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, tokenModelUUIDs...)
 		modelQuery = models.Find(bson.M{
 			"_id":            bson.M{"$in": modelUUIDs},
 			"migration-mode": bson.M{"$ne": MigrationModeImporting},
@@ -313,23 +325,23 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 	// this case the only relevant one is superuser.
 	// The mgo query below wont work for superuser case because it needs at
 	// least one model user per model.
-	access, err := st.UserAccess(user, st.controllerTag)
+	access, err := st.UserPermission(user, st.controllerTag)
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
 
 	var modelUUIDs []string
-	if access.Access == permission.SuperuserAccess {
+	if access == permission.SuperuserAccess {
 		var err error
 		modelUUIDs, err = st.AllModelUUIDs()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 	} else {
-		// Since there are no groups at this stage, the simplest way to get all
-		// the models that a particular user can see is to look through the
-		// model user collection. A raw collection is required to support
-		// queries across multiple models.
+		// The models that a particular user can see are those they are
+		// a model user of, plus those granted to any of their groups.
+		// A raw collection is required to support queries across
+		// multiple models.
 		modelUsers, userCloser := st.db().GetRawCollection(modelUsersC)
 		defer userCloser()
 
@@ -341,6 +353,16 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 		for _, doc := range userSlice {
 			modelUUIDs = append(modelUUIDs, doc.ObjectUUID)
 		}
+		groupModelUUIDs, err := st.groupModelUUIDs(user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
+		tokenModelUUIDs, err := st.serviceAccountModelUUIDs(user)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, tokenModelUUIDs...)
 	}
 
 	modelsColl, close := st.db().GetCollection(modelsC)
@@ -368,14 +390,14 @@ func (st *State) IsControllerAdmin(user names.UserTag) (bool, error) {
 	if err != nil {
 		return false, errors.Trace(err)
 	}
-	ua, err := st.UserAccess(user, model.ControllerTag())
+	access, err := st.UserPermission(user, model.ControllerTag())
 	if errors.IsNotFound(err) {
 		return false, nil
 	}
 	if err != nil {
 		return false, errors.Trace(err)
 	}
-	return ua.Access == permission.SuperuserAccess, nil
+	return access == permission.SuperuserAccess, nil
 }
 
 func (st *State) isControllerOrModelAdmin(user names.UserTag) (bool, error) {
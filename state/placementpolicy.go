@@ -0,0 +1,327 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/instance"
+	stateerrors "github.com/juju/juju/state/errors"
+)
+
+// placementPolicyDoc is the persistent form of an application's
+// placement policy.
+type placementPolicyDoc struct {
+	AntiAffinity []string `bson:"anti-affinity,omitempty"`
+	Strategy     string   `bson:"strategy,omitempty"`
+}
+
+// PlacementPolicy returns the rules for placing the units of the
+// application on machines.
+func (a *Application) PlacementPolicy() application.PlacementPolicy {
+	if a.doc.PlacementPolicy == nil {
+		return application.PlacementPolicy{}
+	}
+	return application.PlacementPolicy{
+		AntiAffinity: a.doc.PlacementPolicy.AntiAffinity,
+		Strategy:     application.PlacementStrategy(a.doc.PlacementPolicy.Strategy),
+	}
+}
+
+// SetPlacementPolicy replaces the rules for placing the units of the
+// application on machines. The policy applies to units assigned from
+// now on; units already assigned are not moved.
+func (a *Application) SetPlacementPolicy(policy application.PlacementPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set placement policy of application %q", a)
+	if err := policy.Validate(a.doc.Name); err != nil {
+		return errors.Trace(err)
+	}
+	var doc *placementPolicyDoc
+	update := bson.D{{"$unset", bson.D{{"placement-policy", nil}}}}
+	if !policy.IsEmpty() {
+		doc = &placementPolicyDoc{
+			AntiAffinity: policy.AntiAffinity,
+			Strategy:     string(policy.Strategy),
+		}
+		update = bson.D{{"$set", bson.D{{"placement-policy", doc}}}}
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, applicationNotAliveErr)
+	}
+	a.doc.PlacementPolicy = doc
+	return nil
+}
+
+// hostPrincipals records the principal units and containers of the
+// machines on a top level host, that is the host and its containers.
+type hostPrincipals struct {
+	// principals holds the names of the principal units on each
+	// machine, keyed by machine ID.
+	principals map[string][]string
+
+	// children holds the IDs of the containers on each machine, keyed
+	// by machine ID.
+	children map[string][]string
+}
+
+// readHostPrincipals returns the principal units and containers of the
+// machines on the top level host of the machine.
+func readHostPrincipals(st *State, machineId string) (*hostPrincipals, error) {
+	host := TopParentId(machineId)
+	query := bson.D{{"$or", []bson.D{
+		{{"machineid", host}},
+		{{"machineid", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(host+"/")}}},
+	}}}
+
+	machines, closer := st.db().GetCollection(machinesC)
+	defer closer()
+	var machineDocs []struct {
+		Id         string   `bson:"machineid"`
+		Principals []string `bson:"principals"`
+	}
+	if err := machines.Find(query).Select(bson.D{{"machineid", 1}, {"principals", 1}}).All(&machineDocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	containerRefs, closer := st.db().GetCollection(containerRefsC)
+	defer closer()
+	var refDocs []machineContainers
+	if err := containerRefs.Find(query).All(&refDocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := &hostPrincipals{
+		principals: make(map[string][]string),
+		children:   make(map[string][]string),
+	}
+	for _, doc := range machineDocs {
+		result.principals[doc.Id] = doc.Principals
+	}
+	for _, doc := range refDocs {
+		result.children[doc.Id] = doc.Children
+	}
+	return result, nil
+}
+
+// applications returns the names of the applications with principal
+// units on the host, ignoring the named unit.
+func (h *hostPrincipals) applications(ignoreUnit string) set.Strings {
+	apps := set.NewStrings()
+	for _, unitNames := range h.principals {
+		for _, unitName := range unitNames {
+			if unitName != ignoreUnit {
+				apps.Add(unitAppName(unitName))
+			}
+		}
+	}
+	return apps
+}
+
+// assertUnchangedOps returns txn.Ops that assert that no principal
+// units or containers have been added to, or removed from, the
+// machines on the host since it was read.
+func (h *hostPrincipals) assertUnchangedOps(st *State) []txn.Op {
+	var ops []txn.Op
+	for _, id := range sortedKeys(h.principals) {
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     st.docID(id),
+			Assert: assertFieldUnchanged("principals", h.principals[id]),
+		})
+	}
+	for _, id := range sortedKeys(h.children) {
+		ops = append(ops, txn.Op{
+			C:      containerRefsC,
+			Id:     st.docID(id),
+			Assert: assertFieldUnchanged("children", h.children[id]),
+		})
+	}
+	return ops
+}
+
+// assertFieldUnchanged returns an assertion that the list held by the
+// named field is the given one, treating a missing field as empty.
+func assertFieldUnchanged(field string, values []string) bson.D {
+	if len(values) == 0 {
+		return bson.D{{"$or", []bson.D{
+			{{field, bson.D{{"$size", 0}}}},
+			{{field, bson.D{{"$exists", false}}}},
+		}}}
+	}
+	return bson.D{{field, values}}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := set.NewStrings()
+	for key := range m {
+		keys.Add(key)
+	}
+	return keys.SortedValues()
+}
+
+// checkPlacementPolicy returns an error satisfying
+// stateerrors.IsPlacementPolicyError if placing the unit on the
+// machine would break the placement policy of its application, or
+// the anti-affinity of an application already on the machine's host.
+//
+// If the placement of the unit is constrained by any policy, the
+// returned txn.Ops assert that the principal units on the machine's
+// host are unchanged, so that concurrent assignments can't break the
+// policy.
+func (u *Unit) checkPlacementPolicy(machineId string) ([]txn.Op, error) {
+	app, err := u.Application()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy := app.PlacementPolicy()
+	constrained := !policy.IsEmpty()
+	if !constrained {
+		// The unit may still be kept away by other applications.
+		applications, closer := u.st.db().GetCollection(applicationsC)
+		defer closer()
+		n, err := applications.Find(bson.D{{"placement-policy.anti-affinity", app.doc.Name}}).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constrained = n > 0
+	}
+	if !constrained {
+		return nil, nil
+	}
+
+	principals, err := readHostPrincipals(u.st, machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostApps := principals.applications(u.doc.Name)
+	host := TopParentId(machineId)
+	if policy.Strategy == application.PlacementStrategySpread && hostApps.Contains(app.doc.Name) {
+		return nil, stateerrors.NewPlacementPolicyError(
+			"placement policy of %q spreads its units, and machine %s already hosts one", app.doc.Name, host)
+	}
+	for _, other := range hostApps.SortedValues() {
+		if other == app.doc.Name {
+			continue
+		}
+		if policy.HasAntiAffinity(other) {
+			return nil, stateerrors.NewPlacementPolicyError(
+				"placement policy of %q has anti-affinity with %q, which has units on machine %s", app.doc.Name, other, host)
+		}
+		otherApp, err := u.st.Application(other)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if otherApp.PlacementPolicy().HasAntiAffinity(app.doc.Name) {
+			return nil, stateerrors.NewPlacementPolicyError(
+				"placement policy of %q, which has units on machine %s, has anti-affinity with %q", other, host, app.doc.Name)
+		}
+	}
+	return principals.assertUnchangedOps(u.st), nil
+}
+
+// packHost returns the id of a host machine already running units of
+// the unit's application on which a new container of the given type
+// can take the unit, or "" if there is none.
+func (u *Unit) packHost(containerType instance.ContainerType) (string, error) {
+	machineIds, err := ApplicationMachines(u.st, u.doc.Application)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	hosts := set.NewStrings()
+	for _, id := range machineIds {
+		hosts.Add(TopParentId(id))
+	}
+	for _, id := range hosts.SortedValues() {
+		m, err := u.st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		if m.Life() != Alive || m.doc.Series != u.doc.Series {
+			continue
+		}
+		if supported, known := m.SupportedContainers(); known && !supportsContainerType(supported, containerType) {
+			continue
+		}
+		if locked, err := m.IsLockedForSeriesUpgrade(); err != nil {
+			return "", errors.Trace(err)
+		} else if locked {
+			continue
+		}
+		if _, err := u.checkPlacementPolicy(id); stateerrors.IsPlacementPolicyError(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		return id, nil
+	}
+	return "", nil
+}
+
+func supportsContainerType(supported []instance.ContainerType, containerType instance.ContainerType) bool {
+	for _, ct := range supported {
+		if ct == containerType {
+			return true
+		}
+	}
+	return false
+}
+
+// distributionGroupApplications returns the names of the applications
+// whose instances the instances of the named application should be
+// distributed away from: the application itself and those it has
+// anti-affinity with. Applications that pack their units are not
+// distributed.
+func distributionGroupApplications(st *State, appName string) ([]string, error) {
+	app, err := st.Application(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policy := app.PlacementPolicy()
+	if policy.Strategy == application.PlacementStrategyPack {
+		return nil, nil
+	}
+	return append([]string{appName}, policy.AntiAffinity...), nil
+}
+
+// ApplicationDistributionGroup returns the instance IDs of provisioned
+// machines that the instances of the named application should be
+// distributed away from, taking the application's placement policy
+// into account.
+func ApplicationDistributionGroup(st *State, appName string) ([]instance.Id, error) {
+	apps, err := distributionGroupApplications(st, appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instanceIds := set.NewStrings()
+	for _, name := range apps {
+		ids, err := ApplicationInstances(st, name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, id := range ids {
+			instanceIds.Add(string(id))
+		}
+	}
+	result := make([]instance.Id, 0, instanceIds.Size())
+	for _, id := range instanceIds.SortedValues() {
+		result = append(result, instance.Id(id))
+	}
+	return result, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/state"
+	stateerrors "github.com/juju/juju/state/errors"
+)
+
+type PlacementPolicySuite struct {
+	ConnSuite
+	wordpress *state.Application
+	mysql     *state.Application
+}
+
+var _ = gc.Suite(&PlacementPolicySuite{})
+
+func (s *PlacementPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *PlacementPolicySuite) setPolicy(c *gc.C, app *state.Application, args string) {
+	policy, err := application.ParsePlacementPolicy(args)
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.SetPlacementPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) addUnit(c *gc.C, app *state.Application) *state.Unit {
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicy(c *gc.C) {
+	c.Assert(s.wordpress.PlacementPolicy().IsEmpty(), jc.IsTrue)
+
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql strategy=spread")
+	expected := application.PlacementPolicy{
+		AntiAffinity: []string{"mysql"},
+		Strategy:     application.PlacementStrategySpread,
+	}
+	c.Assert(s.wordpress.PlacementPolicy(), jc.DeepEquals, expected)
+	err := s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpress.PlacementPolicy(), jc.DeepEquals, expected)
+
+	err = s.wordpress.SetPlacementPolicy(application.PlacementPolicy{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.wordpress.PlacementPolicy().IsEmpty(), jc.IsTrue)
+}
+
+func (s *PlacementPolicySuite) TestSetPlacementPolicyInvalid(c *gc.C) {
+	err := s.wordpress.SetPlacementPolicy(application.PlacementPolicy{Strategy: "scatter"})
+	c.Assert(err, gc.ErrorMatches, `cannot set placement policy of application "wordpress": placement strategy "scatter" not valid`)
+	err = s.wordpress.SetPlacementPolicy(application.PlacementPolicy{AntiAffinity: []string{"wordpress"}})
+	c.Assert(err, gc.ErrorMatches, `cannot set placement policy of application "wordpress": anti-affinity of "wordpress" with itself not valid`)
+}
+
+func (s *PlacementPolicySuite) TestAntiAffinity(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.mysql).AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.addUnit(c, s.wordpress).AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: `+
+		`placement policy of "wordpress" has anti-affinity with "mysql", which has units on machine 0`)
+	c.Assert(err, jc.Satisfies, stateerrors.IsPlacementPolicyError)
+}
+
+func (s *PlacementPolicySuite) TestAntiAffinityOfOtherApplication(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.wordpress).AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A container on the same host is not allowed either.
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.mysql).AssignToMachine(container)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "mysql/0" to machine 0/lxd/0: `+
+		`placement policy of "wordpress", which has units on machine 0, has anti-affinity with "mysql"`)
+}
+
+func (s *PlacementPolicySuite) TestAntiAffinityConcurrentAssignment(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.addUnit(c, s.mysql)
+	wordpress := s.addUnit(c, s.wordpress)
+
+	// A mysql unit is assigned to a container on the host after the
+	// policy has been checked, but before the assignment is made.
+	defer state.SetBeforeHooks(c, s.State, func() {
+		container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		}, machine.Id(), instance.LXD)
+		c.Assert(err, jc.ErrorIsNil)
+		err = mysql.AssignToMachine(container)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = wordpress.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: `+
+		`placement policy of "wordpress" has anti-affinity with "mysql", which has units on machine 0`)
+}
+
+func (s *PlacementPolicySuite) TestSpread(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "strategy=spread")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.wordpress).AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	placement := &instance.Placement{Scope: string(instance.LXD), Directive: machine.Id()}
+	err = s.State.AssignUnitWithPlacement(s.addUnit(c, s.wordpress), placement)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/1" to machine 0/lxd/0: `+
+		`placement policy of "wordpress" spreads its units, and machine 0 already hosts one`)
+
+	// Other applications are not affected.
+	err = s.addUnit(c, s.mysql).AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *PlacementPolicySuite) TestAssignCleanSkipsAntiAffinity(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.mysql).AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The clean host of the mysql container is passed over.
+	unit := s.addUnit(c, s.wordpress)
+	err = s.State.AssignUnit(unit, state.AssignClean)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state.TopParentId(machineId), gc.Not(gc.Equals), machine.Id())
+}
+
+func (s *PlacementPolicySuite) TestPackContainers(c *gc.C) {
+	s.setPolicy(c, s.wordpress, "strategy=pack")
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.addUnit(c, s.wordpress).AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit := s.addUnit(c, s.wordpress)
+	err = s.State.AssignUnitWithPlacement(unit, &instance.Placement{Scope: string(instance.LXD)})
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, "0/lxd/0")
+}
+
+func (s *PlacementPolicySuite) TestApplicationDistributionGroup(c *gc.C) {
+	for i, app := range []*state.Application{s.wordpress, s.mysql} {
+		machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		err = machine.SetProvisioned(instance.Id([]string{"inst-0", "inst-1"}[i]), "", "fake_nonce", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.addUnit(c, app).AssignToMachine(machine)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	group, err := state.ApplicationDistributionGroup(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group, jc.DeepEquals, []instance.Id{"inst-0"})
+
+	s.setPolicy(c, s.wordpress, "anti-affinity=mysql")
+	group, err = state.ApplicationDistributionGroup(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group, jc.DeepEquals, []instance.Id{"inst-0", "inst-1"})
+
+	s.setPolicy(c, s.wordpress, "strategy=pack")
+	group, err = state.ApplicationDistributionGroup(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group, gc.HasLen, 0)
+}
@@ -0,0 +1,293 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/permission"
+)
+
+// roleDoc represents a custom role, made up of capabilities and
+// facade method patterns, that restricts the API calls a user can
+// make.
+type roleDoc struct {
+	DocID       string    `bson:"_id"`
+	Permissions []string  `bson:"permissions"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+}
+
+// Role represents a custom role in the controller.
+type Role struct {
+	st  *State
+	doc roleDoc
+}
+
+// Name returns the name of the role.
+func (r *Role) Name() string {
+	return r.doc.DocID
+}
+
+// Permissions returns the capabilities and facade method patterns
+// that make up the role.
+func (r *Role) Permissions() []string {
+	return append([]string(nil), r.doc.Permissions...)
+}
+
+// CreatedBy returns the name of the user that created the role.
+func (r *Role) CreatedBy() string {
+	return r.doc.CreatedBy
+}
+
+// DateCreated returns when the role was created in UTC.
+func (r *Role) DateCreated() time.Time {
+	return r.doc.DateCreated.UTC()
+}
+
+// Definition returns the role as used to check API calls.
+func (r *Role) Definition() permission.Role {
+	return permission.Role{
+		Name:        r.doc.DocID,
+		Permissions: r.Permissions(),
+	}
+}
+
+// AddRole adds a custom role with the given name and permissions to
+// the controller.
+func (st *State) AddRole(name string, permissions []string, createdBy names.UserTag) (*Role, error) {
+	if err := (permission.Role{Name: name, Permissions: permissions}).Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	role := &Role{
+		st: st,
+		doc: roleDoc{
+			DocID:       name,
+			Permissions: permissions,
+			CreatedBy:   createdBy.Id(),
+			DateCreated: st.nowToTheSecond(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      rolesC,
+		Id:     name,
+		Assert: txn.DocMissing,
+		Insert: &role.doc,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("role %q", name)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return role, nil
+}
+
+// Role returns the custom role with the given name.
+func (st *State) Role(name string) (*Role, error) {
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var doc roleDoc
+	err := roles.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("role %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get role %q", name)
+	}
+	return &Role{st: st, doc: doc}, nil
+}
+
+// AllRoles returns all the custom roles in the controller, sorted by
+// name.
+func (st *State) AllRoles() ([]*Role, error) {
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var docs []roleDoc
+	if err := roles.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all roles")
+	}
+	result := make([]*Role, len(docs))
+	for i, doc := range docs {
+		result[i] = &Role{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveRole removes the custom role, along with all assignments of
+// the role to users.
+func (st *State) RemoveRole(name string) error {
+	ops, err := st.removeInCollectionOps(roleAssignmentsC, bson.D{{"role", name}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      rolesC,
+		Id:     name,
+		Assert: txn.DocExists,
+		Remove: true,
+	})
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("role %q", name)
+	}
+	return errors.Trace(err)
+}
+
+// roleAssignmentDoc records the role assigned to a user on a model
+// or controller.
+type roleAssignmentDoc struct {
+	DocID     string `bson:"_id"`
+	TargetTag string `bson:"target"`
+	User      string `bson:"user"`
+	Role      string `bson:"role"`
+}
+
+// RoleAssignment describes the role assigned to a user on a model or
+// controller.
+type RoleAssignment struct {
+	User   names.UserTag
+	Target names.Tag
+	Role   string
+}
+
+func roleAssignmentID(user names.UserTag, target names.Tag) string {
+	return fmt.Sprintf("%s#%s", target.String(), userAccessID(user))
+}
+
+func validateRoleTarget(target names.Tag) error {
+	switch target.(type) {
+	case names.ModelTag, names.ControllerTag:
+		return nil
+	}
+	return errors.NotValidf("role target %q", names.ReadableString(target))
+}
+
+// AssignRole assigns the custom role to the user on the model or
+// controller, replacing any role previously assigned there. A role
+// assigned on a model takes precedence over one assigned on the
+// controller.
+func (st *State) AssignRole(user names.UserTag, target names.Tag, role string) error {
+	if err := validateRoleTarget(target); err != nil {
+		return errors.Trace(err)
+	}
+	doc := &roleAssignmentDoc{
+		DocID:     roleAssignmentID(user, target),
+		TargetTag: target.String(),
+		User:      userAccessID(user),
+		Role:      role,
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Role(role); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      rolesC,
+			Id:     role,
+			Assert: txn.DocExists,
+		}}
+		if _, err := st.roleAssignment(doc.DocID); errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      roleAssignmentsC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      roleAssignmentsC,
+			Id:     doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"role", role}}}},
+		}), nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// UnassignRole removes the role assigned to the user on the model or
+// controller.
+func (st *State) UnassignRole(user names.UserTag, target names.Tag) error {
+	id := roleAssignmentID(user, target)
+	ops := []txn.Op{{
+		C:      roleAssignmentsC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("role for %q on %s", user.Id(), names.ReadableString(target))
+	}
+	return errors.Trace(err)
+}
+
+// UserRole returns the custom role assigned to the user on the model
+// or controller. It returns a NotFound error if the user has no role
+// there.
+func (st *State) UserRole(user names.UserTag, target names.Tag) (*Role, error) {
+	doc, err := st.roleAssignment(roleAssignmentID(user, target))
+	if errors.IsNotFound(err) {
+		return nil, errors.NotFoundf("role for %q on %s", user.Id(), names.ReadableString(target))
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.Role(doc.Role)
+}
+
+// RoleAssignments returns the assignments of the named role to users,
+// or all role assignments if no role is given.
+func (st *State) RoleAssignments(role string) ([]RoleAssignment, error) {
+	assignments, closer := st.db().GetCollection(roleAssignmentsC)
+	defer closer()
+
+	var query bson.D
+	if role != "" {
+		query = bson.D{{"role", role}}
+	}
+	var docs []roleAssignmentDoc
+	if err := assignments.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get role assignments")
+	}
+	result := make([]RoleAssignment, len(docs))
+	for i, doc := range docs {
+		target, err := names.ParseTag(doc.TargetTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result[i] = RoleAssignment{
+			User:   names.NewUserTag(doc.User),
+			Target: target,
+			Role:   doc.Role,
+		}
+	}
+	return result, nil
+}
+
+func (st *State) roleAssignment(id string) (*roleAssignmentDoc, error) {
+	assignments, closer := st.db().GetCollection(roleAssignmentsC)
+	defer closer()
+
+	var doc roleAssignmentDoc
+	err := assignments.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("role assignment %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get role assignment %q", id)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+type RoleSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RoleSuite{})
+
+func (s *RoleSuite) TestAddRole(c *gc.C) {
+	role, err := s.State.AddRole("operator", []string{"ssh", "run-actions"}, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Name(), gc.Equals, "operator")
+	c.Assert(role.Permissions(), jc.DeepEquals, []string{"ssh", "run-actions"})
+	c.Assert(role.CreatedBy(), gc.Equals, s.Owner.Id())
+	c.Assert(role.Definition(), jc.DeepEquals, permission.Role{
+		Name:        "operator",
+		Permissions: []string{"ssh", "run-actions"},
+	})
+
+	_, err = s.State.AddRole("operator", []string{"ssh"}, s.Owner)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	_, err = s.State.AddRole("deployer", []string{"fly"}, s.Owner)
+	c.Assert(err, gc.ErrorMatches, `role permission "fly" not valid`)
+
+	roles, err := s.State.AllRoles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roles, gc.HasLen, 1)
+	c.Assert(roles[0].Name(), gc.Equals, "operator")
+}
+
+func (s *RoleSuite) TestAssignRole(c *gc.C) {
+	alice := names.NewUserTag("alice")
+	modelTag := s.Model.ModelTag()
+	_, err := s.State.AddRole("operator", []string{"ssh"}, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRole("deployer", []string{"deploy"}, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.UserRole(alice, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.AssignRole(alice, modelTag, "operator")
+	c.Assert(err, jc.ErrorIsNil)
+	role, err := s.State.UserRole(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Name(), gc.Equals, "operator")
+
+	// Assigning another role replaces the first.
+	err = s.State.AssignRole(alice, modelTag, "deployer")
+	c.Assert(err, jc.ErrorIsNil)
+	role, err = s.State.UserRole(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Name(), gc.Equals, "deployer")
+
+	assignments, err := s.State.RoleAssignments("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assignments, jc.DeepEquals, []state.RoleAssignment{{
+		User:   alice,
+		Target: modelTag,
+		Role:   "deployer",
+	}})
+
+	err = s.State.AssignRole(alice, modelTag, "missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.AssignRole(alice, names.NewCloudTag("dummy"), "operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.UnassignRole(alice, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserRole(alice, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.UnassignRole(alice, modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RoleSuite) TestRemoveRole(c *gc.C) {
+	alice := names.NewUserTag("alice")
+	_, err := s.State.AddRole("operator", []string{"ssh"}, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignRole(alice, s.State.ControllerTag(), "operator")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveRole("operator")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Role("operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.UserRole(alice, s.State.ControllerTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveRole("operator")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -225,7 +225,25 @@ func (st *State) RemoveDyingModel() error {
 // for the current model. This method asserts that the model's migration mode
 // is "importing".
 func (st *State) RemoveImportingModelDocs() error {
-	err := st.removeAllModelDocs(bson.D{{"migration-mode", MigrationModeImporting}})
+	// Groups created by the import aren't model documents, but they
+	// are removed along with the model.
+	ops, err := st.removeImportedGroupsOps(st.ModelUUID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		ops = append([]txn.Op{{
+			C:      modelsC,
+			Id:     st.ModelUUID(),
+			Assert: bson.D{{"migration-mode", MigrationModeImporting}},
+		}}, ops...)
+		if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+			return errors.New("can't remove model: model not being imported for migration")
+		} else if err != nil {
+			return errors.Annotate(err, "removing imported groups")
+		}
+	}
+	err = st.removeAllModelDocs(bson.D{{"migration-mode", MigrationModeImporting}})
 	if errors.Cause(err) == txn.ErrAborted {
 		return errors.New("can't remove model: model not being imported for migration")
 	}
@@ -603,18 +621,6 @@ func (st *State) checkCanUpgradeIAAS(currentVersion, newVersion string) error {
 // running the current version). If this is a hosted model, newVersion
 // cannot be higher than the controller version.
 func (st *State) SetModelAgentVersion(newVersion version.Number, ignoreAgentVersions bool) (err error) {
-	if newVersion.Compare(jujuversion.Current) > 0 && !st.IsController() {
-		return errors.Errorf("model cannot be upgraded to %s while the controller is %s: upgrade 'controller' model first",
-			newVersion.String(),
-			jujuversion.Current,
-		)
-	}
-
-	model, err := st.Model()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	isCAAS := model.Type() == ModelTypeCAAS
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		settings, err := readSettings(st.db(), settingsC, modelGlobalKey)
 		if err != nil {
@@ -633,16 +639,8 @@ func (st *State) SetModelAgentVersion(newVersion version.Number, ignoreAgentVers
 			return nil, jujutxn.ErrNoOperations
 		}
 
-		if !ignoreAgentVersions {
-			if isCAAS {
-				if err := st.checkCanUpgradeCAAS(currentVersion, newVersion.String()); err != nil {
-					return nil, errors.Trace(err)
-				}
-			} else {
-				if err := st.checkCanUpgradeIAAS(currentVersion, newVersion.String()); err != nil {
-					return nil, errors.Trace(err)
-				}
-			}
+		if err := st.CheckModelAgentVersion(newVersion, ignoreAgentVersions); err != nil {
+			return nil, errors.Trace(err)
 		}
 
 		ops := []txn.Op{
@@ -675,6 +673,48 @@ func (st *State) SetModelAgentVersion(newVersion version.Number, ignoreAgentVers
 	return errors.Trace(err)
 }
 
+// CheckModelAgentVersion returns an error if SetModelAgentVersion would
+// refuse to change the agent version for the model to the given version.
+// Nothing is changed. Agents not running the current version are
+// reported in preference to an upgrade in progress, as they are the
+// more likely reason for the upgrade not having finished.
+func (st *State) CheckModelAgentVersion(newVersion version.Number, ignoreAgentVersions bool) error {
+	if newVersion.Compare(jujuversion.Current) > 0 && !st.IsController() {
+		return errors.Errorf("model cannot be upgraded to %s while the controller is %s: upgrade 'controller' model first",
+			newVersion.String(),
+			jujuversion.Current,
+		)
+	}
+	if !ignoreAgentVersions {
+		model, err := st.Model()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		settings, err := readSettings(st.db(), settingsC, modelGlobalKey)
+		if err != nil {
+			return errors.Annotatef(err, "model %q", st.modelTag.Id())
+		}
+		currentVersion, ok := settings.Get("agent-version")
+		if !ok {
+			return errors.Errorf("no agent version set in the model")
+		}
+		if model.Type() == ModelTypeCAAS {
+			err = st.checkCanUpgradeCAAS(fmt.Sprint(currentVersion), newVersion.String())
+		} else {
+			err = st.checkCanUpgradeIAAS(fmt.Sprint(currentVersion), newVersion.String())
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if upgrading, err := st.IsUpgrading(); err != nil {
+		return errors.Trace(err)
+	} else if upgrading {
+		return stateerrors.ErrUpgradeInProgress
+	}
+	return nil
+}
+
 // ModelConstraints returns the current model constraints.
 func (st *State) ModelConstraints() (constraints.Value, error) {
 	cons, err := readConstraints(st, modelGlobalKey)
@@ -1010,6 +1050,7 @@ type AddApplicationArgs struct {
 	Series            string
 	Charm             *Charm
 	Channel           csparams.Channel
+	CharmOrigin       *CharmOrigin
 	Storage           map[string]StorageConstraints
 	Devices           map[string]DeviceConstraints
 	AttachStorage     []names.StorageTag
@@ -1035,6 +1076,11 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 	if args.Charm == nil {
 		return nil, errors.Errorf("charm is nil")
 	}
+	if args.CharmOrigin != nil {
+		if err := args.CharmOrigin.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 
 	model, err := st.Model()
 	if err != nil {
@@ -1152,6 +1198,7 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 		Subordinate:   args.Charm.Meta().Subordinate,
 		CharmURL:      args.Charm.URL(),
 		Channel:       string(args.Channel),
+		CharmOrigin:   args.CharmOrigin,
 		RelationCount: len(peers),
 		Life:          Alive,
 
@@ -1602,7 +1649,14 @@ func (p placementData) placementType() placementType {
 
 func (st *State) parsePlacement(placement *instance.Placement) (*placementData, error) {
 	// Extract container type and parent from container placement directives.
+	// A zone directive places the container on a new host in that zone.
 	if container, err := instance.ParseContainerType(placement.Scope); err == nil {
+		if instance.IsZoneDirective(placement.Directive) {
+			return &placementData{
+				containerType: container,
+				directive:     placement.Directive,
+			}, nil
+		}
 		return &placementData{
 			containerType: container,
 			machineId:     placement.Directive,
@@ -1723,10 +1777,19 @@ func (st *State) addMachineWithPlacement(unit *Unit, data *placementData) (*Mach
 			Dirty:       true,
 			Constraints: cons,
 		}
+		if mId == "" && data.directive == "" && app.PlacementPolicy().Strategy == application.PlacementStrategyPack {
+			// Put the container on a host that already runs the
+			// application's units, if there is one.
+			if mId, err = unit.packHost(data.containerType); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 		if mId != "" {
 			return st.AddMachineInsideMachine(template, mId, data.containerType)
 		}
-		return st.AddMachineInsideNewMachine(template, template, data.containerType)
+		parentTemplate := template
+		parentTemplate.Placement = data.directive
+		return st.AddMachineInsideNewMachine(template, parentTemplate, data.containerType)
 	case directivePlacement:
 		return nil, errors.NotSupportedf(
 			"programming error: directly adding a machine for %s with a non-machine placement directive", unit.Name())
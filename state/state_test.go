@@ -955,6 +955,29 @@ func (s *StateSuite) TestAddMachineExtraConstraints(c *gc.C) {
 	c.Assert(mcons, gc.DeepEquals, expectedCons)
 }
 
+func (s *StateSuite) TestAddMachineCloudInitUserData(c *gc.C) {
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:            "quantal",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		CloudInitUserData: "packages: [htop]",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.CloudInitUserData(), gc.Equals, "packages: [htop]")
+
+	m, err = s.State.Machine(m.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.CloudInitUserData(), gc.Equals, "packages: [htop]")
+}
+
+func (s *StateSuite) TestAddMachineInvalidCloudInitUserData(c *gc.C) {
+	_, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:            "quantal",
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		CloudInitUserData: "runcmd: [ls]",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: cloudinit-userdata: runcmd not allowed, use preruncmd or postruncmd instead")
+}
+
 func (s *StateSuite) TestAddMachinePlacementIgnoresModelConstraints(c *gc.C) {
 	err := s.State.SetModelConstraints(constraints.MustParse("mem=4G tags=foo"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -1572,6 +1595,35 @@ func (s *StateSuite) TestAddApplication(c *gc.C) {
 	c.Assert(ch.URL(), gc.DeepEquals, ch.URL())
 }
 
+func (s *StateSuite) TestAddApplicationWithCharmOrigin(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	origin := &state.CharmOrigin{
+		Source:   state.CharmOriginCharmHub,
+		ID:       "dummy-id",
+		Revision: 3,
+	}
+	app, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:        "dummy",
+		Charm:       ch,
+		Channel:     "latest/candidate",
+		CharmOrigin: origin,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.CharmOrigin(), jc.DeepEquals, origin)
+
+	app, err = s.State.Application("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.CharmOrigin(), jc.DeepEquals, origin)
+	c.Assert(string(app.Channel()), gc.Equals, "latest/candidate")
+
+	_, err = s.State.AddApplication(state.AddApplicationArgs{
+		Name:        "another",
+		Charm:       ch,
+		CharmOrigin: &state.CharmOrigin{Source: "elsewhere", ID: "x"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add application "another": charm origin source "elsewhere" not valid`)
+}
+
 func (s *StateSuite) TestAddCAASApplication(c *gc.C) {
 	st := s.Factory.MakeCAASModel(c, nil)
 	defer st.Close()
@@ -3945,6 +3997,45 @@ func (s *StateSuite) TestSetModelAgentVersionMixedVersions(c *gc.C) {
 	assertAgentVersion(c, s.State, "4.5.6")
 }
 
+func (s *StateSuite) TestCheckModelAgentVersion(c *gc.C) {
+	_, currentVersion := s.prepareAgentVersionTests(c, s.State)
+	err := s.State.CheckModelAgentVersion(version.MustParse("4.5.6"), false)
+	c.Assert(err, jc.ErrorIsNil)
+	assertAgentVersion(c, s.State, currentVersion)
+
+	machine, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.MustParseBinary("1.0.1-quantal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CheckModelAgentVersion(version.MustParse("4.5.6"), false)
+	c.Check(err, gc.ErrorMatches, "some agents have not upgraded to the current model version .*: machine-0")
+	err = s.State.CheckModelAgentVersion(version.MustParse("4.5.6"), true)
+	c.Assert(err, jc.ErrorIsNil)
+	assertAgentVersion(c, s.State, currentVersion)
+}
+
+func (s *StateSuite) TestCheckModelAgentVersionFailsIfUpgrading(c *gc.C) {
+	modelConfig, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, ok := modelConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+
+	machine, err := s.State.AddMachine("series", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.MustParseBinary(agentVersion.String() + "-quantal-amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned(instance.Id("i-blah"), "", "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	nextVersion := agentVersion
+	nextVersion.Minor++
+	_, err = s.State.EnsureUpgradeInfo(machine.Tag().Id(), agentVersion, nextVersion)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.CheckModelAgentVersion(nextVersion, true)
+	c.Assert(err, jc.Satisfies, stateerrors.IsUpgradeInProgressError)
+}
+
 func (s *StateSuite) TestSetModelAgentVersionFailsIfUpgrading(c *gc.C) {
 	// Get the agent-version set in the model.
 	modelConfig, err := s.Model.ModelConfig()
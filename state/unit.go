@@ -91,6 +91,7 @@ type unitDoc struct {
 	StorageAttachmentCount int `bson:"storageattachmentcount"`
 	MachineId              string
 	Resolved               ResolvedMode
+	Interrupting           bool         `bson:"interrupting,omitempty"`
 	Tools                  *tools.Tools `bson:",omitempty"`
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
@@ -370,6 +371,12 @@ func (op *UpdateUnitOperation) Build(attempt int) ([]txn.Op, error) {
 		addr := fromNetworkAddress(networkAddr, corenetwork.OriginProvider)
 		containerInfo.Address = &addr
 	}
+	if op.props.SpaceAddresses != nil {
+		containerInfo.SpaceAddresses = nil
+		if len(*op.props.SpaceAddresses) > 0 {
+			containerInfo.SpaceAddresses = fromNetworkAddresses(*op.props.SpaceAddresses, corenetwork.OriginProvider)
+		}
+	}
 	if op.props.Ports != nil {
 		containerInfo.Ports = *op.props.Ports
 	}
@@ -1037,6 +1044,52 @@ func (u *Unit) Resolved() ResolvedMode {
 	return u.doc.Resolved
 }
 
+// Interrupting returns whether the cloud has given notice that it will
+// reclaim the instance hosting the unit.
+func (u *Unit) Interrupting() bool {
+	return u.doc.Interrupting
+}
+
+// SetInterrupting records that the cloud has given notice that it will
+// reclaim the instance hosting the unit, so that the unit agent stops
+// the unit's workload while the instance is still running.
+func (u *Unit) SetInterrupting() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot mark unit %q interrupting", u)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"interrupting", true}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return stateerrors.ErrDead
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	u.doc.Interrupting = true
+	return nil
+}
+
+// ClearInterrupting records that the cloud is no longer going to
+// reclaim the instance hosting the unit, so that the unit agent starts
+// the unit's workload again.
+func (u *Unit) ClearInterrupting() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot clear interrupting for unit %q", u)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"interrupting", nil}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("unit")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	u.doc.Interrupting = false
+	return nil
+}
+
 // IsPrincipal returns whether the unit is deployed in its own container,
 // and can therefore have subordinate applications deployed alongside it.
 func (u *Unit) IsPrincipal() bool {
@@ -1213,6 +1266,22 @@ func (u *Unit) containerAddress() (corenetwork.SpaceAddress, error) {
 	return addr.networkAddress(), nil
 }
 
+// ContainerSpaceAddresses returns the addresses of the pod's container
+// on any secondary networks, each associated with the space of its network.
+func (u *Unit) ContainerSpaceAddresses() (corenetwork.SpaceAddresses, error) {
+	containerInfo, err := u.cloudContainer()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(containerInfo.SpaceAddresses) == 0 {
+		return nil, nil
+	}
+	return networkAddresses(containerInfo.SpaceAddresses), nil
+}
+
 func (u *Unit) scopedAddress(scope string) (corenetwork.SpaceAddress, error) {
 	addresses, err := u.AllAddresses()
 	if err != nil {
@@ -1456,6 +1525,41 @@ func (u *Unit) OpenClosePortsInSubnetOperation(subnetID string, openRanges, clos
 	return machinePorts.OpenClosePortsOperation(u.Name(), openRanges, closeRanges)
 }
 
+// OpenClosePortsOnEndpointsOperation returns a ModelOperation that opens
+// the given port ranges, keyed by the name of the unit endpoint they are
+// opened for, and closes the given port ranges for the unit. Port ranges
+// keyed by an empty endpoint name are opened for all of the unit's
+// endpoints. An error is returned if an endpoint is not defined by the
+// unit's charm.
+func (u *Unit) OpenClosePortsOnEndpointsOperation(openRanges map[string][]corenetwork.PortRange, closeRanges []corenetwork.PortRange) (ModelOperation, error) {
+	machineID, err := u.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Annotatef(err, "unit %q has no assigned machine", u)
+	}
+
+	var app *Application
+	for endpoint := range openRanges {
+		if endpoint == "" {
+			continue
+		}
+		if app == nil {
+			if app, err = u.Application(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if _, err := app.Endpoint(endpoint); err != nil {
+			return nil, errors.Annotatef(err, "cannot open ports for unit %q", u)
+		}
+	}
+
+	machinePorts, err := getOrCreateOpenedMachinePortsInSubnet(u.st, machineID, "")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get or create ports for unit %q", u.Name())
+	}
+
+	return machinePorts.OpenClosePortsOnEndpointsOperation(u.Name(), openRanges, closeRanges)
+}
+
 // OpenedPortsInSubnet returns a slice containing the open port ranges of the
 // unit on the given subnet ID. When subnetID is not empty, it must refer to an
 // existing, alive subnet, otherwise an error is returned.
@@ -1691,6 +1795,10 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 	); err != nil {
 		return nil, errors.Trace(err)
 	}
+	placementOps, err := u.checkPlacementPolicy(m.doc.Id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	storageOps, volumesAttached, filesystemsAttached, err := sb.hostStorageOps(m.doc.Id, storageParams)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1735,6 +1843,7 @@ func (u *Unit) assignToMachineOps(m *Machine, unused bool) ([]txn.Op, error) {
 		removeStagedAssignmentOp(u.doc.DocID),
 	}
 	ops = append(ops, storageOps...)
+	ops = append(ops, placementOps...)
 	return ops, nil
 }
 
@@ -2512,6 +2621,11 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	if cons.HasZones() {
 		suitableTerms = append(suitableTerms, bson.DocElem{"availzone", bson.D{{"$in", *cons.Zones}}})
 	}
+	if cons.HasImageID() {
+		suitableTerms = append(suitableTerms, bson.DocElem{"image", *cons.ImageID})
+	} else if cons.HasImageName() {
+		suitableTerms = append(suitableTerms, bson.DocElem{"image", *cons.ImageName})
+	}
 	if len(suitableTerms) > 0 {
 		instanceDataCollection, closer := db.GetCollection(instanceDataC)
 		defer closer()
@@ -2672,6 +2786,9 @@ func (u *Unit) assignToCleanMaybeEmptyMachineOps(requireEmpty bool) (_ *Machine,
 		if err == nil {
 			return m, ops, nil
 		}
+		if stateerrors.IsPlacementPolicyError(err) {
+			continue
+		}
 		switch errors.Cause(err) {
 		case inUseErr, machineNotAliveErr:
 		default:
@@ -104,6 +104,38 @@ func (s *UnitAssignmentSuite) TestAssignUnitWithPlacementMakesContainerInNewMach
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *UnitAssignmentSuite) TestAssignUnitWithPlacementMakesContainerInNewMachineInZone(c *gc.C) {
+	// Enables juju deploy <charm> --to <container-type>:zone=<zone>
+	// It creates a new machine in the zone with a new container of that type.
+	charm := s.AddTestingCharm(c, "dummy")
+	placement := instance.Placement{Scope: "lxd", Directive: "zone=test"}
+	app, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:      "dummy",
+		Charm:     charm,
+		NumUnits:  1,
+		Placement: []*instance.Placement{&placement},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := app.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+	unit := units[0]
+
+	err = s.State.AssignUnitWithPlacement(unit, &placement)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Placement(), gc.Equals, "")
+	parentId, isContainer := machine.ParentId()
+	c.Assert(isContainer, jc.IsTrue)
+	parent, err := s.State.Machine(parentId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parent.Placement(), gc.Equals, "zone=test")
+}
+
 func (s *UnitAssignmentSuite) TestAssignUnitWithPlacementNewMachinesHaveBindingsAsConstraints(c *gc.C) {
 	specialSpace, err := s.State.AddSpace("special-space", "", nil, false)
 	c.Assert(err, jc.ErrorIsNil)
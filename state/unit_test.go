@@ -1774,6 +1774,45 @@ func (s *UnitSuite) TestGetSetClearResolved(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set resolved mode for unit "wordpress/0": invalid error resolution mode: "foo"`)
 }
 
+func (s *UnitSuite) TestSetInterrupting(c *gc.C) {
+	c.Assert(s.unit.Interrupting(), jc.IsFalse)
+
+	err := s.unit.SetInterrupting()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Interrupting(), jc.IsTrue)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Interrupting(), jc.IsTrue)
+
+	// Marking the unit again is harmless.
+	err = s.unit.SetInterrupting()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitSuite) TestClearInterrupting(c *gc.C) {
+	err := s.unit.SetInterrupting()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.ClearInterrupting()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Interrupting(), jc.IsFalse)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Interrupting(), jc.IsFalse)
+
+	// Clearing the unit again is harmless.
+	err = s.unit.ClearInterrupting()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitSuite) TestSetInterruptingDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.SetInterrupting()
+	c.Assert(err, gc.ErrorMatches, `cannot mark unit "wordpress/0" interrupting: not found or dead`)
+}
+
 func (s *UnitSuite) TestOpenedPortsOnInvalidSubnet(c *gc.C) {
 	s.testOpenedPorts(c, "bad CIDR", `subnet "bad CIDR" not found`)
 }
@@ -2937,6 +2976,47 @@ func (s *CAASUnitSuite) TestUpdateCAASUnitPorts(c *gc.C) {
 	c.Assert(info.Ports(), jc.DeepEquals, []string{"443"})
 }
 
+func (s *CAASUnitSuite) TestUpdateCAASUnitSpaceAddresses(c *gc.C) {
+	existingUnit, err := s.application.AddUnit(state.AddUnitParams{
+		ProviderId: strPtr("unit-uuid"),
+		Address:    strPtr("192.168.1.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr := corenetwork.NewSpaceAddress("10.10.0.5")
+	addr.SpaceID = "1"
+	var updateUnits state.UpdateUnitsOperation
+	updateUnits.Updates = []*state.UpdateUnitOperation{
+		existingUnit.UpdateOperation(state.UnitUpdateProperties{
+			SpaceAddresses: &corenetwork.SpaceAddresses{addr},
+		})}
+	err = s.application.UpdateUnits(&updateUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := existingUnit.ContainerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.SpaceAddresses(), jc.DeepEquals, corenetwork.SpaceAddresses{addr})
+	spaceAddrs, err := existingUnit.ContainerSpaceAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaceAddrs, jc.DeepEquals, corenetwork.SpaceAddresses{addr})
+
+	// The secondary addresses do not affect the unit's own addresses.
+	allAddrs, err := existingUnit.AllAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(allAddrs, jc.DeepEquals, corenetwork.SpaceAddresses{
+		corenetwork.NewScopedSpaceAddress("192.168.1.1", corenetwork.ScopeMachineLocal),
+	})
+
+	updateUnits.Updates = []*state.UpdateUnitOperation{
+		existingUnit.UpdateOperation(state.UnitUpdateProperties{
+			SpaceAddresses: &corenetwork.SpaceAddresses{},
+		})}
+	err = s.application.UpdateUnits(&updateUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	spaceAddrs, err = existingUnit.ContainerSpaceAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaceAddrs, gc.HasLen, 0)
+}
+
 func (s *CAASUnitSuite) TestRemoveUnitDeletesContainerInfo(c *gc.C) {
 	existingUnit, err := s.application.AddUnit(state.AddUnitParams{
 		ProviderId: strPtr("unit-uuid"),
@@ -140,12 +140,41 @@ func NewControllerUserAccess(st *State, userDoc userAccessDoc) (permission.UserA
 	return newUserAccess(perm, userDoc, names.NewControllerTag(userDoc.ObjectUUID)), nil
 }
 
-// UserPermission returns the access permission for the passed subject and target.
+// UserPermission returns the access permission for the passed subject and
+// target. This is the highest of the access granted to the subject directly,
+// the access granted to any group the subject is a member of and, for
+// service accounts, the access granted by their unexpired API tokens.
 func (st *State) UserPermission(subject names.UserTag, target names.Tag) (permission.Access, error) {
 	if err := st.userMayHaveAccess(subject); err != nil {
 		return "", errors.Trace(err)
 	}
+	access, err := st.directUserPermission(subject, target)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	indirectAccess, indirectErr := st.GroupPermission(subject, target)
+	if indirectErr != nil {
+		return "", errors.Trace(indirectErr)
+	}
+	tokenAccess, tokenErr := st.ServiceAccountPermission(subject, target)
+	if tokenErr != nil {
+		return "", errors.Trace(tokenErr)
+	}
+	if greaterAccessForTarget(target, tokenAccess, indirectAccess) {
+		indirectAccess = tokenAccess
+	}
+	if indirectAccess == permission.NoAccess {
+		return access, errors.Trace(err)
+	}
+	if err != nil || greaterAccessForTarget(target, indirectAccess, access) {
+		return indirectAccess, nil
+	}
+	return access, nil
+}
 
+// directUserPermission returns the access permission granted to the
+// subject itself on the target.
+func (st *State) directUserPermission(subject names.UserTag, target names.Tag) (permission.Access, error) {
 	switch target.Kind() {
 	case names.ModelTagKind, names.ControllerTagKind:
 		access, err := st.UserAccess(subject, target)
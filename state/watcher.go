@@ -3761,6 +3761,13 @@ func (st *State) WatchMigrationStatus() NotifyWatcher {
 	return newNotifyCollWatcher(st, migrationsStatusC, isLocalID(st))
 }
 
+// WatchSpaceSubnets returns a NotifyWatcher which triggers whenever
+// subnets are added to, removed from or moved between spaces in the
+// model.
+func (st *State) WatchSpaceSubnets() NotifyWatcher {
+	return newNotifyCollWatcher(st, subnetsC, isLocalID(st))
+}
+
 // WatchMachineRemovals returns a NotifyWatcher which triggers
 // whenever machine removal records are added or removed.
 func (st *State) WatchMachineRemovals() NotifyWatcher {
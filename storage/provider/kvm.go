@@ -0,0 +1,77 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// KVMProviderType is the storage provider type for disks attached
+	// to KVM containers. The disks are created as volumes in a libvirt
+	// storage pool on the host when the container is started.
+	KVMProviderType = storage.ProviderType("kvm")
+
+	// KVMPoolAttr is the storage pool config attribute that names
+	// the libvirt storage pool on the host to create volumes in.
+	KVMPoolAttr = "libvirt-pool"
+)
+
+// kvmProvider describes volumes that the KVM container broker
+// creates on the host when it starts a container.
+type kvmProvider struct{}
+
+var (
+	_ storage.Provider = (*kvmProvider)(nil)
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (*kvmProvider) ValidateConfig(cfg *storage.Config) error {
+	if v, ok := cfg.Attrs()[KVMPoolAttr]; ok {
+		if s, ok := v.(string); !ok || s == "" {
+			return errors.NotValidf("%s %v", KVMPoolAttr, v)
+		}
+	}
+	return nil
+}
+
+// VolumeSource is defined on the Provider interface.
+//
+// Volumes are only created when a KVM container is started, so
+// there is no volume source for the storage provisioner to use.
+func (*kvmProvider) VolumeSource(*storage.Config) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes outside of KVM container creation")
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*kvmProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*kvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*kvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*kvmProvider) Dynamic() bool {
+	return false
+}
+
+// Releasable is defined on the Provider interface.
+func (*kvmProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is defined on the Provider interface.
+func (*kvmProvider) DefaultPools() []*storage.Config {
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&kvmSuite{})
+
+type kvmSuite struct {
+	testing.BaseSuite
+}
+
+func (s *kvmSuite) kvmProvider(c *gc.C) storage.Provider {
+	p, err := provider.CommonStorageProviders().StorageProvider(provider.KVMProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	return p
+}
+
+func (s *kvmSuite) TestValidateConfig(c *gc.C) {
+	p := s.kvmProvider(c)
+	cfg, err := storage.NewConfig("name", provider.KVMProviderType, map[string]interface{}{
+		provider.KVMPoolAttr: "fast",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("name", provider.KVMProviderType, map[string]interface{}{
+		provider.KVMPoolAttr: 42,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, "libvirt-pool 42 not valid")
+}
+
+func (s *kvmSuite) TestNoSources(c *gc.C) {
+	p := s.kvmProvider(c)
+	cfg, err := storage.NewConfig("name", provider.KVMProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = p.VolumeSource(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = p.FilesystemSource(cfg)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *kvmSuite) TestProperties(c *gc.C) {
+	p := s.kvmProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsFalse)
+	c.Assert(p.Releasable(), jc.IsFalse)
+}
@@ -52,6 +52,8 @@ func (s *JujuOSEnvSuite) SetUpTest(c *gc.C) {
 		osenv.JujuControllerEnvKey,
 		osenv.JujuModelEnvKey,
 		osenv.JujuLoggingConfigEnvKey,
+		osenv.JujuAPITokenEnvKey,
+		osenv.JujuStoreAgentEnvKey,
 		osenv.JujuFeatureFlagEnvKey,
 		osenv.JujuFeatures,
 		osenv.XDGDataHome,
@@ -0,0 +1,243 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package oidctest provides a stub OpenID Connect issuer for testing.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+const (
+	// ClientID is the client ID the stub issuer issues tokens to.
+	ClientID = "juju-test"
+
+	// DeviceCode is the device code handed out by the stub
+	// issuer's device authorization endpoint.
+	DeviceCode = "stub-device-code"
+
+	// UserCode is the user code handed out by the stub
+	// issuer's device authorization endpoint.
+	UserCode = "ABCD-EFGH"
+
+	keyID = "stub-key"
+)
+
+// Issuer is a stub OpenID Connect issuer serving a discovery
+// document, a signing key set and the device authorization grant.
+type Issuer struct {
+	// URL holds the issuer URL.
+	URL string
+
+	key    *rsa.PrivateKey
+	server *httptest.Server
+
+	mu sync.Mutex
+	// deviceClaims holds the claims of the token issued once
+	// the device authorization grant has been approved.
+	deviceClaims map[string]interface{}
+	// pendingPolls holds the number of token requests that are
+	// answered with authorization_pending before the grant is
+	// approved.
+	pendingPolls int
+	// denied records whether the user denied the grant.
+	denied bool
+	// keyRequests holds the number of requests for the signing keys.
+	keyRequests int
+}
+
+// NewIssuer starts and returns a new stub issuer. It should be
+// closed with Close when it is no longer needed.
+func NewIssuer() *Issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	iss := &Issuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", iss.serveDiscovery)
+	mux.HandleFunc("/keys", iss.serveKeys)
+	mux.HandleFunc("/device", iss.serveDeviceAuthorization)
+	mux.HandleFunc("/token", iss.serveToken)
+	iss.server = httptest.NewServer(mux)
+	iss.URL = iss.server.URL
+	return iss
+}
+
+// Close shuts the issuer down.
+func (iss *Issuer) Close() {
+	iss.server.Close()
+}
+
+// Claims returns the standard claims for a token issued to the
+// given user, valid for an hour from now.
+func (iss *Issuer) Claims(username string, groups ...string) map[string]interface{} {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":                iss.URL,
+		"aud":                ClientID,
+		"sub":                "subject-" + username,
+		"preferred_username": username,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+	}
+	if len(groups) > 0 {
+		claims["groups"] = groups
+	}
+	return claims
+}
+
+// IDToken returns an ID token holding the given claims, signed
+// with the issuer's key.
+func (iss *Issuer) IDToken(claims map[string]interface{}) string {
+	return iss.sign(claims, iss.key, keyID)
+}
+
+// ForeignIDToken returns an ID token holding the given claims,
+// signed with a key that is not published by the issuer.
+func (iss *Issuer) ForeignIDToken(claims map[string]interface{}) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return iss.sign(claims, key, keyID)
+}
+
+// UnknownKeyIDToken returns an ID token holding the given claims,
+// naming a key ID that is not published by the issuer.
+func (iss *Issuer) UnknownKeyIDToken(claims map[string]interface{}) string {
+	return iss.sign(claims, iss.key, "unknown-key")
+}
+
+func (iss *Issuer) sign(claims map[string]interface{}, key *rsa.PrivateKey, kid string) string {
+	header := encodeJSON(map[string]string{
+		"alg": "RS256",
+		"kid": kid,
+		"typ": "JWT",
+	})
+	signed := header + "." + encodeJSON(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// ApproveDevice arranges for the device authorization grant to be
+// approved after the given number of polls, issuing a token with the
+// given claims.
+func (iss *Issuer) ApproveDevice(pendingPolls int, claims map[string]interface{}) {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.pendingPolls = pendingPolls
+	iss.deviceClaims = claims
+	iss.denied = false
+}
+
+// DenyDevice arranges for the device authorization grant to be
+// denied by the user.
+func (iss *Issuer) DenyDevice() {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.denied = true
+}
+
+func (iss *Issuer) serveDiscovery(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                        iss.URL,
+		"jwks_uri":                      iss.URL + "/keys",
+		"token_endpoint":                iss.URL + "/token",
+		"device_authorization_endpoint": iss.URL + "/device",
+	})
+}
+
+// KeyRequests returns the number of times the issuer's signing keys
+// have been requested.
+func (iss *Issuer) KeyRequests() int {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	return iss.keyRequests
+}
+
+func (iss *Issuer) serveKeys(w http.ResponseWriter, req *http.Request) {
+	iss.mu.Lock()
+	iss.keyRequests++
+	iss.mu.Unlock()
+	pub := iss.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (iss *Issuer) serveDeviceAuthorization(w http.ResponseWriter, req *http.Request) {
+	if req.FormValue("client_id") != ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":      DeviceCode,
+		"user_code":        UserCode,
+		"verification_uri": iss.URL + "/activate",
+		"expires_in":       600,
+		"interval":         1,
+	})
+}
+
+func (iss *Issuer) serveToken(w http.ResponseWriter, req *http.Request) {
+	if req.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" ||
+		req.FormValue("device_code") != DeviceCode {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	switch {
+	case iss.denied:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "access_denied"})
+	case iss.deviceClaims == nil || iss.pendingPolls > 0:
+		iss.pendingPolls--
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": "stub-access-token",
+			"token_type":   "Bearer",
+			"id_token":     iss.IDToken(iss.deviceClaims),
+			"expires_in":   3600,
+		})
+	}
+}
+
+func encodeJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("cannot encode response: %v", err))
+	}
+}
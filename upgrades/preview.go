@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/version"
+)
+
+// PreviewStep describes an upgrade step that would be run by an agent
+// when it is upgraded.
+type PreviewStep struct {
+	// Version is the Juju version for which the step was added.
+	Version version.Number
+
+	// Description is the human readable description of the step.
+	Description string
+
+	// Targets holds the machine types for which the step is run.
+	Targets []Target
+
+	// State is true if the step upgrades the database. Such steps are
+	// run by controllers before any other steps.
+	State bool
+}
+
+// PreviewUpgrade returns the upgrade steps that would be run, in order,
+// by an agent with the given targets upgrading from one version to
+// another. Nothing is run. Only the steps known to this version of Juju
+// are returned; steps for later versions are not.
+func PreviewUpgrade(from, to version.Number, targets []Target) []PreviewStep {
+	var steps []PreviewStep
+	if hasStateTarget(targets) {
+		steps = previewSteps(newOpsIterator(from, to, stateUpgradeOperations()), targets, true)
+	}
+	return append(steps, previewSteps(newOpsIterator(from, to, upgradeOperations()), targets, false)...)
+}
+
+func previewSteps(ops *opsIterator, targets []Target, isState bool) []PreviewStep {
+	var steps []PreviewStep
+	for ops.Next() {
+		op := ops.Get()
+		for _, step := range op.Steps() {
+			if !targetsMatch(targets, step.Targets()) {
+				continue
+			}
+			steps = append(steps, PreviewStep{
+				Version:     op.TargetVersion(),
+				Description: step.Description(),
+				Targets:     step.Targets(),
+				State:       isState,
+			})
+		}
+	}
+	return steps
+}
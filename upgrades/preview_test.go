@@ -0,0 +1,86 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type previewSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&previewSuite{})
+
+func (s *previewSuite) TestPreviewUpgradeMatchesPerformUpgrade(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	for i, test := range upgradeTests {
+		if test.err != "" {
+			// A preview runs nothing, so no step fails.
+			continue
+		}
+		c.Logf("%d: %s", i, test.about)
+		fromVersion := version.Zero
+		if test.fromVersion != "" {
+			fromVersion = version.MustParse(test.fromVersion)
+		}
+		toVersion := version.MustParse("1.18.0")
+		if test.toVersion != "" {
+			toVersion = version.MustParse(test.toVersion)
+		}
+		descriptions := []string{}
+		for _, step := range upgrades.PreviewUpgrade(fromVersion, toVersion, test.targets) {
+			descriptions = append(descriptions, step.Description)
+		}
+		c.Check(descriptions, jc.DeepEquals, test.expectedSteps)
+	}
+}
+
+func (s *previewSuite) TestPreviewUpgrade(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	steps := upgrades.PreviewUpgrade(
+		version.MustParse("1.20.0"),
+		version.MustParse("1.21.0"),
+		targets(upgrades.DatabaseMaster, upgrades.Controller),
+	)
+	c.Assert(steps, jc.DeepEquals, []upgrades.PreviewStep{{
+		Version:     version.MustParse("1.21.0"),
+		Description: "state step 1 - 1.21.0",
+		Targets:     targets(upgrades.DatabaseMaster),
+		State:       true,
+	}, {
+		Version:     version.MustParse("1.21.0"),
+		Description: "state step 2 - 1.21.0",
+		Targets:     targets(upgrades.Controller),
+		State:       true,
+	}, {
+		Version:     version.MustParse("1.21.0"),
+		Description: "step 1 - 1.21.0",
+		Targets:     targets(upgrades.AllMachines),
+	}})
+}
+
+func (s *previewSuite) TestPreviewUpgradeRealSteps(c *gc.C) {
+	// Every step that an upgrade from 2.7.0 would run on a controller
+	// is previewed, with the state steps first.
+	steps := upgrades.PreviewUpgrade(
+		version.MustParse("2.7.0"),
+		version.MustParse("2.8.0"),
+		targets(upgrades.DatabaseMaster, upgrades.Controller, upgrades.HostMachine),
+	)
+	c.Assert(steps, gc.Not(gc.HasLen), 0)
+	c.Assert(steps[0].State, jc.IsTrue)
+	c.Assert(steps[len(steps)-1].State, jc.IsFalse)
+	for _, step := range steps {
+		c.Check(step.Version.Compare(version.MustParse("2.7.0")), gc.Equals, 1)
+		c.Check(step.Version.Compare(version.MustParse("2.8.0")), gc.Not(gc.Equals), 1)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bulkmigration
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/worker/v2"
+
+	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/state"
+	jworker "github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.bulkmigration")
+
+// Backend provides the state used by the bulk migration worker.
+type Backend interface {
+	// AllBulkMigrations returns all of the bulk migrations.
+	AllBulkMigrations() ([]BulkMigration, error)
+
+	// MigrationPhase returns the current phase of the model
+	// migration with the given id.
+	MigrationPhase(migrationId string) (migration.Phase, error)
+
+	// StartMigration starts the migration of the model with the given
+	// UUID, returning the id of the migration.
+	StartMigration(modelUUID string, spec state.MigrationSpec) (string, error)
+}
+
+// BulkMigration describes the parts of a state.BulkMigration used by
+// the bulk migration worker.
+type BulkMigration interface {
+	Id() string
+	InitiatedBy() string
+	MaxConcurrent() int
+	TargetInfo() (*migration.TargetInfo, error)
+	Models() []state.BulkMigrationModel
+	SetModelMigration(modelUUID, migrationId string) error
+	SetModelStartError(modelUUID string, startErr error) error
+}
+
+// New returns a worker which periodically starts the queued migrations
+// of bulk migrations, so that no more than the maximum number of
+// migrations allowed for each bulk migration are running at once.
+func New(backend Backend, interval time.Duration, clock clock.Clock) worker.Worker {
+	return jworker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
+		for {
+			select {
+			case <-clock.After(interval):
+				if err := startQueuedMigrations(backend); err != nil {
+					return errors.Annotate(err, "starting bulk migrations")
+				}
+			case <-stopCh:
+				return nil
+			}
+		}
+	})
+}
+
+func startQueuedMigrations(backend Backend) error {
+	bulks, err := backend.AllBulkMigrations()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, bulk := range bulks {
+		if err := startBulkMigrations(backend, bulk); err != nil {
+			return errors.Annotatef(err, "bulk migration %s", bulk.Id())
+		}
+	}
+	return nil
+}
+
+func startBulkMigrations(backend Backend, bulk BulkMigration) error {
+	var queued []string
+	var started []string
+	for _, m := range bulk.Models() {
+		if m.Queued() {
+			queued = append(queued, m.ModelUUID)
+		} else if m.MigrationId != "" {
+			started = append(started, m.MigrationId)
+		}
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	active := 0
+	for _, migrationId := range started {
+		phase, err := backend.MigrationPhase(migrationId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !phase.IsTerminal() {
+			active++
+		}
+	}
+	if active >= bulk.MaxConcurrent() {
+		return nil
+	}
+
+	targetInfo, err := bulk.TargetInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spec := state.MigrationSpec{
+		InitiatedBy: names.NewUserTag(bulk.InitiatedBy()),
+		TargetInfo:  *targetInfo,
+	}
+	for _, modelUUID := range queued {
+		if active >= bulk.MaxConcurrent() {
+			break
+		}
+		migrationId, err := backend.StartMigration(modelUUID, spec)
+		if err != nil {
+			logger.Warningf("cannot start migration of model %s: %v", modelUUID, err)
+			if err := bulk.SetModelStartError(modelUUID, err); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		logger.Infof("started migration %s of model %s", migrationId, modelUUID)
+		if err := bulk.SetModelMigration(modelUUID, migrationId); err != nil {
+			return errors.Trace(err)
+		}
+		active++
+	}
+	return nil
+}
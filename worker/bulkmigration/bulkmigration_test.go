@@ -0,0 +1,191 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bulkmigration_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/bulkmigration"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	backend *fakeBackend
+	bulk    *fakeBulkMigration
+	clock   *testclock.Clock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+var targetInfo = migration.TargetInfo{
+	ControllerTag: names.NewControllerTag("c0ffee00-0000-0000-0000-000000000000"),
+	Addrs:         []string{"1.2.3.4:17070"},
+	CACert:        "cert",
+	AuthTag:       names.NewUserTag("admin"),
+	Password:      "secret",
+}
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.bulk = &fakeBulkMigration{
+		maxConcurrent: 2,
+		models: []state.BulkMigrationModel{
+			{ModelUUID: "model-a", MigrationId: "model-a:0"},
+			{ModelUUID: "model-b"},
+			{ModelUUID: "model-c"},
+			{ModelUUID: "model-d"},
+		},
+	}
+	s.backend = &fakeBackend{
+		bulks:  []bulkmigration.BulkMigration{s.bulk},
+		phases: map[string]migration.Phase{"model-a:0": migration.IMPORT},
+	}
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *WorkerSuite) runOnce(c *gc.C) {
+	w := bulkmigration.New(s.backend, time.Minute, s.clock)
+	defer workertest.CleanKill(c, w)
+
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	// Wait for the worker to loop around, at which point it has
+	// finished starting migrations.
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker")
+	}
+}
+
+func (s *WorkerSuite) TestStartsUpToMaxConcurrent(c *gc.C) {
+	s.runOnce(c)
+
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"AllBulkMigrations", nil},
+		{"MigrationPhase", []interface{}{"model-a:0"}},
+		{"StartMigration", []interface{}{"model-b", state.MigrationSpec{
+			InitiatedBy: names.NewUserTag("admin"),
+			TargetInfo:  targetInfo,
+		}}},
+	})
+	c.Check(s.bulk.models[1].MigrationId, gc.Equals, "model-b:0")
+	c.Check(s.bulk.models[2].Queued(), jc.IsTrue)
+	c.Check(s.bulk.models[3].Queued(), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestFinishedMigrationsFreeSlots(c *gc.C) {
+	s.backend.phases["model-a:0"] = migration.DONE
+	s.runOnce(c)
+
+	s.backend.CheckCallNames(c, "AllBulkMigrations", "MigrationPhase", "StartMigration", "StartMigration")
+	c.Check(s.bulk.models[1].MigrationId, gc.Equals, "model-b:0")
+	c.Check(s.bulk.models[2].MigrationId, gc.Equals, "model-c:0")
+	c.Check(s.bulk.models[3].Queued(), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestStartErrorRecorded(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.New("model is not alive"))
+	s.runOnce(c)
+
+	s.backend.CheckCallNames(c, "AllBulkMigrations", "MigrationPhase", "StartMigration", "StartMigration")
+	c.Check(s.bulk.models[1].StartError, gc.Equals, "model is not alive")
+	c.Check(s.bulk.models[2].MigrationId, gc.Equals, "model-c:0")
+	c.Check(s.bulk.models[3].Queued(), jc.IsTrue)
+}
+
+func (s *WorkerSuite) TestNothingQueued(c *gc.C) {
+	s.bulk.models = s.bulk.models[:1]
+	s.runOnce(c)
+
+	s.backend.CheckCallNames(c, "AllBulkMigrations")
+}
+
+func (s *WorkerSuite) TestBackendErrorStopsWorker(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	w := bulkmigration.New(s.backend, time.Minute, s.clock)
+	defer workertest.DirtyKill(c, w)
+
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	err := workertest.CheckKilled(c, w)
+	c.Check(err, gc.ErrorMatches, "starting bulk migrations: boom")
+}
+
+type fakeBackend struct {
+	testing.Stub
+	bulks  []bulkmigration.BulkMigration
+	phases map[string]migration.Phase
+}
+
+func (b *fakeBackend) AllBulkMigrations() ([]bulkmigration.BulkMigration, error) {
+	b.MethodCall(b, "AllBulkMigrations")
+	return b.bulks, b.NextErr()
+}
+
+func (b *fakeBackend) MigrationPhase(migrationId string) (migration.Phase, error) {
+	b.MethodCall(b, "MigrationPhase", migrationId)
+	return b.phases[migrationId], b.NextErr()
+}
+
+func (b *fakeBackend) StartMigration(modelUUID string, spec state.MigrationSpec) (string, error) {
+	b.MethodCall(b, "StartMigration", modelUUID, spec)
+	if err := b.NextErr(); err != nil {
+		return "", err
+	}
+	return modelUUID + ":0", nil
+}
+
+type fakeBulkMigration struct {
+	maxConcurrent int
+	models        []state.BulkMigrationModel
+}
+
+func (b *fakeBulkMigration) Id() string {
+	return "0"
+}
+
+func (b *fakeBulkMigration) InitiatedBy() string {
+	return "admin"
+}
+
+func (b *fakeBulkMigration) MaxConcurrent() int {
+	return b.maxConcurrent
+}
+
+func (b *fakeBulkMigration) TargetInfo() (*migration.TargetInfo, error) {
+	info := targetInfo
+	return &info, nil
+}
+
+func (b *fakeBulkMigration) Models() []state.BulkMigrationModel {
+	return b.models
+}
+
+func (b *fakeBulkMigration) SetModelMigration(modelUUID, migrationId string) error {
+	for i, m := range b.models {
+		if m.ModelUUID == modelUUID {
+			b.models[i].MigrationId = migrationId
+		}
+	}
+	return nil
+}
+
+func (b *fakeBulkMigration) SetModelStartError(modelUUID string, startErr error) error {
+	for i, m := range b.models {
+		if m.ModelUUID == modelUUID {
+			b.models[i].StartError = startErr.Error()
+		}
+	}
+	return nil
+}
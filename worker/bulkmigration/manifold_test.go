@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bulkmigration_test
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/bulkmigration"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	config bulkmigration.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.config = bulkmigration.ManifoldConfig{
+		ClockName: "clock",
+		StateName: "state",
+		Interval:  time.Minute,
+		NewBackend: func(*state.StatePool) bulkmigration.Backend {
+			return nil
+		},
+		NewWorker: func(bulkmigration.Backend, time.Duration, clock.Clock) worker.Worker {
+			return nil
+		},
+	}
+}
+
+func (s *ManifoldSuite) TestValid(c *gc.C) {
+	c.Check(s.config.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestMissingClockName(c *gc.C) {
+	s.config.ClockName = ""
+	c.Check(s.config.Validate(), gc.ErrorMatches, "empty ClockName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingStateName(c *gc.C) {
+	s.config.StateName = ""
+	c.Check(s.config.Validate(), gc.ErrorMatches, "empty StateName not valid")
+}
+
+func (s *ManifoldSuite) TestInvalidInterval(c *gc.C) {
+	s.config.Interval = 0
+	c.Check(s.config.Validate(), gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	s.config.NewWorker = nil
+	c.Check(s.config.Validate(), gc.ErrorMatches, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := bulkmigration.Manifold(s.config)
+	c.Check(manifold.Inputs, jc.SameContents, []string{"clock", "state"})
+}
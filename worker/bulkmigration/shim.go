@@ -0,0 +1,58 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bulkmigration
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/migration"
+	"github.com/juju/juju/state"
+)
+
+// NewBackend returns a Backend backed by the given state pool.
+func NewBackend(pool *state.StatePool) Backend {
+	return &backendShim{pool: pool}
+}
+
+type backendShim struct {
+	pool *state.StatePool
+}
+
+// AllBulkMigrations is part of the Backend interface.
+func (b *backendShim) AllBulkMigrations() ([]BulkMigration, error) {
+	bulks, err := b.pool.SystemState().AllBulkMigrations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]BulkMigration, len(bulks))
+	for i, bulk := range bulks {
+		result[i] = bulk
+	}
+	return result, nil
+}
+
+// MigrationPhase is part of the Backend interface.
+func (b *backendShim) MigrationPhase(migrationId string) (migration.Phase, error) {
+	mig, err := b.pool.SystemState().Migration(migrationId)
+	if err != nil {
+		return migration.UNKNOWN, errors.Trace(err)
+	}
+	return mig.Phase()
+}
+
+// StartMigration is part of the Backend interface. The migration
+// prechecks are run when the bulk migration is initiated, and models
+// failing them are recorded as not started, so they don't get here.
+func (b *backendShim) StartMigration(modelUUID string, spec state.MigrationSpec) (string, error) {
+	st, err := b.pool.Get(modelUUID)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer st.Release()
+	mig, err := st.CreateMigration(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return mig.Id(), nil
+}
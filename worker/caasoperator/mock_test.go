@@ -228,6 +228,11 @@ func (m *mockExecutor) Exec(params exec.ExecParams, cancel <-chan struct{}) erro
 	return m.NextErr()
 }
 
+func (m *mockExecutor) Logs(params exec.LogsParams, cancel <-chan struct{}) error {
+	m.MethodCall(m, "Logs", params, cancel)
+	return m.NextErr()
+}
+
 func (m *mockExecutor) Status(params exec.StatusParams) (*exec.Status, error) {
 	m.MethodCall(m, "Status", params)
 	return &m.status, m.NextErr()
@@ -58,6 +58,18 @@ func (mr *MockExecutorMockRecorder) Exec(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockExecutor)(nil).Exec), arg0, arg1)
 }
 
+// Logs mocks base method
+func (m *MockExecutor) Logs(arg0 exec.LogsParams, arg1 <-chan struct{}) error {
+	ret := m.ctrl.Call(m, "Logs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logs indicates an expected call of Logs
+func (mr *MockExecutorMockRecorder) Logs(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockExecutor)(nil).Logs), arg0, arg1)
+}
+
 // Status mocks base method
 func (m *MockExecutor) Status(arg0 exec.StatusParams) (*exec.Status, error) {
 	ret := m.ctrl.Call(m, "Status", arg0)
@@ -304,13 +304,14 @@ func (aw *applicationWorker) clusterChanged(
 			}
 		}
 		unitParams := params.ApplicationUnitParams{
-			ProviderId: u.Id,
-			Address:    u.Address,
-			Ports:      u.Ports,
-			Stateful:   u.Stateful,
-			Status:     unitStatus.Status.String(),
-			Info:       unitStatus.Message,
-			Data:       unitStatus.Data,
+			ProviderId:     u.Id,
+			Address:        u.Address,
+			SpaceAddresses: u.SpaceAddresses,
+			Ports:          u.Ports,
+			Stateful:       u.Stateful,
+			Status:         unitStatus.Status.String(),
+			Info:           unitStatus.Message,
+			Data:           unitStatus.Data,
 		}
 		// Fill in any filesystem info for volumes attached to the unit.
 		// A unit will not become active until all required volumes are
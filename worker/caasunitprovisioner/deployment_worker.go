@@ -227,6 +227,7 @@ func provisionInfoToServiceParams(info *apicaasunitprovisioner.ProvisioningInfo)
 		Devices:              info.Devices,
 		OperatorImagePath:    info.OperatorImagePath,
 		CharmModifiedVersion: info.CharmModifiedVersion,
+		EndpointBindings:     info.EndpointBindings,
 		Deployment: caas.DeploymentParams{
 			DeploymentType: caas.DeploymentType(info.DeploymentInfo.DeploymentType),
 			ServiceType:    caas.ServiceType(info.DeploymentInfo.ServiceType),
@@ -254,7 +255,8 @@ func isProvisionInfoEqual(newInfo, oldInfo *apicaasunitprovisioner.ProvisioningI
 
 	return newInfo.PodSpec == oldInfo.PodSpec &&
 		newInfo.RawK8sSpec == oldInfo.RawK8sSpec &&
-		newInfo.CharmModifiedVersion == oldInfo.CharmModifiedVersion
+		newInfo.CharmModifiedVersion == oldInfo.CharmModifiedVersion &&
+		reflect.DeepEqual(newInfo.EndpointBindings, oldInfo.EndpointBindings)
 }
 
 func updateApplicationService(appTag names.ApplicationTag, svc *caas.Service, updater ApplicationUpdater) error {
@@ -110,6 +110,7 @@ func getExpectedServiceParams() *caas.ServiceParams {
 			StorageName: "database",
 			Size:        100,
 		}},
+		EndpointBindings: map[string]string{"db": "telco"},
 	}
 }
 
@@ -152,6 +153,7 @@ func (s *WorkerSuite) SetUpTest(c *gc.C) {
 			StorageName: "database",
 			Size:        100,
 		}},
+		EndpointBindings: map[string]string{"db": "telco"},
 	})
 
 	s.unitUpdater = mockUnitUpdater{}
@@ -161,9 +163,10 @@ func (s *WorkerSuite) SetUpTest(c *gc.C) {
 		operatorWatcher: watchertest.NewMockNotifyWatcher(s.caasOperatorChanges),
 		units: []caas.Unit{
 			{
-				Id:       "u1",
-				Address:  "10.0.0.1",
-				Stateful: true,
+				Id:             "u1",
+				Address:        "10.0.0.1",
+				SpaceAddresses: map[string]string{"telco": "10.10.0.5"},
+				Stateful:       true,
 				FilesystemInfo: []caas.FilesystemInfo{
 					{MountPoint: "/path-to-here", ReadOnly: true, StorageName: "database",
 						Size: 100, FilesystemId: "fs-id",
@@ -434,7 +437,8 @@ func (s *WorkerSuite) TestScaleChangedInCluster(c *gc.C) {
 			},
 			Units: []params.ApplicationUnitParams{
 				{ProviderId: "u1", Address: "10.0.0.1", Ports: []string(nil),
-					Stateful: true,
+					SpaceAddresses: map[string]string{"telco": "10.10.0.5"},
+					Stateful:       true,
 					FilesystemInfo: []params.KubernetesFilesystemInfo{
 						{StorageName: "database", MountPoint: "/path-to-here", ReadOnly: true,
 							FilesystemId: "fs-id", Size: 100, Pool: "",
@@ -1015,7 +1019,8 @@ func (s *WorkerSuite) assertUnitChange(c *gc.C, reported, expectedUnitStatus sta
 			ApplicationTag: names.NewApplicationTag("gitlab").String(),
 			Scale:          &scale,
 			Units: []params.ApplicationUnitParams{
-				{ProviderId: "u1", Address: "10.0.0.1", Ports: []string(nil), Status: expectedUnitStatus.String(),
+				{ProviderId: "u1", Address: "10.0.0.1", Ports: []string(nil),
+					SpaceAddresses: map[string]string{"telco": "10.10.0.5"}, Status: expectedUnitStatus.String(),
 					Stateful: true,
 					FilesystemInfo: []params.KubernetesFilesystemInfo{
 						{StorageName: "database", MountPoint: "/path-to-here", ReadOnly: true,
@@ -0,0 +1,54 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type ExposedCIDRsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ExposedCIDRsSuite{})
+
+func (s *ExposedCIDRsSuite) TestNoExposeSettings(c *gc.C) {
+	c.Assert(exposedCIDRs(nil, "url").SortedValues(), gc.DeepEquals, []string{"0.0.0.0/0"})
+	c.Assert(exposedCIDRs(nil, "").SortedValues(), gc.DeepEquals, []string{"0.0.0.0/0"})
+}
+
+func (s *ExposedCIDRsSuite) TestPerEndpoint(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"":    {ExposeToCIDRs: []string{"172.16.0.0/12"}},
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"db":  {ExposeToSpaceIDs: []string{"1"}, ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	}
+	c.Assert(exposedCIDRs(exposedEndpoints, "url").SortedValues(), gc.DeepEquals, []string{"10.0.0.0/8"})
+	c.Assert(exposedCIDRs(exposedEndpoints, "db").SortedValues(), gc.DeepEquals, []string{"192.168.0.0/24"})
+
+	// Endpoints without settings of their own use those for all endpoints.
+	c.Assert(exposedCIDRs(exposedEndpoints, "admin").SortedValues(), gc.DeepEquals, []string{"172.16.0.0/12"})
+
+	// Ports opened for all endpoints are open to the sources of each.
+	c.Assert(exposedCIDRs(exposedEndpoints, "").SortedValues(), gc.DeepEquals, []string{
+		"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/24",
+	})
+}
+
+func (s *ExposedCIDRsSuite) TestEndpointNotExposed(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	}
+	c.Assert(exposedCIDRs(exposedEndpoints, "db").Size(), gc.Equals, 0)
+}
+
+func (s *ExposedCIDRsSuite) TestExposedToEverywhere(c *gc.C) {
+	exposedEndpoints := map[string]params.ExposedEndpoint{
+		"url": {},
+	}
+	c.Assert(exposedCIDRs(exposedEndpoints, "url").SortedValues(), gc.DeepEquals, []string{"0.0.0.0/0"})
+}
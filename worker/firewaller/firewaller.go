@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"io"
+	"reflect"
 	"strings"
 	"time"
 
@@ -49,6 +50,7 @@ type FirewallerAPI interface {
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
 	SetRelationStatus(relationKey string, status relation.Status, message string) error
 	FirewallRules(applicationNames ...string) ([]params.FirewallRule, error)
+	WatchSpaceSubnets() (watcher.NotifyWatcher, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -125,7 +127,9 @@ func (cfg Config) Validate() error {
 	return nil
 }
 
-type portRanges map[corenetwork.PortRange]bool
+// portRanges maps the port ranges opened by a unit to the name of the
+// endpoint each is opened for, which is empty for all endpoints.
+type portRanges map[corenetwork.PortRange]string
 
 // Firewaller watches the state for port ranges opened or closed on
 // machines and reflects those changes onto the backing environment.
@@ -139,6 +143,7 @@ type Firewaller struct {
 
 	machinesWatcher      watcher.StringsWatcher
 	portsWatcher         watcher.StringsWatcher
+	spaceSubnetsWatcher  watcher.NotifyWatcher
 	machineds            map[names.MachineTag]*machineData
 	unitsChange          chan *unitsChange
 	unitds               map[names.UnitTag]*unitData
@@ -236,6 +241,17 @@ func (fw *Firewaller) setUp() error {
 		return errors.Trace(err)
 	}
 
+	// Controllers which predate space-aware expose do not offer this
+	// watcher; their applications cannot be exposed to spaces anyway.
+	fw.spaceSubnetsWatcher, err = fw.firewallerApi.WatchSpaceSubnets()
+	if err != nil && !errors.IsNotSupported(err) {
+		return errors.Annotatef(err, "failed to start space subnets watcher")
+	} else if err == nil {
+		if err := fw.catacomb.Add(fw.spaceSubnetsWatcher); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	fw.remoteRelationsWatcher, err = fw.remoteRelationsApi.WatchRemoteRelations()
 	if err != nil {
 		return errors.Trace(err)
@@ -254,6 +270,10 @@ func (fw *Firewaller) loop() error {
 	}
 	var reconciled bool
 	portsChange := fw.portsWatcher.Changes()
+	var spaceSubnetsChange watcher.NotifyChannel
+	if fw.spaceSubnetsWatcher != nil {
+		spaceSubnetsChange = fw.spaceSubnetsWatcher.Changes()
+	}
 	for {
 		select {
 		case <-fw.catacomb.Dying():
@@ -292,6 +312,13 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-spaceSubnetsChange:
+			if !ok {
+				return errors.New("space subnets watcher closed")
+			}
+			if err := fw.spaceSubnetsChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -314,6 +341,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.applicationd.exposed = change.exposed
+			change.applicationd.exposedEndpoints = change.exposedEndpoints
 			unitds := []*unitData{}
 			for _, unitd := range change.applicationd.unitds {
 				unitds = append(unitds, unitd)
@@ -325,6 +353,44 @@ func (fw *Firewaller) loop() error {
 	}
 }
 
+// spaceSubnetsChanged refreshes the expose settings of applications
+// exposed to spaces, as the CIDRs of those spaces are resolved when the
+// settings are read, and flushes their units.
+func (fw *Firewaller) spaceSubnetsChanged() error {
+	var unitds []*unitData
+	for _, applicationd := range fw.applicationids {
+		if !applicationd.exposed || !exposedToSpaces(applicationd.exposedEndpoints) {
+			continue
+		}
+		exposed, exposedEndpoints, err := applicationd.application.ExposeInfo()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		applicationd.exposed = exposed
+		applicationd.exposedEndpoints = exposedEndpoints
+		for _, unitd := range applicationd.unitds {
+			unitds = append(unitds, unitd)
+		}
+	}
+	if err := fw.flushUnits(unitds); err != nil {
+		return errors.Annotate(err, "cannot change firewall ports")
+	}
+	return nil
+}
+
+// exposedToSpaces returns true if any of the exposed endpoints allows
+// access from a space.
+func exposedToSpaces(exposedEndpoints map[string]params.ExposedEndpoint) bool {
+	for _, exposed := range exposedEndpoints {
+		if len(exposed.ExposeToSpaceIDs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (fw *Firewaller) relationIngressChanged(change *remoteRelationNetworkChange) error {
 	fw.logger.Debugf("process remote relation ingress change for %v", change.relationTag)
 	relData, ok := fw.relationIngress[change.relationTag]
@@ -476,22 +542,23 @@ func (fw *Firewaller) startUnit(unit *firewaller.Unit, machineTag names.MachineT
 // startApplication creates a new data value for tracking details of the
 // application and starts watching the application for exposure changes.
 func (fw *Firewaller) startApplication(app *firewaller.Application) error {
-	exposed, err := app.IsExposed()
+	exposed, exposedEndpoints, err := app.ExposeInfo()
 	if err != nil {
 		return err
 	}
 	applicationd := &applicationData{
-		fw:          fw,
-		application: app,
-		exposed:     exposed,
-		unitds:      make(map[names.UnitTag]*unitData),
+		fw:               fw,
+		application:      app,
+		exposed:          exposed,
+		exposedEndpoints: exposedEndpoints,
+		unitds:           make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
 
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed)
+			return applicationd.watchLoop(exposed, exposedEndpoints)
 		},
 	})
 	if err != nil {
@@ -672,13 +739,13 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 	}
 
 	newPortRanges := make(map[names.UnitTag]portRanges)
-	for portRange, unitTag := range ports {
-		unitd, ok := machined.unitds[unitTag]
+	for portRange, opener := range ports {
+		unitd, ok := machined.unitds[opener.UnitTag]
 		if !ok {
 			// It is common to receive port change notification before
 			// registering a unit. Skip handling the port change - it will
 			// be handled when the unit is registered.
-			fw.logger.Debugf("failed to lookup %q, skipping port change", unitTag)
+			fw.logger.Debugf("failed to lookup %q, skipping port change", opener.UnitTag)
 			return nil
 		}
 		ranges, ok := newPortRanges[unitd.tag]
@@ -686,7 +753,7 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 			ranges = make(portRanges)
 			newPortRanges[unitd.tag] = ranges
 		}
-		ranges[portRange] = true
+		ranges[portRange] = opener.Endpoint
 	}
 
 	if !unitPortsEqual(machined.definedPorts, newPortRanges) {
@@ -768,32 +835,79 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 				continue
 			}
 
-			cidrs := set.NewStrings()
-			// If the unit is exposed, allow access from everywhere.
-			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
-			} else {
+			exposed := unitd.applicationd.exposed
+			relationCidrs := set.NewStrings()
+			if !exposed {
 				// Not exposed, so add any ingress rules required by remote relations.
-				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
+				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), relationCidrs); err != nil {
 					return nil, errors.Trace(err)
 				}
-				fw.logger.Debugf("CIDRS for %v: %v", unitTag, cidrs.Values())
+				fw.logger.Debugf("CIDRS for %v: %v", unitTag, relationCidrs.Values())
 			}
-			if cidrs.Size() > 0 {
-				for portRange := range portRanges {
-					sourceCidrs := cidrs.SortedValues()
-					rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
-					if err != nil {
-						return nil, errors.Trace(err)
-					}
-					want = append(want, rule)
+			for portRange, endpoint := range portRanges {
+				cidrs := relationCidrs
+				// If the unit is exposed, allow access from the sources
+				// the endpoint that opened the port is exposed to.
+				if exposed {
+					cidrs = exposedCIDRs(unitd.applicationd.exposedEndpoints, endpoint)
 				}
+				if cidrs.Size() == 0 {
+					continue
+				}
+				rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, cidrs.SortedValues()...)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				want = append(want, rule)
 			}
 		}
 	}
 	return want, nil
 }
 
+// exposedCIDRs returns the source CIDRs allowed to access a port opened
+// for the specified endpoint of an exposed application with the
+// specified expose settings. Access from everywhere is allowed when
+// there are no expose settings. The settings of the endpoint are used,
+// or those for all endpoints if it has none. A port opened for all
+// endpoints may be accessed from the sources of any of them.
+func exposedCIDRs(exposedEndpoints map[string]params.ExposedEndpoint, endpoint string) set.Strings {
+	cidrs := set.NewStrings()
+	if len(exposedEndpoints) == 0 {
+		cidrs.Add("0.0.0.0/0")
+		return cidrs
+	}
+	if endpoint == "" {
+		for _, exposed := range exposedEndpoints {
+			addExposedCIDRs(cidrs, exposed)
+		}
+		return cidrs
+	}
+	exposed, ok := exposedEndpoints[endpoint]
+	if !ok {
+		exposed, ok = exposedEndpoints[""]
+	}
+	if ok {
+		addExposedCIDRs(cidrs, exposed)
+	}
+	return cidrs
+}
+
+// addExposedCIDRs adds the source CIDRs allowed by the expose settings
+// of an endpoint to cidrs. Access from everywhere is allowed when the
+// endpoint is exposed without specifying any spaces or CIDRs.
+func addExposedCIDRs(cidrs set.Strings, exposed params.ExposedEndpoint) {
+	if len(exposed.ExposeToSpaceIDs) == 0 && len(exposed.ExposeToCIDRs) == 0 {
+		cidrs.Add("0.0.0.0/0")
+		return
+	}
+	// The CIDRs of the subnets in each space are included in
+	// ExposeToCIDRs by the API server.
+	for _, cidr := range exposed.ExposeToCIDRs {
+		cidrs.Add(cidr)
+	}
+}
+
 // TODO(wallyworld) - consider making this configurable.
 const maxAllowedCIDRS = 20
 
@@ -1094,23 +1208,27 @@ type unitData struct {
 	machined     *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific application.
+// exposedChange contains the changed exposed flag and expose settings
+// for one specific application.
 type exposedChange struct {
-	applicationd *applicationData
-	exposed      bool
+	applicationd     *applicationData
+	exposed          bool
+	exposedEndpoints map[string]params.ExposedEndpoint
 }
 
 // applicationData holds application details and watches exposure changes.
 type applicationData struct {
-	catacomb    catacomb.Catacomb
-	fw          *Firewaller
-	application *firewaller.Application
-	exposed     bool
-	unitds      map[names.UnitTag]*unitData
+	catacomb         catacomb.Catacomb
+	fw               *Firewaller
+	application      *firewaller.Application
+	exposed          bool
+	exposedEndpoints map[string]params.ExposedEndpoint
+	unitds           map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(exposed bool) error {
+// watchLoop watches the application's exposed flag and expose
+// settings for changes.
+func (ad *applicationData) watchLoop(exposed bool, exposedEndpoints map[string]params.ExposedEndpoint) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1129,25 +1247,27 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 			if !ok {
 				return errors.New("application watcher closed")
 			}
-			change, err := ad.application.IsExposed()
+			change, changedEndpoints, err := ad.application.ExposeInfo()
 			if err != nil {
 				if errors.IsNotFound(err) {
-					ad.fw.logger.Debugf("application(%q).IsExposed() returned NotFound: %v", ad.application.Name(), err)
+					ad.fw.logger.Debugf("application(%q).ExposeInfo() returned NotFound: %v", ad.application.Name(), err)
 					return nil
 				}
 				return errors.Trace(err)
 			}
-			if change == exposed {
-				ad.fw.logger.Tracef("application(%q).IsExposed() == %v (unchanged)", ad.application.Name(), exposed)
+			if change == exposed && reflect.DeepEqual(changedEndpoints, exposedEndpoints) {
+				ad.fw.logger.Tracef("application(%q).ExposeInfo() == %v, %v (unchanged)", ad.application.Name(), exposed, exposedEndpoints)
 				continue
 			}
-			ad.fw.logger.Tracef("application(%q).IsExposed() changed %v => %v", ad.application.Name(), exposed, change)
+			ad.fw.logger.Tracef("application(%q).ExposeInfo() changed %v, %v => %v, %v",
+				ad.application.Name(), exposed, exposedEndpoints, change, changedEndpoints)
 
 			exposed = change
+			exposedEndpoints = changedEndpoints
 			select {
 			case <-ad.catacomb.Dying():
 				return ad.catacomb.ErrDying()
-			case ad.fw.exposedChange <- &exposedChange{ad, change}:
+			case ad.fw.exposedChange <- &exposedChange{ad, change, changedEndpoints}:
 			}
 		}
 	}
@@ -285,6 +285,105 @@ func (s *InstanceModeSuite) TestExposedApplication(c *gc.C) {
 	})
 }
 
+func (s *InstanceModeSuite) TestExposedApplicationWithExposeSettings(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+
+	err := app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	s.AssertOpenUnitPort(c, u, "", "tcp", 80)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8", "192.168.0.0/24"),
+	})
+
+	// Changing the expose settings updates the ingress rules.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+
+	// Unexposing the application closes the ports.
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestExposedApplicationScopesPortsToEndpoints(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err := app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"db":  {ExposeToCIDRs: []string{"192.168.0.0/24"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	// Each port is opened to the sources of the endpoint it was opened
+	// for, and a port opened for all endpoints to all of them.
+	op, err := u.OpenClosePortsOnEndpointsOperation(map[string][]corenetwork.PortRange{
+		"":    {corenetwork.MustParsePortRange("22/tcp")},
+		"url": {corenetwork.MustParsePortRange("80/tcp")},
+		"db":  {corenetwork.MustParsePortRange("3306/tcp")},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ApplyOperation(op)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.0.0/24"),
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 3306, 3306, "192.168.0.0/24"),
+	})
+}
+
+func (s *InstanceModeSuite) TestExposedApplicationFollowsSpaceSubnets(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	subnet, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace("dmz", "", []string{subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToSpaceIDs: []string{space.Id()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	s.AssertOpenUnitPort(c, u, "", "tcp", 80)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/24"),
+	})
+
+	// Adding a subnet to the space widens the ingress rules.
+	_, err = s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "10.0.1.0/24", SpaceID: space.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/24", "10.0.1.0/24"),
+	})
+}
+
 func (s *InstanceModeSuite) TestMultipleExposedApplications(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
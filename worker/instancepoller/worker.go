@@ -44,6 +44,17 @@ var (
 	LongPoll         = 15 * time.Minute
 )
 
+// LostMissingPolls and LostGracePeriod control when a machine whose
+// instance was running is considered lost. The instance must be missing
+// from the provider's results in at least LostMissingPolls consecutive
+// polls spanning at least LostGracePeriod; providers' listings are
+// eventually consistent, so a single miss is not proof that the instance
+// is gone.
+var (
+	LostMissingPolls = 3
+	LostGracePeriod  = 5 * time.Minute
+)
+
 // Environ specifies the provider-specific methods needed by the instance
 // poller.
 type Environ interface {
@@ -119,6 +130,11 @@ type pollGroupEntry struct {
 
 	shortPollInterval time.Duration
 	shortPollAt       time.Time
+
+	// missingPolls counts the consecutive polls in which the provider
+	// did not know of the instance; missingSince records the first.
+	missingPolls int
+	missingSince time.Time
 }
 
 func (e *pollGroupEntry) resetShortPollInterval(clk clock.Clock) {
@@ -359,6 +375,10 @@ func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
 	if err != nil && !isPartialOrNoInstancesError(err) {
 		return errors.Trace(err)
 	}
+	noInstances := errors.Cause(err) == environs.ErrNoInstances
+	if noInstances {
+		infoList = make([]instances.Instance, len(instList))
+	}
 
 	netList, err := u.config.Environ.NetworkInterfaces(u.callContext, instList)
 	if err != nil && !(errors.IsNotSupported(errors.Cause(err)) || isPartialOrNoInstancesError(err)) {
@@ -366,20 +386,32 @@ func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
 	}
 
 	for idx, info := range infoList {
+		entry := u.instanceIDToGroupEntry[instList[idx]]
+
 		// No details found for this instance. This most probably means
 		// that the unit has been killed and we haven't been notified
-		// yet. Log the error and keep going.
+		// yet, or that the instance was removed behind our back. Log
+		// the error and keep going.
 		if info == nil {
 			u.config.Logger.Warningf("unable to retrieve instance information for instance: %q", instList[idx])
+			if noInstances {
+				// The provider returned nothing for the whole batch,
+				// which is as likely to be a transient listing failure
+				// as every instance having gone; don't count it.
+				continue
+			}
+			if err := u.instanceMissing(groupType, entry); err != nil {
+				return errors.Trace(err)
+			}
 			continue
 		}
+		entry.missingPolls = 0
 
 		var ifList network.InterfaceInfos
 		if netList != nil {
 			ifList = netList[idx]
 		}
 
-		entry := u.instanceIDToGroupEntry[instList[idx]]
 		providerStatus, providerAddrCount, err := u.processProviderInfo(entry, info, ifList)
 		if err != nil {
 			return errors.Trace(err)
@@ -396,6 +428,49 @@ func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
 	return nil
 }
 
+// instanceMissing records that the provider did not know of an entry's
+// instance. Once the instance has been missing for long enough the
+// machine is marked as lost; until then it is polled more frequently so
+// that it is either found again or confirmed gone.
+func (u *updaterWorker) instanceMissing(groupType pollGroupType, entry *pollGroupEntry) error {
+	now := u.config.Clock.Now()
+	if entry.missingPolls == 0 {
+		entry.missingSince = now
+	}
+	entry.missingPolls++
+	if entry.missingPolls < LostMissingPolls || now.Sub(entry.missingSince) < LostGracePeriod {
+		if groupType == longPollGroup {
+			u.moveEntryToPollGroup(shortPollGroup, entry)
+		} else {
+			entry.bumpShortPollInterval(u.config.Clock)
+		}
+		return nil
+	}
+	return u.markInstanceLost(entry)
+}
+
+// markInstanceLost sets the instance status of a machine whose instance
+// was running, but is no longer known to the provider, to lost. The
+// controller replaces lost machines that belong to a machine pool.
+func (u *updaterWorker) markInstanceLost(entry *pollGroupEntry) error {
+	curStatus, err := entry.m.InstanceStatus()
+	if err != nil {
+		return errors.Annotatef(err, "retrieving current instance status for machine %q", entry.m.Id())
+	}
+	switch status.Status(curStatus.Status) {
+	case status.Running, status.Interrupted:
+	default:
+		// Instances that have not been seen running may not be
+		// visible to the provider yet.
+		return nil
+	}
+	u.config.Logger.Warningf("machine %q (instance ID %q) is lost: its instance has not been known to the provider since %v", entry.m.Id(), entry.instanceID, entry.missingSince)
+	if err := entry.m.SetInstanceStatus(status.Lost, "instance not found by the provider", nil); err != nil {
+		return errors.Annotatef(err, "setting instance status for machine %q", entry.m.Id())
+	}
+	return nil
+}
+
 func (u *updaterWorker) resolveInstanceID(entry *pollGroupEntry) error {
 	if entry.instanceID != "" {
 		return nil // already resolved
@@ -445,6 +520,16 @@ func (u *updaterWorker) processProviderInfo(entry *pollGroupEntry, info instance
 		if providerStatus.Status == status.Running {
 			entry.resetShortPollInterval(u.config.Clock)
 		}
+
+		// The cloud is reclaiming, or has reclaimed, a spot or
+		// preemptible instance; the controller asks the units to stop
+		// or marks the machine in error when the status is set.
+		switch providerStatus.Status {
+		case status.Interrupting:
+			u.config.Logger.Warningf("machine %q (instance ID %q) is being interrupted by the cloud: %s", entry.m.Id(), entry.instanceID, providerStatus.Message)
+		case status.Interrupted:
+			u.config.Logger.Warningf("machine %q (instance ID %q) was interrupted by the cloud: %s", entry.m.Id(), entry.instanceID, providerStatus.Message)
+		}
 	}
 
 	// We don't care about dead machines; they will be cleaned up when we
@@ -223,6 +223,39 @@ func (s *workerSuite) TestUpdateOfStatusAndAddressDetails(c *gc.C) {
 	c.Assert(addrCount, gc.Equals, len(testAddrs))
 }
 
+func (s *workerSuite) TestUpdateOfStatusForInterruptedInstance(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, _ := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	entry := &pollGroupEntry{
+		tag:        machineTag,
+		m:          machine,
+		instanceID: "b4dc0ffee",
+	}
+
+	// The machine is running until the provider reports that its spot
+	// instance has been interrupted.
+	machine.EXPECT().Id().Return("0").AnyTimes()
+	machine.EXPECT().Life().Return(life.Alive)
+	machine.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
+
+	instInfo := mocks.NewMockInstance(ctrl)
+	instInfo.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Interrupted, Message: "spot instance reclaimed"})
+
+	machine.EXPECT().SetInstanceStatus(status.Interrupted, "spot instance reclaimed", nil).Return(nil)
+	machine.EXPECT().SetProviderNetworkConfig(testNetIfs).Return(testAddrs, false, nil)
+
+	providerStatus, _, err := updWorker.processProviderInfo(entry, instInfo, testNetIfs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(providerStatus, gc.Equals, status.Interrupted)
+}
+
 func (s *workerSuite) TestStartedMachineWithNetAddressesMovesToLongPollGroup(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -558,6 +591,9 @@ func (s *workerSuite) TestLongPollNoMachineInGroupKnownByProvider(c *gc.C) {
 		nil, errors.NotSupportedf("network interfaces"),
 	)
 
+	// An empty result for the whole batch is not counted against the
+	// instance, so nothing is done with the machine.
+
 	// Advance the clock to trigger processing of both the short AND long
 	// poll groups. This should trigger to full loop runs.
 	s.assertWorkerCompletesLoops(c, updWorker, 2, func() {
@@ -565,6 +601,78 @@ func (s *workerSuite) TestLongPollNoMachineInGroupKnownByProvider(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestLongPollRunningMachineNotKnownByProviderIsShortPolled(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, mocked := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+
+	// Add machine to short poll group and manually move it to long poll group.
+	updWorker.appendToShortPollGroup(machineTag, machine)
+	entry, _ := updWorker.lookupPolledMachine(machineTag)
+	updWorker.pollGroup[longPollGroup][machineTag] = entry
+	delete(updWorker.pollGroup[shortPollGroup], machineTag)
+
+	// The provider no longer knows of the machine's running instance.
+	instID := instance.Id("d3adc0de")
+	machine.EXPECT().InstanceId().Return(instID, nil)
+	mocked.environ.EXPECT().Instances(gomock.Any(), []instance.Id{instID}).Return(
+		[]instances.Instance{nil}, environs.ErrPartialInstances,
+	)
+	mocked.environ.EXPECT().NetworkInterfaces(gomock.Any(), []instance.Id{instID}).Return(
+		nil, errors.NotSupportedf("network interfaces"),
+	)
+
+	// A single miss doesn't mark the machine as lost, but moves it to
+	// the short poll group to confirm whether the instance is gone.
+	s.assertWorkerCompletesLoops(c, updWorker, 2, func() {
+		mocked.clock.Advance(LongPoll)
+	})
+	_, group := updWorker.lookupPolledMachine(machineTag)
+	c.Assert(group, gc.Equals, shortPollGroup)
+	c.Assert(entry.missingPolls, gc.Equals, 1)
+}
+
+func (s *workerSuite) TestInstanceMissingMarksMachineLost(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	clock := testclock.NewClock(time.Now())
+	updWorker := &updaterWorker{
+		config: Config{
+			Clock:  clock,
+			Logger: loggo.GetLogger("juju.worker.instancepoller"),
+		},
+	}
+	machine := mocks.NewMockMachine(ctrl)
+	entry := &pollGroupEntry{
+		tag:        names.NewMachineTag("0"),
+		m:          machine,
+		instanceID: "d3adc0de",
+	}
+	entry.resetShortPollInterval(clock)
+
+	// Enough misses, but not for long enough.
+	for i := 0; i < LostMissingPolls; i++ {
+		err := updWorker.instanceMissing(shortPollGroup, entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// Once the grace period has passed the next miss marks the
+	// machine as lost.
+	clock.Advance(LostGracePeriod)
+	machine.EXPECT().Id().Return("0").AnyTimes()
+	machine.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
+	machine.EXPECT().SetInstanceStatus(status.Lost, "instance not found by the provider", nil).Return(nil)
+	err := updWorker.instanceMissing(shortPollGroup, entry)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *workerSuite) assertWorkerCompletesLoop(c *gc.C, w *updaterWorker, triggerFn func()) {
 	s.assertWorkerCompletesLoops(c, w, 1, triggerFn)
 }
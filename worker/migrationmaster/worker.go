@@ -223,7 +223,11 @@ func (w *Worker) run() error {
 		case coremigration.QUIESCE:
 			phase, err = w.doQUIESCE(status)
 		case coremigration.IMPORT:
-			phase, err = w.doIMPORT(status.TargetInfo, status.ModelUUID)
+			if status.Offline {
+				phase, err = w.waitForImport(&status)
+			} else {
+				phase, err = w.doIMPORT(status.TargetInfo, status.ModelUUID)
+			}
 		case coremigration.PROCESSRELATIONS:
 			phase, err = w.doPROCESSRELATIONS(status)
 		case coremigration.VALIDATION:
@@ -231,11 +235,18 @@ func (w *Worker) run() error {
 		case coremigration.SUCCESS:
 			phase, err = w.doSUCCESS(status)
 		case coremigration.LOGTRANSFER:
-			phase, err = w.doLOGTRANSFER(status.TargetInfo, status.ModelUUID)
+			if status.Offline {
+				// The target controller of an offline migration
+				// may not be reachable from this controller, so
+				// the logs stay behind.
+				phase = coremigration.REAP
+			} else {
+				phase, err = w.doLOGTRANSFER(status.TargetInfo, status.ModelUUID)
+			}
 		case coremigration.REAP:
 			phase, err = w.doREAP()
 		case coremigration.ABORT:
-			phase, err = w.doABORT(status.TargetInfo, status.ModelUUID)
+			phase, err = w.doABORT(status)
 		default:
 			return errors.Errorf("unknown phase: %v [%d]", phase.String(), phase)
 		}
@@ -335,6 +346,11 @@ func (w *Worker) prechecks(status coremigration.MigrationStatus) error {
 	if err != nil {
 		return errors.Annotate(err, "source prechecks failed")
 	}
+	if status.Offline {
+		// The target controller checks the model archive when it is
+		// imported.
+		return nil
+	}
 
 	w.setInfoStatus("performing target prechecks")
 	model, err := w.config.Facade.ModelInfo()
@@ -366,6 +382,44 @@ func (w *Worker) doIMPORT(targetInfo coremigration.TargetInfo, modelUUID string)
 	return coremigration.PROCESSRELATIONS, nil
 }
 
+// waitForImport waits for the model archive of an offline migration to
+// be imported into the target controller, which is recorded by the
+// migration's target being set. The migration is aborted if the import
+// fails.
+func (w *Worker) waitForImport(status *coremigration.MigrationStatus) (coremigration.Phase, error) {
+	w.setInfoStatus("waiting for model archive to be imported into target controller")
+
+	watcher, err := w.config.Facade.Watch()
+	if err != nil {
+		return coremigration.UNKNOWN, errors.Annotate(err, "watching migration")
+	}
+	if err := w.catacomb.Add(watcher); err != nil {
+		return coremigration.UNKNOWN, errors.Trace(err)
+	}
+	defer watcher.Kill()
+
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return coremigration.UNKNOWN, w.catacomb.ErrDying()
+		case <-watcher.Changes():
+		}
+
+		current, err := w.config.Facade.MigrationStatus()
+		if err != nil {
+			return coremigration.UNKNOWN, errors.Annotate(err, "retrieving migration status")
+		}
+		if current.MigrationId != status.MigrationId || current.Phase != coremigration.IMPORT {
+			w.lastFailure = "model archive import aborted"
+			return coremigration.ABORT, nil
+		}
+		if current.TargetInfo.ControllerTag.Id() != "" {
+			status.TargetInfo = current.TargetInfo
+			return coremigration.PROCESSRELATIONS, nil
+		}
+	}
+}
+
 type uploadWrapper struct {
 	client    *migrationtarget.Client
 	modelUUID string
@@ -465,6 +519,12 @@ func (w *Worker) doVALIDATION(status coremigration.MigrationStatus) (coremigrati
 	if !ok {
 		return coremigration.ABORT, nil
 	}
+	if status.Offline {
+		// The client which imported the model archive checks the
+		// machines and activates the model in the target controller
+		// once the agents have been handed over.
+		return coremigration.SUCCESS, nil
+	}
 
 	client, closer, err := w.openTargetAPI(status.TargetInfo)
 	if err != nil {
@@ -522,9 +582,13 @@ func (w *Worker) doSUCCESS(status coremigration.MigrationStatus) (coremigration.
 	if err != nil {
 		return coremigration.UNKNOWN, errors.Trace(err)
 	}
-	err = w.transferResources(status.TargetInfo, status.ModelUUID)
-	if err != nil {
-		return coremigration.UNKNOWN, errors.Trace(err)
+	// The cloud resources of an offline migration are adopted by the
+	// client which imported the model archive.
+	if !status.Offline {
+		err = w.transferResources(status.TargetInfo, status.ModelUUID)
+		if err != nil {
+			return coremigration.UNKNOWN, errors.Trace(err)
+		}
 	}
 	// There's no turning back from SUCCESS - any problems should have
 	// been picked up in VALIDATION. After the minion wait in the
@@ -642,9 +706,15 @@ func (w *Worker) doREAP() (coremigration.Phase, error) {
 	return coremigration.DONE, nil
 }
 
-func (w *Worker) doABORT(targetInfo coremigration.TargetInfo, modelUUID string) (coremigration.Phase, error) {
+func (w *Worker) doABORT(status coremigration.MigrationStatus) (coremigration.Phase, error) {
+	if status.Offline {
+		// The client which imported the model archive removes the
+		// model from the target controller.
+		w.setInfoStatus("aborted: %s", w.lastFailure)
+		return coremigration.ABORTDONE, nil
+	}
 	w.setInfoStatus("aborted, removing model from target controller: %s", w.lastFailure)
-	if err := w.removeImportedModel(targetInfo, modelUUID); err != nil {
+	if err := w.removeImportedModel(status.TargetInfo, status.ModelUUID); err != nil {
 		// This isn't fatal. Removing the imported model is a best
 		// efforts attempt so just report the error and proceed.
 		w.logger.Warningf("failed to remove model from target controller, %v", err)
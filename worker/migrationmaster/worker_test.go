@@ -318,6 +318,88 @@ func (s *Suite) TestMigrationResume(c *gc.C) {
 	))
 }
 
+func (s *Suite) makeOfflineStatus(phase coremigration.Phase) coremigration.MigrationStatus {
+	status := s.makeStatus(phase)
+	status.TargetInfo = coremigration.TargetInfo{}
+	status.Offline = true
+	return status
+}
+
+func (s *Suite) TestSuccessfulOfflineMigration(c *gc.C) {
+	s.facade.queueStatus(s.makeOfflineStatus(coremigration.QUIESCE))
+	s.facade.queueMinionReports(makeMinionReports(coremigration.QUIESCE))
+	// The model archive is imported, setting the target.
+	imported := s.makeStatus(coremigration.IMPORT)
+	imported.Offline = true
+	s.facade.queueStatus(imported)
+	s.facade.queueMinionReports(makeMinionReports(coremigration.VALIDATION))
+	s.facade.queueMinionReports(makeMinionReports(coremigration.SUCCESS))
+
+	s.checkWorkerReturns(c, migrationmaster.ErrMigrated)
+
+	// The target controller is never contacted; the client importing
+	// the model archive activates the model and adopts its resources.
+	s.stub.CheckCalls(c, joinCalls(
+		watchStatusLockdownCalls,
+		[]jujutesting.StubCall{
+			// QUIESCE
+			{"facade.Prechecks", nil},
+			{"facade.WatchMinionReports", nil},
+			{"facade.MinionReports", nil},
+			{"facade.Prechecks", nil},
+			{"facade.SetPhase", []interface{}{coremigration.IMPORT}},
+
+			// IMPORT
+			{"facade.Watch", nil},
+			{"facade.MigrationStatus", nil},
+			{"facade.SetPhase", []interface{}{coremigration.PROCESSRELATIONS}},
+
+			// PROCESSRELATIONS
+			{"facade.ProcessRelations", []interface{}{""}},
+			{"facade.SetPhase", []interface{}{coremigration.VALIDATION}},
+
+			// VALIDATION
+			{"facade.WatchMinionReports", nil},
+			{"facade.MinionReports", nil},
+			{"facade.SetPhase", []interface{}{coremigration.SUCCESS}},
+
+			// SUCCESS
+			{"facade.WatchMinionReports", nil},
+			{"facade.MinionReports", nil},
+			{"facade.SetPhase", []interface{}{coremigration.LOGTRANSFER}},
+
+			// LOGTRANSFER
+			{"facade.SetPhase", []interface{}{coremigration.REAP}},
+
+			// REAP
+			{"facade.Reap", nil},
+			{"facade.SetPhase", []interface{}{coremigration.DONE}},
+		},
+	))
+}
+
+func (s *Suite) TestOfflineMigrationImportAborted(c *gc.C) {
+	s.facade.queueStatus(s.makeOfflineStatus(coremigration.QUIESCE))
+	s.facade.queueMinionReports(makeMinionReports(coremigration.QUIESCE))
+	s.facade.queueStatus(s.makeOfflineStatus(coremigration.ABORT))
+
+	s.checkWorkerReturns(c, migrationmaster.ErrInactive)
+	s.stub.CheckCalls(c, joinCalls(
+		watchStatusLockdownCalls,
+		[]jujutesting.StubCall{
+			{"facade.Prechecks", nil},
+			{"facade.WatchMinionReports", nil},
+			{"facade.MinionReports", nil},
+			{"facade.Prechecks", nil},
+			{"facade.SetPhase", []interface{}{coremigration.IMPORT}},
+			{"facade.Watch", nil},
+			{"facade.MigrationStatus", nil},
+			{"facade.SetPhase", []interface{}{coremigration.ABORT}},
+			{"facade.SetPhase", []interface{}{coremigration.ABORTDONE}},
+		},
+	))
+}
+
 func (s *Suite) TestPreviouslyAbortedMigration(c *gc.C) {
 	s.facade.queueStatus(s.makeStatus(coremigration.ABORTDONE))
 
@@ -42,6 +42,8 @@ var (
 	RetryStrategyCount      = &retryStrategyCount
 )
 
+const MachinePoolReconcileInterval = machinePoolReconcileInterval
+
 var ClassifyMachine = classifyMachine
 
 // GetCopyAvailabilityZoneMachines returns a copy of p.(*provisionerTask).availabilityZoneMachines
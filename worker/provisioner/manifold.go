@@ -4,6 +4,7 @@
 package provisioner
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v2"
 	"github.com/juju/worker/v2/dependency"
@@ -34,8 +35,9 @@ type ManifoldConfig struct {
 	APICallerName string
 	EnvironName   string
 	Logger        Logger
+	Clock         clock.Clock
 
-	NewProvisionerFunc           func(*apiprovisioner.State, agent.Config, Logger, environs.Environ, common.CredentialAPI) (Provisioner, error)
+	NewProvisionerFunc           func(*apiprovisioner.State, agent.Config, Logger, environs.Environ, common.CredentialAPI, clock.Clock) (Provisioner, error)
 	NewCredentialValidatorFacade func(base.APICaller) (common.CredentialAPI, error)
 }
 
@@ -72,7 +74,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, errors.Trace(err)
 			}
 
-			w, err := config.NewProvisionerFunc(api, agentConfig, config.Logger, environ, credentialAPI)
+			w, err := config.NewProvisionerFunc(api, agentConfig, config.Logger, environ, credentialAPI, config.Clock)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
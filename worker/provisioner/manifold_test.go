@@ -4,6 +4,7 @@
 package provisioner_test
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
@@ -35,6 +36,7 @@ func (s *ManifoldSuite) makeManifold() dependency.Manifold {
 		provisioner.Logger,
 		environs.Environ,
 		common.CredentialAPI,
+		clock.Clock,
 	) (provisioner.Provisioner, error) {
 		s.stub.AddCall("NewProvisionerFunc")
 		return struct{ provisioner.Provisioner }{}, nil
@@ -44,6 +46,7 @@ func (s *ManifoldSuite) makeManifold() dependency.Manifold {
 		APICallerName:                "api-caller",
 		Logger:                       loggo.GetLogger("test"),
 		EnvironName:                  "environ",
+		Clock:                        clock.WallClock,
 		NewProvisionerFunc:           fakeNewProvFunc,
 		NewCredentialValidatorFacade: func(base.APICaller) (common.CredentialAPI, error) { return nil, nil },
 	})
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"github.com/juju/worker/v2"
@@ -32,6 +33,11 @@ var (
 	retryStrategyCount = 10
 )
 
+// machinePoolReconcileInterval is how often the environ
+// provisioner asks the controller to replace lost machines in
+// machine pools and to bring the pools to their target sizes.
+const machinePoolReconcileInterval = time.Minute
+
 // Provisioner represents a running provisioner worker.
 type Provisioner interface {
 	worker.Worker
@@ -44,6 +50,7 @@ type Provisioner interface {
 type environProvisioner struct {
 	provisioner
 	environ        environs.Environ
+	clock          clock.Clock
 	configObserver configObserver
 }
 
@@ -195,10 +202,14 @@ func NewEnvironProvisioner(
 	logger Logger,
 	environ environs.Environ,
 	credentialAPI common.CredentialAPI,
+	clock clock.Clock,
 ) (Provisioner, error) {
 	if logger == nil {
 		return nil, errors.NotValidf("missing logger")
 	}
+	if clock == nil {
+		return nil, errors.NotValidf("missing clock")
+	}
 	p := &environProvisioner{
 		provisioner: provisioner{
 			st:                      st,
@@ -209,6 +220,7 @@ func NewEnvironProvisioner(
 			callContext:             common.NewCloudCallContext(credentialAPI, nil),
 		},
 		environ: environ,
+		clock:   clock,
 	}
 	p.Provisioner = p
 	p.broker = environ
@@ -250,6 +262,7 @@ func (p *environProvisioner) loop() error {
 		return errors.Trace(err)
 	}
 
+	reconcileMachinePools := p.clock.After(machinePoolReconcileInterval)
 	for {
 		select {
 		case <-p.catacomb.Dying():
@@ -266,6 +279,17 @@ func (p *environProvisioner) loop() error {
 				return errors.Annotate(err, "loaded invalid model configuration")
 			}
 			task.SetHarvestMode(modelConfig.ProvisionerHarvestMode())
+		case <-reconcileMachinePools:
+			reconcileMachinePools = p.clock.After(machinePoolReconcileInterval)
+			err := p.st.ReconcileMachinePools()
+			if errors.IsNotSupported(err) {
+				p.logger.Debugf("controller does not support machine pools")
+				reconcileMachinePools = nil
+			} else if err != nil {
+				// Failing to reconcile the pools must not stop the
+				// provisioning of other machines; try again later.
+				p.logger.Errorf("cannot reconcile machine pools: %v", err)
+			}
 		}
 	}
 }
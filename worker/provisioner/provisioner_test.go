@@ -9,6 +9,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/clock"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -472,10 +474,14 @@ func (s *CommonProvisionerSuite) waitInstanceIdNoAssert(c *gc.C, m *state.Machin
 }
 
 func (s *CommonProvisionerSuite) newEnvironProvisioner(c *gc.C) provisioner.Provisioner {
+	return s.newEnvironProvisionerWithClock(c, clock.WallClock)
+}
+
+func (s *CommonProvisionerSuite) newEnvironProvisionerWithClock(c *gc.C, clock clock.Clock) provisioner.Provisioner {
 	machineTag := names.NewMachineTag("0")
 	agentConfig := s.AgentConfigForTag(c, machineTag)
 	apiState := apiprovisioner.NewState(s.st)
-	w, err := provisioner.NewEnvironProvisioner(apiState, agentConfig, loggo.GetLogger("test"), s.Environ, &credentialAPIForTest{})
+	w, err := provisioner.NewEnvironProvisioner(apiState, agentConfig, loggo.GetLogger("test"), s.Environ, &credentialAPIForTest{}, clock)
 	c.Assert(err, jc.ErrorIsNil)
 	return w
 }
@@ -536,6 +542,31 @@ func (s *ProvisionerSuite) TestSimple(c *gc.C) {
 	s.waitForRemovalMark(c, m)
 }
 
+func (s *ProvisionerSuite) TestReconcilesMachinePools(c *gc.C) {
+	pool, err := s.BackingState.AddMachinePool(state.MachinePoolArgs{
+		Name:       "workers",
+		Series:     series.DefaultSupportedLTS(),
+		TargetSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testclock.NewClock(time.Now())
+	p := s.newEnvironProvisionerWithClock(c, clock)
+	defer workertest.CleanKill(c, p)
+
+	c.Assert(clock.WaitAdvance(provisioner.MachinePoolReconcileInterval, coretesting.LongWait, 1), jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		c.Assert(pool.Refresh(), jc.ErrorIsNil)
+		if len(pool.MachineIds()) == 1 {
+			break
+		}
+	}
+	c.Assert(pool.MachineIds(), gc.HasLen, 1)
+	m, err := s.BackingState.Machine(pool.MachineIds()[0])
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitInstanceIdNoAssert(c, m)
+}
+
 func (s *ProvisionerSuite) TestConstraints(c *gc.C) {
 	// Create a machine with non-standard constraints.
 	m, err := s.addMachine()
@@ -295,6 +295,7 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Installed = true
 	case hooks.Start:
 		newState.Started = true
+		newState.Stopped = false
 	case hooks.Stop:
 		newState.Stopped = true
 	case hooks.Remove:
@@ -555,6 +555,19 @@ func (s *RunHookSuite) TestCommitSuccess_Start_SetStarted(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestCommitSuccess_Start_ClearStopped(c *gc.C) {
+	s.testCommitSuccess(c,
+		operation.Factory.NewRunHook,
+		hook.Info{Kind: hooks.Start},
+		operation.State{Started: true, Stopped: true},
+		operation.State{
+			Started: true,
+			Kind:    operation.Continue,
+			Step:    operation.Pending,
+		},
+	)
+}
+
 func (s *RunHookSuite) TestCommitSuccess_Start_Preserve(c *gc.C) {
 	for i, newHook := range []newHook{
 		operation.Factory.NewRunHook,
@@ -217,6 +217,7 @@ type mockUnit struct {
 	life                             life.Value
 	providerID                       string
 	resolved                         params.ResolvedMode
+	interrupting                     bool
 	application                      mockApplication
 	unitWatcher                      *mockNotifyWatcher
 	addressesWatcher                 *mockStringsWatcher
@@ -250,6 +251,10 @@ func (u *mockUnit) Resolved() params.ResolvedMode {
 	return u.resolved
 }
 
+func (u *mockUnit) Interrupting() bool {
+	return u.interrupting
+}
+
 func (u *mockUnit) Application() (remotestate.Application, error) {
 	return &u.application, nil
 }
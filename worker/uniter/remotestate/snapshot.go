@@ -45,6 +45,10 @@ type Snapshot struct {
 	// ProviderID is the cloud container's provider ID.
 	ProviderID string
 
+	// Interrupting reports whether the cloud has given notice that it
+	// will reclaim the instance hosting the unit.
+	Interrupting bool
+
 	// RetryHookVersion increments each time a failed
 	// hook is meant to be retried if ResolvedMode is
 	// set to ResolvedNone.
@@ -40,6 +40,7 @@ type Unit interface {
 	Refresh() error
 	ProviderID() string
 	Resolved() params.ResolvedMode
+	Interrupting() bool
 	Application() (Application, error)
 	Tag() names.UnitTag
 	Watch() (watcher.NotifyWatcher, error)
@@ -429,6 +429,17 @@ func (s *WatcherSuite) TestClearResolvedMode(c *gc.C) {
 	c.Assert(snap.ResolvedMode, gc.Equals, params.ResolvedNone)
 }
 
+func (s *WatcherSuite) TestInterrupting(c *gc.C) {
+	s.signalAll()
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().Interrupting, jc.IsFalse)
+
+	s.st.unit.interrupting = true
+	s.st.unit.unitWatcher.changes <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().Interrupting, jc.IsTrue)
+}
+
 func (s *WatcherSuite) TestLeadershipChanged(c *gc.C) {
 	s.leadership.claimTicket.result = false
 	s.signalAll()
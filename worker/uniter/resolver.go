@@ -297,6 +297,20 @@ func (s *uniterResolver) nextOp(
 ) (operation.Operation, error) {
 	switch remoteState.Life {
 	case life.Alive:
+		// The cloud is about to reclaim the unit's instance, so the
+		// charm's workload is stopped while the instance still runs.
+		// The unit itself is left in place.
+		if remoteState.Interrupting && localState.Started {
+			if !localState.Stopped {
+				return opFactory.NewRunHook(hook.Info{Kind: hooks.Stop})
+			}
+			return nil, resolver.ErrNoOperation
+		}
+		// The cloud withdrew its notice, so the workload stopped
+		// for it is started again.
+		if localState.Started && localState.Stopped {
+			return opFactory.NewRunHook(hook.Info{Kind: hooks.Start})
+		}
 	case life.Dying:
 		// Normally we handle relations last, but if we're dying we
 		// must ensure that all relations are broken first.
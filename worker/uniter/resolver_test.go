@@ -434,6 +434,45 @@ func (s *resolverSuite) TestNoOperationIfHashesAllMatch(c *gc.C) {
 	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
 }
 
+func (s *resolverSuite) TestInterruptingRunsStopHook(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.Interrupting = true
+	s.remoteState.ConfigHash = "differenthash"
+
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run stop hook")
+
+	// Once stopped, nothing else is run.
+	localState.Stopped = true
+	_, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestNoLongerInterruptingRunsStartHook(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmURL: s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+			Stopped:   true,
+		},
+	}
+	s.remoteState.Interrupting = false
+
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run start hook")
+}
+
 func (s *resolverSuite) TestUpgradeOperation(c *gc.C) {
 	opFactory := setupUpgradeOpFactory()
 	localState := resolver.LocalState{
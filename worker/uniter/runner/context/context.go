@@ -697,12 +697,13 @@ func (ctx *HookContext) AddUnitStorage(cons map[string]params.StorageConstraints
 	return nil
 }
 
-// OpenPorts marks the supplied port range for opening when the
-// executing unit's application is exposed.
+// OpenPorts marks the supplied port range for opening, for the named
+// endpoint or for all endpoints if the name is empty, when the executing
+// unit's application is exposed.
 // Implements jujuc.HookContext.ContextNetworking, part of runner.Context.
-func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
+func (ctx *HookContext) OpenPorts(endpoint, protocol string, fromPort, toPort int) error {
 	return tryOpenPorts(
-		protocol, fromPort, toPort,
+		endpoint, protocol, fromPort, toPort,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
@@ -1122,7 +1123,7 @@ func (ctx *HookContext) doFlush(process string) error {
 
 	for portRange, info := range ctx.pendingPorts {
 		if info.ShouldOpen {
-			b.OpenPortRange(portRange.Ports.Protocol, portRange.Ports.FromPort, portRange.Ports.ToPort)
+			b.OpenPortRange(info.Endpoint, portRange.Ports.Protocol, portRange.Ports.FromPort, portRange.Ports.ToPort)
 		} else {
 			b.ClosePortRange(portRange.Ports.Protocol, portRange.Ports.FromPort, portRange.Ports.ToPort)
 		}
@@ -222,19 +222,19 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 	ctx := s.context(c)
 
 	// Try opening some ports via the context.
-	err = ctx.OpenPorts("tcp", 100, 200)
+	err = ctx.OpenPorts("", "tcp", 100, 200)
 	c.Assert(err, jc.ErrorIsNil) // duplicates are ignored
-	err = ctx.OpenPorts("udp", 200, 300)
+	err = ctx.OpenPorts("", "udp", 200, 300)
 	c.Assert(err, gc.ErrorMatches, `cannot open 200-300/udp \(unit "u/0"\): conflicts with existing 200-300/udp \(unit "u/1"\)`)
-	err = ctx.OpenPorts("udp", 100, 200)
+	err = ctx.OpenPorts("", "udp", 100, 200)
 	c.Assert(err, gc.ErrorMatches, `cannot open 100-200/udp \(unit "u/0"\): conflicts with existing 200-300/udp \(unit "u/1"\)`)
-	err = ctx.OpenPorts("udp", 10, 20)
+	err = ctx.OpenPorts("", "udp", 10, 20)
 	c.Assert(err, jc.ErrorIsNil)
-	err = ctx.OpenPorts("tcp", 50, 100)
+	err = ctx.OpenPorts("", "tcp", 50, 100)
 	c.Assert(err, gc.ErrorMatches, `cannot open 50-100/tcp \(unit "u/0"\): conflicts with existing 100-200/tcp \(unit "u/0"\)`)
-	err = ctx.OpenPorts("tcp", 50, 80)
+	err = ctx.OpenPorts("", "tcp", 50, 80)
 	c.Assert(err, jc.ErrorIsNil)
-	err = ctx.OpenPorts("tcp", 40, 90)
+	err = ctx.OpenPorts("", "tcp", 40, 90)
 	c.Assert(err, gc.ErrorMatches, `cannot open 40-90/tcp \(unit "u/0"\): conflicts with 50-80/tcp requested earlier`)
 
 	// Now try closing some ports as well.
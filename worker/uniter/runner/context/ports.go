@@ -19,6 +19,9 @@ import (
 type PortRangeInfo struct {
 	ShouldOpen  bool
 	RelationTag names.RelationTag
+	// Endpoint is the name of the endpoint a port range is opened
+	// for, or empty if it is opened for all endpoints.
+	Endpoint string
 }
 
 // PortRange contains a port range and a relation id. Used as key to
@@ -42,7 +45,7 @@ func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange
 }
 
 func tryOpenPorts(
-	protocol string,
+	endpoint, protocol string,
 	fromPort, toPort int,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
@@ -65,12 +68,11 @@ func tryOpenPorts(
 
 	rangeInfo, isKnown := pendingPorts[rangeKey]
 	if isKnown {
-		if !rangeInfo.ShouldOpen {
-			// If the same range is already pending to be closed, just
-			// mark is pending to be opened.
-			rangeInfo.ShouldOpen = true
-			pendingPorts[rangeKey] = rangeInfo
-		}
+		// If the same range is already pending to be closed, just
+		// mark is pending to be opened, for the requested endpoint.
+		rangeInfo.ShouldOpen = true
+		rangeInfo.Endpoint = endpoint
+		pendingPorts[rangeKey] = rangeInfo
 		return nil
 	}
 
@@ -88,8 +90,12 @@ func tryOpenPorts(
 		if newRange.ConflictsWith(portRange) {
 			if portRange == newRange && relUnitTag == unitTag {
 				// The same unit trying to open the same range is just
-				// ignored.
-				return nil
+				// ignored, unless it opens it for an endpoint, which
+				// may differ from the one it was opened for.
+				if endpoint == "" {
+					return nil
+				}
+				break
 			}
 			return errors.Errorf(
 				"cannot open %v (unit %q): conflicts with existing %v (unit %q)",
@@ -109,6 +115,7 @@ func tryOpenPorts(
 
 	rangeInfo = pendingPorts[rangeKey]
 	rangeInfo.ShouldOpen = true
+	rangeInfo.Endpoint = endpoint
 	pendingPorts[rangeKey] = rangeInfo
 	return nil
 }
@@ -133,8 +133,19 @@ func makePendingPorts(
 	return result
 }
 
+func withEndpoint(
+	pendingPorts map[context.PortRange]context.PortRangeInfo, endpoint string,
+) map[context.PortRange]context.PortRangeInfo {
+	for key, info := range pendingPorts {
+		info.Endpoint = endpoint
+		pendingPorts[key] = info
+	}
+	return pendingPorts
+}
+
 type portsTest struct {
 	about         string
+	endpoint      string
 	proto         string
 	ports         []int
 	machinePorts  map[network.PortRange]params.RelationUnit
@@ -196,12 +207,27 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 		about:        "try opening a range conflicting with another pending range",
 		pendingPorts: makePendingPorts("tcp", 5, 25, true),
 		expectErr:    `cannot open 10-20/tcp \(unit "u/0"\): conflicts with 5-25/tcp requested earlier`,
+	}, {
+		about:         "open a new range for an endpoint",
+		endpoint:      "website",
+		expectPending: withEndpoint(makePendingPorts("tcp", 10, 20, true), "website"),
+	}, {
+		about:         "open an existing range for an endpoint",
+		endpoint:      "website",
+		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
+		expectPending: withEndpoint(makePendingPorts("tcp", 10, 20, true), "website"),
+	}, {
+		about:         "open a range pending to be opened already for another endpoint",
+		endpoint:      "website",
+		pendingPorts:  withEndpoint(makePendingPorts("tcp", 10, 20, true), "admin"),
+		expectPending: withEndpoint(makePendingPorts("tcp", 10, 20, true), "website"),
 	}}
 	for i, test := range tests {
 		c.Logf("test %d: %s", i, test.about)
 
 		test = test.withDefaults("tcp", 10, 20)
 		err := context.TryOpenPorts(
+			test.endpoint,
 			test.proto,
 			test.ports[0],
 			test.ports[1],
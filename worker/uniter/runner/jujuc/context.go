@@ -183,9 +183,10 @@ type ContextNetworking interface {
 	// error if it is not available.
 	PrivateAddress() (string, error)
 
-	// OpenPorts marks the supplied port range for opening when the
-	// executing unit's application is exposed.
-	OpenPorts(protocol string, fromPort, toPort int) error
+	// OpenPorts marks the supplied port range for opening, for the
+	// named endpoint or for all endpoints if the name is empty, when
+	// the executing unit's application is exposed.
+	OpenPorts(endpoint, protocol string, fromPort, toPort int) error
 
 	// ClosePorts ensures the supplied port range is closed even when
 	// the executing unit's application is exposed (unless it is opened
@@ -74,8 +74,8 @@ func (c *ContextNetworking) PrivateAddress() (string, error) {
 }
 
 // OpenPorts implements jujuc.ContextNetworking.
-func (c *ContextNetworking) OpenPorts(protocol string, from, to int) error {
-	c.stub.AddCall("OpenPorts", protocol, from, to)
+func (c *ContextNetworking) OpenPorts(endpoint, protocol string, from, to int) error {
+	c.stub.AddCall("OpenPorts", endpoint, protocol, from, to)
 	if err := c.stub.NextErr(); err != nil {
 		return errors.Trace(err)
 	}
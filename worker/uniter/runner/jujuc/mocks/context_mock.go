@@ -364,17 +364,17 @@ func (mr *MockContextMockRecorder) NetworkInfo(arg0, arg1 interface{}) *gomock.C
 }
 
 // OpenPorts mocks base method
-func (m *MockContext) OpenPorts(arg0 string, arg1, arg2 int) error {
+func (m *MockContext) OpenPorts(arg0, arg1 string, arg2, arg3 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OpenPorts", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "OpenPorts", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // OpenPorts indicates an expected call of OpenPorts
-func (mr *MockContextMockRecorder) OpenPorts(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockContextMockRecorder) OpenPorts(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenPorts", reflect.TypeOf((*MockContext)(nil).OpenPorts), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenPorts", reflect.TypeOf((*MockContext)(nil).OpenPorts), arg0, arg1, arg2, arg3)
 }
 
 // OpenedPorts mocks base method
@@ -132,6 +132,11 @@ type portCommand struct {
 	FromPort   int
 	ToPort     int
 	formatFlag string // deprecated
+
+	// hasEndpoint is true for commands which accept the --endpoint
+	// flag, which sets Endpoint.
+	hasEndpoint bool
+	Endpoint    string
 }
 
 func (c *portCommand) Info() *cmd.Info {
@@ -140,6 +145,9 @@ func (c *portCommand) Info() *cmd.Info {
 
 func (c *portCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
+	if c.hasEndpoint {
+		f.StringVar(&c.Endpoint, "endpoint", "", "only open the port range for the named endpoint")
+	}
 }
 
 func (c *portCommand) Init(args []string) error {
@@ -169,14 +177,19 @@ var openPortInfo = &cmd.Info{
 	Name:    "open-port",
 	Args:    portFormat,
 	Purpose: "register a port or range to open",
-	Doc:     "The port range will only be open while the application is exposed.",
+	Doc: `
+The port range will only be open while the application is exposed.
+If --endpoint is given, the port range is only opened to the CIDRs and
+spaces the named endpoint is exposed to; otherwise it is opened for all
+endpoints.`[1:],
 }
 
 func NewOpenPortCommand(ctx Context) (cmd.Command, error) {
 	return &portCommand{
-		info: openPortInfo,
+		info:        openPortInfo,
+		hasEndpoint: true,
 		action: func(c *portCommand) error {
-			return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort)
+			return ctx.OpenPorts(c.Endpoint, c.Protocol, c.FromPort, c.ToPort)
 		},
 	}, nil
 }
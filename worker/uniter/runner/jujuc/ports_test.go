@@ -91,6 +91,25 @@ func (s *PortsSuite) TestOpenClose(c *gc.C) {
 	}
 }
 
+func (s *PortsSuite) TestOpenOnEndpoint(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("open-port"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(jujuc.NewJujucCommandWrappedForTest(com), ctx, []string{"--endpoint", "website", "80"})
+	c.Check(code, gc.Equals, 0)
+	s.Stub.CheckCall(c, 0, "OpenPorts", "website", "tcp", 80, 80)
+	hctx.info.CheckPorts(c, makeRanges("80/tcp"))
+}
+
+func (s *PortsSuite) TestCloseRejectsEndpoint(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("close-port"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = cmdtesting.InitCommand(jujuc.NewJujucCommandWrappedForTest(com), []string{"--endpoint", "website", "80"})
+	c.Assert(err, gc.ErrorMatches, "option provided but not defined: --endpoint")
+}
+
 var badPortsTests = []struct {
 	args []string
 	err  string
@@ -136,6 +155,9 @@ register a port or range to open
 
 Details:
 The port range will only be open while the application is exposed.
+If --endpoint is given, the port range is only opened to the CIDRs and
+spaces the named endpoint is exposed to; otherwise it is opened for all
+endpoints.
 `[1:])
 
 	close, err := jujuc.NewCommand(hctx, cmdString("close-port"))